@@ -0,0 +1,90 @@
+package air
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DumpText renders program as indented text for golden tests and debugging.
+//
+// Each line is one node: its path from the parent (a field name, with an
+// index for slice elements) followed by the node's scalar fields as
+// name=value pairs. Composite fields follow on indented lines. Fields holding
+// their zero value are omitted, except Kind, so adding a field to AIR does not
+// change dumps of programs that leave it unset. Kinds print by name and
+// strings are quoted.
+//
+// The text is a pure function of the program's contents: equal programs,
+// including a program and its serialized round trip, dump identically.
+func DumpText(program *Program) string {
+	var out strings.Builder
+	dumpNode(&out, "Program", reflect.ValueOf(*program), 0)
+	return out.String()
+}
+
+func dumpNode(out *strings.Builder, label string, value reflect.Value, depth int) {
+	out.WriteString(strings.Repeat("  ", depth))
+	out.WriteString(label)
+	var children []func()
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		fieldValue := value.Field(i)
+		if fieldValue.IsZero() && field.Name != "Kind" {
+			continue
+		}
+		if scalar, ok := dumpScalar(fieldValue); ok {
+			fmt.Fprintf(out, " %s=%s", field.Name, scalar)
+			continue
+		}
+		name := field.Name
+		children = append(children, func() { dumpComposite(out, name, fieldValue, depth+1) })
+	}
+	out.WriteString("\n")
+	for _, child := range children {
+		child()
+	}
+}
+
+func dumpComposite(out *strings.Builder, name string, value reflect.Value, depth int) {
+	switch value.Kind() {
+	case reflect.Pointer:
+		dumpNode(out, name, value.Elem(), depth)
+	case reflect.Slice:
+		for i := 0; i < value.Len(); i++ {
+			dumpNode(out, fmt.Sprintf("%s[%d]", name, i), value.Index(i), depth)
+		}
+	case reflect.Struct:
+		dumpNode(out, name, value, depth)
+	}
+}
+
+// dumpScalar renders value on its node's line, reporting false for structs
+// and for pointers and slices that lead to them.
+func dumpScalar(value reflect.Value) (string, bool) {
+	if stringer, ok := value.Interface().(fmt.Stringer); ok && value.Kind() != reflect.Struct {
+		return stringer.String(), true
+	}
+	switch value.Kind() {
+	case reflect.String:
+		return strconv.Quote(value.String()), true
+	case reflect.Bool:
+		return strconv.FormatBool(value.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10), true
+	case reflect.Slice:
+		elem := value.Type().Elem()
+		if elem.Kind() == reflect.Struct || elem.Kind() == reflect.Pointer {
+			return "", false
+		}
+		items := make([]string, value.Len())
+		for i := range items {
+			items[i], _ = dumpScalar(value.Index(i))
+		}
+		return "[" + strings.Join(items, " ") + "]", true
+	}
+	return "", false
+}
@@ -0,0 +1,127 @@
+package air
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestDumpTextRendersFunctions(t *testing.T) {
+	program := lowerSource(t, `
+		fn add(a: Int, b: Int) Int {
+			a + b
+		}
+
+		fn main() Int {
+			let sum = add(1, 2)
+			sum
+		}
+	`)
+	dump := DumpText(program)
+	want := `  Functions[0] Name="add"
+    Signature Return=2
+      Params[0] Name="a" Type=2
+      Params[1] Name="b" Type=2
+    Locals[0] Name="a" Type=2
+    Locals[1] ID=1 Name="b" Type=2
+    Body
+      Result Kind=IntAdd Type=2
+        Left Kind=LoadLocal Type=2
+        Right Kind=LoadLocal Type=2 Local=1
+  Functions[1] ID=1 Name="main"
+    Signature Return=2
+    Locals[0] Name="sum" Type=2
+    Body
+      Stmts[0] Kind=Let Name="sum" Type=2
+        Value Kind=Call Type=2
+          Args[0] Kind=ConstInt Type=2 Int="1"
+          Args[1] Kind=ConstInt Type=2 Int="2"
+      Result Kind=LoadLocal Type=2
+`
+	if _, functions, ok := strings.Cut(dump, "  Functions[0]"); !ok || "  Functions[0]"+functions != want {
+		t.Fatalf("dump =\n%s\nwant functions =\n%s", dump, want)
+	}
+	if !strings.HasPrefix(dump, "Program Entry=1 Script=-1\n  Modules[0] Path=\"test.ard\"") {
+		t.Fatalf("dump header =\n%s", dump[:min(len(dump), 80)])
+	}
+}
+
+func TestDumpTextIsDeterministic(t *testing.T) {
+	input := `
+		use ard/list
+
+		struct Point { x: Int, y: Int }
+
+		fn main() Int {
+			let points = [Point{x: 1, y: 2}, Point{x: 3, y: 4}]
+			mut total = 0
+			for point in points {
+				total = total + point.x * point.y
+			}
+			total + list::new<Int>().size()
+		}
+	`
+	want := DumpText(lowerSource(t, input))
+	for range 5 {
+		if got := DumpText(lowerSource(t, input)); got != want {
+			t.Fatalf("dump changed between lowerings:\n%s\nwant:\n%s", got, want)
+		}
+	}
+
+	data, err := SerializeProgram(lowerSource(t, input))
+	if err != nil {
+		t.Fatalf("serialize AIR: %v", err)
+	}
+	decoded, err := DeserializeProgram(data)
+	if err != nil {
+		t.Fatalf("deserialize AIR: %v", err)
+	}
+	if got := DumpText(decoded); got != want {
+		t.Fatalf("dump changed across a serialize round trip:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestKindsHaveNames keeps the name tables in kinds.go in step with the kind
+// constants, so a new kind cannot dump as a number.
+func TestKindsHaveNames(t *testing.T) {
+	kinds := map[string]struct {
+		prefix string
+		name   func(int) string
+	}{
+		"StmtKind":           {"Stmt", func(k int) string { return StmtKind(k).String() }},
+		"ExprKind":           {"Expr", func(k int) string { return ExprKind(k).String() }},
+		"TypeKind":           {"Type", func(k int) string { return TypeKind(k).String() }},
+		"SelectArmKind":      {"SelectArm", func(k int) string { return SelectArmKind(k).String() }},
+		"ForeignResultShape": {"ForeignResult", func(k int) string { return ForeignResultShape(k).String() }},
+	}
+	fset := token.NewFileSet()
+	for _, file := range []string{"nodes.go", "types.go"} {
+		parsed, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, decl := range parsed.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST || len(gen.Specs) == 0 {
+				continue
+			}
+			first := gen.Specs[0].(*ast.ValueSpec)
+			typ, ok := first.Type.(*ast.Ident)
+			if !ok {
+				continue
+			}
+			kind, ok := kinds[typ.Name]
+			if !ok {
+				continue
+			}
+			for i, spec := range gen.Specs {
+				constant := spec.(*ast.ValueSpec).Names[0].Name
+				if got, want := kind.name(i), strings.TrimPrefix(constant, kind.prefix); got != want {
+					t.Errorf("%s prints as %q, want %q", constant, got, want)
+				}
+			}
+		}
+	}
+}
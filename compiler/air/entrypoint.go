@@ -2,6 +2,10 @@ package air
 
 import "fmt"
 
+// ValidateEntrypointSignature checks that main has one of the supported
+// shapes: `fn main()`, `fn main() Int`, `fn main(args: [Str])`, or
+// `fn main(args: [Str]) Int`. Targets pass the command-line arguments after
+// the program name as args and exit with the returned Int.
 func ValidateEntrypointSignature(program *Program) error {
 	if program == nil || program.Entry == NoFunction {
 		return nil
@@ -10,15 +14,36 @@ func ValidateEntrypointSignature(program *Program) error {
 		return fmt.Errorf("entrypoint function %d out of range", program.Entry)
 	}
 	entry := program.Functions[program.Entry]
-	if len(entry.Signature.Params) != 0 {
-		return fmt.Errorf("main entrypoint cannot have parameters")
+	switch len(entry.Signature.Params) {
+	case 0:
+	case 1:
+		if !entrypointArgsType(program, entry.Signature.Params[0]) {
+			return fmt.Errorf("main entrypoint parameter must be [Str], got %s", typeName(program, entry.Signature.Params[0].Type))
+		}
+	default:
+		return fmt.Errorf("main entrypoint can only take a single [Str] parameter")
 	}
 	if int(entry.Signature.Return) <= 0 || int(entry.Signature.Return) > len(program.Types) {
 		return fmt.Errorf("main entrypoint return type %d out of range", entry.Signature.Return)
 	}
 	returnType := program.Types[entry.Signature.Return-1]
-	if returnType.Kind != TypeVoid {
-		return fmt.Errorf("main entrypoint must return Void, got %s", returnType.Name)
+	if returnType.Kind != TypeVoid && returnType.Kind != TypeInt {
+		return fmt.Errorf("main entrypoint must return Void or Int, got %s", returnType.Name)
 	}
 	return nil
 }
+
+func entrypointArgsType(program *Program, param Param) bool {
+	if param.Mutable || !validTypeID(program, param.Type) {
+		return false
+	}
+	list := program.Types[param.Type-1]
+	return list.Kind == TypeList && validTypeID(program, list.Elem) && program.Types[list.Elem-1].Kind == TypeStr
+}
+
+func typeName(program *Program, id TypeID) string {
+	if !validTypeID(program, id) {
+		return fmt.Sprintf("type %d", id)
+	}
+	return program.Types[id-1].Name
+}
@@ -20,17 +20,35 @@ func TestValidateEntrypointSignature(t *testing.T) {
 			}`,
 		},
 		{
-			name: "rejects main with args",
-			source: `fn main(name: Str) Void {
+			name: "allows main taking [Str] args and returning an Int exit code",
+			source: `fn main(args: [Str]) Int {
+			  args.size()
 			}`,
-			wantErr: "main entrypoint cannot have parameters",
 		},
 		{
-			name: "rejects main returning non-Void",
+			name: "allows main returning an Int exit code",
 			source: `fn main() Int {
 			  1
 			}`,
-			wantErr: "main entrypoint must return Void, got Int",
+		},
+		{
+			name: "rejects main with a non-[Str] parameter",
+			source: `fn main(name: Str) Void {
+			}`,
+			wantErr: "main entrypoint parameter must be [Str], got Str",
+		},
+		{
+			name: "rejects main with several parameters",
+			source: `fn main(args: [Str], extra: [Str]) Void {
+			}`,
+			wantErr: "main entrypoint can only take a single [Str] parameter",
+		},
+		{
+			name: "rejects main returning neither Void nor Int",
+			source: `fn main() Str {
+			  "done"
+			}`,
+			wantErr: "main entrypoint must return Void or Int, got Str",
 		},
 	}
 
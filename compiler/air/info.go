@@ -0,0 +1,43 @@
+package air
+
+import (
+	"sort"
+
+	"github.com/akonwi/ard/version"
+)
+
+// ProgramInfo describes a built program for tools that inspect build
+// artifacts without running them.
+type ProgramInfo struct {
+	Compiler string   `json:"compiler"`
+	Modules  []string `json:"modules"`
+	Entry    string   `json:"entry,omitempty"`
+	Tests    []string `json:"tests,omitempty"`
+	// GoImports lists the Go packages the program imports, sorted. It is
+	// never nil for a described program, so a missing list means the
+	// program was built before ard recorded it.
+	GoImports []string `json:"go_imports"`
+}
+
+// Describe summarizes program as built by this compiler.
+func Describe(program *Program) ProgramInfo {
+	info := ProgramInfo{Compiler: version.Get(), GoImports: []string{}}
+	seen := map[string]bool{}
+	for _, module := range program.Modules {
+		info.Modules = append(info.Modules, module.Path)
+		for _, importPath := range module.GoImports {
+			if !seen[importPath] {
+				seen[importPath] = true
+				info.GoImports = append(info.GoImports, importPath)
+			}
+		}
+	}
+	sort.Strings(info.GoImports)
+	if validFunctionID(program, program.Entry) {
+		info.Entry = program.Functions[program.Entry].Name
+	}
+	for _, test := range program.Tests {
+		info.Tests = append(info.Tests, test.Name)
+	}
+	return info
+}
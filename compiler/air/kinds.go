@@ -0,0 +1,248 @@
+package air
+
+import "fmt"
+
+// Kind names are the constant names without their prefix. DumpText prints
+// them, so renaming a kind changes every dump that contains it.
+
+var stmtKindNames = [...]string{
+	StmtLet:             "Let",
+	StmtAssign:          "Assign",
+	StmtAssignGlobal:    "AssignGlobal",
+	StmtSetField:        "SetField",
+	StmtSetForeignField: "SetForeignField",
+	StmtSetForeignValue: "SetForeignValue",
+	StmtExpr:            "Expr",
+	StmtWhile:           "While",
+	StmtForMap:          "ForMap",
+	StmtBreak:           "Break",
+	StmtDefer:           "Defer",
+}
+
+func (k StmtKind) String() string {
+	if int(k) < len(stmtKindNames) {
+		return stmtKindNames[k]
+	}
+	return fmt.Sprintf("StmtKind(%d)", k)
+}
+
+var exprKindNames = [...]string{
+	ExprConstVoid:                  "ConstVoid",
+	ExprConstInt:                   "ConstInt",
+	ExprConstFloat:                 "ConstFloat",
+	ExprConstBool:                  "ConstBool",
+	ExprConstStr:                   "ConstStr",
+	ExprPanic:                      "Panic",
+	ExprLoadLocal:                  "LoadLocal",
+	ExprLoadGlobal:                 "LoadGlobal",
+	ExprFunctionRef:                "FunctionRef",
+	ExprCall:                       "Call",
+	ExprForeignCall:                "ForeignCall",
+	ExprForeignMethodCall:          "ForeignMethodCall",
+	ExprForeignMethodValue:         "ForeignMethodValue",
+	ExprForeignFieldAccess:         "ForeignFieldAccess",
+	ExprForeignStructInstance:      "ForeignStructInstance",
+	ExprForeignValue:               "ForeignValue",
+	ExprForeignInterfaceUpcast:     "ForeignInterfaceUpcast",
+	ExprDiscardingFunctionCoercion: "DiscardingFunctionCoercion",
+	ExprUnsafeCast:                 "UnsafeCast",
+	ExprUnsafeIsNil:                "UnsafeIsNil",
+	ExprMutRef:                     "MutRef",
+	ExprMatchForeignType:           "MatchForeignType",
+	ExprScalarConvert:              "ScalarConvert",
+	ExprMakeClosure:                "MakeClosure",
+	ExprCallClosure:                "CallClosure",
+	ExprUnionWrap:                  "UnionWrap",
+	ExprMatchUnion:                 "MatchUnion",
+	ExprTraitUpcast:                "TraitUpcast",
+	ExprCallTrait:                  "CallTrait",
+	ExprMakeList:                   "MakeList",
+	ExprMakeFixedArray:             "MakeFixedArray",
+	ExprListAt:                     "ListAt",
+	ExprListAtChecked:              "ListAtChecked",
+	ExprListPrepend:                "ListPrepend",
+	ExprListPush:                   "ListPush",
+	ExprListSet:                    "ListSet",
+	ExprListSize:                   "ListSize",
+	ExprListSort:                   "ListSort",
+	ExprListSwap:                   "ListSwap",
+	ExprMakeMap:                    "MakeMap",
+	ExprAsyncStart:                 "AsyncStart",
+	ExprMakeChannel:                "MakeChannel",
+	ExprChannelSend:                "ChannelSend",
+	ExprChannelRecv:                "ChannelRecv",
+	ExprChannelClose:               "ChannelClose",
+	ExprChannelNarrow:              "ChannelNarrow",
+	ExprSelect:                     "Select",
+	ExprMapKeys:                    "MapKeys",
+	ExprMapSize:                    "MapSize",
+	ExprMapGet:                     "MapGet",
+	ExprMapSet:                     "MapSet",
+	ExprMapDelete:                  "MapDelete",
+	ExprMapHas:                     "MapHas",
+	ExprMapKeyAt:                   "MapKeyAt",
+	ExprMapValueAt:                 "MapValueAt",
+	ExprMakeStruct:                 "MakeStruct",
+	ExprGetField:                   "GetField",
+	ExprIntAdd:                     "IntAdd",
+	ExprIntSub:                     "IntSub",
+	ExprIntMul:                     "IntMul",
+	ExprIntDiv:                     "IntDiv",
+	ExprIntMod:                     "IntMod",
+	ExprFloatAdd:                   "FloatAdd",
+	ExprFloatSub:                   "FloatSub",
+	ExprFloatMul:                   "FloatMul",
+	ExprFloatDiv:                   "FloatDiv",
+	ExprStrConcat:                  "StrConcat",
+	ExprToStr:                      "ToStr",
+	ExprToInt:                      "ToInt",
+	ExprToF64:                      "ToF64",
+	ExprIntAbs:                     "IntAbs",
+	ExprIntPow:                     "IntPow",
+	ExprIntClamp:                   "IntClamp",
+	ExprFloatRound:                 "FloatRound",
+	ExprFloatFloor:                 "FloatFloor",
+	ExprFloatCeil:                  "FloatCeil",
+	ExprIntParse:                   "IntParse",
+	ExprFloatParse:                 "FloatParse",
+	ExprStrAt:                      "StrAt",
+	ExprStrBytes:                   "StrBytes",
+	ExprStrRunes:                   "StrRunes",
+	ExprStrSize:                    "StrSize",
+	ExprStrIsEmpty:                 "StrIsEmpty",
+	ExprStrContains:                "StrContains",
+	ExprStrReplace:                 "StrReplace",
+	ExprStrReplaceAll:              "StrReplaceAll",
+	ExprStrStartsWith:              "StrStartsWith",
+	ExprStrEndsWith:                "StrEndsWith",
+	ExprToAny:                      "ToAny",
+	ExprStrTrim:                    "StrTrim",
+	ExprStrTrimStart:               "StrTrimStart",
+	ExprStrTrimEnd:                 "StrTrimEnd",
+	ExprStrSlice:                   "StrSlice",
+	ExprStrIndexOf:                 "StrIndexOf",
+	ExprStrToUpper:                 "StrToUpper",
+	ExprStrToLower:                 "StrToLower",
+	ExprStrPadLeft:                 "StrPadLeft",
+	ExprStrPadRight:                "StrPadRight",
+	ExprStrRepeat:                  "StrRepeat",
+	ExprStrReverse:                 "StrReverse",
+	ExprStrChars:                   "StrChars",
+	ExprStrGraphemes:               "StrGraphemes",
+	ExprStrFormat:                  "StrFormat",
+	ExprInspect:                    "Inspect",
+	ExprEq:                         "Eq",
+	ExprNotEq:                      "NotEq",
+	ExprLt:                         "Lt",
+	ExprLte:                        "Lte",
+	ExprGt:                         "Gt",
+	ExprGte:                        "Gte",
+	ExprAnd:                        "And",
+	ExprOr:                         "Or",
+	ExprNot:                        "Not",
+	ExprNeg:                        "Neg",
+	ExprBlock:                      "Block",
+	ExprUnsafeBlock:                "UnsafeBlock",
+	ExprIf:                         "If",
+	ExprMakeResultOk:               "MakeResultOk",
+	ExprMakeResultErr:              "MakeResultErr",
+	ExprEnumVariant:                "EnumVariant",
+	ExprMatchEnum:                  "MatchEnum",
+	ExprMatchInt:                   "MatchInt",
+	ExprMatchStr:                   "MatchStr",
+	ExprMakeMaybeSome:              "MakeMaybeSome",
+	ExprMakeMaybeNone:              "MakeMaybeNone",
+	ExprMakeMaybeNew:               "MakeMaybeNew",
+	ExprMakeError:                  "MakeError",
+	ExprWrapError:                  "WrapError",
+	ExprErrorCause:                 "ErrorCause",
+	ExprMatchMaybe:                 "MatchMaybe",
+	ExprMaybeExpect:                "MaybeExpect",
+	ExprMaybeIsNone:                "MaybeIsNone",
+	ExprMaybeIsSome:                "MaybeIsSome",
+	ExprMaybeOr:                    "MaybeOr",
+	ExprMaybeMap:                   "MaybeMap",
+	ExprMaybeAndThen:               "MaybeAndThen",
+	ExprMaybeSet:                   "MaybeSet",
+	ExprMaybeClear:                 "MaybeClear",
+	ExprMatchResult:                "MatchResult",
+	ExprResultExpect:               "ResultExpect",
+	ExprResultOr:                   "ResultOr",
+	ExprResultIsOk:                 "ResultIsOk",
+	ExprResultIsErr:                "ResultIsErr",
+	ExprResultMap:                  "ResultMap",
+	ExprResultMapErr:               "ResultMapErr",
+	ExprResultAndThen:              "ResultAndThen",
+	ExprTryResult:                  "TryResult",
+	ExprTryMaybe:                   "TryMaybe",
+}
+
+func (k ExprKind) String() string {
+	if int(k) < len(exprKindNames) {
+		return exprKindNames[k]
+	}
+	return fmt.Sprintf("ExprKind(%d)", k)
+}
+
+var typeKindNames = [...]string{
+	TypeVoid:        "Void",
+	TypeInt:         "Int",
+	TypeScalar:      "Scalar",
+	TypeForeignType: "ForeignType",
+	TypeFloat64:     "Float64",
+	TypeBool:        "Bool",
+	TypeByte:        "Byte",
+	TypeRune:        "Rune",
+	TypeStr:         "Str",
+	TypeList:        "List",
+	TypeFixedArray:  "FixedArray",
+	TypeMap:         "Map",
+	TypeStruct:      "Struct",
+	TypeEnum:        "Enum",
+	TypeMaybe:       "Maybe",
+	TypeResult:      "Result",
+	TypeUnion:       "Union",
+	TypeAny:         "Any",
+	TypeFunction:    "Function",
+	TypeChannel:     "Channel",
+	TypeReceiver:    "Receiver",
+	TypeSender:      "Sender",
+	TypeTraitObject: "TraitObject",
+	TypeReference:   "Reference",
+	TypeParam:       "Param",
+}
+
+func (k TypeKind) String() string {
+	if int(k) < len(typeKindNames) {
+		return typeKindNames[k]
+	}
+	return fmt.Sprintf("TypeKind(%d)", k)
+}
+
+var selectArmKindNames = [...]string{
+	SelectArmRecv:    "Recv",
+	SelectArmSend:    "Send",
+	SelectArmDefault: "Default",
+}
+
+func (k SelectArmKind) String() string {
+	if int(k) < len(selectArmKindNames) {
+		return selectArmKindNames[k]
+	}
+	return fmt.Sprintf("SelectArmKind(%d)", k)
+}
+
+var foreignResultShapeNames = [...]string{
+	ForeignResultUnknown:    "Unknown",
+	ForeignResultDirect:     "Direct",
+	ForeignResultValueError: "ValueError",
+	ForeignResultErrorOnly:  "ErrorOnly",
+	ForeignResultValueBool:  "ValueBool",
+}
+
+func (k ForeignResultShape) String() string {
+	if int(k) < len(foreignResultShapeNames) {
+		return foreignResultShapeNames[k]
+	}
+	return fmt.Sprintf("ForeignResultShape(%d)", k)
+}
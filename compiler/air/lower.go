@@ -11,6 +11,8 @@ import (
 
 type LowerOptions struct {
 	IncludeTests bool
+	// Release drops `assert` statements; `ensure` statements always run.
+	Release bool
 }
 
 func Lower(module checker.Module) (*Program, error) {
@@ -70,6 +72,7 @@ type lowerer struct {
 	defParams                map[string]int
 	defParamOwner            string
 	includeTests             bool
+	release                  bool
 }
 
 type functionLowerer struct {
@@ -113,6 +116,7 @@ func newLowerer(options LowerOptions) *lowerer {
 		genericFunctionOriginals: map[string]*checker.FunctionDef{},
 		genericMethodDefs:        map[string]FunctionID{},
 		includeTests:             options.IncludeTests,
+		release:                  options.Release,
 	}
 	l.mustIntern(checker.Void)
 	l.mustIntern(checker.Int)
@@ -169,7 +173,7 @@ func findReachableModuleSeen(mod checker.Module, path string, seen map[string]bo
 	if program == nil {
 		return nil
 	}
-	for _, imported := range program.Imports {
+	for _, imported := range sortedImports(program) {
 		if found := findReachableModuleSeen(imported, path, seen); found != nil {
 			return found
 		}
@@ -177,6 +181,22 @@ func findReachableModuleSeen(mod checker.Module, path string, seen map[string]bo
 	return nil
 }
 
+// sortedImports returns program's imports ordered by alias. Imports are a
+// map, so visiting them in this order keeps module ids, and everything
+// numbered after them, the same on every run.
+func sortedImports(program *checker.Program) []checker.Module {
+	aliases := make([]string, 0, len(program.Imports))
+	for alias := range program.Imports {
+		aliases = append(aliases, alias)
+	}
+	sort.Strings(aliases)
+	modules := make([]checker.Module, len(aliases))
+	for i, alias := range aliases {
+		modules[i] = program.Imports[alias]
+	}
+	return modules
+}
+
 func (l *lowerer) hasStructMethod(def *checker.StructDef, name string) bool {
 	methods := l.structMethods(def)
 	return methods != nil && methods[name] != nil
@@ -205,11 +225,17 @@ func (l *lowerer) lowerModule(module checker.Module) error {
 		return nil
 	}
 
-	for _, imported := range prog.Imports {
+	for _, imported := range sortedImports(prog) {
 		l.moduleByName[imported.Path()] = imported
 		importID := l.internModule(imported.Path())
 		mod.Imports = append(mod.Imports, importID)
 	}
+	var goImports []string
+	for _, pkg := range prog.GoImports {
+		goImports = append(goImports, pkg.Path)
+	}
+	sort.Strings(goImports)
+	l.program.Modules[modID].GoImports = goImports
 
 	for i := range prog.Statements {
 		stmt := prog.Statements[i]
@@ -680,6 +706,17 @@ func lowerForeignResultShape(shape checker.ForeignResultShape) ForeignResultShap
 	}
 }
 
+func lowerIntLimit(limit checker.IntLimit) IntLimit {
+	switch limit {
+	case checker.IntMax:
+		return IntMax
+	case checker.IntMin:
+		return IntMin
+	default:
+		return NoIntLimit
+	}
+}
+
 // declareGoAdapterFunction synthesizes the boundary adapter behind a
 // reference to an adapted Go function (ADR 0031 boundary contract): the
 // adapter's body is an ordinary foreign call, so the existing call lowering
@@ -876,6 +913,12 @@ func (l *lowerer) newFunctionLowerer(fn *Function, def *checker.FunctionDef, par
 }
 
 func (fl *functionLowerer) bindTypeVars(pattern checker.Type, actual TypeID) {
+	fl.bindTypeVarsSeen(pattern, actual, map[*checker.StructDef]struct{}{})
+}
+
+// bindTypeVarsSeen visits each struct once so a self-referential struct,
+// whose fields lead back to it, does not recurse forever.
+func (fl *functionLowerer) bindTypeVarsSeen(pattern checker.Type, actual TypeID, seen map[*checker.StructDef]struct{}) {
 	if pattern == nil || actual == NoType || !validTypeID(&fl.l.program, actual) {
 		return
 	}
@@ -892,46 +935,50 @@ func (fl *functionLowerer) bindTypeVars(pattern checker.Type, actual TypeID) {
 	switch typ := pattern.(type) {
 	case *checker.List:
 		if actualInfo.Kind == TypeList {
-			fl.bindTypeVars(typ.Of(), actualInfo.Elem)
+			fl.bindTypeVarsSeen(typ.Of(), actualInfo.Elem, seen)
 		}
 	case *checker.Chan:
 		if actualInfo.Kind == TypeChannel {
-			fl.bindTypeVars(typ.Of(), actualInfo.Elem)
+			fl.bindTypeVarsSeen(typ.Of(), actualInfo.Elem, seen)
 		}
 	case *checker.Receiver:
 		if actualInfo.Kind == TypeReceiver {
-			fl.bindTypeVars(typ.Of(), actualInfo.Elem)
+			fl.bindTypeVarsSeen(typ.Of(), actualInfo.Elem, seen)
 		}
 	case *checker.Sender:
 		if actualInfo.Kind == TypeSender {
-			fl.bindTypeVars(typ.Of(), actualInfo.Elem)
+			fl.bindTypeVarsSeen(typ.Of(), actualInfo.Elem, seen)
 		}
 	case *checker.Map:
 		if actualInfo.Kind == TypeMap {
-			fl.bindTypeVars(typ.Key(), actualInfo.Key)
-			fl.bindTypeVars(typ.Value(), actualInfo.Value)
+			fl.bindTypeVarsSeen(typ.Key(), actualInfo.Key, seen)
+			fl.bindTypeVarsSeen(typ.Value(), actualInfo.Value, seen)
 		}
 	case *checker.Maybe:
 		if actualInfo.Kind == TypeMaybe {
-			fl.bindTypeVars(typ.Of(), actualInfo.Elem)
+			fl.bindTypeVarsSeen(typ.Of(), actualInfo.Elem, seen)
 		}
 	case *checker.MutableRef:
-		fl.bindTypeVars(typ.Of(), actual)
+		fl.bindTypeVarsSeen(typ.Of(), actual, seen)
 	case *checker.Result:
 		if actualInfo.Kind == TypeResult {
-			fl.bindTypeVars(typ.Val(), actualInfo.Value)
-			fl.bindTypeVars(typ.Err(), actualInfo.Error)
+			fl.bindTypeVarsSeen(typ.Val(), actualInfo.Value, seen)
+			fl.bindTypeVarsSeen(typ.Err(), actualInfo.Error, seen)
 		}
 	case *checker.FunctionDef:
 		if actualInfo.Kind == TypeFunction {
 			for i, param := range typ.Parameters {
 				if i < len(actualInfo.Params) {
-					fl.bindTypeVars(param.Type, actualInfo.Params[i])
+					fl.bindTypeVarsSeen(param.Type, actualInfo.Params[i], seen)
 				}
 			}
-			fl.bindTypeVars(typ.ReturnType, actualInfo.Return)
+			fl.bindTypeVarsSeen(typ.ReturnType, actualInfo.Return, seen)
 		}
 	case *checker.StructDef:
+		if _, ok := seen[typ]; ok {
+			return
+		}
+		seen[typ] = struct{}{}
 		if actualInfo.Kind == TypeStruct {
 			fieldsByName := map[string]FieldInfo{}
 			for _, field := range actualInfo.Fields {
@@ -939,7 +986,7 @@ func (fl *functionLowerer) bindTypeVars(pattern checker.Type, actual TypeID) {
 			}
 			for name, fieldType := range typ.Fields {
 				if field, ok := fieldsByName[name]; ok {
-					fl.bindTypeVars(fieldType, field.Type)
+					fl.bindTypeVarsSeen(fieldType, field.Type, seen)
 				}
 			}
 		}
@@ -1264,13 +1311,14 @@ func (l *lowerer) internStructApplicationWithInterner(typ *checker.StructDef, in
 
 	structFields := checker.StructFields(typ)
 	fields := sortedFieldNames(structFields)
+	declared := declaredFieldPositions(typ, structFields)
 	info.Fields = make([]FieldInfo, len(fields))
 	for i, fieldName := range fields {
 		fieldType, fieldMutable, err := l.internStructFieldType(structFields[fieldName], intern)
 		if err != nil {
 			return NoType, err
 		}
-		info.Fields[i] = FieldInfo{Name: fieldName, Type: fieldType, Index: i, Mutable: fieldMutable}
+		info.Fields[i] = FieldInfo{Name: fieldName, Type: fieldType, Index: i, Declared: declared[fieldName], Mutable: fieldMutable}
 	}
 	l.program.Types[idx] = info
 	return id, nil
@@ -1353,13 +1401,14 @@ func (fl *functionLowerer) internStructTypeWithInterner(typ *checker.StructDef,
 
 	structFields := checker.StructFields(typ)
 	fields := sortedFieldNames(structFields)
+	declared := declaredFieldPositions(typ, structFields)
 	info.Fields = make([]FieldInfo, len(fields))
 	for i, fieldName := range fields {
 		fieldType, fieldMutable, err := fl.l.internStructFieldType(structFields[fieldName], intern)
 		if err != nil {
 			return NoType, err
 		}
-		info.Fields[i] = FieldInfo{Name: fieldName, Type: fieldType, Index: i, Mutable: fieldMutable}
+		info.Fields[i] = FieldInfo{Name: fieldName, Type: fieldType, Index: i, Declared: declared[fieldName], Mutable: fieldMutable}
 	}
 	fl.l.program.Types[idx] = info
 	return id, nil
@@ -2187,6 +2236,7 @@ func (l *lowerer) internGenericStructDef(typ *checker.StructDef) (TypeID, error)
 	l.defParams = params
 	l.defParamOwner = key
 	fieldNames := sortedFieldNames(typ.Fields)
+	declared := declaredFieldPositions(typ, typ.Fields)
 	info.Fields = make([]FieldInfo, len(fieldNames))
 	for i, name := range fieldNames {
 		ft := typ.Fields[name]
@@ -2201,7 +2251,7 @@ func (l *lowerer) internGenericStructDef(typ *checker.StructDef) (TypeID, error)
 			l.defParamOwner = prevOwner
 			return NoType, err
 		}
-		info.Fields[i] = FieldInfo{Name: name, Type: ftid, Index: i, Mutable: mut}
+		info.Fields[i] = FieldInfo{Name: name, Type: ftid, Index: i, Declared: declared[name], Mutable: mut}
 	}
 	l.defParams = prev
 	l.defParamOwner = prevOwner
@@ -2233,7 +2283,7 @@ func collectReachableModules(mod checker.Module, seen map[string]checker.Module)
 	if mod.Program() == nil {
 		return
 	}
-	for _, imported := range mod.Program().Imports {
+	for _, imported := range sortedImports(mod.Program()) {
 		collectReachableModules(imported, seen)
 	}
 }
@@ -2410,13 +2460,14 @@ func (l *lowerer) internType(t checker.Type) (TypeID, error) {
 		info.Kind = TypeStruct
 		structFields := checker.StructFields(typ)
 		fields := sortedFieldNames(structFields)
+		declared := declaredFieldPositions(typ, structFields)
 		info.Fields = make([]FieldInfo, len(fields))
 		for i, name := range fields {
 			fieldType, fieldMutable, err := l.internStructFieldType(structFields[name], l.internType)
 			if err != nil {
 				return NoType, err
 			}
-			info.Fields[i] = FieldInfo{Name: name, Type: fieldType, Index: i, Mutable: fieldMutable}
+			info.Fields[i] = FieldInfo{Name: name, Type: fieldType, Index: i, Declared: declared[name], Mutable: fieldMutable}
 		}
 		// Tag concrete instantiations of a generic struct (ADR 0031). The
 		// generic definition is interned lazily from the checker module scope,
@@ -2451,6 +2502,13 @@ func (l *lowerer) internType(t checker.Type) (TypeID, error) {
 		info.Variants = make([]VariantInfo, len(typ.Values))
 		for i, variant := range typ.Values {
 			info.Variants[i] = VariantInfo{Name: variant.Name, Discriminant: variant.Value}
+			for _, payload := range variant.Payload {
+				payloadID, err := l.internType(payload)
+				if err != nil {
+					return NoType, err
+				}
+				info.Variants[i].Payload = append(info.Variants[i].Payload, payloadID)
+			}
 		}
 	case *checker.Union:
 		info.Kind = TypeUnion
@@ -3162,8 +3220,8 @@ func (fl *functionLowerer) lowerExprWithExpectedRaw(expr checker.Expression, exp
 		if kind, ok := maybeConstructorKind(call); ok {
 			return fl.lowerMaybeConstructor(kind, expected, call)
 		}
-		if errorConstructor(call) {
-			return fl.lowerErrorConstructor(expected, call)
+		if kind, ok := errorBuiltinKind(call); ok {
+			return fl.lowerErrorBuiltin(kind, expected, call)
 		}
 	}
 	if match, ok := expr.(*checker.BoolMatch); ok {
@@ -3210,6 +3268,19 @@ func (fl *functionLowerer) normalizeContextualType(expr *Expr, expected TypeID)
 	}
 }
 
+// ifChainHasElse reports whether an if chain ends in an else. A chain without
+// one has a path that produces no value, so it never supplies a type to infer
+// (issue #267), even when its branches end in values.
+func ifChainHasElse(expr *Expr) bool {
+	for expr.Kind == ExprIf {
+		if expr.Else.Result == nil {
+			return false
+		}
+		expr = expr.Else.Result
+	}
+	return true
+}
+
 func (fl *functionLowerer) inferValueType(expr *Expr) TypeID {
 	if expr == nil {
 		return NoType
@@ -3230,6 +3301,9 @@ func (fl *functionLowerer) inferValueType(expr *Expr) TypeID {
 	case ExprBlock:
 		return fl.inferValueType(expr.Body.Result)
 	case ExprIf:
+		if !ifChainHasElse(expr) {
+			return NoType
+		}
 		return fl.mergeValueTypes(fl.inferValueType(expr.Then.Result), fl.inferValueType(expr.Else.Result))
 	case ExprMatchInt:
 		return fl.inferValueTypeFromCases(expr.IntCases, expr.RangeCases, expr.CatchAll)
@@ -3270,6 +3344,9 @@ func (fl *functionLowerer) inferMaybeType(expr *Expr) TypeID {
 	case ExprBlock:
 		return fl.inferMaybeType(expr.Body.Result)
 	case ExprIf:
+		if !ifChainHasElse(expr) {
+			return NoType
+		}
 		return fl.mergeValueTypes(fl.inferMaybeType(expr.Then.Result), fl.inferMaybeType(expr.Else.Result))
 	case ExprMatchInt:
 		return fl.inferMaybeTypeFromCases(expr.IntCases, expr.RangeCases, expr.CatchAll)
@@ -3330,6 +3407,9 @@ func (fl *functionLowerer) inferResultParts(expr *Expr) (TypeID, TypeID) {
 	case ExprBlock:
 		return fl.inferResultParts(expr.Body.Result)
 	case ExprIf:
+		if !ifChainHasElse(expr) {
+			return NoType, NoType
+		}
 		leftValue, leftErr := fl.inferResultParts(expr.Then.Result)
 		rightValue, rightErr := fl.inferResultParts(expr.Else.Result)
 		return fl.mergeResultParts(leftValue, leftErr, rightValue, rightErr)
@@ -3590,19 +3670,76 @@ func (fl *functionLowerer) lowerUnionWrapIfNeeded(expr checker.Expression, expec
 	if err != nil {
 		return nil, false, err
 	}
-	if actual == expected {
+	if actual == expected || !fl.l.widensToUnion(actual, expected) {
 		return nil, false, nil
 	}
-	for _, member := range expectedInfo.Members {
+	value, err := fl.lowerExpr(expr)
+	if err != nil {
+		return nil, true, err
+	}
+	return fl.widenToUnion(value, expected), true, nil
+}
+
+// widensToUnion mirrors the checker's union subtyping: actual is a member of
+// the union, a member of a union nested in it, or a union whose members all
+// widen into it.
+func (l *lowerer) widensToUnion(actual TypeID, union TypeID) bool {
+	if actual == union {
+		return true
+	}
+	unionInfo, ok := l.typeInfo(union)
+	if !ok || unionInfo.Kind != TypeUnion {
+		return false
+	}
+	for _, member := range unionInfo.Members {
 		if member.Type == actual {
-			value, err := fl.lowerExpr(expr)
-			if err != nil {
-				return nil, true, err
-			}
-			return &Expr{Kind: ExprUnionWrap, Type: expected, Target: value, Tag: member.Tag}, true, nil
+			return true
 		}
 	}
-	return nil, false, nil
+	for _, member := range unionInfo.Members {
+		if memberInfo, ok := l.typeInfo(member.Type); ok && memberInfo.Kind == TypeUnion && l.widensToUnion(actual, member.Type) {
+			return true
+		}
+	}
+	actualInfo, ok := l.typeInfo(actual)
+	if !ok || actualInfo.Kind != TypeUnion || len(actualInfo.Members) == 0 {
+		return false
+	}
+	for _, member := range actualInfo.Members {
+		if !l.widensToUnion(member.Type, union) {
+			return false
+		}
+	}
+	return true
+}
+
+// widenToUnion wraps value, whose type widensToUnion the union, in the
+// union's representation. A member of a nested union is wrapped into that
+// union first, and a narrower union is matched and each member rewrapped.
+func (fl *functionLowerer) widenToUnion(value *Expr, union TypeID) *Expr {
+	if value.Type == union {
+		return value
+	}
+	unionInfo, _ := fl.l.typeInfo(union)
+	for _, member := range unionInfo.Members {
+		if member.Type == value.Type {
+			return &Expr{Kind: ExprUnionWrap, Type: union, Target: value, Tag: member.Tag}
+		}
+	}
+	for _, member := range unionInfo.Members {
+		if memberInfo, ok := fl.l.typeInfo(member.Type); ok && memberInfo.Kind == TypeUnion && fl.l.widensToUnion(value.Type, member.Type) {
+			return &Expr{Kind: ExprUnionWrap, Type: union, Target: fl.widenToUnion(value, member.Type), Tag: member.Tag}
+		}
+	}
+	defer fl.scopeLocals()()
+	actualInfo, _ := fl.l.typeInfo(value.Type)
+	cases := make([]UnionMatchCase, 0, len(actualInfo.Members))
+	for _, member := range actualInfo.Members {
+		local := fl.defineLocal("$member", member.Type, false)
+		load := &Expr{Kind: ExprLoadLocal, Type: member.Type, Local: local}
+		cases = append(cases, UnionMatchCase{Tag: member.Tag, Local: local, Body: Block{Result: fl.widenToUnion(load, union)}})
+	}
+	return &Expr{Kind: ExprMatchUnion, Type: union, Target: value, UnionCases: cases}
 }
 
 func (fl *functionLowerer) lowerTraitUpcastIfNeeded(expr checker.Expression, expected TypeID) (*Expr, bool, error) {
@@ -3774,6 +3911,19 @@ func (fl *functionLowerer) lowerStmts(stmt checker.Statement) ([]Stmt, error) {
 	case *checker.ForLoop:
 		defer fl.scopeLocals()()
 		return fl.lowerForLoop(loop)
+	case *checker.Assert:
+		if loop.Debug && fl.l.release {
+			return nil, nil
+		}
+		stmts := []Stmt{}
+		for _, inner := range loop.Body {
+			lowered, err := fl.lowerStmts(inner)
+			if err != nil {
+				return nil, err
+			}
+			stmts = append(stmts, lowered...)
+		}
+		return stmts, nil
 	}
 	lowered, err := fl.lowerStmt(stmt)
 	if err != nil || lowered == nil {
@@ -3806,6 +3956,16 @@ func (fl *functionLowerer) lowerForIntRange(loop *checker.ForIntRange) ([]Stmt,
 		{Kind: StmtLet, Local: counter, Name: loop.Cursor + "$range", Type: intType, Mutable: true, Value: start},
 		{Kind: StmtLet, Local: endLocal, Name: loop.Cursor + "$end", Type: intType, Value: end},
 	}
+	step := func() *Expr { return &Expr{Kind: ExprConstInt, Type: intType, Int: "1"} }
+	if loop.Step != nil {
+		value, err := fl.lowerExprWithExpected(loop.Step, intType)
+		if err != nil {
+			return nil, err
+		}
+		stepLocal := fl.defineLocal(loop.Cursor+"$step", intType, false)
+		stmts = append(stmts, Stmt{Kind: StmtLet, Local: stepLocal, Name: loop.Cursor + "$step", Type: intType, Value: value})
+		step = func() *Expr { return loadLocal(intType, stepLocal) }
+	}
 
 	cursor := fl.defineLocal(loop.Cursor, intType, false)
 	var indexCounter LocalID
@@ -3840,7 +4000,7 @@ func (fl *functionLowerer) lowerForIntRange(loop *checker.ForIntRange) ([]Stmt,
 	body.Stmts = append(body.Stmts, Stmt{
 		Kind:  StmtAssign,
 		Local: counter,
-		Value: &Expr{Kind: ExprIntAdd, Type: intType, Left: loadLocal(intType, counter), Right: &Expr{Kind: ExprConstInt, Type: intType, Int: "1"}},
+		Value: &Expr{Kind: ExprIntAdd, Type: intType, Left: loadLocal(intType, counter), Right: step()},
 	})
 	if loop.Index != "" {
 		body.Stmts = append(body.Stmts, Stmt{
@@ -3850,14 +4010,51 @@ func (fl *functionLowerer) lowerForIntRange(loop *checker.ForIntRange) ([]Stmt,
 		})
 	}
 
+	// Ranges are inclusive: counting up runs while the cursor is <= end and
+	// counting down while it is >= end. A step whose sign is unknown until
+	// runtime checks it on each iteration, so a step of 0 runs nothing.
+	ascending := &Expr{Kind: ExprLte, Type: boolType, Left: loadLocal(intType, counter), Right: loadLocal(intType, endLocal)}
+	descending := &Expr{Kind: ExprGte, Type: boolType, Left: loadLocal(intType, counter), Right: loadLocal(intType, endLocal)}
+	condition := ascending
+	switch rangeStepSign(loop.Step) {
+	case -1:
+		condition = descending
+	case 0:
+		zero := func() *Expr { return &Expr{Kind: ExprConstInt, Type: intType, Int: "0"} }
+		condition = &Expr{
+			Kind:  ExprOr,
+			Type:  boolType,
+			Left:  &Expr{Kind: ExprAnd, Type: boolType, Left: &Expr{Kind: ExprGt, Type: boolType, Left: step(), Right: zero()}, Right: ascending},
+			Right: &Expr{Kind: ExprAnd, Type: boolType, Left: &Expr{Kind: ExprLt, Type: boolType, Left: step(), Right: zero()}, Right: descending},
+		}
+	}
 	stmts = append(stmts, Stmt{
 		Kind:      StmtWhile,
-		Condition: &Expr{Kind: ExprLte, Type: boolType, Left: loadLocal(intType, counter), Right: loadLocal(intType, endLocal)},
+		Condition: condition,
 		Body:      body,
 	})
 	return stmts, nil
 }
 
+// rangeStepSign is 1 or -1 when a range loop's step is a literal (or absent,
+// meaning 1), and 0 when its sign is only known at runtime.
+func rangeStepSign(step checker.Expression) int {
+	switch s := step.(type) {
+	case nil:
+		return 1
+	case *checker.IntLiteral:
+		if s.Value > 0 {
+			return 1
+		}
+		if s.Value < 0 {
+			return -1
+		}
+	case *checker.Negation:
+		return -rangeStepSign(s.Value)
+	}
+	return 0
+}
+
 func (fl *functionLowerer) lowerForInStr(loop *checker.ForInStr) ([]Stmt, error) {
 	strType, err := fl.l.internType(checker.Str)
 	if err != nil {
@@ -4264,7 +4461,7 @@ func (fl *functionLowerer) lowerExpr(expr checker.Expression) (*Expr, error) {
 	case *checker.VoidLiteral:
 		return &Expr{Kind: ExprConstVoid, Type: typeID}, nil
 	case *checker.IntLiteral:
-		return &Expr{Kind: ExprConstInt, Type: typeID, Int: strconv.Itoa(e.Value)}, nil
+		return &Expr{Kind: ExprConstInt, Type: typeID, Int: strconv.Itoa(e.Value), IntLimit: lowerIntLimit(e.Limit)}, nil
 	case *checker.TypedIntLiteral:
 		return &Expr{Kind: ExprConstInt, Type: typeID, Int: e.String()}, nil
 	case *checker.FloatLiteral:
@@ -4287,6 +4484,8 @@ func (fl *functionLowerer) lowerExpr(expr checker.Expression) (*Expr, error) {
 		return fl.lowerTemplateStr(typeID, e)
 	case *checker.FunctionDef:
 		return fl.lowerClosure(typeID, e)
+	case *checker.BoundMethod:
+		return fl.lowerBoundMethod(typeID, e)
 	case *checker.FunctionValueCall:
 		target, err := fl.lowerExpr(e.Callee)
 		if err != nil {
@@ -4294,6 +4493,10 @@ func (fl *functionLowerer) lowerExpr(expr checker.Expression) (*Expr, error) {
 		}
 		return fl.lowerFunctionTypeCall("function value", e.Args, target)
 	case *checker.FunctionCall:
+		// ard/async calls its own start when spawning fibers.
+		if e.Name == "start" && fl.l.program.Modules[fl.fn.Module].Path == "ard/async" {
+			return fl.lowerAsyncStart(typeID, e.Args)
+		}
 		if local, ok := fl.locals[e.Name]; ok {
 			if _, callable := fl.functionTypeIDForCallable(fl.fn.Locals[local].Type); callable {
 				target := &Expr{Kind: ExprLoadLocal, Type: fl.fn.Locals[local].Type, Local: local}
@@ -4394,6 +4597,19 @@ func (fl *functionLowerer) lowerExpr(expr checker.Expression) (*Expr, error) {
 			return nil, err
 		}
 		return &Expr{Kind: ExprScalarConvert, Type: typeID, Target: value}, nil
+	case *checker.NumberParse:
+		if e.Target == checker.Float64 {
+			return fl.lowerUnary(ExprFloatParse, typeID, e.Text)
+		}
+		return fl.lowerUnary(ExprIntParse, typeID, e.Text)
+	case *checker.StrFormat:
+		return fl.lowerStrFormat(typeID, e)
+	case *checker.InspectValue:
+		value, err := fl.lowerExpr(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &Expr{Kind: ExprInspect, Type: typeID, Target: value}, nil
 	case *checker.ForeignFieldAccess:
 		target, err := fl.lowerExpr(e.Subject)
 		if err != nil {
@@ -4505,8 +4721,8 @@ func (fl *functionLowerer) lowerExpr(expr checker.Expression) (*Expr, error) {
 		if kind, ok := maybeConstructorKind(e); ok {
 			return fl.lowerMaybeConstructor(kind, typeID, e)
 		}
-		if errorConstructor(e) {
-			return fl.lowerErrorConstructor(typeID, e)
+		if kind, ok := errorBuiltinKind(e); ok {
+			return fl.lowerErrorBuiltin(kind, typeID, e)
 		}
 		if e.Module == "ard/list" && e.Call.Name == "new" {
 			if len(e.Call.Args) != 0 {
@@ -4515,14 +4731,7 @@ func (fl *functionLowerer) lowerExpr(expr checker.Expression) (*Expr, error) {
 			return &Expr{Kind: ExprMakeList, Type: typeID}, nil
 		}
 		if e.Module == "ard/async" && e.Call.Name == "start" {
-			if len(e.Call.Args) != 1 {
-				return nil, fmt.Errorf("ard/async::start expects one argument")
-			}
-			task, err := fl.lowerExpr(e.Call.Args[0])
-			if err != nil {
-				return nil, err
-			}
-			return &Expr{Kind: ExprAsyncStart, Type: typeID, Args: []Expr{*task}}, nil
+			return fl.lowerAsyncStart(typeID, e.Call.Args)
 		}
 		if e.Module == "builtin/Chan" {
 			return fl.lowerChannelCall(typeID, e)
@@ -4597,16 +4806,23 @@ func (fl *functionLowerer) lowerExpr(expr checker.Expression) (*Expr, error) {
 		if e.Kind == checker.IntToStr {
 			return fl.lowerUnary(ExprToStr, typeID, e.Subject)
 		}
-		if e.Kind == checker.IntToF64 {
+		if e.Kind == checker.IntToF64 || e.Kind == checker.IntToFloat {
 			return fl.lowerUnary(ExprToF64, typeID, e.Subject)
 		}
-		return nil, fmt.Errorf("unsupported AIR Int method %d", e.Kind)
+		return fl.lowerIntMethod(typeID, e)
 	case *checker.FloatMethod:
 		if e.Kind == checker.FloatToStr {
 			return fl.lowerUnary(ExprToStr, typeID, e.Subject)
 		}
-		if e.Kind == checker.FloatToInt {
+		switch e.Kind {
+		case checker.FloatToInt:
 			return fl.lowerUnary(ExprToInt, typeID, e.Subject)
+		case checker.FloatRound:
+			return fl.lowerUnary(ExprFloatRound, typeID, e.Subject)
+		case checker.FloatFloor:
+			return fl.lowerUnary(ExprFloatFloor, typeID, e.Subject)
+		case checker.FloatCeil:
+			return fl.lowerUnary(ExprFloatCeil, typeID, e.Subject)
 		}
 		return nil, fmt.Errorf("unsupported AIR Float method %d", e.Kind)
 	case *checker.BoolMethod:
@@ -4619,7 +4835,13 @@ func (fl *functionLowerer) lowerExpr(expr checker.Expression) (*Expr, error) {
 	case *checker.MapMethod:
 		return fl.lowerMapMethod(typeID, e)
 	case *checker.EnumVariant:
-		return &Expr{Kind: ExprEnumVariant, Type: typeID, Variant: int(e.Variant), Discriminant: e.Discriminant}, nil
+		return fl.lowerEnumVariant(typeID, e)
+	case *checker.EnumMethod:
+		return fl.lowerEnumMethod(typeID, e)
+	case *checker.EnumValues:
+		return fl.lowerEnumValues(typeID, e)
+	case *checker.EnumFromInt:
+		return fl.lowerEnumFromInt(typeID, e)
 	case *checker.BoolMatch:
 		return fl.lowerBoolMatch(typeID, e)
 	case *checker.IntMatch:
@@ -4842,15 +5064,48 @@ func (fl *functionLowerer) lowerResultConstructor(kind ExprKind, typeID TypeID,
 	return &Expr{Kind: kind, Type: typeID, Target: value}, nil
 }
 
-func (fl *functionLowerer) lowerErrorConstructor(typeID TypeID, call *checker.ModuleFunctionCall) (*Expr, error) {
-	if len(call.Call.Args) != 1 {
-		return nil, fmt.Errorf("Error::new expects one argument")
+func (fl *functionLowerer) lowerErrorBuiltin(kind ExprKind, typeID TypeID, call *checker.ModuleFunctionCall) (*Expr, error) {
+	if kind == ExprMakeError {
+		if len(call.Call.Args) != 1 {
+			return nil, fmt.Errorf("Error::new expects one argument")
+		}
+		message, err := fl.lowerExpr(call.Call.Args[0])
+		if err != nil {
+			return nil, err
+		}
+		return &Expr{Kind: ExprMakeError, Type: typeID, Target: message}, nil
+	}
+	wantArgs := 1
+	if kind == ExprWrapError {
+		wantArgs = 2
+	}
+	if len(call.Call.Args) != wantArgs {
+		return nil, fmt.Errorf("Error::%s expects %d arguments", call.Call.Name, wantArgs)
+	}
+	errorType, err := fl.internType(checker.BuiltinError)
+	if err != nil {
+		return nil, err
 	}
-	message, err := fl.lowerExpr(call.Call.Args[0])
+	target, err := fl.lowerExprWithExpected(call.Call.Args[0], errorType)
 	if err != nil {
 		return nil, err
 	}
-	return &Expr{Kind: ExprMakeError, Type: typeID, Target: message}, nil
+	args, err := fl.lowerArgs(call.Call.Args[1:])
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{Kind: kind, Type: typeID, Target: target, Args: args}, nil
+}
+
+func (fl *functionLowerer) lowerAsyncStart(typeID TypeID, args []checker.Expression) (*Expr, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("ard/async::start expects one argument")
+	}
+	task, err := fl.lowerExpr(args[0])
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{Kind: ExprAsyncStart, Type: typeID, Args: []Expr{*task}}, nil
 }
 
 func (fl *functionLowerer) lowerMaybeConstructor(kind ExprKind, typeID TypeID, call *checker.ModuleFunctionCall) (*Expr, error) {
@@ -4957,6 +5212,109 @@ func (fl *functionLowerer) lowerMapLiteral(typeID TypeID, m *checker.MapLiteral,
 	return &Expr{Kind: ExprMakeMap, Type: typeID, Entries: entries}, nil
 }
 
+func (fl *functionLowerer) lowerEnumVariant(typeID TypeID, variant *checker.EnumVariant) (*Expr, error) {
+	expr := &Expr{Kind: ExprEnumVariant, Type: typeID, Variant: variant.Variant, Discriminant: variant.Discriminant}
+	if len(variant.Args) == 0 {
+		return expr, nil
+	}
+	enumType, ok := fl.l.typeInfo(typeID)
+	if !ok || enumType.Kind != TypeEnum || variant.Variant >= len(enumType.Variants) {
+		return nil, fmt.Errorf("enum variant lowered with non-enum type %s", variant.Type().String())
+	}
+	payload := enumType.Variants[variant.Variant].Payload
+	if len(payload) != len(variant.Args) {
+		return nil, fmt.Errorf("enum variant %s has %d payload values, want %d", variant, len(variant.Args), len(payload))
+	}
+	expr.Args = make([]Expr, len(variant.Args))
+	for i, arg := range variant.Args {
+		lowered, _, err := fl.lowerContextualExpr(arg, payload[i])
+		if err != nil {
+			return nil, err
+		}
+		expr.Args[i] = *lowered
+	}
+	return expr, nil
+}
+
+// lowerEnumMethod lowers Enum.name() to a match returning each variant's
+// name. An open enum holding a value outside its variants has no name.
+func (fl *functionLowerer) lowerEnumMethod(typeID TypeID, method *checker.EnumMethod) (*Expr, error) {
+	if method.Kind != checker.EnumName {
+		return nil, fmt.Errorf("unsupported AIR Enum method %d", method.Kind)
+	}
+	subject, err := fl.lowerExpr(method.Subject)
+	if err != nil {
+		return nil, err
+	}
+	enumType, ok := fl.l.typeInfo(subject.Type)
+	if !ok || enumType.Kind != TypeEnum {
+		return nil, fmt.Errorf("enum method lowered with non-enum subject %s", method.Subject.Type().String())
+	}
+	cases := make([]EnumMatchCase, len(enumType.Variants))
+	for i, variant := range enumType.Variants {
+		cases[i] = EnumMatchCase{
+			Variant:      i,
+			Discriminant: variant.Discriminant,
+			Body:         Block{Result: &Expr{Kind: ExprConstStr, Type: typeID, Str: variant.Name}},
+		}
+	}
+	var catchAll Block
+	if enumType.EnumOpen {
+		catchAll = Block{Result: &Expr{Kind: ExprConstStr, Type: typeID}}
+	}
+	return &Expr{Kind: ExprMatchEnum, Type: typeID, Target: subject, EnumCases: cases, CatchAll: catchAll}, nil
+}
+
+// lowerEnumValues lowers Enum::values() to a list of every variant.
+func (fl *functionLowerer) lowerEnumValues(typeID TypeID, values *checker.EnumValues) (*Expr, error) {
+	enumTypeID, err := fl.l.internType(values.Enum)
+	if err != nil {
+		return nil, err
+	}
+	enumType, ok := fl.l.typeInfo(enumTypeID)
+	if !ok || enumType.Kind != TypeEnum {
+		return nil, fmt.Errorf("enum values lowered with non-enum type %s", values.Enum.String())
+	}
+	args := make([]Expr, len(enumType.Variants))
+	for i, variant := range enumType.Variants {
+		args[i] = Expr{Kind: ExprEnumVariant, Type: enumTypeID, Variant: i, Discriminant: variant.Discriminant}
+	}
+	return &Expr{Kind: ExprMakeList, Type: typeID, Args: args}, nil
+}
+
+// lowerEnumFromInt lowers Enum::from_int(n) to an Int match over the
+// variants' discriminants.
+func (fl *functionLowerer) lowerEnumFromInt(typeID TypeID, from *checker.EnumFromInt) (*Expr, error) {
+	value, err := fl.lowerExpr(from.Value)
+	if err != nil {
+		return nil, err
+	}
+	enumTypeID, err := fl.l.internType(from.Enum)
+	if err != nil {
+		return nil, err
+	}
+	enumType, ok := fl.l.typeInfo(enumTypeID)
+	if !ok || enumType.Kind != TypeEnum {
+		return nil, fmt.Errorf("enum from_int lowered with non-enum type %s", from.Enum.String())
+	}
+	cases := make([]IntMatchCase, len(enumType.Variants))
+	for i, variant := range enumType.Variants {
+		some := &Expr{Kind: ExprEnumVariant, Type: enumTypeID, Variant: i, Discriminant: variant.Discriminant}
+		cases[i] = IntMatchCase{
+			Value: variant.Discriminant,
+			Body:  Block{Result: &Expr{Kind: ExprMakeMaybeSome, Type: typeID, Target: some}},
+		}
+	}
+	sort.Slice(cases, func(i, j int) bool { return cases[i].Value < cases[j].Value })
+	return &Expr{
+		Kind:     ExprMatchInt,
+		Type:     typeID,
+		Target:   value,
+		IntCases: cases,
+		CatchAll: Block{Result: &Expr{Kind: ExprMakeMaybeNone, Type: typeID}},
+	}, nil
+}
+
 func (fl *functionLowerer) lowerEnumMatch(typeID TypeID, match *checker.EnumMatch) (*Expr, error) {
 	subject, err := fl.lowerExpr(match.Subject)
 	if err != nil {
@@ -4968,20 +5326,39 @@ func (fl *functionLowerer) lowerEnumMatch(typeID TypeID, match *checker.EnumMatc
 	}
 
 	cases := make([]EnumMatchCase, 0, len(match.Cases))
+	shared := map[*checker.Block]int{}
 	for variant, block := range match.Cases {
 		if block == nil {
 			continue
 		}
+		if i, ok := shared[block]; ok {
+			cases[i].Also = append(cases[i].Also, enumType.Variants[variant].Discriminant)
+			continue
+		}
+		shared[block] = len(cases)
 		if variant < 0 || variant >= len(enumType.Variants) {
 			return nil, fmt.Errorf("enum match case index %d out of range for %s", variant, enumType.Name)
 		}
+		oldLocals := fl.cloneLocals()
+		var bindings []EnumPayloadBinding
+		if variant < len(match.Bindings) {
+			payload := enumType.Variants[variant].Payload
+			for i, name := range match.Bindings[variant] {
+				if name == "" || i >= len(payload) {
+					continue
+				}
+				bindings = append(bindings, EnumPayloadBinding{Index: i, Local: fl.defineLocal(name, payload[i], false)})
+			}
+		}
 		lowered, err := fl.lowerBlockWithDefault(block.Stmts, typeID)
+		fl.locals = oldLocals
 		if err != nil {
 			return nil, err
 		}
 		cases = append(cases, EnumMatchCase{
 			Variant:      variant,
 			Discriminant: enumType.Variants[variant].Discriminant,
+			Bindings:     bindings,
 			Body:         lowered,
 		})
 	}
@@ -5019,11 +5396,17 @@ func (fl *functionLowerer) lowerIntMatch(typeID TypeID, match *checker.IntMatch)
 	}
 	sort.Ints(intValues)
 	intCases := make([]IntMatchCase, 0, len(intValues))
+	shared := map[*checker.Block]int{}
 	for _, value := range intValues {
 		block := match.IntCases[value]
 		if block == nil {
 			continue
 		}
+		if i, ok := shared[block]; ok {
+			intCases[i].Also = append(intCases[i].Also, value)
+			continue
+		}
+		shared[block] = len(intCases)
 		lowered, err := fl.lowerBlockWithDefault(block.Stmts, typeID)
 		if err != nil {
 			return nil, err
@@ -5088,11 +5471,17 @@ func (fl *functionLowerer) lowerStrMatch(typeID TypeID, match *checker.StrMatch)
 	}
 	sort.Strings(values)
 	strCases := make([]StrMatchCase, 0, len(values))
+	shared := map[*checker.Block]int{}
 	for _, value := range values {
 		block := match.Cases[value]
 		if block == nil {
 			continue
 		}
+		if i, ok := shared[block]; ok {
+			strCases[i].Also = append(strCases[i].Also, value)
+			continue
+		}
+		shared[block] = len(strCases)
 		lowered, err := fl.lowerBlockWithDefault(block.Stmts, typeID)
 		if err != nil {
 			return nil, err
@@ -5310,6 +5699,35 @@ func (fl *functionLowerer) lowerStrMethod(typeID TypeID, method *checker.StrMeth
 		kind = ExprToStr
 	case checker.StrTrim:
 		kind = ExprStrTrim
+	case checker.StrTrimStart:
+		kind = ExprStrTrimStart
+	case checker.StrTrimEnd:
+		kind = ExprStrTrimEnd
+	case checker.StrSlice:
+		kind = ExprStrSlice
+		expected = []TypeID{intType, intType}
+	case checker.StrIndexOf:
+		kind = ExprStrIndexOf
+		expected = []TypeID{strType}
+	case checker.StrToUpper:
+		kind = ExprStrToUpper
+	case checker.StrToLower:
+		kind = ExprStrToLower
+	case checker.StrPadLeft:
+		kind = ExprStrPadLeft
+		expected = []TypeID{intType, strType}
+	case checker.StrPadRight:
+		kind = ExprStrPadRight
+		expected = []TypeID{intType, strType}
+	case checker.StrRepeat:
+		kind = ExprStrRepeat
+		expected = []TypeID{intType}
+	case checker.StrReverse:
+		kind = ExprStrReverse
+	case checker.StrChars:
+		kind = ExprStrChars
+	case checker.StrGraphemes:
+		kind = ExprStrGraphemes
 	default:
 		return nil, fmt.Errorf("unsupported AIR Str method %d", method.Kind)
 	}
@@ -5325,6 +5743,33 @@ func (fl *functionLowerer) lowerStrMethod(typeID TypeID, method *checker.StrMeth
 	return &Expr{Kind: kind, Type: typeID, Target: target, Args: args}, nil
 }
 
+func (fl *functionLowerer) lowerIntMethod(typeID TypeID, method *checker.IntMethod) (*Expr, error) {
+	var kind ExprKind
+	switch method.Kind {
+	case checker.IntAbs:
+		kind = ExprIntAbs
+	case checker.IntPow:
+		kind = ExprIntPow
+	case checker.IntClamp:
+		kind = ExprIntClamp
+	default:
+		return nil, fmt.Errorf("unsupported AIR Int method %d", method.Kind)
+	}
+	target, err := fl.lowerExpr(method.Subject)
+	if err != nil {
+		return nil, err
+	}
+	expected := make([]TypeID, len(method.Args))
+	for i := range expected {
+		expected[i] = typeID
+	}
+	args, err := fl.lowerArgsWithTypeIDs(method.Args, expected)
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{Kind: kind, Type: typeID, Target: target, Args: args}, nil
+}
+
 func (fl *functionLowerer) lowerListMethod(typeID TypeID, method *checker.ListMethod) (*Expr, error) {
 	target, err := fl.lowerExpr(method.Subject)
 	if err != nil {
@@ -5540,6 +5985,9 @@ func (fl *functionLowerer) lowerTryOp(typeID TypeID, op *checker.TryOp) (*Expr,
 		CatchLocal: -1,
 	}
 	if op.CatchBlock == nil {
+		if op.Kind == checker.TryResult {
+			fl.widenPropagatedError(expr)
+		}
 		return expr, nil
 	}
 
@@ -5566,6 +6014,33 @@ func (fl *functionLowerer) lowerTryOp(typeID TypeID, op *checker.TryOp) (*Expr,
 	return expr, nil
 }
 
+// widenPropagatedError gives a catch-less `try` whose error is narrower than
+// the function's union error type an implicit catch that returns the error
+// widened into that union, so backends only ever propagate matching errors.
+func (fl *functionLowerer) widenPropagatedError(expr *Expr) {
+	targetInfo, ok := fl.l.typeInfo(expr.Target.Type)
+	if !ok || targetInfo.Kind != TypeResult {
+		return
+	}
+	returnInfo, ok := fl.l.typeInfo(fl.fn.Signature.Return)
+	if !ok || returnInfo.Kind != TypeResult || returnInfo.Error == targetInfo.Error {
+		return
+	}
+	if errInfo, ok := fl.l.typeInfo(returnInfo.Error); !ok || errInfo.Kind != TypeUnion {
+		return
+	}
+	defer fl.scopeLocals()()
+	local := fl.defineLocal("$err", targetInfo.Error, false)
+	load := &Expr{Kind: ExprLoadLocal, Type: targetInfo.Error, Local: local}
+	expr.HasCatch = true
+	expr.CatchLocal = local
+	expr.Catch = Block{Result: &Expr{
+		Kind:   ExprMakeResultErr,
+		Type:   fl.fn.Signature.Return,
+		Target: fl.widenToUnion(load, returnInfo.Error),
+	}}
+}
+
 func (fl *functionLowerer) lowerBoundBlock(name string, typeID TypeID, stmts []checker.Statement) (LocalID, Block, error) {
 	return fl.lowerBoundBlockWithDefault(name, typeID, stmts, fl.fn.Signature.Return)
 }
@@ -5610,8 +6085,20 @@ func resultConstructorKind(call *checker.ModuleFunctionCall) (ExprKind, bool) {
 	}
 }
 
-func errorConstructor(call *checker.ModuleFunctionCall) bool {
-	return call.Module == "builtin/Error" && call.Call.Name == "new"
+func errorBuiltinKind(call *checker.ModuleFunctionCall) (ExprKind, bool) {
+	if call.Module != "builtin/Error" {
+		return 0, false
+	}
+	switch call.Call.Name {
+	case "new":
+		return ExprMakeError, true
+	case "wrap":
+		return ExprWrapError, true
+	case "cause":
+		return ExprErrorCause, true
+	default:
+		return 0, false
+	}
 }
 
 func maybeConstructorKind(call *checker.ModuleFunctionCall) (ExprKind, bool) {
@@ -5707,6 +6194,29 @@ func (fl *functionLowerer) lowerTemplateStr(typeID TypeID, template *checker.Tem
 	return current, nil
 }
 
+func (fl *functionLowerer) lowerStrFormat(typeID TypeID, format *checker.StrFormat) (*Expr, error) {
+	template, err := fl.lowerExprWithExpected(format.Template, typeID)
+	if err != nil {
+		return nil, err
+	}
+	out := &Expr{Kind: ExprStrFormat, Type: typeID, Target: template}
+	for _, arg := range format.Args {
+		value, err := fl.lowerExpr(arg)
+		if err != nil {
+			return nil, err
+		}
+		out.Args = append(out.Args, *value)
+	}
+	for _, named := range format.Named {
+		value, err := fl.lowerExpr(named.Value)
+		if err != nil {
+			return nil, err
+		}
+		out.Entries = append(out.Entries, MapEntry{Key: Expr{Kind: ExprConstStr, Type: typeID, Str: named.Name}, Value: *value})
+	}
+	return out, nil
+}
+
 func loadLocal(typeID TypeID, local LocalID) *Expr {
 	return &Expr{Kind: ExprLoadLocal, Type: typeID, Local: local}
 }
@@ -5716,6 +6226,9 @@ func (fl *functionLowerer) lowerStructInstance(typeID TypeID, inst *checker.Stru
 	if !ok || typeInfo.Kind != TypeStruct {
 		return nil, fmt.Errorf("struct instance lowered with non-struct type %s", inst.Type().String())
 	}
+	if inst.Base != nil {
+		return fl.lowerStructUpdate(typeID, typeInfo, inst)
+	}
 	fields := make([]StructFieldValue, 0, len(typeInfo.Fields))
 	for _, field := range typeInfo.Fields {
 		fieldExpr, ok := inst.Fields[field.Name]
@@ -5731,6 +6244,39 @@ func (fl *functionLowerer) lowerStructInstance(typeID TypeID, inst *checker.Stru
 	return &Expr{Kind: ExprMakeStruct, Type: typeID, Fields: fields}, nil
 }
 
+// lowerStructUpdate lowers `Name{..base, field: value}`. The base is
+// evaluated once into a local, and every field the literal leaves out is
+// read from it.
+func (fl *functionLowerer) lowerStructUpdate(typeID TypeID, typeInfo TypeInfo, inst *checker.StructInstance) (*Expr, error) {
+	defer fl.scopeLocals()()
+	base, err := fl.lowerExprWithExpected(inst.Base, typeID)
+	if err != nil {
+		return nil, err
+	}
+	baseLocal := fl.defineLocal("$base", typeID, false)
+	fields := make([]StructFieldValue, 0, len(typeInfo.Fields))
+	for _, field := range typeInfo.Fields {
+		var value *Expr
+		if fieldExpr, ok := inst.Fields[field.Name]; ok {
+			value, err = fl.lowerExprWithExpected(fieldExpr, field.Type)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			value = &Expr{Kind: ExprGetField, Type: field.Type, Target: &Expr{Kind: ExprLoadLocal, Type: typeID, Local: baseLocal}, Field: field.Index}
+		}
+		fields = append(fields, StructFieldValue{Index: field.Index, Name: field.Name, Value: *value})
+	}
+	return &Expr{
+		Kind: ExprBlock,
+		Type: typeID,
+		Body: Block{
+			Stmts:  []Stmt{{Kind: StmtLet, Local: baseLocal, Name: "$base", Type: typeID, Value: base}},
+			Result: &Expr{Kind: ExprMakeStruct, Type: typeID, Fields: fields},
+		},
+	}, nil
+}
+
 func (fl *functionLowerer) lowerInstanceProperty(typeID TypeID, prop *checker.InstanceProperty) (*Expr, error) {
 	target, err := fl.lowerExpr(prop.Subject)
 	if err != nil {
@@ -5844,6 +6390,33 @@ func (fl *functionLowerer) lowerClosure(typeID TypeID, def *checker.FunctionDef)
 	return &Expr{Kind: ExprMakeClosure, Type: typeID, Function: id, CaptureLocals: child.captureLocals}, nil
 }
 
+// lowerBoundMethod lowers a method used as a function value to its closure.
+// A receiver evaluated at binding time is stored in a local the closure
+// captures.
+func (fl *functionLowerer) lowerBoundMethod(typeID TypeID, bound *checker.BoundMethod) (*Expr, error) {
+	if bound.Receiver == nil {
+		return fl.lowerClosure(typeID, bound.Function)
+	}
+	defer fl.scopeLocals()()
+	receiver, err := fl.lowerExpr(bound.Receiver)
+	if err != nil {
+		return nil, err
+	}
+	receiverLocal := fl.defineLocal(bound.ReceiverName, receiver.Type, false)
+	closure, err := fl.lowerClosure(typeID, bound.Function)
+	if err != nil {
+		return nil, err
+	}
+	return &Expr{
+		Kind: ExprBlock,
+		Type: typeID,
+		Body: Block{
+			Stmts:  []Stmt{{Kind: StmtLet, Local: receiverLocal, Name: bound.ReceiverName, Type: receiver.Type, Value: receiver}},
+			Result: closure,
+		},
+	}, nil
+}
+
 func (fl *functionLowerer) lowerModuleSymbol(typeID TypeID, symbol *checker.ModuleSymbol) (*Expr, error) {
 	if global, ok, err := fl.l.resolveModuleGlobal(symbol.Module, symbol.Symbol.Name); err != nil {
 		return nil, err
@@ -5943,6 +6516,13 @@ func (fl *functionLowerer) lowerChanMethod(typeID TypeID, target *Expr, method *
 
 func (fl *functionLowerer) lowerUserInstanceMethod(typeID TypeID, target *Expr, typeInfo TypeInfo, method *checker.InstanceMethod) (*Expr, error) {
 	def := method.Method.Definition()
+	if def != nil && def.Body == nil && method.StructType != nil {
+		// A method calling itself on a generic struct is checked against a
+		// copy of its signature made before the body existed.
+		if bodyDef := fl.l.structMethods(method.StructType)[method.Method.Name]; bodyDef != nil && bodyDef.Body != nil {
+			def = bodyDef
+		}
+	}
 	if def == nil || def.Body == nil {
 		if expr, ok, err := fl.lowerStaticTraitMethod(typeID, target, method); ok || err != nil {
 			return expr, err
@@ -6148,7 +6728,7 @@ func (l *lowerer) ensureModuleImportTraitImplsDeclared(moduleID ModuleID) error
 	if !ok || mod.Program() == nil {
 		return nil
 	}
-	for _, imported := range mod.Program().Imports {
+	for _, imported := range sortedImports(mod.Program()) {
 		importedID := l.internModule(imported.Path())
 		l.program.Modules[moduleID].Imports = appendUniqueModule(l.program.Modules[moduleID].Imports, importedID)
 		if err := l.ensureModuleTraitImplsDeclaredRecursive(imported.Path(), map[string]bool{}); err != nil {
@@ -6170,7 +6750,7 @@ func (l *lowerer) ensureModuleTraitImplsDeclaredRecursive(modulePath string, see
 	if !ok || mod.Program() == nil {
 		return nil
 	}
-	for _, imported := range mod.Program().Imports {
+	for _, imported := range sortedImports(mod.Program()) {
 		if err := l.ensureModuleTraitImplsDeclaredRecursive(imported.Path(), seen); err != nil {
 			return err
 		}
@@ -6219,7 +6799,7 @@ func (l *lowerer) ensureModuleTypesDeclared(modulePath string) error {
 	}
 	modID := l.internModule(modulePath)
 	prog := mod.Program()
-	for _, imported := range prog.Imports {
+	for _, imported := range sortedImports(prog) {
 		l.moduleByName[imported.Path()] = imported
 		importedID := l.internModule(imported.Path())
 		l.program.Modules[modID].Imports = appendUniqueModule(l.program.Modules[modID].Imports, importedID)
@@ -6262,7 +6842,7 @@ func (l *lowerer) ensureModuleGlobalsDeclared(modulePath string) error {
 	}
 	modID := l.internModule(modulePath)
 	prog := mod.Program()
-	for _, imported := range prog.Imports {
+	for _, imported := range sortedImports(prog) {
 		l.moduleByName[imported.Path()] = imported
 		importedID := l.internModule(imported.Path())
 		l.program.Modules[modID].Imports = appendUniqueModule(l.program.Modules[modID].Imports, importedID)
@@ -6512,6 +7092,26 @@ func sortedFieldNames(fields map[string]checker.Type) []string {
 	return names
 }
 
+// declaredFieldPositions maps each field to its position in the struct's
+// declaration. Fields without one, as in structs the compiler builds itself,
+// come after the declared fields, sorted by name.
+func declaredFieldPositions(typ *checker.StructDef, fields map[string]checker.Type) map[string]int {
+	positions := make(map[string]int, len(fields))
+	for _, name := range checker.StructFieldOrder(typ) {
+		if _, ok := positions[name]; !ok {
+			if _, isField := fields[name]; isField {
+				positions[name] = len(positions)
+			}
+		}
+	}
+	for _, name := range sortedFieldNames(fields) {
+		if _, ok := positions[name]; !ok {
+			positions[name] = len(positions)
+		}
+	}
+	return positions
+}
+
 func appendUniqueType(items []TypeID, id TypeID) []TypeID {
 	for _, item := range items {
 		if item == id {
@@ -3,6 +3,7 @@ package air
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/akonwi/ard/checker"
@@ -166,18 +167,19 @@ func TestLowerFunctionCanReadModuleLevelLet(t *testing.T) {
 		}
 	`)
 
-	if len(program.Globals) != 1 {
-		t.Fatalf("global count = %d, want 1", len(program.Globals))
+	globals := sourceGlobals(program)
+	if len(globals) != 1 {
+		t.Fatalf("global count = %d, want 1", len(globals))
 	}
-	if program.Globals[0].Name != "refresh_event" {
-		t.Fatalf("global name = %q, want refresh_event", program.Globals[0].Name)
+	if globals[0].Name != "refresh_event" {
+		t.Fatalf("global name = %q, want refresh_event", globals[0].Name)
 	}
 	eventName := findFunction(t, program, "event_name")
 	if eventName.Body.Result == nil || eventName.Body.Result.Kind != ExprLoadGlobal {
 		t.Fatalf("event_name result = %#v, want ExprLoadGlobal", eventName.Body.Result)
 	}
-	if eventName.Body.Result.Global != program.Globals[0].ID {
-		t.Fatalf("event_name loads global %d, want %d", eventName.Body.Result.Global, program.Globals[0].ID)
+	if eventName.Body.Result.Global != globals[0].ID {
+		t.Fatalf("event_name loads global %d, want %d", eventName.Body.Result.Global, globals[0].ID)
 	}
 }
 func TestLowerMutableModuleGlobalAssignment(t *testing.T) {
@@ -189,10 +191,11 @@ func TestLowerMutableModuleGlobalAssignment(t *testing.T) {
 		}
 	`)
 
-	if len(program.Globals) != 1 {
-		t.Fatalf("global count = %d, want 1", len(program.Globals))
+	globals := sourceGlobals(program)
+	if len(globals) != 1 {
+		t.Fatalf("global count = %d, want 1", len(globals))
 	}
-	global := program.Globals[0]
+	global := globals[0]
 	if global.Name != "counter" || !global.Mutable || !global.Private {
 		t.Fatalf("global = %#v, want mutable private counter", global)
 	}
@@ -403,8 +406,8 @@ func TestLowerWhileLoop(t *testing.T) {
 	if len(script.Body.Stmts) != 1 {
 		t.Fatalf("script stmt count = %d, want while only", len(script.Body.Stmts))
 	}
-	if len(program.Globals) != 1 || program.Globals[0].Name != "count" {
-		t.Fatalf("globals = %#v, want mut count", program.Globals)
+	if globals := sourceGlobals(program); len(globals) != 1 || globals[0].Name != "count" {
+		t.Fatalf("globals = %#v, want mut count", globals)
 	}
 	loop := script.Body.Stmts[0]
 	if loop.Kind != StmtWhile {
@@ -863,6 +866,60 @@ fn main() Str {
 		t.Fatalf("Store module paths = %#v, want inbox and issues", paths)
 	}
 }
+
+// The checker has no shared type registry; AIR lowering interns every
+// module's types into one program-wide table keyed by structure, so equal
+// types from different modules share one TypeID.
+func TestLowerSharesStructurallyEqualTypesAcrossModules(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "ard.toml"), []byte("name = \"app\"\nard = \">= 0.1.0\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "lib.ard"), []byte(`
+fn numbers() [Int] {
+  [1, 2]
+}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mainPath := filepath.Join(tempDir, "main.ard")
+	result := parse.Parse([]byte(`
+use app/lib
+
+fn main() Int {
+  let local: [Int] = [3]
+  let imported = lib::numbers()
+  local.size() + imported.size()
+}
+`), mainPath)
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse error: %s", result.Errors[0].Message)
+	}
+	resolver, err := checker.NewModuleResolver(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checker.New(mainPath, result.Program, resolver)
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("checker diagnostics: %v", c.Diagnostics())
+	}
+
+	program, err := Lower(c.Module())
+	if err != nil {
+		t.Fatalf("lower error: %v", err)
+	}
+
+	var lists []TypeInfo
+	for _, typ := range program.Types {
+		if typ.Kind == TypeList && program.Types[typ.Elem-1].Kind == TypeInt {
+			lists = append(lists, typ)
+		}
+	}
+	if len(lists) != 1 {
+		t.Fatalf("[Int] type count = %d, want 1: %#v", len(lists), lists)
+	}
+}
 func TestLowerImportedModuleFunctionCanReadModuleLevelLet(t *testing.T) {
 	tempDir := t.TempDir()
 	if err := os.WriteFile(filepath.Join(tempDir, "ard.toml"), []byte("name = \"app\"\nard = \">= 0.1.0\"\n"), 0o644); err != nil {
@@ -1096,6 +1153,49 @@ func TestLowerRecursiveGenericMethodUsesReceiverTypeParameterIdentity(t *testing
 	}
 }
 
+func TestLowerRecursiveStructParamsAndMethods(t *testing.T) {
+	program := lowerSource(t, `
+		struct Node {
+			value: Int,
+			next: Node?,
+		}
+
+		struct Cell<$T> {
+			value: $T,
+			next: Cell<$T>?,
+		}
+
+		impl Cell {
+			fn len() Int {
+				match self.next {
+					next => 1 + next.len(),
+					_ => 1,
+				}
+			}
+		}
+
+		fn depth(node: Node) Int {
+			match node.next {
+				next => 1 + depth(next),
+				_ => 1,
+			}
+		}
+
+		fn count(cell: Cell<Int>) Int {
+			cell.len()
+		}
+	`)
+
+	depth := findFunction(t, program, "depth")
+	if typeKind(t, program, depth.Signature.Params[0].Type) != TypeStruct {
+		t.Fatalf("depth param kind = %v, want TypeStruct", typeKind(t, program, depth.Signature.Params[0].Type))
+	}
+	method := findFunction(t, program, "Cell.len")
+	if method.Body.Result == nil && len(method.Body.Stmts) == 0 {
+		t.Fatal("recursive generic method lowered without a body")
+	}
+}
+
 func TestLowerGenericStructMethodLowersOnceAsGeneric(t *testing.T) {
 	program := lowerSource(t, `
 		struct Box {
@@ -1296,6 +1396,72 @@ func TestValidateRejectsBadTypeReference(t *testing.T) {
 	}
 }
 
+func TestValidateRejectsMistypedPrograms(t *testing.T) {
+	const source = `
+		fn add(a: Int, b: Int) Int {
+			a + b
+		}
+
+		fn main() Int {
+			let sum = add(1, 2)
+			sum
+		}
+	`
+	tests := []struct {
+		name    string
+		corrupt func(main *Function, add *Function)
+		want    string
+	}{
+		{
+			name: "binding outside the frame",
+			corrupt: func(main *Function, _ *Function) {
+				main.Body.Stmts[0].Local = 99
+			},
+			want: "function main: statement binds invalid local 99",
+		},
+		{
+			name: "missing argument",
+			corrupt: func(main *Function, _ *Function) {
+				main.Body.Stmts[0].Value.Args = main.Body.Stmts[0].Value.Args[:1]
+			},
+			want: "function main: call to add passes 1 args, want 2",
+		},
+		{
+			name: "constant of the wrong kind",
+			corrupt: func(main *Function, _ *Function) {
+				main.Body.Stmts[0].Value.Args[0].Kind = ExprConstStr
+			},
+			want: "function main: string constant has type kind 1, want 8",
+		},
+		{
+			name: "load with the wrong type",
+			corrupt: func(main *Function, add *Function) {
+				main.Body.Result.Type = add.Signature.Return + 1
+			},
+			want: "function main: load of local sum",
+		},
+		{
+			name: "arithmetic missing an operand",
+			corrupt: func(_ *Function, add *Function) {
+				add.Body.Result.Left = nil
+			},
+			want: "function add: binary expression missing an operand",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			program := lowerSource(t, source)
+			main := &program.Functions[findFunction(t, program, "main").ID]
+			add := &program.Functions[findFunction(t, program, "add").ID]
+			tt.corrupt(main, add)
+			err := Validate(program)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Fatalf("Validate error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
 func checkedModuleWithPath(t *testing.T, modulePath string, input string) checker.Module {
 	t.Helper()
 	result := parse.Parse([]byte(input), modulePath+".ard")
@@ -1368,6 +1534,153 @@ func lowerSourceWithTests(t *testing.T, input string) *Program {
 	return program
 }
 
+// sourceGlobals returns the globals declared by the test source, leaving out
+// those of prelude modules such as ard/int.
+func sourceGlobals(program *Program) []Global {
+	var globals []Global
+	for _, global := range program.Globals {
+		if program.Modules[global.Module].Path == "test.ard" {
+			globals = append(globals, global)
+		}
+	}
+	return globals
+}
+
+func TestLowerOrPatternArmsShareOneCase(t *testing.T) {
+	program := lowerSource(t, `
+		enum Color { red, green, blue }
+
+		fn size(n: Int) Str {
+			match n {
+				1 | 2 | 3 => "few",
+				_ => "many",
+			}
+		}
+
+		fn vowel(s: Str) Bool {
+			match s {
+				"a" | "e" => true,
+				_ => false,
+			}
+		}
+
+		fn warm(c: Color) Bool {
+			match c {
+				Color::red | Color::green => true,
+				Color::blue => false,
+			}
+		}
+	`)
+
+	size := findFunction(t, program, "size").Body.Result
+	if size == nil || size.Kind != ExprMatchInt || len(size.IntCases) != 1 {
+		t.Fatalf("size result = %#v, want one int case", size)
+	}
+	if got := size.IntCases[0]; got.Value != 1 || len(got.Also) != 2 || got.Also[0] != 2 || got.Also[1] != 3 {
+		t.Fatalf("int case = %d also %v, want 1 also [2 3]", got.Value, got.Also)
+	}
+	vowel := findFunction(t, program, "vowel").Body.Result
+	if vowel == nil || vowel.Kind != ExprMatchStr || len(vowel.StrCases) != 1 {
+		t.Fatalf("vowel result = %#v, want one str case", vowel)
+	}
+	if got := vowel.StrCases[0]; got.Value != "a" || len(got.Also) != 1 || got.Also[0] != "e" {
+		t.Fatalf("str case = %q also %v, want \"a\" also [e]", got.Value, got.Also)
+	}
+	warm := findFunction(t, program, "warm").Body.Result
+	if warm == nil || warm.Kind != ExprMatchEnum || len(warm.EnumCases) != 2 {
+		t.Fatalf("warm result = %#v, want two enum cases", warm)
+	}
+	if got := warm.EnumCases[0]; got.Discriminant != 0 || len(got.Also) != 1 || got.Also[0] != 1 {
+		t.Fatalf("enum case = %d also %v, want 0 also [1]", got.Discriminant, got.Also)
+	}
+}
+
+func TestLowerWidensNestedUnions(t *testing.T) {
+	program := lowerSource(t, `
+		type Num = Int | Float64
+		type Value = Num | Str
+
+		fn from_int() Value { 1 }
+		fn from_num(n: Num) Value { n }
+		fn main() {}
+	`)
+	value := findType(t, program, "Value")
+	num := findType(t, program, "Num")
+
+	// Int is a member of Num, so it is wrapped into Num and then into Value.
+	fromInt := findFunction(t, program, "from_int").Body.Result
+	if fromInt.Kind != ExprUnionWrap || fromInt.Type != value.ID {
+		t.Fatalf("from_int result = %s, want a wrap into Value", fromInt.Kind)
+	}
+	if inner := fromInt.Target; inner.Kind != ExprUnionWrap || inner.Type != num.ID {
+		t.Fatalf("from_int wraps %s, want a wrap into Num", inner.Kind)
+	}
+
+	// Num is itself a member of Value, so it is wrapped directly.
+	fromNum := findFunction(t, program, "from_num").Body.Result
+	if fromNum.Kind != ExprUnionWrap || fromNum.Target.Type != num.ID {
+		t.Fatalf("from_num result = %s, want a wrap of Num into Value", fromNum.Kind)
+	}
+}
+
+func TestLowerWidensUnionIntoWiderUnion(t *testing.T) {
+	program := lowerSource(t, `
+		type Small = Int | Str
+		type Value = Int | Str | Bool
+
+		fn widen(s: Small) Value { s }
+		fn main() {}
+	`)
+	value := findType(t, program, "Value")
+	widen := findFunction(t, program, "widen").Body.Result
+	if widen.Kind != ExprMatchUnion || widen.Type != value.ID || len(widen.UnionCases) != 2 {
+		t.Fatalf("widen result = %s with %d cases, want a two-case union match", widen.Kind, len(widen.UnionCases))
+	}
+	for _, matchCase := range widen.UnionCases {
+		if result := matchCase.Body.Result; result.Kind != ExprUnionWrap || result.Type != value.ID {
+			t.Fatalf("widen case %d = %s, want a wrap into Value", matchCase.Tag, result.Kind)
+		}
+	}
+}
+
+func TestLowerTryWidensPropagatedErrors(t *testing.T) {
+	program := lowerSource(t, `
+		type Failure = Int | Str
+
+		fn check() Int!Str { Result::ok(1) }
+		fn relay() Int!Failure {
+		  let n = try check()
+		  Result::ok(n)
+		}
+		fn main() {}
+	`)
+	failure := findType(t, program, "Failure")
+	try := findFunction(t, program, "relay").Body.Stmts[0].Value
+	if try.Kind != ExprTryResult || !try.HasCatch {
+		t.Fatalf("try = %s (catch %t), want a try with an implicit catch", try.Kind, try.HasCatch)
+	}
+	propagated := try.Catch.Result
+	if propagated.Kind != ExprMakeResultErr || propagated.Target.Kind != ExprUnionWrap || propagated.Target.Type != failure.ID {
+		t.Fatalf("catch result = %s, want an error wrapped into Failure", propagated.Kind)
+	}
+}
+
+func TestLowerElseLessIfEndingInAValueStaysVoid(t *testing.T) {
+	program := lowerSource(t, `
+		fn send(n: Int) Int!Str { Result::ok(n) }
+		fn maybe_send(n: Int) {
+		  if n > 0 {
+		    send(n)
+		  }
+		}
+		fn main() { maybe_send(1) }
+	`)
+	result := findFunction(t, program, "maybe_send").Body.Result
+	if result.Kind != ExprIf || typeKind(t, program, result.Type) != TypeVoid {
+		t.Fatalf("maybe_send result = %s of type %d, want a Void if", result.Kind, result.Type)
+	}
+}
+
 func findFunction(t *testing.T, program *Program, name string) Function {
 	t.Helper()
 	for _, fn := range program.Functions {
@@ -131,6 +131,20 @@ const (
 	ExprToStr
 	ExprToInt
 	ExprToF64
+	// ExprIntAbs, ExprIntPow, and ExprIntClamp are Int.abs, Int.pow, and
+	// Int.clamp. Target is the receiver and Args are the method arguments.
+	ExprIntAbs
+	ExprIntPow
+	ExprIntClamp
+	// ExprFloatRound, ExprFloatFloor, and ExprFloatCeil are Float64.round,
+	// Float64.floor, and Float64.ceil. Target is the receiver.
+	ExprFloatRound
+	ExprFloatFloor
+	ExprFloatCeil
+	// ExprIntParse and ExprFloatParse are Int::parse and Float64::parse.
+	// Target is the Str to parse and the type is a Result with a Str error.
+	ExprIntParse
+	ExprFloatParse
 	ExprStrAt
 	ExprStrBytes
 	ExprStrRunes
@@ -143,6 +157,27 @@ const (
 	ExprStrEndsWith
 	ExprToAny
 	ExprStrTrim
+	// The Str methods below take Target as the receiver and Args as the
+	// method arguments. Positions and widths count runes.
+	ExprStrTrimStart
+	ExprStrTrimEnd
+	ExprStrSlice
+	ExprStrIndexOf
+	ExprStrToUpper
+	ExprStrToLower
+	ExprStrPadLeft
+	ExprStrPadRight
+	ExprStrRepeat
+	ExprStrReverse
+	ExprStrChars
+	ExprStrGraphemes
+	// ExprStrFormat is Str::format. Target is the template, Args are the
+	// positional arguments, and Entries pair each named argument's name (a
+	// Str constant) with its value.
+	ExprStrFormat
+	// ExprInspect renders Target as Str the way it is written in Ard source.
+	// Targets render it from Target's type, so every type needs a rendering.
+	ExprInspect
 	ExprEq
 	ExprNotEq
 	ExprLt
@@ -166,6 +201,11 @@ const (
 	ExprMakeMaybeNone
 	ExprMakeMaybeNew
 	ExprMakeError
+	// ExprWrapError is Error::wrap: Target is the wrapped Error and Args holds
+	// the context Str. ExprErrorCause is Error::cause: a Maybe of the Error
+	// Target wraps.
+	ExprWrapError
+	ExprErrorCause
 	ExprMatchMaybe
 	ExprMaybeExpect
 	ExprMaybeIsNone
@@ -197,14 +237,26 @@ const (
 	ForeignResultValueBool
 )
 
+// IntLimit marks an ExprConstInt that stands for Int::MAX or Int::MIN. Int
+// has 64 bits on the Go target, but JavaScript numbers are exact only up to
+// 2^53, so that target emits its own bounds for these constants.
+type IntLimit uint8
+
+const (
+	NoIntLimit IntLimit = iota
+	IntMax
+	IntMin
+)
+
 type Expr struct {
 	Kind ExprKind
 	Type TypeID
 
-	Int   string
-	Float string
-	Bool  bool
-	Str   string
+	Int      string
+	IntLimit IntLimit
+	Float    string
+	Bool     bool
+	Str      string
 
 	Variant      int
 	Discriminant int
@@ -301,17 +353,32 @@ type MapEntry struct {
 type EnumMatchCase struct {
 	Variant      int
 	Discriminant int
-	Body         Block
+	// Also lists the discriminants of the other variants of an or-pattern
+	// arm, which share Body.
+	Also     []int
+	Bindings []EnumPayloadBinding
+	Body     Block
+}
+
+// EnumPayloadBinding binds the payload value at Index of the matched variant
+// to Local before the case body runs.
+type EnumPayloadBinding struct {
+	Index int
+	Local LocalID
 }
 
 type IntMatchCase struct {
 	Value int
-	Body  Block
+	// Also lists the other values of an or-pattern arm, which share Body.
+	Also []int
+	Body Block
 }
 
 type StrMatchCase struct {
 	Value string
-	Body  Block
+	// Also lists the other values of an or-pattern arm, which share Body.
+	Also []string
+	Body Block
 }
 
 type IntRangeMatchCase struct {
@@ -1,17 +1,46 @@
 package air
 
-// AIR serialization round-trips within one compiler binary only: node kind
-// enums are iota-assigned and not stable across versions. If this format
-// ever backs an on-disk cache, it must gain explicit numbering and a format
-// version first.
+// Serialized AIR starts with a header naming its format version and the
+// compiler release that wrote it. The body is gob, and node kind enums are
+// iota-assigned and not stable across releases, so the compatibility policy
+// is strict: a program only loads in the release that built it, and any other
+// release reports both versions so the program can be rebuilt. FormatVersion
+// changes when the header or envelope changes, so later releases can still
+// recognize and explain artifacts they no longer load.
 
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
+
+	"github.com/akonwi/ard/version"
+)
+
+const (
+	// FormatMagic opens every serialized program.
+	FormatMagic = "ARDAIR"
+	// FormatVersion is the version of the serialized program format.
+	FormatVersion = 1
 )
 
+// IncompatibleProgramError reports serialized AIR that this compiler cannot
+// load because a different release or format wrote it.
+type IncompatibleProgramError struct {
+	Format    int
+	BuiltWith string
+	Compiler  string
+}
+
+func (e IncompatibleProgramError) Error() string {
+	if e.Format != FormatVersion {
+		return fmt.Sprintf("program uses AIR format %d, this compiler reads format %d; rebuild it with ard %s", e.Format, FormatVersion, e.Compiler)
+	}
+	return fmt.Sprintf("program was built with ard %s, this compiler is ard %s; rebuild it with this compiler", e.BuiltWith, e.Compiler)
+}
+
 func SerializeProgram(program *Program) ([]byte, error) {
 	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %d %s\n", FormatMagic, FormatVersion, version.Get())
 	enc := gob.NewEncoder(&buf)
 	if err := enc.Encode(program); err != nil {
 		return nil, err
@@ -19,11 +48,45 @@ func SerializeProgram(program *Program) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// Header is the first line of serialized AIR.
+type Header struct {
+	Format   int
+	Compiler string
+}
+
+// ReadHeader reads the header of serialized AIR without decoding the
+// program, so artifacts from other releases can still be described.
+func ReadHeader(data []byte) (Header, error) {
+	line, _, found := bytes.Cut(data, []byte("\n"))
+	var magic string
+	var header Header
+	if !found {
+		return Header{}, fmt.Errorf("not a serialized Ard program")
+	}
+	if _, err := fmt.Sscanf(string(line), "%s %d %s", &magic, &header.Format, &header.Compiler); err != nil || magic != FormatMagic {
+		return Header{}, fmt.Errorf("not a serialized Ard program")
+	}
+	return header, nil
+}
+
+// DeserializeProgram checks the header, then decodes and validates the
+// program, so corrupted data fails here rather than in a backend.
 func DeserializeProgram(data []byte) (*Program, error) {
+	header, err := ReadHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if header.Format != FormatVersion || header.Compiler != version.Get() {
+		return nil, IncompatibleProgramError{Format: header.Format, BuiltWith: header.Compiler, Compiler: version.Get()}
+	}
+	_, body, _ := bytes.Cut(data, []byte("\n"))
 	var program Program
-	dec := gob.NewDecoder(bytes.NewReader(data))
+	dec := gob.NewDecoder(bytes.NewReader(body))
 	if err := dec.Decode(&program); err != nil {
 		return nil, err
 	}
+	if err := Validate(&program); err != nil {
+		return nil, err
+	}
 	return &program, nil
 }
@@ -1,11 +1,14 @@
 package air_test
 
 import (
+	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/akonwi/ard/air"
 	"github.com/akonwi/ard/checker"
 	"github.com/akonwi/ard/parse"
+	"github.com/akonwi/ard/version"
 )
 
 func TestSerializeProgram(t *testing.T) {
@@ -45,3 +48,90 @@ func TestSerializeProgram(t *testing.T) {
 		t.Fatalf("decoded functions = %d, want %d", len(decoded.Functions), len(program.Functions))
 	}
 }
+
+func TestSerializeProgramKeepsEnumPayloads(t *testing.T) {
+	result := parse.Parse([]byte(`
+		enum Event { Click(Int, Int), Quit }
+
+		fn main() Int {
+			match Event::Click(20, 22) {
+				Event::Click(x, y) => x + y,
+				Event::Quit => 0,
+			}
+		}
+	`), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse error: %s", result.Errors[0].Message)
+	}
+	c := checker.New("main.ard", result.Program, nil)
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("checker diagnostics: %v", c.Diagnostics())
+	}
+	program, err := air.Lower(c.Module())
+	if err != nil {
+		t.Fatalf("lower AIR: %v", err)
+	}
+	data, err := air.SerializeProgram(program)
+	if err != nil {
+		t.Fatalf("serialize AIR: %v", err)
+	}
+	decoded, err := air.DeserializeProgram(data)
+	if err != nil {
+		t.Fatalf("deserialize AIR: %v", err)
+	}
+	if err := air.Validate(decoded); err != nil {
+		t.Fatalf("validate decoded AIR: %v", err)
+	}
+	var event air.TypeInfo
+	for _, typ := range decoded.Types {
+		if typ.Kind == air.TypeEnum && typ.Name == "Event" {
+			event = typ
+		}
+	}
+	if !event.HasPayload() || len(event.Variants[0].Payload) != 2 || len(event.Variants[1].Payload) != 0 {
+		t.Fatalf("decoded Event variants = %+v, want Click(Int, Int) and Quit", event.Variants)
+	}
+}
+
+func TestDeserializeProgramRejectsOtherReleases(t *testing.T) {
+	result := parse.Parse([]byte("fn main() {}"), "main.ard")
+	c := checker.New("main.ard", result.Program, nil)
+	c.Check()
+	program, err := air.Lower(c.Module())
+	if err != nil {
+		t.Fatalf("lower AIR: %v", err)
+	}
+	released := version.Version
+	t.Cleanup(func() { version.Version = released })
+
+	version.Version = "0.21.0"
+	data, err := air.SerializeProgram(program)
+	if err != nil {
+		t.Fatalf("serialize AIR: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("ARDAIR 1 0.21.0\n")) {
+		t.Fatalf("header = %q", data[:min(len(data), 20)])
+	}
+	if _, err := air.DeserializeProgram(data); err != nil {
+		t.Fatalf("deserialize with the same release: %v", err)
+	}
+
+	version.Version = "0.23.0"
+	_, err = air.DeserializeProgram(data)
+	var incompatible air.IncompatibleProgramError
+	if !errors.As(err, &incompatible) {
+		t.Fatalf("deserialize error = %v, want IncompatibleProgramError", err)
+	}
+	if want := "program was built with ard 0.21.0, this compiler is ard 0.23.0; rebuild it with this compiler"; err.Error() != want {
+		t.Fatalf("error = %q, want %q", err.Error(), want)
+	}
+
+	newer := bytes.Replace(data, []byte("ARDAIR 1"), []byte("ARDAIR 2"), 1)
+	if _, err := air.DeserializeProgram(newer); err == nil || err.Error() != "program uses AIR format 2, this compiler reads format 1; rebuild it with ard 0.23.0" {
+		t.Fatalf("format error = %v", err)
+	}
+	if _, err := air.DeserializeProgram([]byte("package main\n")); err == nil || err.Error() != "not a serialized Ard program" {
+		t.Fatalf("foreign data error = %v", err)
+	}
+}
@@ -1,5 +1,7 @@
 package air
 
+import "sort"
+
 type ModuleID int
 type TypeID int
 type FunctionID int
@@ -27,9 +29,12 @@ type Program struct {
 }
 
 type Module struct {
-	ID        ModuleID
-	Path      string
-	Imports   []ModuleID
+	ID      ModuleID
+	Path    string
+	Imports []ModuleID
+	// GoImports are the Go packages the module's source imports with
+	// `use go:`, sorted by path.
+	GoImports []string
 	Types     []TypeID
 	Globals   []GlobalID
 	Functions []FunctionID
@@ -185,15 +190,40 @@ type TypeInfo struct {
 }
 
 type FieldInfo struct {
-	Name    string
-	Type    TypeID
-	Index   int
-	Mutable bool
+	Name  string
+	Type  TypeID
+	Index int
+	// Declared is the field's position in the struct's declaration; Fields
+	// is sorted by name.
+	Declared int
+	Mutable  bool
 }
 
 type VariantInfo struct {
 	Name         string
 	Discriminant int
+	// Payload lists the types of the variant's associated data. An enum with
+	// any payload is a tagged value rather than a plain integer.
+	Payload []TypeID
+}
+
+// HasPayload reports whether t is an enum with a variant that carries
+// associated data.
+func (t TypeInfo) HasPayload() bool {
+	for _, variant := range t.Variants {
+		if len(variant.Payload) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// DeclaredFields returns a struct's fields in the order its declaration
+// lists them, for output that should read like the source.
+func (t TypeInfo) DeclaredFields() []FieldInfo {
+	fields := append([]FieldInfo(nil), t.Fields...)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Declared < fields[j].Declared })
+	return fields
 }
 
 type UnionMember struct {
@@ -232,6 +232,9 @@ func validateBlock(program *Program, fn Function, block Block) error {
 		if stmt.Type != NoType && !validTypeID(program, stmt.Type) {
 			return fmt.Errorf("statement has invalid type %d", stmt.Type)
 		}
+		if err := validateStmtTypes(program, fn, stmt); err != nil {
+			return err
+		}
 		if stmt.Value != nil {
 			if err := validateExpr(program, fn, *stmt.Value); err != nil {
 				return err
@@ -328,6 +331,9 @@ func validateExpr(program *Program, fn Function, expr Expr) error {
 	if expr.Kind == ExprMakeClosure && !validFunctionID(program, expr.Function) {
 		return fmt.Errorf("expression creates invalid closure function %d", expr.Function)
 	}
+	if err := validateExprTypes(program, fn, expr); err != nil {
+		return err
+	}
 	if expr.Kind == ExprMakeClosure && validFunctionID(program, expr.Function) {
 		closureFn := program.Functions[expr.Function]
 		if len(expr.CaptureLocals) != len(closureFn.Captures) {
@@ -375,6 +381,12 @@ func validateExpr(program *Program, fn Function, expr Expr) error {
 			return fmt.Errorf("unsafe::cast expression expects one target type, got %d", len(expr.TypeArgs))
 		}
 	}
+	if expr.Kind == ExprStrFormat && expr.Target == nil {
+		return fmt.Errorf("Str::format expression missing template")
+	}
+	if expr.Kind == ExprInspect && expr.Target == nil {
+		return fmt.Errorf("inspect expression missing target")
+	}
 	if expr.Kind == ExprUnsafeIsNil && expr.Target == nil {
 		return fmt.Errorf("unsafe::is_nil expression missing target")
 	}
@@ -458,8 +470,25 @@ func validateExpr(program *Program, fn Function, expr Expr) error {
 			return err
 		}
 	}
+	if expr.Kind == ExprEnumVariant && len(expr.Args) > 0 {
+		enumType, err := typeInfo(program, expr.Type)
+		if err != nil {
+			return err
+		}
+		if enumType.Kind != TypeEnum || expr.Variant < 0 || expr.Variant >= len(enumType.Variants) {
+			return fmt.Errorf("enum variant %d is invalid for type %s", expr.Variant, enumType.Name)
+		}
+		if want := len(enumType.Variants[expr.Variant].Payload); len(expr.Args) != want {
+			return fmt.Errorf("enum variant %s has %d payload values, want %d", enumType.Variants[expr.Variant].Name, len(expr.Args), want)
+		}
+	}
 	if expr.Kind == ExprMatchEnum {
 		for _, matchCase := range expr.EnumCases {
+			for _, binding := range matchCase.Bindings {
+				if binding.Local < 0 || int(binding.Local) >= len(fn.Locals) {
+					return fmt.Errorf("enum match binds invalid local %d", binding.Local)
+				}
+			}
 			if err := validateBlock(program, fn, matchCase.Body); err != nil {
 				return err
 			}
@@ -0,0 +1,207 @@
+package air
+
+import "fmt"
+
+// The checks below make Validate type-aware: beyond every id being in range,
+// each node's operands must have the types its kind consumes and its own type
+// must be the one its kind produces. A program that passes them cannot make a
+// backend index past a table or lower an operation on the wrong kind of
+// value, so a corrupted or hand-built program fails here instead.
+
+func validateStmtTypes(program *Program, fn Function, stmt Stmt) error {
+	switch stmt.Kind {
+	case StmtLet, StmtAssign:
+		if !validLocalID(fn, stmt.Local) {
+			return fmt.Errorf("statement binds invalid local %d", stmt.Local)
+		}
+		if stmt.Value == nil {
+			return fmt.Errorf("statement binding local %s missing value", fn.Locals[stmt.Local].Name)
+		}
+		if want := fn.Locals[stmt.Local].Type; !assignableType(program, stmt.Value.Type, want) {
+			return fmt.Errorf("local %s is bound to type %d, want %d", fn.Locals[stmt.Local].Name, stmt.Value.Type, want)
+		}
+	case StmtForMap:
+		if !validLocalID(fn, stmt.Local) || !validLocalID(fn, stmt.ValueLocal) {
+			return fmt.Errorf("map loop binds invalid locals %d and %d", stmt.Local, stmt.ValueLocal)
+		}
+	case StmtWhile:
+		if stmt.Condition != nil {
+			if err := requireKind(program, *stmt.Condition, "while condition", TypeBool); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateExprTypes(program *Program, fn Function, expr Expr) error {
+	switch expr.Kind {
+	case ExprConstBool:
+		return requireKind(program, expr, "bool constant", TypeBool)
+	case ExprConstStr:
+		return requireKind(program, expr, "string constant", TypeStr)
+	case ExprLoadLocal:
+		if want := fn.Locals[expr.Local].Type; expr.Type != want {
+			return fmt.Errorf("load of local %s has type %d, want %d", fn.Locals[expr.Local].Name, expr.Type, want)
+		}
+	case ExprLoadGlobal:
+		if want := program.Globals[expr.Global].Type; expr.Type != want {
+			return fmt.Errorf("load of global %s has type %d, want %d", program.Globals[expr.Global].Name, expr.Type, want)
+		}
+	case ExprCall:
+		callee := program.Functions[expr.Function]
+		if len(expr.Args) != len(callee.Signature.Params) {
+			return fmt.Errorf("call to %s passes %d args, want %d", callee.Name, len(expr.Args), len(callee.Signature.Params))
+		}
+		if len(expr.TypeArgs) > 0 {
+			return nil
+		}
+		for i, param := range callee.Signature.Params {
+			if !assignableType(program, expr.Args[i].Type, param.Type) {
+				return fmt.Errorf("call to %s passes type %d for %s, want %d", callee.Name, expr.Args[i].Type, param.Name, param.Type)
+			}
+		}
+		if expr.Type != callee.Signature.Return {
+			return fmt.Errorf("call to %s has type %d, want return type %d", callee.Name, expr.Type, callee.Signature.Return)
+		}
+	case ExprIntAdd, ExprIntSub, ExprIntMul, ExprIntDiv, ExprIntMod,
+		ExprFloatAdd, ExprFloatSub, ExprFloatMul, ExprFloatDiv, ExprStrConcat:
+		left, right, err := binaryOperands(expr)
+		if err != nil {
+			return err
+		}
+		if left.Type != expr.Type || right.Type != expr.Type {
+			return fmt.Errorf("arithmetic on types %d and %d has type %d", left.Type, right.Type, expr.Type)
+		}
+	case ExprEq, ExprNotEq, ExprLt, ExprLte, ExprGt, ExprGte:
+		if _, _, err := binaryOperands(expr); err != nil {
+			return err
+		}
+		return requireKind(program, expr, "comparison", TypeBool)
+	case ExprAnd, ExprOr:
+		left, right, err := binaryOperands(expr)
+		if err != nil {
+			return err
+		}
+		for _, operand := range []Expr{expr, *left, *right} {
+			if err := requireKind(program, operand, "logical operand", TypeBool); err != nil {
+				return err
+			}
+		}
+	case ExprNot:
+		if expr.Target == nil {
+			return fmt.Errorf("not expression missing operand")
+		}
+		if err := requireKind(program, *expr.Target, "not operand", TypeBool); err != nil {
+			return err
+		}
+		return requireKind(program, expr, "not expression", TypeBool)
+	case ExprIf:
+		if expr.Condition == nil {
+			return fmt.Errorf("if expression missing condition")
+		}
+		return requireKind(program, *expr.Condition, "if condition", TypeBool)
+	case ExprGetField:
+		if expr.Target == nil {
+			return fmt.Errorf("field access missing target")
+		}
+		structType, err := typeInfo(program, expr.Target.Type)
+		if err != nil {
+			return err
+		}
+		if structType.Kind != TypeStruct {
+			return fmt.Errorf("field access target has type kind %d", structType.Kind)
+		}
+		if expr.Field < 0 || expr.Field >= len(structType.Fields) {
+			return fmt.Errorf("field access index %d out of range for %s", expr.Field, structType.Name)
+		}
+	case ExprMakeStruct:
+		structType, err := typeInfo(program, expr.Type)
+		if err != nil {
+			return err
+		}
+		if structType.Kind != TypeStruct {
+			return fmt.Errorf("struct literal has type kind %d", structType.Kind)
+		}
+		for _, field := range expr.Fields {
+			if field.Index < 0 || field.Index >= len(structType.Fields) {
+				return fmt.Errorf("struct literal field index %d out of range for %s", field.Index, structType.Name)
+			}
+		}
+	case ExprListSize, ExprMapSize, ExprStrSize:
+		return requireKind(program, expr, "size", TypeInt)
+	}
+	return nil
+}
+
+// assignableType reports whether a value of type from may be stored where to
+// is expected. Besides identical types, Go's assignability lets a named
+// foreign type and its underlying type stand in for each other, and a generic
+// definition's types stand in for their instantiations.
+func assignableType(program *Program, from TypeID, to TypeID) bool {
+	if from == to {
+		return true
+	}
+	a, err := typeInfo(program, from)
+	if err != nil {
+		return false
+	}
+	b, err := typeInfo(program, to)
+	if err != nil {
+		return false
+	}
+	if a.Kind == TypeForeignType || b.Kind == TypeForeignType || a.Kind == TypeParam || b.Kind == TypeParam {
+		return true
+	}
+	if a.Kind != b.Kind {
+		return false
+	}
+	switch a.Kind {
+	case TypeList, TypeMaybe, TypeChannel, TypeReceiver, TypeSender, TypeReference:
+		return assignableType(program, a.Elem, b.Elem)
+	case TypeFixedArray:
+		return a.Length == b.Length && assignableType(program, a.Elem, b.Elem)
+	case TypeMap:
+		return assignableType(program, a.Key, b.Key) && assignableType(program, a.Value, b.Value)
+	case TypeResult:
+		return assignableType(program, a.Value, b.Value) && assignableType(program, a.Error, b.Error)
+	case TypeFunction:
+		if len(a.Params) != len(b.Params) {
+			return false
+		}
+		for i := range a.Params {
+			if !assignableType(program, a.Params[i], b.Params[i]) {
+				return false
+			}
+		}
+		return assignableType(program, a.Return, b.Return)
+	case TypeStruct, TypeEnum, TypeUnion, TypeTraitObject:
+		if len(a.TypeParams) > 0 || len(b.TypeParams) > 0 {
+			return true
+		}
+		return a.Generic != NoType && (a.Generic == b.Generic || a.Generic == to) || b.Generic == from
+	}
+	return false
+}
+
+func binaryOperands(expr Expr) (*Expr, *Expr, error) {
+	if expr.Left == nil || expr.Right == nil {
+		return nil, nil, fmt.Errorf("binary expression missing an operand")
+	}
+	return expr.Left, expr.Right, nil
+}
+
+func requireKind(program *Program, expr Expr, what string, kind TypeKind) error {
+	info, err := typeInfo(program, expr.Type)
+	if err != nil {
+		return err
+	}
+	if info.Kind != kind {
+		return fmt.Errorf("%s has type kind %d, want %d", what, info.Kind, kind)
+	}
+	return nil
+}
+
+func validLocalID(fn Function, id LocalID) bool {
+	return id >= 0 && int(id) < len(fn.Locals)
+}
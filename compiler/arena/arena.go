@@ -0,0 +1,32 @@
+// Package arena allocates syntax and checker nodes in bulk.
+package arena
+
+// slabSize is how many nodes share one allocation.
+const slabSize = 64
+
+// Slab hands out values of one type from shared backing arrays, so a file's
+// thousands of identifiers cost a handful of allocations instead of one each.
+// A slab never reuses a value: the values of a backing array live as long as
+// any one of them is reachable, which for a file's nodes is as long as the
+// file's tree is kept.
+//
+// The zero Slab is ready to use. A Slab is not safe for concurrent use.
+type Slab[T any] struct {
+	chunk []T
+}
+
+// New returns a pointer to a zero T.
+func (s *Slab[T]) New() *T {
+	if len(s.chunk) == cap(s.chunk) {
+		s.chunk = make([]T, 0, slabSize)
+	}
+	s.chunk = s.chunk[:len(s.chunk)+1]
+	return &s.chunk[len(s.chunk)-1]
+}
+
+// Put returns a pointer to a copy of value.
+func (s *Slab[T]) Put(value T) *T {
+	node := s.New()
+	*node = value
+	return node
+}
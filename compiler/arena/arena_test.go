@@ -0,0 +1,23 @@
+package arena
+
+import "testing"
+
+func TestSlabHandsOutDistinctValues(t *testing.T) {
+	var slab Slab[int]
+	values := make([]*int, slabSize*3)
+	for i := range values {
+		values[i] = slab.Put(i)
+	}
+	for i, value := range values {
+		if *value != i {
+			t.Fatalf("value %d = %d, want it kept as written", i, *value)
+		}
+	}
+}
+
+func TestSlabStartsValuesAtZero(t *testing.T) {
+	var slab Slab[string]
+	if value := slab.New(); *value != "" {
+		t.Fatalf("New() = %q, want the zero value", *value)
+	}
+}
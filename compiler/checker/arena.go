@@ -0,0 +1,13 @@
+package checker
+
+import "github.com/akonwi/ard/arena"
+
+// nodeArena holds a slab for each of the nodes a check makes the most of,
+// so checking a large file makes one allocation per few dozen nodes instead
+// of one per node.
+type nodeArena struct {
+	statements arena.Slab[Statement]
+	blocks     arena.Slab[Block]
+	variables  arena.Slab[Variable]
+	strs       arena.Slab[StrLiteral]
+}
@@ -0,0 +1,45 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestAsExpressions(t *testing.T) {
+	run(t, []test{
+		{
+			name: "as narrows a union to a Maybe of the member",
+			input: `struct Circle { radius: Int }
+struct Square { side: Int }
+type Shape = Circle | Square
+
+fn radius(shape: Shape) Int {
+  let circle: Circle? = shape as Circle
+  circle.or(Circle{radius: 0}).radius
+}`,
+		},
+		{
+			name: "the type must be a member of the union",
+			input: `struct Circle { radius: Int }
+struct Square { side: Int }
+type Shape = Circle | Square
+
+fn check(shape: Shape) {
+  let n = shape as Int
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type Int is not part of union Shape"},
+			},
+		},
+		{
+			name: "the value must be a union",
+			input: `fn check(n: Int) {
+  let s = n as Str
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Cannot use 'as' on Int: only union values can be narrowed"},
+			},
+		},
+	})
+}
@@ -0,0 +1,125 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// checkAssert checks `assert cond, message` and `ensure cond, message`. The
+// failure message names the statement's location and condition, followed by
+// the compared operands when the condition is a comparison of printable
+// values.
+func (c *Checker) checkAssert(s *parse.Assert) *Statement {
+	condition := c.checkExpr(s.Condition)
+	if condition == nil {
+		return nil
+	}
+	if condition.Type() != Bool {
+		c.addDiagnostic(nonBooleanAssertionDiagnostic{Keyword: s.Keyword(), Actual: condition.Type(), Span: c.sourceSpan(s.Condition.GetLocation())}.build())
+		return nil
+	}
+	var message Expression
+	if s.Message != nil {
+		message = c.checkExprAs(s.Message, Str)
+		if message == nil {
+			return nil
+		}
+		if message.Type() != Str {
+			c.addTypeMismatch(Str, message.Type(), s.Message.GetLocation())
+			return nil
+		}
+	}
+
+	location := s.GetLocation().Start
+	heading := fmt.Sprintf("%s failed at %s:%d:%d: ", s.Keyword(), c.filePath, location.Row, location.Col)
+	chunks := []Expression{&StrLiteral{Value: heading}}
+	if message != nil {
+		chunks = append(chunks, message, &StrLiteral{Value: "\n  condition: " + s.Source})
+	} else {
+		chunks = append(chunks, &StrLiteral{Value: s.Source})
+	}
+
+	body := []Statement{}
+	if left, right, rebuild := comparisonOperands(condition); rebuild != nil && c.canStringify(left.Type()) && c.canStringify(right.Type()) {
+		// the position keeps the names distinct between asserts in one function
+		suffix := fmt.Sprintf("%d_%d", location.Row, location.Col)
+		leftVar := c.assertOperand("assert$left"+suffix, left, &body)
+		rightVar := c.assertOperand("assert$right"+suffix, right, &body)
+		condition = rebuild(leftVar, rightVar)
+		chunks = append(chunks, &StrLiteral{Value: "\n  left: "})
+		chunks = append(chunks, c.displayOperand(leftVar)...)
+		chunks = append(chunks, &StrLiteral{Value: "\n  right: "})
+		chunks = append(chunks, c.displayOperand(rightVar)...)
+	}
+
+	failure := &Panic{
+		Message: &TemplateStr{Chunks: chunks},
+		node:    &parse.FunctionCall{Location: s.GetLocation(), Name: "panic"},
+	}
+	body = append(body, Statement{Expr: &If{Branches: []IfBranch{{
+		Condition: &Not{Value: condition},
+		Body:      &Block{Stmts: []Statement{{Expr: failure}}},
+	}}}})
+	return &Statement{Stmt: &Assert{Debug: !s.Ensure, Body: body}}
+}
+
+// assertOperand binds an operand to a hidden local so the condition and the
+// failure message share one evaluation.
+func (c *Checker) assertOperand(name string, value Expression, body *[]Statement) *Variable {
+	*body = append(*body, Statement{Stmt: &VariableDef{Name: name, __type: value.Type(), Value: value}})
+	return &Variable{sym: Symbol{Name: name, Type: value.Type()}}
+}
+
+// displayOperand renders an operand for a failure message, quoting Str
+// values so empty and padded strings stay visible.
+func (c *Checker) displayOperand(operand *Variable) []Expression {
+	if operand.Type() == Str {
+		return []Expression{&StrLiteral{Value: `"`}, operand, &StrLiteral{Value: `"`}}
+	}
+	return []Expression{c.stringify(operand, parse.Location{})}
+}
+
+// canStringify reports whether stringify converts values of t without a
+// diagnostic.
+func (c *Checker) canStringify(t Type) bool {
+	if t == Str || foreignScalarPrimitive(t) != nil || isInspectable(t) {
+		return true
+	}
+	if toStr, ok := t.get("to_str").(*FunctionDef); ok && toStr.ReturnType == Str && len(toStr.Parameters) == 0 {
+		return true
+	}
+	if strMod := c.findModuleByPath("ard/string"); strMod != nil {
+		return t.hasTrait(strMod.Get("ToString").Type.(*Trait))
+	}
+	return false
+}
+
+// comparisonOperands splits a checked comparison into its operands and a
+// function that rebuilds the same comparison over new operands. rebuild is
+// nil for any other condition.
+func comparisonOperands(condition Expression) (Expression, Expression, func(left, right Expression) Expression) {
+	switch e := condition.(type) {
+	case *Equality:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &Equality{l, r} }
+	case *Inequality:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &Inequality{l, r} }
+	case *IntGreater:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &IntGreater{l, r} }
+	case *IntGreaterEqual:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &IntGreaterEqual{l, r} }
+	case *IntLess:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &IntLess{l, r} }
+	case *IntLessEqual:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &IntLessEqual{l, r} }
+	case *FloatGreater:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &FloatGreater{l, r} }
+	case *FloatGreaterEqual:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &FloatGreaterEqual{l, r} }
+	case *FloatLess:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &FloatLess{l, r} }
+	case *FloatLessEqual:
+		return e.Left, e.Right, func(l, r Expression) Expression { return &FloatLessEqual{l, r} }
+	}
+	return nil, nil, nil
+}
@@ -0,0 +1,36 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestAssertStatements(t *testing.T) {
+	run(t, []test{
+		{
+			name: "assert and ensure accept boolean conditions",
+			input: `fn main() {
+  let total = 3
+  assert total == 3
+  ensure total > 0, "total must be positive"
+  assert "a" + "b" == "ab", "concat"
+}`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "conditions must be boolean",
+			input: `fn main() {
+  assert 1
+}`,
+			diagnostics: []checker.Diagnostic{{Kind: checker.Error, Message: "Assert conditions must be boolean expressions"}},
+		},
+		{
+			name: "messages must be strings",
+			input: `fn main() {
+  ensure true, 42
+}`,
+			diagnostics: []checker.Diagnostic{{Kind: checker.Error, Message: "Type mismatch: Expected Str, got Int"}},
+		},
+	})
+}
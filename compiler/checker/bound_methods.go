@@ -0,0 +1,201 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// boundReceiverName is the hidden local a BoundMethod stores its receiver in.
+// It can't be written in source, so it never shadows a user binding.
+const boundReceiverName = "$receiver"
+
+// checkBoundMethod checks `subject.method` used as a value instead of being
+// called, e.g. `nums.map(scale.apply)`. The result is a closure over the
+// method's remaining parameters that calls it on subject. ok is false when
+// subject has no such method, so the caller can report a missing field.
+func (c *Checker) checkBoundMethod(s *parse.InstanceProperty, subj Expression) (Expression, bool) {
+	name := s.Property.Name
+	var method *FunctionDef
+	var receiverBindings map[string]Type
+	switch subjType := subj.Type().(type) {
+	case *ForeignType:
+		return nil, false
+	case *StructDef:
+		if _, isField := structField(subjType, name); isField {
+			return nil, false
+		}
+		found, ok := c.structMethod(subjType, name)
+		if !ok {
+			return nil, false
+		}
+		method = found
+		if originalDef := c.structDefinition(subjType); originalDef != nil && originalDef.hasGenerics() {
+			receiverBindings = c.extractGenericBindingsFromSpecializedStruct(originalDef, subjType)
+		}
+	default:
+		found, ok := subjType.get(name).(*FunctionDef)
+		if !ok {
+			return nil, false
+		}
+		method = found
+	}
+
+	params := make([]Parameter, len(method.Parameters))
+	for i, param := range method.Parameters {
+		params[i] = Parameter{Name: fmt.Sprintf("$arg%d", i), Type: substituteTypeBindings(param.Type, receiverBindings), Mutable: param.Mutable}
+	}
+	returnType := substituteTypeBindings(method.ReturnType, receiverBindings)
+	if c.rejectMethodValueGenerics(params, returnType, s.Property.GetLocation()) {
+		return nil, true
+	}
+
+	// A receiver that is a variable or one of its fields is read again on each
+	// call, like any closure capture, so mutating methods act on the original.
+	// Any other receiver is evaluated once, when the method is bound.
+	if isPlaceExpression(s.Target) {
+		fn := c.checkMethodClosure(s.Target, name, params, returnType, nil, s.Property.GetLocation())
+		if fn == nil {
+			return nil, true
+		}
+		return &BoundMethod{Function: fn}, true
+	}
+	receiver := &parse.Identifier{Location: s.Target.GetLocation(), Name: boundReceiverName}
+	fn := c.checkMethodClosure(receiver, name, params, returnType, func() {
+		c.scope.add(boundReceiverName, subj.Type(), false)
+	}, s.Property.GetLocation())
+	if fn == nil {
+		return nil, true
+	}
+	return &BoundMethod{Receiver: subj, ReceiverName: boundReceiverName, Function: fn}, true
+}
+
+// checkMethodReference checks `Type::method` naming an instance method as a
+// value. The result takes the receiver as its first parameter, followed by the
+// method's own.
+func (c *Checker) checkMethodReference(owner Type, typeName string, prop *parse.Identifier) (Expression, bool) {
+	var method *FunctionDef
+	switch def := owner.(type) {
+	case *StructDef:
+		found, ok := c.structMethod(def, prop.Name)
+		if !ok {
+			return nil, false
+		}
+		if def.hasGenerics() {
+			c.addError(fmt.Sprintf("Methods of generic type %s can't be referenced as values; bind one to an instance instead", typeName), prop.GetLocation())
+			return nil, true
+		}
+		method = found
+	case *Enum:
+		found, ok := def.Methods[prop.Name]
+		if !ok {
+			return nil, false
+		}
+		method = found
+	default:
+		return nil, false
+	}
+
+	params := make([]Parameter, len(method.Parameters)+1)
+	params[0] = Parameter{Name: boundReceiverName, Type: owner, Mutable: method.Mutates}
+	for i, param := range method.Parameters {
+		params[i+1] = Parameter{Name: fmt.Sprintf("$arg%d", i), Type: param.Type, Mutable: param.Mutable}
+	}
+	if c.rejectMethodValueGenerics(params, method.ReturnType, prop.GetLocation()) {
+		return nil, true
+	}
+	receiver := &parse.Identifier{Location: prop.GetLocation(), Name: boundReceiverName}
+	fn := c.checkMethodClosure(receiver, prop.Name, params, method.ReturnType, nil, prop.GetLocation())
+	if fn == nil {
+		return nil, true
+	}
+	return &BoundMethod{Function: fn}, true
+}
+
+// checkMethodClosure checks a closure taking params whose body calls method on
+// receiver, passing every parameter but a receiver one along. setup declares a
+// hidden receiver the body reads from the enclosing scope.
+func (c *Checker) checkMethodClosure(receiver parse.Expression, method string, params []Parameter, returnType Type, setup func(), loc parse.Location) *FunctionDef {
+	args := []parse.Argument{}
+	for _, param := range params {
+		if param.Name == boundReceiverName {
+			continue
+		}
+		var value parse.Expression = &parse.Identifier{Location: loc, Name: param.Name}
+		if param.Mutable {
+			value = &parse.MutRef{Location: loc, Operand: value}
+		}
+		args = append(args, parse.Argument{Location: loc, Value: value})
+	}
+	call := &parse.InstanceMethod{
+		Location: loc,
+		Target:   receiver,
+		Method:   parse.FunctionCall{Location: loc, Name: method, Args: args},
+	}
+
+	fn := &FunctionDef{
+		Name:              fmt.Sprintf("anon_func_%p", call),
+		CallGenericParams: []string{},
+		Parameters:        params,
+		ReturnType:        returnType,
+	}
+	diagnosticsBefore := len(c.diagnostics)
+	c.pushFunctionGenericContext(fn)
+	body := c.checkBlockWithExpected([]parse.Statement{call}, func() {
+		c.scope.expectReturn(returnType)
+		if setup != nil {
+			setup()
+		}
+		for _, param := range params {
+			c.scope.add(param.Name, param.Type, param.Mutable)
+		}
+	}, returnType, true)
+	c.popFunctionGenericContext()
+	if len(c.diagnostics) > diagnosticsBefore {
+		return nil
+	}
+	c.scope.add(fn.Name, fn, false)
+	fn.Body = body
+	return fn
+}
+
+// rejectMethodValueGenerics reports a method whose signature still has
+// generics of its own, which a function value has no way to fix.
+func (c *Checker) rejectMethodValueGenerics(params []Parameter, returnType Type, loc parse.Location) bool {
+	signature := &FunctionDef{Parameters: params, ReturnType: returnType}
+	for _, generic := range genericParamsForFunction(signature) {
+		if !c.genericInCurrentContext(generic) {
+			c.addDiagnostic(unresolvedGenericDiagnostic{Generic: "$" + generic, Span: c.sourceSpan(loc)}.build())
+			return true
+		}
+	}
+	return false
+}
+
+// isPlaceExpression reports whether expr names a variable or a field path
+// rooted at one, which can be read again without side effects.
+func isPlaceExpression(expr parse.Expression) bool {
+	switch e := expr.(type) {
+	case *parse.Identifier:
+		return true
+	case *parse.InstanceProperty:
+		return isPlaceExpression(e.Target)
+	default:
+		return false
+	}
+}
+
+// enumHasVariant reports whether typ is an enum with a variant called name,
+// which takes precedence over a method of the same name in `Type::name`.
+func enumHasVariant(typ Type, name string) bool {
+	enum, ok := typ.(*Enum)
+	if !ok {
+		return false
+	}
+	for _, value := range enum.Values {
+		if value.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,134 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestBoundMethods(t *testing.T) {
+	run(t, []test{
+		{
+			name: "a method bound to its receiver is a function value",
+			input: `struct Scale {
+  factor: Int,
+}
+
+impl Scale {
+  fn apply(n: Int) Int {
+    n * self.factor
+  }
+}
+
+fn apply_all(nums: [Int], f: fn(Int) Int) [Int] {
+  mut out: [Int] = []
+  for n in nums {
+    out.push(f(n))
+  }
+  out
+}
+
+fn make_scale(factor: Int) Scale {
+  Scale{factor: factor}
+}
+
+fn main() {
+  let scale = Scale{factor: 2}
+  let doubled = apply_all([1, 2], scale.apply)
+  let apply: fn(Int) Int = make_scale(3).apply
+}`,
+		},
+		{
+			name: "built-in methods can be bound",
+			input: `fn main() {
+  mut items: [Int] = []
+  let push: fn(Int) Int = items.push
+  let size: fn() Int = "hello".size
+}`,
+		},
+		{
+			name: "Type::method takes the receiver first",
+			input: `struct Point {
+  x: Int,
+}
+
+impl Point {
+  fn add(other: Point) Point {
+    Point{x: self.x + other.x}
+  }
+}
+
+enum Dir { up, down }
+
+impl Dir {
+  fn flip() Dir {
+    match self {
+      Dir::up => Dir::down,
+      Dir::down => Dir::up,
+    }
+  }
+}
+
+fn main() {
+  let add: fn(Point, Point) Point = Point::add
+  let flip: fn(Dir) Dir = Dir::flip
+}`,
+		},
+		{
+			name: "fields take precedence over methods",
+			input: `struct Handler {
+  run: fn() Int,
+}
+
+impl Handler {
+  fn call() Str {
+    "method"
+  }
+}
+
+fn main() {
+  let h = Handler{run: fn() Int { 1 }}
+  let run: fn() Int = h.run
+}`,
+		},
+		{
+			name: "binding a mutating method needs a mutable receiver",
+			input: `struct Counter {
+  count: Int,
+}
+
+impl Counter {
+  fn mut bump() {
+    self.count = self.count + 1
+  }
+}
+
+fn main() {
+  let counter = Counter{count: 0}
+  let bump = counter.bump
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Cannot mutate immutable 'counter' with '.bump()'"},
+			},
+		},
+		{
+			name: "methods of generic types can't be referenced without a receiver",
+			input: `struct Box<$T> {
+  item: $T,
+}
+
+impl Box {
+  fn get() $T {
+    self.item
+  }
+}
+
+fn main() {
+  let get = Box::get
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Methods of generic type Box can't be referenced as values; bind one to an instance instead"},
+			},
+		},
+	})
+}
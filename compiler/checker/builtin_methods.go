@@ -12,13 +12,24 @@ var (
 		StrReplaceAll: "replace_all",
 		StrStartsWith: "starts_with", StrEndsWith: "ends_with",
 		StrToStr: "to_str", StrTrim: "trim",
+		StrTrimStart: "trim_start", StrTrimEnd: "trim_end", StrSlice: "slice",
+		StrIndexOf: "index_of", StrToUpper: "to_upper", StrToLower: "to_lower",
+		StrPadLeft: "pad_left", StrPadRight: "pad_right", StrRepeat: "repeat",
+		StrReverse: "reverse", StrChars: "chars", StrGraphemes: "graphemes",
 	}
-	byteMethodNames  = map[ByteMethodKind]string{ByteToInt: "to_int", ByteToStr: "to_str"}
-	runeMethodNames  = map[RuneMethodKind]string{RuneToInt: "to_int", RuneToStr: "to_str"}
-	intMethodNames   = map[IntMethodKind]string{IntToStr: "to_str", IntToF64: "to_f64"}
-	floatMethodNames = map[FloatMethodKind]string{FloatToStr: "to_str", FloatToInt: "to_int"}
-	boolMethodNames  = map[BoolMethodKind]string{BoolToStr: "to_str"}
-	listMethodNames  = map[ListMethodKind]string{
+	byteMethodNames = map[ByteMethodKind]string{ByteToInt: "to_int", ByteToStr: "to_str"}
+	runeMethodNames = map[RuneMethodKind]string{RuneToInt: "to_int", RuneToStr: "to_str"}
+	intMethodNames  = map[IntMethodKind]string{
+		IntToStr: "to_str", IntToF64: "to_f64", IntAbs: "abs", IntPow: "pow", IntClamp: "clamp",
+		IntToFloat: "to_float",
+	}
+	floatMethodNames = map[FloatMethodKind]string{
+		FloatToStr: "to_str", FloatToInt: "to_int", FloatRound: "round",
+		FloatFloor: "floor", FloatCeil: "ceil",
+	}
+	boolMethodNames = map[BoolMethodKind]string{BoolToStr: "to_str"}
+	enumMethodNames = map[EnumMethodKind]string{EnumName: "name"}
+	listMethodNames = map[ListMethodKind]string{
 		ListAt: "at", ListPrepend: "prepend", ListPush: "push", ListSet: "set",
 		ListSize: "size", ListSort: "sort", ListSwap: "swap",
 	}
@@ -56,6 +67,8 @@ func BuiltinMethodInfo(node Expression) (receiver Type, name string, ok bool) {
 		receiver, name = Float64, floatMethodNames[m.Kind]
 	case *BoolMethod:
 		receiver, name = Bool, boolMethodNames[m.Kind]
+	case *EnumMethod:
+		receiver, name = m.Subject.Type(), enumMethodNames[m.Kind]
 	case *ListMethod:
 		receiver, name = m.Subject.Type(), listMethodNames[m.Kind]
 	case *MapMethod:
@@ -140,6 +153,10 @@ func BuiltinMemberNames(receiver Type) []string {
 			for _, name := range resultMethodNames {
 				collect(name)
 			}
+		case *Enum:
+			for _, name := range enumMethodNames {
+				collect(name)
+			}
 		}
 	}
 	if table == nil {
@@ -46,10 +46,30 @@ let same = slash == '/'`,
 				{Kind: checker.Error, Message: "Rune literal must contain exactly one Unicode scalar value"},
 			},
 		},
+		{
+			name: "rune prelude converts from code points and strings",
+			input: `let e: Rune? = Rune::from_int(233)
+let x: Rune? = Rune::from_str("x")
+let max: Int = Rune::MAX
+let raw: Rune = Rune::from(97)
+let ordered = 'a' < 'b'`,
+		},
+		{
+			name:  "rune from requires a numeric value",
+			input: `let bad = Rune::from("a")`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Rune::from expects a numeric value, got Str"},
+			},
+		},
 		{
 			name:  "integer literals can contextually type as byte",
 			input: `let b: Byte = 65`,
 		},
+		{
+			name: "byte from truncates a numeric value",
+			input: `let n = 300
+let b: Byte = Byte::from(n)`,
+		},
 		{
 			name:  "split method is removed from primitive str",
 			input: `"a,b".split(",")`,
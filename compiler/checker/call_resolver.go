@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// callSite is a call to an Ard function signature as written in source. Plain,
+// static, module, method, and function value calls all describe themselves
+// this way and share resolveCall, so argument binding, omitted Maybe and
+// variadic parameters, generic setup, and mutability checks behave the same
+// for every call form.
+type callSite struct {
+	fn             *FunctionDef
+	args           []parse.Argument
+	typeArgs       []parse.DeclaredType
+	location       parse.Location
+	expectedReturn Type
+	// receiver is set for method calls, whose signature may reference the
+	// receiver's generics.
+	receiver *callReceiver
+}
+
+// callReceiver is the generic context a method call inherits from its
+// receiver.
+type callReceiver struct {
+	// genericParams are the receiver-owned generics added to the call scope.
+	genericParams []string
+	// bindings are the receiver's concrete type arguments.
+	bindings map[string]Type
+	// explicitParams are the generics explicit type arguments bind.
+	explicitParams []string
+	location       parse.Location
+}
+
+// resolvedCall is a call whose arguments checked against its callee.
+type resolvedCall struct {
+	args []Expression
+	// fn is the callee specialized for this call.
+	fn       *FunctionDef
+	typeArgs []Type
+}
+
+// resolveCall binds and checks the arguments of site against its signature.
+// It reports a diagnostic and returns false when the call is invalid.
+func (c *Checker) resolveCall(site callSite) (resolvedCall, bool) {
+	fnDef := site.fn
+	callTypeArgs := c.resolveCallTypeArgs(site.typeArgs)
+
+	// Resolve named and positional arguments to match parameters
+	resolvedExprs, err := c.resolveArguments(site.args, fnDef.Parameters)
+	if err != nil {
+		c.addArgumentBindingError(err, site.location)
+		return resolvedCall{}, false
+	}
+
+	// Check argument count and validate omitted arguments
+	numOmittedArgs := 0
+	if len(resolvedExprs) < len(fnDef.Parameters) {
+		// Find first non-nullable parameter that's missing
+		for i := len(resolvedExprs); i < len(fnDef.Parameters); i++ {
+			if !parameterOmittable(fnDef.Parameters[i]) {
+				c.addMissingArgument(fnDef.Parameters[i], site.location)
+				return resolvedCall{}, false
+			}
+		}
+		numOmittedArgs = len(fnDef.Parameters) - len(resolvedExprs)
+	} else if len(resolvedExprs) > len(fnDef.Parameters) && !(len(fnDef.Parameters) > 0 && fnDef.Parameters[len(fnDef.Parameters)-1].Variadic) {
+		c.addArgumentCount(fmt.Sprint(len(fnDef.Parameters)), len(resolvedExprs), site.location, "")
+		resolvedExprs = resolvedExprs[:len(fnDef.Parameters)]
+	}
+
+	genericParams := append([]string(nil), callGenericParamsForFunction(fnDef)...)
+	explicitParams := genericParams
+	var receiverBindings map[string]Type
+	if site.receiver != nil {
+		// Receiver, explicit, and argument evidence share one call-local scope.
+		// A method's signature may reference receiver-owned generics; only
+		// explicitly marked method outputs are independently call-owned.
+		genericParams = appendUniqueStrings(genericParams, site.receiver.genericParams...)
+		receiverBindings = site.receiver.bindings
+		explicitParams = site.receiver.explicitParams
+	}
+	fnDefCopy, genericScope, setupErr := c.setupFunctionCallWithBindings(fnDef, genericParams, receiverBindings, explicitParams, callTypeArgs, site.typeArgs)
+	if setupErr != nil {
+		c.addGenericFunctionResolutionError(setupErr, site.location)
+		return resolvedCall{}, false
+	}
+	if genericScope != nil && site.receiver != nil {
+		for name := range receiverBindings {
+			if _, exists := genericScope.genericOrigins[name]; !exists {
+				genericScope.genericOrigins[name] = genericBindingOrigin{Span: c.sourceSpan(site.receiver.location), Kind: "receiver type"}
+			}
+		}
+	}
+
+	fnDef = expandFunctionDefForRepeatedVariadic(fnDef, len(resolvedExprs))
+	fnDefCopy = expandFunctionDefForRepeatedVariadic(fnDefCopy, len(resolvedExprs))
+
+	// Check and process arguments (handles both generics and mutability)
+	args, fnToUse := c.checkAndProcessArguments(fnDef, resolvedExprs, fnDefCopy, genericScope, numOmittedArgs, contextualGenericReturn(site.expectedReturn, callTypeArgs), site.location)
+	if args == nil {
+		return resolvedCall{}, false
+	}
+	c.recordHashableCall(site.fn, fnToUse, site.location)
+	return resolvedCall{args: args, fn: fnToUse, typeArgs: callTypeArgs}, true
+}
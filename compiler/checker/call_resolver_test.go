@@ -0,0 +1,138 @@
+package checker_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+const callFormsPrelude = `use proj/lib
+
+struct Counter {
+  start: Int,
+}
+
+impl Counter {
+  fn tally(count: Int, label: Str?, items: mut [Int]) Int {
+    count
+  }
+}
+
+fn Counter::make(count: Int, label: Str?, items: mut [Int]) Int {
+  count
+}
+
+fn tally(count: Int, label: Str?, items: mut [Int]) Int {
+  count
+}
+`
+
+// callForms are the ways to call a function with the signature
+// (count: Int, label: Str?, items: mut [Int]) Int. Every form resolves its
+// arguments through the same call resolver, so each behaves identically.
+var callForms = map[string]string{
+	"function":       "tally",
+	"static":         "Counter::make",
+	"module":         "lib::tally",
+	"method":         "counter.tally",
+	"function value": "tally_value",
+}
+
+func checkCallForm(t *testing.T, callee, args string) []checker.Diagnostic {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ard.toml"), []byte("name = \"proj\"\nard = \">= 0.1.0\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lib := "fn tally(count: Int, label: Str?, items: mut [Int]) Int {\n  count\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "lib.ard"), []byte(lib), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := callFormsPrelude + `
+fn main() {
+  let counter = Counter{start: 0}
+  let tally_value = tally
+  mut items = [1]
+  let frozen = [2]
+  let result = ` + callee + args + `
+}
+`
+	result := parse.Parse([]byte(source), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	resolver, err := checker.NewModuleResolver(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checker.New("main.ard", result.Program, resolver)
+	c.Check()
+	return c.Diagnostics()
+}
+
+func TestCallFormsResolveArgumentsAlike(t *testing.T) {
+	cases := []struct {
+		name string
+		args string
+		// code is the expected diagnostic, or empty for a clean call.
+		code checker.DiagnosticCode
+	}{
+		{name: "positional", args: `(1, "a", items)`},
+		{name: "omitted Maybe parameter", args: `(1, items: items)`},
+		{name: "named out of order", args: `(items: items, count: 1)`},
+		{name: "missing argument", args: `(1)`, code: checker.DiagnosticCodeMissingArgument},
+		{name: "too many arguments", args: `(1, "a", items, 4)`, code: checker.DiagnosticCodeIncorrectArgumentCount},
+		{name: "wrong argument type", args: `("1", "a", items)`, code: checker.DiagnosticCodeIncorrectArgumentType},
+		{name: "immutable argument for mut parameter", args: `(1, "a", frozen)`, code: checker.DiagnosticCodeIncorrectArgumentType},
+		{name: "unknown named argument", args: `(1, items: items, size: 2)`, code: checker.DiagnosticCodeUnknownNamedArgument},
+		{name: "duplicate argument", args: `(1, count: 2, items: items)`, code: checker.DiagnosticCodeDuplicateArgument},
+	}
+	for form, callee := range callForms {
+		for _, tc := range cases {
+			t.Run(form+"/"+tc.name, func(t *testing.T) {
+				diagnostics := checkCallForm(t, callee, tc.args)
+				if tc.code == "" {
+					if len(diagnostics) > 0 {
+						t.Fatalf("%s%s: unexpected diagnostics %v", callee, tc.args, diagnostics)
+					}
+					return
+				}
+				requireDiagnosticCode(t, diagnostics, tc.code)
+			})
+		}
+	}
+}
+
+func TestCallFormsInferGenericsAlike(t *testing.T) {
+	prelude := `struct Holder<$T> {
+  value: $T,
+}
+
+impl Holder {
+  fn pick(fallback: $T) $T {
+    fallback
+  }
+}
+
+fn pick(value: $T, fallback: $T) $T {
+  value
+}
+`
+	for name, call := range map[string]string{
+		"function": "pick(1, 2)",
+		"method":   "holder.pick(2)",
+	} {
+		t.Run(name, func(t *testing.T) {
+			source := prelude + "\nfn main() Int {\n  let holder = Holder{value: 1}\n  " + call + "\n}\n"
+			if diagnostics := checkSource(t, source); len(diagnostics) > 0 {
+				t.Fatalf("unexpected diagnostics %v", diagnostics)
+			}
+			source = prelude + "\nfn main() Int {\n  let holder = Holder{value: 1}\n  " + strings.Replace(call, "2", `"2"`, 1) + "\n}\n"
+			requireDiagnosticCode(t, checkSource(t, source), checker.DiagnosticCodeIncorrectArgumentType)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package checker_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	checker "github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+// largeSource generates a program of `count` functions that build and pass
+// around lists, maps, maybes, and results, so checking it leans on composite
+// type construction and equality.
+func largeSource(count int) []byte {
+	var b strings.Builder
+	b.WriteString("struct Item {\n  id: Int,\n  tags: [Str],\n  scores: [Str: [Float64]],\n}\n\n")
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&b, `fn build_%d(items: [Item], index: [Str: [Item]]) [Str: [Item?]]!Str {
+  mut out: [Str: [Item?]] = [:]
+  for item in items {
+    let found: Item? = index.get("k").or([]).at(item.id)
+    let grouped: [Item?] = [found, Maybe::new(item)]
+    out.set("{item.id}", grouped)
+  }
+  match out.size() > %d {
+    true => Result::ok(out),
+    false => Result::err("too few"),
+  }
+}
+
+`, i, i)
+	}
+	return []byte(b.String())
+}
+
+func benchmarkCheck(b *testing.B, count int) {
+	source := largeSource(count)
+	result := parse.Parse(source, "large.ard")
+	if len(result.Errors) > 0 {
+		b.Fatalf("parse errors: %v", result.Errors[0].Message)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := checker.New("large.ard", result.Program, nil)
+		c.Check()
+		if c.HasErrors() {
+			b.Fatalf("diagnostics: %v", c.Diagnostics())
+		}
+	}
+}
+
+func BenchmarkCheckLargeSource100(b *testing.B)  { benchmarkCheck(b, 100) }
+func BenchmarkCheckLargeSource1000(b *testing.B) { benchmarkCheck(b, 1000) }
@@ -21,6 +21,10 @@ type CheckOptions struct {
 	// internal parser bug. Tooling that checks error-carrying trees (the
 	// LSP) must set this.
 	HasParseErrors bool
+	// Strict gathers warnings for unused variables, imports, and private
+	// functions and for unreachable statements (see Checker.Warnings). Off
+	// unless `ard check --strict` asks for it.
+	Strict bool
 }
 
 func normalizeCheckOptions(options []CheckOptions) CheckOptions {
@@ -22,6 +22,9 @@ type Program struct {
 	Statements            []Statement
 	StructMethods         map[MethodOwner]map[string]*FunctionDef
 	ForeignInterfaceImpls map[MethodOwner][]*ForeignType
+	// ReExports maps the names made public by `pub use path::Name` to the
+	// module that declares them.
+	ReExports map[string]Module
 }
 
 type Module interface {
@@ -31,6 +34,10 @@ type Module interface {
 	// Symbols returns the module's public symbols by name. The map is owned
 	// by the module and must be treated as read-only.
 	Symbols() map[string]Symbol
+	// References returns the sites in the module's source that name the
+	// declaration identified by key. Only user modules checked with
+	// CheckOptions.RecordSpans record them.
+	References(key string) []Reference
 }
 
 // deref follows TypeVar bindings to find the concrete type.
@@ -57,6 +64,9 @@ func deref(t Type) Type {
 // Example: If $T is bound to Int, derefType([$T]) returns [Int].
 // Ensures anonymous function parameters see fully resolved types.
 func derefType(t Type) Type {
+	if isClosedType(t) {
+		return t
+	}
 	return derefTypeSeen(t, map[Type]bool{})
 }
 
@@ -66,8 +76,8 @@ func derefType(t Type) Type {
 // method namespace cannot recursively contain each other.
 func derefTypeSeen(t Type, seen map[Type]bool) Type {
 	t = deref(t) // First dereference at the top level
-	if t == nil {
-		return nil
+	if t == nil || isClosedType(t) {
+		return t
 	}
 	if seen[t] {
 		return t
@@ -79,7 +89,7 @@ func derefTypeSeen(t Type, seen map[Type]bool) Type {
 		if derefInner == typ.of {
 			return typ // No change, return original
 		}
-		return &List{of: derefInner}
+		return MakeList(derefInner)
 	case *FixedArray:
 		derefInner := derefTypeSeen(typ.of, seen)
 		if derefInner == typ.of {
@@ -110,26 +120,20 @@ func derefTypeSeen(t Type, seen map[Type]bool) Type {
 		if derefKey == typ.key && derefVal == typ.value {
 			return typ // No change, return original
 		}
-		return &Map{
-			key:   derefKey,
-			value: derefVal,
-		}
+		return MakeMap(derefKey, derefVal)
 	case *Maybe:
 		derefInner := derefTypeSeen(typ.of, seen)
 		if derefInner == typ.of {
 			return typ // No change, return original
 		}
-		return &Maybe{of: derefInner}
+		return MakeMaybe(derefInner)
 	case *Result:
 		derefVal := derefTypeSeen(typ.val, seen)
 		derefErr := derefTypeSeen(typ.err, seen)
 		if derefVal == typ.val && derefErr == typ.err {
 			return typ // No change, return original
 		}
-		return &Result{
-			val: derefVal,
-			err: derefErr,
-		}
+		return MakeResult(derefVal, derefErr)
 	case *MutableRef:
 		derefInner := derefTypeSeen(typ.of, seen)
 		if derefInner == typ.of {
@@ -182,6 +186,10 @@ func derefTypeSeen(t Type, seen map[Type]bool) Type {
 			}
 			return newStructApplication(typ, newTypeArgs)
 		}
+		if len(typ.GenericParams) == 0 && len(typ.TypeArgs) == 0 {
+			// a struct declared without generics has no type variables in its fields
+			return typ
+		}
 		fieldsChanged := false
 		newFields := make(map[string]Type, len(typ.Fields))
 		for name, fieldType := range typ.Fields {
@@ -206,6 +214,7 @@ func derefTypeSeen(t Type, seen map[Type]bool) Type {
 			Name:             typ.Name,
 			ModulePath:       typ.ModulePath,
 			Fields:           newFields,
+			FieldOrder:       typ.FieldOrder,
 			Self:             typ.Self,
 			Traits:           typ.Traits,
 			GenericParams:    append([]string(nil), typ.GenericParams...),
@@ -213,6 +222,8 @@ func derefTypeSeen(t Type, seen map[Type]bool) Type {
 			TypeArgs:         newTypeArgs,
 			Definition:       typ.Definition,
 			Private:          typ.Private,
+			Deprecated:       typ.Deprecated,
+			PrivateFields:    typ.PrivateFields,
 		}
 	case *FunctionDef:
 		newParams := make([]Parameter, len(typ.Parameters))
@@ -244,6 +255,7 @@ func derefTypeSeen(t Type, seen map[Type]bool) Type {
 			Mutates:                 typ.Mutates,
 			IsTest:                  typ.IsTest,
 			Private:                 typ.Private,
+			Deprecated:              typ.Deprecated,
 			GenericBindings:         cloneTypeMap(typ.GenericBindings),
 		}
 	default:
@@ -359,39 +371,53 @@ func (c Checker) isMutable(expr Expression) bool {
 }
 
 type Checker struct {
-	diagnostics                       []Diagnostic
-	input                             *parse.Program
-	scope                             *SymbolTable
-	filePath                          string
-	modulePath                        string
-	program                           *Program
-	halted                            bool
-	moduleResolver                    *ModuleResolver
-	options                           CheckOptions
-	expectedExpr                      Type
-	duplicateTopLevelTypeDeclarations map[parse.Statement]bool
-	topLevelStructDeclarations        map[string]*parse.StructDefinition
-	topLevelTypeAliases               map[string]*parse.TypeDeclaration
-	hoistedTopLevelFunctions          map[*parse.FunctionDeclaration]*FunctionDef
-	resolvingTopLevelStructs          map[string]bool
-	resolvedTopLevelStructs           map[string]bool
-	resolvingTopLevelAliases          map[string]bool
-	resolvingTopLevelAliasEdges       []typeAliasResolutionEdge
-	resolvingTopLevelAliasNames       []string
-	recursiveTopLevelAliases          map[string]bool
-	resolvedTopLevelAliases           map[string]bool
-	genericContextStack               []map[string]bool
-	methodGenericAllowlist            []map[string]bool
-	discardExprContext                bool
-	matchArmDiscardContext            bool
-	deferredWorkDepth                 int
-	reportedMapKeyErrors              map[parse.Location]bool
-	emptyCollectionBinding            *collectionBindingContext
-	goTypesContext                    *gotypes.Context
-	spans                             *SpanIndex
-	nextCallInferenceID               uint64
-	expectedCallExpectation           *typeExpectation
-	moduleFiles                       map[string]string
+	diagnostics                   []Diagnostic
+	warnings                      []Diagnostic
+	input                         *parse.Program
+	scope                         *SymbolTable
+	filePath                      string
+	modulePath                    string
+	program                       *Program
+	halted                        bool
+	moduleResolver                *ModuleResolver
+	options                       CheckOptions
+	expectedExpr                  Type
+	duplicateTopLevelDeclarations map[parse.Statement]bool
+	topLevelStructDeclarations    map[string]*parse.StructDefinition
+	topLevelTypeAliases           map[string]*parse.TypeDeclaration
+	hoistedTopLevelFunctions      map[*parse.FunctionDeclaration]*FunctionDef
+	hoistedMethodSignatures       map[*parse.FunctionDeclaration]*FunctionDef
+	resolvingTopLevelStructs      map[string]bool
+	resolvedTopLevelStructs       map[string]bool
+	resolvedStructDefaults        map[*StructDef]bool
+	resolvingTopLevelAliases      map[string]bool
+	resolvingTopLevelAliasEdges   []typeAliasResolutionEdge
+	resolvingTopLevelAliasNames   []string
+	recursiveTopLevelAliases      map[string]bool
+	resolvedTopLevelAliases       map[string]bool
+	genericContextStack           []map[string]bool
+	genericContextFunctions       []*FunctionDef
+	hashableCalls                 []hashableCall
+	methodGenericAllowlist        []map[string]bool
+	selfType                      Type
+	discardExprContext            bool
+	matchArmDiscardContext        bool
+	enumPatternContext            bool
+	deferredWorkDepth             int
+	reportedMapKeyErrors          map[parse.Location]bool
+	reportedDynamic               map[parse.Location]bool
+	denyWarnings                  *SourceSpan
+	forbidDynamic                 *SourceSpan
+	forbidNondeterminism          *SourceSpan
+	emptyCollectionBinding        *collectionBindingContext
+	goTypesContext                *gotypes.Context
+	spans                         *SpanIndex
+	strict                        *strictUsage
+	nextCallInferenceID           uint64
+	expectedCallExpectation       *typeExpectation
+	moduleFiles                   map[string]string
+	genericBindings               map[Node]genericBindingSite
+	nodes                         nodeArena
 }
 
 func New(filePath string, input *parse.Program, moduleResolver *ModuleResolver, options ...CheckOptions) *Checker {
@@ -422,6 +448,9 @@ func New(filePath string, input *parse.Program, moduleResolver *ModuleResolver,
 		c.spans = &SpanIndex{}
 		c.moduleFiles = map[string]string{}
 	}
+	if checkOptions.Strict {
+		c.strict = &strictUsage{used: map[*Symbol]bool{}}
+	}
 
 	return c
 }
@@ -441,6 +470,13 @@ func (c *Checker) Diagnostics() []Diagnostic {
 	return c.diagnostics
 }
 
+// Warnings returns diagnostics that do not stop compilation: uses of
+// deprecated declarations and, in strict mode, unused code. They are not
+// counted by HasErrors unless the module declares `#deny(warnings)`.
+func (c *Checker) Warnings() []Diagnostic {
+	return c.warnings
+}
+
 func isTopLevelExecutableStatement(stmt parse.Statement) bool {
 	if isTopLevelTypeDeclaration(stmt) {
 		return false
@@ -455,8 +491,14 @@ func isTopLevelExecutableStatement(stmt parse.Statement) bool {
 
 func (c *Checker) Check() {
 	c.primeGoResolver()
+	c.checkPragmas()
 	seenImportAliases := map[string]parse.Location{}
+	var reExports []parse.Import
 	for _, imp := range c.input.Imports {
+		if imp.ReExport != "" {
+			reExports = append(reExports, imp)
+			continue
+		}
 		if original, dup := seenImportAliases[imp.Name]; dup {
 			c.addDiagnostic(duplicateImportDiagnostic{
 				Name:           imp.Name,
@@ -486,84 +528,12 @@ func (c *Checker) Check() {
 			continue
 		}
 
-		if strings.HasPrefix(imp.Path, "ard/") {
-			// Handle standard library imports
-			if mod, ok := findInStdLib(imp.Path); ok {
-				c.program.Imports[imp.Name] = mod
-			} else {
-				c.addUnresolvedReference(unknownModule, imp.Path, imp.GetLocation())
-			}
-		} else {
-			// Handle user module imports
-			if c.moduleResolver == nil {
-				panic(fmt.Sprintf("No module resolver provided for user import: %s", imp.Path))
-			}
-
-			resolved, err := c.moduleResolver.ResolveImport(c.modulePath, imp.Path)
-			if err != nil {
-				c.addDiagnostic(ardImportResolutionDiagnostic{
-					Path:  imp.Path,
-					Cause: err.Error(),
-					Span:  c.sourceSpan(imp.PathLocation),
-				}.build())
-				continue
-			}
-			filePath := filepath.Clean(resolved.FilePath)
-
-			// Check if module is already cached
-			if cachedModule, ok := c.moduleResolver.moduleCache[filePath]; ok {
-				c.program.Imports[imp.Name] = cachedModule
-				continue
-			}
-			if slices.Contains(c.moduleResolver.loadingChain, resolved.ModulePath) {
-				chain := append(append([]string{}, c.moduleResolver.loadingChain...), resolved.ModulePath)
-				c.addDiagnostic(circularImportDiagnostic{
-					Chain:       chain,
-					ClosingSpan: c.sourceSpan(imp.PathLocation),
-				}.build())
-				continue
-			}
-			c.moduleResolver.loadingChain = append(c.moduleResolver.loadingChain, resolved.ModulePath)
-
-			// Load and parse the module file using the resolved package context.
-			ast, err := c.moduleResolver.LoadModuleFile(filePath)
-			if err != nil {
-				c.moduleResolver.loadingChain = c.moduleResolver.loadingChain[:len(c.moduleResolver.loadingChain)-1]
-				c.addDiagnostic(moduleLoadDiagnostic{
-					ImportPath: imp.Path,
-					TargetFile: filePath,
-					Cause:      err.Error(),
-					ImportSpan: c.sourceSpan(imp.PathLocation),
-				}.build())
-				continue
-			}
-
-			// Type-check the imported module
-			importOptions := c.options
-			userModule, diagnostics := check(ast, c.moduleResolver, filePath, resolved.ModulePath, importOptions)
-			c.moduleResolver.loadingChain = c.moduleResolver.loadingChain[:len(c.moduleResolver.loadingChain)-1]
-			if len(diagnostics) > 0 {
-				// Add all diagnostics from the imported module
-				for _, diag := range diagnostics {
-					diag = reanchorCircularImportDiagnostic(diag, c.sourceSpan(imp.PathLocation))
-					c.diagnostics = append(c.diagnostics, diag)
-				}
-				continue
-			}
-
-			// Set the correct module path for the module
-			if um, ok := userModule.(*UserModule); ok {
-				um.setFilePath(resolved.ModulePath)
-			}
-			if c.moduleFiles != nil {
-				c.moduleFiles[resolved.ModulePath] = filePath
-			}
-
-			// Cache and add to imports
-			c.moduleResolver.moduleCache[filePath] = userModule
-			c.program.Imports[imp.Name] = userModule
+		if mod := c.loadImport(imp); mod != nil {
+			c.program.Imports[imp.Name] = mod
 		}
 	}
+	c.checkReExports(reExports)
+	c.checkDeterministicImports()
 
 	// Auto-import prelude modules (only for non-std lib)
 	if !strings.HasPrefix(c.filePath, "ard/") {
@@ -591,6 +561,7 @@ func (c *Checker) Check() {
 	c.predeclareTopLevelTypeAliases()
 	c.populateTopLevelTypeDefinitions()
 	c.hoistTopLevelFunctionSignatures()
+	c.hoistTopLevelImplSignatures()
 
 	for i := range c.input.Statements {
 		if stmt := c.checkedTopLevelTypeStatement(c.input.Statements[i]); stmt != nil {
@@ -612,7 +583,13 @@ func (c *Checker) Check() {
 			c.program.Statements = append(c.program.Statements, *stmt)
 		}
 		if c.halted {
-			break
+			if !c.options.HasParseErrors {
+				break
+			}
+			// in a tree with parse errors the halt most likely came from a
+			// hole recovery left, so the declarations after it still get
+			// checked
+			c.halted = false
 		}
 	}
 
@@ -620,6 +597,10 @@ func (c *Checker) Check() {
 	c.checkStructFieldMapKeyTypes()
 	c.checkRecursiveStructLayouts()
 	c.checkGenericInstantiationCycles()
+	c.checkHashableCalls()
+	c.scanForUnresolvedGenerics()
+	c.reportUnusedCode()
+	c.enforceDenyWarnings()
 
 	// now that we're done with the aliases, use module paths for the import keys
 	for alias, mod := range c.program.Imports {
@@ -628,28 +609,164 @@ func (c *Checker) Check() {
 	}
 }
 
-func (c *Checker) scanForUnresolvedGenerics() {
-	for _, stmt := range c.program.Statements {
-		if stmt.Expr == nil {
-			continue
+// loadImport resolves and checks the Ard module named by imp. It returns nil
+// after reporting a diagnostic when the module cannot be loaded.
+func (c *Checker) loadImport(imp parse.Import) Module {
+	if strings.HasPrefix(imp.Path, "ard/") {
+		// Handle standard library imports
+		if mod, ok := findInStdLib(imp.Path); ok {
+			return mod
 		}
+		c.addUnresolvedReference(unknownModule, imp.Path, imp.GetLocation())
+		return nil
+	}
 
-		if typeVar, ok := stmt.Expr.Type().(*TypeVar); ok && typeVar.actual == nil {
-			loc := parse.Location{}
-			if locatable, ok := stmt.Expr.(interface{ GetLocation() parse.Location }); ok {
-				loc = locatable.GetLocation()
-			}
+	if c.moduleResolver == nil {
+		panic(fmt.Sprintf("No module resolver provided for user import: %s", imp.Path))
+	}
 
-			c.addDiagnostic(unresolvedGenericDiagnostic{Generic: typeVar.String(), Span: c.sourceSpan(loc)}.build())
-			break
+	resolved, err := c.moduleResolver.ResolveImport(c.modulePath, imp.Path)
+	if err != nil {
+		c.addDiagnostic(ardImportResolutionDiagnostic{
+			Path:  imp.Path,
+			Cause: err.Error(),
+			Span:  c.sourceSpan(imp.PathLocation),
+		}.build())
+		return nil
+	}
+	filePath := filepath.Clean(resolved.FilePath)
+
+	// Check if module is already cached
+	if cachedModule, ok := c.moduleResolver.moduleCache[filePath]; ok {
+		return cachedModule
+	}
+	if slices.Contains(c.moduleResolver.loadingChain, resolved.ModulePath) {
+		chain := append(append([]string{}, c.moduleResolver.loadingChain...), resolved.ModulePath)
+		c.addDiagnostic(circularImportDiagnostic{
+			Chain:       chain,
+			ClosingSpan: c.sourceSpan(imp.PathLocation),
+		}.build())
+		return nil
+	}
+	c.moduleResolver.loadingChain = append(c.moduleResolver.loadingChain, resolved.ModulePath)
+
+	// Load and parse the module file using the resolved package context.
+	ast, err := c.moduleResolver.LoadModuleFile(filePath)
+	if err != nil {
+		c.moduleResolver.loadingChain = c.moduleResolver.loadingChain[:len(c.moduleResolver.loadingChain)-1]
+		c.addDiagnostic(moduleLoadDiagnostic{
+			ImportPath: imp.Path,
+			TargetFile: filePath,
+			Cause:      err.Error(),
+			ImportSpan: c.sourceSpan(imp.PathLocation),
+		}.build())
+		return nil
+	}
+
+	// Type-check the imported module
+	importOptions := c.options
+	userModule, diagnostics := check(ast, c.moduleResolver, filePath, resolved.ModulePath, importOptions)
+	c.moduleResolver.loadingChain = c.moduleResolver.loadingChain[:len(c.moduleResolver.loadingChain)-1]
+	if len(diagnostics) > 0 {
+		// Add all diagnostics from the imported module
+		for _, diag := range diagnostics {
+			diag = reanchorCircularImportDiagnostic(diag, c.sourceSpan(imp.PathLocation))
+			c.diagnostics = append(c.diagnostics, diag)
+		}
+		return nil
+	}
+
+	// Set the correct module path for the module
+	if um, ok := userModule.(*UserModule); ok {
+		um.setFilePath(resolved.ModulePath)
+	}
+	if c.moduleFiles != nil {
+		c.moduleFiles[resolved.ModulePath] = filePath
+	}
+
+	// Cache and add to imports
+	c.moduleResolver.moduleCache[filePath] = userModule
+	return userModule
+}
+
+// genericBindingSite locates a node that binds a value: a variable
+// definition, a reassignment, or a struct literal and its fields.
+type genericBindingSite struct {
+	location parse.Location
+	// fields locates each field value of a struct literal.
+	fields map[string]parse.Location
+}
+
+// recordGenericBinding remembers where node binds its value. Only nodes that
+// survive into the checked program are scanned, so speculative checks that
+// are thrown away leave nothing behind.
+func (c *Checker) recordGenericBinding(node Node, site genericBindingSite) {
+	if c.genericBindings == nil {
+		c.genericBindings = map[Node]genericBindingSite{}
+	}
+	c.genericBindings[node] = site
+}
+
+// scanForUnresolvedGenerics runs after the whole module is checked, once
+// every deferred inference has had its chance to bind, and reports each
+// binding site still holding a call-owned type variable. Such a variable has
+// no concrete type to lower to. Each variable is reported once, at the first
+// site that binds it, and sites inside an expression already reported as
+// unresolved are skipped.
+func (c *Checker) scanForUnresolvedGenerics() {
+	if c.halted || len(c.genericBindings) == 0 {
+		return
+	}
+	var alreadyReported []parse.Location
+	for _, diagnostic := range c.diagnostics {
+		if diagnostic.Code == DiagnosticCodeUnresolvedGeneric && diagnostic.Primary.Span.FilePath == c.filePath {
+			alreadyReported = append(alreadyReported, diagnostic.Primary.Span.Location)
 		}
 	}
+	reported := map[*TypeVar]bool{}
+	report := func(t Type, location parse.Location) {
+		unresolved := firstUnresolvedCallTypeVar(t)
+		if unresolved == nil || reported[unresolved] {
+			return
+		}
+		if slices.ContainsFunc(alreadyReported, func(loc parse.Location) bool { return spanContains(loc, location.Start) }) {
+			return
+		}
+		reported[unresolved] = true
+		c.addDiagnostic(uninferredGenericBindingDiagnostic{Generic: unresolved.String(), Span: c.sourceSpan(location)}.build())
+	}
+	WalkProgram(inspector(func(node Node) bool {
+		switch n := node.(type) {
+		case *VariableDef:
+			if site, ok := c.genericBindings[n]; ok {
+				report(n.Type(), site.location)
+			}
+		case *Reassignment:
+			if site, ok := c.genericBindings[n]; ok {
+				report(n.Value.Type(), site.location)
+			}
+		case *StructInstance:
+			if site, ok := c.genericBindings[n]; ok {
+				for _, name := range slices.Sorted(maps.Keys(site.fields)) {
+					if field := n.Fields[name]; field != nil {
+						report(field.Type(), site.fields[name])
+					}
+				}
+				report(n.Type(), site.location)
+			}
+		}
+		return true
+	}), c.program)
 }
 
 // This should only be called after .Check()
 // The returned module could be problematic if there are diagnostic errors.
 func (c *Checker) Module() Module {
-	return NewUserModule(c.modulePath, c.program, c.scope)
+	module := NewUserModule(c.modulePath, c.program, c.scope)
+	if c.spans != nil {
+		module.references = buildReferences(c.filePath, c.spans)
+	}
+	return module
 }
 
 // check is an internal helper for recursive module checking.
@@ -679,8 +796,36 @@ func (c *Checker) addDuplicateMatchArm(kind DiagnosticKind, message string, loca
 	c.addDiagnostic(duplicateMatchArmDiagnostic{Kind: kind, LegacyMessage: message, Span: c.sourceSpan(location), OriginalSpan: original}.build())
 }
 
-func (c *Checker) addNonExhaustiveMatch(message string, location parse.Location, label string) {
-	c.addDiagnostic(nonExhaustiveMatchDiagnostic{LegacyMessage: message, Span: c.sourceSpan(location), Label: label}.build())
+// addUndefinedName reports a name that is not in scope. A public declaration
+// of an imported module with that name is offered as a qualifying fix, and
+// otherwise a similar name in scope is suggested.
+func (c *Checker) addUndefinedName(kind undefinedNameKind, name string, location parse.Location) {
+	d := undefinedNameDiagnostic{Kind: kind, Name: name, Span: c.sourceSpan(location)}
+	alias, qualify := c.qualifyingImport(name)
+	if qualify {
+		d.Suggestion = alias + "::" + name
+	} else {
+		d.Suggestion = suggestName(name, c.scope.visibleNames())
+	}
+	diagnostic := d.build()
+	if qualify {
+		diagnostic.Fixes = c.qualifyFix(alias, name, location)
+	}
+	c.addDiagnostic(diagnostic)
+}
+
+// addNonExhaustiveMatch reports a match missing cases. arms are the patterns
+// of the missing cases, offered as a fix when they can be written out.
+// addUnreachableMatchArm warns about a match arm, or part of one, that the
+// earlier arm at original already matches.
+func (c *Checker) addUnreachableMatchArm(message string, location parse.Location, original *SourceSpan, label string) {
+	c.addDiagnostic(duplicateMatchArmDiagnostic{Kind: Warn, LegacyMessage: message, Span: c.sourceSpan(location), OriginalSpan: original, Label: label}.build())
+}
+
+func (c *Checker) addNonExhaustiveMatch(message string, location parse.Location, label string, arms ...string) {
+	diagnostic := nonExhaustiveMatchDiagnostic{LegacyMessage: message, Span: c.sourceSpan(location), Label: label}.build()
+	diagnostic.Fixes = c.missingArmsFix(location, arms)
+	c.addDiagnostic(diagnostic)
 }
 
 func (c *Checker) addInvalidSelectArm(message string, location parse.Location, label string) {
@@ -773,6 +918,10 @@ func (c *Checker) addMethodIntroducedGeneric(name string, reason methodIntroduce
 }
 
 func (c *Checker) addIncorrectArgumentType(legacyMessage string, expected Type, actual Type, argumentLocation parse.Location, parameter Parameter, requiresMutable bool, related ...DiagnosticLabel) {
+	c.addDiagnostic(c.incorrectArgumentType(legacyMessage, expected, actual, argumentLocation, parameter, requiresMutable, related...))
+}
+
+func (c *Checker) incorrectArgumentType(legacyMessage string, expected Type, actual Type, argumentLocation parse.Location, parameter Parameter, requiresMutable bool, related ...DiagnosticLabel) Diagnostic {
 	var parameterSpan *SourceSpan
 	if parameter.declaredAt.FilePath != "" {
 		span := parameter.declaredAt
@@ -790,7 +939,7 @@ func (c *Checker) addIncorrectArgumentType(legacyMessage string, expected Type,
 	if len(related) > 0 {
 		diagnostic.Secondary = append(related, diagnostic.Secondary...)
 	}
-	c.addDiagnostic(diagnostic)
+	return diagnostic
 }
 
 func (c *Checker) resolveModule(name string) Module {
@@ -929,6 +1078,7 @@ func (c *Checker) specializeAliasedType(originalType Type, typeArgs []parse.Decl
 // plain Go map (ADR 0031). Unresolved generic parameters are allowed; the
 // constraint applies when they are instantiated.
 func (c *Checker) validateMapKeyType(key Type, loc parse.Location) {
+	c.requireHashable(key)
 	if key == nil || isValidMapKeyType(key) {
 		return
 	}
@@ -977,8 +1127,9 @@ func isComparableValueType(t Type) bool {
 	if foreign, ok := t.(*ForeignType); ok && !foreign.Pointer && foreign.Underlying != nil && isComparableValueType(foreign.Underlying) {
 		return true
 	}
-	_, isEnum := t.(*Enum)
-	return isEnum
+	// Enums with associated data compare structurally only through match.
+	enum, isEnum := t.(*Enum)
+	return isEnum && !enum.HasPayload()
 }
 
 type mapKeyTypeContext struct {
@@ -1030,6 +1181,8 @@ func isValidMapKeyTypeSeen(t Type, context *mapKeyTypeContext) bool {
 		return true
 	case *FixedArray:
 		return isValidMapKeyTypeSeen(ty.Of(), context)
+	case *Enum:
+		return !ty.HasPayload()
 	case *ForeignType:
 		return ty.GoType == nil || gotypes.Comparable(ty.GoType)
 	case *Maybe, *List, *Map, *Result, *Union, *FunctionDef, *Trait, *anyType:
@@ -1203,9 +1356,12 @@ func (c *Checker) resolveType(t parse.DeclaredType) Type {
 		val := c.resolveType(ty.Val)
 		err := c.resolveType(ty.Err)
 		baseType = MakeResult(val, err)
+	case *parse.UnionType:
+		baseType = c.resolveInlineUnion(ty)
 	case *parse.CustomType:
 		switch t.GetName() {
 		case "Any":
+			c.checkDynamicAllowed(t.GetLocation(), "`Any` is written here")
 			baseType = Any
 			break
 		case "Byte":
@@ -1221,6 +1377,13 @@ func (c *Checker) resolveType(t parse.DeclaredType) Type {
 		case "Rune":
 			baseType = Rune
 			break
+		case "Self":
+			if c.selfType == nil {
+				c.addUnresolvedReference(selfOutsideType, "Self", ty.GetLocation())
+				return &TypeVar{name: "unknown"}
+			}
+			baseType = c.resolveSelfType(ty.GetLocation())
+			break
 		case "Maybe":
 			if len(ty.TypeArgs) != 1 {
 				c.addIncorrectTypeArgumentCount(1, len(ty.TypeArgs), "Generic type Maybe requires type arguments", ty.GetLocation())
@@ -1318,6 +1481,7 @@ func (c *Checker) resolveType(t parse.DeclaredType) Type {
 				propName := ty.Type.Property.(*parse.Identifier).Name
 				sym := mod.Get(propName)
 				if !sym.IsZero() {
+					mod = memberOrigin(mod, propName)
 					if c.spans != nil {
 						c.spans.add(SpanRecord{
 							Loc:    ty.GetLocation(),
@@ -1334,6 +1498,9 @@ func (c *Checker) resolveType(t parse.DeclaredType) Type {
 					}
 					break
 				}
+				if c.checkPrivateModuleMember(mod, propName, t.GetName(), t.GetLocation()) {
+					return &TypeVar{name: "unknown"}
+				}
 			}
 		}
 		c.addUnresolvedReference(unrecognizedType, t.GetName(), t.GetLocation())
@@ -1353,7 +1520,7 @@ func (c *Checker) resolveType(t parse.DeclaredType) Type {
 
 	// If the type is nullable, wrap it in a Maybe
 	if t.IsNullable() {
-		return &Maybe{of: baseType}
+		return MakeMaybe(baseType)
 	}
 
 	return baseType
@@ -1374,6 +1541,7 @@ func (c *Checker) destructurePath(expr *parse.StaticFunction) (string, string) {
 }
 
 func (c *Checker) pushFunctionGenericContext(fnDef *FunctionDef, extraParams ...string) {
+	c.genericContextFunctions = append(c.genericContextFunctions, fnDef)
 	params := genericParamsForFunction(fnDef)
 	params = appendUniqueStrings(params, extraParams...)
 	if len(params) == 0 {
@@ -1392,6 +1560,7 @@ func (c *Checker) popFunctionGenericContext() {
 		return
 	}
 	c.genericContextStack = c.genericContextStack[:len(c.genericContextStack)-1]
+	c.genericContextFunctions = c.genericContextFunctions[:len(c.genericContextFunctions)-1]
 }
 
 func (c *Checker) pushMethodGenericAllowlist(params []string) {
@@ -1409,6 +1578,29 @@ func (c *Checker) popMethodGenericAllowlist() {
 	c.methodGenericAllowlist = c.methodGenericAllowlist[:len(c.methodGenericAllowlist)-1]
 }
 
+// withSelfType resolves `Self` to typ while fn runs, for the methods of an
+// impl block and for static functions declared on a type.
+func (c *Checker) withSelfType(typ Type, fn func()) {
+	previous := c.selfType
+	c.selfType = typ
+	defer func() { c.selfType = previous }()
+	fn()
+}
+
+// resolveSelfType returns the type `Self` names. A generic struct is applied
+// to its own parameters, so `Self` within `impl Box` means `Box<$T>`.
+func (c *Checker) resolveSelfType(loc parse.Location) Type {
+	def, ok := c.selfType.(*StructDef)
+	if !ok || len(def.GenericParams) == 0 {
+		return c.selfType
+	}
+	args := make([]parse.DeclaredType, len(def.GenericParams))
+	for i, param := range def.GenericParams {
+		args[i] = &parse.GenericType{Location: loc, Name: param}
+	}
+	return c.specializeAliasedType(def, args, loc)
+}
+
 func (c *Checker) genericAllowedInCurrentMethod(name string) bool {
 	if len(c.methodGenericAllowlist) == 0 {
 		return true
@@ -1569,7 +1761,7 @@ func (c *Checker) checkForeignTypeMatch(s *parse.MatchExpression, subject Expres
 		}
 	}
 	if catchAll == nil {
-		c.addNonExhaustiveMatch("Match on a dynamic value requires a catch-all '_' case because the type set is open", s.GetLocation(), "add a catch-all `_` case for this open type set")
+		c.addNonExhaustiveMatch("Match on a dynamic value requires a catch-all '_' case because the type set is open", s.GetLocation(), "add a catch-all `_` case for this open type set", "_")
 		return nil
 	}
 	var resultType Type
@@ -1740,20 +1932,6 @@ func (c *Checker) explicitMethodGenericParams(fnDef *FunctionDef, subject Type)
 	return out
 }
 
-func formatTypeForDisplay(t Type) string {
-	// For StructDef with generic fields, show type parameters
-	if structDef, ok := t.(*StructDef); ok {
-		if len(structDef.TypeArgs) > 0 {
-			return structDef.String()
-		}
-		if resultType, hasResult := structDef.Fields["result"]; hasResult {
-			// The result field's type indicates the generic parameter
-			return fmt.Sprintf("%s<%s>", structDef.String(), resultType.String())
-		}
-	}
-	return t.String()
-}
-
 func mergeMatchResultType(c *Checker, current Type, next Type, loc parse.Location, allowMixedVoid bool) (Type, bool) {
 	if current == nil {
 		return next, true
@@ -1805,15 +1983,15 @@ func mixedVoidMatchTypes(left Type, right Type) (Type, Type, bool) {
 }
 
 func matchBranchTypeMismatch(expected Type, got Type) string {
-	return fmt.Sprintf("Type mismatch in match branches: expected %s, got %s", formatTypeForDisplay(expected), formatTypeForDisplay(got))
+	return fmt.Sprintf("Type mismatch in match branches: expected %s, got %s", formatType(expected), formatType(got))
 }
 
 func typeMismatch(expected, got Type) string {
-	exMsg := formatTypeForDisplay(expected)
+	exMsg := formatType(expected)
 	if _, isTrait := expected.(*Trait); isTrait {
 		exMsg = "implementation of " + exMsg
 	}
-	return fmt.Sprintf("Type mismatch: Expected %s, got %s", exMsg, formatTypeForDisplay(got))
+	return fmt.Sprintf("Type mismatch: Expected %s, got %s", exMsg, formatType(got))
 }
 
 func (c *Checker) areCompatible(expected Type, actual Type) bool {
@@ -1837,6 +2015,12 @@ func (c *Checker) areCompatible(expected Type, actual Type) bool {
 	if trait, ok := expected.(*Trait); ok {
 		return actual.hasTrait(trait)
 	}
+	if union, ok := expected.(*Union); ok {
+		return unionAccepts(union, actual)
+	}
+	if unionNarrows(expected, actual) {
+		return false
+	}
 	if iface, ok := expected.(*ForeignType); ok && iface.Interface {
 		// A named empty Go interface accepts any value, matching Go's own
 		// assignability rules.
@@ -1912,6 +2096,57 @@ func (c *Checker) areCompatible(expected Type, actual Type) bool {
 	return expected.equal(actual)
 }
 
+// unionAccepts is the one subtyping rule for unions: a value widens into a
+// union when its type is a member, a member of a nested union, or a union
+// whose members all widen. Every position that checks assignability (let
+// bindings, arguments, returns, fields, collection elements, match branches)
+// goes through areCompatible, so they all widen the same way.
+func unionAccepts(union *Union, actual Type) bool {
+	if union.equal(actual) {
+		if _, ok := actual.(*Union); !ok {
+			return true
+		}
+	}
+	if other, ok := actual.(*Union); ok {
+		if union == other || equalTypes(*union, other) {
+			return true
+		}
+		for _, member := range other.Types {
+			if !unionAccepts(union, member) {
+				return false
+			}
+		}
+		return len(other.Types) > 0
+	}
+	for _, member := range union.Types {
+		if nested, ok := member.(*Union); ok && unionAccepts(nested, actual) {
+			return true
+		}
+	}
+	return false
+}
+
+// unionNarrows reports a union value offered where only one of its members
+// fits. Type equality matches a union against each member, but a value of
+// the union may hold any of them, so narrowing takes a match or `as`.
+func unionNarrows(expected Type, actual Type) bool {
+	if _, ok := actual.(*Union); !ok {
+		return false
+	}
+	switch expected.(type) {
+	case *Union, *TypeVar, *MutableRef:
+		return false
+	}
+	return true
+}
+
+// propagatedErrorWidens reports whether a `try` may propagate an error of
+// type actual out of a function whose Result error is expected.
+func propagatedErrorWidens(expected Type, actual Type) bool {
+	union, ok := expected.(*Union)
+	return ok && unionAccepts(union, actual)
+}
+
 func (c *Checker) checkForeignInterfaceImplementation(s *parse.TraitImplementation, iface *ForeignType) *Statement {
 	typeSym, ok := c.scope.get(s.ForType.Name)
 	if !ok {
@@ -1928,6 +2163,9 @@ func (c *Checker) checkForeignInterfaceImplementation(s *parse.TraitImplementati
 		}.build())
 		return nil
 	}
+	previousSelf := c.selfType
+	c.selfType = targetType
+	defer func() { c.selfType = previousSelf }()
 	if !iface.MethodsLoaded && iface.LoadMethods != nil {
 		iface.Methods, iface.UnsupportedMethods = iface.LoadMethods(false)
 		iface.MethodsLoaded = true
@@ -2084,7 +2322,7 @@ func (c *Checker) checkForeignInterfaceImplementation(s *parse.TraitImplementati
 	}
 	owner := StructMethodOwner(targetType)
 	c.program.ForeignInterfaceImpls[owner] = append(c.program.ForeignInterfaceImpls[owner], iface)
-	return &Statement{Stmt: targetType}
+	return c.nodes.statements.Put(Statement{Stmt: targetType})
 }
 
 func (c *Checker) structImplementsForeignInterface(def *StructDef, iface *ForeignType) bool {
@@ -2233,7 +2471,7 @@ func (c *Checker) checkDefer(s *parse.Defer) *Statement {
 		if expr == nil {
 			return nil
 		}
-		return &Statement{Stmt: &Defer{Expr: expr}}
+		return c.nodes.statements.Put(Statement{Stmt: &Defer{Expr: expr}})
 	}
 
 	diagnosticCount := len(c.diagnostics)
@@ -2249,7 +2487,7 @@ func (c *Checker) checkDefer(s *parse.Defer) *Statement {
 	if len(c.diagnostics) != diagnosticCount {
 		return nil
 	}
-	return &Statement{Stmt: &Defer{Body: body}}
+	return c.nodes.statements.Put(Statement{Stmt: &Defer{Body: body}})
 }
 
 func (c *Checker) rejectUnresolvedCallType(t Type, location parse.Location) bool {
@@ -2264,7 +2502,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 	if c.halted {
 		return nil
 	}
-	if c.isDuplicateTopLevelTypeDeclaration(*stmt) {
+	if c.isDuplicateTopLevelDeclaration(*stmt) {
 		return nil
 	}
 	switch s := (*stmt).(type) {
@@ -2279,9 +2517,11 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 			}
 			return nil
 		}
-		return &Statement{Break: true}
+		return c.nodes.statements.Put(Statement{Break: true})
 	case *parse.Defer:
 		return c.checkDefer(s)
+	case *parse.Assert:
+		return c.checkAssert(s)
 	case *parse.TraitDefinition:
 		{
 			trait, ok := c.hoistedTrait(s.Name.Name)
@@ -2344,6 +2584,9 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				if mod != nil {
 					if propId, ok := name.Property.(*parse.Identifier); ok {
 						sym = mod.Get(propId.Name)
+						if sym.IsZero() && c.checkPrivateModuleMember(mod, propId.Name, name.String(), name.GetLocation()) {
+							return nil
+						}
 					} else {
 						panic(fmt.Errorf("unexpected trait path property: %T", name.Property))
 					}
@@ -2401,92 +2644,94 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				implementedMethods := make(map[string]bool)
 				invalidImplementedMethods := map[string]bool{}
 				receiverGenerics := genericParamsForType(targetType)
+				c.withSelfType(targetType, func() {
+					// Check each method in the implementation
+					for i, method := range s.Methods {
+						if len(method.TypeParams) > 0 {
+							c.addMethodIntroducedGeneric("", methodGenericExplicitDeclaration, method.GetLocation())
+							invalidImplementedMethods[method.Name] = true
+							continue
+						}
+						implementedMethods[method.Name] = true
+
+						// Find the corresponding trait method
+						var traitMethod *FunctionDef
+						for _, m := range traitMethods {
+							if m.Name == method.Name {
+								traitMethod = &m
+								break
+							}
+						}
 
-				// Check each method in the implementation
-				for _, method := range s.Methods {
-					if len(method.TypeParams) > 0 {
-						c.addMethodIntroducedGeneric("", methodGenericExplicitDeclaration, method.GetLocation())
-						invalidImplementedMethods[method.Name] = true
-						continue
-					}
-					implementedMethods[method.Name] = true
+						if traitMethod == nil {
+							c.addDiagnostic(unexpectedImplementationMethodDiagnostic{
+								Method: method.Name, Contract: trait.name(), ContractKind: "trait", Span: c.sourceSpan(method.GetLocation()),
+							}.build())
+							continue
+						}
 
-					// Find the corresponding trait method
-					var traitMethod *FunctionDef
-					for _, m := range traitMethods {
-						if m.Name == method.Name {
-							traitMethod = &m
-							break
+						// Check parameter count
+						if len(method.Parameters) != len(traitMethod.Parameters) {
+							c.addDiagnostic(implementationParameterCountDiagnostic{
+								Method: method.Name, Expected: len(traitMethod.Parameters), Actual: len(method.Parameters), Span: c.sourceSpan(method.GetLocation()),
+							}.build())
+							continue
 						}
-					}
 
-					if traitMethod == nil {
-						c.addDiagnostic(unexpectedImplementationMethodDiagnostic{
-							Method: method.Name, Contract: trait.name(), ContractKind: "trait", Span: c.sourceSpan(method.GetLocation()),
-						}.build())
-						continue
-					}
+						params := make([]Parameter, len(method.Parameters))
+						for i, param := range method.Parameters {
+							paramType, paramMutable := c.resolveParameterType(param.Type)
+							expectedType := traitMethod.Parameters[i].Type
+							if !paramType.equal(expectedType) {
+								c.addTypeMismatch(expectedType, paramType, param.GetLocation())
+							}
 
-					// Check parameter count
-					if len(method.Parameters) != len(traitMethod.Parameters) {
-						c.addDiagnostic(implementationParameterCountDiagnostic{
-							Method: method.Name, Expected: len(traitMethod.Parameters), Actual: len(method.Parameters), Span: c.sourceSpan(method.GetLocation()),
-						}.build())
-						continue
-					}
+							if paramMutable != traitMethod.Parameters[i].Mutable {
+								legacy := fmt.Sprintf("Trait method '%s' parameter '%s' mutability mismatch", method.Name, param.Name)
+								c.addDiagnostic(implementationParameterMutabilityDiagnostic{
+									Method: method.Name, Parameter: param.Name, ExpectedMutable: traitMethod.Parameters[i].Mutable,
+									Span: c.sourceSpan(param.GetLocation()), ExpectedSpan: sourceSpanIfPresent(traitMethod.Parameters[i].declaredAt), LegacyMessage: legacy,
+								}.build())
+							}
 
-					params := make([]Parameter, len(method.Parameters))
-					for i, param := range method.Parameters {
-						paramType, paramMutable := c.resolveParameterType(param.Type)
-						expectedType := traitMethod.Parameters[i].Type
-						if !paramType.equal(expectedType) {
-							c.addTypeMismatch(expectedType, paramType, param.GetLocation())
+							params[i] = Parameter{Name: param.Name, Type: paramType, Mutable: paramMutable, Loc: param.GetLocation(), declaredAt: c.sourceSpan(param.GetLocation())}
 						}
 
-						if paramMutable != traitMethod.Parameters[i].Mutable {
-							legacy := fmt.Sprintf("Trait method '%s' parameter '%s' mutability mismatch", method.Name, param.Name)
-							c.addDiagnostic(implementationParameterMutabilityDiagnostic{
-								Method: method.Name, Parameter: param.Name, ExpectedMutable: traitMethod.Parameters[i].Mutable,
-								Span: c.sourceSpan(param.GetLocation()), ExpectedSpan: sourceSpanIfPresent(traitMethod.Parameters[i].declaredAt), LegacyMessage: legacy,
+						// Check return type
+						var returnType Type = Void
+						if method.ReturnType != nil {
+							returnType = c.resolveType(method.ReturnType)
+						}
+						if !traitMethod.ReturnType.equal(returnType) {
+							location := method.GetLocation()
+							if method.ReturnType != nil {
+								location = method.ReturnType.GetLocation()
+							}
+							legacy := fmt.Sprintf("Trait method '%s' has return type of %s", method.Name, traitMethod.ReturnType)
+							c.addDiagnostic(implementationReturnTypeDiagnostic{
+								Method: method.Name, Expected: traitMethod.ReturnType, Actual: returnType, Span: c.sourceSpan(location), LegacyMessage: legacy,
 							}.build())
+							continue
 						}
 
-						params[i] = Parameter{Name: param.Name, Type: paramType, Mutable: paramMutable, Loc: param.GetLocation(), declaredAt: c.sourceSpan(param.GetLocation())}
-					}
-
-					// Check return type
-					var returnType Type = Void
-					if method.ReturnType != nil {
-						returnType = c.resolveType(method.ReturnType)
-					}
-					if !traitMethod.ReturnType.equal(returnType) {
-						location := method.GetLocation()
-						if method.ReturnType != nil {
-							location = method.ReturnType.GetLocation()
+						// if we made it this far, it's a valid implementation
+						c.pushMethodGenericAllowlist(receiverGenerics)
+						fnDef := c.checkFunction(&method, func() {
+							c.scope.add(s.Receiver.Name, targetType, method.Mutates)
+						}, receiverGenerics...)
+						c.popMethodGenericAllowlist()
+						if fnDef != nil && !methodUsesOnlyReceiverGenerics(fnDef, receiverGenerics) {
+							c.addMethodIntroducedGeneric("", methodGenericSemanticLeak, method.GetLocation())
+							invalidImplementedMethods[method.Name] = true
+							continue
 						}
-						legacy := fmt.Sprintf("Trait method '%s' has return type of %s", method.Name, traitMethod.ReturnType)
-						c.addDiagnostic(implementationReturnTypeDiagnostic{
-							Method: method.Name, Expected: traitMethod.ReturnType, Actual: returnType, Span: c.sourceSpan(location), LegacyMessage: legacy,
-						}.build())
-						continue
-					}
-
-					// if we made it this far, it's a valid implementation
-					c.pushMethodGenericAllowlist(receiverGenerics)
-					fnDef := c.checkFunction(&method, func() {
-						c.scope.add(s.Receiver.Name, targetType, method.Mutates)
-					}, receiverGenerics...)
-					c.popMethodGenericAllowlist()
-					if fnDef != nil && !methodUsesOnlyReceiverGenerics(fnDef, receiverGenerics) {
-						c.addMethodIntroducedGeneric("", methodGenericSemanticLeak, method.GetLocation())
-						invalidImplementedMethods[method.Name] = true
-						continue
+						fnDef.Receiver = s.Receiver.Name
+						fnDef.Mutates = method.Mutates
+						fnDef = c.adoptHoistedMethod(&s.Methods[i], fnDef)
+						// add the method to the struct method table
+						c.addStructMethod(targetType, fnDef)
 					}
-					fnDef.Receiver = s.Receiver.Name
-					fnDef.Mutates = method.Mutates
-					// add the method to the struct method table
-					c.addStructMethod(targetType, fnDef)
-				}
+				})
 
 				// Check if all required methods are implemented
 				for _, method := range traitMethods {
@@ -2498,11 +2743,14 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 					}
 				}
 
-				// Add the trait to the struct type's traits list
-				targetType.Traits = append(targetType.Traits, trait)
+				// Add the trait to the struct type's traits list, unless hoisting
+				// already recorded it
+				if !slices.Contains(targetType.Traits, trait) {
+					targetType.Traits = append(targetType.Traits, trait)
+				}
 
 				// Return the struct so downstream backends can register the new trait methods
-				return &Statement{Stmt: targetType}
+				return c.nodes.statements.Put(Statement{Stmt: targetType})
 
 			case *Enum:
 				// Verify that all required methods are implemented (same logic as structs)
@@ -2510,101 +2758,103 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				implementedMethods := make(map[string]bool)
 				invalidImplementedMethods := map[string]bool{}
 				receiverGenerics := genericParamsForType(targetType)
+				c.withSelfType(targetType, func() {
+					// Check each method in the implementation
+					for i, method := range s.Methods {
+						if len(method.TypeParams) > 0 {
+							c.addMethodIntroducedGeneric("", methodGenericExplicitDeclaration, method.GetLocation())
+							invalidImplementedMethods[method.Name] = true
+							continue
+						}
+						implementedMethods[method.Name] = true
+
+						// Find the corresponding trait method
+						var traitMethod *FunctionDef
+						for _, m := range traitMethods {
+							if m.Name == method.Name {
+								traitMethod = &m
+								break
+							}
+						}
 
-				// Check each method in the implementation
-				for _, method := range s.Methods {
-					if len(method.TypeParams) > 0 {
-						c.addMethodIntroducedGeneric("", methodGenericExplicitDeclaration, method.GetLocation())
-						invalidImplementedMethods[method.Name] = true
-						continue
-					}
-					implementedMethods[method.Name] = true
+						if traitMethod == nil {
+							c.addDiagnostic(unexpectedImplementationMethodDiagnostic{
+								Method: method.Name, Contract: trait.name(), ContractKind: "trait", Span: c.sourceSpan(method.GetLocation()),
+							}.build())
+							continue
+						}
 
-					// Find the corresponding trait method
-					var traitMethod *FunctionDef
-					for _, m := range traitMethods {
-						if m.Name == method.Name {
-							traitMethod = &m
-							break
+						// Check parameter count
+						if len(method.Parameters) != len(traitMethod.Parameters) {
+							c.addDiagnostic(implementationParameterCountDiagnostic{
+								Method: method.Name, Expected: len(traitMethod.Parameters), Actual: len(method.Parameters), Span: c.sourceSpan(method.GetLocation()),
+							}.build())
+							continue
 						}
-					}
 
-					if traitMethod == nil {
-						c.addDiagnostic(unexpectedImplementationMethodDiagnostic{
-							Method: method.Name, Contract: trait.name(), ContractKind: "trait", Span: c.sourceSpan(method.GetLocation()),
-						}.build())
-						continue
-					}
+						params := make([]Parameter, len(method.Parameters))
+						for i, param := range method.Parameters {
+							paramType, paramMutable := c.resolveParameterType(param.Type)
+							expectedType := traitMethod.Parameters[i].Type
+							if !paramType.equal(expectedType) {
+								c.addTypeMismatch(expectedType, paramType, param.GetLocation())
+							}
 
-					// Check parameter count
-					if len(method.Parameters) != len(traitMethod.Parameters) {
-						c.addDiagnostic(implementationParameterCountDiagnostic{
-							Method: method.Name, Expected: len(traitMethod.Parameters), Actual: len(method.Parameters), Span: c.sourceSpan(method.GetLocation()),
-						}.build())
-						continue
-					}
+							if paramMutable != traitMethod.Parameters[i].Mutable {
+								legacy := fmt.Sprintf("Trait method '%s' parameter '%s' mutability mismatch", method.Name, param.Name)
+								c.addDiagnostic(implementationParameterMutabilityDiagnostic{
+									Method: method.Name, Parameter: param.Name, ExpectedMutable: traitMethod.Parameters[i].Mutable,
+									Span: c.sourceSpan(param.GetLocation()), ExpectedSpan: sourceSpanIfPresent(traitMethod.Parameters[i].declaredAt), LegacyMessage: legacy,
+								}.build())
+							}
 
-					params := make([]Parameter, len(method.Parameters))
-					for i, param := range method.Parameters {
-						paramType, paramMutable := c.resolveParameterType(param.Type)
-						expectedType := traitMethod.Parameters[i].Type
-						if !paramType.equal(expectedType) {
-							c.addTypeMismatch(expectedType, paramType, param.GetLocation())
+							params[i] = Parameter{Name: param.Name, Type: paramType, Mutable: paramMutable, Loc: param.GetLocation(), declaredAt: c.sourceSpan(param.GetLocation())}
 						}
 
-						if paramMutable != traitMethod.Parameters[i].Mutable {
-							legacy := fmt.Sprintf("Trait method '%s' parameter '%s' mutability mismatch", method.Name, param.Name)
-							c.addDiagnostic(implementationParameterMutabilityDiagnostic{
-								Method: method.Name, Parameter: param.Name, ExpectedMutable: traitMethod.Parameters[i].Mutable,
-								Span: c.sourceSpan(param.GetLocation()), ExpectedSpan: sourceSpanIfPresent(traitMethod.Parameters[i].declaredAt), LegacyMessage: legacy,
+						// Check return type
+						var returnType Type = Void
+						if method.ReturnType != nil {
+							returnType = c.resolveType(method.ReturnType)
+						}
+						if !traitMethod.ReturnType.equal(returnType) {
+							location := method.GetLocation()
+							if method.ReturnType != nil {
+								location = method.ReturnType.GetLocation()
+							}
+							legacy := fmt.Sprintf("Trait method '%s' has return type of %s", method.Name, traitMethod.ReturnType)
+							c.addDiagnostic(implementationReturnTypeDiagnostic{
+								Method: method.Name, Expected: traitMethod.ReturnType, Actual: returnType, Span: c.sourceSpan(location), LegacyMessage: legacy,
 							}.build())
+							continue
 						}
 
-						params[i] = Parameter{Name: param.Name, Type: paramType, Mutable: paramMutable, Loc: param.GetLocation(), declaredAt: c.sourceSpan(param.GetLocation())}
-					}
-
-					// Check return type
-					var returnType Type = Void
-					if method.ReturnType != nil {
-						returnType = c.resolveType(method.ReturnType)
-					}
-					if !traitMethod.ReturnType.equal(returnType) {
-						location := method.GetLocation()
-						if method.ReturnType != nil {
-							location = method.ReturnType.GetLocation()
+						// if we made it this far, it's a valid implementation
+						c.pushMethodGenericAllowlist(receiverGenerics)
+						fnDef := c.checkFunction(&method, func() {
+							c.scope.add(s.Receiver.Name, targetType, false) // Enums are immutable, so always false
+						}, receiverGenerics...)
+						c.popMethodGenericAllowlist()
+						if fnDef != nil && !methodUsesOnlyReceiverGenerics(fnDef, receiverGenerics) {
+							c.addMethodIntroducedGeneric("", methodGenericSemanticLeak, method.GetLocation())
+							invalidImplementedMethods[method.Name] = true
+							continue
 						}
-						legacy := fmt.Sprintf("Trait method '%s' has return type of %s", method.Name, traitMethod.ReturnType)
-						c.addDiagnostic(implementationReturnTypeDiagnostic{
-							Method: method.Name, Expected: traitMethod.ReturnType, Actual: returnType, Span: c.sourceSpan(location), LegacyMessage: legacy,
-						}.build())
-						continue
-					}
-
-					// if we made it this far, it's a valid implementation
-					c.pushMethodGenericAllowlist(receiverGenerics)
-					fnDef := c.checkFunction(&method, func() {
-						c.scope.add(s.Receiver.Name, targetType, false) // Enums are immutable, so always false
-					}, receiverGenerics...)
-					c.popMethodGenericAllowlist()
-					if fnDef != nil && !methodUsesOnlyReceiverGenerics(fnDef, receiverGenerics) {
-						c.addMethodIntroducedGeneric("", methodGenericSemanticLeak, method.GetLocation())
-						invalidImplementedMethods[method.Name] = true
-						continue
-					}
-					fnDef.Receiver = s.Receiver.Name
-					// Enums cannot have mutating methods
-					if method.Mutates {
-						c.addDiagnostic(mutatingEnumMethodDiagnostic{Span: c.sourceSpan(method.GetLocation())}.build())
-					}
-					fnDef.Mutates = false // Enums are always immutable
+						fnDef.Receiver = s.Receiver.Name
+						// Enums cannot have mutating methods
+						if method.Mutates {
+							c.addDiagnostic(mutatingEnumMethodDiagnostic{Span: c.sourceSpan(method.GetLocation())}.build())
+						}
+						fnDef.Mutates = false // Enums are always immutable
+						fnDef = c.adoptHoistedMethod(&s.Methods[i], fnDef)
 
-					// Ensure enum has Methods map initialized
-					if targetType.Methods == nil {
-						targetType.Methods = make(map[string]*FunctionDef)
+						// Ensure enum has Methods map initialized
+						if targetType.Methods == nil {
+							targetType.Methods = make(map[string]*FunctionDef)
+						}
+						// add the method to the enum
+						targetType.Methods[method.Name] = fnDef
 					}
-					// add the method to the enum
-					targetType.Methods[method.Name] = fnDef
-				}
+				})
 
 				// Check if all required methods are implemented
 				for _, method := range traitMethods {
@@ -2616,11 +2866,14 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 					}
 				}
 
-				// Add the trait to the enum type's traits list
-				targetType.Traits = append(targetType.Traits, trait)
+				// Add the trait to the enum type's traits list, unless hoisting
+				// already recorded it
+				if !slices.Contains(targetType.Traits, trait) {
+					targetType.Traits = append(targetType.Traits, trait)
+				}
 
 				// Return the enum so downstream backends can register the new trait methods
-				return &Statement{Stmt: targetType}
+				return c.nodes.statements.Put(Statement{Stmt: targetType})
 
 			default:
 				legacy := fmt.Sprintf("%s cannot implement a Trait", s.ForType.Name)
@@ -2668,10 +2921,23 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 			unionType.ModulePath = c.typeOwnerPath()
 			unionType.Types = types
 			unionType.Private = s.Private
-			return &Statement{Stmt: unionType}
+			return c.nodes.statements.Put(Statement{Stmt: unionType})
 		}
 	case *parse.VariableDeclaration:
 		{
+			if s.Else != nil {
+				// `let x = value else { ... }` unwraps value like a try whose
+				// catch block is the else block.
+				decl := *s
+				decl.Value = &parse.Try{
+					Location:   s.Value.GetLocation(),
+					Expression: s.Value,
+					CatchVar:   &parse.Identifier{Location: s.Value.GetLocation(), Name: "_"},
+					CatchBlock: s.Else,
+				}
+				decl.Else = nil
+				s = &decl
+			}
 			var val Expression
 			c.withValueExprContext(func() {
 				if s.Type == nil {
@@ -2698,6 +2964,12 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 						} else {
 							check()
 						}
+					case *parse.IfStatement:
+						if ifChainHasElse(literal) {
+							val = c.checkExpr(s.Value)
+						} else {
+							val = c.checkOptionalIf(literal, nil)
+						}
 					default:
 						val = c.checkExpr(s.Value)
 					}
@@ -2761,14 +3033,30 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				return nil
 			}
 
+			if s.Const {
+				folded, ok := c.checkConstant(s, val, __type)
+				if !ok {
+					return nil
+				}
+				val = folded
+			}
+
 			v := &VariableDef{
 				Mutable: s.Mutable,
+				Const:   s.Const,
+				Private: s.Private,
 				Name:    s.Name,
 				Value:   val,
 				__type:  __type,
 			}
 			bound := c.scope.add(v.Name, v.__type, v.Mutable)
+			if v.Const {
+				bound.constant = val
+			}
+			c.recordGenericBinding(v, genericBindingSite{location: s.NameLocation})
 			c.recordBindingWithSpan(s.NameLocation, s.GetLocation(), bound)
+			c.trackLocal(bound, s.NameLocation)
+			c.trackShadowing(bound, s.NameLocation)
 			if c.spans != nil && c.scope.parent == nil {
 				// Module-level values are importable; give them a canonical
 				// identity for cross-module references.
@@ -2778,9 +3066,9 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 					IsDef: true,
 				})
 			}
-			return &Statement{
+			return c.nodes.statements.Put(Statement{
 				Stmt: v,
-			}
+			})
 		}
 	case *parse.VariableAssignment:
 		{
@@ -2846,9 +3134,11 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 					return nil
 				}
 
-				return &Statement{
-					Stmt: &Reassignment{Target: &Variable{*target}, Value: value},
-				}
+				reassignment := &Reassignment{Target: c.nodes.variables.Put(Variable{*target}), Value: value}
+				c.recordGenericBinding(reassignment, genericBindingSite{location: s.Value.GetLocation()})
+				return c.nodes.statements.Put(Statement{
+					Stmt: reassignment,
+				})
 			}
 
 			if ip, ok := s.Target.(*parse.InstanceProperty); ok {
@@ -2911,9 +3201,11 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 					return nil
 				}
 
-				return &Statement{
-					Stmt: &Reassignment{Target: subject, Value: value},
-				}
+				reassignment := &Reassignment{Target: subject, Value: value}
+				c.recordGenericBinding(reassignment, genericBindingSite{location: s.Value.GetLocation()})
+				return c.nodes.statements.Put(Statement{
+					Stmt: reassignment,
+				})
 			}
 
 			if sp, ok := s.Target.(*parse.StaticProperty); ok {
@@ -2932,7 +3224,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 									c.addTypeMismatch(typ, value.Type(), s.Value.GetLocation())
 									return nil
 								}
-								return &Statement{Stmt: &Reassignment{Target: &ForeignValue{Target: "go", Namespace: goPkg.Path, Qualifier: goPkg.TypesName, Symbol: prop.Name, ValueType: typ, Assignable: true}, Value: value}}
+								return c.nodes.statements.Put(Statement{Stmt: &Reassignment{Target: &ForeignValue{Target: "go", Namespace: goPkg.Path, Qualifier: goPkg.TypesName, Symbol: prop.Name, ValueType: typ, Assignable: true}, Value: value}})
 							}
 							if goPkg.Constants[prop.Name] != nil {
 								c.addDiagnostic(nonAssignableStaticPropertyDiagnostic{
@@ -2972,6 +3264,14 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 		}
 	case *parse.WhileLoop:
 		{
+			if s.Let != nil {
+				loop := c.checkWhileLet(s)
+				if loop == nil {
+					return nil
+				}
+				return c.nodes.statements.Put(Statement{Stmt: loop})
+			}
+
 			// Check the condition expression
 			var condition Expression
 			if s.Condition == nil {
@@ -2999,7 +3299,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				Body:      body,
 			}
 
-			return &Statement{Stmt: loop}
+			return c.nodes.statements.Put(Statement{Stmt: loop})
 		}
 	case *parse.ForLoop:
 		{
@@ -3060,7 +3360,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				Body:      body,
 			}
 
-			return &Statement{Stmt: loop}
+			return c.nodes.statements.Put(Statement{Stmt: loop})
 		}
 	case *parse.RangeLoop:
 		{
@@ -3081,6 +3381,16 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 					Start:  start,
 					End:    end,
 				}
+				if s.Step != nil {
+					loop.Step = c.checkExprAs(s.Step, Int)
+					if loop.Step == nil {
+						return nil
+					}
+					if value, err := evalConstant(loop.Step); err == nil && value.(*IntLiteral).Value == 0 {
+						c.addDiagnostic(zeroRangeStepDiagnostic{Span: c.sourceSpan(s.Step.GetLocation())}.build())
+						return nil
+					}
+				}
 				body := c.checkBlock(s.Body, c.markLoopScope(func() {
 					c.recordBinding(s.Cursor.GetLocation(), c.scope.add(s.Cursor.Name, start.Type(), false))
 					if loop.Index != "" {
@@ -3088,7 +3398,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 					}
 				}))
 				loop.Body = body
-				return &Statement{Stmt: loop}
+				return c.nodes.statements.Put(Statement{Stmt: loop})
 			}
 
 			legacy := fmt.Sprintf("Cannot create range of %s", start.Type())
@@ -3120,7 +3430,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				}))
 
 				loop.Body = body
-				return &Statement{Stmt: loop}
+				return c.nodes.statements.Put(Statement{Stmt: loop})
 			}
 
 			// Handle integer iteration (for i in n - sugar for 0..n)
@@ -3129,8 +3439,8 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				loop := &ForIntRange{
 					Cursor: s.Cursor.Name,
 					Index:  s.Cursor2.Name,
-					Start:  &IntLiteral{0}, // Start from 0
-					End:    iterValue,      // End at the specified number
+					Start:  &IntLiteral{Value: 0}, // Start from 0
+					End:    iterValue,             // End at the specified number
 				}
 
 				// Create a new scope for the loop body where the cursor is defined
@@ -3143,7 +3453,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				}))
 
 				loop.Body = body
-				return &Statement{Stmt: loop}
+				return c.nodes.statements.Put(Statement{Stmt: loop})
 			}
 
 			if listType, ok := iterValue.Type().(*List); ok {
@@ -3164,7 +3474,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				}))
 
 				loop.Body = body
-				return &Statement{Stmt: loop}
+				return c.nodes.statements.Put(Statement{Stmt: loop})
 			}
 
 			if arrayType, ok := iterValue.Type().(*FixedArray); ok {
@@ -3181,7 +3491,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				}))
 
 				loop.Body = body
-				return &Statement{Stmt: loop}
+				return c.nodes.statements.Put(Statement{Stmt: loop})
 			}
 
 			if mapType, ok := iterValue.Type().(*Map); ok {
@@ -3204,7 +3514,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				}))
 
 				loop.Body = body
-				return &Statement{Stmt: loop}
+				return c.nodes.statements.Put(Statement{Stmt: loop})
 			}
 
 			// Currently we only support string, integer, and List iteration
@@ -3281,9 +3591,17 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 				seenValues[value] = variant.Name
 				seenValueSpans[value] = valueSpan
 
+				var payload []Type
+				for _, declared := range variant.Payload {
+					if payloadType := c.resolveType(declared); payloadType != nil {
+						payload = append(payload, payloadType)
+					}
+				}
+
 				computedValues = append(computedValues, EnumValue{
-					Name:  variant.Name,
-					Value: value,
+					Name:    variant.Name,
+					Value:   value,
+					Payload: payload,
 				})
 			}
 
@@ -3304,7 +3622,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 			}
 			c.recordDef(s.Name.GetLocation(), TypeKey(c.typeOwnerPath(), s.Name.Name))
 			c.populateStructDefinition(def, s)
-			return &Statement{Stmt: def}
+			return c.nodes.statements.Put(Statement{Stmt: def})
 		}
 	case *parse.ImplBlock:
 		{
@@ -3320,80 +3638,86 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 			switch def := sym.Type.(type) {
 			case *StructDef:
 				receiverGenerics := genericParamsForType(def)
-				signatures := make([]*FunctionDef, len(s.Methods))
-				for i := range s.Methods {
-					method := &s.Methods[i]
-					if len(method.TypeParams) > 0 {
-						c.addMethodIntroducedGeneric("", methodGenericExplicitDeclaration, method.GetLocation())
-						continue
-					}
-					c.pushMethodGenericAllowlist(receiverGenerics)
-					fnDef := c.resolveMethodSignature(method)
-					c.popMethodGenericAllowlist()
-					fnDef.Receiver = s.Receiver.Name
-					fnDef.Mutates = method.Mutates
-					signatures[i] = fnDef
-					c.addStructMethod(def, fnDef)
-				}
-				for i := range s.Methods {
-					method := &s.Methods[i]
-					if signatures[i] == nil {
-						continue
-					}
-					if c.spans != nil {
-						c.spans.add(SpanRecord{
-							Loc:   method.GetLocation(),
-							Key:   MemberKey(TargetMethod, def.ModulePath, def.Name, method.Name),
-							IsDef: true,
-						})
-					}
-					c.pushMethodGenericAllowlist(receiverGenerics)
-					fnDef := c.checkFunctionWithSignature(method, func() {
-						c.scope.add(s.Receiver.Name, def, method.Mutates)
-					}, signatures[i], receiverGenerics...)
-					c.popMethodGenericAllowlist()
-					if !methodUsesOnlyReceiverGenerics(fnDef, receiverGenerics) {
-						c.addMethodIntroducedGeneric("", methodGenericSemanticLeak, method.GetLocation())
+				c.withSelfType(def, func() {
+					signatures := make([]*FunctionDef, len(s.Methods))
+					for i := range s.Methods {
+						method := &s.Methods[i]
+						if len(method.TypeParams) > 0 {
+							c.addMethodIntroducedGeneric("", methodGenericExplicitDeclaration, method.GetLocation())
+							continue
+						}
+						c.pushMethodGenericAllowlist(receiverGenerics)
+						fnDef := c.resolveMethodSignature(method)
+						c.popMethodGenericAllowlist()
+						fnDef.Receiver = s.Receiver.Name
+						fnDef.Mutates = method.Mutates
+						fnDef = c.adoptHoistedMethod(method, fnDef)
+						signatures[i] = fnDef
+						c.addStructMethod(def, fnDef)
+					}
+					for i := range s.Methods {
+						method := &s.Methods[i]
+						if signatures[i] == nil {
+							continue
+						}
+						if c.spans != nil {
+							c.spans.add(SpanRecord{
+								Loc:   method.GetLocation(),
+								Key:   MemberKey(TargetMethod, def.ModulePath, def.Name, method.Name),
+								IsDef: true,
+							})
+						}
+						c.pushMethodGenericAllowlist(receiverGenerics)
+						fnDef := c.checkFunctionWithSignature(method, func() {
+							c.scope.add(s.Receiver.Name, def, method.Mutates)
+						}, signatures[i], receiverGenerics...)
+						c.popMethodGenericAllowlist()
+						if !methodUsesOnlyReceiverGenerics(fnDef, receiverGenerics) {
+							c.addMethodIntroducedGeneric("", methodGenericSemanticLeak, method.GetLocation())
+						}
 					}
-				}
-				return &Statement{Stmt: def}
+				})
+				return c.nodes.statements.Put(Statement{Stmt: def})
 			case *Enum:
 				if def.Methods == nil {
 					def.Methods = make(map[string]*FunctionDef)
 				}
 				receiverGenerics := genericParamsForType(def)
-				signatures := make([]*FunctionDef, len(s.Methods))
-				for i := range s.Methods {
-					method := &s.Methods[i]
-					if len(method.TypeParams) > 0 {
-						c.addMethodIntroducedGeneric("", methodGenericExplicitDeclaration, method.GetLocation())
-						continue
-					}
-					if method.Mutates {
-						c.addDiagnostic(mutatingEnumMethodDiagnostic{Span: c.sourceSpan(method.GetLocation())}.build())
-					}
-					c.pushMethodGenericAllowlist(receiverGenerics)
-					fnDef := c.resolveMethodSignature(method)
-					c.popMethodGenericAllowlist()
-					fnDef.Receiver = s.Receiver.Name
-					signatures[i] = fnDef
-					def.Methods[method.Name] = fnDef
-				}
-				for i := range s.Methods {
-					method := &s.Methods[i]
-					if signatures[i] == nil {
-						continue
-					}
-					c.pushMethodGenericAllowlist(receiverGenerics)
-					fnDef := c.checkFunctionWithSignature(method, func() {
-						c.scope.add(s.Receiver.Name, def, false)
-					}, signatures[i], receiverGenerics...)
-					c.popMethodGenericAllowlist()
-					if !methodUsesOnlyReceiverGenerics(fnDef, receiverGenerics) {
-						c.addMethodIntroducedGeneric("", methodGenericSemanticLeak, method.GetLocation())
+				c.withSelfType(def, func() {
+					signatures := make([]*FunctionDef, len(s.Methods))
+					for i := range s.Methods {
+						method := &s.Methods[i]
+						if len(method.TypeParams) > 0 {
+							c.addMethodIntroducedGeneric("", methodGenericExplicitDeclaration, method.GetLocation())
+							continue
+						}
+						if method.Mutates {
+							c.addDiagnostic(mutatingEnumMethodDiagnostic{Span: c.sourceSpan(method.GetLocation())}.build())
+						}
+						c.pushMethodGenericAllowlist(receiverGenerics)
+						fnDef := c.resolveMethodSignature(method)
+						c.popMethodGenericAllowlist()
+						fnDef.Receiver = s.Receiver.Name
+						fnDef = c.adoptHoistedMethod(method, fnDef)
+						signatures[i] = fnDef
+						def.Methods[method.Name] = fnDef
+					}
+					for i := range s.Methods {
+						method := &s.Methods[i]
+						if signatures[i] == nil {
+							continue
+						}
+						c.pushMethodGenericAllowlist(receiverGenerics)
+						fnDef := c.checkFunctionWithSignature(method, func() {
+							c.scope.add(s.Receiver.Name, def, false)
+						}, signatures[i], receiverGenerics...)
+						c.popMethodGenericAllowlist()
+						if !methodUsesOnlyReceiverGenerics(fnDef, receiverGenerics) {
+							c.addMethodIntroducedGeneric("", methodGenericSemanticLeak, method.GetLocation())
+						}
 					}
-				}
-				return &Statement{Stmt: def}
+				})
+				return c.nodes.statements.Put(Statement{Stmt: def})
 			default:
 				legacy := fmt.Sprintf("Can only implement methods on structs and enums, not %s", sym.Type)
 				c.addDiagnostic(invalidImplementationTargetDiagnostic{
@@ -3414,7 +3738,7 @@ func (c *Checker) checkStmt(stmt *parse.Statement) *Statement {
 		if c.rejectUnresolvedCallType(expr.Type(), (*stmt).GetLocation()) {
 			return nil
 		}
-		return &Statement{Expr: expr}
+		return c.nodes.statements.Put(Statement{Expr: expr})
 	}
 }
 
@@ -3561,8 +3885,9 @@ func (c *Checker) markLoopScope(setup func()) func() {
 
 func (c *Checker) checkBlockWithExpected(stmts []parse.Statement, setup func(), expectedFinal Type, onlyMatchFinal bool) *Block {
 	if len(stmts) == 0 {
-		return &Block{Stmts: []Statement{}}
+		return c.nodes.blocks.Put(Block{Stmts: []Statement{}})
 	}
+	c.reportUnreachable(stmts)
 
 	parent := c.scope
 	newScope := makeScope(parent)
@@ -3591,7 +3916,7 @@ func (c *Checker) checkBlockWithExpected(stmts []parse.Statement, setup func(),
 		}
 	}
 
-	block := &Block{Stmts: make([]Statement, len(stmts)), DiscardFinalValue: expectedFinal == Void}
+	block := c.nodes.blocks.Put(Block{Stmts: make([]Statement, len(stmts)), DiscardFinalValue: expectedFinal == Void})
 	for i := range stmts {
 		if i == lastExprIndex {
 			expr := c.checkExprAs(stmts[i].(parse.Expression), expectedFinal)
@@ -3613,11 +3938,15 @@ func (c *Checker) checkBlockWithExpected(stmts []parse.Statement, setup func(),
 
 func (c *Checker) canCheckStatementAsExpectedExpression(stmt parse.Statement, expectedFinal Type, onlyMatchFinal bool) bool {
 	if onlyMatchFinal && expectedFinal != Void {
-		switch stmt.(type) {
+		switch s := stmt.(type) {
 		case *parse.MatchExpression, *parse.ConditionalMatchExpression, *parse.SelectExpression, *parse.IfStatement,
 			*parse.FunctionCall, *parse.FunctionValueCall, *parse.InstanceMethod, *parse.StaticFunction,
 			*parse.Try, *parse.UnsafeBlock:
 			return true
+		case *parse.StructInstance:
+			// A generic literal without type arguments takes them from the
+			// return type.
+			return len(s.TypeArgs) == 0 && c.isGenericStruct(s.Name.Name)
 		default:
 			return false
 		}
@@ -3639,7 +3968,7 @@ func (c *Checker) canCheckStatementAsExpectedExpression(stmt parse.Statement, ex
 	switch stmt.(type) {
 	case *parse.MatchExpression, *parse.ConditionalMatchExpression, *parse.SelectExpression, *parse.IfStatement,
 		*parse.FunctionCall, *parse.FunctionValueCall, *parse.InstanceMethod, *parse.StaticFunction,
-		*parse.ListLiteral, *parse.MapLiteral, *parse.AnonymousFunction, *parse.UnsafeBlock:
+		*parse.ListLiteral, *parse.MapLiteral, *parse.AnonymousFunction, *parse.StructInstance, *parse.UnsafeBlock:
 		return true
 	default:
 		return false
@@ -3664,7 +3993,7 @@ func parseStatementContainsBreak(stmt parse.Statement) bool {
 	case *parse.WhileLoop:
 		return parseExpressionContainsBreak(s.Condition) || parseStatementsContainBreak(s.Body)
 	case *parse.RangeLoop:
-		return parseExpressionContainsBreak(s.Start) || parseExpressionContainsBreak(s.End) || parseStatementsContainBreak(s.Body)
+		return parseExpressionContainsBreak(s.Start) || parseExpressionContainsBreak(s.End) || parseExpressionContainsBreak(s.Step) || parseStatementsContainBreak(s.Body)
 	case *parse.ForInLoop:
 		return parseExpressionContainsBreak(s.Iterable) || parseStatementsContainBreak(s.Body)
 	case *parse.ForLoop:
@@ -3753,6 +4082,9 @@ func parseExpressionContainsBreak(expr parse.Expression) bool {
 			}
 		}
 	case *parse.StructInstance:
+		if e.Base != nil && parseExpressionContainsBreak(e.Base) {
+			return true
+		}
 		for _, prop := range e.Properties {
 			if parseExpressionContainsBreak(prop.Value) {
 				return true
@@ -4204,6 +4536,7 @@ func (c *Checker) validateUnsafeCatchResultsInStatement(stmt Statement, resultTy
 	case *ForIntRange:
 		c.validateUnsafeCatchResultsInExpression(s.Start, resultType, loc)
 		c.validateUnsafeCatchResultsInExpression(s.End, resultType, loc)
+		c.validateUnsafeCatchResultsInExpression(s.Step, resultType, loc)
 		c.validateUnsafeCatchResults(s.Body, resultType, loc)
 	case *ForInStr:
 		c.validateUnsafeCatchResultsInExpression(s.Value, resultType, loc)
@@ -4397,10 +4730,19 @@ func (c *Checker) validateUnsafeCatchResultsInExpression(expr Expression, result
 		c.validateUnsafeCatchResultsInExpression(e.Subject, resultType, loc)
 	case *IntMethod:
 		c.validateUnsafeCatchResultsInExpression(e.Subject, resultType, loc)
+		for _, arg := range e.Args {
+			c.validateUnsafeCatchResultsInExpression(arg, resultType, loc)
+		}
 	case *FloatMethod:
 		c.validateUnsafeCatchResultsInExpression(e.Subject, resultType, loc)
 	case *BoolMethod:
 		c.validateUnsafeCatchResultsInExpression(e.Subject, resultType, loc)
+	case *EnumMethod:
+		c.validateUnsafeCatchResultsInExpression(e.Subject, resultType, loc)
+	case *EnumFromInt:
+		c.validateUnsafeCatchResultsInExpression(e.Value, resultType, loc)
+	case *NumberParse:
+		c.validateUnsafeCatchResultsInExpression(e.Text, resultType, loc)
 	case *ListMethod:
 		c.validateUnsafeCatchResultsInExpression(e.Subject, resultType, loc)
 		for _, arg := range e.Args {
@@ -4433,6 +4775,9 @@ func (c *Checker) validateUnsafeCatchResultsInExpression(expr Expression, result
 			c.validateUnsafeCatchResultsInExpression(value, resultType, loc)
 		}
 	case *StructInstance:
+		if e.Base != nil {
+			c.validateUnsafeCatchResultsInExpression(e.Base, resultType, loc)
+		}
 		for _, field := range e.Fields {
 			c.validateUnsafeCatchResultsInExpression(field, resultType, loc)
 		}
@@ -4464,6 +4809,14 @@ func (c *Checker) validateUnsafeCatchResultsInExpression(expr Expression, result
 		c.validateUnsafeCatchResultsInExpression(e.Value, resultType, loc)
 	case *UnsafeIsNil:
 		c.validateUnsafeCatchResultsInExpression(e.Value, resultType, loc)
+	case *StrFormat:
+		c.validateUnsafeCatchResultsInExpression(e.Template, resultType, loc)
+		for _, arg := range e.Args {
+			c.validateUnsafeCatchResultsInExpression(arg, resultType, loc)
+		}
+		for _, named := range e.Named {
+			c.validateUnsafeCatchResultsInExpression(named.Value, resultType, loc)
+		}
 	case *ModuleStructInstance:
 		if e.Property != nil {
 			for _, field := range e.Property.Fields {
@@ -4558,8 +4911,9 @@ func (c *Checker) checkMatchArmBlock(stmts []parse.Statement, setup func()) *Blo
 
 func (c *Checker) checkBlockWithInferredFinalValue(stmts []parse.Statement, setup func(), finalDiscardContext bool) *Block {
 	if len(stmts) == 0 {
-		return &Block{Stmts: []Statement{}}
+		return c.nodes.blocks.Put(Block{Stmts: []Statement{}})
 	}
+	c.reportUnreachable(stmts)
 
 	parent := c.scope
 	newScope := makeScope(parent)
@@ -4583,7 +4937,7 @@ func (c *Checker) checkBlockWithInferredFinalValue(stmts []parse.Statement, setu
 		break
 	}
 
-	block := &Block{Stmts: make([]Statement, len(stmts))}
+	block := c.nodes.blocks.Put(Block{Stmts: make([]Statement, len(stmts))})
 	for i := range stmts {
 		if i == lastExprIndex {
 			var expr Expression
@@ -4709,7 +5063,7 @@ func (c *Checker) checkMap(declaredType Type, expr *parse.MapLiteral) *MapLitera
 		hasError := false
 		for i, entry := range expr.Entries {
 			// Type check the key
-			key := c.checkExpr(entry.Key)
+			key := c.checkExprAs(entry.Key, expectedKeyType)
 			if key == nil {
 				hasError = true
 				continue
@@ -4722,7 +5076,7 @@ func (c *Checker) checkMap(declaredType Type, expr *parse.MapLiteral) *MapLitera
 			keys[i] = key
 
 			// Type check the value
-			value := c.checkExpr(entry.Value)
+			value := c.checkExprAs(entry.Value, expectedValueType)
 			if value == nil {
 				hasError = true
 				continue
@@ -4826,7 +5180,7 @@ func (c *Checker) validateForeignStructInstance(foreign *ForeignType, typeArgs [
 			if reason := foreign.UnsupportedFields[name]; reason != "" {
 				c.addUnsupportedGoEntity("field", fmt.Sprintf("%s.%s", foreign, name), reason, "Unsupported foreign field", property.GetLocation())
 			} else {
-				c.addUnresolvedReference(unknownStructField, name, property.GetLocation())
+				c.addUnknownStructField(name, slices.Collect(maps.Keys(foreign.Fields)), property.GetLocation())
 			}
 			continue
 		}
@@ -5153,8 +5507,84 @@ func (c *Checker) resolveStructTypeArgs(instance *parse.StructInstance) ([]Type,
 	return typeArgs, true
 }
 
-// validateStructInstance validates struct instantiation and returns the instance or nil if errors
-func (c *Checker) validateStructInstance(structType *StructDef, properties []parse.StructValue, structName string, loc parse.Location, typeArgs []Type) *StructInstance {
+// checkStructInstance checks a struct literal. A generic struct literal
+// without type arguments takes them from expected, the type its context
+// requires, when that is an instantiation of the same struct; its fields are
+// then checked against the instantiated field types.
+func (c *Checker) checkStructInstance(s *parse.StructInstance, expected Type) Expression {
+	typeArgs, argsOk := c.resolveStructTypeArgs(s)
+	if !argsOk {
+		return nil
+	}
+	name := s.Name.Name
+	var declared Type
+	if name == "Self" && c.selfType != nil {
+		declared = c.selfType
+	} else if sym, ok := c.scope.get(name); ok {
+		declared = sym.Type
+	} else {
+		c.addUnresolvedReference(undefinedStructType, name, s.GetLocation())
+		return nil
+	}
+
+	structType, ok := declared.(*StructDef)
+	if !ok {
+		c.addUnresolvedReference(notAStruct, name, s.GetLocation())
+		return nil
+	}
+	if name != "Self" && !strings.Contains(name, "::") {
+		c.recordTypeRef(s.Name.GetLocation(), name)
+	}
+	if len(typeArgs) == 0 {
+		typeArgs = c.contextualStructTypeArgs(structType, expected)
+	}
+
+	// Use helper function for validation
+	instance := c.validateStructInstance(structType, s.Properties, s.Base, name, s.GetLocation(), typeArgs)
+	if instance == nil {
+		return nil
+	}
+	return instance
+}
+
+func (c *Checker) isGenericStruct(name string) bool {
+	sym, ok := c.scope.get(name)
+	if !ok {
+		return false
+	}
+	structType, ok := sym.Type.(*StructDef)
+	return ok && structType.hasGenerics()
+}
+
+// contextualStructTypeArgs returns the type arguments of expected for a
+// literal of the generic struct structType, or nil when expected is not an
+// instantiation of it or still depends on an unresolved call's generics.
+func (c *Checker) contextualStructTypeArgs(structType *StructDef, expected Type) []Type {
+	expectedStruct, ok := derefType(expected).(*StructDef)
+	if !ok || !structType.hasGenerics() || len(structType.GenericParams) == 0 {
+		return nil
+	}
+	if !structType.DeclaredGenerics && len(structType.GenericParams) > 1 {
+		return nil
+	}
+	if c.structDefinition(expectedStruct) != structType || len(expectedStruct.TypeArgs) != len(structType.GenericParams) {
+		return nil
+	}
+	typeArgs := make([]Type, len(expectedStruct.TypeArgs))
+	for i, arg := range expectedStruct.TypeArgs {
+		arg = derefType(arg)
+		if firstUnresolvedCallTypeVar(arg) != nil {
+			return nil
+		}
+		typeArgs[i] = arg
+	}
+	return typeArgs
+}
+
+// validateStructInstance validates struct instantiation and returns the instance or nil if errors.
+// A non-nil base (`..base`) supplies the fields that properties leave out.
+func (c *Checker) validateStructInstance(structType *StructDef, properties []parse.StructValue, base parse.Expression, structName string, loc parse.Location, typeArgs []Type) *StructInstance {
+	c.warnDeprecatedStruct(structType, loc)
 	instance := &StructInstance{Name: structName, _type: structType}
 	if c.spans != nil {
 		for _, prop := range properties {
@@ -5182,8 +5612,10 @@ func (c *Checker) validateStructInstance(structType *StructDef, properties []par
 			}
 		}
 
-		// Create generic scope and fresh struct copy
-		genericScope = c.scope.createGenericScope(genericParams)
+		// Create a literal-local generic scope and fresh struct copy. Its
+		// variables are owned by this literal, like a call's, so one left
+		// unbound is reported rather than lowered as an abstract generic.
+		genericScope = c.createCallGenericScope(genericParams)
 		structDefCopy = copyStructWithTypeVarMap(structType, *genericScope.genericContext)
 
 		// Bind explicit type arguments (`Box<Str>{...}`) before checking
@@ -5221,6 +5653,26 @@ func (c *Checker) validateStructInstance(structType *StructDef, properties []par
 		structDefCopy = structType
 	}
 
+	// The base must be the same struct; for generic structs it also binds the
+	// type arguments its fields carry over
+	var baseValue Expression
+	if base != nil {
+		if genericScope != nil {
+			baseValue = c.checkExpr(base)
+			if baseValue != nil {
+				if err := c.unifyTypes(structDefCopy, baseValue.Type(), genericScope); err != nil {
+					c.addUnificationTypeMismatch(err, structDefCopy, baseValue.Type(), base.GetLocation())
+					baseValue = nil
+				}
+			}
+		} else {
+			baseValue = c.checkExprAs(base, structType)
+		}
+		if baseValue == nil {
+			return nil
+		}
+	}
+
 	// Check all provided properties
 	for _, property := range properties {
 		fieldName := property.Name.Name
@@ -5235,7 +5687,7 @@ func (c *Checker) validateStructInstance(structType *StructDef, properties []par
 		}
 
 		if !ok {
-			c.addUnresolvedReference(unknownStructField, fieldName, property.GetLocation())
+			c.addUnknownStructField(fieldName, fieldNames(structType), property.GetLocation())
 		} else {
 			providedFields[fieldName] = true
 
@@ -5263,8 +5715,25 @@ func (c *Checker) validateStructInstance(structType *StructDef, properties []par
 
 			// For generic structs, unify types to resolve generics
 			if genericScope != nil {
-				// Check expression without type context first (let it infer if possible)
-				checkVal := c.checkExpr(property.Value)
+				// Check expression without type context first (let it infer if
+				// possible). When explicit, contextual, or earlier field type
+				// arguments already resolve the field's type, a call may bind its
+				// generics from it and a literal takes it as its type.
+				var expectedCall Type
+				if resolved := derefType(fieldExpected); !hasGenericsInType(resolved) && !IsMaybe(resolved) {
+					expectedCall = resolved
+				}
+				var checkVal Expression
+				switch property.Value.(type) {
+				case *parse.ListLiteral, *parse.MapLiteral, *parse.AnonymousFunction:
+					if expectedCall != nil {
+						checkVal = c.checkExprAs(property.Value, expectedCall)
+					} else {
+						checkVal = c.checkExpr(property.Value)
+					}
+				default:
+					checkVal = c.checkExprWithExpectedCall(property.Value, expectedCall)
+				}
 				if checkVal == nil {
 					continue
 				}
@@ -5376,9 +5845,19 @@ func (c *Checker) validateStructInstance(structType *StructDef, properties []par
 		checkFieldsMap = structType.Fields
 	}
 
+	definition := canonicalStructDefinition(structType)
+	if decl := c.topLevelStructDeclarations[definition.Name]; decl != nil && definition.ModulePath == c.typeOwnerPath() {
+		c.resolveStructDefaults(definition, decl)
+	}
+	defaults := definition.Defaults
 	for name, t := range checkFieldsMap {
 		if _, exists := fields[name]; !exists {
-			if _, isMaybe := t.(*Maybe); !isMaybe {
+			if baseValue != nil && !providedFields[name] {
+				fieldTypes[name] = t
+			} else if value, hasDefault := defaults[name]; hasDefault && !providedFields[name] {
+				fields[name] = value
+				fieldTypes[name] = t
+			} else if _, isMaybe := t.(*Maybe); !isMaybe {
 				if !providedFields[name] {
 					missing = append(missing, name)
 				}
@@ -5392,11 +5871,18 @@ func (c *Checker) validateStructInstance(structType *StructDef, properties []par
 		}
 	}
 	if len(missing) > 0 {
-		c.addDiagnostic(missingStructFieldsDiagnostic{Fields: missing, Span: c.sourceSpan(loc)}.build())
+		diagnostic := missingStructFieldsDiagnostic{Fields: missing, Span: c.sourceSpan(loc)}.build()
+		diagnostic.Fixes = c.missingFieldsFix(missing, checkFieldsMap, properties, loc)
+		c.addDiagnostic(diagnostic)
 	}
 
+	fieldLocations := make(map[string]parse.Location, len(properties))
+	for _, property := range properties {
+		fieldLocations[property.Name.Name] = property.Value.GetLocation()
+	}
 	instance.Fields = fields
 	instance.FieldTypes = fieldTypes
+	instance.Base = baseValue
 	// Store the refined struct definition (with resolved generics) as the instance's type
 	resolvedTypeArgs := make([]Type, len(structDefCopy.TypeArgs))
 	for i, typeArg := range structDefCopy.TypeArgs {
@@ -5406,13 +5892,13 @@ func (c *Checker) validateStructInstance(structType *StructDef, properties []par
 	if len(genericParams) == 0 {
 		genericParams = nil
 	}
-	definition := canonicalStructDefinition(structType)
 	if len(genericParams) > 0 {
 		instance._type = newStructApplication(definition, resolvedTypeArgs)
 	} else {
 		instance._type = definition
 	}
 	instance.StructType = instance._type
+	c.recordGenericBinding(instance, genericBindingSite{location: loc, fields: fieldLocations})
 	return instance
 }
 
@@ -5424,7 +5910,7 @@ func (c *Checker) createPrimitiveMethodNode(subject Expression, methodName strin
 	case Str:
 		return c.createStrMethod(subject, methodName, args)
 	case Int:
-		return c.createIntMethod(subject, methodName)
+		return c.createIntMethod(subject, methodName, args)
 	case Byte:
 		return c.createByteMethod(subject, methodName)
 	case Rune:
@@ -5463,6 +5949,10 @@ func (c *Checker) createPrimitiveMethodNode(subject Expression, methodName strin
 		return c.createResultMethod(subject, methodName, args, fnDef)
 	}
 
+	if enum, isEnum := subject.Type().(*Enum); isEnum && methodName == "name" && enum.Methods[methodName] == nil {
+		return &EnumMethod{Subject: subject, Kind: EnumName}
+	}
+
 	// For user-defined types (structs, enums), use generic InstanceMethod
 	receiverKind := ReceiverUnknown
 	var structType *StructDef
@@ -5496,11 +5986,13 @@ func (c *Checker) createPrimitiveMethodNode(subject Expression, methodName strin
 }
 
 // checkStrStatic resolves built-in static functions on the Str type, such as
-// Str::from (build a Str from a [Byte] or [Rune] view). It returns
+// Str::from (build a Str from a [Byte] or [Rune] view) and Str::format. It returns
 // handled=false when the name is not a known Str static so the caller can
 // continue normal resolution. (#283)
 func (c *Checker) checkStrStatic(s *parse.StaticFunction) (Expression, bool) {
 	switch s.Function.Name {
+	case "format":
+		return c.checkStrFormat(s), true
 	case "from":
 		if len(s.Function.TypeArgs) > 0 {
 			c.addInvalidFunctionTypeArguments("Str::from", 0, len(s.Function.TypeArgs), false, s.GetLocation(), "Str::from does not take type arguments")
@@ -5535,10 +6027,224 @@ func (c *Checker) checkStrStatic(s *parse.StaticFunction) (Expression, bool) {
 	return nil, false
 }
 
+// checkNumberParse checks `Int::parse(text)` and `Float64::parse(text)`,
+// which return a Result with a Str error rather than converting implicitly.
+func (c *Checker) checkNumberParse(s *parse.StaticFunction) Expression {
+	target := Type(Int)
+	if s.Target.String() == "Float64" {
+		target = Float64
+	}
+	name := target.String() + "::parse"
+	if len(s.Function.TypeArgs) > 0 {
+		c.addInvalidFunctionTypeArguments(name, 0, len(s.Function.TypeArgs), false, s.GetLocation(), name+" does not take type arguments")
+		return nil
+	}
+	if len(s.Function.Args) != 1 {
+		c.addArgumentCount("1", len(s.Function.Args), s.GetLocation(), "")
+		return nil
+	}
+	text := c.checkExprAs(s.Function.Args[0].Value, Str)
+	if text == nil {
+		return nil
+	}
+	return &NumberParse{Text: text, Target: target}
+}
+
+// checkAsExpression checks `value as T`, which narrows a union value to its
+// member T, or none when it holds another member. It is a UnionMatch with a
+// T arm that wraps the value in some and a catch-all of none, so backends
+// need no changes.
+func (c *Checker) checkAsExpression(s *parse.AsExpression) Expression {
+	subject := c.checkExpr(s.Value)
+	if subject == nil {
+		return nil
+	}
+	target := c.resolveType(s.Type)
+	if target == nil {
+		return nil
+	}
+	union, ok := subject.Type().(*Union)
+	if !ok {
+		legacy := fmt.Sprintf("Cannot use 'as' on %s: only union values can be narrowed", subject.Type())
+		c.addDiagnostic(invalidConversionDiagnostic{LegacyMessage: legacy, Span: c.sourceSpan(s.Value.GetLocation()), Label: fmt.Sprintf("expected a union value, but found `%s`", subject.Type())}.build())
+		return nil
+	}
+	var member Type
+	for _, t := range union.Types {
+		if t.String() == target.String() {
+			member = t
+		}
+	}
+	if member == nil {
+		legacy := fmt.Sprintf("Type %s is not part of union %s", target, union)
+		c.addDiagnostic(invalidConversionDiagnostic{LegacyMessage: legacy, Span: c.sourceSpan(s.Type.GetLocation()), Label: fmt.Sprintf("`%s` is not a member of `%s`", target, union)}.build())
+		return nil
+	}
+	maybeType := MakeMaybe(member)
+	binding := Symbol{Name: "it", Type: member}
+	arm := &Match{
+		Pattern: &Identifier{Name: binding.Name},
+		Body:    c.nodes.blocks.Put(Block{Stmts: []Statement{{Expr: c.synthesizeMaybeSome(&Variable{binding}, maybeType)}}}),
+	}
+	return &UnionMatch{
+		Subject:         subject,
+		TypeCases:       map[string]*Match{member.String(): arm},
+		TypeCasesByType: map[Type]*Match{member: arm},
+		CatchAll:        c.nodes.blocks.Put(Block{Stmts: []Statement{{Expr: c.synthesizeMaybeNone(maybeType)}}}),
+		ResultType:      maybeType,
+	}
+}
+
 // checkScalarFrom checks a `T::from(value)` conversion into the sized/named
 // scalar `target`. The conversion is truncating like Go's `T(x)`: integer
 // targets accept an integer-like value, float targets accept a numeric value,
 // and the result is `target` (never optional). (#284)
+// enumVariantValue builds a reference to a variant named without arguments.
+// Outside of match patterns, a variant with associated data must be given
+// its payload values.
+func (c *Checker) enumVariantValue(enum *Enum, variant int, location parse.Location) Expression {
+	value := enum.Values[variant]
+	if len(value.Payload) > 0 && !c.enumPatternContext {
+		legacy := fmt.Sprintf("%s::%s requires %d payload value(s)", enum.Name, value.Name, len(value.Payload))
+		c.addArgumentCount(fmt.Sprint(len(value.Payload)), 0, location, legacy)
+		return nil
+	}
+	return &EnumVariant{
+		enum:         enum,
+		Variant:      variant,
+		EnumType:     enum,
+		Discriminant: value.Value,
+	}
+}
+
+// lookupEnum resolves target when it names a local enum (`Event`) or one
+// exported by an imported module (`events::Event`), and nil otherwise.
+func (c *Checker) lookupEnum(target parse.Expression) *Enum {
+	switch t := target.(type) {
+	case *parse.Identifier:
+		if sym, ok := c.scope.get(t.Name); ok {
+			enum, _ := sym.Type.(*Enum)
+			return enum
+		}
+	case *parse.StaticProperty:
+		modIdent, ok := t.Target.(*parse.Identifier)
+		prop, propOk := t.Property.(*parse.Identifier)
+		if ok && propOk && c.program.GoImports[modIdent.Name] == nil {
+			if mod := c.resolveModule(modIdent.Name); mod != nil {
+				enum, _ := mod.Get(prop.Name).Type.(*Enum)
+				return enum
+			}
+		}
+	}
+	return nil
+}
+
+// lookupEnumVariant finds the variant called name on the enum target names.
+// It returns a nil enum when target is not an enum or has no such variant.
+func (c *Checker) lookupEnumVariant(target parse.Expression, name string) (*Enum, int) {
+	enum := c.lookupEnum(target)
+	if enum == nil {
+		return nil, -1
+	}
+	for i := range enum.Values {
+		if enum.Values[i].Name == name {
+			return enum, i
+		}
+	}
+	return nil, -1
+}
+
+// checkEnumVariantConstruction checks `Enum::Variant(a, b)`, typing each
+// argument against the variant's declared payload.
+func (c *Checker) checkEnumVariantConstruction(s *parse.StaticFunction, enum *Enum, variant int) Expression {
+	value := enum.Values[variant]
+	name := enum.Name + "::" + value.Name
+	if targetIdent, ok := s.Target.(*parse.Identifier); ok {
+		c.recordTypeRef(targetIdent.GetLocation(), targetIdent.Name)
+	}
+	if len(s.Function.TypeArgs) > 0 {
+		c.addInvalidFunctionTypeArguments(name, 0, len(s.Function.TypeArgs), false, s.GetLocation(), name+" does not take type arguments")
+		return nil
+	}
+	if len(s.Function.Args) != len(value.Payload) {
+		legacy := fmt.Sprintf("%s requires %d payload value(s), got %d", name, len(value.Payload), len(s.Function.Args))
+		c.addArgumentCount(fmt.Sprint(len(value.Payload)), len(s.Function.Args), s.GetLocation(), legacy)
+		return nil
+	}
+	args := make([]Expression, len(value.Payload))
+	for i, arg := range s.Function.Args {
+		if arg.Name != "" {
+			c.addNamedArgumentsUnsupported("Enum variant", arg.GetLocation())
+			return nil
+		}
+		checked := c.checkExprAs(arg.Value, value.Payload[i])
+		if checked == nil {
+			return nil
+		}
+		args[i] = checked
+	}
+	return &EnumVariant{
+		enum:         enum,
+		Variant:      variant,
+		EnumType:     enum,
+		Discriminant: value.Value,
+		Args:         args,
+	}
+}
+
+// checkEnumStatic checks the statics built in to every enum:
+// `Status::values()` lists the variants in declaration order and
+// `Status::from_int(n)` finds the variant with discriminant n. An enum's own
+// static function of the same name takes precedence. handled is false when s
+// is not one of these calls.
+func (c *Checker) checkEnumStatic(s *parse.StaticFunction) (Expression, bool) {
+	if s.Function.Name != "values" && s.Function.Name != "from_int" {
+		return nil, false
+	}
+	enum := c.lookupEnum(s.Target)
+	if enum == nil {
+		return nil, false
+	}
+	if prop, ok := s.Target.(*parse.StaticProperty); ok {
+		modIdent := prop.Target.(*parse.Identifier)
+		if mod := c.resolveModule(modIdent.Name); mod != nil && !mod.Get(enum.Name+"::"+s.Function.Name).IsZero() {
+			return nil, false
+		}
+	}
+	if targetIdent, ok := s.Target.(*parse.Identifier); ok {
+		c.recordTypeRef(targetIdent.GetLocation(), targetIdent.Name)
+	}
+	name := enum.Name + "::" + s.Function.Name
+	if enum.HasPayload() {
+		c.addError(fmt.Sprintf("%s is not available on enums with associated data", name), s.GetLocation())
+		return nil, true
+	}
+	if len(s.Function.TypeArgs) > 0 {
+		c.addInvalidFunctionTypeArguments(name, 0, len(s.Function.TypeArgs), false, s.GetLocation(), name+" does not take type arguments")
+		return nil, true
+	}
+	if s.Function.Name == "values" {
+		if len(s.Function.Args) != 0 {
+			c.addArgumentCount("0", len(s.Function.Args), s.GetLocation(), "")
+			return nil, true
+		}
+		return &EnumValues{Enum: enum}, true
+	}
+	if len(s.Function.Args) != 1 {
+		c.addArgumentCount("1", len(s.Function.Args), s.GetLocation(), "")
+		return nil, true
+	}
+	if s.Function.Args[0].Name != "" {
+		c.addNamedArgumentsUnsupported("Enum::from_int", s.Function.Args[0].GetLocation())
+		return nil, true
+	}
+	value := c.checkExprAs(s.Function.Args[0].Value, Int)
+	if value == nil {
+		return nil, true
+	}
+	return &EnumFromInt{Enum: enum, Value: value}, true
+}
+
 func (c *Checker) checkScalarFrom(s *parse.StaticFunction, target Type) Expression {
 	if len(s.Function.TypeArgs) > 0 {
 		name := target.String() + "::from"
@@ -5641,6 +6347,30 @@ func (c *Checker) createStrMethod(subject Expression, methodName string, args []
 		kind = StrToStr
 	case "trim":
 		kind = StrTrim
+	case "trim_start":
+		kind = StrTrimStart
+	case "trim_end":
+		kind = StrTrimEnd
+	case "slice":
+		kind = StrSlice
+	case "index_of":
+		kind = StrIndexOf
+	case "to_upper":
+		kind = StrToUpper
+	case "to_lower":
+		kind = StrToLower
+	case "pad_left":
+		kind = StrPadLeft
+	case "pad_right":
+		kind = StrPadRight
+	case "repeat":
+		kind = StrRepeat
+	case "reverse":
+		kind = StrReverse
+	case "chars":
+		kind = StrChars
+	case "graphemes":
+		kind = StrGraphemes
 	default:
 		// Fallback for unknown methods
 		panic(fmt.Sprintf("Unknown Str method: %s", methodName))
@@ -5678,20 +6408,32 @@ func (c *Checker) createRuneMethod(subject Expression, methodName string) Expres
 	return &RuneMethod{Subject: subject, Kind: kind}
 }
 
-func (c *Checker) createIntMethod(subject Expression, methodName string) Expression {
+func (c *Checker) createIntMethod(subject Expression, methodName string, args []Expression) Expression {
 	var kind IntMethodKind
 	switch methodName {
 	case "to_str":
 		kind = IntToStr
 	case "to_f64":
 		kind = IntToF64
+	case "abs":
+		kind = IntAbs
+	case "pow":
+		kind = IntPow
+	case "clamp":
+		kind = IntClamp
+	case "to_float":
+		kind = IntToFloat
 	default:
 		panic(fmt.Sprintf("Unknown Int method: %s", methodName))
 	}
-	return &IntMethod{
+	method := &IntMethod{
 		Subject: subject,
 		Kind:    kind,
 	}
+	if len(args) > 0 {
+		method.Args = args
+	}
+	return method
 }
 
 func (c *Checker) createFloatMethod(subject Expression, methodName string) Expression {
@@ -5701,6 +6443,12 @@ func (c *Checker) createFloatMethod(subject Expression, methodName string) Expre
 		kind = FloatToStr
 	case "to_int":
 		kind = FloatToInt
+	case "round":
+		kind = FloatRound
+	case "floor":
+		kind = FloatFloor
+	case "ceil":
+		kind = FloatCeil
 	default:
 		panic(fmt.Sprintf("Unknown Float64 method: %s", methodName))
 	}
@@ -6031,17 +6779,30 @@ func (c *Checker) checkIfChain(s *parse.IfStatement) Expression {
 	if s == nil || s.Condition == nil {
 		return nil
 	}
+	if s.Let != nil {
+		return c.checkIfLet(s)
+	}
 	branches := []IfBranch{}
 	var elseBlock *Block
 	var referenceType Type
 	var referenceSpan parse.Location
 	current := s
 	for current != nil {
-		if current.Condition == nil {
-			expectedType := c.expectedExpr
-			c.expectedExpr = nil
-			block := c.checkBlockWithExpected(current.Body, nil, expectedType, false)
-			c.expectedExpr = expectedType
+		if current.Condition == nil || current.Let != nil {
+			var block *Block
+			if current.Let != nil {
+				// An `else if let` branch is the else block of the chain so far.
+				ifLet := c.checkIfLet(current)
+				if ifLet == nil {
+					return nil
+				}
+				block = c.nodes.blocks.Put(Block{Stmts: []Statement{{Expr: ifLet}}})
+			} else {
+				expectedType := c.expectedExpr
+				c.expectedExpr = nil
+				block = c.checkBlockWithExpected(current.Body, nil, expectedType, false)
+				c.expectedExpr = expectedType
+			}
 			if referenceType != nil && !block.Type().equal(referenceType) {
 				if referenceType == Void || block.Type() == Void {
 					if referenceType != Void {
@@ -6120,6 +6881,309 @@ func (c *Checker) checkIfChain(s *parse.IfStatement) Expression {
 	return &If{Branches: branches, Else: elseBlock}
 }
 
+// checkIfLet checks `if let name = value { ... } else ...`, which unwraps a
+// Maybe or Result like a two-armed match: the body sees the present or ok
+// value as name, and the else branch, if any, handles the rest.
+// checkWhileLet desugars `while let name = value { body }` into a loop that
+// evaluates value on each iteration, runs body with the unwrapped value bound
+// to name, and breaks once value is none or an error:
+//
+//	while {
+//	  match value {
+//	    name => body,
+//	    _ => break,
+//	  }
+//	}
+func (c *Checker) checkWhileLet(s *parse.WhileLoop) *WhileLoop {
+	subject := c.checkExpr(s.Condition)
+	if subject == nil {
+		return nil
+	}
+	var inner Type
+	switch subjectType := subject.Type().(type) {
+	case *Maybe:
+		inner = subjectType.of
+	case *Result:
+		inner = subjectType.Val()
+	default:
+		c.addError(fmt.Sprintf("while let expects a Maybe or Result, got %s", subject.Type()), s.Condition.GetLocation())
+		return nil
+	}
+
+	bindingMutable := c.isMutable(subject)
+	body := c.checkBlock(s.Body, c.markLoopScope(func() {
+		c.scope.add(s.Let.Name, inner, bindingMutable)
+	}))
+	if body.Type() != Void {
+		body.DiscardFinalValue = true
+	}
+
+	exit := c.nodes.blocks.Put(Block{Stmts: []Statement{{Break: true}}})
+	pattern := &Identifier{Name: s.Let.Name}
+	var match Expression
+	switch subjectType := subject.Type().(type) {
+	case *Maybe:
+		match = &OptionMatch{
+			Subject:    subject,
+			InnerType:  inner,
+			Some:       &Match{Pattern: pattern, Body: body},
+			None:       exit,
+			ResultType: Void,
+		}
+	default:
+		resultSubject := subjectType.(*Result)
+		match = &ResultMatch{
+			Subject:    subject,
+			Ok:         &Match{Pattern: pattern, Body: body},
+			Err:        &Match{Pattern: &Identifier{Name: "_"}, Body: exit},
+			OkType:     inner,
+			ErrType:    resultSubject.Err(),
+			ResultType: Void,
+		}
+	}
+	return &WhileLoop{
+		Condition: &BoolLiteral{true},
+		Body:      c.nodes.blocks.Put(Block{Stmts: []Statement{{Expr: match}}}),
+	}
+}
+
+func (c *Checker) checkIfLet(s *parse.IfStatement) Expression {
+	subject := c.checkExpr(s.Condition)
+	if subject == nil {
+		return nil
+	}
+	var inner Type
+	switch subjectType := subject.Type().(type) {
+	case *Maybe:
+		inner = subjectType.of
+	case *Result:
+		inner = subjectType.Val()
+	default:
+		c.addError(fmt.Sprintf("if let expects a Maybe or Result, got %s", subject.Type()), s.Condition.GetLocation())
+		return nil
+	}
+
+	expectedType := c.expectedExpr
+	c.expectedExpr = nil
+	bindingMutable := c.isMutable(subject)
+	body := c.checkBlockWithExpected(s.Body, func() {
+		c.scope.add(s.Let.Name, inner, bindingMutable)
+	}, expectedType, false)
+	c.expectedExpr = expectedType
+
+	elseBlock := c.nodes.blocks.Put(Block{})
+	if next, ok := s.Else.(*parse.IfStatement); ok {
+		if next.Condition == nil {
+			c.expectedExpr = nil
+			elseBlock = c.checkBlockWithExpected(next.Body, nil, expectedType, false)
+			c.expectedExpr = expectedType
+		} else {
+			chain := c.checkIfChain(next)
+			if chain == nil {
+				return nil
+			}
+			elseBlock = c.nodes.blocks.Put(Block{Stmts: []Statement{{Expr: chain}}})
+		}
+	}
+
+	resultType := body.Type()
+	if s.Else == nil || !elseBlock.Type().equal(resultType) {
+		if s.Else != nil && resultType != Void && elseBlock.Type() != Void {
+			c.addDiagnostic(branchTypeMismatchDiagnostic{
+				Expected:      resultType,
+				Actual:        elseBlock.Type(),
+				ExpectedSpan:  c.sourceSpanPtr(bodyResultLocation(s.Body, s.GetLocation())),
+				ActualSpan:    c.sourceSpan(s.Else.GetLocation()),
+				LegacyMessage: "All branches must have the same result type",
+				Title:         "Incompatible if branch types",
+			}.build())
+			return nil
+		}
+		if body.Type() != Void {
+			body.DiscardFinalValue = true
+		}
+		if elseBlock.Type() != Void {
+			elseBlock.DiscardFinalValue = true
+		}
+		resultType = Void
+	}
+
+	pattern := &Identifier{Name: s.Let.Name}
+	switch subjectType := subject.Type().(type) {
+	case *Maybe:
+		return &OptionMatch{
+			Subject:    subject,
+			InnerType:  inner,
+			Some:       &Match{Pattern: pattern, Body: body},
+			None:       elseBlock,
+			ResultType: resultType,
+		}
+	default:
+		resultSubject := subjectType.(*Result)
+		return &ResultMatch{
+			Subject:    subject,
+			Ok:         &Match{Pattern: pattern, Body: body},
+			Err:        &Match{Pattern: &Identifier{Name: "_"}, Body: elseBlock},
+			OkType:     inner,
+			ErrType:    resultSubject.Err(),
+			ResultType: resultType,
+		}
+	}
+}
+
+// checkOptionalIf checks an if chain without a final else whose value is
+// used. Its branches produce T and the chain produces T?: each branch's value
+// is present and the missing else is none. A branch that is already a Maybe
+// is not wrapped again. expected is the Maybe the context asks for, or nil.
+func (c *Checker) checkOptionalIf(s *parse.IfStatement, expected *Maybe) Expression {
+	var expectedValue Type
+	if expected != nil {
+		expectedValue = expected.of
+	}
+	checked := c.withExpectedExpr(expectedValue, func() Expression {
+		return c.checkExpr(s)
+	})
+	if checked == nil {
+		return nil
+	}
+	valueType, ok := optionalIfValueType(checked)
+	if !ok || valueType == Void {
+		if expected == nil {
+			return checked
+		}
+		c.addDiagnostic(nonExhaustiveValueIfDiagnostic{IfSpan: c.sourceSpan(s.GetLocation())}.build())
+		return nil
+	}
+	maybe := expected
+	if maybe == nil {
+		if present, isMaybe := valueType.(*Maybe); isMaybe {
+			maybe = present
+		} else {
+			maybe = MakeMaybe(valueType)
+		}
+	}
+	c.fillMissingElse(checked, maybe)
+	return checked
+}
+
+// optionalIfValueType is the type every branch of a checked if chain without
+// a final else produces, following `else if let` tails. It reports false
+// when the branches disagree.
+func optionalIfValueType(expr Expression) (Type, bool) {
+	var valueType Type
+	agree := func(t Type) bool {
+		if valueType == nil {
+			valueType = t
+		}
+		return valueType.equal(t)
+	}
+	for expr != nil {
+		var tail *Block
+		switch node := expr.(type) {
+		case *If:
+			for _, branch := range node.Branches {
+				if !agree(blockValueType(branch.Body)) {
+					return nil, false
+				}
+			}
+			tail = node.Else
+		case *OptionMatch:
+			if !agree(blockValueType(node.Some.Body)) {
+				return nil, false
+			}
+			tail = node.None
+		case *ResultMatch:
+			if !agree(blockValueType(node.Ok.Body)) {
+				return nil, false
+			}
+			tail = node.Err.Body
+		default:
+			return nil, false
+		}
+		expr = ifChainTail(tail)
+	}
+	return valueType, valueType != nil
+}
+
+// fillMissingElse makes a checked if chain without a final else produce
+// maybe, wrapping each branch's value and adding a none branch.
+func (c *Checker) fillMissingElse(expr Expression, maybe *Maybe) {
+	none := func() *Block {
+		return c.nodes.blocks.Put(Block{Stmts: []Statement{{Expr: c.synthesizeMaybeNone(maybe)}}})
+	}
+	switch node := expr.(type) {
+	case *If:
+		for _, branch := range node.Branches {
+			c.wrapBlockValue(branch.Body, maybe)
+		}
+		if tail := ifChainTail(node.Else); tail != nil {
+			node.Else.DiscardFinalValue = false
+			c.fillMissingElse(tail, maybe)
+		} else {
+			node.Else = none()
+		}
+	case *OptionMatch:
+		c.wrapBlockValue(node.Some.Body, maybe)
+		if tail := ifChainTail(node.None); tail != nil {
+			node.None.DiscardFinalValue = false
+			c.fillMissingElse(tail, maybe)
+		} else {
+			node.None = none()
+		}
+		node.ResultType = maybe
+	case *ResultMatch:
+		c.wrapBlockValue(node.Ok.Body, maybe)
+		if tail := ifChainTail(node.Err.Body); tail != nil {
+			node.Err.Body.DiscardFinalValue = false
+			c.fillMissingElse(tail, maybe)
+		} else {
+			node.Err.Body = none()
+		}
+		node.ResultType = maybe
+	}
+}
+
+// ifChainTail is the `else if` chain an if chain's else block holds, or nil
+// when the chain ends there.
+func ifChainTail(block *Block) Expression {
+	if block == nil || len(block.Stmts) != 1 {
+		return nil
+	}
+	switch tail := block.Stmts[0].Expr.(type) {
+	case *If, *OptionMatch, *ResultMatch:
+		return tail
+	default:
+		return nil
+	}
+}
+
+// blockValueType is the type of a block's final value, even when the block
+// was marked to discard it.
+func blockValueType(block *Block) Type {
+	if block == nil {
+		return Void
+	}
+	for i := len(block.Stmts) - 1; i >= 0; i-- {
+		if block.Stmts[i].Expr != nil {
+			return block.Stmts[i].Expr.Type()
+		}
+	}
+	return Void
+}
+
+// wrapBlockValue makes a block's final value a maybe, unless it already is one.
+func (c *Checker) wrapBlockValue(block *Block, maybe *Maybe) {
+	block.DiscardFinalValue = false
+	for i := len(block.Stmts) - 1; i >= 0; i-- {
+		if value := block.Stmts[i].Expr; value != nil {
+			if !value.Type().equal(maybe) {
+				block.Stmts[i].Expr = c.synthesizeMaybeSome(value, maybe)
+			}
+			return
+		}
+	}
+}
+
 func functionDefForCallableType(typ Type) (*FunctionDef, bool) {
 	typ = derefType(typ)
 	switch fn := typ.(type) {
@@ -6160,42 +7224,16 @@ func (c *Checker) checkFunctionValueCall(callee Expression, callArgs []parse.Arg
 		return nil
 	}
 
-	callTypeArgs := c.resolveCallTypeArgs(typeArgs)
-	resolvedExprs, err := c.resolveArguments(callArgs, fnDef.Parameters)
-	if err != nil {
-		c.addArgumentBindingError(err, location)
-		return nil
-	}
-
-	numOmittedArgs := 0
-	if len(resolvedExprs) < len(fnDef.Parameters) {
-		for i := len(resolvedExprs); i < len(fnDef.Parameters); i++ {
-			if !parameterOmittable(fnDef.Parameters[i]) {
-				c.addMissingArgument(fnDef.Parameters[i], location)
-				return nil
-			}
-		}
-		numOmittedArgs = len(fnDef.Parameters) - len(resolvedExprs)
-	} else if len(resolvedExprs) > len(fnDef.Parameters) {
-		c.addArgumentCount(fmt.Sprint(len(fnDef.Parameters)), len(resolvedExprs), location, "")
-		resolvedExprs = resolvedExprs[:len(fnDef.Parameters)]
-	}
-
-	fnDefCopy, genericScope, setupErr := c.setupFunctionCall(fnDef, callTypeArgs, typeArgs)
-	if setupErr != nil {
-		c.addGenericFunctionResolutionError(setupErr, location)
-		return nil
-	}
-	args, fnToUse := c.checkAndProcessArguments(fnDef, resolvedExprs, fnDefCopy, genericScope, numOmittedArgs, contextualGenericReturn(expectedReturn, callTypeArgs), location)
-	if args == nil {
+	resolved, ok := c.resolveCall(callSite{fn: fnDef, args: callArgs, typeArgs: typeArgs, location: location, expectedReturn: expectedReturn})
+	if !ok {
 		return nil
 	}
 
 	return &FunctionValueCall{
 		Callee:       callee,
-		Args:         args,
-		FunctionType: fnToUse,
-		ReturnType:   fnToUse.ReturnType,
+		Args:         resolved.args,
+		FunctionType: resolved.fn,
+		ReturnType:   resolved.fn.ReturnType,
 	}
 }
 
@@ -6207,6 +7245,9 @@ func (c *Checker) checkFunctionFieldCall(subject Expression, method parse.Functi
 	if fieldType == nil {
 		return nil, false
 	}
+	if !c.checkFieldVisible(subject.Type(), method.Name, method.GetLocation()) {
+		return nil, true
+	}
 	field := &InstanceProperty{
 		Subject:  subject,
 		Property: method.Name,
@@ -6263,6 +7304,12 @@ func (c *Checker) checkExprWithExpectedCall(expr parse.Expression, expectedRetur
 	result := c.checkExprInner(expr, expectedReturn)
 	if result != nil {
 		c.recordExprSpan(expr, result)
+		// a name bound to Any is reported where the value was produced
+		if _, isAny := result.Type().(*anyType); isAny && c.forbidDynamic != nil {
+			if _, isName := expr.(*parse.Identifier); !isName {
+				c.checkDynamicAllowed(expr.GetLocation(), "this value has type `Any`")
+			}
+		}
 	}
 	return result
 }
@@ -6278,9 +7325,13 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 		c.discardExprContext = previousDiscard
 	}()
 
+	if pipe, ok := expr.(*parse.PipeExpression); ok {
+		expr = pipe.Desugar()
+	}
+
 	switch s := (expr).(type) {
 	case *parse.StrLiteral:
-		return &StrLiteral{s.Value}
+		return c.nodes.strs.Put(StrLiteral{s.Value})
 	case *parse.RuneLiteral:
 		runes := []rune(s.Value)
 		if len(runes) != 1 || !utf8.ValidRune(runes[0]) {
@@ -6295,7 +7346,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 	case *parse.NumLiteral:
 		{
 			stripped := strings.ReplaceAll(s.Value, "_", "")
-			if strings.Contains(stripped, ".") {
+			if isFloatLiteralText(stripped) {
 				value, err := strconv.ParseFloat(stripped, 64)
 				if err != nil {
 					legacy := fmt.Sprintf("Invalid float: %s", s.Value)
@@ -6304,17 +7355,17 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				}
 				return &FloatLiteral{Value: value}
 			}
-			value64, err := strconv.ParseInt(stripped, 0, 64)
+			value64, err := parseIntLiteral(stripped)
 			if err != nil {
 				legacy := fmt.Sprintf("Invalid int: %s", s.Value)
 				c.addDiagnostic(invalidLiteralDiagnostic{LegacyMessage: legacy, Span: c.sourceSpan(s.GetLocation()), Label: "this is not a valid integer literal"}.build())
-				return &IntLiteral{0}
+				return &IntLiteral{Value: 0}
 			}
 			if !c.intLiteralFitsType(value64, Int) {
 				legacy := fmt.Sprintf("Integer literal %s overflows Int", s.Value)
 				c.addDiagnostic(numericLiteralOverflowDiagnostic{LegacyMessage: legacy, Span: c.sourceSpan(s.GetLocation()), Target: Int}.build())
 			}
-			return &IntLiteral{int(value64)}
+			return &IntLiteral{Value: int(value64)}
 		}
 	case *parse.InterpolatedStr:
 		{
@@ -6323,48 +7374,10 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				cx := c.checkExpr(s.Chunks[i])
 				if cx == nil {
 					// skip bad expressions
-					chunks[i] = &StrLiteral{}
-					continue
-				}
-
-				// A foreign named scalar stringifies as its underlying primitive
-				// (e.g. term::EventTitle interpolates as its Str value).
-				if prim := foreignScalarPrimitive(cx.Type()); prim != nil {
-					cx = &ForeignScalarConvert{Value: cx, Target: prim}
-				}
-
-				// If chunk is a string, use it directly
-				if cx.Type() == Str {
-					chunks[i] = cx
-					continue
-				}
-
-				if toStr, ok := cx.Type().get("to_str").(*FunctionDef); ok && toStr.ReturnType == Str && len(toStr.Parameters) == 0 {
-					chunks[i] = c.createPrimitiveMethodNode(cx, toStr.Name, []Expression{}, toStr, nil, parse.Location{})
+					chunks[i] = c.nodes.strs.Put(StrLiteral{})
 					continue
 				}
-
-				if strMod := c.findModuleByPath("ard/string"); strMod != nil {
-					toStringTrait := strMod.Get("ToString").Type.(*Trait)
-					if !cx.Type().hasTrait(toStringTrait) {
-						c.addTypeMismatch(toStringTrait, cx.Type(), s.Chunks[i].GetLocation())
-						// a non-stringable chunk stays empty
-						chunks[i] = &StrLiteral{}
-						continue
-					}
-
-					// For non-string types that satisfy ToString trait, wrap with to_str() call
-					toStrMethod := toStringTrait.methods[0]
-					methodNode := c.createPrimitiveMethodNode(cx, toStrMethod.Name, []Expression{}, &toStrMethod, nil, parse.Location{})
-					chunks[i] = methodNode
-					continue
-				}
-
-				c.addDiagnostic(stringInterpolationMismatchDiagnostic{
-					Actual: cx.Type(),
-					Span:   c.sourceSpan(s.Chunks[i].GetLocation()),
-				}.build())
-				chunks[i] = &StrLiteral{}
+				chunks[i] = c.stringify(cx, s.Chunks[i].GetLocation())
 			}
 			return &TemplateStr{chunks}
 		}
@@ -6374,15 +7387,17 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				return nil
 			}
 			c.recordSymbolUse(s, sym, nil)
-			return &Variable{*sym}
+			c.markUsed(sym)
+			if sym.constant != nil {
+				return sym.constant
+			}
+			return c.nodes.variables.Put(Variable{*sym})
 		}
-		c.addDiagnostic(undefinedNameDiagnostic{
-			Kind: undefinedVariable,
-			Name: s.Name,
-			Span: c.sourceSpan(s.GetLocation()),
-		}.build())
+		c.addUndefinedName(undefinedVariable, s.Name, s.GetLocation())
 		c.halted = true
 		return nil
+	case *parse.AsExpression:
+		return c.checkAsExpression(s)
 	case *parse.FunctionValueCall:
 		{
 			callee := c.checkExpr(s.Callee)
@@ -6413,16 +7428,18 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				}
 			}
 
+			// inspect is a builtin unless a function of that name is in scope
+			if _, defined := c.scope.get(s.Name); !defined && s.Name == "inspect" {
+				return c.checkInspect(s)
+			}
+
 			// Find the function in the scope
 			fnSym, got := c.scope.get(s.Name)
 			if !got {
-				c.addDiagnostic(undefinedNameDiagnostic{
-					Kind: undefinedFunction,
-					Name: s.Name,
-					Span: c.sourceSpan(s.GetLocation()),
-				}.build())
+				c.addUndefinedName(undefinedFunction, s.Name, s.GetLocation())
 				return nil
 			}
+			c.markUsed(fnSym)
 
 			// Cast to FunctionDef
 			var fnDef *FunctionDef
@@ -6437,49 +7454,17 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			}
 			c.recordCallAttempt(s, s.Name, fnDef)
 
-			callTypeArgs := c.resolveCallTypeArgs(s.TypeArgs)
-
-			// Resolve named arguments to positional arguments (for expressions only)
-			resolvedExprs, err := c.resolveArguments(s.Args, fnDef.Parameters)
-			if err != nil {
-				c.addArgumentBindingError(err, s.GetLocation())
-				return nil
-			}
-
-			// Check argument count after resolving
-			numOmittedArgs := 0
-			if len(resolvedExprs) < len(fnDef.Parameters) {
-				// Find first non-nullable parameter that's missing
-				for i := len(resolvedExprs); i < len(fnDef.Parameters); i++ {
-					if !parameterOmittable(fnDef.Parameters[i]) {
-						c.addMissingArgument(fnDef.Parameters[i], s.GetLocation())
-						return nil
-					}
-				}
-				numOmittedArgs = len(fnDef.Parameters) - len(resolvedExprs)
-			} else if len(resolvedExprs) > len(fnDef.Parameters) {
-				c.addArgumentCount(fmt.Sprint(len(fnDef.Parameters)), len(resolvedExprs), s.GetLocation(), "")
-				resolvedExprs = resolvedExprs[:len(fnDef.Parameters)]
-			}
-
-			fnDefCopy, genericScope, setupErr := c.setupFunctionCall(fnDef, callTypeArgs, s.TypeArgs)
-			if setupErr != nil {
-				c.addGenericFunctionResolutionError(setupErr, s.GetLocation())
-				return nil
-			}
-
-			// Check and process arguments (handles both generics and mutability)
-			args, fnToUse := c.checkAndProcessArguments(fnDef, resolvedExprs, fnDefCopy, genericScope, numOmittedArgs, contextualGenericReturn(expectedReturn, callTypeArgs), s.GetLocation())
-			if args == nil {
+			resolved, ok := c.resolveCall(callSite{fn: fnDef, args: s.Args, typeArgs: s.TypeArgs, location: s.GetLocation(), expectedReturn: expectedReturn})
+			if !ok {
 				return nil
 			}
 
 			call := &FunctionCall{
 				Name:       s.Name,
-				Args:       args,
-				TypeArgs:   callTypeArgs,
-				fn:         fnToUse,
-				ReturnType: fnToUse.ReturnType,
+				Args:       resolved.args,
+				TypeArgs:   resolved.typeArgs,
+				fn:         resolved.fn,
+				ReturnType: resolved.fn.ReturnType,
 			}
 			return call
 		}
@@ -6505,6 +7490,10 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				}
 			}
 
+			if bound, ok := c.checkBoundMethod(s, subj); ok {
+				return bound
+			}
+
 			propType := subj.Type().get(s.Property.Name)
 			foreignPointerReceiver := false
 			if propType == nil {
@@ -6541,14 +7530,19 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					}
 				}
 				c.addDiagnostic(undefinedMemberDiagnostic{
-					Kind:     undefinedField,
-					Receiver: fmt.Sprint(subj),
-					Member:   s.Property.Name,
-					Span:     c.sourceSpan(s.Property.GetLocation()),
+					Kind:       undefinedField,
+					Receiver:   fmt.Sprint(subj),
+					Member:     s.Property.Name,
+					Span:       c.sourceSpan(s.Property.GetLocation()),
+					Suggestion: suggestName(s.Property.Name, fieldNames(subj.Type())),
 				}.build())
 				return nil
 			}
 
+			if !c.checkFieldVisible(subj.Type(), s.Property.Name, s.Property.GetLocation()) {
+				return nil
+			}
+
 			if fnDef, ok := propType.(*FunctionDef); ok {
 				if foreign, ok := subj.Type().(*ForeignType); ok {
 					pointer := foreign.Pointer || foreignPointerReceiver
@@ -6644,6 +7638,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					}
 				}
 				if sig == nil {
+					if c.checkPrivateMethod(subj.Type(), s.Method.Name, s.Method.GetLocation()) {
+						return nil
+					}
 					c.addDiagnostic(undefinedMemberDiagnostic{
 						Kind:     undefinedMethod,
 						Receiver: fmt.Sprint(subj),
@@ -6671,70 +7668,18 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				return nil
 			}
 
-			// Resolve named and positional arguments to match parameters
-			resolvedExprs, err := c.resolveArguments(s.Method.Args, fnDef.Parameters)
-			if err != nil {
-				c.addArgumentBindingError(err, s.GetLocation())
-				return nil
-			}
-
-			// Check argument count and validate omitted arguments
-			numOmittedArgs := 0
-			if len(resolvedExprs) < len(fnDef.Parameters) {
-				// Find first non-nullable parameter that's missing
-				for i := len(resolvedExprs); i < len(fnDef.Parameters); i++ {
-					if !parameterOmittable(fnDef.Parameters[i]) {
-						c.addMissingArgument(fnDef.Parameters[i], s.GetLocation())
-						return nil
-					}
-				}
-				numOmittedArgs = len(fnDef.Parameters) - len(resolvedExprs)
-			} else if len(resolvedExprs) > len(fnDef.Parameters) && !(len(fnDef.Parameters) > 0 && fnDef.Parameters[len(fnDef.Parameters)-1].Variadic) {
-				c.addArgumentCount(fmt.Sprint(len(fnDef.Parameters)), len(resolvedExprs), s.GetLocation(), "")
-				resolvedExprs = resolvedExprs[:len(fnDef.Parameters)]
-			}
-
-			// Receiver, explicit, and argument evidence share one call-local scope.
-			// A method's signature may reference receiver-owned generics; only
-			// explicitly marked method outputs are independently call-owned.
-			genericParams := append([]string(nil), callGenericParamsForFunction(fnDef)...)
-			var receiverBindings map[string]Type
+			receiver := &callReceiver{explicitParams: c.explicitMethodGenericParams(fnDef, subj.Type()), location: s.Target.GetLocation()}
 			if structType, isStruct := subj.Type().(*StructDef); isStruct {
 				if originalDef := c.structDefinition(structType); originalDef != nil && originalDef.hasGenerics() {
-					genericParams = appendUniqueStrings(genericParams, originalDef.GenericParams...)
-					receiverBindings = c.extractGenericBindingsFromSpecializedStruct(originalDef, structType)
-				}
-			}
-			callTypeArgs := c.resolveCallTypeArgs(s.Method.TypeArgs)
-			methodGenericParams := c.explicitMethodGenericParams(fnDef, subj.Type())
-			fnDefCopy, genericScope, setupErr := c.setupFunctionCallWithBindings(
-				fnDef,
-				genericParams,
-				receiverBindings,
-				methodGenericParams,
-				callTypeArgs,
-				s.Method.TypeArgs,
-			)
-			if setupErr != nil {
-				c.addGenericFunctionResolutionError(setupErr, s.GetLocation())
-				return nil
-			}
-			if genericScope != nil {
-				for name := range receiverBindings {
-					if _, exists := genericScope.genericOrigins[name]; !exists {
-						genericScope.genericOrigins[name] = genericBindingOrigin{Span: c.sourceSpan(s.Target.GetLocation()), Kind: "receiver type"}
-					}
+					receiver.genericParams = originalDef.GenericParams
+					receiver.bindings = c.extractGenericBindingsFromSpecializedStruct(originalDef, structType)
 				}
 			}
-
-			fnDef = expandFunctionDefForRepeatedVariadic(fnDef, len(resolvedExprs))
-			fnDefCopy = expandFunctionDefForRepeatedVariadic(fnDefCopy, len(resolvedExprs))
-
-			// Check and process arguments (handles both generics and mutability)
-			args, fnToUse := c.checkAndProcessArguments(fnDef, resolvedExprs, fnDefCopy, genericScope, numOmittedArgs, contextualGenericReturn(expectedReturn, callTypeArgs), s.GetLocation())
-			if args == nil {
+			resolved, ok := c.resolveCall(callSite{fn: fnDef, args: s.Method.Args, typeArgs: s.Method.TypeArgs, location: s.GetLocation(), expectedReturn: expectedReturn, receiver: receiver})
+			if !ok {
 				return nil
 			}
+			args, fnToUse, callTypeArgs := resolved.args, resolved.fn, resolved.typeArgs
 			if foreign, ok := subj.Type().(*ForeignType); ok {
 				if foreign.MapKey != nil && foreign.MapValue != nil && isMapMethodName(s.Method.Name) {
 					return c.createPrimitiveMethodNode(subj, s.Method.Name, args, fnToUse, callTypeArgs, s.Method.GetLocation())
@@ -6766,11 +7711,14 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				return nil
 			}
 			if s.Operator == parse.Minus {
+				if def, ok := operatorStruct(value.Type()); ok {
+					return c.operatorMethodCall(def, "neg", value)
+				}
 				if !isSignedArithmeticLike(value.Type()) {
 					c.addDiagnostic(invalidUnaryOperatorDiagnostic{Operator: "-", Operand: value.Type(), Span: c.sourceSpan(s.Operand.GetLocation()), LegacyMessage: "Only signed numbers can be negated with '-'"}.build())
 					return nil
 				}
-				return &Negation{value}
+				return c.checkConstantOverflow(&Negation{value}, s.GetLocation(), value)
 			}
 
 			if value.Type() != Bool {
@@ -6788,13 +7736,16 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					if !left.Type().equal(right.Type()) {
 						c.addInvalidArithmetic("+", left, right, s.Left.GetLocation(), s.Right.GetLocation(), "Cannot add different types", false)
 						return nil
 					}
 					if isArithmeticIntegerLike(left.Type()) {
-						return &IntAddition{left, right}
+						return c.checkConstantOverflow(&IntAddition{left, right}, s.GetLocation(), left, right)
 					}
 					if isArithmeticFloatLike(left.Type()) {
 						return &FloatAddition{left, right}
@@ -6811,13 +7762,16 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					if !left.Type().equal(right.Type()) {
 						c.addInvalidArithmetic("-", left, right, s.Left.GetLocation(), s.Right.GetLocation(), "Cannot subtract different types", false)
 						return nil
 					}
 					if isArithmeticIntegerLike(left.Type()) {
-						return &IntSubtraction{left, right}
+						return c.checkConstantOverflow(&IntSubtraction{left, right}, s.GetLocation(), left, right)
 					}
 					if isArithmeticFloatLike(left.Type()) {
 						return &FloatSubtraction{left, right}
@@ -6831,13 +7785,16 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					if !left.Type().equal(right.Type()) {
 						c.addInvalidArithmetic("*", left, right, s.Left.GetLocation(), s.Right.GetLocation(), "Cannot multiply different types", false)
 						return nil
 					}
 					if isArithmeticIntegerLike(left.Type()) {
-						return &IntMultiplication{left, right}
+						return c.checkConstantOverflow(&IntMultiplication{left, right}, s.GetLocation(), left, right)
 					}
 					if isArithmeticFloatLike(left.Type()) {
 						return &FloatMultiplication{left, right}
@@ -6851,13 +7808,16 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					if !left.Type().equal(right.Type()) {
 						c.addInvalidArithmetic("/", left, right, s.Left.GetLocation(), s.Right.GetLocation(), "Cannot divide different types", false)
 						return nil
 					}
 					if isArithmeticIntegerLike(left.Type()) {
-						return &IntDivision{left, right}
+						return c.checkConstantOverflow(&IntDivision{left, right}, s.GetLocation(), left, right)
 					}
 					if isArithmeticFloatLike(left.Type()) {
 						return &FloatDivision{left, right}
@@ -6871,6 +7831,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					if !left.Type().equal(right.Type()) {
 						c.addInvalidArithmetic("%", left, right, s.Left.GetLocation(), s.Right.GetLocation(), "Cannot modulo different types", false)
@@ -6888,6 +7851,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					// Allow Enum vs Int comparisons
 					if c.areTypesComparable(left.Type(), right.Type()) {
@@ -6907,6 +7873,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					// Allow Enum vs Int comparisons
 					if c.areTypesComparable(left.Type(), right.Type()) {
@@ -6926,6 +7895,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					// Allow Enum vs Int comparisons
 					if c.areTypesComparable(left.Type(), right.Type()) {
@@ -6945,6 +7917,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					// Allow Enum vs Int comparisons
 					if c.areTypesComparable(left.Type(), right.Type()) {
@@ -6969,6 +7944,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					if left == nil || right == nil {
 						return nil
 					}
+					if dispatched, ok := c.dispatchOperator(s.Operator, left, right); ok {
+						return dispatched
+					}
 
 					leftMaybe, leftIsMaybe := left.Type().(*Maybe)
 					rightMaybe, rightIsMaybe := right.Type().(*Maybe)
@@ -7055,9 +8033,10 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 
 	case *parse.ChainedComparison:
 		{
-			// Validate that only relative operators are used (not == or !=)
+			// `==` links are rejected: `a == b == c` reads as comparing a Bool
+			// result rather than as a conjunction. `!=` links are allowed.
 			for _, op := range s.Operators {
-				if op == parse.Equal || op == parse.NotEqual {
+				if op == parse.Equal {
 					c.addDiagnostic(invalidChainedComparisonDiagnostic{Span: c.sourceSpan(s.GetLocation())}.build())
 					return nil
 				}
@@ -7068,7 +8047,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			var result Expression
 
 			// Build the first comparison: operands[0] op operators[0] operands[1]
-			firstComparison := c.buildComparison(s.Operands[0], s.Operators[0], s.Operands[1])
+			firstComparison := c.buildChainLink(s, 0)
 			if firstComparison == nil {
 				return nil
 			}
@@ -7076,7 +8055,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 
 			// Build remaining comparisons and AND them together
 			for i := 1; i < len(s.Operators); i++ {
-				nextComparison := c.buildComparison(s.Operands[i], s.Operators[i], s.Operands[i+1])
+				nextComparison := c.buildChainLink(s, i)
 				if nextComparison == nil {
 					return nil
 				}
@@ -7101,12 +8080,37 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				}
 			}
 
+			// `Int::parse(text)` and `Float64::parse(text)`
+			if targetIdent, ok := s.Target.(*parse.Identifier); ok && s.Function.Name == "parse" {
+				if targetIdent.Name == "Int" || targetIdent.Name == "Float64" {
+					return c.checkNumberParse(s)
+				}
+			}
+
 			// `Int64::from(x)`, `Uint32::from(x)`, ... truncating conversion into a
-			// bare sized scalar. (#284)
+			// bare sized scalar. (#284) `Int::from(x)` and `Float64::from(x)` bring
+			// sized values back, and `Rune::from(x)`/`Byte::from(x)` are the same
+			// conversion into a code point or byte; ard/rune's from_int is the
+			// checked form.
 			if targetIdent, ok := s.Target.(*parse.Identifier); ok && s.Function.Name == "from" {
 				if scalar := scalarTypeByName(targetIdent.Name); scalar != nil {
 					return c.checkScalarFrom(s, scalar)
 				}
+				switch targetIdent.Name {
+				case "Int":
+					return c.checkScalarFrom(s, Int)
+				case "Float64":
+					return c.checkScalarFrom(s, Float64)
+				case "Rune":
+					return c.checkScalarFrom(s, Rune)
+				case "Byte":
+					return c.checkScalarFrom(s, Byte)
+				}
+			}
+
+			// `Event::Click(x, y)` builds a variant with associated data
+			if enum, variant := c.lookupEnumVariant(s.Target, s.Function.Name); enum != nil {
+				return c.checkEnumVariantConstruction(s, enum, variant)
 			}
 
 			// Handle local functions
@@ -7120,48 +8124,25 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					}
 				}
 				fnDef := sym.Type.(*FunctionDef)
-				callTypeArgs := c.resolveCallTypeArgs(s.Function.TypeArgs)
-
-				// Resolve named and positional arguments to match parameters
-				resolvedExprs, err := c.resolveArguments(s.Function.Args, fnDef.Parameters)
-				if err != nil {
-					c.addArgumentBindingError(err, s.GetLocation())
-					return nil
-				}
-
-				numOmittedArgs := 0
-				if len(resolvedExprs) < len(fnDef.Parameters) {
-					for i := len(resolvedExprs); i < len(fnDef.Parameters); i++ {
-						if !parameterOmittable(fnDef.Parameters[i]) {
-							c.addMissingArgument(fnDef.Parameters[i], s.GetLocation())
-							return nil
-						}
-					}
-					numOmittedArgs = len(fnDef.Parameters) - len(resolvedExprs)
-				} else if len(resolvedExprs) > len(fnDef.Parameters) {
-					c.addArgumentCount(fmt.Sprint(len(fnDef.Parameters)), len(resolvedExprs), s.GetLocation(), "")
-					resolvedExprs = resolvedExprs[:len(fnDef.Parameters)]
-				}
-
-				fnDefCopy, genericScope, setupErr := c.setupFunctionCall(fnDef, callTypeArgs, s.Function.TypeArgs)
-				if setupErr != nil {
-					c.addGenericFunctionResolutionError(setupErr, s.GetLocation())
-					return nil
-				}
-				args, fnToUse := c.checkAndProcessArguments(fnDef, resolvedExprs, fnDefCopy, genericScope, numOmittedArgs, contextualGenericReturn(expectedReturn, callTypeArgs), s.GetLocation())
-				if args == nil {
+				resolved, ok := c.resolveCall(callSite{fn: fnDef, args: s.Function.Args, typeArgs: s.Function.TypeArgs, location: s.GetLocation(), expectedReturn: expectedReturn})
+				if !ok {
 					return nil
 				}
 
 				return &FunctionCall{
 					Name:       absolutePath,
-					Args:       args,
-					TypeArgs:   callTypeArgs,
-					fn:         fnToUse,
-					ReturnType: fnToUse.ReturnType,
+					Args:       resolved.args,
+					TypeArgs:   resolved.typeArgs,
+					fn:         resolved.fn,
+					ReturnType: resolved.fn.ReturnType,
 				}
 			}
 
+			// `Status::values()` and `Status::from_int(n)`
+			if expr, handled := c.checkEnumStatic(s); handled {
+				return expr
+			}
+
 			// find the function in a module or Go package namespace
 			modName, name := c.destructurePath(s)
 			if mod := c.resolveModule(modName); mod != nil && mod.Path() == "ard/unsafe" {
@@ -7294,9 +8275,10 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			sym := mod.Get(name)
 			if sym.IsZero() {
 				targetName := s.Target.String()
-				c.addUnresolvedReference(undefinedQualifiedMember, fmt.Sprintf("%s::%s", targetName, s.Function.Name), s.GetLocation())
+				c.addUndefinedModuleMember(mod, name, fmt.Sprintf("%s::%s", targetName, s.Function.Name), s.GetLocation())
 				return nil
 			}
+			mod = memberOrigin(mod, name)
 
 			// Handle both regular functions and external functions
 			var ok bool
@@ -7313,40 +8295,8 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				c.addNonCallable(fmt.Sprintf("%s::%s", targetName, s.Function.Name), s.GetLocation(), nil, nonCallableSuffix)
 				return nil
 			}
-			callTypeArgs := c.resolveCallTypeArgs(s.Function.TypeArgs)
-
-			// Resolve named and positional arguments to match parameters
-			resolvedExprs, err := c.resolveArguments(s.Function.Args, fnDef.Parameters)
-			if err != nil {
-				c.addArgumentBindingError(err, s.GetLocation())
-				return nil
-			}
-
-			// Check argument count and validate omitted arguments
-			numOmittedArgs := 0
-			if len(resolvedExprs) < len(fnDef.Parameters) {
-				// Find first non-nullable parameter that's missing
-				for i := len(resolvedExprs); i < len(fnDef.Parameters); i++ {
-					if !parameterOmittable(fnDef.Parameters[i]) {
-						c.addMissingArgument(fnDef.Parameters[i], s.GetLocation())
-						return nil
-					}
-				}
-				numOmittedArgs = len(fnDef.Parameters) - len(resolvedExprs)
-			} else if len(resolvedExprs) > len(fnDef.Parameters) {
-				c.addArgumentCount(fmt.Sprint(len(fnDef.Parameters)), len(resolvedExprs), s.GetLocation(), "")
-				resolvedExprs = resolvedExprs[:len(fnDef.Parameters)]
-			}
-
-			fnDefCopy, genericScope, setupErr := c.setupFunctionCall(fnDef, callTypeArgs, s.Function.TypeArgs)
-			if setupErr != nil {
-				c.addGenericFunctionResolutionError(setupErr, s.GetLocation())
-				return nil
-			}
-
-			// Check and process arguments (handles both generics and mutability)
-			args, fnToUse := c.checkAndProcessArguments(fnDef, resolvedExprs, fnDefCopy, genericScope, numOmittedArgs, contextualGenericReturn(expectedReturn, callTypeArgs), s.GetLocation())
-			if args == nil {
+			resolved, ok := c.resolveCall(callSite{fn: fnDef, args: s.Function.Args, typeArgs: s.Function.TypeArgs, location: s.GetLocation(), expectedReturn: expectedReturn})
+			if !ok {
 				return nil
 			}
 
@@ -7355,10 +8305,10 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			callName := name
 			call := &FunctionCall{
 				Name:       callName,
-				Args:       args,
-				TypeArgs:   callTypeArgs,
-				fn:         fnToUse,
-				ReturnType: fnToUse.ReturnType,
+				Args:       resolved.args,
+				TypeArgs:   resolved.typeArgs,
+				fn:         resolved.fn,
+				ReturnType: resolved.fn.ReturnType,
 			}
 			c.recordTarget(s, call, SpanTarget{Kind: TargetFunction, Module: mod.Path(), Symbol: name})
 			return &ModuleFunctionCall{
@@ -7428,14 +8378,22 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			return fn
 		}
 	case *parse.StaticFunctionDeclaration:
-		if c.spans != nil {
-			if target, ok := s.Path.Target.(*parse.Identifier); ok {
-				if sym, found := c.scope.get(target.Name); found && isNominalType(sym.Type) {
-					c.recordTypeRef(target.GetLocation(), target.Name)
-				}
+		// A static function belongs to the type it is declared on, which is
+		// also what `Self` refers to within it.
+		var owner Type
+		if target, ok := s.Path.Target.(*parse.Identifier); ok {
+			if sym, found := c.scope.get(target.Name); found && isNominalType(sym.Type) {
+				owner = sym.Type
+				c.recordTypeRef(target.GetLocation(), target.Name)
 			}
 		}
-		fn := c.checkFunction(&s.FunctionDeclaration, nil)
+		if owner == nil {
+			c.addUnresolvedReference(undefinedType, s.Path.Target.String(), s.Path.Target.GetLocation())
+		}
+		var fn *FunctionDef
+		c.withSelfType(owner, func() {
+			fn = c.checkFunction(&s.FunctionDeclaration, nil)
+		})
 		if fn != nil {
 			fn.Name = s.Path.String()
 			c.scope.add(fn.Name, fn, false)
@@ -7519,7 +8477,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				}
 				body := c.checkMatchArmBlock(arm.Body, func() {
 					if arm.Binding != nil {
-						c.scope.add(arm.Binding.Name, &Maybe{elem}, false)
+						c.scope.add(arm.Binding.Name, &Maybe{of: elem}, false)
 					}
 				})
 				sel.Arms = append(sel.Arms, SelectArm{Kind: SelectArmRecv, Channel: channel, Binding: binding, ElemType: elem, Body: body})
@@ -7573,6 +8531,11 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			return c.checkForeignTypeMatch(s, subject, allowMixedVoid)
 		}
 
+		s, ok := c.flattenNestedPatterns(s, subject.Type())
+		if !ok {
+			return nil
+		}
+
 		// For Maybe types, generate an OptionMatch
 		if maybeType, ok := subject.Type().(*Maybe); ok {
 			var patternIdent *Identifier
@@ -7607,12 +8570,12 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 
 			// Ensure we have both some and none cases
 			if someBody == nil {
-				c.addNonExhaustiveMatch("Match on a Maybe type must include a binding case", s.GetLocation(), "add a binding case for the present value")
+				c.addNonExhaustiveMatch("Match on a Maybe type must include a binding case", s.GetLocation(), "add a binding case for the present value", "value")
 				return nil
 			}
 
 			if noneBody == nil {
-				c.addNonExhaustiveMatch("Match on a Maybe type must include a wildcard (_) case", s.GetLocation(), "add a wildcard `_` case for the absent value")
+				c.addNonExhaustiveMatch("Match on a Maybe type must include a wildcard (_) case", s.GetLocation(), "add a wildcard `_` case for the absent value", "_")
 				return nil
 			}
 
@@ -7638,14 +8601,12 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 		if enumType, ok := subject.Type().(*Enum); ok {
 			// Map to track which discriminant values we've seen. Imported Go enum-like
 			// constants may have multiple exported aliases for the same value.
-			seenDiscriminants := make(map[int]struct {
-				Name string
-				Span SourceSpan
-			})
+			seenDiscriminants := enumPatternCases{}
 			// Track whether we've seen a catch-all case
 			var catchAllSpan *SourceSpan
 			// Cases in the match statement mapped to enum variants
 			cases := make([]*Block, len(enumType.Values))
+			var bindings [][]string
 			var catchAllBody *Block
 
 			// Process the cases
@@ -7665,52 +8626,85 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					}
 				}
 
-				// Handle enum variant case - the pattern should be a static property reference like Enum::Variant
-				if staticProp, ok := matchCase.Pattern.(*parse.StaticProperty); ok {
-					// Resolve the pattern using existing expression resolution logic
-					patternExpr := c.checkExpr(staticProp)
-					if patternExpr == nil {
-						continue // Error already reported by checkExpr
+				if or, ok := matchCase.Pattern.(*parse.OrPattern); ok {
+					var variants []int
+					valid := true
+					for _, pattern := range or.Patterns {
+						staticProp, ok := pattern.(*parse.StaticProperty)
+						if !ok {
+							c.addInvalidMatchPattern("Patterns joined with | must be enum variants without bindings", pattern.GetLocation(), "expected an enum variant")
+							valid = false
+							continue
+						}
+						variant, ok := c.enumPatternVariant(enumType, staticProp, seenDiscriminants)
+						valid = ok && valid
+						variants = append(variants, variant)
 					}
-
-					// Check if the pattern resolves to an enum variant
-					enumVariant, ok := patternExpr.(*EnumVariant)
-					if !ok {
-						c.addInvalidMatchPattern("Pattern in enum match must be an enum variant", staticProp.GetLocation(), "this does not resolve to an enum variant")
+					if !valid {
 						continue
 					}
+					body := c.checkMatchArmBlock(matchCase.Body, nil)
+					for _, variant := range variants {
+						cases[variant] = body
+					}
+					continue
+				}
 
-					// Verify that the variant's enum matches the subject's enum
-					if !enumVariant.enum.equal(enumType) {
-						legacy := fmt.Sprintf("Cannot match %s variant against %s enum", enumVariant.enum.Name, enumType.Name)
-						c.addInvalidMatchPattern(legacy, staticProp.GetLocation(), fmt.Sprintf("this variant belongs to `%s`, not `%s`", enumVariant.enum.Name, enumType.Name))
+				// Handle enum variant case - the pattern should be a static property
+				// reference like Enum::Variant, or Enum::Variant(a, b) to bind the
+				// variant's payload
+				staticProp, ok := matchCase.Pattern.(*parse.StaticProperty)
+				var payloadPatterns []parse.Argument
+				call, bindsPayload := matchCase.Pattern.(*parse.StaticFunction)
+				if bindsPayload {
+					staticProp = &parse.StaticProperty{
+						Location: call.Location,
+						Target:   call.Target,
+						Property: &parse.Identifier{Location: call.Function.Location, Name: call.Function.Name},
+					}
+					payloadPatterns = call.Function.Args
+					ok = true
+				}
+				if ok {
+					variantIndex, ok := c.enumPatternVariant(enumType, staticProp, seenDiscriminants)
+					if !ok {
 						continue
 					}
+					current := fmt.Sprintf("%s::%s", enumType.Name, enumType.Values[variantIndex].Name)
 
-					// Get the variant name and index
-					variantName := enumType.Values[enumVariant.Variant].Name
-					variantIndex := int(enumVariant.Variant)
-
-					// Check for duplicate cases by value, not just by name. This lets Go
-					// enum-like constants import aliases while preserving closed enum
-					// exhaustiveness over distinct values.
-					discriminant := enumType.Values[enumVariant.Variant].Value
-					current := fmt.Sprintf("%s::%s", enumType.Name, variantName)
-					if previous, found := seenDiscriminants[discriminant]; found {
-						legacy := fmt.Sprintf("Duplicate case: %s", current)
-						if previous.Name != current {
-							legacy = fmt.Sprintf("Duplicate case: %s has same value as %s", current, previous.Name)
+					payload := enumType.Values[variantIndex].Payload
+					var names []string
+					if bindsPayload {
+						if len(payloadPatterns) != len(payload) {
+							legacy := fmt.Sprintf("%s binds %d payload value(s), but the variant has %d", current, len(payloadPatterns), len(payload))
+							c.addInvalidMatchPattern(legacy, matchCase.Pattern.GetLocation(), fmt.Sprintf("`%s` carries %d value(s)", current, len(payload)))
+							continue
 						}
-						c.addDuplicateMatchArm(Error, legacy, staticProp.GetLocation(), &previous.Span)
-						continue
+						names = make([]string, len(payloadPatterns))
+						for i, pattern := range payloadPatterns {
+							id, isIdent := pattern.Value.(*parse.Identifier)
+							if !isIdent || pattern.Name != "" {
+								c.addInvalidMatchPattern("Payload patterns must be names or _", pattern.GetLocation(), "expected a name to bind or `_`")
+								return nil
+							}
+							if id.Name != "_" {
+								names[i] = id.Name
+							}
+						}
+						if bindings == nil {
+							bindings = make([][]string, len(enumType.Values))
+						}
+						bindings[variantIndex] = names
 					}
-					seenDiscriminants[discriminant] = struct {
-						Name string
-						Span SourceSpan
-					}{Name: current, Span: c.sourceSpan(staticProp.GetLocation())}
 
 					// Check the body for this case
-					body := c.checkMatchArmBlock(matchCase.Body, nil)
+					body := c.checkMatchArmBlock(matchCase.Body, func() {
+						for i, name := range names {
+							if name != "" {
+								c.scope.add(name, payload[i], false)
+							}
+						}
+					})
 					cases[variantIndex] = body
 				} else {
 					c.addInvalidMatchPattern("Pattern in enum match must be an enum variant or wildcard", matchCase.Pattern.GetLocation(), "expected an enum variant or `_`")
@@ -7724,7 +8718,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			if catchAllSpan == nil {
 				if enumType.Open {
 					legacy := fmt.Sprintf("Open enum-like Go type %s requires a catch-all (_) match case", enumType.Name)
-					c.addNonExhaustiveMatch(legacy, s.GetLocation(), "add a catch-all `_` case for this open enum-like type")
+					c.addNonExhaustiveMatch(legacy, s.GetLocation(), "add a catch-all `_` case for this open enum-like type", "_")
 				} else {
 					missingValues := map[int]bool{}
 					for i, value := range enumType.Values {
@@ -7733,7 +8727,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 						}
 						if cases[i] == nil && !missingValues[value.Value] {
 							legacy := fmt.Sprintf("Incomplete match: missing case for '%s::%s'", enumType.Name, value.Name)
-							c.addNonExhaustiveMatch(legacy, s.GetLocation(), fmt.Sprintf("add a case for `%s::%s`", enumType.Name, value.Name))
+							c.addNonExhaustiveMatch(legacy, s.GetLocation(), fmt.Sprintf("add a case for `%s::%s`", enumType.Name, value.Name), c.enumArmPattern(enumType, value)...)
 							missingValues[value.Value] = true
 						}
 					}
@@ -7772,6 +8766,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 				Cases:               cases,
 				CatchAll:            catchAllBody,
 				DiscriminantToIndex: discriminantToIndex,
+				Bindings:            bindings,
 				ResultType:          enumResultType,
 			}
 
@@ -7828,9 +8823,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			// Check exhaustiveness
 			if trueSpan == nil || falseSpan == nil {
 				if trueSpan == nil {
-					c.addNonExhaustiveMatch("Incomplete match: Missing case for 'true'", s.GetLocation(), "add a case for `true`")
+					c.addNonExhaustiveMatch("Incomplete match: Missing case for 'true'", s.GetLocation(), "add a case for `true`", "true")
 				} else {
-					c.addNonExhaustiveMatch("Incomplete match: Missing case for 'false'", s.GetLocation(), "add a case for `false`")
+					c.addNonExhaustiveMatch("Incomplete match: Missing case for 'false'", s.GetLocation(), "add a case for `false`", "false")
 				}
 				return nil
 			}
@@ -8011,7 +9006,17 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					}
 				case *parse.FunctionCall: // use FunctionCall node as aliasing variable
 					{
-						varName := p.Args[0].Value.(*parse.Identifier).Name
+						if len(p.Args) != 1 {
+							c.addInvalidMatchPattern("Result patterns take one binding, like ok(value)", p.GetLocation(), "expected `ok(name)` or `err(name)`")
+							continue
+						}
+						binding, ok := p.Args[0].Value.(*parse.Identifier)
+						if !ok {
+							legacy := fmt.Sprintf("Invalid pattern in %s(...): expected a name or a nested ok, err, or some pattern", p.Name)
+							c.addInvalidMatchPattern(legacy, p.Args[0].Value.GetLocation(), "expected a binding or nested pattern")
+							continue
+						}
+						varName := binding.Name
 						switch p.Name {
 						case "ok":
 							varName := p.Args[0].Value.(*parse.Identifier).Name
@@ -8036,11 +9041,11 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			}
 
 			if okCase == nil {
-				c.addNonExhaustiveMatch("Missing ok case", s.GetLocation(), "add an `ok` case")
+				c.addNonExhaustiveMatch("Missing ok case", s.GetLocation(), "add an `ok` case", "ok")
 				return nil
 			}
 			if errCase == nil {
-				c.addNonExhaustiveMatch("Missing err case", s.GetLocation(), "add an `err` case")
+				c.addNonExhaustiveMatch("Missing err case", s.GetLocation(), "add an `err` case", "err")
 				return nil
 			}
 
@@ -8082,19 +9087,24 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					continue
 				}
 
-				literal, ok := matchCase.Pattern.(*parse.StrLiteral)
-				if !ok {
-					c.addInvalidMatchPattern("Pattern in Str match must be a string literal or '_'", matchCase.Pattern.GetLocation(), "expected a string literal or `_`")
-					return nil
-				}
-				if _, exists := strCases[literal.Value]; exists {
-					original := strCaseSpans[literal.Value]
-					c.addDuplicateMatchArm(Error, fmt.Sprintf("Duplicate case: %q", literal.Value), matchCase.Pattern.GetLocation(), &original)
-					return nil
+				var values []string
+				for _, pattern := range orPatternAlternatives(matchCase.Pattern) {
+					literal, ok := pattern.(*parse.StrLiteral)
+					if !ok {
+						c.addInvalidMatchPattern("Pattern in Str match must be a string literal or '_'", pattern.GetLocation(), "expected a string literal or `_`")
+						return nil
+					}
+					if original, exists := strCaseSpans[literal.Value]; exists {
+						c.addDuplicateMatchArm(Error, fmt.Sprintf("Duplicate case: %q", literal.Value), pattern.GetLocation(), &original)
+						return nil
+					}
+					strCaseSpans[literal.Value] = c.sourceSpan(pattern.GetLocation())
+					values = append(values, literal.Value)
 				}
 				caseBlock := c.checkMatchArmBlock(matchCase.Body, nil)
-				strCases[literal.Value] = caseBlock
-				strCaseSpans[literal.Value] = c.sourceSpan(matchCase.Pattern.GetLocation())
+				for _, value := range values {
+					strCases[value] = caseBlock
+				}
 				var mergeOK bool
 				strResultType, mergeOK = mergeMatchResultType(c, strResultType, caseBlock.Type(), matchCase.Pattern.GetLocation(), allowMixedVoid)
 				if !mergeOK {
@@ -8103,7 +9113,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			}
 
 			if catchAll == nil {
-				c.addNonExhaustiveMatch("Incomplete match: missing catch-all case for Str match", s.GetLocation(), "add a catch-all `_` case")
+				c.addNonExhaustiveMatch("Incomplete match: missing catch-all case for Str match", s.GetLocation(), "add a catch-all `_` case", "_")
 				return nil
 			}
 
@@ -8160,7 +9170,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			}
 
 			if catchAll == nil {
-				c.addNonExhaustiveMatch("Incomplete match: missing catch-all case for Rune match", s.GetLocation(), "add a catch-all `_` case")
+				c.addNonExhaustiveMatch("Incomplete match: missing catch-all case for Rune match", s.GetLocation(), "add a catch-all `_` case", "_")
 			}
 
 			return &IntMatch{
@@ -8175,57 +9185,30 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 		// Check for Int matching
 		if subject.Type() == Int {
 			intCases := make(map[int]*Block)
+			intCaseSpans := make(map[int]SourceSpan)
 			rangeCases := make(map[IntRange]*Block)
 			var catchAll *Block
 			var intResultType Type
+			// Arms are matched in order, so track what earlier arms cover to
+			// find arms that can never match
+			coverage := intMatchCoverage{}
 
 			for _, matchCase := range s.Cases {
+				if coverage.catchAll != nil {
+					c.addUnreachableMatchArm("Unreachable case: an earlier arm matches every value", matchCase.Pattern.GetLocation(), coverage.catchAll, "this arm can never match")
+					c.checkMatchArmBlock(matchCase.Body, nil)
+					continue
+				}
 				// Check if it's the default case (_)
 				if id, ok := matchCase.Pattern.(*parse.Identifier); ok && id.Name == "_" {
+					span := c.sourceSpan(matchCase.Pattern.GetLocation())
+					coverage.catchAll = &span
 					catchAll = c.checkMatchArmBlock(matchCase.Body, nil)
 					var ok bool
 					intResultType, ok = mergeMatchResultType(c, intResultType, catchAll.Type(), matchCase.Pattern.GetLocation(), allowMixedVoid)
 					if !ok {
 						return nil
 					}
-				} else if literal, ok := matchCase.Pattern.(*parse.NumLiteral); ok {
-					// Convert string to int
-					value, err := strconv.Atoi(literal.Value)
-					if err != nil {
-						legacy := fmt.Sprintf("Invalid integer literal: %s", literal.Value)
-						c.addInvalidMatchPattern(legacy, matchCase.Pattern.GetLocation(), "this is not a valid integer pattern")
-						return nil
-					}
-					caseBlock := c.checkMatchArmBlock(matchCase.Body, nil)
-					intCases[value] = caseBlock
-					var ok bool
-					intResultType, ok = mergeMatchResultType(c, intResultType, caseBlock.Type(), matchCase.Pattern.GetLocation(), allowMixedVoid)
-					if !ok {
-						return nil
-					}
-				} else if unaryExpr, ok := matchCase.Pattern.(*parse.UnaryExpression); ok && unaryExpr.Operator == parse.Minus {
-					// Handle negative numbers like -1, -5, etc.
-					if literal, ok := unaryExpr.Operand.(*parse.NumLiteral); ok {
-						// Convert string to int and negate
-						value, err := strconv.Atoi(literal.Value)
-						if err != nil {
-							legacy := fmt.Sprintf("Invalid integer literal: %s", literal.Value)
-							c.addInvalidMatchPattern(legacy, literal.GetLocation(), "this is not a valid integer pattern")
-							return nil
-						}
-						negativeValue := -value
-						caseBlock := c.checkMatchArmBlock(matchCase.Body, nil)
-						intCases[negativeValue] = caseBlock
-						var ok bool
-						intResultType, ok = mergeMatchResultType(c, intResultType, caseBlock.Type(), matchCase.Pattern.GetLocation(), allowMixedVoid)
-						if !ok {
-							return nil
-						}
-					} else {
-						legacy := fmt.Sprintf("Invalid pattern for Int match: %T", matchCase.Pattern)
-						c.addInvalidMatchPattern(legacy, matchCase.Pattern.GetLocation(), "expected an integer literal, range, enum variant, or `_`")
-						return nil
-					}
 				} else if rangeExpr, ok := matchCase.Pattern.(*parse.RangeExpression); ok {
 					// Handle range pattern like 1..10 or -10..5
 					startValue, startErr := c.extractIntFromPattern(rangeExpr.Start)
@@ -8247,46 +9230,52 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 						return nil
 					}
 
+					intRange := IntRange{Start: startValue, End: endValue}
+					reachable := c.checkRangeCoverage(&coverage, intRange, matchCase.Pattern.GetLocation())
 					caseBlock := c.checkMatchArmBlock(matchCase.Body, nil)
-					rangeCases[IntRange{Start: startValue, End: endValue}] = caseBlock
+					if reachable {
+						rangeCases[intRange] = caseBlock
+					}
 					var ok bool
 					intResultType, ok = mergeMatchResultType(c, intResultType, caseBlock.Type(), matchCase.Pattern.GetLocation(), allowMixedVoid)
 					if !ok {
 						return nil
 					}
-				} else if staticProp, ok := matchCase.Pattern.(*parse.StaticProperty); ok {
-					// Handle enum variant pattern like Status::active
-					patternExpr := c.checkExpr(staticProp)
-					if patternExpr == nil {
-						continue // Error already reported by checkExpr
-					}
-
-					// Check if the pattern resolves to an enum variant
-					enumVariant, ok := patternExpr.(*EnumVariant)
-					if !ok {
-						c.addInvalidMatchPattern("Pattern in Int match must be an integer literal, range, or enum variant", staticProp.GetLocation(), "this does not resolve to an enum variant")
-						continue
+				} else {
+					// A single value, or several joined with | that share the arm
+					var values []int
+					for _, pattern := range orPatternAlternatives(matchCase.Pattern) {
+						value, ok := c.intPatternValue(pattern)
+						if !ok {
+							return nil
+						}
+						if original, exists := intCaseSpans[value]; exists {
+							c.addDuplicateMatchArm(Error, fmt.Sprintf("Duplicate case: %d", value), pattern.GetLocation(), &original)
+							return nil
+						}
+						intCaseSpans[value] = c.sourceSpan(pattern.GetLocation())
+						if earlier, covered := coverage.rangeContaining(value); covered {
+							legacy := fmt.Sprintf("Unreachable case: %d is already covered by %d..%d", value, earlier.Start, earlier.End)
+							c.addUnreachableMatchArm(legacy, pattern.GetLocation(), &earlier.span, "an earlier range matches this value")
+							continue
+						}
+						values = append(values, value)
 					}
-
-					// Extract the integer value from the enum variant's actual value (supports custom enum values)
-					value := enumVariant.enum.Values[enumVariant.Variant].Value
 					caseBlock := c.checkMatchArmBlock(matchCase.Body, nil)
-					intCases[value] = caseBlock
-					var mergeOK bool
-					intResultType, mergeOK = mergeMatchResultType(c, intResultType, caseBlock.Type(), matchCase.Pattern.GetLocation(), allowMixedVoid)
-					if !mergeOK {
+					for _, value := range values {
+						intCases[value] = caseBlock
+					}
+					var ok bool
+					intResultType, ok = mergeMatchResultType(c, intResultType, caseBlock.Type(), matchCase.Pattern.GetLocation(), allowMixedVoid)
+					if !ok {
 						return nil
 					}
-				} else {
-					legacy := fmt.Sprintf("Invalid pattern for Int match: %T", matchCase.Pattern)
-					c.addInvalidMatchPattern(legacy, matchCase.Pattern.GetLocation(), "expected an integer literal, range, enum variant, or `_`")
-					return nil
 				}
 			}
 
 			// Validate that there is a catch-all case for Int match
 			if catchAll == nil {
-				c.addNonExhaustiveMatch("Incomplete match: missing catch-all case for Int match", s.GetLocation(), "add a catch-all `_` case")
+				c.addNonExhaustiveMatch("Incomplete match: missing catch-all case for Int match", s.GetLocation(), "add a catch-all `_` case", "_")
 			}
 
 			return &IntMatch{
@@ -8298,6 +9287,10 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			}
 		}
 
+		if def, ok := subject.Type().(*StructDef); ok {
+			return c.checkStructMatch(s, subject, def, allowMixedVoid)
+		}
+
 		legacy := fmt.Sprintf("Cannot match on %s", subject.Type())
 		c.addDiagnostic(invalidMatchSubjectDiagnostic{Actual: subject.Type(), Span: c.sourceSpan(s.Subject.GetLocation()), LegacyMessage: legacy}.build())
 		return nil
@@ -8353,7 +9346,7 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 
 		// Require catch-all case for conditional match to guarantee a return value
 		if catchAll == nil {
-			c.addNonExhaustiveMatch("Conditional match must include a catch-all (_) case", s.GetLocation(), "add a catch-all `_` case")
+			c.addNonExhaustiveMatch("Conditional match must include a catch-all (_) case", s.GetLocation(), "add a catch-all `_` case", "_")
 		}
 
 		return &ConditionalMatch{
@@ -8418,6 +9411,10 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 							c.addUnresolvedReference(undefinedGoType, fmt.Sprintf("%s::%s", id.Name, prop.Name.Name), prop.Name.GetLocation())
 							return nil
 						}
+						if prop.Base != nil {
+							c.addError("Go struct literals cannot use '..' to copy from a base", prop.Base.GetLocation())
+							return nil
+						}
 						instance := c.validateForeignStructInstance(foreign, prop.TypeArgs, prop.Properties, prop.GetLocation())
 						if instance == nil {
 							return nil
@@ -8439,18 +9436,22 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 						// Look up the struct symbol directly from the module
 						sym := mod.Get(prop.Name.Name)
 						if sym.IsZero() {
-							c.addUnresolvedReference(undefinedQualifiedMember, fmt.Sprintf("%s::%s", id.Name, prop.Name.Name), prop.Name.GetLocation())
+							c.addUndefinedModuleMember(mod, prop.Name.Name, fmt.Sprintf("%s::%s", id.Name, prop.Name.Name), prop.Name.GetLocation())
 							return nil
 						}
+						mod = memberOrigin(mod, prop.Name.Name)
 
 						structType, ok := sym.Type.(*StructDef)
 						if !ok {
 							c.addUnresolvedReference(notAStruct, fmt.Sprintf("%s::%s", id.Name, prop.Name.Name), prop.Name.GetLocation())
 							return nil
 						}
+						if !c.checkStructLiteralVisible(structType, fmt.Sprintf("%s::%s", id.Name, prop.Name.Name), prop.Name.GetLocation()) {
+							return nil
+						}
 
 						// Use helper function for validation
-						instance := c.validateStructInstance(structType, prop.Properties, prop.Name.Name, prop.GetLocation(), typeArgs)
+						instance := c.validateStructInstance(structType, prop.Properties, prop.Base, prop.Name.Name, prop.GetLocation(), typeArgs)
 						if instance == nil {
 							return nil
 						}
@@ -8468,12 +9469,17 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					case *parse.Identifier:
 						sym := mod.Get(prop.Name)
 						if sym.IsZero() {
-							c.addUnresolvedReference(undefinedQualifiedMember, fmt.Sprintf("%s::%s", id.Name, prop.Name), prop.GetLocation())
+							c.addUndefinedModuleMember(mod, prop.Name, fmt.Sprintf("%s::%s", id.Name, prop.Name), prop.GetLocation())
 							return nil
 						}
+						mod = memberOrigin(mod, prop.Name)
 						if c.rejectUnspecializedGenericFunctionValue(sym.Type, prop.GetLocation()) {
 							return nil
 						}
+						if sym.constant != nil {
+							c.recordTarget(prop, sym.constant, SpanTarget{Kind: TargetValue, Module: mod.Path(), Symbol: prop.Name})
+							return sym.constant
+						}
 						node := &ModuleSymbol{Module: mod.Path(), Symbol: Symbol{Name: prop.Name, Type: sym.Type}}
 						c.recordTarget(prop, node, SpanTarget{Kind: TargetValue, Module: mod.Path(), Symbol: prop.Name})
 						return node
@@ -8498,11 +9504,18 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 							if c.rejectUnspecializedGenericFunctionValue(fnDef, propIdent.GetLocation()) {
 								return nil
 							}
-							return &Variable{Symbol{Name: staticFnName, Type: fnDef}}
+							return c.nodes.variables.Put(Variable{Symbol{Name: staticFnName, Type: fnDef}})
 						}
 					}
 				}
 
+				// Check if it's an instance method referenced as a function
+				if propIdent, ok := s.Property.(*parse.Identifier); ok && !enumHasVariant(sym.Type, propIdent.Name) {
+					if method, ok := c.checkMethodReference(sym.Type, id.Name, propIdent); ok {
+						return method
+					}
+				}
+
 				// Check if it's an enum variant
 				enum, ok := sym.Type.(*Enum)
 				if !ok {
@@ -8518,16 +9531,14 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 					}
 				}
 				if variant == -1 {
+					if s.Property.(*parse.Identifier).Name == "count" && !c.enumPatternContext {
+						return &IntLiteral{Value: len(enum.Values)}
+					}
 					c.addUnresolvedReference(undefinedEnumVariant, fmt.Sprintf("%s::%s", sym.Name, s.Property.(*parse.Identifier).Name), id.GetLocation())
 					return nil
 				}
 
-				return &EnumVariant{
-					enum:         enum,
-					Variant:      variant,
-					EnumType:     enum,
-					Discriminant: enum.Values[variant].Value,
-				}
+				return c.enumVariantValue(enum, variant, s.GetLocation())
 			}
 			// Handle nested static properties like http::Method::Get
 			if _, ok := s.Target.(*parse.StaticProperty); ok {
@@ -8548,16 +9559,14 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 						}
 					}
 					if variant == -1 {
+						if s.Property.(*parse.Identifier).Name == "count" && !c.enumPatternContext {
+							return &IntLiteral{Value: len(enum.Values)}
+						}
 						c.addUnresolvedReference(undefinedEnumVariant, fmt.Sprintf("%s::%s", enum.Name, s.Property.(*parse.Identifier).Name), s.Property.GetLocation())
 						return nil
 					}
 
-					return &EnumVariant{
-						enum:         enum,
-						Variant:      variant,
-						EnumType:     enum,
-						Discriminant: enum.Values[variant].Value,
-					}
+					return c.enumVariantValue(enum, variant, s.GetLocation())
 				}
 
 				c.addUnresolvedReference(invalidStaticMember, fmt.Sprintf("%s::%s", s.Target, s.Property), s.Property.GetLocation())
@@ -8566,28 +9575,9 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 			panic(fmt.Errorf("Unexpected static property target: %T", s.Target))
 		}
 	case *parse.StructInstance:
-		typeArgs, argsOk := c.resolveStructTypeArgs(s)
-		if !argsOk {
-			return nil
-		}
-		name := s.Name.Name
-		sym, ok := c.scope.get(name)
-		if !ok {
-			c.addUnresolvedReference(undefinedStructType, name, s.GetLocation())
-			return nil
-		}
-
-		structType, ok := sym.Type.(*StructDef)
-		if !ok {
-			c.addUnresolvedReference(notAStruct, name, s.GetLocation())
-			return nil
-		}
-		if !strings.Contains(name, "::") {
-			c.recordTypeRef(s.Name.GetLocation(), name)
-		}
-
-		// Use helper function for validation
-		return c.validateStructInstance(structType, s.Properties, name, s.GetLocation(), typeArgs)
+		// A generic literal without type arguments may take them from the
+		// expected type.
+		return c.checkStructInstance(s, expectedReturn)
 	case *parse.Try:
 		{
 			if c.deferredWorkDepth > 0 {
@@ -8703,8 +9693,8 @@ func (c *Checker) checkExprInner(expr parse.Expression, expectedReturn Type) Exp
 						}
 					}
 
-					// Error types must match for direct propagation
-					if !_type.err.equal(fnReturnResult.err) {
+					// Errors propagate unchanged, or widen into a union error type
+					if !_type.err.equal(fnReturnResult.err) && !propagatedErrorWidens(fnReturnResult.err, _type.err) {
 						legacyMessage := fmt.Sprintf("Error type mismatch: Expected %s, got %s", fnReturnResult.err.String(), _type.err.String())
 						c.addTypeMismatchWithLegacy(fnReturnResult.err, _type.err, legacyMessage, s.Expression.GetLocation())
 						// Return a try op with the unwrapped type to avoid cascading errors
@@ -8859,27 +9849,44 @@ func (c *Checker) parseRuneLiteralValue(literal *parse.RuneLiteral) (rune, bool)
 func (c *Checker) extractIntFromPattern(expr parse.Expression) (int, error) {
 	switch e := expr.(type) {
 	case *parse.NumLiteral:
-		return strconv.Atoi(e.Value)
+		value, err := parseIntLiteral(e.Value)
+		return int(value), err
 	case *parse.UnaryExpression:
 		if e.Operator == parse.Minus {
 			if literal, ok := e.Operand.(*parse.NumLiteral); ok {
-				value, err := strconv.Atoi(literal.Value)
+				value, err := parseIntLiteral(literal.Value)
 				if err != nil {
 					return 0, err
 				}
-				return -value, nil
+				return -int(value), nil
 			}
 		}
 		return 0, fmt.Errorf("unsupported unary expression in pattern")
+	case *parse.Identifier:
+		if sym, ok := c.scope.get(e.Name); ok {
+			if literal, ok := sym.constant.(*IntLiteral); ok {
+				c.recordSymbolUse(e, sym, nil)
+				c.markUsed(sym)
+				return literal.Value, nil
+			}
+		}
+		return 0, fmt.Errorf("%s is not an Int constant", e.Name)
+	case *parse.StaticProperty:
+		if literal, ok := c.checkExpr(e).(*IntLiteral); ok {
+			return literal.Value, nil
+		}
+		return 0, fmt.Errorf("%s is not an Int constant", e)
 	default:
-		return 0, fmt.Errorf("pattern must be an integer literal or negative integer")
+		return 0, fmt.Errorf("pattern must be an integer literal, negative integer, or Int constant")
 	}
 }
 
 // isEnum checks if a type is an Enum
+// isEnum reports whether t is an integer-backed enum, which compares and
+// orders like Int.
 func (c *Checker) isEnum(t Type) bool {
-	_, ok := t.(*Enum)
-	return ok
+	enum, ok := t.(*Enum)
+	return ok && !enum.HasPayload()
 }
 
 // areTypesComparable checks if two types can be compared together
@@ -8902,12 +9909,30 @@ func (c *Checker) areTypesComparable(left, right Type) bool {
 
 // use this when we know what the expr's Type should be
 func bindInferredTypeVars(expected Type, actual Type) {
+	// a closed type has no type variables to bind
+	if isClosedType(expected) {
+		return
+	}
+	bindInferredTypeVarsSeen(expected, actual, map[[2]Type]bool{})
+}
+
+// bindInferredTypeVarsSeen skips pairs it has already walked, so a struct
+// whose fields refer back to it, such as a tree of nodes, terminates.
+func bindInferredTypeVarsSeen(expected Type, actual Type, seen map[[2]Type]bool) {
 	if expected == nil || actual == nil {
 		return
 	}
 
 	expected = derefType(expected)
+	if isClosedType(expected) {
+		return
+	}
 	actual = derefType(actual)
+	pair := [2]Type{expected, actual}
+	if seen[pair] {
+		return
+	}
+	seen[pair] = true
 
 	switch exp := expected.(type) {
 	case *TypeVar:
@@ -8919,36 +9944,36 @@ func bindInferredTypeVars(expected Type, actual Type) {
 			exp.bound = true
 			return
 		}
-		bindInferredTypeVars(exp.actual, actual)
+		bindInferredTypeVarsSeen(exp.actual, actual, seen)
 	case *Maybe:
 		if act, ok := actual.(*Maybe); ok {
-			bindInferredTypeVars(exp.Of(), act.Of())
+			bindInferredTypeVarsSeen(exp.Of(), act.Of(), seen)
 		}
 	case *Result:
 		if act, ok := actual.(*Result); ok {
-			bindInferredTypeVars(exp.Val(), act.Val())
-			bindInferredTypeVars(exp.Err(), act.Err())
+			bindInferredTypeVarsSeen(exp.Val(), act.Val(), seen)
+			bindInferredTypeVarsSeen(exp.Err(), act.Err(), seen)
 		}
 	case *List:
 		if act, ok := actual.(*List); ok {
-			bindInferredTypeVars(exp.Of(), act.Of())
+			bindInferredTypeVarsSeen(exp.Of(), act.Of(), seen)
 		}
 	case *Chan:
 		if act, ok := actual.(*Chan); ok {
-			bindInferredTypeVars(exp.Of(), act.Of())
+			bindInferredTypeVarsSeen(exp.Of(), act.Of(), seen)
 		}
 	case *Receiver:
 		if act, ok := actual.(*Receiver); ok {
-			bindInferredTypeVars(exp.Of(), act.Of())
+			bindInferredTypeVarsSeen(exp.Of(), act.Of(), seen)
 		}
 	case *Sender:
 		if act, ok := actual.(*Sender); ok {
-			bindInferredTypeVars(exp.Of(), act.Of())
+			bindInferredTypeVarsSeen(exp.Of(), act.Of(), seen)
 		}
 	case *Map:
 		if act, ok := actual.(*Map); ok {
-			bindInferredTypeVars(exp.Key(), act.Key())
-			bindInferredTypeVars(exp.Value(), act.Value())
+			bindInferredTypeVarsSeen(exp.Key(), act.Key(), seen)
+			bindInferredTypeVarsSeen(exp.Value(), act.Value(), seen)
 		}
 	case *StructDef:
 		if act, ok := actual.(*StructDef); ok && exp.Name == act.Name && !namedTypeOwnersDiffer(exp.ModulePath, act.ModulePath) {
@@ -8957,11 +9982,11 @@ func bindInferredTypeVars(expected Type, actual Type) {
 				limit = len(act.TypeArgs)
 			}
 			for i := 0; i < limit; i++ {
-				bindInferredTypeVars(exp.TypeArgs[i], act.TypeArgs[i])
+				bindInferredTypeVarsSeen(exp.TypeArgs[i], act.TypeArgs[i], seen)
 			}
 			for fieldName, expectedField := range exp.Fields {
 				if actualField, ok := act.Fields[fieldName]; ok {
-					bindInferredTypeVars(expectedField, actualField)
+					bindInferredTypeVarsSeen(expectedField, actualField, seen)
 				}
 			}
 		}
@@ -8972,9 +9997,9 @@ func bindInferredTypeVars(expected Type, actual Type) {
 				limit = len(act.Parameters)
 			}
 			for i := 0; i < limit; i++ {
-				bindInferredTypeVars(exp.Parameters[i].Type, act.Parameters[i].Type)
+				bindInferredTypeVarsSeen(exp.Parameters[i].Type, act.Parameters[i].Type, seen)
 			}
-			bindInferredTypeVars(exp.ReturnType, act.ReturnType)
+			bindInferredTypeVarsSeen(exp.ReturnType, act.ReturnType, seen)
 		}
 	}
 }
@@ -9004,7 +10029,7 @@ func (c *Checker) checkSignedNumericLiteralAs(num *parse.NumLiteral, expected Ty
 	if negative {
 		literalText = "-" + literalText
 	}
-	if strings.Contains(num.Value, ".") {
+	if isFloatLiteralText(num.Value) {
 		clean := strings.ReplaceAll(literalText, "_", "")
 		value, err := strconv.ParseFloat(clean, 64)
 		if err != nil {
@@ -9031,17 +10056,20 @@ func (c *Checker) checkSignedNumericLiteralAs(num *parse.NumLiteral, expected Ty
 	}
 	clean := strings.ReplaceAll(literalText, "_", "")
 	if isUnsignedScalar(literalType) {
-		value := new(big.Int)
-		if _, ok := value.SetString(clean, 0); !ok || value.Sign() < 0 || !c.uintLiteralFitsType(value, literalType) {
+		value, ok := parseBigIntLiteral(clean)
+		if !ok || value.Sign() < 0 || !c.uintLiteralFitsType(value, literalType) {
 			legacy := fmt.Sprintf("Integer literal %s overflows %s", literalText, expected)
 			c.addDiagnostic(numericLiteralOverflowDiagnostic{LegacyMessage: legacy, Span: c.sourceSpan(literalLocation), Target: expected}.build())
+			if !ok {
+				value = new(big.Int)
+			}
 		}
-		return &TypedIntLiteral{Value: int(value.Int64()), Text: clean, Typed: expected}
+		return &TypedIntLiteral{Value: int(value.Int64()), Text: value.String(), Typed: expected}
 	}
 	if literalType == Float32 || literalType == Float64 {
 		return nil
 	}
-	value64, err := strconv.ParseInt(clean, 0, 64)
+	value64, err := parseIntLiteral(clean)
 	if err != nil {
 		legacy := fmt.Sprintf("Invalid int: %s", literalText)
 		c.addDiagnostic(invalidLiteralDiagnostic{LegacyMessage: legacy, Span: c.sourceSpan(literalLocation), Label: "this is not a valid integer literal"}.build())
@@ -9051,7 +10079,7 @@ func (c *Checker) checkSignedNumericLiteralAs(num *parse.NumLiteral, expected Ty
 		legacy := fmt.Sprintf("Integer literal %s overflows %s", literalText, expected)
 		c.addDiagnostic(numericLiteralOverflowDiagnostic{LegacyMessage: legacy, Span: c.sourceSpan(literalLocation), Target: expected}.build())
 		if isIntegerScalar(literalType) {
-			return &TypedIntLiteral{Value: int(value64), Text: clean, Typed: expected}
+			return &TypedIntLiteral{Value: int(value64), Text: strconv.FormatInt(value64, 10), Typed: expected}
 		}
 		return nil
 	}
@@ -9059,7 +10087,7 @@ func (c *Checker) checkSignedNumericLiteralAs(num *parse.NumLiteral, expected Ty
 		return &IntLiteral{Value: int(value64)}
 	}
 	if isIntegerScalar(literalType) {
-		return &TypedIntLiteral{Value: int(value64), Text: clean, Typed: expected}
+		return &TypedIntLiteral{Value: int(value64), Text: strconv.FormatInt(value64, 10), Typed: expected}
 	}
 	return nil
 }
@@ -9424,6 +10452,9 @@ func discardingFunctionTypes(expected Type, actual Type) (*FunctionDef, *Functio
 }
 
 func (c *Checker) checkExprAsInner(expr parse.Expression, expectedType Type, expectation *typeExpectation, argumentParameter *Parameter) Expression {
+	if pipe, ok := expr.(*parse.PipeExpression); ok {
+		expr = pipe.Desugar()
+	}
 	if literal := c.checkNumericLiteralAs(expr, expectedType); literal != nil {
 		return literal
 	}
@@ -9437,9 +10468,13 @@ func (c *Checker) checkExprAsInner(expr parse.Expression, expectedType Type, exp
 			return c.checkExpr(s)
 		})
 	case *parse.IfStatement:
-		// A value is expected, so the chain must be exhaustive: without an
-		// else there is a path that produces nothing (issue #267).
+		// A value is expected, so a chain without an else must produce a
+		// Maybe: the missing path is none. Any other expected type needs the
+		// chain to be exhaustive (issue #267).
 		if expectedType != nil && expectedType != Void && !ifChainHasElse(s) {
+			if maybe, ok := expectedType.(*Maybe); ok {
+				return c.checkOptionalIf(s, maybe)
+			}
 			c.addDiagnostic(nonExhaustiveValueIfDiagnostic{IfSpan: c.sourceSpan(s.GetLocation())}.build())
 			return nil
 		}
@@ -9571,7 +10606,7 @@ func (c *Checker) checkExprAsInner(expr parse.Expression, expectedType Type, exp
 
 			sym := mod.Get(s.Function.Name)
 			if sym.IsZero() {
-				c.addUnresolvedReference(undefinedQualifiedMember, fmt.Sprintf("%s::%s", moduleName, s.Function.Name), s.GetLocation())
+				c.addUndefinedModuleMember(mod, s.Function.Name, fmt.Sprintf("%s::%s", moduleName, s.Function.Name), s.GetLocation())
 				return nil
 			}
 
@@ -9601,7 +10636,7 @@ func (c *Checker) checkExprAsInner(expr parse.Expression, expectedType Type, exp
 
 			var arg Expression = nil
 			if fnDef.name() == "ok" {
-				arg = c.checkExpr(s.Function.Args[0].Value)
+				arg = c.checkExprAs(s.Function.Args[0].Value, resultType.Val())
 				if arg == nil {
 					return nil
 				}
@@ -9612,7 +10647,7 @@ func (c *Checker) checkExprAsInner(expr parse.Expression, expectedType Type, exp
 				bindInferredTypeVars(resultType.Val(), arg.Type())
 			}
 			if fnDef.name() == "err" {
-				arg = c.checkExpr(s.Function.Args[0].Value)
+				arg = c.checkExprAs(s.Function.Args[0].Value, resultType.Err())
 				if arg == nil {
 					return nil
 				}
@@ -9645,7 +10680,7 @@ func (c *Checker) checkExprAsInner(expr parse.Expression, expectedType Type, exp
 
 	var checked Expression
 	switch expr.(type) {
-	case *parse.FunctionCall, *parse.FunctionValueCall, *parse.StaticFunction, *parse.Try:
+	case *parse.FunctionCall, *parse.FunctionValueCall, *parse.StaticFunction, *parse.Try, *parse.StructInstance:
 		checked = c.checkExprWithExpectedCall(expr, expectedType)
 	default:
 		checked = c.checkExpr(expr)
@@ -9851,6 +10886,7 @@ func (c *Checker) resolveMethodSignature(def *parse.FunctionDeclaration) *Functi
 		ReturnType:    returnType,
 		Private:       def.Private,
 		IsTest:        def.IsTest,
+		Deprecated:    deprecationNote(def.Deprecated),
 	}
 }
 
@@ -9904,6 +10940,7 @@ func (c *Checker) checkFunctionWithSignature(def *parse.FunctionDeclaration, ini
 			Body:          nil,
 			Private:       def.Private,
 			IsTest:        def.IsTest,
+			Deprecated:    deprecationNote(def.Deprecated),
 		}
 	}
 
@@ -9986,14 +11023,14 @@ func substituteType(t Type, typeMap map[string]Type) Type {
 		}
 		return typ
 	case *Maybe:
-		return &Maybe{of: substituteType(typ.of, typeMap)}
+		return MakeMaybe(substituteType(typ.of, typeMap))
 	case *Result:
 		return MakeResult(
 			substituteType(typ.val, typeMap),
 			substituteType(typ.err, typeMap),
 		)
 	case *List:
-		return &List{of: substituteType(typ.of, typeMap)}
+		return MakeList(substituteType(typ.of, typeMap))
 	case *Chan:
 		return &Chan{of: substituteType(typ.of, typeMap)}
 	case *Receiver:
@@ -10001,7 +11038,7 @@ func substituteType(t Type, typeMap map[string]Type) Type {
 	case *Sender:
 		return &Sender{of: substituteType(typ.of, typeMap)}
 	case *Map:
-		return &Map{key: substituteType(typ.key, typeMap), value: substituteType(typ.value, typeMap)}
+		return MakeMap(substituteType(typ.key, typeMap), substituteType(typ.value, typeMap))
 	case *Union:
 		types := make([]Type, len(typ.Types))
 		for i, member := range typ.Types {
@@ -10035,6 +11072,7 @@ func substituteType(t Type, typeMap map[string]Type) Type {
 			Mutates:                 typ.Mutates,
 			IsTest:                  typ.IsTest,
 			Private:                 typ.Private,
+			Deprecated:              typ.Deprecated,
 			GenericBindings:         cloneTypeMap(typ.GenericBindings),
 		}
 	// Handle other compound types
@@ -10169,14 +11207,10 @@ func (c *Checker) createCallGenericScope(genericParams []string) *SymbolTable {
 	return scope
 }
 
-// setupFunctionCall creates the single call-local generic scope used by
-// argument checking and final specialization. Explicit and receiver-derived
-// bindings enter this scope before context-sensitive arguments are checked.
-func (c *Checker) setupFunctionCall(fnDef *FunctionDef, typeArgs []Type, declaredTypeArgs []parse.DeclaredType) (*FunctionDef, *SymbolTable, error) {
-	genericParams := append([]string(nil), callGenericParamsForFunction(fnDef)...)
-	return c.setupFunctionCallWithBindings(fnDef, genericParams, nil, genericParams, typeArgs, declaredTypeArgs)
-}
-
+// setupFunctionCallWithBindings creates the single call-local generic scope
+// used by argument checking and final specialization. Explicit and
+// receiver-derived bindings enter this scope before context-sensitive
+// arguments are checked.
 func (c *Checker) setupFunctionCallWithBindings(fnDef *FunctionDef, genericParams []string, initialBindings map[string]Type, explicitParams []string, typeArgs []Type, declaredTypeArgs []parse.DeclaredType) (*FunctionDef, *SymbolTable, error) {
 	genericParams = appendUniqueStrings(nil, genericParams...)
 	if len(genericParams) == 0 {
@@ -10538,6 +11572,7 @@ func (c *Checker) inferBindingsFromExpectedReturn(pattern Type, expected Type, g
 }
 
 func (c *Checker) checkAndProcessArguments(fnDef *FunctionDef, resolvedExprs []parse.Expression, fnDefCopy *FunctionDef, genericScope *SymbolTable, numOmittedArgs int, expectedReturn Type, callLocation parse.Location) ([]Expression, *FunctionDef) {
+	c.warnDeprecatedCall(fnDef, callLocation)
 	// Create the full argument list including synthesized Maybe::new() calls for omitted arguments
 	// Need to maintain parameter order, so use indexed assignment instead of appending
 	totalArgs := len(fnDefCopy.Parameters)
@@ -10559,9 +11594,9 @@ func (c *Checker) checkAndProcessArguments(fnDef *FunctionDef, resolvedExprs []p
 		}
 		return leftPoint.Col - rightPoint.Col
 	})
-	for _, i := range providedOrder {
+	for position, i := range providedOrder {
 		if genericScope != nil {
-			origin := genericBindingOrigin{Span: c.sourceSpan(resolvedExprs[i].GetLocation()), Kind: "earlier argument"}
+			origin := genericBindingOrigin{Span: c.sourceSpan(resolvedExprs[i].GetLocation()), Kind: "earlier argument", Argument: position + 1}
 			genericScope.genericPendingOrigin = &origin
 		}
 
@@ -10617,6 +11652,7 @@ func (c *Checker) checkAndProcessArguments(fnDef *FunctionDef, resolvedExprs []p
 		}
 
 		var checkedArg Expression
+		argumentDiagnostics := len(c.diagnostics)
 		c.withValueExprContext(func() {
 			switch resolvedExprs[i].(type) {
 			case *parse.ListLiteral, *parse.MapLiteral:
@@ -10627,11 +11663,11 @@ func (c *Checker) checkAndProcessArguments(fnDef *FunctionDef, resolvedExprs []p
 				}
 			case *parse.AnonymousFunction:
 				checkedArg = c.checkExprAsArgument(resolvedExprs[i], expectedType, fnDefCopy.Parameters[i])
-			case *parse.FunctionCall, *parse.FunctionValueCall, *parse.StaticFunction, *parse.InstanceMethod:
-				// A nested call gets parameter context only after receiver, explicit,
-				// earlier-argument, or enclosing-return evidence has resolved this
-				// call's variables. Enclosing declaration generics are safe context;
-				// unresolved variables owned by this call are not.
+			case *parse.FunctionCall, *parse.FunctionValueCall, *parse.StaticFunction, *parse.InstanceMethod, *parse.StructInstance:
+				// A nested call or struct literal gets parameter context only after
+				// receiver, explicit, earlier-argument, or enclosing-return evidence
+				// has resolved this call's variables. Enclosing declaration generics
+				// are safe context; unresolved variables owned by this call are not.
 				if genericScope != nil && hasUnresolvedGenericsFrom(expectedType, genericScope.genericContext) {
 					partialContext := maskUnresolvedGenericsFrom(expectedType, genericScope.genericContext)
 					checkedArg = c.checkExprAsArgument(resolvedExprs[i], partialContext, fnDefCopy.Parameters[i])
@@ -10642,6 +11678,9 @@ func (c *Checker) checkAndProcessArguments(fnDef *FunctionDef, resolvedExprs []p
 				checkedArg = c.checkExpr(resolvedExprs[i])
 			}
 		})
+		// Mismatches inside the argument, such as a list element, follow from
+		// bindings that earlier arguments made.
+		c.noteGenericBindings(c.diagnostics[argumentDiagnostics:], genericScope, fnDefCopy.Parameters[i].Type)
 
 		if checkedArg == nil {
 			return nil, nil
@@ -10837,6 +11876,7 @@ func (c *Checker) checkAndProcessArguments(fnDef *FunctionDef, resolvedExprs []p
 				Body:                    fnDefCopy.Body,
 				Mutates:                 fnDefCopy.Mutates,
 				Private:                 fnDefCopy.Private,
+				Deprecated:              fnDefCopy.Deprecated,
 				GenericBindings:         cloneTypeMap(bindings),
 			}
 
@@ -10934,6 +11974,8 @@ func (c *Checker) addExpectedReturnConflict(expected, actual Type, location pars
 		Expectation: c.expectedCallExpectation,
 	}.build()
 
+	diagnostic.Text = genericBindingNotes(genericScope, returnPattern)
+
 	patternNames := map[string]bool{}
 	extractGenericNames(returnPattern, patternNames)
 	var establishing *DiagnosticLabel
@@ -10964,12 +12006,15 @@ func (c *Checker) addExpectedReturnConflict(expected, actual Type, location pars
 func (c *Checker) addUnificationArgumentMismatch(err error, expected, actual Type, location parse.Location, parameter Parameter, genericScope *SymbolTable, parameterPattern Type) {
 	expected, actual = unificationDiagnosticTypes(err, expected, actual)
 	var related []DiagnosticLabel
+	var note string
 	if genericScope != nil {
 		if conflict, ok := err.(*genericBindingConflictError); ok {
 			if origin, exists := genericScope.genericOrigins[conflict.Name]; exists {
 				related = append(related, DiagnosticLabel{Span: origin.Span, Message: fmt.Sprintf("%s establishes `$%s` as `%s`", origin.Kind, conflict.Name, conflict.Existing)})
+				note = origin.note(conflict.Name, conflict.Existing)
 			}
 		} else {
+			note = genericBindingNotes(genericScope, parameterPattern)
 			patternNames := map[string]bool{}
 			extractGenericNames(parameterPattern, patternNames)
 			for _, name := range slices.Sorted(maps.Keys(patternNames)) {
@@ -10982,7 +12027,55 @@ func (c *Checker) addUnificationArgumentMismatch(err error, expected, actual Typ
 			}
 		}
 	}
-	c.addIncorrectArgumentType(err.Error(), expected, actual, location, parameter, false, related...)
+	diagnostic := c.incorrectArgumentType(err.Error(), expected, actual, location, parameter, false, related...)
+	diagnostic.Text = note
+	c.addDiagnostic(diagnostic)
+}
+
+// note explains where a call-local generic got its binding, for diagnostics
+// whose mismatch follows from that binding.
+func (o genericBindingOrigin) note(name string, bound Type) string {
+	location := fmt.Sprintf("%s:%d:%d", o.Span.FilePath, o.Span.Location.Start.Row, o.Span.Location.Start.Col)
+	if o.Argument > 0 {
+		return fmt.Sprintf("note: `$%s` was bound to `%s` because of argument %d at %s", name, bound, o.Argument, location)
+	}
+	return fmt.Sprintf("note: `$%s` was bound to `%s` because of the %s at %s", name, bound, o.Kind, location)
+}
+
+// genericBindingNotes returns one note line for each generic in pattern that
+// already has a recorded binding in genericScope.
+func genericBindingNotes(genericScope *SymbolTable, pattern Type) string {
+	if genericScope == nil || genericScope.genericContext == nil {
+		return ""
+	}
+	names := map[string]bool{}
+	extractGenericNames(pattern, names)
+	var notes []string
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		origin, hasOrigin := genericScope.genericOrigins[name]
+		typeVar := (*genericScope.genericContext)[name]
+		if hasOrigin && typeVar != nil && typeVar.bound {
+			notes = append(notes, origin.note(name, derefType(typeVar.actual)))
+		}
+	}
+	return strings.Join(notes, "\n")
+}
+
+// noteGenericBindings attaches binding notes to type mismatches reported
+// while checking a generic call's argument against pattern.
+func (c *Checker) noteGenericBindings(diagnostics []Diagnostic, genericScope *SymbolTable, pattern Type) {
+	if len(diagnostics) == 0 {
+		return
+	}
+	note := genericBindingNotes(genericScope, pattern)
+	if note == "" {
+		return
+	}
+	for i := range diagnostics {
+		if diagnostics[i].Code == DiagnosticCodeTypeMismatch && diagnostics[i].Text == "" {
+			diagnostics[i].Text = note
+		}
+	}
 }
 
 func (c *Checker) unifyTypes(expected Type, actual Type, genericScope *SymbolTable) error {
@@ -11030,22 +12123,22 @@ func (c *Checker) unifyTypes(expected Type, actual Type, genericScope *SymbolTab
 			}
 			return c.unifyTypes(expectedType.err, actualResult.err, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected result type, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected result type, got %s", formatType(actual)))
 	case *Maybe:
 		if actualMaybe, ok := actual.(*Maybe); ok {
 			return c.unifyTypes(expectedType.of, actualMaybe.of, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected maybe type, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected maybe type, got %s", formatType(actual)))
 	case *List:
 		if actualList, ok := actual.(*List); ok {
 			return c.unifyTypes(expectedType.of, actualList.of, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected list type, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected list type, got %s", formatType(actual)))
 	case *FixedArray:
 		if actualArray, ok := actual.(*FixedArray); ok && expectedType.length == actualArray.length {
 			return c.unifyTypes(expectedType.of, actualArray.of, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected fixed array type, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected fixed array type, got %s", formatType(actual)))
 	case *Map:
 		if actualMap, ok := actual.(*Map); ok {
 			if err := c.unifyTypes(expectedType.key, actualMap.key, genericScope); err != nil {
@@ -11053,27 +12146,27 @@ func (c *Checker) unifyTypes(expected Type, actual Type, genericScope *SymbolTab
 			}
 			return c.unifyTypes(expectedType.value, actualMap.value, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected map type, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected map type, got %s", formatType(actual)))
 	case *MutableRef:
 		if actualRef, ok := actual.(*MutableRef); ok {
 			return c.unifyTypes(expectedType.of, actualRef.of, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected mutable reference, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected mutable reference, got %s", formatType(actual)))
 	case *Chan:
 		if actualChannel, ok := actual.(*Chan); ok {
 			return c.unifyTypes(expectedType.of, actualChannel.of, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected channel type, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected channel type, got %s", formatType(actual)))
 	case *Receiver:
 		if act, ok := actual.(*Receiver); ok {
 			return c.unifyTypes(expectedType.of, act.of, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected receiver type, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected receiver type, got %s", formatType(actual)))
 	case *Sender:
 		if act, ok := actual.(*Sender); ok {
 			return c.unifyTypes(expectedType.of, act.of, genericScope)
 		}
-		return newUnificationError(expected, actual, fmt.Sprintf("expected sender type, got %T", actual))
+		return newUnificationError(expected, actual, fmt.Sprintf("expected sender type, got %s", formatType(actual)))
 	case *StructDef:
 		actualStruct, ok := actual.(*StructDef)
 		if !ok || expectedType.Name != actualStruct.Name || namedTypeOwnersDiffer(expectedType.ModulePath, actualStruct.ModulePath) || len(expectedType.TypeArgs) != len(actualStruct.TypeArgs) {
@@ -11208,12 +12301,31 @@ func (c *Checker) resolveArguments(args []parse.Argument, params []Parameter) ([
 
 // buildComparison builds a comparison expression node from two operands and an operator
 // It returns the appropriate typed comparison node (IntGreater, FloatLess, etc.)
+// buildChainLink checks the i-th comparison of a chain. A `!=` link is
+// checked as a standalone inequality so it accepts every equality-eligible
+// type, not only the ordered ones.
+func (c *Checker) buildChainLink(chain *parse.ChainedComparison, i int) Expression {
+	left, right := chain.Operands[i], chain.Operands[i+1]
+	if chain.Operators[i] == parse.NotEqual {
+		return c.checkExpr(&parse.BinaryExpression{
+			Location: parse.Location{Start: left.GetLocation().Start, End: right.GetLocation().End},
+			Operator: parse.NotEqual,
+			Left:     left,
+			Right:    right,
+		})
+	}
+	return c.buildComparison(left, chain.Operators[i], right)
+}
+
 func (c *Checker) buildComparison(leftExpr parse.Expression, op parse.Operator, rightExpr parse.Expression) Expression {
 	left := c.checkExpr(leftExpr)
 	right := c.checkExpr(rightExpr)
 	if left == nil || right == nil {
 		return nil
 	}
+	if dispatched, ok := c.dispatchOperator(op, left, right); ok {
+		return dispatched
+	}
 
 	// Allow Enum vs Int comparisons
 	operator := comparisonOperatorText(op)
@@ -11315,13 +12427,17 @@ func (c *Checker) checkAccessorChainWithMaybes(parseExpr parse.Expression) Expre
 		propType := innerType.get(p.Property.Name)
 		if propType == nil {
 			c.addDiagnostic(undefinedMemberDiagnostic{
-				Kind:     undefinedField,
-				Receiver: fmt.Sprint(innerType),
-				Member:   p.Property.Name,
-				Span:     c.sourceSpan(p.Property.GetLocation()),
+				Kind:       undefinedField,
+				Receiver:   fmt.Sprint(innerType),
+				Member:     p.Property.Name,
+				Span:       c.sourceSpan(p.Property.GetLocation()),
+				Suggestion: suggestName(p.Property.Name, fieldNames(innerType)),
 			}.build())
 			return nil
 		}
+		if !c.checkFieldVisible(innerType, p.Property.Name, p.Property.GetLocation()) {
+			return nil
+		}
 
 		prop := &InstanceProperty{
 			Subject:  target,
@@ -11381,6 +12497,9 @@ func (c *Checker) checkAccessorChainWithMaybes(parseExpr parse.Expression) Expre
 					return call
 				}
 			}
+			if c.checkPrivateMethod(innerType, p.Method.Name, p.Method.GetLocation()) {
+				return nil
+			}
 			c.addDiagnostic(undefinedMemberDiagnostic{
 				Kind:     undefinedMethod,
 				Receiver: fmt.Sprint(innerType),
@@ -11439,19 +12558,19 @@ func (c *Checker) wrapAccessorInMatch(subject Expression, prop *InstanceProperty
 	}
 
 	// Create the Some block containing the property access
-	someBlock := &Block{
+	someBlock := c.nodes.blocks.Put(Block{
 		Stmts: []Statement{
 			{Expr: propOnUnwrapped},
 		},
-	}
+	})
 
 	// The None block just returns the subject (which is None)
 	// The subject's type is Maybe<innerType>, so it will propagate as None of type propType
-	noneBlock := &Block{
+	noneBlock := c.nodes.blocks.Put(Block{
 		Stmts: []Statement{
 			{Expr: subject},
 		},
-	}
+	})
 
 	// Create and return the OptionMatch
 	return &OptionMatch{
@@ -23,7 +23,7 @@ var compareOptions = cmp.Options{
 	cmpopts.IgnoreFields(checker.Parameter{}, "Loc", "declaredAt"),
 	// Legacy table tests assert the compatibility message. Each migrated
 	// diagnostic family must assert its structured fields in diagnostics_test.go.
-	cmpopts.IgnoreFields(checker.Diagnostic{}, "Code", "Title", "Text", "Primary", "Secondary"),
+	cmpopts.IgnoreFields(checker.Diagnostic{}, "Code", "Title", "Text", "Help", "Fixes", "Primary", "Secondary"),
 	cmpopts.IgnoreFields(checker.OptionMatch{}, "ResultType"),
 	cmpopts.IgnoreFields(checker.EnumMatch{}, "DiscriminantToIndex", "ResultType"),
 	cmpopts.IgnoreFields(checker.EnumVariant{}, "EnumType", "Discriminant"),
@@ -157,7 +157,7 @@ func TestPrimitiveLiterals(t *testing.T) {
 							Chunks: []checker.Expression{
 								&checker.StrLiteral{"Hello, "},
 								&checker.IntMethod{
-									Subject: &checker.IntLiteral{3},
+									Subject: &checker.IntLiteral{Value: 3},
 									Kind:    checker.IntToStr,
 								},
 							},
@@ -371,6 +371,56 @@ func TestVariables(t *testing.T) {
 				{Kind: checker.Error, Message: "Type mismatch: Expected Str, got Int"},
 			},
 		},
+		{
+			name: "Compound assignments apply arithmetic to the target",
+			input: strings.Join([]string{
+				`mut total = 10`,
+				`total =* 3`,
+				`total =/ 2`,
+			}, "\n"),
+			output: &checker.Program{
+				Statements: []checker.Statement{
+					{
+						Stmt: &checker.VariableDef{
+							Mutable: true,
+							Name:    "total",
+							Value:   &checker.IntLiteral{Value: 10},
+						},
+					},
+					{
+						Stmt: &checker.Reassignment{
+							Target: &checker.Variable{},
+							Value: &checker.IntMultiplication{
+								Left:  &checker.Variable{},
+								Right: &checker.IntLiteral{Value: 3},
+							},
+						},
+					},
+					{
+						Stmt: &checker.Reassignment{
+							Target: &checker.Variable{},
+							Value: &checker.IntDivision{
+								Left:  &checker.Variable{},
+								Right: &checker.IntLiteral{Value: 2},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "Compound assignments require a mutable target and matching operands",
+			input: strings.Join([]string{
+				`let fixed = 2.0`,
+				`fixed =* 2.0`,
+				`mut count = 1`,
+				`count =/ 2.0`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Immutable variable: fixed"},
+				{Kind: checker.Error, Message: "Cannot divide different types"},
+			},
+		},
 		{
 			name:  "Cannot reassign undeclared variables",
 			input: `name = "Bob"`,
@@ -473,14 +523,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntAddition{
-							Left:  &checker.IntLiteral{1},
-							Right: &checker.IntLiteral{2},
+							Left:  &checker.IntLiteral{Value: 1},
+							Right: &checker.IntLiteral{Value: 2},
 						},
 					},
 					{
 						Expr: &checker.IntAddition{
-							Left:  &checker.IntLiteral{3},
-							Right: &checker.Negation{&checker.IntLiteral{4}},
+							Left:  &checker.IntLiteral{Value: 3},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 4}},
 						},
 					},
 				},
@@ -535,14 +585,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntSubtraction{
-							Left:  &checker.IntLiteral{1},
-							Right: &checker.IntLiteral{2},
+							Left:  &checker.IntLiteral{Value: 1},
+							Right: &checker.IntLiteral{Value: 2},
 						},
 					},
 					{
 						Expr: &checker.IntSubtraction{
-							Left:  &checker.IntLiteral{3},
-							Right: &checker.Negation{&checker.IntLiteral{4}},
+							Left:  &checker.IntLiteral{Value: 3},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 4}},
 						},
 					},
 				},
@@ -581,14 +631,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntMultiplication{
-							Left:  &checker.IntLiteral{1},
-							Right: &checker.IntLiteral{2},
+							Left:  &checker.IntLiteral{Value: 1},
+							Right: &checker.IntLiteral{Value: 2},
 						},
 					},
 					{
 						Expr: &checker.IntMultiplication{
-							Left:  &checker.IntLiteral{3},
-							Right: &checker.Negation{&checker.IntLiteral{4}},
+							Left:  &checker.IntLiteral{Value: 3},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 4}},
 						},
 					},
 				},
@@ -627,14 +677,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntDivision{
-							Left:  &checker.IntLiteral{10},
-							Right: &checker.IntLiteral{2},
+							Left:  &checker.IntLiteral{Value: 10},
+							Right: &checker.IntLiteral{Value: 2},
 						},
 					},
 					{
 						Expr: &checker.IntDivision{
-							Left:  &checker.IntLiteral{15},
-							Right: &checker.Negation{&checker.IntLiteral{3}},
+							Left:  &checker.IntLiteral{Value: 15},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 3}},
 						},
 					},
 				},
@@ -673,14 +723,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntModulo{
-							Left:  &checker.IntLiteral{10},
-							Right: &checker.IntLiteral{3},
+							Left:  &checker.IntLiteral{Value: 10},
+							Right: &checker.IntLiteral{Value: 3},
 						},
 					},
 					{
 						Expr: &checker.IntModulo{
-							Left:  &checker.IntLiteral{15},
-							Right: &checker.Negation{&checker.IntLiteral{4}},
+							Left:  &checker.IntLiteral{Value: 15},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 4}},
 						},
 					},
 				},
@@ -703,14 +753,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntGreater{
-							Left:  &checker.IntLiteral{1},
-							Right: &checker.IntLiteral{2},
+							Left:  &checker.IntLiteral{Value: 1},
+							Right: &checker.IntLiteral{Value: 2},
 						},
 					},
 					{
 						Expr: &checker.IntGreater{
-							Left:  &checker.IntLiteral{3},
-							Right: &checker.Negation{&checker.IntLiteral{4}},
+							Left:  &checker.IntLiteral{Value: 3},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 4}},
 						},
 					},
 				},
@@ -726,14 +776,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntGreaterEqual{
-							Left:  &checker.IntLiteral{1},
-							Right: &checker.IntLiteral{2},
+							Left:  &checker.IntLiteral{Value: 1},
+							Right: &checker.IntLiteral{Value: 2},
 						},
 					},
 					{
 						Expr: &checker.IntGreaterEqual{
-							Left:  &checker.IntLiteral{3},
-							Right: &checker.Negation{&checker.IntLiteral{4}},
+							Left:  &checker.IntLiteral{Value: 3},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 4}},
 						},
 					},
 				},
@@ -795,14 +845,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntLess{
-							Left:  &checker.IntLiteral{1},
-							Right: &checker.IntLiteral{2},
+							Left:  &checker.IntLiteral{Value: 1},
+							Right: &checker.IntLiteral{Value: 2},
 						},
 					},
 					{
 						Expr: &checker.IntLess{
-							Left:  &checker.IntLiteral{3},
-							Right: &checker.Negation{&checker.IntLiteral{4}},
+							Left:  &checker.IntLiteral{Value: 3},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 4}},
 						},
 					},
 				},
@@ -818,14 +868,14 @@ func TestIntMath(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntLessEqual{
-							Left:  &checker.IntLiteral{1},
-							Right: &checker.IntLiteral{2},
+							Left:  &checker.IntLiteral{Value: 1},
+							Right: &checker.IntLiteral{Value: 2},
 						},
 					},
 					{
 						Expr: &checker.IntLessEqual{
-							Left:  &checker.IntLiteral{3},
-							Right: &checker.Negation{&checker.IntLiteral{4}},
+							Left:  &checker.IntLiteral{Value: 3},
+							Right: &checker.Negation{&checker.IntLiteral{Value: 4}},
 						},
 					},
 				},
@@ -921,8 +971,8 @@ func TestEqualityComparisons(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.Equality{
-							&checker.IntLiteral{1},
-							&checker.IntLiteral{2},
+							&checker.IntLiteral{Value: 1},
+							&checker.IntLiteral{Value: 2},
 						},
 					},
 					{
@@ -962,8 +1012,8 @@ func TestEqualityComparisons(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.Inequality{
-							&checker.IntLiteral{1},
-							&checker.IntLiteral{2},
+							&checker.IntLiteral{Value: 1},
+							&checker.IntLiteral{Value: 2},
 						},
 					},
 					{
@@ -1041,13 +1091,38 @@ func TestChainedComparisons(t *testing.T) {
 			},
 		},
 		{
-			name:  "Chained comparison with inequality operators should error",
+			name:  "Chained comparison with an inequality link is a conjunction",
 			input: "1 < 2 != 1",
 			output: &checker.Program{
-				Statements: []checker.Statement{},
+				Statements: []checker.Statement{
+					{
+						Expr: &checker.And{
+							Left: &checker.IntLess{
+								Left:  &checker.IntLiteral{Value: 1},
+								Right: &checker.IntLiteral{Value: 2},
+							},
+							Right: &checker.Inequality{
+								Left:  &checker.IntLiteral{Value: 2},
+								Right: &checker.IntLiteral{Value: 1},
+							},
+						},
+					},
+				},
 			},
+		},
+		{
+			name: "Inequality links accept every equality-eligible type",
+			input: strings.Join([]string{
+				`fn check(low: Int, x: Int, label: Str, flag: Bool, found: Int?) Bool {`,
+				`  low <= x != 10 and label != "none" and flag != false and found != Maybe::new(x)`,
+				`}`,
+			}, "\n"),
+		},
+		{
+			name:  "Inequality links still reject mismatched types",
+			input: `1 < 2 != "two"`,
 			diagnostics: []checker.Diagnostic{
-				{Kind: checker.Error, Message: "equality operators cannot be chained"},
+				{Kind: checker.Error, Message: "Invalid: Int != Str"},
 			},
 		},
 	})
@@ -1094,8 +1169,8 @@ func TestParenthesizedExpressions(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntMultiplication{
-							Left:  &checker.IntAddition{&checker.IntLiteral{30}, &checker.IntLiteral{20}},
-							Right: &checker.IntLiteral{4},
+							Left:  &checker.IntAddition{&checker.IntLiteral{Value: 30}, &checker.IntLiteral{Value: 20}},
+							Right: &checker.IntLiteral{Value: 4},
 						},
 					},
 				},
@@ -1267,14 +1342,14 @@ func TestForLoops(t *testing.T) {
 						Stmt: &checker.VariableDef{
 							Mutable: true,
 							Name:    "count",
-							Value:   &checker.IntLiteral{0},
+							Value:   &checker.IntLiteral{Value: 0},
 						},
 					},
 					{
 						Stmt: &checker.ForIntRange{
 							Cursor: "i",
-							Start:  &checker.IntLiteral{1},
-							End:    &checker.IntLiteral{10},
+							Start:  &checker.IntLiteral{Value: 1},
+							End:    &checker.IntLiteral{Value: 10},
 							Body: &checker.Block{
 								Stmts: []checker.Statement{
 									{
@@ -1302,6 +1377,49 @@ func TestForLoops(t *testing.T) {
 				{Kind: checker.Error, Message: "Invalid range: Int..Bool"},
 			},
 		},
+		{
+			name: "A range can count down with a step",
+			input: strings.Join([]string{
+				`for i in 10..0 by -2 {`,
+				`  i`,
+				`}`,
+			}, "\n"),
+			output: &checker.Program{
+				Statements: []checker.Statement{
+					{
+						Stmt: &checker.ForIntRange{
+							Cursor: "i",
+							Start:  &checker.IntLiteral{Value: 10},
+							End:    &checker.IntLiteral{Value: 0},
+							Step:   &checker.IntLiteral{Value: -2},
+							Body: &checker.Block{
+								Stmts: []checker.Statement{
+									{Expr: &checker.Variable{}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "The range step must be an Int",
+			input: strings.Join([]string{
+				`for i in 1..10 by 0.5 {}`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Float64"},
+			},
+		},
+		{
+			name: "The range step cannot be a constant 0",
+			input: strings.Join([]string{
+				`for i in 1..10 by 1 - 1 {}`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Range step cannot be 0"},
+			},
+		},
 		{
 			name: "Iterating over a string",
 			input: strings.Join([]string{
@@ -1344,8 +1462,8 @@ func TestForLoops(t *testing.T) {
 					{
 						Stmt: &checker.ForIntRange{
 							Cursor: "i",
-							Start:  &checker.IntLiteral{0},
-							End:    &checker.IntLiteral{20},
+							Start:  &checker.IntLiteral{Value: 0},
+							End:    &checker.IntLiteral{Value: 20},
 							Body: &checker.Block{
 								Stmts: []checker.Statement{
 									{Expr: &checker.Variable{}},
@@ -1381,7 +1499,7 @@ func TestLoopingOverMaps(t *testing.T) {
 							Val: "val",
 							Map: &checker.MapLiteral{
 								Keys:      []checker.Expression{&checker.StrLiteral{"hello"}, &checker.StrLiteral{"world"}},
-								Values:    []checker.Expression{&checker.IntLiteral{5}, &checker.IntLiteral{5}},
+								Values:    []checker.Expression{&checker.IntLiteral{Value: 5}, &checker.IntLiteral{Value: 5}},
 								KeyType:   checker.Str,
 								ValueType: checker.Int,
 							},
@@ -1427,17 +1545,17 @@ func TestTraditionalForLoop(t *testing.T) {
 							Init: &checker.VariableDef{
 								Mutable: true,
 								Name:    "i",
-								Value:   &checker.IntLiteral{0},
+								Value:   &checker.IntLiteral{Value: 0},
 							},
 							Condition: &checker.IntLess{
 								Left:  &checker.Variable{},
-								Right: &checker.IntLiteral{10},
+								Right: &checker.IntLiteral{Value: 10},
 							},
 							Update: &checker.Reassignment{
 								Target: &checker.Variable{},
 								Value: &checker.IntAddition{
 									Left:  &checker.Variable{},
-									Right: &checker.IntLiteral{1},
+									Right: &checker.IntLiteral{Value: 1},
 								},
 							},
 							Body: &checker.Block{
@@ -1507,14 +1625,14 @@ func TestWhileLoops(t *testing.T) {
 						Stmt: &checker.VariableDef{
 							Mutable: true,
 							Name:    "count",
-							Value:   &checker.IntLiteral{10},
+							Value:   &checker.IntLiteral{Value: 10},
 						},
 					},
 					{
 						Stmt: &checker.WhileLoop{
 							Condition: &checker.IntGreater{
 								Left:  &checker.Variable{},
-								Right: &checker.IntLiteral{0},
+								Right: &checker.IntLiteral{Value: 0},
 							},
 							Body: &checker.Block{
 								Stmts: []checker.Statement{
@@ -1523,7 +1641,7 @@ func TestWhileLoops(t *testing.T) {
 											Target: &checker.Variable{},
 											Value: &checker.IntSubtraction{
 												Left:  &checker.Variable{},
-												Right: &checker.IntLiteral{1},
+												Right: &checker.IntLiteral{Value: 1},
 											},
 										},
 									},
@@ -1560,14 +1678,14 @@ func TestWhileLoops(t *testing.T) {
 						Stmt: &checker.VariableDef{
 							Mutable: true,
 							Name:    "i",
-							Value:   &checker.IntLiteral{0},
+							Value:   &checker.IntLiteral{Value: 0},
 						},
 					},
 					{
 						Stmt: &checker.VariableDef{
 							Mutable: true,
 							Name:    "j",
-							Value:   &checker.IntLiteral{10},
+							Value:   &checker.IntLiteral{Value: 10},
 						},
 					},
 					{
@@ -1575,11 +1693,11 @@ func TestWhileLoops(t *testing.T) {
 							Condition: &checker.And{
 								Left: &checker.IntLess{
 									Left:  &checker.Variable{},
-									Right: &checker.IntLiteral{5},
+									Right: &checker.IntLiteral{Value: 5},
 								},
 								Right: &checker.IntGreater{
 									Left:  &checker.Variable{},
-									Right: &checker.IntLiteral{0},
+									Right: &checker.IntLiteral{Value: 0},
 								},
 							},
 							Body: &checker.Block{
@@ -1589,7 +1707,7 @@ func TestWhileLoops(t *testing.T) {
 											Target: &checker.Variable{},
 											Value: &checker.IntAddition{
 												Left:  &checker.Variable{},
-												Right: &checker.IntLiteral{1},
+												Right: &checker.IntLiteral{Value: 1},
 											},
 										},
 									},
@@ -1773,7 +1891,7 @@ func TestLists(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.ListMethod{
-							Subject:     &checker.ListLiteral{Elements: []checker.Expression{&checker.IntLiteral{1}}},
+							Subject:     &checker.ListLiteral{Elements: []checker.Expression{&checker.IntLiteral{Value: 1}}},
 							Kind:        checker.ListSize,
 							Args:        []checker.Expression{},
 							ElementType: checker.Int,
@@ -1813,6 +1931,34 @@ func TestMapKeyTypeConstraint(t *testing.T) {
 			name:  "primitive and enum keys are allowed",
 			input: `let ages: [Str: Int] = ["ard": 0]`,
 		},
+		{
+			name: "struct keys are allowed",
+			input: `struct Point { x: Int, y: Int }
+let origin = Point{x: 0, y: 0}
+let names: [Point: Str] = [origin: "origin"]`,
+		},
+		{
+			name: "generics used as map keys must be bound to valid key types",
+			input: `struct Point { x: Int, y: Int }
+fn count(key: $K) [$K: Int] {
+  [key: 1]
+}
+fn tally(value: $T) Int {
+  count(value).size()
+}
+fn first(items: [$T]) $T {
+  items.at(0)
+}
+let a = count(Point{x: 1, y: 2})
+let b = tally("ard")
+let c = first([[1]])
+let d = count([1])
+let e = tally(Maybe::new(1))`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid map key type [Int] for $K: map keys must be comparable (primitives, enums, or structs)"},
+				{Kind: checker.Error, Message: "Invalid map key type Int? for $T: map keys must be comparable (primitives, enums, or structs)"},
+			},
+		},
 	})
 }
 func TestMaps(t *testing.T) {
@@ -1831,8 +1977,8 @@ func TestMaps(t *testing.T) {
 									&checker.StrLiteral{"go"},
 								},
 								Values: []checker.Expression{
-									&checker.IntLiteral{0},
-									&checker.IntLiteral{15},
+									&checker.IntLiteral{Value: 0},
+									&checker.IntLiteral{Value: 15},
 								},
 								KeyType:   checker.Str,
 								ValueType: checker.Int,
@@ -1856,8 +2002,8 @@ func TestMaps(t *testing.T) {
 									&checker.StrLiteral{"go"},
 								},
 								Values: []checker.Expression{
-									&checker.IntLiteral{0},
-									&checker.IntLiteral{15},
+									&checker.IntLiteral{Value: 0},
+									&checker.IntLiteral{Value: 15},
 								},
 								KeyType:   checker.Str,
 								ValueType: checker.Int,
@@ -2180,6 +2326,130 @@ func TestMatchingOnEnums(t *testing.T) {
 		},
 	})
 }
+func TestEnumPayloads(t *testing.T) {
+	event := `enum Event { Click(Int, Int), Key(Str), Quit }`
+	run(t, []test{
+		{
+			name: "Variants are constructed with their payload and bound by match",
+			input: strings.Join([]string{
+				event,
+				"let click = Event::Click(1, 2)",
+				"match click {",
+				"  Event::Click(x, _) => x,",
+				"  Event::Key(key) => key.size(),",
+				"  Event::Quit => 0",
+				"}",
+			}, "\n"),
+		},
+		{
+			name: "A case may ignore the payload",
+			input: strings.Join([]string{
+				event,
+				"match Event::Key(\"a\") {",
+				"  Event::Click => 1,",
+				"  _ => 0",
+				"}",
+			}, "\n"),
+		},
+		{
+			name:  "Payload variants require their values",
+			input: event + "\nlet click = Event::Click",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Event::Click requires 2 payload value(s)"},
+			},
+		},
+		{
+			name:  "Payload arity is checked",
+			input: event + "\nlet click = Event::Click(1)",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Event::Click requires 2 payload value(s), got 1"},
+			},
+		},
+		{
+			name:  "Payload types are checked",
+			input: event + "\nlet key = Event::Key(1)",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Str, got Int"},
+			},
+		},
+		{
+			name: "Patterns bind every payload value",
+			input: strings.Join([]string{
+				event,
+				"match Event::Quit {",
+				"  Event::Click(x) => x,",
+				"  _ => 0",
+				"}",
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Event::Click binds 1 payload value(s), but the variant has 2"},
+			},
+		},
+		{
+			name: "Matches stay exhaustive",
+			input: strings.Join([]string{
+				event,
+				"match Event::Quit {",
+				"  Event::Click(x, y) => x + y,",
+				"  Event::Quit => 0",
+				"}",
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Incomplete match: missing case for 'Event::Key'"},
+			},
+		},
+		{
+			name:  "Payload enums are not comparable with ==",
+			input: event + "\nlet same = Event::Quit == Event::Quit",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid: Event == Event"},
+			},
+		},
+	})
+}
+
+func TestEnumUtilities(t *testing.T) {
+	status := `enum Status { Active, Paused = 5, Done }`
+	run(t, []test{
+		{
+			name: "Enums list their variants, count them, and convert from Int",
+			input: strings.Join([]string{
+				status,
+				"let all: [Status] = Status::values()",
+				"let total: Int = Status::count",
+				"let paused: Status? = Status::from_int(5)",
+				"let label: Str = Status::Done.name()",
+			}, "\n"),
+		},
+		{
+			name: "An enum's own methods and statics take precedence",
+			input: strings.Join([]string{
+				status,
+				"impl Status {",
+				"  fn name() Int { 1 }",
+				"}",
+				"fn Status::values() Int { 0 }",
+				"let label: Int = Status::Done.name()",
+				"let none: Int = Status::values()",
+			}, "\n"),
+		},
+		{
+			name:  "from_int takes an Int",
+			input: status + "\nlet found = Status::from_int(\"5\")",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Str"},
+			},
+		},
+		{
+			name:  "Enums with associated data have no values()",
+			input: "enum Event { Click(Int, Int), Quit }\nlet all = Event::values()",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Event::values is not available on enums with associated data"},
+			},
+		},
+	})
+}
+
 func TestMatchingOnBooleans(t *testing.T) {
 	run(t, []test{
 		{
@@ -2482,6 +2752,70 @@ func TestMatchingOnInts(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Int arms that earlier arms already match are reported",
+			input: strings.Join([]string{
+				`let code: Int = 0`,
+				`match code {`,
+				`  40..50 => "forties",`,
+				`  42 => "the answer",`,
+				`  43 | 51 => "some",`,
+				`  44..46 => "inside",`,
+				`  48..55 => "overlapping",`,
+				`  _ => "other",`,
+				`  7 => "seven",`,
+				`  _ => "again",`,
+				`}`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Warn, Message: "Unreachable case: 42 is already covered by 40..50"},
+				{Kind: checker.Warn, Message: "Unreachable case: 43 is already covered by 40..50"},
+				{Kind: checker.Warn, Message: "Unreachable case: 44..46 is already covered by 40..50"},
+				{Kind: checker.Warn, Message: "Range 48..55 overlaps 40..50"},
+				{Kind: checker.Warn, Message: "Unreachable case: an earlier arm matches every value"},
+				{Kind: checker.Warn, Message: "Unreachable case: an earlier arm matches every value"},
+			},
+		},
+		{
+			name: "Shadowed Int arms are left out of the match",
+			input: strings.Join([]string{
+				`let code: Int = 0`,
+				`match code {`,
+				`  0..9 => "digit",`,
+				`  5 | 10 => "five or ten",`,
+				`  2..3 => "small",`,
+				`  _ => "other",`,
+				`}`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Warn, Message: "Unreachable case: 5 is already covered by 0..9"},
+				{Kind: checker.Warn, Message: "Unreachable case: 2..3 is already covered by 0..9"},
+			},
+			output: &checker.Program{
+				Statements: []checker.Statement{
+					{
+						Stmt: &checker.VariableDef{
+							Name:  "code",
+							Value: &checker.IntLiteral{Value: 0},
+						},
+					},
+					{
+						Expr: &checker.IntMatch{
+							Subject: &checker.Variable{},
+							IntCases: map[int]*checker.Block{
+								10: {Stmts: []checker.Statement{{Expr: &checker.StrLiteral{Value: "five or ten"}}}},
+							},
+							RangeCases: map[checker.IntRange]*checker.Block{
+								{Start: 0, End: 9}: {Stmts: []checker.Statement{{Expr: &checker.StrLiteral{Value: "digit"}}}},
+							},
+							CatchAll: &checker.Block{
+								Stmts: []checker.Statement{{Expr: &checker.StrLiteral{Value: "other"}}},
+							},
+						},
+					},
+				},
+			},
+		},
 	})
 }
 func TestGenerics(t *testing.T) {
@@ -2528,10 +2862,10 @@ func TestGenerics(t *testing.T) {
 					},
 					{
 						Expr: &checker.IntAddition{
-							&checker.IntLiteral{2},
+							&checker.IntLiteral{Value: 2},
 							&checker.FunctionCall{
 								Name: "identity",
-								Args: []checker.Expression{&checker.IntLiteral{1}},
+								Args: []checker.Expression{&checker.IntLiteral{Value: 1}},
 							},
 						},
 					},
@@ -2729,6 +3063,25 @@ fn main() {
   async::start(value)
 }`,
 		},
+		{
+			name: "try propagates a spawned fiber's error",
+			input: `use ard/async
+fn run() Int!Str {
+  let fiber: async::Fiber<Int!Str> = async::spawn(fn() Int!Str { Result::ok(1) })
+  let value = try fiber.await()
+  Result::ok(value + 1)
+}`,
+		},
+		{
+			name: "spawn rejects a task with parameters",
+			input: `use ard/async
+fn main() {
+  async::spawn(fn(x: Int) Int { x })
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Incorrect number of arguments: Expected 0, got 1"},
+			},
+		},
 	})
 }
 
@@ -3025,5 +3378,70 @@ func TestValuePositionIfRequiresElse(t *testing.T) {
 			`,
 			diagnostics: []checker.Diagnostic{},
 		},
+		{
+			name: "if without else produces a Maybe when one is expected",
+			input: `
+				fn half(x: Int) Int? {
+					if x % 2 == 0 {
+						x / 2
+					}
+				}
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "else-if chain without a final else produces a Maybe",
+			input: `
+				fn sign(x: Int) Str? {
+					if x > 0 {
+						"+"
+					} else if x < 0 {
+						"-"
+					}
+				}
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "let infers a Maybe from an if without else",
+			input: `
+				fn pick(x: Int) Int {
+					let doubled = if x > 1 {
+						x * 2
+					}
+					let annotated: Int? = if x > 10 {
+						x
+					}
+					doubled.or(0) + annotated.or(0)
+				}
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "a Maybe branch is not wrapped twice",
+			input: `
+				fn pick(x: Int, fallback: Int?) Int? {
+					if x > 1 {
+						fallback
+					}
+				}
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "let with an if without else rejects mismatched branches",
+			input: `
+				fn pick(x: Int) {
+					let value = if x > 1 {
+						1
+					} else if x > 0 {
+						"one"
+					}
+				}
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "All branches must have the same result type"},
+			},
+		},
 	})
 }
@@ -0,0 +1,282 @@
+package checker
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// checkConstant folds the checked initializer of a `const` declaration to a
+// literal. References to the constant resolve to that literal, so backends
+// see folded values and match patterns can use them. ard/int's MAX and MIN
+// fold to literals marked with their IntLimit.
+func (c *Checker) checkConstant(decl *parse.VariableDeclaration, value Expression, typ Type) (Expression, bool) {
+	if c.scope.parent != nil {
+		c.addDiagnostic(invalidConstantDiagnostic{
+			Kind: constantNotTopLevel,
+			Name: decl.Name,
+			Span: c.sourceSpan(decl.GetLocation()),
+		}.build())
+		return nil, false
+	}
+	if typ != Int && typ != Float64 && typ != Str && typ != Bool {
+		c.addDiagnostic(invalidConstantDiagnostic{
+			Kind:   unsupportedConstantType,
+			Name:   decl.Name,
+			Reason: typ.String(),
+			Span:   c.sourceSpan(decl.Value.GetLocation()),
+		}.build())
+		return nil, false
+	}
+	folded, err := evalConstant(value)
+	if errors.Is(err, errConstantOverflow) {
+		// already reported by checkConstantOverflow
+		return nil, false
+	}
+	if err != nil {
+		c.addDiagnostic(invalidConstantDiagnostic{
+			Kind:   nonConstantInitializer,
+			Name:   decl.Name,
+			Reason: err.Error(),
+			Span:   c.sourceSpan(decl.Value.GetLocation()),
+		}.build())
+		return nil, false
+	}
+	if literal, ok := folded.(*IntLiteral); ok && c.modulePath == "ard/int" {
+		switch decl.Name {
+		case "MAX":
+			return &IntLiteral{Value: literal.Value, Limit: IntMax}, true
+		case "MIN":
+			return &IntLiteral{Value: literal.Value, Limit: IntMin}, true
+		}
+	}
+	return folded, true
+}
+
+// resolveStructDefaults checks the field defaults declared on def, once. It
+// runs when the declaration is reached or when an earlier instance needs the
+// defaults, whichever comes first, and always in the module scope so
+// defaults see the module's constants but never a caller's locals.
+func (c *Checker) resolveStructDefaults(def *StructDef, decl *parse.StructDefinition) {
+	if c.resolvedStructDefaults[def] {
+		return
+	}
+	if c.resolvedStructDefaults == nil {
+		c.resolvedStructDefaults = map[*StructDef]bool{}
+	}
+	c.resolvedStructDefaults[def] = true
+	scope := c.scope
+	for c.scope.parent != nil {
+		c.scope = c.scope.parent
+	}
+	defer func() { c.scope = scope }()
+	for _, field := range decl.Fields {
+		fieldType, ok := def.Fields[field.Name.Name]
+		if field.Default == nil || !ok {
+			continue
+		}
+		if value := c.checkFieldDefault(field, fieldType); value != nil {
+			if def.Defaults == nil {
+				def.Defaults = map[string]Expression{}
+			}
+			def.Defaults[field.Name.Name] = value
+		}
+	}
+}
+
+// checkFieldDefault folds a field's declared default to a constant. Like a
+// `const`, it may use literals, constants, arithmetic, and concatenation; a
+// field of enum type may also default to one of its plain variants.
+func (c *Checker) checkFieldDefault(field parse.StructField, fieldType Type) Expression {
+	enum, isEnum := fieldType.(*Enum)
+	if fieldType != Int && fieldType != Float64 && fieldType != Str && fieldType != Bool && (!isEnum || enum.HasPayload()) {
+		c.addDiagnostic(invalidFieldDefaultDiagnostic{
+			Field:       field.Name.Name,
+			Unsupported: true,
+			Reason:      fieldType.String(),
+			Span:        c.sourceSpan(field.Default.GetLocation()),
+		}.build())
+		return nil
+	}
+	value := c.checkExprAs(field.Default, fieldType)
+	if value == nil {
+		return nil
+	}
+	if variant, ok := value.(*EnumVariant); ok {
+		return variant
+	}
+	folded, err := evalConstant(value)
+	if errors.Is(err, errConstantOverflow) {
+		// already reported by checkConstantOverflow
+		return nil
+	}
+	if err != nil {
+		c.addDiagnostic(invalidFieldDefaultDiagnostic{
+			Field:  field.Name.Name,
+			Reason: err.Error(),
+			Span:   c.sourceSpan(field.Default.GetLocation()),
+		}.build())
+		return nil
+	}
+	return folded
+}
+
+// errConstantOverflow marks Int constant arithmetic whose result does not
+// fit in an Int.
+var errConstantOverflow = errors.New("Int overflow")
+
+// checkConstantOverflow reports Int arithmetic on constant operands whose
+// result does not fit in an Int. Int arithmetic wraps at runtime, but Go
+// folds constant expressions exactly, so these would not wrap and a wrapped
+// constant is almost always a mistake anyway. Only the innermost overflowing
+// expression is reported.
+func (c *Checker) checkConstantOverflow(expr Expression, location parse.Location, operands ...Expression) Expression {
+	if expr.Type() != Int {
+		return expr
+	}
+	for _, operand := range operands {
+		if _, err := evalConstant(operand); err != nil {
+			return expr
+		}
+	}
+	if _, err := evalConstant(expr); errors.Is(err, errConstantOverflow) {
+		c.addDiagnostic(constantOverflowDiagnostic{Span: c.sourceSpan(location)}.build())
+	}
+	return expr
+}
+
+// evalConstant computes expr at compile time. It accepts literals, references
+// to other constants (already folded to literals), negation, Int and Float
+// arithmetic, and Str concatenation.
+func evalConstant(expr Expression) (Expression, error) {
+	switch e := expr.(type) {
+	case *IntLiteral, *FloatLiteral, *StrLiteral, *BoolLiteral:
+		return e, nil
+	case *Negation:
+		value, err := evalConstant(e.Value)
+		if err != nil {
+			return nil, err
+		}
+		switch v := value.(type) {
+		case *IntLiteral:
+			if v.Value == math.MinInt {
+				return nil, errConstantOverflow
+			}
+			return &IntLiteral{Value: -v.Value}, nil
+		case *FloatLiteral:
+			return &FloatLiteral{Value: -v.Value}, nil
+		}
+	case *IntAddition:
+		return evalIntConstant(e.Left, e.Right, func(a, b int) (int, error) {
+			if (b > 0 && a > math.MaxInt-b) || (b < 0 && a < math.MinInt-b) {
+				return 0, errConstantOverflow
+			}
+			return a + b, nil
+		})
+	case *IntSubtraction:
+		return evalIntConstant(e.Left, e.Right, func(a, b int) (int, error) {
+			if (b < 0 && a > math.MaxInt+b) || (b > 0 && a < math.MinInt+b) {
+				return 0, errConstantOverflow
+			}
+			return a - b, nil
+		})
+	case *IntMultiplication:
+		return evalIntConstant(e.Left, e.Right, func(a, b int) (int, error) {
+			if a != 0 && b != 0 && ((a == -1 && b == math.MinInt) || (b == -1 && a == math.MinInt) || (a*b)/b != a) {
+				return 0, errConstantOverflow
+			}
+			return a * b, nil
+		})
+	case *IntDivision:
+		return evalIntConstant(e.Left, e.Right, func(a, b int) (int, error) {
+			if b == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			if a == math.MinInt && b == -1 {
+				return 0, errConstantOverflow
+			}
+			return a / b, nil
+		})
+	case *IntModulo:
+		return evalIntConstant(e.Left, e.Right, func(a, b int) (int, error) {
+			if b == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			return a % b, nil
+		})
+	case *FloatAddition:
+		return evalFloatConstant(e.Left, e.Right, func(a, b float64) float64 { return a + b })
+	case *FloatSubtraction:
+		return evalFloatConstant(e.Left, e.Right, func(a, b float64) float64 { return a - b })
+	case *FloatMultiplication:
+		return evalFloatConstant(e.Left, e.Right, func(a, b float64) float64 { return a * b })
+	case *FloatDivision:
+		return evalFloatConstant(e.Left, e.Right, func(a, b float64) float64 { return a / b })
+	case *StrAddition:
+		left, err := evalConstant(e.Left)
+		if err != nil {
+			return nil, err
+		}
+		right, err := evalConstant(e.Right)
+		if err != nil {
+			return nil, err
+		}
+		l, lok := left.(*StrLiteral)
+		r, rok := right.(*StrLiteral)
+		if lok && rok {
+			return &StrLiteral{Value: l.Value + r.Value}, nil
+		}
+	case *TemplateStr:
+		var out strings.Builder
+		for _, chunk := range e.Chunks {
+			literal, ok := chunk.(*StrLiteral)
+			if !ok {
+				return nil, fmt.Errorf("only Str constants can be interpolated")
+			}
+			out.WriteString(literal.Value)
+		}
+		return &StrLiteral{Value: out.String()}, nil
+	}
+	return nil, fmt.Errorf("only literals, constants, arithmetic, and string concatenation are allowed")
+}
+
+func evalIntConstant(left, right Expression, op func(a, b int) (int, error)) (Expression, error) {
+	l, r, err := evalConstantOperands[*IntLiteral](left, right)
+	if err != nil {
+		return nil, err
+	}
+	value, err := op(l.Value, r.Value)
+	if err != nil {
+		return nil, err
+	}
+	return &IntLiteral{Value: value}, nil
+}
+
+func evalFloatConstant(left, right Expression, op func(a, b float64) float64) (Expression, error) {
+	l, r, err := evalConstantOperands[*FloatLiteral](left, right)
+	if err != nil {
+		return nil, err
+	}
+	return &FloatLiteral{Value: op(l.Value, r.Value)}, nil
+}
+
+func evalConstantOperands[T Expression](left, right Expression) (T, T, error) {
+	var zero T
+	l, err := evalConstant(left)
+	if err != nil {
+		return zero, zero, err
+	}
+	r, err := evalConstant(right)
+	if err != nil {
+		return zero, zero, err
+	}
+	lv, lok := l.(T)
+	rv, rok := r.(T)
+	if !lok || !rok {
+		return zero, zero, fmt.Errorf("operands must be constants of the same type")
+	}
+	return lv, rv, nil
+}
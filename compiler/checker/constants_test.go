@@ -0,0 +1,109 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestConstants(t *testing.T) {
+	run(t, []test{
+		{
+			name: "constants fold literals, arithmetic, and concatenation",
+			input: `const MAX = 100
+const HALF = MAX / 2
+const RATE = -1.5 * 2.0
+const PREFIX = "v"
+const VERSION = PREFIX + "1." + "{PREFIX}"
+const DEBUG = false
+
+fn main() {
+  let total: Int = HALF + 1
+}`,
+		},
+		{
+			name: "constants can be used as match patterns and range bounds",
+			input: `const LOW = 10
+const HIGH = LOW * 10
+
+fn grade(n: Int) Str {
+  match n {
+    0..LOW => "low",
+    HIGH => "max",
+    _ => "mid",
+  }
+}`,
+		},
+		{
+			name: "initializers must be computable at compile time",
+			input: `fn one() Int { 1 }
+const ONE = one()`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Constant ONE is not computable at compile time: only literals, constants, arithmetic, and string concatenation are allowed"},
+			},
+		},
+		{
+			name:  "constant division by zero is rejected",
+			input: `const BAD = 1 % 0`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Constant BAD is not computable at compile time: division by zero"},
+			},
+		},
+		{
+			name: "constant arithmetic that overflows Int is rejected",
+			input: `const BIG = 9223372036854775807
+const LOWEST = -BIG - 1
+const NEXT = BIG + 1
+const SQUARE = BIG * 2
+const FLIPPED = -LOWEST`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Constant expression overflows Int"},
+				{Kind: checker.Error, Message: "Constant expression overflows Int"},
+				{Kind: checker.Error, Message: "Constant expression overflows Int"},
+			},
+		},
+		{
+			name: "overflow is reported once for nested constant arithmetic",
+			input: `const BIG = 9223372036854775807
+
+fn main() {
+  let wrapped = BIG + 1 + 1
+  let runtime = BIG
+  let fine = runtime + 1
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Constant expression overflows Int"},
+			},
+		},
+		{
+			name:  "constants must have a primitive type",
+			input: `const NAMES = ["a"]`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Constant NAMES must be Int, Float64, Str, or Bool, not [Str]"},
+			},
+		},
+		{
+			name: "constants must be declared at the module level",
+			input: `fn main() {
+  const LIMIT = 3
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Constants must be declared at the module level"},
+			},
+		},
+		{
+			name: "non-constant identifiers are not match patterns",
+			input: `let limit = 3
+
+fn main() {
+  match 3 {
+    limit => (),
+    _ => (),
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid pattern for Int match: limit is not an Int constant"},
+			},
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package checker_test
+
+import "testing"
+
+func TestExpectedTypeInfersCallGenerics(t *testing.T) {
+	run(t, []test{
+		{
+			name: "declared binding",
+			input: `use ard/list
+let xs: [Int] = list::new()`,
+		},
+		{
+			name: "map literal value",
+			input: `use ard/list
+let groups: [Str:[Int]] = ["a": list::new()]`,
+		},
+		{
+			name: "Result::ok argument",
+			input: `use ard/list
+let items: [Int]!Str = Result::ok(list::new())`,
+		},
+		{
+			name: "generic struct field from the declared type",
+			input: `use ard/list
+struct Box<$T> {
+  value: $T,
+}
+let b: Box<[Int]> = Box{value: list::new()}`,
+		},
+		{
+			name: "generic struct field from explicit type args",
+			input: `use ard/list
+struct Box<$T> {
+  value: $T,
+}
+let b = Box<[Int]>{value: list::new()}`,
+		},
+		{
+			name: "function return type",
+			input: `use ard/list
+fn empty() [Str] {
+  list::new()
+}`,
+		},
+	})
+}
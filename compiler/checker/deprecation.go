@@ -0,0 +1,41 @@
+package checker
+
+import "github.com/akonwi/ard/parse"
+
+// deprecationNote returns the note of a `@deprecated` attribute, or "" for an
+// undeprecated declaration.
+func deprecationNote(deprecation *parse.Deprecation) string {
+	if deprecation == nil {
+		return ""
+	}
+	return deprecation.Note
+}
+
+// warnDeprecatedCall reports a call site of a `@deprecated` function.
+func (c *Checker) warnDeprecatedCall(fnDef *FunctionDef, loc parse.Location) {
+	if fnDef == nil || fnDef.Deprecated == "" {
+		return
+	}
+	c.warnings = append(c.warnings, deprecatedUseDiagnostic{
+		Kind: "function",
+		Name: fnDef.Name,
+		Note: fnDef.Deprecated,
+		Span: c.sourceSpan(loc),
+	}.build())
+}
+
+// warnDeprecatedStruct reports an instantiation of a `@deprecated` struct.
+func (c *Checker) warnDeprecatedStruct(structDef *StructDef, loc parse.Location) {
+	if structDef.Definition != nil {
+		structDef = structDef.Definition
+	}
+	if structDef.Deprecated == "" {
+		return
+	}
+	c.warnings = append(c.warnings, deprecatedUseDiagnostic{
+		Kind: "struct",
+		Name: structDef.Name,
+		Note: structDef.Deprecated,
+		Span: c.sourceSpan(loc),
+	}.build())
+}
@@ -0,0 +1,78 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+func TestDeprecatedDeclarationsWarnAtUseSites(t *testing.T) {
+	const filePath = "main.ard"
+	input := `@deprecated("use add")
+fn plus(a: Int, b: Int) Int { a + b }
+
+fn add(a: Int, b: Int) Int { a + b }
+
+@deprecated("use Point")
+struct Pt { x: Int }
+
+struct Point { x: Int }
+
+impl Point {
+  @deprecated("read x directly")
+  fn get_x() Int { self.x }
+}
+
+@deprecated("use Point")
+fn Point::zero() Point { Point{x: 0} }
+
+fn main() Int {
+  let p = Pt{x: 1}
+  let q = Point::zero()
+  plus(p.x, q.get_x()) + add(1, 2)
+}`
+	result := parse.Parse([]byte(input), filePath)
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	c := checker.New(filePath, result.Program, nil)
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("deprecations must not be errors: %v", c.Diagnostics())
+	}
+	want := []string{
+		"Deprecated struct: Pt: use Point",
+		"Deprecated function: Point::zero: use Point",
+		"Deprecated function: plus: use add",
+		"Deprecated function: get_x: read x directly",
+	}
+	warnings := c.Warnings()
+	if len(warnings) != len(want) {
+		t.Fatalf("warnings = %v, want %q", warnings, want)
+	}
+	for i, warning := range warnings {
+		if warning.Message != want[i] || warning.Kind != checker.Warn || warning.Code != checker.DiagnosticCodeDeprecatedUse {
+			t.Fatalf("warning %d = %#v, want %q", i, warning, want[i])
+		}
+	}
+	if warnings[2].Help != "use add" || warnings[2].Primary.Span.Location.Start != (parse.Point{Row: 22, Col: 3}) {
+		t.Fatalf("plus warning = %#v", warnings[2])
+	}
+}
+
+func TestDenyWarningsRejectsDeprecatedUses(t *testing.T) {
+	const filePath = "main.ard"
+	result := parse.Parse([]byte("#deny(warnings)\n\n@deprecated(\"use add\")\nfn plus(a: Int, b: Int) Int { a + b }\n\nlet x = plus(1, 2)\n"), filePath)
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	c := checker.New(filePath, result.Program, nil)
+	c.Check()
+	if len(c.Diagnostics()) != 1 || len(c.Warnings()) != 0 {
+		t.Fatalf("diagnostics = %v, warnings = %v", c.Diagnostics(), c.Warnings())
+	}
+	if diagnostic := c.Diagnostics()[0]; diagnostic.Kind != checker.Error || diagnostic.Code != checker.DiagnosticCodeDeprecatedUse {
+		t.Fatalf("diagnostic = %#v", diagnostic)
+	}
+}
@@ -102,6 +102,34 @@ func TestUndefinedMemberDiagnostic(t *testing.T) {
 	}
 }
 
+func TestPrivateMemberDiagnostic(t *testing.T) {
+	span := SourceSpan{FilePath: "main.ard", Location: parse.Location{Start: parse.Point{Row: 3, Col: 4}}}
+	tests := []struct {
+		name        string
+		kind        privateMemberKind
+		member      string
+		title       string
+		legacy      string
+		primaryText string
+	}{
+		{name: "declaration", kind: privateDeclaration, member: "bank::audit", title: "Private declaration", legacy: "Private: bank::audit", primaryText: "`bank::audit` is private to module `app/bank`"},
+		{name: "field", kind: privateField, member: "Account.balance", title: "Private field", legacy: "Private field: Account.balance", primaryText: "`Account.balance` is private to module `app/bank`"},
+		{name: "method", kind: privateMethod, member: "Account.reset", title: "Private method", legacy: "Private method: Account.reset", primaryText: "`Account.reset` is private to module `app/bank`"},
+		{name: "literal", kind: privateFieldsLiteral, member: "bank::Account", title: "Private fields", legacy: "Cannot construct bank::Account outside its module", primaryText: "`bank::Account` has private fields; only module `app/bank` can construct it"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostic := (privateMemberDiagnostic{Kind: tt.kind, Name: tt.member, Module: "app/bank", Span: span}).build()
+			if diagnostic.Code != DiagnosticCodePrivateMember || diagnostic.Title != tt.title || diagnostic.Message != tt.legacy {
+				t.Fatalf("code/title/message = %q/%q/%q", diagnostic.Code, diagnostic.Title, diagnostic.Message)
+			}
+			if diagnostic.Primary.Span != span || diagnostic.Primary.Message != tt.primaryText {
+				t.Fatalf("primary = %#v", diagnostic.Primary)
+			}
+		})
+	}
+}
+
 func TestDuplicateDeclarationDiagnosticBuildsBothLabels(t *testing.T) {
 	original := SourceSpan{FilePath: "main.ard", Location: parse.Location{Start: parse.Point{Row: 1, Col: 8}}}
 	duplicate := SourceSpan{FilePath: "main.ard", Location: parse.Location{Start: parse.Point{Row: 2, Col: 6}}}
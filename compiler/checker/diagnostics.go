@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/akonwi/ard/parse"
+	"github.com/akonwi/ard/version"
 )
 
 type DiagnosticKind string
@@ -127,6 +128,23 @@ const (
 	DiagnosticCodeInvalidLiteral                DiagnosticCode = "invalid_literal"
 	DiagnosticCodeNumericLiteralOverflow        DiagnosticCode = "numeric_literal_overflow"
 	DiagnosticCodeInvalidConversion             DiagnosticCode = "invalid_conversion"
+	DiagnosticCodeDeprecatedSyntax              DiagnosticCode = "deprecated_syntax"
+	DiagnosticCodePrivateMember                 DiagnosticCode = "private_member"
+	DiagnosticCodeInvalidConstant               DiagnosticCode = "invalid_constant"
+	DiagnosticCodeConstantOverflow              DiagnosticCode = "constant_overflow"
+	DiagnosticCodeInvalidFieldDefault           DiagnosticCode = "invalid_field_default"
+	DiagnosticCodeInvalidFormat                 DiagnosticCode = "invalid_format"
+	DiagnosticCodeInvalidPragma                 DiagnosticCode = "invalid_pragma"
+	DiagnosticCodeUnusedImport                  DiagnosticCode = "unused_import"
+	DiagnosticCodeUnusedVariable                DiagnosticCode = "unused_variable"
+	DiagnosticCodeUnusedFunction                DiagnosticCode = "unused_function"
+	DiagnosticCodeShadowedVariable              DiagnosticCode = "shadowed_variable"
+	DiagnosticCodeUnreachableCode               DiagnosticCode = "unreachable_code"
+	DiagnosticCodeDeprecatedUse                 DiagnosticCode = "deprecated_use"
+	DiagnosticCodeForbiddenDynamic              DiagnosticCode = "forbidden_dynamic"
+	DiagnosticCodeForbiddenNondeterminism       DiagnosticCode = "forbidden_nondeterminism"
+	DiagnosticCodeNonBooleanAssertion           DiagnosticCode = "non_boolean_assertion"
+	DiagnosticCodeSyntaxError                   DiagnosticCode = "syntax_error"
 )
 
 type SourceSpan struct {
@@ -146,10 +164,15 @@ type Diagnostic struct {
 	Code    DiagnosticCode
 	Message string
 
-	Title     string
-	Text      string
+	Title string
+	Text  string
+	// Help is an optional suggestion for fixing the problem, rendered on its
+	// own "help:" line after Text.
+	Help      string
 	Primary   DiagnosticLabel
 	Secondary []DiagnosticLabel
+	// Fixes are machine-applicable edits that resolve the diagnostic.
+	Fixes []DiagnosticFix
 }
 
 func NewDiagnostic(kind DiagnosticKind, message string, filePath string, location parse.Location) Diagnostic {
@@ -204,12 +227,15 @@ const (
 	invalidStaticMember
 	undefinedStructType
 	notAStruct
+	selfOutsideType
 )
 
 type unresolvedReferenceDiagnostic struct {
 	Kind unresolvedReferenceKind
 	Name string
 	Span SourceSpan
+	// Suggestion is a similar known name, offered as "did you mean".
+	Suggestion string
 }
 
 func (d unresolvedReferenceDiagnostic) build() Diagnostic {
@@ -267,11 +293,15 @@ func (d unresolvedReferenceDiagnostic) build() Diagnostic {
 	case notAStruct:
 		code, message, title = DiagnosticCodeNotAStruct, "Undefined: "+d.Name, "Not a struct"
 		label = fmt.Sprintf("`%s` does not name a struct", d.Name)
+	case selfOutsideType:
+		code, message, title = DiagnosticCodeUndefinedType, "Self can only be used inside an impl block or a static function", "Self outside a type"
+		label = "`Self` has no type to refer to here"
 	default:
 		panic(fmt.Sprintf("unknown unresolved-reference kind: %d", d.Kind))
 	}
 	diagnostic := newLabeledDiagnostic(Error, message, title, "", DiagnosticLabel{Span: d.Span, Message: label})
 	diagnostic.Code = code
+	diagnostic.Help = didYouMean(d.Suggestion)
 	return diagnostic
 }
 
@@ -283,6 +313,17 @@ func (c *Checker) addUnresolvedReference(kind unresolvedReferenceKind, name stri
 	}.build())
 }
 
+// addUnknownStructField reports a struct literal field that is not one of
+// fields, suggesting the closest of them.
+func (c *Checker) addUnknownStructField(name string, fields []string, location parse.Location) {
+	c.addDiagnostic(unresolvedReferenceDiagnostic{
+		Kind:       unknownStructField,
+		Name:       name,
+		Span:       c.sourceSpan(location),
+		Suggestion: suggestName(name, fields),
+	}.build())
+}
+
 type undefinedNameKind uint8
 
 const (
@@ -294,6 +335,8 @@ type undefinedNameDiagnostic struct {
 	Kind undefinedNameKind
 	Name string
 	Span SourceSpan
+	// Suggestion is a similar name in scope, offered as "did you mean".
+	Suggestion string
 }
 
 func (d undefinedNameDiagnostic) build() Diagnostic {
@@ -317,9 +360,18 @@ func (d undefinedNameDiagnostic) build() Diagnostic {
 		},
 	)
 	diagnostic.Code = DiagnosticCodeUndefinedName
+	diagnostic.Help = didYouMean(d.Suggestion)
 	return diagnostic
 }
 
+// didYouMean is the help text offering suggestion, or "" without one.
+func didYouMean(suggestion string) string {
+	if suggestion == "" {
+		return ""
+	}
+	return fmt.Sprintf("did you mean `%s`?", suggestion)
+}
+
 type undefinedMemberKind uint8
 
 const (
@@ -332,6 +384,9 @@ type undefinedMemberDiagnostic struct {
 	Receiver string
 	Member   string
 	Span     SourceSpan
+	// Suggestion is a similar member of the receiver, offered as "did you
+	// mean".
+	Suggestion string
 }
 
 func (d undefinedMemberDiagnostic) build() Diagnostic {
@@ -355,6 +410,307 @@ func (d undefinedMemberDiagnostic) build() Diagnostic {
 		},
 	)
 	diagnostic.Code = DiagnosticCodeUndefinedMember
+	diagnostic.Help = didYouMean(d.Suggestion)
+	return diagnostic
+}
+
+type privateMemberKind uint8
+
+const (
+	privateDeclaration privateMemberKind = iota
+	privateField
+	privateMethod
+	privateFieldsLiteral
+)
+
+// privateMemberDiagnostic reports a reference from another module to a
+// declaration, field, or method marked `private`.
+type privateMemberDiagnostic struct {
+	Kind   privateMemberKind
+	Name   string
+	Module string
+	Span   SourceSpan
+}
+
+func (d privateMemberDiagnostic) build() Diagnostic {
+	var message, title, label string
+	switch d.Kind {
+	case privateDeclaration:
+		message, title = "Private: "+d.Name, "Private declaration"
+		label = fmt.Sprintf("`%s` is private to module `%s`", d.Name, d.Module)
+	case privateField:
+		message, title = "Private field: "+d.Name, "Private field"
+		label = fmt.Sprintf("`%s` is private to module `%s`", d.Name, d.Module)
+	case privateMethod:
+		message, title = "Private method: "+d.Name, "Private method"
+		label = fmt.Sprintf("`%s` is private to module `%s`", d.Name, d.Module)
+	case privateFieldsLiteral:
+		message, title = "Cannot construct "+d.Name+" outside its module", "Private fields"
+		label = fmt.Sprintf("`%s` has private fields; only module `%s` can construct it", d.Name, d.Module)
+	default:
+		panic(fmt.Sprintf("unknown private-member kind: %d", d.Kind))
+	}
+	diagnostic := newLabeledDiagnostic(Error, message, title, "", DiagnosticLabel{Span: d.Span, Message: label})
+	diagnostic.Code = DiagnosticCodePrivateMember
+	return diagnostic
+}
+
+type invalidConstantKind uint8
+
+const (
+	constantNotTopLevel invalidConstantKind = iota
+	unsupportedConstantType
+	nonConstantInitializer
+)
+
+// invalidConstantDiagnostic reports a `const` declaration whose value cannot
+// be computed at compile time.
+type invalidConstantDiagnostic struct {
+	Kind   invalidConstantKind
+	Name   string
+	Reason string
+	Span   SourceSpan
+}
+
+func (d invalidConstantDiagnostic) build() Diagnostic {
+	var message, title, label string
+	switch d.Kind {
+	case constantNotTopLevel:
+		message, title = "Constants must be declared at the module level", "Constant must be top-level"
+		label = fmt.Sprintf("move `%s` to the module level or use `let`", d.Name)
+	case unsupportedConstantType:
+		message, title = fmt.Sprintf("Constant %s must be Int, Float64, Str, or Bool, not %s", d.Name, d.Reason), "Unsupported constant type"
+		label = fmt.Sprintf("this has type `%s`", d.Reason)
+	case nonConstantInitializer:
+		message, title = fmt.Sprintf("Constant %s is not computable at compile time: %s", d.Name, d.Reason), "Non-constant initializer"
+		label = d.Reason
+	default:
+		panic(fmt.Sprintf("unknown invalid-constant kind: %d", d.Kind))
+	}
+	diagnostic := newLabeledDiagnostic(Error, message, title, "", DiagnosticLabel{Span: d.Span, Message: label})
+	diagnostic.Code = DiagnosticCodeInvalidConstant
+	return diagnostic
+}
+
+// constantOverflowDiagnostic reports Int arithmetic on constant operands
+// whose result does not fit in an Int.
+type constantOverflowDiagnostic struct {
+	Span SourceSpan
+}
+
+func (d constantOverflowDiagnostic) build() Diagnostic {
+	diagnostic := newLabeledDiagnostic(Error, "Constant expression overflows Int", "Constant overflow",
+		"Int arithmetic wraps at runtime, but constant expressions must fit in an Int.",
+		DiagnosticLabel{Span: d.Span, Message: "this does not fit in an Int"})
+	diagnostic.Code = DiagnosticCodeConstantOverflow
+	return diagnostic
+}
+
+// invalidFieldDefaultDiagnostic reports a struct field default that is not a
+// compile-time constant of a supported type.
+type invalidFieldDefaultDiagnostic struct {
+	Field       string
+	Unsupported bool
+	Reason      string
+	Span        SourceSpan
+}
+
+func (d invalidFieldDefaultDiagnostic) build() Diagnostic {
+	var message, label string
+	if d.Unsupported {
+		message = fmt.Sprintf("Default for field %s must be Int, Float64, Str, Bool, or an enum, not %s", d.Field, d.Reason)
+		label = fmt.Sprintf("`%s` has type `%s`", d.Field, d.Reason)
+	} else {
+		message = fmt.Sprintf("Default for field %s is not computable at compile time: %s", d.Field, d.Reason)
+		label = d.Reason
+	}
+	diagnostic := newLabeledDiagnostic(Error, message, "Invalid field default", "", DiagnosticLabel{Span: d.Span, Message: label})
+	diagnostic.Code = DiagnosticCodeInvalidFieldDefault
+	return diagnostic
+}
+
+// invalidFormatDiagnostic reports a Str::format call whose literal template
+// does not fit its arguments.
+type invalidFormatDiagnostic struct {
+	Reason string
+	Label  string
+	Span   SourceSpan
+}
+
+func (d invalidFormatDiagnostic) build() Diagnostic {
+	diagnostic := newLabeledDiagnostic(Error, "Invalid format string: "+d.Reason, "Invalid format string", "", DiagnosticLabel{Span: d.Span, Message: d.Label})
+	diagnostic.Code = DiagnosticCodeInvalidFormat
+	return diagnostic
+}
+
+// invalidPragmaDiagnostic reports a `#name(...)` directive the checker does
+// not recognize.
+type invalidPragmaDiagnostic struct {
+	Reason string
+	Label  string
+	Span   SourceSpan
+}
+
+func (d invalidPragmaDiagnostic) build() Diagnostic {
+	diagnostic := newLabeledDiagnostic(Error, "Invalid pragma: "+d.Reason, "Invalid pragma", "supported pragmas are `#deny(warnings)` and `#forbid(dynamic)`", DiagnosticLabel{Span: d.Span, Message: d.Label})
+	diagnostic.Code = DiagnosticCodeInvalidPragma
+	return diagnostic
+}
+
+// unusedImportDiagnostic reports an import nothing in a `#deny(warnings)` or
+// strictly checked module refers to.
+type unusedImportDiagnostic struct {
+	Name string
+	Span SourceSpan
+}
+
+func (d unusedImportDiagnostic) build() Diagnostic {
+	legacy := fmt.Sprintf("Unused import: %s", d.Name)
+	diagnostic := newLabeledDiagnostic(Warn, legacy, "Unused import", "", DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("`%s` is never used", d.Name)})
+	diagnostic.Code = DiagnosticCodeUnusedImport
+	return diagnostic
+}
+
+// unusedVariableDiagnostic reports a local binding that `ard check --strict`
+// found nothing reading.
+type unusedVariableDiagnostic struct {
+	Name string
+	Span SourceSpan
+}
+
+func (d unusedVariableDiagnostic) build() Diagnostic {
+	legacy := fmt.Sprintf("Unused variable: %s", d.Name)
+	diagnostic := newLabeledDiagnostic(Warn, legacy, "Unused variable", "", DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("`%s` is never read", d.Name)})
+	diagnostic.Help = "prefix the name with `_` if the value is intentionally unused"
+	diagnostic.Code = DiagnosticCodeUnusedVariable
+	return diagnostic
+}
+
+// unusedFunctionDiagnostic reports a private function nothing in its module
+// refers to.
+type unusedFunctionDiagnostic struct {
+	Name string
+	Span SourceSpan
+}
+
+func (d unusedFunctionDiagnostic) build() Diagnostic {
+	legacy := fmt.Sprintf("Unused private function: %s", d.Name)
+	diagnostic := newLabeledDiagnostic(Warn, legacy, "Unused private function", "", DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("`%s` is never called", d.Name)})
+	diagnostic.Code = DiagnosticCodeUnusedFunction
+	return diagnostic
+}
+
+// shadowedVariableDiagnostic reports a local binding that `ard check --strict`
+// found hiding a binding of an enclosing block.
+type shadowedVariableDiagnostic struct {
+	Name         string
+	Span         SourceSpan
+	ShadowedSpan *SourceSpan
+}
+
+func (d shadowedVariableDiagnostic) build() Diagnostic {
+	legacy := fmt.Sprintf("Shadowed variable: %s", d.Name)
+	var secondary []DiagnosticLabel
+	if d.ShadowedSpan != nil {
+		secondary = append(secondary, DiagnosticLabel{Span: *d.ShadowedSpan, Message: fmt.Sprintf("outer `%s` is declared here", d.Name)})
+	}
+	diagnostic := newLabeledDiagnostic(Warn, legacy, "Shadowed variable", "", DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("`%s` hides a binding of an enclosing block", d.Name)}, secondary...)
+	diagnostic.Help = "rename the inner binding so both values stay reachable"
+	diagnostic.Code = DiagnosticCodeShadowedVariable
+	return diagnostic
+}
+
+// unreachableCodeDiagnostic reports statements that follow a `break` or a
+// `panic` in the same block.
+type unreachableCodeDiagnostic struct {
+	Exit     string
+	Span     SourceSpan
+	ExitSpan SourceSpan
+}
+
+func (d unreachableCodeDiagnostic) build() Diagnostic {
+	diagnostic := newLabeledDiagnostic(
+		Warn,
+		fmt.Sprintf("Unreachable code after %s", d.Exit),
+		"Unreachable code",
+		"",
+		DiagnosticLabel{Span: d.Span, Message: "this code never runs"},
+		DiagnosticLabel{Span: d.ExitSpan, Message: fmt.Sprintf("any code following this %s is unreachable", d.Exit)},
+	)
+	diagnostic.Code = DiagnosticCodeUnreachableCode
+	return diagnostic
+}
+
+// deprecatedUseDiagnostic reports a call to a `@deprecated` function or an
+// instantiation of a `@deprecated` struct. The attribute's note, which usually
+// names the replacement, becomes the help text.
+type deprecatedUseDiagnostic struct {
+	Kind string
+	Name string
+	Note string
+	Span SourceSpan
+}
+
+func (d deprecatedUseDiagnostic) build() Diagnostic {
+	legacy := fmt.Sprintf("Deprecated %s: %s: %s", d.Kind, d.Name, d.Note)
+	diagnostic := newLabeledDiagnostic(Warn, legacy, "Deprecated "+d.Kind, "", DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("`%s` is deprecated", d.Name)})
+	diagnostic.Help = d.Note
+	diagnostic.Code = DiagnosticCodeDeprecatedUse
+	return diagnostic
+}
+
+// forbiddenDynamicDiagnostic reports an `Any` type or value in a module that
+// declares `#forbid(dynamic)`.
+type forbiddenDynamicDiagnostic struct {
+	Label      string
+	Span       SourceSpan
+	PragmaSpan SourceSpan
+}
+
+func (d forbiddenDynamicDiagnostic) build() Diagnostic {
+	diagnostic := newLabeledDiagnostic(
+		Error,
+		"Dynamic values are forbidden in this module",
+		"Forbidden dynamic value",
+		"convert foreign values to a concrete type at the module boundary",
+		DiagnosticLabel{Span: d.Span, Message: d.Label},
+		DiagnosticLabel{Span: d.PragmaSpan, Message: "`Any` is forbidden here"},
+	)
+	diagnostic.Code = DiagnosticCodeForbiddenDynamic
+	return diagnostic
+}
+
+type forbiddenNondeterminismDiagnostic struct {
+	// Source is the Go package the import reaches, and Via the Ard module it
+	// reaches it through; Via is empty for a direct Go import.
+	Source     string
+	Via        string
+	Span       SourceSpan
+	PragmaSpan SourceSpan
+}
+
+func (d forbiddenNondeterminismDiagnostic) build() Diagnostic {
+	label := fmt.Sprintf("`go:%s` is not known to be deterministic", d.Source)
+	if d.Via != "" {
+		label = fmt.Sprintf("`%s` uses `go:%s`, which is not known to be deterministic", d.Via, d.Source)
+	}
+	diagnostic := newLabeledDiagnostic(
+		Error,
+		"Nondeterministic imports are forbidden in this module",
+		"Forbidden nondeterministic import",
+		"pass clocks, random numbers, files, and network access in as function values from a module that allows them",
+		DiagnosticLabel{Span: d.Span, Message: label},
+		DiagnosticLabel{Span: d.PragmaSpan, Message: "nondeterminism is forbidden here"},
+	)
+	diagnostic.Code = DiagnosticCodeForbiddenNondeterminism
+	return diagnostic
+}
+
+// deniedWarning turns a warning into an error for a module that declares
+// `#deny(warnings)`, pointing at the pragma.
+func deniedWarning(diagnostic Diagnostic, pragmaSpan SourceSpan) Diagnostic {
+	diagnostic.Kind = Error
+	diagnostic.Secondary = append(diagnostic.Secondary, DiagnosticLabel{Span: pragmaSpan, Message: "warnings are denied here"})
 	return diagnostic
 }
 
@@ -1385,6 +1741,16 @@ func (d invalidRangeDiagnostic) build() Diagnostic {
 	return diagnostic
 }
 
+type zeroRangeStepDiagnostic struct {
+	Span SourceSpan
+}
+
+func (d zeroRangeStepDiagnostic) build() Diagnostic {
+	diagnostic := newLabeledDiagnostic(Error, "Range step cannot be 0", "Invalid range", "", DiagnosticLabel{Span: d.Span, Message: "a step of 0 never reaches the end of the range"})
+	diagnostic.Code = DiagnosticCodeInvalidRange
+	return diagnostic
+}
+
 type unsupportedIterationDiagnostic struct {
 	Actual        Type
 	Span          SourceSpan
@@ -1437,6 +1803,10 @@ func (d invalidArithmeticDiagnostic) build() Diagnostic {
 	}
 	diagnostic := newLabeledDiagnostic(Error, d.LegacyMessage, title, "", primary, secondary)
 	diagnostic.Code = DiagnosticCodeInvalidArithmeticOperation
+	// Int and Float64 never widen implicitly; point at the explicit conversions
+	if (d.LeftType == Int && d.RightType == Float64) || (d.LeftType == Float64 && d.RightType == Int) {
+		diagnostic.Help = "convert explicitly with `.to_float()` on the Int, or `.to_int()`, `.round()`, `.floor()`, or `.ceil()` on the Float64"
+	}
 	return diagnostic
 }
 
@@ -1527,6 +1897,19 @@ func (d nonBooleanIfConditionDiagnostic) build() Diagnostic {
 	return diagnostic
 }
 
+type nonBooleanAssertionDiagnostic struct {
+	Keyword string
+	Actual  Type
+	Span    SourceSpan
+}
+
+func (d nonBooleanAssertionDiagnostic) build() Diagnostic {
+	legacy := fmt.Sprintf("%s%s conditions must be boolean expressions", strings.ToUpper(d.Keyword[:1]), d.Keyword[1:])
+	diagnostic := newLabeledDiagnostic(Error, legacy, "Invalid "+d.Keyword+" condition", "", DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("expected `Bool`, but found `%s`", d.Actual)})
+	diagnostic.Code = DiagnosticCodeNonBooleanAssertion
+	return diagnostic
+}
+
 type invalidMatchPatternDiagnostic struct {
 	LegacyMessage string
 	Span          SourceSpan
@@ -1619,6 +2002,45 @@ func (d ignoredMatchPatternDiagnostic) build() Diagnostic {
 	return diagnostic
 }
 
+type deprecatedSyntaxDiagnostic struct {
+	Legacy parse.LegacySyntax
+	Span   SourceSpan
+}
+
+func (d deprecatedSyntaxDiagnostic) build() Diagnostic {
+	removedIn := version.LanguageString(d.Legacy.RemovedIn)
+	legacy := fmt.Sprintf("Deprecated syntax: %s", d.Legacy.Error.Message)
+	text := fmt.Sprintf("this spelling is rejected from language %s", removedIn)
+	diagnostic := newLabeledDiagnostic(Warn, legacy, "Deprecated syntax", text, DiagnosticLabel{Span: d.Span, Message: d.Legacy.Error.Message})
+	diagnostic.Code = DiagnosticCodeDeprecatedSyntax
+	diagnostic.Help = "run `ard migrate` to rewrite it"
+	return diagnostic
+}
+
+// ParseErrorDiagnostics converts parse errors into diagnostics so they render
+// like the checker's own.
+func ParseErrorDiagnostics(filePath string, errors []parse.ParseError) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(errors))
+	for _, err := range errors {
+		span := SourceSpan{FilePath: filePath, Location: err.Location}
+		diagnostic := newLabeledDiagnostic(Error, err.Message, "Syntax error", "", DiagnosticLabel{Span: span, Message: err.Message})
+		diagnostic.Code = DiagnosticCodeSyntaxError
+		diagnostics = append(diagnostics, diagnostic)
+	}
+	return diagnostics
+}
+
+// DeprecatedSyntaxDiagnostics reports legacy spellings a project's pinned
+// language version still accepts (see parse.ParseResult.AllowLegacy).
+func DeprecatedSyntaxDiagnostics(filePath string, legacy []parse.LegacySyntax) []Diagnostic {
+	diagnostics := make([]Diagnostic, 0, len(legacy))
+	for _, item := range legacy {
+		span := SourceSpan{FilePath: filePath, Location: item.Error.Location}
+		diagnostics = append(diagnostics, deprecatedSyntaxDiagnostic{Legacy: item, Span: span}.build())
+	}
+	return diagnostics
+}
+
 type nonBooleanMatchConditionDiagnostic struct {
 	Actual Type
 	Span   SourceSpan
@@ -1765,17 +2187,26 @@ func (d nonExhaustiveValueIfDiagnostic) build() Diagnostic {
 
 type invalidMapKeyTypeDiagnostic struct {
 	KeyType Type
+	// Generic is set when a call binds KeyType to a generic the callee uses
+	// as a map key.
+	Generic string
 	Span    SourceSpan
 }
 
 func (d invalidMapKeyTypeDiagnostic) build() Diagnostic {
-	displayed := formatTypeForDisplay(d.KeyType)
+	displayed := formatType(d.KeyType)
+	message := fmt.Sprintf("Invalid map key type %s: map keys must be comparable (primitives, enums, or structs)", displayed)
+	label := fmt.Sprintf("`%s` cannot be used as a map key", displayed)
+	if d.Generic != "" {
+		message = fmt.Sprintf("Invalid map key type %s for %s: map keys must be comparable (primitives, enums, or structs)", displayed, d.Generic)
+		label = fmt.Sprintf("`%s` is used as a map key, so it cannot be `%s`", d.Generic, displayed)
+	}
 	diagnostic := newLabeledDiagnostic(
 		Error,
-		fmt.Sprintf("Invalid map key type %s: map keys must be comparable (primitives, enums, or structs)", displayed),
+		message,
 		"Invalid map key type",
 		"Map keys must be comparable primitives, enums, or structs.",
-		DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("`%s` cannot be used as a map key", displayed)},
+		DiagnosticLabel{Span: d.Span, Message: label},
 	)
 	diagnostic.Code = DiagnosticCodeInvalidMapKeyType
 	return diagnostic
@@ -1814,6 +2245,23 @@ func (d unresolvedGenericDiagnostic) build() Diagnostic {
 	return diagnostic
 }
 
+type uninferredGenericBindingDiagnostic struct {
+	Generic string
+	Span    SourceSpan
+}
+
+func (d uninferredGenericBindingDiagnostic) build() Diagnostic {
+	diagnostic := newLabeledDiagnostic(
+		Error,
+		fmt.Sprintf("cannot infer type for generic %s here", d.Generic),
+		"Unresolved generic",
+		"Nothing in the program determines this generic's type. Add a type annotation or explicit type arguments.",
+		DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("generic `%s` is never inferred", d.Generic)},
+	)
+	diagnostic.Code = DiagnosticCodeUnresolvedGeneric
+	return diagnostic
+}
+
 type unboundGenericTypeArgumentDiagnostic struct {
 	Name string
 	Span SourceSpan
@@ -2293,7 +2741,7 @@ type unexpectedListDiagnostic struct {
 func (d unexpectedListDiagnostic) build() Diagnostic {
 	diagnostic := newLabeledDiagnostic(
 		Error,
-		fmt.Sprintf("Expected %s but got a list", formatTypeForDisplay(d.Expected)),
+		fmt.Sprintf("Expected %s but got a list", formatType(d.Expected)),
 		"Unexpected list",
 		"",
 		DiagnosticLabel{Span: d.Span, Message: fmt.Sprintf("expected `%s`, but found a list", d.Expected)},
@@ -454,6 +454,16 @@ func TestOperatorDiagnosticsAreStructured(t *testing.T) {
 	}
 }
 
+func TestMixedIntFloatArithmeticSuggestsConversions(t *testing.T) {
+	result := parse.Parse([]byte("let n = 2\nn * 1.5\n"), "main.ard")
+	c := checker.New("main.ard", result.Program, nil)
+	c.Check()
+	diagnostic := requireDiagnosticCode(t, c.Diagnostics(), checker.DiagnosticCodeInvalidArithmeticOperation)
+	if !strings.Contains(diagnostic.Help, "`.to_float()`") {
+		t.Fatalf("diagnostic help = %q", diagnostic.Help)
+	}
+}
+
 func TestOperatorDiagnosticLabelSpans(t *testing.T) {
 	result := parse.Parse([]byte("-true\n1 + \"two\"\n1 < 2 == 1\n"), "main.ard")
 	unary := result.Program.Statements[0].(*parse.UnaryExpression)
@@ -770,6 +780,51 @@ func TestExplicitGenericConflictUsesMatchingGenericOrigin(t *testing.T) {
 	}
 }
 
+func TestGenericMismatchNotesBindingProvenance(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		code   checker.DiagnosticCode
+		note   string
+	}{
+		{
+			name:   "conflicting argument",
+			source: "fn pick(a: $T, b: Int, c: $T) $T { a }\npick(1, 2, \"three\")\n",
+			code:   checker.DiagnosticCodeIncorrectArgumentType,
+			note:   "note: `$T` was bound to `Int` because of argument 1 at main.ard:2:6",
+		},
+		{
+			name:   "mismatch inside a later argument",
+			source: "fn both(a: $T, b: $T) [$T] { [a, b] }\nboth([1], [\"x\"])\n",
+			code:   checker.DiagnosticCodeTypeMismatch,
+			note:   "note: `$T` was bound to `[Int]` because of argument 1 at main.ard:2:6",
+		},
+		{
+			name:   "expected return type",
+			source: "fn both(a: $T, b: $T) [$T] { [a, b] }\nlet pair: [Str] = both(1, 2)\n",
+			code:   checker.DiagnosticCodeTypeMismatch,
+			note:   "note: `$T` was bound to `Int` because of argument 1 at main.ard:2:24",
+		},
+		{
+			name:   "explicit type argument",
+			source: "fn identity(of: $T) $T { of }\nidentity<Str>(1)\n",
+			code:   checker.DiagnosticCodeIncorrectArgumentType,
+			note:   "note: `$T` was bound to `Str` because of the explicit type argument at main.ard:2:10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parse.Parse([]byte(tt.source), "main.ard")
+			c := checker.New("main.ard", result.Program, nil)
+			c.Check()
+			diagnostic := requireDiagnosticCode(t, c.Diagnostics(), tt.code)
+			if diagnostic.Text != tt.note {
+				t.Fatalf("note = %q, want %q", diagnostic.Text, tt.note)
+			}
+		})
+	}
+}
+
 func TestStringTypeMismatchSpansRawSourceLiteral(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -1454,6 +1509,60 @@ func TestIncorrectFunctionArgumentHasStructuredLabels(t *testing.T) {
 	}
 }
 
+func TestUndefinedNamesSuggestSimilarNames(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		code  checker.DiagnosticCode
+		help  string
+	}{
+		{
+			name:  "variable",
+			input: "fn main() Int {\n  let length = 3\n  lenght\n}\n",
+			code:  checker.DiagnosticCodeUndefinedName,
+			help:  "did you mean `length`?",
+		},
+		{
+			name:  "function",
+			input: "fn greet() {}\n\nfn main() {\n  gret()\n}\n",
+			code:  checker.DiagnosticCodeUndefinedName,
+			help:  "did you mean `greet`?",
+		},
+		{
+			name:  "struct literal field",
+			input: "struct Person {\n  name: Str,\n}\n\nlet p = Person{nme: \"a\"}\n",
+			code:  checker.DiagnosticCodeUnknownField,
+			help:  "did you mean `name`?",
+		},
+		{
+			name:  "field access",
+			input: "struct Person {\n  age: Int,\n}\n\nlet p = Person{age: 1}\nlet years = p.agee\n",
+			code:  checker.DiagnosticCodeUndefinedMember,
+			help:  "did you mean `age`?",
+		},
+		{
+			name:  "nothing similar",
+			input: "fn main() Int {\n  let length = 3\n  total\n}\n",
+			code:  checker.DiagnosticCodeUndefinedName,
+			help:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parse.Parse([]byte(tt.input), "main.ard")
+			if len(result.Errors) > 0 {
+				t.Fatalf("parse errors: %v", result.Errors)
+			}
+			c := checker.New("main.ard", result.Program, nil)
+			c.Check()
+			diagnostic := requireDiagnosticCode(t, c.Diagnostics(), tt.code)
+			if diagnostic.Help != tt.help {
+				t.Fatalf("help = %q, want %q", diagnostic.Help, tt.help)
+			}
+		})
+	}
+}
+
 func requireDiagnosticCode(t *testing.T, diagnostics []checker.Diagnostic, code checker.DiagnosticCode) checker.Diagnostic {
 	t.Helper()
 	for _, diagnostic := range diagnostics {
@@ -2242,6 +2351,43 @@ func TestDuplicateTopLevelTypesPointBackToFirstDeclaration(t *testing.T) {
 	}
 }
 
+func TestDuplicateTopLevelFunctionsPointBackToFirstDeclaration(t *testing.T) {
+	source := "struct User {}\nfn load() Int { 1 }\nfn User::guest() User { User{} }\nfn load() Int { 2 }\nfn User::guest() User { User{} }\nfn User() {}\n"
+	result := parse.Parse([]byte(source), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	structName := result.Program.Statements[0].(*parse.StructDefinition).Name.GetLocation()
+	load := result.Program.Statements[1].(*parse.FunctionDeclaration).NameLocation
+	guest := result.Program.Statements[2].(*parse.StaticFunctionDeclaration).NameLocation
+
+	c := checker.New("main.ard", result.Program, nil)
+	c.Check()
+	want := []struct {
+		name     string
+		row      int
+		original parse.Location
+	}{
+		{name: "load", row: 4, original: load},
+		{name: "User::guest", row: 5, original: guest},
+		{name: "User", row: 6, original: structName},
+	}
+	if len(c.Diagnostics()) != len(want) {
+		t.Fatalf("diagnostics = %#v, want %d", c.Diagnostics(), len(want))
+	}
+	for i, diagnostic := range c.Diagnostics() {
+		if diagnostic.Code != checker.DiagnosticCodeDuplicateDeclaration || diagnostic.Message != "Duplicate declaration: "+want[i].name {
+			t.Fatalf("diagnostic %d = %q (%s)", i, diagnostic.Message, diagnostic.Code)
+		}
+		if diagnostic.Primary.Span.Location.Start.Row != want[i].row {
+			t.Fatalf("diagnostic %d primary = %#v", i, diagnostic.Primary.Span)
+		}
+		if len(diagnostic.Secondary) != 1 || diagnostic.Secondary[0].Span.Location != want[i].original {
+			t.Fatalf("diagnostic %d secondary = %#v, want first declaration", i, diagnostic.Secondary)
+		}
+	}
+}
+
 func TestAnnotatedBindingTypeMismatchHasStructuredLabels(t *testing.T) {
 	const filePath = "main.ard"
 	result := parse.Parse([]byte(`let name: Str = 42`), filePath)
@@ -2290,3 +2436,31 @@ func TestAnnotatedBindingTypeMismatchHasStructuredLabels(t *testing.T) {
 		t.Fatalf("related label = %q", related.Message)
 	}
 }
+
+func TestDiagnosticsRenderTypesAsSourceSyntax(t *testing.T) {
+	run(t, []test{
+		{
+			name: "a failed generic match names the argument's type",
+			input: `struct Box {
+  value: Int,
+}
+
+fn flat(items: [[$T]]) Int {
+  items.size()
+}
+
+let boxes: [Box] = [Box{value: 1}]
+let count = flat(boxes)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "expected list type, got Box"},
+			},
+		},
+		{
+			name:  "a generic function type keeps its return type",
+			input: `let f: Int = fn(x: $T) $T { x }`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got fn($T) $T"},
+			},
+		},
+	})
+}
@@ -43,6 +43,39 @@ let custom: Error = report(ValidationError{message: "bad"})
 	}
 }
 
+func TestBuiltinErrorWrapAndCause(t *testing.T) {
+	source := `struct NotFound {
+  path: Str,
+}
+
+impl Error for NotFound {
+  fn error() Str {
+    "{self.path} not found"
+  }
+}
+
+let wrapped: Error = Error::wrap(NotFound{path: "a"}, "load")
+let nested: Error = Error::wrap(wrapped, "start")
+let cause: Error? = Error::cause(nested)
+`
+	result := parse.Parse([]byte(source), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	c := checker.New("main.ard", result.Program, nil)
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("checker diagnostics: %v", c.Diagnostics())
+	}
+
+	result = parse.Parse([]byte(`let wrapped = Error::wrap("boom", "load")`), "main.ard")
+	c = checker.New("main.ard", result.Program, nil)
+	c.Check()
+	if !c.HasErrors() {
+		t.Fatal("checker succeeded; expected Error::wrap to require an Error")
+	}
+}
+
 func TestBuiltinErrorRejectsNonStructImplementation(t *testing.T) {
 	source := `enum Failure { invalid }
 
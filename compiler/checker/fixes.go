@@ -0,0 +1,141 @@
+package checker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// TextEdit replaces the source between Start and End of FilePath with
+// NewText. Points are 1-based like parse locations, but End is exclusive, so
+// an edit whose Start and End are equal inserts NewText.
+type TextEdit struct {
+	FilePath string
+	Start    parse.Point
+	End      parse.Point
+	NewText  string
+}
+
+// DiagnosticFix is a machine-applicable fix for a diagnostic. Applying all of
+// its edits resolves the problem, though the result may need formatting.
+type DiagnosticFix struct {
+	Title string
+	Edits []TextEdit
+}
+
+func (c *Checker) insertion(at parse.Point, text string) TextEdit {
+	return TextEdit{FilePath: c.filePath, Start: at, End: at, NewText: text}
+}
+
+// missingArmsFix inserts a `panic("TODO")` arm for each pattern before the
+// closing brace of a match. It is only offered for a match whose closing
+// brace is on its own line, so the arms can be indented to match.
+func (c *Checker) missingArmsFix(location parse.Location, patterns []string) []DiagnosticFix {
+	if len(patterns) == 0 || location.End.Row <= location.Start.Row || location.End.Col < 1 {
+		return nil
+	}
+	indent := strings.Repeat(" ", location.End.Col-1)
+	var text strings.Builder
+	for _, pattern := range patterns {
+		fmt.Fprintf(&text, "  %s => panic(\"TODO\"),\n%s", pattern, indent)
+	}
+	title := fmt.Sprintf("Add missing match arm `%s`", patterns[0])
+	if len(patterns) > 1 {
+		title = "Add missing match arms"
+	}
+	return []DiagnosticFix{{Title: title, Edits: []TextEdit{c.insertion(location.End, text.String())}}}
+}
+
+// enumArmPattern is the pattern matching variant of enumType, with wildcards
+// for its associated data. Enums of other modules are left out, since their
+// qualified name depends on the import.
+func (c *Checker) enumArmPattern(enumType *Enum, variant EnumValue) []string {
+	if enumType.ModulePath != "" && enumType.ModulePath != c.modulePath {
+		return nil
+	}
+	pattern := enumType.Name + "::" + variant.Name
+	if len(variant.Payload) > 0 {
+		pattern += "(" + strings.Repeat("_, ", len(variant.Payload)-1) + "_)"
+	}
+	return []string{pattern}
+}
+
+// missingFieldsFix inserts the missing fields of a struct literal with the
+// zero value of their types. It is not offered when a field's type has no
+// literal zero value.
+func (c *Checker) missingFieldsFix(missing []string, fieldTypes map[string]Type, properties []parse.StructValue, location parse.Location) []DiagnosticFix {
+	names := slices.Sorted(slices.Values(missing))
+	values := make([]string, 0, len(names))
+	for _, name := range names {
+		zero, ok := zeroValueLiteral(fieldTypes[name])
+		if !ok {
+			return nil
+		}
+		values = append(values, fmt.Sprintf("%s: %s", name, zero))
+	}
+	text := strings.Join(values, ", ")
+	at := location.End
+	if len(properties) > 0 {
+		// after the last field, so a trailing comma stays valid
+		at = properties[len(properties)-1].GetLocation().End
+		at.Col++
+		text = ", " + text
+	}
+	title := fmt.Sprintf("Insert missing field `%s` with a default value", names[0])
+	if len(names) > 1 {
+		title = "Insert missing fields with default values"
+	}
+	return []DiagnosticFix{{Title: title, Edits: []TextEdit{c.insertion(at, text)}}}
+}
+
+// zeroValueLiteral is the source of a literal zero value of t.
+func zeroValueLiteral(t Type) (string, bool) {
+	switch t := derefType(t).(type) {
+	case *List:
+		return "[]", true
+	case *Map:
+		return "[:]", true
+	default:
+		switch t {
+		case Str:
+			return `""`, true
+		case Int:
+			return "0", true
+		case Float64:
+			return "0.0", true
+		case Bool:
+			return "false", true
+		}
+	}
+	return "", false
+}
+
+// qualifyingImport finds the one import whose module publicly declares name,
+// for a name that was used without its module qualifier.
+func (c *Checker) qualifyingImport(name string) (string, bool) {
+	found := ""
+	for _, imp := range c.input.Imports {
+		mod, ok := c.program.Imports[imp.Name]
+		if !ok || imp.ReExport != "" {
+			continue
+		}
+		if _, public := mod.Symbols()[name]; !public {
+			continue
+		}
+		if found != "" && found != imp.Name {
+			return "", false
+		}
+		found = imp.Name
+	}
+	return found, found != ""
+}
+
+// qualifyFix prefixes an unqualified name at location with its module.
+func (c *Checker) qualifyFix(alias string, name string, location parse.Location) []DiagnosticFix {
+	return []DiagnosticFix{{
+		Title: fmt.Sprintf("Qualify as `%s::%s`", alias, name),
+		Edits: []TextEdit{c.insertion(location.Start, alias+"::")},
+	}}
+}
@@ -0,0 +1,145 @@
+package checker_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+// applyEdits applies non-overlapping edits to source, last first so earlier
+// positions stay valid.
+func applyEdits(t *testing.T, source string, edits []checker.TextEdit) string {
+	t.Helper()
+	lines := strings.SplitAfter(source, "\n")
+	offset := func(p parse.Point) int {
+		n := 0
+		for _, line := range lines[:p.Row-1] {
+			n += len(line)
+		}
+		return n + p.Col - 1
+	}
+	sorted := slices.Clone(edits)
+	slices.SortFunc(sorted, func(a, b checker.TextEdit) int { return offset(b.Start) - offset(a.Start) })
+	for _, edit := range sorted {
+		source = source[:offset(edit.Start)] + edit.NewText + source[offset(edit.End):]
+	}
+	return source
+}
+
+func TestDiagnosticFixesResolveTheProblem(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		code  checker.DiagnosticCode
+		title string
+		want  string
+	}{
+		{
+			name:  "missing enum arm",
+			input: "enum Shape {\n  Dot,\n  Circle(Float64),\n}\n\nfn area(s: Shape) Float64 {\n  match s {\n    Shape::Dot => 0.0,\n  }\n}\n",
+			code:  checker.DiagnosticCodeNonExhaustiveMatch,
+			title: "Add missing match arm `Shape::Circle(_)`",
+			want:  "enum Shape {\n  Dot,\n  Circle(Float64),\n}\n\nfn area(s: Shape) Float64 {\n  match s {\n    Shape::Dot => 0.0,\n    Shape::Circle(_) => panic(\"TODO\"),\n  }\n}\n",
+		},
+		{
+			name:  "missing catch-all arm",
+			input: "fn label(n: Int) Str {\n  match n {\n    0 => \"zero\",\n  }\n}\n",
+			code:  checker.DiagnosticCodeNonExhaustiveMatch,
+			title: "Add missing match arm `_`",
+			want:  "fn label(n: Int) Str {\n  match n {\n    0 => \"zero\",\n    _ => panic(\"TODO\"),\n  }\n}\n",
+		},
+		{
+			name:  "missing struct fields",
+			input: "struct User {\n  name: Str,\n  age: Int,\n  tags: [Str],\n}\n\nlet u = User{\n  name: \"a\",\n}\n",
+			code:  checker.DiagnosticCodeMissingStructFields,
+			title: "Insert missing fields with default values",
+			want:  "struct User {\n  name: Str,\n  age: Int,\n  tags: [Str],\n}\n\nlet u = User{\n  name: \"a\", age: 0, tags: [],\n}\n",
+		},
+		{
+			name:  "missing field of an empty literal",
+			input: "struct Flag {\n  on: Bool,\n}\n\nlet f = Flag{}\n",
+			code:  checker.DiagnosticCodeMissingStructFields,
+			title: "Insert missing field `on` with a default value",
+			want:  "struct Flag {\n  on: Bool,\n}\n\nlet f = Flag{on: false}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostic := requireDiagnosticCode(t, checkSource(t, tt.input), tt.code)
+			if len(diagnostic.Fixes) != 1 {
+				t.Fatalf("fixes = %#v, want one", diagnostic.Fixes)
+			}
+			fix := diagnostic.Fixes[0]
+			if fix.Title != tt.title {
+				t.Fatalf("title = %q, want %q", fix.Title, tt.title)
+			}
+			got := applyEdits(t, tt.input, fix.Edits)
+			if got != tt.want {
+				t.Fatalf("fixed source = %q, want %q", got, tt.want)
+			}
+			if diagnostics := checkSource(t, got); len(diagnostics) > 0 {
+				t.Fatalf("fixed source still has diagnostics: %v", diagnostics)
+			}
+		})
+	}
+}
+
+func TestFixesAreOnlyOfferedWhenApplicable(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		code  checker.DiagnosticCode
+	}{
+		{
+			name:  "field without a zero value",
+			input: "struct Inner {\n  n: Int,\n}\n\nstruct Outer {\n  inner: Inner,\n}\n\nlet o = Outer{}\n",
+			code:  checker.DiagnosticCodeMissingStructFields,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diagnostic := requireDiagnosticCode(t, checkSource(t, tt.input), tt.code)
+			if len(diagnostic.Fixes) != 0 {
+				t.Fatalf("fixes = %#v, want none", diagnostic.Fixes)
+			}
+		})
+	}
+}
+
+func TestUndefinedNameFixQualifiesWithImportedModule(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ard.toml"), []byte("name = \"demo\"\nard = \">= 0.1.0\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "shapes.ard"), []byte("fn area(w: Int, h: Int) Int {\n  w * h\n}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resolver, err := checker.NewModuleResolver(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := "use demo/shapes\n\nlet a = area(2, 3)\n"
+	result := parse.Parse([]byte(input), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	c := checker.New("main.ard", result.Program, resolver)
+	c.Check()
+
+	diagnostic := requireDiagnosticCode(t, c.Diagnostics(), checker.DiagnosticCodeUndefinedName)
+	if diagnostic.Help != "did you mean `shapes::area`?" {
+		t.Fatalf("help = %q", diagnostic.Help)
+	}
+	if len(diagnostic.Fixes) != 1 || diagnostic.Fixes[0].Title != "Qualify as `shapes::area`" {
+		t.Fatalf("fixes = %#v", diagnostic.Fixes)
+	}
+	want := "use demo/shapes\n\nlet a = shapes::area(2, 3)\n"
+	if got := applyEdits(t, input, diagnostic.Fixes[0].Edits); got != want {
+		t.Fatalf("fixed source = %q, want %q", got, want)
+	}
+}
@@ -61,24 +61,7 @@ func (f *ForeignType) ComparableTypeArgs() []bool {
 }
 
 func (f *ForeignType) String() string {
-	name := f.Name
-	if f.Qualifier != "" {
-		name = f.Qualifier + "::" + f.Name
-	}
-	if len(f.TypeArgs) > 0 {
-		name += "<"
-		for i, arg := range f.TypeArgs {
-			if i > 0 {
-				name += ", "
-			}
-			name += arg.String()
-		}
-		name += ">"
-	}
-	if f.Pointer {
-		return "mut " + name
-	}
-	return name
+	return formatType(f)
 }
 
 func (f *ForeignType) get(name string) Type {
@@ -0,0 +1,208 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/akonwi/ard/parse"
+	"github.com/akonwi/ard/runtime"
+)
+
+// stringify converts a checked interpolation chunk to Str: Str values pass
+// through, values with to_str are converted with it, and user types and
+// containers are inspected. Values that cannot be converted are reported and
+// become empty strings.
+func (c *Checker) stringify(cx Expression, location parse.Location) Expression {
+	// A foreign named scalar stringifies as its underlying primitive
+	// (e.g. term::EventTitle interpolates as its Str value).
+	if prim := foreignScalarPrimitive(cx.Type()); prim != nil {
+		cx = &ForeignScalarConvert{Value: cx, Target: prim}
+	}
+
+	if cx.Type() == Str {
+		return cx
+	}
+
+	if toStr, ok := cx.Type().get("to_str").(*FunctionDef); ok && toStr.ReturnType == Str && len(toStr.Parameters) == 0 {
+		return c.createPrimitiveMethodNode(cx, toStr.Name, []Expression{}, toStr, nil, parse.Location{})
+	}
+
+	strMod := c.findModuleByPath("ard/string")
+	if strMod != nil {
+		toStringTrait := strMod.Get("ToString").Type.(*Trait)
+		if cx.Type().hasTrait(toStringTrait) {
+			// For non-string types that satisfy ToString trait, wrap with to_str() call
+			toStrMethod := toStringTrait.methods[0]
+			return c.createPrimitiveMethodNode(cx, toStrMethod.Name, []Expression{}, &toStrMethod, nil, parse.Location{})
+		}
+	}
+
+	// user types and containers without to_str render as Ard source
+	if isInspectable(cx.Type()) {
+		return &InspectValue{Value: cx}
+	}
+
+	if strMod != nil {
+		c.addTypeMismatch(strMod.Get("ToString").Type.(*Trait), cx.Type(), location)
+		// a non-stringable chunk stays empty
+		return &StrLiteral{}
+	}
+
+	c.addDiagnostic(stringInterpolationMismatchDiagnostic{
+		Actual: cx.Type(),
+		Span:   c.sourceSpan(location),
+	}.build())
+	return &StrLiteral{}
+}
+
+// checkInspect checks the builtin inspect(value), which renders any value as
+// Str.
+func (c *Checker) checkInspect(s *parse.FunctionCall) Expression {
+	if len(s.TypeArgs) > 0 {
+		c.addInvalidFunctionTypeArguments("inspect", 0, len(s.TypeArgs), false, s.GetLocation(), "")
+		return nil
+	}
+	if len(s.Args) != 1 || s.Args[0].Name != "" {
+		c.addArgumentCount("1", len(s.Args), s.GetLocation(), "")
+		return nil
+	}
+	value := c.checkExpr(s.Args[0].Value)
+	if value == nil {
+		return nil
+	}
+	if value.Type() == Void {
+		c.addTypeMismatchWithLegacy(Any, Void, "Cannot inspect a Void value", s.Args[0].GetLocation())
+		return nil
+	}
+	return &InspectValue{Value: value}
+}
+
+// isInspectable reports whether stringify renders values of t with
+// InspectValue: user types and containers, which have no to_str of their own.
+func isInspectable(t Type) bool {
+	switch t.(type) {
+	case *StructDef, *Enum, *Union, *List, *FixedArray, *Map, *Maybe, *Result:
+		return true
+	}
+	return false
+}
+
+// checkStrFormat checks Str::format(template, args..., name: value). When the
+// template is a literal (or a Str constant) its placeholders are checked
+// against the arguments here; other templates are checked when they run.
+func (c *Checker) checkStrFormat(s *parse.StaticFunction) Expression {
+	if len(s.Function.TypeArgs) > 0 {
+		c.addInvalidFunctionTypeArguments("Str::format", 0, len(s.Function.TypeArgs), false, s.GetLocation(), "Str::format does not take type arguments")
+		return nil
+	}
+	if len(s.Function.Args) == 0 || s.Function.Args[0].Name != "" {
+		c.addArgumentCount("at least 1", len(s.Function.Args), s.GetLocation(), "")
+		return nil
+	}
+	templateNode := s.Function.Args[0].Value
+	template := c.checkExprAs(templateNode, Str)
+	if template == nil {
+		return nil
+	}
+
+	format := &StrFormat{Template: template}
+	argLocations := []parse.Location{}
+	namedLocations := map[string]parse.Location{}
+	for _, arg := range s.Function.Args[1:] {
+		value := c.checkExpr(arg.Value)
+		if value == nil {
+			return nil
+		}
+		value = c.formatArgument(value, arg.Value.GetLocation())
+		if arg.Name == "" {
+			format.Args = append(format.Args, value)
+			argLocations = append(argLocations, arg.GetLocation())
+			continue
+		}
+		if _, dup := namedLocations[arg.Name]; dup {
+			c.addDiagnostic(invalidFormatDiagnostic{
+				Reason: fmt.Sprintf("%s is given more than once", arg.Name),
+				Label:  fmt.Sprintf("`%s` was already given", arg.Name),
+				Span:   c.sourceSpan(arg.GetLocation()),
+			}.build())
+			return nil
+		}
+		namedLocations[arg.Name] = arg.GetLocation()
+		format.Named = append(format.Named, FormatNamedArg{Name: arg.Name, Value: value})
+	}
+
+	if literal, ok := template.(*StrLiteral); ok {
+		if !c.validateFormat(literal.Value, format, templateNode.GetLocation(), argLocations, namedLocations) {
+			return nil
+		}
+	}
+	return format
+}
+
+// formatArgument keeps values the runtime formats natively and converts the
+// rest to Str.
+func (c *Checker) formatArgument(value Expression, location parse.Location) Expression {
+	switch value.Type() {
+	case Str, Bool, Int, Byte, Float64:
+		return value
+	}
+	return c.stringify(value, location)
+}
+
+func (c *Checker) validateFormat(template string, format *StrFormat, location parse.Location, argLocations []parse.Location, namedLocations map[string]parse.Location) bool {
+	invalid := func(reason, label string, loc parse.Location) bool {
+		c.addDiagnostic(invalidFormatDiagnostic{Reason: reason, Label: label, Span: c.sourceSpan(loc)}.build())
+		return false
+	}
+	parts, err := runtime.ParseFormat(template)
+	if err != nil {
+		return invalid(err.Error(), err.Error(), location)
+	}
+
+	usedArgs := make([]bool, len(format.Args))
+	usedNames := map[string]bool{}
+	for _, part := range parts {
+		if !part.Placeholder {
+			continue
+		}
+		var value Expression
+		if part.Name != "" {
+			for _, named := range format.Named {
+				if named.Name == part.Name {
+					value = named.Value
+				}
+			}
+			if value == nil {
+				return invalid(fmt.Sprintf("no argument named %s", part.Name), fmt.Sprintf("`{%s}` needs a `%s:` argument", part.Name, part.Name), location)
+			}
+			usedNames[part.Name] = true
+		} else {
+			if part.Index >= len(format.Args) {
+				return invalid(
+					fmt.Sprintf("placeholder {%d} needs %d positional arguments, got %d", part.Index, part.Index+1, len(format.Args)),
+					fmt.Sprintf("there is no argument at position %d", part.Index),
+					location,
+				)
+			}
+			value = format.Args[part.Index]
+			usedArgs[part.Index] = true
+		}
+		if part.Precision >= 0 && value.Type() != Float64 {
+			return invalid(
+				fmt.Sprintf("precision needs a Float64 argument, got %s", value.Type()),
+				"precision only applies to Float64 values",
+				location,
+			)
+		}
+	}
+	for i, used := range usedArgs {
+		if !used {
+			return invalid(fmt.Sprintf("argument %d is not used", i), "no placeholder refers to this argument", argLocations[i])
+		}
+	}
+	for _, named := range format.Named {
+		if !usedNames[named.Name] {
+			return invalid(fmt.Sprintf("argument %s is not used", named.Name), fmt.Sprintf("no `{%s}` placeholder refers to this argument", named.Name), namedLocations[named.Name])
+		}
+	}
+	return true
+}
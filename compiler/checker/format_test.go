@@ -0,0 +1,140 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestStrFormat(t *testing.T) {
+	run(t, []test{
+		{
+			name: "literal templates with positional, automatic, and named placeholders",
+			input: `const ROW = "\{0:>8} \{1:.2} \{}"
+
+fn main() {
+  let a: Str = Str::format("\{0:>8} \{1:.2} \{label:*^9}", 42, 3.5, label: "total")
+  let b: Str = Str::format("\{} and \{}", "x", true)
+  let c: Str = Str::format(ROW, 1, 2.0)
+}`,
+		},
+		{
+			name: "placeholders in a literal template need no escapes",
+			input: `fn main() {
+  let label = "unused"
+  let a: Str = Str::format("{0:>8} {1:.2} {label:*^9}", 42, 3.5, label: "total")
+  let b: Str = Str::format("{0} and {1}", "x", true)
+  let c: Str = Str::format("{label} x{0}", 2, label: "y")
+}`,
+		},
+		{
+			name:  "unescaped placeholders are checked like escaped ones",
+			input: `let s = Str::format("{0} {1}", 1)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid format string: placeholder {1} needs 2 positional arguments, got 1"},
+			},
+		},
+		{
+			name:  "literal templates do not interpolate",
+			input: "let count = 1\nlet s = Str::format(\"{count} {0}\", \"{count}\")",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid format string: no argument named count"},
+			},
+		},
+		{
+			name: "templates that are not literals are checked at runtime",
+			input: `fn render(template: Str) Str {
+  Str::format(template, 1, name: "x")
+}`,
+		},
+		{
+			name:  "placeholders need matching positional arguments",
+			input: `let s = Str::format("\{0} \{1}", 1)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid format string: placeholder {1} needs 2 positional arguments, got 1"},
+			},
+		},
+		{
+			name:  "named placeholders need named arguments",
+			input: `let s = Str::format("\{name}", other: 1)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid format string: no argument named name"},
+			},
+		},
+		{
+			name:  "every argument must be used",
+			input: `let s = Str::format("\{0}", 1, 2)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid format string: argument 1 is not used"},
+			},
+		},
+		{
+			name:  "precision applies only to Float64",
+			input: `let s = Str::format("\{0:.2}", 1)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid format string: precision needs a Float64 argument, got Int"},
+			},
+		},
+		{
+			name:  "malformed templates are rejected",
+			input: `let s = Str::format("\{0:x}", 1)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid format string: invalid format spec in {0:x}"},
+			},
+		},
+		{
+			name:  "the template must be a Str",
+			input: `let s = Str::format(1)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Str, got Int"},
+			},
+		},
+	})
+}
+
+func TestInspect(t *testing.T) {
+	run(t, []test{
+		{
+			name: "inspect renders any value and interpolation inspects user types",
+			input: `struct Point { x: Int, y: Int }
+enum Color { red, green }
+
+fn main() {
+  let p = Point{x: 1, y: 2}
+  let a: Str = inspect(p)
+  let counts: [Str: [Int]] = ["a": [1, 2]]
+  let b: Str = inspect(counts)
+  let c: Str = "{p} {Color::green} {[p]} {Maybe::new(p)}"
+}`,
+		},
+		{
+			name: "a function named inspect shadows the builtin",
+			input: `fn inspect(value: Int) Int { value }
+
+fn main() {
+  let n: Int = inspect(1)
+}`,
+		},
+		{
+			name: "inspect takes one value",
+			input: `fn nothing() {}
+
+fn main() {
+  inspect(1, 2)
+  inspect(nothing())
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Incorrect number of arguments: Expected 1, got 2"},
+				{Kind: checker.Error, Message: "Cannot inspect a Void value"},
+			},
+		},
+		{
+			name: "functions still cannot be interpolated",
+			input: `fn one() Int { 1 }
+let s = "{one}"`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected stringable value, got fn() Int"},
+			},
+		},
+	})
+}
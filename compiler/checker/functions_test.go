@@ -758,7 +758,7 @@ func TestCallingInstanceMethods(t *testing.T) {
 				Statements: []checker.Statement{
 					{
 						Expr: &checker.IntMethod{
-							Subject: &checker.IntLiteral{200},
+							Subject: &checker.IntLiteral{Value: 200},
 							Kind:    checker.IntToStr,
 						},
 					},
@@ -1293,6 +1293,67 @@ func TestForwardFunctionReferences(t *testing.T) {
 				{Kind: checker.Error, Message: "Type mismatch: Expected Str, got Void"},
 			},
 		},
+		{
+			name: "call a static function declared later in the module",
+			input: strings.Join([]string{
+				`fn caller() Int {`,
+				`  Shape::unit().size`,
+				`}`,
+				`fn Shape::unit() Shape { Shape{size: 1} }`,
+				`struct Shape { size: Int }`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "call methods from impl blocks declared later in the module",
+			input: strings.Join([]string{
+				`fn caller(shape: Shape, color: Color) Str {`,
+				`  "{shape.area()} {color.name()}"`,
+				`}`,
+				`impl Shape {`,
+				`  fn area() Int { self.size * self.size }`,
+				`}`,
+				`impl Color {`,
+				`  fn name() Str { "red" }`,
+				`}`,
+				`struct Shape { size: Int }`,
+				`enum Color { Red }`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "use a trait implementation declared later in the module",
+			input: strings.Join([]string{
+				`fn caller(shape: Shape) Str {`,
+				`  describe(shape) + shape.describe()`,
+				`}`,
+				`fn describe(value: Describe) Str { value.describe() }`,
+				`impl Describe for Shape {`,
+				`  fn describe() Str { "shape" }`,
+				`}`,
+				`trait Describe {`,
+				`  fn describe() Str`,
+				`}`,
+				`struct Shape { size: Int }`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "forward method call with a bad argument still reports a mismatch",
+			input: strings.Join([]string{
+				`fn caller(shape: Shape) Int {`,
+				`  shape.scale("2")`,
+				`}`,
+				`impl Shape {`,
+				`  fn scale(by: Int) Int { self.size * by }`,
+				`}`,
+				`struct Shape { size: Int }`,
+			}, "\n"),
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Str"},
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Void"},
+			},
+		},
 	})
 }
 
@@ -1415,3 +1476,42 @@ func TestClosureReturnTypeInference(t *testing.T) {
 		},
 	})
 }
+
+func TestClosureCaptures(t *testing.T) {
+	run(t, []test{
+		{
+			name: "closures can mutate captured mut variables",
+			input: `
+				mut count = 0
+				mut items: [Int] = []
+				let record = fn(n: Int) {
+					count = count + 1
+					items.push(n)
+				}
+				record(1)
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "closures can't assign to captured immutable variables",
+			input: `
+				let count = 0
+				let inc = fn() { count = count + 1 }
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Immutable variable: count"},
+			},
+		},
+		{
+			name: "closures can't mutate captured immutable parameters",
+			input: `
+				fn fill(items: [Int]) {
+					let add = fn() { items.push(1) }
+				}
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Cannot mutate immutable 'items' with '.push()'"},
+			},
+		},
+	})
+}
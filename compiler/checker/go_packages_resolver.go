@@ -11,8 +11,11 @@ import (
 )
 
 type GoPackagesResolver struct {
-	ProjectRoot   string
-	BuildTags     []string
+	ProjectRoot string
+	BuildTags   []string
+	// Env holds extra environment entries for the go command, such as
+	// GOOS and GOARCH, added to the process's own environment.
+	Env           []string
 	modulePath    string
 	modulePathErr error
 	cache         map[string]goPackageResolveResult
@@ -158,6 +161,9 @@ func (r *GoPackagesResolver) loadConfig() *packages.Config {
 	if len(r.BuildTags) > 0 {
 		cfg.BuildFlags = []string{"-tags=" + strings.Join(r.BuildTags, ",")}
 	}
+	if len(r.Env) > 0 {
+		cfg.Env = append(os.Environ(), r.Env...)
+	}
 	return cfg
 }
 
@@ -0,0 +1,108 @@
+package checker
+
+import (
+	"slices"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// hashableCall is a call to a generic function. Whether its bindings can key
+// a map is only known once every body has been checked, so the check waits
+// until the end of the module.
+type hashableCall struct {
+	caller   *FunctionDef
+	callee   *FunctionDef
+	bindings map[string]Type
+	location parse.Location
+}
+
+// requireHashable records that key, when it is a generic of an enclosing
+// function, is used as a map key in that function's body.
+func (c *Checker) requireHashable(key Type) {
+	generic, ok := unboundTypeVar(key)
+	if !ok {
+		return
+	}
+	for i := len(c.genericContextStack) - 1; i >= 0; i-- {
+		if !c.genericContextStack[i][generic.name] {
+			continue
+		}
+		if fn := c.genericContextFunctions[i]; fn != nil {
+			fn.Hashable = appendUniqueStrings(fn.Hashable, generic.name)
+		}
+		return
+	}
+}
+
+// recordHashableCall keeps a generic call so checkHashableCalls can check its
+// bindings.
+func (c *Checker) recordHashableCall(callee *FunctionDef, resolved *FunctionDef, location parse.Location) {
+	if callee == nil || resolved == nil || len(resolved.GenericBindings) == 0 {
+		return
+	}
+	var caller *FunctionDef
+	if len(c.genericContextFunctions) > 0 {
+		caller = c.genericContextFunctions[len(c.genericContextFunctions)-1]
+	}
+	c.hashableCalls = append(c.hashableCalls, hashableCall{
+		caller:   caller,
+		callee:   callee,
+		bindings: resolved.GenericBindings,
+		location: location,
+	})
+}
+
+// checkHashableCalls reports generic calls that bind a generic used as a map
+// key to a type that cannot key a map. A call that passes along a generic of
+// its caller makes that generic a map key of the caller too.
+func (c *Checker) checkHashableCalls() {
+	for changed := true; changed; {
+		changed = false
+		for _, call := range c.hashableCalls {
+			if call.caller == nil {
+				continue
+			}
+			callerGenerics := genericParamsForFunction(call.caller)
+			for _, name := range call.callee.Hashable {
+				generic, ok := unboundTypeVar(call.bindings[name])
+				if !ok || !slices.Contains(callerGenerics, generic.name) || slices.Contains(call.caller.Hashable, generic.name) {
+					continue
+				}
+				call.caller.Hashable = append(call.caller.Hashable, generic.name)
+				changed = true
+			}
+		}
+	}
+
+	for _, call := range c.hashableCalls {
+		for _, name := range call.callee.Hashable {
+			binding := call.bindings[name]
+			if binding == nil || isValidMapKeyType(binding) {
+				continue
+			}
+			if c.reportedMapKeyErrors == nil {
+				c.reportedMapKeyErrors = map[parse.Location]bool{}
+			}
+			if c.reportedMapKeyErrors[call.location] {
+				break
+			}
+			c.reportedMapKeyErrors[call.location] = true
+			c.addDiagnostic(invalidMapKeyTypeDiagnostic{KeyType: binding, Generic: "$" + name, Span: c.sourceSpan(call.location)}.build())
+			break
+		}
+	}
+}
+
+// unboundTypeVar is the generic t stands for when nothing has bound it yet.
+func unboundTypeVar(t Type) (*TypeVar, bool) {
+	for {
+		generic, ok := t.(*TypeVar)
+		if !ok {
+			return nil, false
+		}
+		if generic.actual == nil {
+			return generic, true
+		}
+		t = generic.actual
+	}
+}
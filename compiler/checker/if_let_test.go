@@ -0,0 +1,110 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestIfLet(t *testing.T) {
+	run(t, []test{
+		{
+			name: "if let unwraps a Maybe or a Result",
+			input: `fn find(n: Int) Int? {
+  Maybe::new(n)
+}
+
+fn parse(s: Str) Int!Str {
+  Result::ok(1)
+}
+
+fn label(n: Int) Str {
+  if let found = find(n) {
+    "found {found}"
+  } else if let parsed = parse("1") {
+    "parsed {parsed}"
+  } else {
+    "none"
+  }
+}
+
+fn main() {
+  if let found = find(1) {
+    let doubled: Int = found * 2
+  }
+}`,
+		},
+		{
+			name: "the binding is only in scope in the body",
+			input: `fn main() {
+  let maybe: Int? = Maybe::new()
+  if let found = maybe {
+    ()
+  } else {
+    found
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Undefined variable: found"},
+			},
+		},
+		{
+			name: "if let needs a Maybe or Result",
+			input: `fn main() {
+  if let found = 1 {
+    ()
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "if let expects a Maybe or Result, got Int"},
+			},
+		},
+		{
+			name: "branches must agree on a value type",
+			input: `let maybe: Int? = Maybe::new(1)
+if let found = maybe {
+  found
+} else {
+  "none"
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "All branches must have the same result type"},
+			},
+		},
+	})
+}
+
+func TestLetElse(t *testing.T) {
+	run(t, []test{
+		{
+			name: "let else unwraps or returns the else block's value",
+			input: `fn find(n: Int) Int? {
+  Maybe::new(n)
+}
+
+fn parse(s: Str) Int!Str {
+  Result::ok(1)
+}
+
+fn describe(n: Int) Str {
+  let found = find(n) else { "nothing" }
+  "found {found}"
+}
+
+fn total(s: Str) Int!Str {
+  let parsed: Int = parse(s) else { Result::err("bad input") }
+  Result::ok(parsed + 1)
+}`,
+		},
+		{
+			name: "the else block must produce the function's return type",
+			input: `fn describe(maybe: Int?) Int {
+  let found = maybe else { "nothing" }
+  found
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Str"},
+			},
+		},
+	})
+}
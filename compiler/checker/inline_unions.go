@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// resolveInlineUnion builds the union an inline `A | B` annotation names.
+// Nested inline unions are flattened and repeated members dropped, then the
+// members are sorted by name, so `Int | Str` and `Str | Int` are one type with
+// one member order. A union of a single distinct member is that member.
+func (c *Checker) resolveInlineUnion(declared *parse.UnionType) Type {
+	var members []Type
+	var add func(member Type)
+	add = func(member Type) {
+		if nested, ok := member.(*Union); ok && nested.Inline {
+			for _, inner := range nested.Types {
+				add(inner)
+			}
+			return
+		}
+		for _, existing := range members {
+			if existing.String() == member.String() && existing.equal(member) {
+				return
+			}
+		}
+		members = append(members, member)
+	}
+	for _, member := range declared.Members {
+		resolved := c.resolveType(member)
+		if resolved == nil {
+			c.addUnresolvedReference(unrecognizedType, member.GetName(), member.GetLocation())
+			return nil
+		}
+		add(resolved)
+	}
+	if len(members) == 1 {
+		return members[0]
+	}
+	sort.SliceStable(members, func(i, j int) bool { return members[i].String() < members[j].String() })
+	names := make([]string, len(members))
+	for i, member := range members {
+		names[i] = member.String()
+	}
+	return &Union{
+		Name:       strings.Join(names, " | "),
+		ModulePath: c.typeOwnerPath(),
+		Types:      members,
+		Inline:     true,
+	}
+}
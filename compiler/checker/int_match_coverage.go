@@ -0,0 +1,51 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// intMatchCoverage is what the arms of an Int match have matched so far, in
+// arm order.
+type intMatchCoverage struct {
+	ranges   []coveredRange
+	catchAll *SourceSpan
+}
+
+type coveredRange struct {
+	IntRange
+	span SourceSpan
+}
+
+// rangeContaining finds the earliest range arm that matches value.
+func (cov *intMatchCoverage) rangeContaining(value int) (coveredRange, bool) {
+	for _, earlier := range cov.ranges {
+		if earlier.Start <= value && value <= earlier.End {
+			return earlier, true
+		}
+	}
+	return coveredRange{}, false
+}
+
+// checkRangeCoverage records a range arm, warning when earlier ranges already
+// match some or all of it. It reports false when an earlier range covers the
+// whole of intRange, so the arm can never match.
+func (c *Checker) checkRangeCoverage(cov *intMatchCoverage, intRange IntRange, location parse.Location) bool {
+	reachable := true
+	for _, earlier := range cov.ranges {
+		if earlier.End < intRange.Start || intRange.End < earlier.Start {
+			continue
+		}
+		if earlier.Start <= intRange.Start && intRange.End <= earlier.End {
+			legacy := fmt.Sprintf("Unreachable case: %d..%d is already covered by %d..%d", intRange.Start, intRange.End, earlier.Start, earlier.End)
+			c.addUnreachableMatchArm(legacy, location, &earlier.span, "an earlier range matches every value in this one")
+			reachable = false
+			break
+		}
+		legacy := fmt.Sprintf("Range %d..%d overlaps %d..%d", intRange.Start, intRange.End, earlier.Start, earlier.End)
+		c.addUnreachableMatchArm(legacy, location, &earlier.span, "an earlier range matches part of this one")
+	}
+	cov.ranges = append(cov.ranges, coveredRange{IntRange: intRange, span: c.sourceSpan(location)})
+	return reachable
+}
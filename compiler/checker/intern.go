@@ -0,0 +1,60 @@
+package checker
+
+import "sync"
+
+// Checking rebuilds the same list, map, maybe, and result types over and over.
+// MakeList, MakeMap, MakeMaybe, and MakeResult share one instance per closed
+// shape, one built only from builtin types and other shared shapes, such as
+// `[Str: [Int]]`. A closed shape holds no type variables, so it is the same
+// pointer wherever it appears and dereferencing, inference, and equality can
+// stop at it without walking it.
+//
+// Shapes that involve type variables or named types are not shared: type
+// variables are bound in place during inference, and named types belong to a
+// single check, so sharing them would let one check hold on to another's types.
+
+type internKind uint8
+
+const (
+	internList internKind = iota
+	internMap
+	internMaybe
+	internResult
+)
+
+type internKey struct {
+	kind  internKind
+	left  Type
+	right Type
+}
+
+var internedShapes sync.Map // internKey -> the shared instance of that shape
+
+// internType returns the shared instance for key, creating it with build the
+// first time the shape is seen.
+func internType[T Type](key internKey, build func() T) T {
+	if shared, ok := internedShapes.Load(key); ok {
+		return shared.(T)
+	}
+	shared, _ := internedShapes.LoadOrStore(key, build())
+	return shared.(T)
+}
+
+// isClosedType reports whether t is a builtin type or a shared shape, and so
+// holds no type variables anywhere inside it.
+func isClosedType(t Type) bool {
+	switch t := t.(type) {
+	case *str, *byteType, *runeType, *_int, *scalarType, *float, *_bool, *void, *anyType:
+		return true
+	case *List:
+		return t.closed
+	case *Map:
+		return t.closed
+	case *Maybe:
+		return t.closed
+	case *Result:
+		return t.closed
+	default:
+		return false
+	}
+}
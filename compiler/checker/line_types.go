@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"sort"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// LineTypes returns, for each source row, the inferred types of the bindings
+// declared on it as "name: Type". Rows without bindings list the types of
+// their outermost expressions instead: expressions not enclosed by another
+// expression that starts on the same row. `ard check --types` prints these
+// beside the source.
+func (i *SpanIndex) LineTypes() map[int][]string {
+	bindings := map[int][]string{}
+	exprs := map[int][]SpanRecord{}
+	for _, rec := range i.Records() {
+		row := rec.Loc.Start.Row
+		if sym, ok := rec.Key.(*Symbol); ok && rec.IsDef {
+			if sym.Type != nil {
+				bindings[row] = append(bindings[row], sym.Name+": "+sym.Type.String())
+			}
+			continue
+		}
+		if def, ok := rec.Node.(*FunctionDef); ok && isFunctionDeclaration(rec.Source) {
+			bindings[row] = append(bindings[row], def.Name+": "+def.Type().String())
+			continue
+		}
+		if rec.Node != nil && rec.Source != nil && rec.Node.Type() != nil {
+			exprs[row] = append(exprs[row], rec)
+		}
+	}
+
+	out := map[int][]string{}
+	for row, names := range bindings {
+		out[row] = names
+	}
+	for row, recs := range exprs {
+		if _, ok := out[row]; ok {
+			continue
+		}
+		var types []string
+		for _, rec := range outermostRecords(recs) {
+			types = append(types, rec.Node.Type().String())
+		}
+		out[row] = types
+	}
+	return out
+}
+
+func isFunctionDeclaration(source parse.Expression) bool {
+	switch source.(type) {
+	case *parse.FunctionDeclaration, *parse.StaticFunctionDeclaration:
+		return true
+	default:
+		return false
+	}
+}
+
+// outermostRecords drops records whose span lies inside another record's span,
+// keeping one record per span in source order.
+func outermostRecords(recs []SpanRecord) []SpanRecord {
+	sort.SliceStable(recs, func(a, b int) bool {
+		return spanSize(recs[a].Loc) > spanSize(recs[b].Loc)
+	})
+	var kept []SpanRecord
+	for _, rec := range recs {
+		enclosed := false
+		for _, outer := range kept {
+			if spanContains(outer.Loc, rec.Loc.Start) && spanContains(outer.Loc, rec.Loc.End) {
+				enclosed = true
+				break
+			}
+		}
+		if !enclosed {
+			kept = append(kept, rec)
+		}
+	}
+	sort.SliceStable(kept, func(a, b int) bool {
+		return kept[a].Loc.Start.Col < kept[b].Loc.Start.Col
+	})
+	return kept
+}
@@ -29,6 +29,18 @@ type ProjectInfo struct {
 	Go            GoProjectConfig
 	RootPackageID string
 	Packages      map[string]PackageInfo
+	// Language is the language version pinned with `language = "0.21"` in
+	// ard.toml. The zero value means the compiler's current version.
+	Language version.Semver
+}
+
+// LanguageVersion returns the language version the project's sources are
+// checked against.
+func (p *ProjectInfo) LanguageVersion() version.Semver {
+	if p == nil || p.Language == (version.Semver{}) {
+		return version.Language()
+	}
+	return p.Language
 }
 
 type GoProjectConfig struct {
@@ -119,6 +131,13 @@ func FindProjectRoot(startPath string) (*ProjectInfo, error) {
 			if err := version.CheckVersion(constraint); err != nil {
 				return nil, err
 			}
+			language := version.Language()
+			if raw, ok := parseLanguageVersion(tomlPath); ok {
+				language, err = version.ParseLanguage(raw)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse ard.toml: %w", err)
+				}
+			}
 
 			dependencies, err := parseProjectDependencies(tomlPath, current)
 			if err != nil {
@@ -152,6 +171,7 @@ func FindProjectRoot(startPath string) (*ProjectInfo, error) {
 				Go:            goConfig,
 				RootPackageID: rootPackageID,
 				Packages:      packages,
+				Language:      language,
 			}, nil
 		}
 
@@ -209,6 +229,23 @@ func parseArdVersion(tomlPath string) (string, bool) {
 	return matches[1], true
 }
 
+// parseLanguageVersion extracts the pinned language version from ard.toml if
+// present. Format: language = "0.22"
+func parseLanguageVersion(tomlPath string) (string, bool) {
+	content, err := os.ReadFile(tomlPath)
+	if err != nil {
+		return "", false
+	}
+
+	re := regexp.MustCompile(`(?m)^\s*language\s*=\s*["']([^"']+)["']`)
+	matches := re.FindStringSubmatch(string(content))
+	if len(matches) < 2 {
+		return "", false
+	}
+
+	return matches[1], true
+}
+
 func parseGoProjectConfig(tomlPath string) (GoProjectConfig, error) {
 	content, err := os.ReadFile(tomlPath)
 	if err != nil {
@@ -1370,27 +1407,30 @@ func (mr *ModuleResolver) ResolveImport(importerModulePath string, importPath st
 	}
 	if pkg.ID == mr.project.RootPackageID {
 		if dep, ok := mr.project.Dependencies[rootName]; ok {
-			modulePath := strings.Join(parts[1:], "/")
-			if modulePath == "" {
-				modulePath = dep.Name
-				if modulePath == "" {
-					modulePath = dep.Alias
-				}
+			return mr.resolveRootDependencyModule(dep, strings.Join(parts[1:], "/"))
+		}
+		if alias, modulePath, ok := matchDependencySource(importPath, mr.project.Dependencies); ok {
+			return mr.resolveRootDependencyModule(mr.project.Dependencies[alias], modulePath)
+		}
+	} else {
+		depPackageID := pkg.Dependencies[rootName]
+		modulePath := strings.Join(parts[1:], "/")
+		if depPackageID == "" {
+			deps := make(map[string]DependencyInfo, len(pkg.Dependencies))
+			for alias, id := range pkg.Dependencies {
+				deps[alias] = DependencyInfo{Alias: alias, Git: mr.packageInfo(id).Git, PackageID: id}
 			}
-			if dep.PackageID != "" {
-				if _, ok := mr.project.Packages[dep.PackageID]; ok {
-					return mr.resolvePackageModule(dep.PackageID, modulePath)
-				}
+			if alias, path, ok := matchDependencySource(importPath, deps); ok {
+				depPackageID, modulePath = deps[alias].PackageID, path
 			}
-			return mr.resolveDependencyModule(dep, modulePath)
 		}
-	} else if depPackageID := pkg.Dependencies[rootName]; depPackageID != "" {
-		depPkg := mr.packageInfo(depPackageID)
-		modulePath := strings.Join(parts[1:], "/")
-		if modulePath == "" {
-			modulePath = depPkg.Name
+		if depPackageID != "" {
+			depPkg := mr.packageInfo(depPackageID)
+			if modulePath == "" {
+				modulePath = depPkg.Name
+			}
+			return mr.resolvePackageModule(depPkg.ID, modulePath)
 		}
-		return mr.resolvePackageModule(depPkg.ID, modulePath)
 	}
 	if pkg.ID == mr.project.RootPackageID {
 		return ResolvedImport{}, fmt.Errorf("unknown import root %q for package %q; import path '%s' does not match project name '%s' or a dependency alias", rootName, pkg.Name, importPath, mr.project.ProjectName)
@@ -1398,6 +1438,62 @@ func (mr *ModuleResolver) ResolveImport(importerModulePath string, importPath st
 	return ResolvedImport{}, fmt.Errorf("unknown import root %q for package %q", rootName, pkg.Name)
 }
 
+func (mr *ModuleResolver) resolveRootDependencyModule(dep DependencyInfo, modulePath string) (ResolvedImport, error) {
+	if modulePath == "" {
+		modulePath = dep.Name
+		if modulePath == "" {
+			modulePath = dep.Alias
+		}
+	}
+	if dep.PackageID != "" {
+		if _, ok := mr.project.Packages[dep.PackageID]; ok {
+			return mr.resolvePackageModule(dep.PackageID, modulePath)
+		}
+	}
+	return mr.resolveDependencyModule(dep, modulePath)
+}
+
+// matchDependencySource finds the git dependency whose source is a prefix of
+// importPath, so `use github.com/owner/repo/module` resolves the same module
+// as `use repo/module`. It returns the dependency alias and the module path
+// inside the dependency.
+func matchDependencySource(importPath string, deps map[string]DependencyInfo) (string, string, bool) {
+	lower := strings.ToLower(importPath)
+	matchedAlias, matchedPrefix := "", ""
+	for alias, dep := range deps {
+		prefix := GitSourceImportPath(dep.Git)
+		if prefix == "" || len(prefix) <= len(matchedPrefix) {
+			continue
+		}
+		if lower == prefix || strings.HasPrefix(lower, prefix+"/") {
+			matchedAlias, matchedPrefix = alias, prefix
+		}
+	}
+	if matchedAlias == "" {
+		return "", "", false
+	}
+	return matchedAlias, strings.TrimPrefix(importPath[len(matchedPrefix):], "/"), true
+}
+
+// GitSourceImportPath returns the import path prefix for a git source: the
+// host and repository path without scheme, user, or `.git` suffix, e.g.
+// github.com/owner/repo for every GitHub URL form.
+func GitSourceImportPath(source string) string {
+	canonical := CanonicalGitSource(source)
+	if canonical == "" {
+		return ""
+	}
+	path := canonical
+	if rest, ok := strings.CutPrefix(canonical, "git@"); ok {
+		host, repo, _ := strings.Cut(rest, ":")
+		path = host + "/" + repo
+	} else if parsed, err := url.Parse(canonical); err == nil && parsed.Host != "" {
+		path = parsed.Hostname() + "/" + parsed.Path
+	}
+	path = strings.Trim(strings.ReplaceAll(path, "//", "/"), "/")
+	return strings.ToLower(strings.TrimSuffix(path, ".git"))
+}
+
 func (mr *ModuleResolver) packageIDForModule(modulePath string) string {
 	if mr == nil || mr.project == nil {
 		return "root"
@@ -1533,6 +1629,7 @@ func (mr *ModuleResolver) LoadModuleFile(filePath string) (*parse.Program, error
 		}
 	}
 	result := parse.Parse(sourceCode, filePath)
+	result.AllowLegacy(mr.project.LanguageVersion())
 	if len(result.Errors) > 0 {
 		return nil, fmt.Errorf("failed to parse module %s: %s", filePath, result.Errors[0].Message)
 	}
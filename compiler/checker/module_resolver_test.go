@@ -10,6 +10,7 @@ import (
 
 	"github.com/akonwi/ard/checker"
 	"github.com/akonwi/ard/parse"
+	"github.com/akonwi/ard/version"
 )
 
 func TestFindProjectRoot(t *testing.T) {
@@ -68,6 +69,69 @@ func TestFindProjectRootFallback(t *testing.T) {
 		t.Errorf("Expected project name '%s', got '%s'", expectedName, project.ProjectName)
 	}
 }
+func TestProjectLanguageVersion(t *testing.T) {
+	t.Run("defaults to the current language", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "ard.toml"), []byte("name = \"demo\"\nard = \">= 0.1.0\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		resolver, err := checker.NewModuleResolver(dir)
+		if err != nil {
+			t.Fatalf("NewModuleResolver: %v", err)
+		}
+		if got := resolver.GetProjectInfo().LanguageVersion(); got != version.Language() {
+			t.Fatalf("language = %v, want %v", got, version.Language())
+		}
+	})
+
+	t.Run("parses a pinned language", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "ard.toml"), []byte("name = \"demo\"\nard = \">= 0.1.0\"\nlanguage = \"0.21\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		resolver, err := checker.NewModuleResolver(dir)
+		if err != nil {
+			t.Fatalf("NewModuleResolver: %v", err)
+		}
+		if got := resolver.GetProjectInfo().LanguageVersion(); got != (version.Semver{Major: 0, Minor: 21}) {
+			t.Fatalf("language = %v, want 0.21.0", got)
+		}
+	})
+
+	t.Run("imports accept legacy syntax under an older language", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "ard.toml"), []byte("name = \"demo\"\nard = \">= 0.1.0\"\nlanguage = \"0.21\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		source := "struct Model {\n  n: Int,\n}\n\nfn bump(mut m: Model) {\n  m.n = 1\n}\n"
+		if err := os.WriteFile(filepath.Join(dir, "model.ard"), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		resolver, err := checker.NewModuleResolver(dir)
+		if err != nil {
+			t.Fatalf("NewModuleResolver: %v", err)
+		}
+		program, err := resolver.LoadModuleFile(filepath.Join(dir, "model.ard"))
+		if err != nil {
+			t.Fatalf("LoadModuleFile: %v", err)
+		}
+		fn := program.Statements[1].(*parse.FunctionDeclaration)
+		if _, ok := fn.Parameters[0].Type.(*parse.MutableType); !ok {
+			t.Fatalf("parameter type = %T, want *parse.MutableType", fn.Parameters[0].Type)
+		}
+	})
+
+	t.Run("rejects languages newer than the compiler", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.WriteFile(filepath.Join(dir, "ard.toml"), []byte("name = \"demo\"\nard = \">= 0.1.0\"\nlanguage = \"99.0\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := checker.NewModuleResolver(dir); err == nil || !strings.Contains(err.Error(), "this project targets Ard language 99.0") {
+			t.Fatalf("expected language version error, got %v", err)
+		}
+	})
+}
+
 func TestGoBuildTagsConfig(t *testing.T) {
 	t.Run("parses configured tags", func(t *testing.T) {
 		dir := t.TempDir()
@@ -585,6 +649,83 @@ func TestGitDependencyResolvesFromLockCache(t *testing.T) {
 		t.Fatalf("resolved path = %q, want %q", path, filepath.Join(cachePath, "dep.ard"))
 	}
 }
+func TestGitSourceImportPath(t *testing.T) {
+	for input, want := range map[string]string{
+		"https://github.com/Akonwi/Vaxis-Ard.git": "github.com/akonwi/vaxis-ard",
+		"git@github.com:akonwi/vaxis-ard.git":     "github.com/akonwi/vaxis-ard",
+		"https://gitlab.com/group/sub/repo.git":   "gitlab.com/group/sub/repo",
+		"ssh://git@example.com/team/repo":         "example.com/team/repo",
+		"git@example.com:team/repo.git":           "example.com/team/repo",
+	} {
+		if got := checker.GitSourceImportPath(input); got != want {
+			t.Fatalf("GitSourceImportPath(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestGitDependencyResolvesBySourceImportPath(t *testing.T) {
+	cacheRoot := t.TempDir()
+	t.Setenv("ARD_CACHE_DIR", cacheRoot)
+	root := t.TempDir()
+	gitSource := "https://github.com/akonwi/kit.git"
+	commit := "0123456789abcdef0123456789abcdef01234567"
+	cachePath := checker.DependencyCachePath(gitSource, commit)
+	if err := os.MkdirAll(filepath.Join(cachePath, "text"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cachePath, "ard.toml"), []byte("name = \"kit\"\nard = \">= 0.1.0\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cachePath, "kit.ard"), []byte("fn answer() Int { 42 }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(cachePath, "text", "case.ard"), []byte("fn shout(s: Str) Str { s }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "ard.toml"), []byte("name = \"app\"\nard = \">= 0.1.0\"\n\n[dependencies]\nkit = { git = \""+gitSource+"\", commit = \""+commit+"\" }\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	lock := fmt.Sprintf(`{
+  "version": 1,
+  "root": "root",
+  "packages": [
+    {"id": "root", "name": "app", "path": ".", "dependencies": {"kit": "%s"}},
+    {"id": "%s", "name": "kit", "git": "%s", "commit": "%s"}
+  ]
+}
+`, checker.GitPackageID(gitSource, commit), checker.GitPackageID(gitSource, commit), gitSource, commit)
+	if err := os.WriteFile(filepath.Join(root, "ard.lock"), []byte(lock), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	main := "use github.com/akonwi/kit\nuse github.com/akonwi/kit/text/case\nuse kit/text/case as by_alias\n\nlet answer = kit::answer()\nlet loud = case::shout(\"hi\")\nlet same = by_alias::shout(\"hi\")\n"
+	if err := os.WriteFile(filepath.Join(root, "main.ard"), []byte(main), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	resolver, err := checker.NewModuleResolver(root)
+	if err != nil {
+		t.Fatalf("new resolver: %v", err)
+	}
+	bySource, err := resolver.ResolveImport("", "github.com/akonwi/kit/text/case")
+	if err != nil {
+		t.Fatalf("resolve source import: %v", err)
+	}
+	byAlias, err := resolver.ResolveImport("", "kit/text/case")
+	if err != nil {
+		t.Fatalf("resolve alias import: %v", err)
+	}
+	if bySource != byAlias {
+		t.Fatalf("source import = %+v, want the alias import %+v", bySource, byAlias)
+	}
+
+	result := parseSourceForResolverTest(t, filepath.Join(root, "main.ard"))
+	c := checker.New(filepath.Join(root, "main.ard"), result, resolver)
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("checker diagnostics: %v", c.Diagnostics())
+	}
+}
+
 func TestGitDependencyWithoutLockFailsClearly(t *testing.T) {
 	root := t.TempDir()
 	if err := os.WriteFile(filepath.Join(root, "ard.toml"), []byte("name = \"app\"\nard = \">= 0.1.0\"\n\n[dependencies]\ndep = { git = \"https://example.invalid/dep.git\", commit = \"0123456\" }\n"), 0o644); err != nil {
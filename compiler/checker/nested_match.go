@@ -0,0 +1,157 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// flattenNestedPatterns rewrites a match over a Maybe or Result whose arms
+// nest patterns, like `ok(some(x))`, into matches over one level at a time.
+// Arms are grouped by their outer variant; a group with a nested pattern
+// becomes one arm that binds the variant's value to a hidden name and matches
+// on it with the group's inner patterns. A Maybe arm written `some(x)` becomes
+// the plain binding `x`. It returns s itself when no arm needs rewriting, and
+// false when a nested pattern cannot match the value it is nested in.
+func (c *Checker) flattenNestedPatterns(s *parse.MatchExpression, subjectType Type) (*parse.MatchExpression, bool) {
+	var variants []string
+	switch subjectType.(type) {
+	case *Maybe:
+		variants = []string{"some"}
+	case *Result:
+		variants = []string{"ok", "err"}
+	default:
+		return s, true
+	}
+
+	groups := map[string][]parse.MatchCase{}
+	rewrite := false
+	valid := true
+	for _, matchCase := range s.Cases {
+		call, ok := matchCase.Pattern.(*parse.FunctionCall)
+		if !ok || len(call.Args) != 1 || !isNestedPatternVariant(variants, call.Name) {
+			continue
+		}
+		if call.Name == "some" {
+			rewrite = true
+		}
+		if nested, ok := call.Args[0].Value.(*parse.FunctionCall); ok {
+			rewrite = true
+			valid = c.nestedPatternFits(nested, variantValueType(subjectType, call.Name)) && valid
+		}
+		groups[call.Name] = append(groups[call.Name], parse.MatchCase{
+			Location: matchCase.Location,
+			Pattern:  call.Args[0].Value,
+			Body:     matchCase.Body,
+		})
+	}
+	if !valid {
+		return nil, false
+	}
+	if !rewrite {
+		return s, true
+	}
+
+	flattened := &parse.MatchExpression{Location: s.Location, Subject: s.Subject, Comments: s.Comments}
+	emitted := map[string]bool{}
+	for _, matchCase := range s.Cases {
+		call, ok := matchCase.Pattern.(*parse.FunctionCall)
+		if !ok || len(call.Args) != 1 || !isNestedPatternVariant(variants, call.Name) {
+			flattened.Cases = append(flattened.Cases, matchCase)
+			continue
+		}
+		if emitted[call.Name] {
+			continue
+		}
+		emitted[call.Name] = true
+
+		start := call.GetLocation().Start
+		hidden := &parse.Identifier{
+			Location: call.Args[0].GetLocation(),
+			Name:     fmt.Sprintf("match$%s%d_%d", call.Name, start.Row, start.Col),
+		}
+		group := groups[call.Name]
+		if len(group) == 1 {
+			if _, nested := group[0].Pattern.(*parse.FunctionCall); !nested {
+				binding := group[0].Pattern
+				// a bare `_` in a Maybe match is the empty case, so `some(_)`
+				// binds a name nothing reads instead
+				if id, ok := binding.(*parse.Identifier); ok && id.Name == "_" && call.Name == "some" {
+					binding = hidden
+				}
+				flattened.Cases = append(flattened.Cases, nestedPatternArm(call, binding, matchCase))
+				continue
+			}
+		}
+
+		// the parser leaves arms without a location, so the inner match
+		// reports at the variant pattern it was lifted from
+		inner := &parse.MatchExpression{Location: call.GetLocation(), Subject: hidden, Cases: group}
+		flattened.Cases = append(flattened.Cases, nestedPatternArm(call, hidden, parse.MatchCase{
+			Location: matchCase.Location,
+			Body:     []parse.Statement{inner},
+		}))
+	}
+	return flattened, true
+}
+
+// variantValueType is the type of the value a variant pattern of subjectType
+// binds.
+func variantValueType(subjectType Type, variant string) Type {
+	switch t := subjectType.(type) {
+	case *Maybe:
+		return t.of
+	case *Result:
+		if variant == "err" {
+			return t.Err()
+		}
+		return t.Val()
+	}
+	return nil
+}
+
+// nestedPatternFits reports whether a nested variant pattern can match
+// values of valueType, reporting the pattern when it cannot.
+func (c *Checker) nestedPatternFits(pattern *parse.FunctionCall, valueType Type) bool {
+	switch pattern.Name {
+	case "some":
+		if _, ok := valueType.(*Maybe); ok {
+			return true
+		}
+	case "ok", "err":
+		if _, ok := valueType.(*Result); ok {
+			return true
+		}
+	default:
+		legacy := fmt.Sprintf("Invalid nested pattern %s(...): expected ok, err, or some", pattern.Name)
+		c.addInvalidMatchPattern(legacy, pattern.GetLocation(), "expected `ok(...)`, `err(...)`, or `some(...)`")
+		return false
+	}
+	legacy := fmt.Sprintf("Pattern %s(...) cannot match a value of type %s", pattern.Name, valueType)
+	c.addInvalidMatchPattern(legacy, pattern.GetLocation(), fmt.Sprintf("this value is a `%s`", valueType))
+	return false
+}
+
+func isNestedPatternVariant(variants []string, name string) bool {
+	for _, variant := range variants {
+		if variant == name {
+			return true
+		}
+	}
+	return false
+}
+
+// nestedPatternArm builds the one-level arm for a variant whose value is
+// bound by binding: `ok(binding)` or `err(binding)` for a Result, and the
+// bare binding for a Maybe.
+func nestedPatternArm(variant *parse.FunctionCall, binding parse.Expression, arm parse.MatchCase) parse.MatchCase {
+	pattern := binding
+	if variant.Name != "some" {
+		pattern = &parse.FunctionCall{
+			Location: variant.Location,
+			Name:     variant.Name,
+			Args:     []parse.Argument{{Location: binding.GetLocation(), Value: binding}},
+		}
+	}
+	return parse.MatchCase{Location: arm.Location, Pattern: pattern, Body: arm.Body}
+}
@@ -0,0 +1,126 @@
+package checker_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+const nestedMatchPrelude = `fn lookup(id: Int) Int?!Str {
+  Result::ok(Maybe::new(id))
+}
+
+fn find(id: Int) (Int!Str)? {
+  Maybe::new(Result::ok(id))
+}
+`
+
+func TestNestedMatchPatterns(t *testing.T) {
+	run(t, []test{
+		{
+			name: "nested patterns match through a Result and a Maybe in one match",
+			input: nestedMatchPrelude + `
+fn main() {
+  let a: Str = match lookup(1) {
+    ok(some(x)) => "found {x}",
+    ok(_) => "missing",
+    err(e) => e,
+  }
+  let b: Str = match find(1) {
+    some(ok(x)) => "ok {x}",
+    some(err(e)) => e,
+    _ => "none",
+  }
+  let c: Str = match find(1) {
+    some(_) => "something",
+    _ => "nothing",
+  }
+}`,
+		},
+		{
+			name: "each nested level must be exhaustive",
+			input: nestedMatchPrelude + `
+fn main() {
+  match lookup(1) {
+    ok(some(x)) => x,
+    err(e) => 0,
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Match on a Maybe type must include a wildcard (_) case"},
+			},
+		},
+		{
+			name: "nested bindings are only in scope in their arm",
+			input: nestedMatchPrelude + `
+fn main() {
+  let value = match lookup(1) {
+    ok(some(x)) => x,
+    ok(_) => 0,
+    err(e) => 0,
+  }
+  x
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Undefined variable: x"},
+			},
+		},
+		{
+			name: "nested patterns must fit the value they match",
+			input: nestedMatchPrelude + `
+fn main() {
+  match find(1) {
+    some(some(x)) => x,
+    _ => 0,
+  }
+  match lookup(1) {
+    ok(first(x)) => x,
+    ok(_) => 0,
+    err(e) => 0,
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Pattern some(...) cannot match a value of type Int!Str"},
+				{Kind: checker.Error, Message: "Invalid nested pattern first(...): expected ok, err, or some"},
+			},
+		},
+		{
+			name: "Result arms bind a name",
+			input: nestedMatchPrelude + `
+fn main() {
+  match lookup(1) {
+    ok(1) => 1,
+    err(e) => 0,
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid pattern in ok(...): expected a name or a nested ok, err, or some pattern"},
+				{Kind: checker.Error, Message: "Missing ok case"},
+			},
+		},
+	})
+}
+
+func TestNestedMatchReportsAtTheVariantPattern(t *testing.T) {
+	source := nestedMatchPrelude + `
+fn main() {
+  match lookup(1) {
+    ok(some(x)) => x,
+    err(e) => 0,
+  }
+}`
+	diags := checkSource(t, source)
+	if len(diags) != 1 {
+		t.Fatalf("diagnostics = %v, want one", diags)
+	}
+	before := source[:strings.Index(source, "ok(some(x))")]
+	want := parse.Point{
+		Row: strings.Count(before, "\n") + 1,
+		Col: len(before) - strings.LastIndex(before, "\n"),
+	}
+	if got := diags[0].Primary.Span.Location.Start; got != want {
+		t.Fatalf("non-exhaustive match reported at %v, want %v at `ok(some(x))`", got, want)
+	}
+}
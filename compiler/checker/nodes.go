@@ -3,7 +3,6 @@ package checker
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/akonwi/ard/parse"
 )
@@ -80,8 +79,20 @@ func (v *VoidLiteral) Type() Type {
 
 type IntLiteral struct {
 	Value int
+	// Limit is set on the literals that Int::MAX and Int::MIN fold to, so
+	// targets with a narrower Int can emit their own bounds instead.
+	Limit IntLimit
 }
 
+// IntLimit names the bound of Int that a literal stands for.
+type IntLimit uint8
+
+const (
+	NoIntLimit IntLimit = iota
+	IntMax
+	IntMin
+)
+
 func (i *IntLiteral) String() string {
 	return strconv.Itoa(i.Value)
 }
@@ -126,6 +137,8 @@ func (m *MapLiteral) Type() Type {
 
 type VariableDef struct {
 	Mutable bool
+	Const   bool
+	Private bool
 	Name    string
 	__type  Type
 	Value   Expression
@@ -148,6 +161,17 @@ func (r *Reassignment) Type() Type {
 	return Void
 }
 
+// Assert is a checked `assert` or `ensure` statement. Body binds compared
+// operands to hidden locals, then panics when the condition is false, so
+// each operand is evaluated once and can be printed in the failure message.
+type Assert struct {
+	// Debug marks `assert`, which release builds drop. `ensure` always runs.
+	Debug bool
+	Body  []Statement
+}
+
+func (a *Assert) NonProducing() {}
+
 type Defer struct {
 	Expr Expression
 	Body *Block
@@ -252,6 +276,41 @@ type ScalarFrom struct {
 
 func (s *ScalarFrom) Type() Type { return s.Target }
 
+// NumberParse is Int::parse(text) or Float64::parse(text). Target is Int or
+// Float64, and a Str that is not a number of that type is an error.
+type NumberParse struct {
+	Text   Expression
+	Target Type
+}
+
+func (n *NumberParse) Type() Type { return MakeResult(n.Target, Str) }
+
+// StrFormat is Str::format(template, args..., name: value). Arguments are
+// Str, Bool, Int, Byte, or Float64 values; other types are converted with
+// to_str first, so they format as Str.
+type StrFormat struct {
+	Template Expression
+	Args     []Expression
+	Named    []FormatNamedArg
+}
+
+type FormatNamedArg struct {
+	Name  string
+	Value Expression
+}
+
+func (s *StrFormat) Type() Type { return Str }
+
+// InspectValue renders a value as Str the way it is written in Ard source,
+// like `Point{x: 1, y: 2}` or `some([1, 2])`. It is the builtin
+// inspect(value) and the conversion interpolation falls back to for values
+// without to_str.
+type InspectValue struct {
+	Value Expression
+}
+
+func (i *InspectValue) Type() Type { return Str }
+
 type ForeignFieldAccess struct {
 	Subject Expression
 	Target  string
@@ -315,6 +374,18 @@ const (
 	StrEndsWith
 	StrToStr
 	StrTrim
+	StrTrimStart
+	StrTrimEnd
+	StrSlice
+	StrIndexOf
+	StrToUpper
+	StrToLower
+	StrPadLeft
+	StrPadRight
+	StrRepeat
+	StrReverse
+	StrChars
+	StrGraphemes
 )
 
 type StrMethod struct {
@@ -343,8 +414,13 @@ func (s *StrMethod) Type() Type {
 		return Bool
 	case StrToStr:
 		return Str
-	case StrTrim:
+	case StrTrim, StrTrimStart, StrTrimEnd, StrSlice, StrToUpper, StrToLower,
+		StrPadLeft, StrPadRight, StrRepeat, StrReverse:
 		return Str
+	case StrIndexOf:
+		return MakeMaybe(Int)
+	case StrChars, StrGraphemes:
+		return MakeList(Str)
 	default:
 		return Void
 	}
@@ -401,19 +477,26 @@ type IntMethodKind uint8
 const (
 	IntToStr IntMethodKind = iota
 	IntToF64
+	IntAbs
+	IntPow
+	IntClamp
+	IntToFloat
 )
 
 type IntMethod struct {
 	Subject Expression
 	Kind    IntMethodKind
+	Args    []Expression
 }
 
 func (m *IntMethod) Type() Type {
 	switch m.Kind {
 	case IntToStr:
 		return Str
-	case IntToF64:
+	case IntToF64, IntToFloat:
 		return Float64
+	case IntAbs, IntPow, IntClamp:
+		return Int
 	default:
 		return Void
 	}
@@ -424,6 +507,9 @@ type FloatMethodKind uint8
 const (
 	FloatToStr FloatMethodKind = iota
 	FloatToInt
+	FloatRound
+	FloatFloor
+	FloatCeil
 )
 
 type FloatMethod struct {
@@ -435,7 +521,7 @@ func (m *FloatMethod) Type() Type {
 	switch m.Kind {
 	case FloatToStr:
 		return Str
-	case FloatToInt:
+	case FloatToInt, FloatRound, FloatFloor, FloatCeil:
 		return Int
 	default:
 		return Void
@@ -769,7 +855,11 @@ type EnumMatch struct {
 	Cases               []*Block
 	CatchAll            *Block
 	DiscriminantToIndex map[int]int // Pre-computed discriminant lookup
-	ResultType          Type
+	// Bindings holds, per variant, the names a case pattern gives the
+	// variant's payload values, e.g. `Event::Click(x, y)`. An entry is nil
+	// when the case binds nothing and "" for a payload value matched by `_`.
+	Bindings   [][]string
+	ResultType Type
 }
 
 func (e *EnumMatch) Type() Type {
@@ -1154,7 +1244,10 @@ type ForIntRange struct {
 	Index  string
 	Start  Expression
 	End    Expression
-	Body   *Block
+	// Step is added to the cursor after each iteration; nil means 1. A
+	// negative step counts down to End.
+	Step Expression
+	Body *Block
 }
 
 func (f ForIntRange) NonProducing() {}
@@ -1238,6 +1331,9 @@ type FunctionDef struct {
 	// instantiated independently at each call. They exclude receiver and
 	// enclosing-declaration generics referenced by methods and closures.
 	CallGenericParams []string
+	// Hashable names the generics the body uses as map keys. Every call must
+	// bind them to types that can key a map.
+	Hashable []string
 	// DefaultVoidGeneric identifies a builtin adapter output that is
 	// unobservable when every path returns none/error and may therefore resolve
 	// to Void. User functions never set this field.
@@ -1253,7 +1349,10 @@ type FunctionDef struct {
 	IsTest                  bool
 	Body                    *Block
 	Private                 bool
-	GenericBindings         map[string]Type
+	// Deprecated is the note of a `@deprecated` attribute; empty when the
+	// function is not deprecated.
+	Deprecated      string
+	GenericBindings map[string]Type
 }
 
 // String renders the function's *type* in Ard syntax (`fn(Str) Int`), never
@@ -1366,6 +1465,18 @@ func (p *ForeignFunctionCall) Type() Type {
 	return p.Call.Type()
 }
 
+// BoundMethod is an Ard method used as a function value: `point.distance`,
+// or `Point::distance` taking the receiver first. Function is the closure
+// that calls the method. When Receiver is set, it is evaluated once where the
+// value is created and Function reads it from a local named ReceiverName.
+type BoundMethod struct {
+	Receiver     Expression
+	ReceiverName string
+	Function     *FunctionDef
+}
+
+func (b *BoundMethod) Type() Type { return b.Function }
+
 type ForeignMethodValue struct {
 	Subject            Expression
 	Target             string
@@ -1467,8 +1578,9 @@ func (p *ModuleSymbol) Type() Type {
 }
 
 type EnumValue struct {
-	Name  string
-	Value int // The computed integer discriminant
+	Name    string
+	Value   int    // The computed integer discriminant
+	Payload []Type // The types of the variant's associated data, if any
 }
 
 type Enum struct {
@@ -1518,6 +1630,17 @@ func (e Enum) equal(other Type) bool {
 	}
 	return true
 }
+
+// HasPayload reports whether any variant carries associated data. Such enums
+// are tagged values rather than plain integers.
+func (e Enum) HasPayload() bool {
+	for _, value := range e.Values {
+		if len(value.Payload) > 0 {
+			return true
+		}
+	}
+	return false
+}
 func (e Enum) get(name string) Type {
 	if method, ok := e.Methods[name]; ok {
 		return method
@@ -1530,6 +1653,13 @@ func (e Enum) get(name string) Type {
 			}
 		}
 	}
+	if name == "name" {
+		return &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{},
+			ReturnType: Str,
+		}
+	}
 	return nil
 }
 
@@ -1547,6 +1677,8 @@ type EnumVariant struct {
 	Variant      int
 	EnumType     Type // Pre-computed by checker
 	Discriminant int  // Pre-computed by checker
+	// Args are the variant's payload values, in declaration order.
+	Args []Expression
 }
 
 func (ev EnumVariant) Type() Type {
@@ -1561,11 +1693,57 @@ func (ev EnumVariant) String() string {
 	return fmt.Sprintf("%s::%s", ev.enum.Name, ev.enum.Values[ev.Variant].Name)
 }
 
+type EnumMethodKind uint8
+
+const (
+	EnumName EnumMethodKind = iota
+)
+
+// EnumMethod is a built-in method on an enum value. User-defined methods of
+// the same name take precedence.
+type EnumMethod struct {
+	Subject Expression
+	Kind    EnumMethodKind
+}
+
+func (m *EnumMethod) Type() Type {
+	switch m.Kind {
+	case EnumName:
+		return Str
+	default:
+		return Void
+	}
+}
+
+// EnumValues is `Enum::values()`, every variant in declaration order.
+type EnumValues struct {
+	Enum *Enum
+}
+
+func (v *EnumValues) Type() Type {
+	return MakeList(v.Enum)
+}
+
+// EnumFromInt is `Enum::from_int(n)`, the variant whose discriminant is n,
+// or none.
+type EnumFromInt struct {
+	Enum  *Enum
+	Value Expression
+}
+
+func (f *EnumFromInt) Type() Type {
+	return MakeMaybe(f.Enum)
+}
+
 type Union struct {
 	Name       string
 	ModulePath string
 	Types      []Type
 	Private    bool
+	// Inline is true for a union written in place, such as `Int | Str`.
+	// Its Name is its members in canonical order, and it equals any other
+	// inline union with the same members, whichever module wrote it.
+	Inline bool
 }
 
 func (u Union) NonProducing() {}
@@ -1599,9 +1777,11 @@ func (u Union) hasTrait(trait *Trait) bool {
 }
 
 type StructDef struct {
-	Name          string
-	ModulePath    string
-	Fields        map[string]Type
+	Name       string
+	ModulePath string
+	Fields     map[string]Type
+	// FieldOrder names the fields in declaration order.
+	FieldOrder    []string
 	Self          string
 	Traits        []*Trait
 	GenericParams []string
@@ -1615,6 +1795,15 @@ type StructDef struct {
 	// field templates; applications own only their ordered TypeArgs.
 	Definition *StructDef
 	Private    bool
+	// Deprecated is the note of a `@deprecated` attribute; empty when the
+	// struct is not deprecated.
+	Deprecated string
+	// PrivateFields names the fields declared `private`. Only the declaring
+	// module may read them or construct the struct with a literal.
+	PrivateFields map[string]bool
+	// Defaults holds the constant value of each field declared with a
+	// default. Instances that omit such a field take this value.
+	Defaults map[string]Expression
 }
 
 func (def StructDef) NonProducing() {}
@@ -1624,14 +1813,7 @@ func (def *StructDef) name() string {
 }
 
 func (def StructDef) String() string {
-	if len(def.TypeArgs) == 0 || strings.Contains(def.Name, "<") {
-		return def.name()
-	}
-	parts := make([]string, len(def.TypeArgs))
-	for i, arg := range def.TypeArgs {
-		parts[i] = arg.String()
-	}
-	return fmt.Sprintf("%s<%s>", def.name(), strings.Join(parts, ", "))
+	return formatType(&def)
 }
 func (def StructDef) get(name string) Type {
 	// Struct type identity describes value shape. Method namespaces live on the
@@ -1672,8 +1854,11 @@ func (def StructDef) hasTrait(trait *Trait) bool {
 }
 
 type StructInstance struct {
-	Name       string
-	Fields     map[string]Expression
+	Name   string
+	Fields map[string]Expression
+	// Base is the `..base` value whose fields fill in those not listed in
+	// Fields; nil when the literal has no base.
+	Base       Expression
 	_type      *StructDef
 	FieldTypes map[string]Type // Pre-computed by checker
 	StructType Type            // Pre-computed by checker
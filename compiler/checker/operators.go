@@ -0,0 +1,72 @@
+package checker
+
+import "github.com/akonwi/ard/parse"
+
+// arithmeticOperatorMethods names the method that implements each arithmetic
+// operator on a struct that overloads operators.
+var arithmeticOperatorMethods = map[parse.Operator]string{
+	parse.Plus:     "add",
+	parse.Minus:    "sub",
+	parse.Multiply: "mul",
+	parse.Divide:   "div",
+	parse.Modulo:   "rem",
+}
+
+// operatorStruct reports whether operators on values of t dispatch to the
+// type's methods. Only ard/bigint's BigInt does: it wraps each target's own
+// big integers, which the primitive operator nodes cannot reach.
+func operatorStruct(t Type) (*StructDef, bool) {
+	def, ok := t.(*StructDef)
+	return def, ok && def.Name == "BigInt" && def.ModulePath == "ard/bigint"
+}
+
+// operatorMethodCall calls the method named method on subject, or returns
+// nil when def has no such method.
+func (c *Checker) operatorMethodCall(def *StructDef, method string, subject Expression, args ...Expression) Expression {
+	fn, ok := c.structMethod(def, method)
+	if !ok {
+		return nil
+	}
+	return &InstanceMethod{
+		Subject:      subject,
+		Method:       &FunctionCall{Name: method, Args: args, fn: fn, ReturnType: fn.ReturnType},
+		ReceiverKind: ReceiverStruct,
+		StructType:   def,
+	}
+}
+
+// dispatchOperator lowers `left op right` to a method call when both
+// operands are the same operator struct: arithmetic to add, sub, mul, div, and
+// rem, equality to equals, and ordering to compare. It reports false for any
+// other operands, which keep the primitive rules.
+func (c *Checker) dispatchOperator(op parse.Operator, left, right Expression) (Expression, bool) {
+	def, ok := operatorStruct(left.Type())
+	if !ok || !left.Type().equal(right.Type()) {
+		return nil, false
+	}
+	if method, ok := arithmeticOperatorMethods[op]; ok {
+		return c.operatorMethodCall(def, method, left, right), true
+	}
+	var compare Expression
+	switch op {
+	case parse.Equal:
+		return c.operatorMethodCall(def, "equals", left, right), true
+	case parse.NotEqual:
+		return &Not{c.operatorMethodCall(def, "equals", left, right)}, true
+	case parse.LessThan, parse.LessThanOrEqual, parse.GreaterThan, parse.GreaterThanOrEqual:
+		compare = c.operatorMethodCall(def, "compare", left, right)
+	default:
+		return nil, false
+	}
+	zero := &IntLiteral{Value: 0}
+	switch op {
+	case parse.LessThan:
+		return &IntLess{compare, zero}, true
+	case parse.LessThanOrEqual:
+		return &IntLessEqual{compare, zero}, true
+	case parse.GreaterThan:
+		return &IntGreater{compare, zero}, true
+	default:
+		return &IntGreaterEqual{compare, zero}, true
+	}
+}
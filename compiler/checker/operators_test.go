@@ -0,0 +1,65 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+func TestBigIntOperatorsDispatchToMethods(t *testing.T) {
+	source := `use ard/bigint
+let a = bigint::BigInt::new(2)
+let b = bigint::BigInt::new(3)
+a + b
+-a
+a < b
+a != b
+`
+	result := parse.Parse([]byte(source), "test.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	c := checker.New("test.ard", result.Program, nil)
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("diagnostics: %v", c.Diagnostics())
+	}
+	statements := c.Module().Program().Statements
+	sum, ok := statements[2].Expr.(*checker.InstanceMethod)
+	if !ok || sum.Method.Name != "add" || sum.Type().String() != "BigInt" {
+		t.Fatalf("a + b = %#v", statements[2].Expr)
+	}
+	if neg, ok := statements[3].Expr.(*checker.InstanceMethod); !ok || neg.Method.Name != "neg" {
+		t.Fatalf("-a = %#v", statements[3].Expr)
+	}
+	less, ok := statements[4].Expr.(*checker.IntLess)
+	if !ok {
+		t.Fatalf("a < b = %#v", statements[4].Expr)
+	}
+	if compare, ok := less.Left.(*checker.InstanceMethod); !ok || compare.Method.Name != "compare" {
+		t.Fatalf("a < b compares %#v", less.Left)
+	}
+	if _, ok := statements[5].Expr.(*checker.Not); !ok {
+		t.Fatalf("a != b = %#v", statements[5].Expr)
+	}
+}
+
+func TestBigIntOperatorsRequireBigIntOperands(t *testing.T) {
+	run(t, []test{
+		{
+			name:  "BigInt and Int do not mix",
+			input: "use ard/bigint\nlet a = bigint::BigInt::new(2)\na + 1",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Cannot add different types"},
+			},
+		},
+		{
+			name:  "other structs keep the primitive rules",
+			input: "struct Point { x: Int }\nlet p = Point{x: 1}\np + p",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "The '+' operator can only be used for Int or Float64"},
+			},
+		},
+	})
+}
@@ -0,0 +1,123 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// orPatternAlternatives lists the patterns of a match arm: each side of an
+// or-pattern like `1 | 2 | 3`, or the pattern itself.
+func orPatternAlternatives(pattern parse.Expression) []parse.Expression {
+	if or, ok := pattern.(*parse.OrPattern); ok {
+		return or.Patterns
+	}
+	return []parse.Expression{pattern}
+}
+
+// intPatternValue resolves a single-value pattern of an Int match: an integer
+// literal, a negated literal, an Int constant, or an enum variant.
+func (c *Checker) intPatternValue(pattern parse.Expression) (int, bool) {
+	switch p := pattern.(type) {
+	case *parse.NumLiteral:
+		value, err := parseIntLiteral(p.Value)
+		if err != nil {
+			legacy := fmt.Sprintf("Invalid integer literal: %s", p.Value)
+			c.addInvalidMatchPattern(legacy, p.GetLocation(), "this is not a valid integer pattern")
+			return 0, false
+		}
+		return int(value), true
+	case *parse.Identifier:
+		// Int constants like MAX
+		value, err := c.extractIntFromPattern(p)
+		if err != nil {
+			c.addInvalidMatchPattern(fmt.Sprintf("Invalid pattern for Int match: %s", err.Error()), p.GetLocation(), "expected an Int constant")
+			return 0, false
+		}
+		return value, true
+	case *parse.UnaryExpression:
+		// Negative numbers like -1
+		if literal, ok := p.Operand.(*parse.NumLiteral); ok && p.Operator == parse.Minus {
+			value, err := parseIntLiteral(literal.Value)
+			if err != nil {
+				legacy := fmt.Sprintf("Invalid integer literal: %s", literal.Value)
+				c.addInvalidMatchPattern(legacy, literal.GetLocation(), "this is not a valid integer pattern")
+				return 0, false
+			}
+			return -int(value), true
+		}
+	case *parse.StaticProperty:
+		// An Int constant of another module or an enum variant like Status::active
+		switch resolved := c.checkExpr(p).(type) {
+		case nil:
+			return 0, false
+		case *IntLiteral:
+			return resolved.Value, true
+		case *EnumVariant:
+			// custom enum values are matched by value
+			return resolved.enum.Values[resolved.Variant].Value, true
+		default:
+			c.addInvalidMatchPattern("Pattern in Int match must be an integer literal, range, constant, or enum variant", p.GetLocation(), "this does not resolve to an Int constant or enum variant")
+			return 0, false
+		}
+	case *parse.RangeExpression:
+		c.addInvalidMatchPattern("Range patterns cannot be joined with |", p.Start.GetLocation(), "give this range its own arm")
+		return 0, false
+	}
+	legacy := fmt.Sprintf("Invalid pattern for Int match: %T", pattern)
+	c.addInvalidMatchPattern(legacy, pattern.GetLocation(), "expected an integer literal, range, enum variant, or `_`")
+	return 0, false
+}
+
+// enumPatternCases records the variants an enum match has covered, by value,
+// with the name and span of the arm that covered each.
+type enumPatternCases map[int]struct {
+	Name string
+	Span SourceSpan
+}
+
+// enumPatternVariant resolves an `Enum::Variant` pattern of an enum match to
+// the variant's index and records it in seen, reporting variants of another
+// enum and values an earlier arm already covers.
+func (c *Checker) enumPatternVariant(enumType *Enum, staticProp *parse.StaticProperty, seen enumPatternCases) (int, bool) {
+	// Resolve the pattern using existing expression resolution logic
+	c.enumPatternContext = true
+	patternExpr := c.checkExpr(staticProp)
+	c.enumPatternContext = false
+	if patternExpr == nil {
+		return 0, false // Error already reported by checkExpr
+	}
+
+	// Check if the pattern resolves to an enum variant
+	enumVariant, ok := patternExpr.(*EnumVariant)
+	if !ok {
+		c.addInvalidMatchPattern("Pattern in enum match must be an enum variant", staticProp.GetLocation(), "this does not resolve to an enum variant")
+		return 0, false
+	}
+
+	// Verify that the variant's enum matches the subject's enum
+	if !enumVariant.enum.equal(enumType) {
+		legacy := fmt.Sprintf("Cannot match %s variant against %s enum", enumVariant.enum.Name, enumType.Name)
+		c.addInvalidMatchPattern(legacy, staticProp.GetLocation(), fmt.Sprintf("this variant belongs to `%s`, not `%s`", enumVariant.enum.Name, enumType.Name))
+		return 0, false
+	}
+
+	// Check for duplicate cases by value, not just by name. This lets Go
+	// enum-like constants import aliases while preserving closed enum
+	// exhaustiveness over distinct values.
+	discriminant := enumType.Values[enumVariant.Variant].Value
+	current := fmt.Sprintf("%s::%s", enumType.Name, enumType.Values[enumVariant.Variant].Name)
+	if previous, found := seen[discriminant]; found {
+		legacy := fmt.Sprintf("Duplicate case: %s", current)
+		if previous.Name != current {
+			legacy = fmt.Sprintf("Duplicate case: %s has same value as %s", current, previous.Name)
+		}
+		c.addDuplicateMatchArm(Error, legacy, staticProp.GetLocation(), &previous.Span)
+		return 0, false
+	}
+	seen[discriminant] = struct {
+		Name string
+		Span SourceSpan
+	}{Name: current, Span: c.sourceSpan(staticProp.GetLocation())}
+	return int(enumVariant.Variant), true
+}
@@ -0,0 +1,104 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestOrPatterns(t *testing.T) {
+	run(t, []test{
+		{
+			name: "or-patterns over Int, Str, and enum values",
+			input: `enum Color { red, green, blue }
+
+const LIMIT = 10
+
+fn size(n: Int) Str {
+  match n {
+    1 | 2 | -1 => "few",
+    3 | LIMIT => "some",
+    _ => "many",
+  }
+}
+
+fn vowel(s: Str) Bool {
+  match s {
+    "a" | "e" | "i" | "o" | "u" => true,
+    _ => false,
+  }
+}
+
+fn warm(c: Color) Bool {
+  match c {
+    Color::red | Color::green => true,
+    Color::blue => false,
+  }
+}`,
+		},
+		{
+			name: "a value covered twice is a duplicate",
+			input: `fn size(n: Int) {
+  match n {
+    1 | 2 => "few",
+    2 | 3 => "some",
+    _ => "many",
+  }
+}
+
+fn vowel(s: Str) {
+  match s {
+    "a" | "a" => true,
+    _ => false,
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Duplicate case: 2"},
+				{Kind: checker.Error, Message: `Duplicate case: "a"`},
+			},
+		},
+		{
+			name: "enum or-patterns count toward exhaustiveness and catch duplicates",
+			input: `enum Color { red, green, blue }
+
+fn warm(c: Color) Bool {
+  match c {
+    Color::red | Color::green => true,
+  }
+}
+
+fn cool(c: Color) Bool {
+  match c {
+    Color::blue | Color::blue => true,
+    _ => false,
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Incomplete match: missing case for 'Color::blue'"},
+				{Kind: checker.Error, Message: "Duplicate case: Color::blue"},
+			},
+		},
+		{
+			name: "ranges and payload bindings cannot be joined",
+			input: `enum Shape { circle(Float64), dot }
+
+fn size(n: Int) {
+  match n {
+    1 | 5..9 => "some",
+    _ => "many",
+  }
+}
+
+fn round(s: Shape) {
+  match s {
+    Shape::circle(r) | Shape::dot => true,
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Range patterns cannot be joined with |"},
+				{Kind: checker.Error, Message: "Patterns joined with | must be enum variants without bindings"},
+				{Kind: checker.Error, Message: "Incomplete match: missing case for 'Shape::circle'"},
+			},
+		},
+	})
+}
@@ -0,0 +1,37 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestPipeExpressions(t *testing.T) {
+	run(t, []test{
+		{
+			name: "the piped value is the first argument",
+			input: `use ard/list
+
+fn double(x: Int) Int { x * 2 }
+fn add(x: Int, y: Int) Int { x + y }
+
+fn main() {
+  let n: Int = 3 |> double |> add(1)
+  let names: [Str] = [1, 2] |> list::map(fn(x: Int) Str { "{x}" })
+  let inc = fn(x: Int) Int { x + 1 }
+  let m: Int = n |> inc
+}`,
+		},
+		{
+			name: "the piped value is checked against the first parameter",
+			input: `fn add(x: Int, y: Int) Int { x + y }
+
+fn main() {
+  let n = "one" |> add(1)
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Str"},
+			},
+		},
+	})
+}
@@ -0,0 +1,225 @@
+package checker
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// supportedPragmas lists the arguments each module pragma accepts.
+var supportedPragmas = map[string][]string{
+	"deny":   {"warnings"},
+	"forbid": {"dynamic", "nondeterminism"},
+}
+
+// checkPragmas validates the module's `#name(arg)` directives and records the
+// stricter rules they opt into.
+func (c *Checker) checkPragmas() {
+	for _, pragma := range c.input.Pragmas {
+		accepted, ok := supportedPragmas[pragma.Name]
+		if !ok {
+			c.addDiagnostic(invalidPragmaDiagnostic{
+				Reason: fmt.Sprintf("unknown pragma #%s", pragma.Name),
+				Label:  "unknown pragma",
+				Span:   c.sourceSpan(pragma.NameLocation),
+			}.build())
+			continue
+		}
+		if len(pragma.Args) == 0 {
+			c.addDiagnostic(invalidPragmaDiagnostic{
+				Reason: fmt.Sprintf("#%s needs an argument", pragma.Name),
+				Label:  fmt.Sprintf("expected `#%s(%s)`", pragma.Name, accepted[0]),
+				Span:   c.sourceSpan(pragma.Location),
+			}.build())
+			continue
+		}
+		for _, arg := range pragma.Args {
+			span := c.sourceSpan(pragma.Location)
+			switch {
+			case pragma.Name == "deny" && arg == "warnings":
+				c.denyWarnings = &span
+			case pragma.Name == "forbid" && arg == "dynamic":
+				c.forbidDynamic = &span
+			case pragma.Name == "forbid" && arg == "nondeterminism":
+				c.forbidNondeterminism = &span
+			default:
+				c.addDiagnostic(invalidPragmaDiagnostic{
+					Reason: fmt.Sprintf("#%s does not accept %s", pragma.Name, arg),
+					Label:  fmt.Sprintf("expected `#%s(%s)`", pragma.Name, accepted[0]),
+					Span:   span,
+				}.build())
+			}
+		}
+	}
+}
+
+// checkDynamicAllowed reports an Any type or value when the module forbids
+// dynamic values. Each location is reported once.
+func (c *Checker) checkDynamicAllowed(location parse.Location, label string) {
+	if c.forbidDynamic == nil {
+		return
+	}
+	if c.reportedDynamic == nil {
+		c.reportedDynamic = map[parse.Location]bool{}
+	}
+	if c.reportedDynamic[location] {
+		return
+	}
+	c.reportedDynamic[location] = true
+	c.addDiagnostic(forbiddenDynamicDiagnostic{
+		Label:      label,
+		Span:       c.sourceSpan(location),
+		PragmaSpan: *c.forbidDynamic,
+	}.build())
+}
+
+// enforceDenyWarnings applies `#deny(warnings)` once the module is checked:
+// unused imports and deprecated syntax are reported, and every warning from
+// this module, including those from Warnings, becomes an error.
+func (c *Checker) enforceDenyWarnings() {
+	if c.denyWarnings == nil {
+		return
+	}
+	used := parse.ImportUses(c.input)
+	for _, imp := range c.input.Imports {
+		if imp.ReExport != "" || used[imp.Name] {
+			continue
+		}
+		c.addDiagnostic(unusedImportDiagnostic{Name: imp.Name, Span: c.sourceSpan(imp.Location)}.build())
+	}
+	c.diagnostics = append(c.diagnostics, DeprecatedSyntaxDiagnostics(c.filePath, c.input.Legacy)...)
+
+	for i, diagnostic := range c.diagnostics {
+		if diagnostic.Kind == Warn && diagnostic.FilePath() == c.filePath {
+			c.diagnostics[i] = deniedWarning(diagnostic, *c.denyWarnings)
+		}
+	}
+	for _, warning := range c.warnings {
+		c.diagnostics = append(c.diagnostics, deniedWarning(warning, *c.denyWarnings))
+	}
+	c.warnings = nil
+}
+
+// deterministicGoPackages are the Go packages whose functions compute their
+// results from their arguments alone. A `#forbid(nondeterminism)` module may
+// import only these, so a package not listed, such as `time`, `os`,
+// `path/filepath`, or a third-party package, is rejected. Some otherwise pure
+// packages are left out for a single source of variation: `fmt` scans
+// standard input, `maps` and `hash/maphash` are randomly ordered and seeded,
+// `sync` exists for concurrent code, and `crypto/cipher` draws random nonces.
+var deterministicGoPackages = map[string]bool{
+	"bufio":            true,
+	"bytes":            true,
+	"cmp":              true,
+	"container/heap":   true,
+	"container/list":   true,
+	"container/ring":   true,
+	"crypto":           true,
+	"crypto/aes":       true,
+	"crypto/hkdf":      true,
+	"crypto/hmac":      true,
+	"crypto/md5":       true,
+	"crypto/pbkdf2":    true,
+	"crypto/sha1":      true,
+	"crypto/sha256":    true,
+	"crypto/sha3":      true,
+	"crypto/sha512":    true,
+	"crypto/subtle":    true,
+	"encoding":         true,
+	"encoding/ascii85": true,
+	"encoding/base32":  true,
+	"encoding/base64":  true,
+	"encoding/binary":  true,
+	"encoding/csv":     true,
+	"encoding/hex":     true,
+	"encoding/json":    true,
+	"encoding/pem":     true,
+	"encoding/xml":     true,
+	"errors":           true,
+	"hash":             true,
+	"hash/adler32":     true,
+	"hash/crc32":       true,
+	"hash/crc64":       true,
+	"hash/fnv":         true,
+	"html":             true,
+	"io":               true,
+	"iter":             true,
+	"math":             true,
+	"math/big":         true,
+	"math/bits":        true,
+	"math/cmplx":       true,
+	"net/netip":        true,
+	"net/url":          true,
+	"path":             true,
+	"regexp":           true,
+	"regexp/syntax":    true,
+	"slices":           true,
+	"sort":             true,
+	"strconv":          true,
+	"strings":          true,
+	"text/scanner":     true,
+	"text/tabwriter":   true,
+	"unicode":          true,
+	"unicode/utf16":    true,
+	"unicode/utf8":     true,
+}
+
+// checkDeterministicImports applies `#forbid(nondeterminism)`: the module may
+// only import deterministic Go packages, directly or through the Ard modules
+// it imports. Clocks, randomness, and I/O reach it as arguments instead.
+func (c *Checker) checkDeterministicImports() {
+	if c.forbidNondeterminism == nil {
+		return
+	}
+	for _, imp := range c.input.Imports {
+		source, via := "", ""
+		if imp.Kind == parse.ImportKindGo {
+			if deterministicGoPackages[imp.Path] {
+				continue
+			}
+			source = imp.Path
+		} else {
+			mod, ok := c.program.Imports[imp.Name]
+			if !ok {
+				continue
+			}
+			found, ok := nondeterministicSource(mod, map[Module]bool{})
+			if !ok {
+				continue
+			}
+			source, via = found, imp.Path
+		}
+		c.addDiagnostic(forbiddenNondeterminismDiagnostic{
+			Source:     source,
+			Via:        via,
+			Span:       c.sourceSpan(imp.PathLocation),
+			PragmaSpan: *c.forbidNondeterminism,
+		}.build())
+	}
+}
+
+// nondeterministicSource finds a Go package outside deterministicGoPackages
+// that mod imports, directly or through its own imports.
+func nondeterministicSource(mod Module, seen map[Module]bool) (string, bool) {
+	if mod == nil || seen[mod] {
+		return "", false
+	}
+	seen[mod] = true
+	program := mod.Program()
+	if program == nil {
+		return "", false
+	}
+	for _, alias := range slices.Sorted(maps.Keys(program.GoImports)) {
+		if pkg := program.GoImports[alias]; pkg != nil && !deterministicGoPackages[pkg.Path] {
+			return pkg.Path, true
+		}
+	}
+	for _, path := range slices.Sorted(maps.Keys(program.Imports)) {
+		if source, ok := nondeterministicSource(program.Imports[path], seen); ok {
+			return source, true
+		}
+	}
+	return "", false
+}
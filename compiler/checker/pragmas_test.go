@@ -0,0 +1,124 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+	"github.com/akonwi/ard/version"
+)
+
+func TestModulePragmas(t *testing.T) {
+	run(t, []test{
+		{
+			name:  "unknown pragmas are rejected",
+			input: "#strict(all)\n#deny(dynamic)\n#forbid()\nfn main() {}",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Invalid pragma: unknown pragma #strict"},
+				{Kind: checker.Error, Message: "Invalid pragma: #deny does not accept dynamic"},
+				{Kind: checker.Error, Message: "Invalid pragma: #forbid needs an argument"},
+			},
+		},
+		{
+			name:  "forbid(dynamic) rejects Any annotations",
+			input: "#forbid(dynamic)\nfn id(value: Any) Int { 1 }",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Dynamic values are forbidden in this module"},
+			},
+		},
+		{
+			name:  "forbid(dynamic) rejects foreign values typed Any",
+			input: "#forbid(dynamic)\nuse go:context\n\nfn main() {\n  let value = context::Background().Value(\"key\")\n  let same = value\n}",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Dynamic values are forbidden in this module"},
+			},
+		},
+		{
+			name:        "Any is allowed without the pragma",
+			input:       "use go:context\n\nfn id(value: Any) Any { value }\nlet value = id(context::Background().Value(\"key\"))",
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name:  "forbid(nondeterminism) rejects clock, random, file, and network imports",
+			input: "#forbid(nondeterminism)\nuse go:time\nuse go:math/rand/v2\nuse go:net/http\nuse go:strings\n\nfn count() Int {\n  strings::Count(\"aa\", \"a\")\n}",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Nondeterministic imports are forbidden in this module"},
+				{Kind: checker.Error, Message: "Nondeterministic imports are forbidden in this module"},
+				{Kind: checker.Error, Message: "Nondeterministic imports are forbidden in this module"},
+			},
+		},
+		{
+			name:  "forbid(nondeterminism) rejects packages not known to be deterministic",
+			input: "#forbid(nondeterminism)\nuse go:path/filepath\nuse go:runtime\nuse go:fmt\nuse go:encoding/json\nuse go:math/big\n\nfn main() {}",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Nondeterministic imports are forbidden in this module"},
+				{Kind: checker.Error, Message: "Nondeterministic imports are forbidden in this module"},
+				{Kind: checker.Error, Message: "Nondeterministic imports are forbidden in this module"},
+			},
+		},
+		{
+			name:  "forbid(nondeterminism) rejects modules that reach nondeterministic packages",
+			input: "#forbid(nondeterminism)\nuse ard/io\nuse ard/list\n\nfn main() {}",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Nondeterministic imports are forbidden in this module"},
+			},
+		},
+		{
+			name:        "forbid(nondeterminism) allows injected effects",
+			input:       "#forbid(nondeterminism)\n\nfn elapsed(now: fn() Int, start: Int) Int {\n  now() - start\n}",
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name:  "deny(warnings) reports unused imports",
+			input: "#deny(warnings)\nuse ard/list\nuse ard/map as dict\nuse go:strings\n\nfn main() {\n  let total = list::new<Int>().size() + strings::Count(\"a\", \"a\")\n}",
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Unused import: dict"},
+			},
+		},
+		{
+			name:        "unused imports are allowed without the pragma",
+			input:       "use ard/list\n\nfn main() {}",
+			diagnostics: []checker.Diagnostic{},
+		},
+	})
+}
+
+func TestDenyWarningsTurnsWarningsIntoErrors(t *testing.T) {
+	const filePath = "main.ard"
+	result := parse.Parse([]byte("#deny(warnings)\nuse ard/list as shared\nuse ard/map as shared\n\nlet size = shared::new<Int>().size()\n"), filePath)
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+
+	c := checker.New(filePath, result.Program, nil)
+	c.Check()
+	if len(c.Diagnostics()) != 1 {
+		t.Fatalf("diagnostics = %#v, want one", c.Diagnostics())
+	}
+	diagnostic := c.Diagnostics()[0]
+	if diagnostic.Kind != checker.Error || diagnostic.Code != checker.DiagnosticCodeDuplicateImport {
+		t.Fatalf("kind/code = %q/%q", diagnostic.Kind, diagnostic.Code)
+	}
+	pragma := result.Program.Pragmas[0].Location
+	last := diagnostic.Secondary[len(diagnostic.Secondary)-1]
+	if last.Span.Location != pragma || last.Message != "warnings are denied here" {
+		t.Fatalf("secondary = %#v", diagnostic.Secondary)
+	}
+}
+
+func TestDenyWarningsRejectsDeprecatedSyntax(t *testing.T) {
+	const filePath = "main.ard"
+	result := parse.Parse([]byte("#deny(warnings)\nstruct Model { n: Int }\n\nfn bump(mut m: Model) {\n  m.n = 1\n}\n"), filePath)
+	if allowed := result.AllowLegacy(version.Semver{Major: 0, Minor: 21}); len(allowed) != 1 || len(result.Errors) != 0 {
+		t.Fatalf("allowed %v, errors %v", allowed, result.Errors)
+	}
+
+	c := checker.New(filePath, result.Program, nil)
+	c.Check()
+	if len(c.Diagnostics()) != 1 {
+		t.Fatalf("diagnostics = %#v, want one", c.Diagnostics())
+	}
+	if diagnostic := c.Diagnostics()[0]; diagnostic.Kind != checker.Error || diagnostic.Code != checker.DiagnosticCodeDeprecatedSyntax {
+		t.Fatalf("kind/code = %q/%q", diagnostic.Kind, diagnostic.Code)
+	}
+}
@@ -0,0 +1,36 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+// The language server checks files with parse errors, so the checker must
+// handle whatever the parser recovers without panicking. No recover here: a
+// panic fails the test.
+func TestCheckRecoveredTrees(t *testing.T) {
+	for _, source := range []string{
+		"impl)Point {\n  fn x() Int { 1 }\n}\n\nfn main() {\n  let y: Str = 1\n}\n",
+		"trait)Named {\n  fn name() Str\n}\n\nfn main() {\n  let y: Str = 1\n}\n",
+		"struct Point {\n  x: Int\n  y: \n}\n\nfn main() {\n  let y: Str = 1\n}\n",
+		"fn broken(a: Int) Int {\n  a +\n}\n\nfn main() {\n  let y: Str = 1\n}\n",
+		"enum Dir {\n  Up,\n  Down(\n}\n\nfn main() {\n  let y: Str = 1\n}\n",
+		"fn main() {\n  let x = match {\n    1 =>\n  }\n  let y: Str = 1\n}\n",
+	} {
+		result := parse.Parse([]byte(source), "test.ard")
+		if len(result.Errors) == 0 {
+			t.Fatalf("%q: expected parse errors", source)
+		}
+		resolver, err := checker.NewModuleResolver(t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := checker.New("test.ard", result.Program, resolver, checker.CheckOptions{HasParseErrors: true})
+		c.Check()
+		if len(c.Diagnostics()) == 0 {
+			t.Errorf("%q: expected main to still be checked", source)
+		}
+	}
+}
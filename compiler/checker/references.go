@@ -0,0 +1,151 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// Reference is a site in a module's source that names a declaration: the
+// declaration itself or a use of it. References are only recorded when the
+// module was checked with CheckOptions.RecordSpans.
+type Reference struct {
+	// Key is the declaration's identity, as built by FunctionKey, TypeKey,
+	// ValueKey, MemberKey, or LocalKey. Uses in one module of a declaration in
+	// another share the declaring module's key.
+	Key string
+	// Name is the declared name.
+	Name     string
+	FilePath string
+	// Location covers the name for uses and local bindings. Other definitions
+	// span the whole declaration; the name is then its first occurrence on the first
+	// line, which NameLocation finds.
+	Location parse.Location
+	IsDef    bool
+}
+
+// NameLocation narrows the reference to the name, given the text of its
+// first line. The result's End is exclusive.
+func (r Reference) NameLocation(line string) (parse.Location, bool) {
+	loc := r.Location
+	if loc.End.Row == loc.Start.Row && loc.End.Col-loc.Start.Col+1 == len(r.Name) {
+		return parse.Location{Start: loc.Start, End: parse.Point{Row: loc.Start.Row, Col: loc.End.Col + 1}}, true
+	}
+	for idx := max(loc.Start.Col-1, 0); idx+len(r.Name) <= len(line); idx++ {
+		if line[idx:idx+len(r.Name)] != r.Name {
+			continue
+		}
+		if idx > 0 && isIdentifierByte(line[idx-1]) {
+			continue
+		}
+		if end := idx + len(r.Name); end < len(line) && isIdentifierByte(line[end]) {
+			continue
+		}
+		return parse.Location{
+			Start: parse.Point{Row: loc.Start.Row, Col: idx + 1},
+			End:   parse.Point{Row: loc.Start.Row, Col: idx + 1 + len(r.Name)},
+		}, true
+	}
+	return parse.Location{}, false
+}
+
+func isIdentifierByte(b byte) bool {
+	return b == '_' || (b >= 'a' && b <= 'z') || (b >= 'A' && b <= 'Z') || (b >= '0' && b <= '9')
+}
+
+// LocalKey builds the identity key for a local binding, which is only visible
+// in the file that declares it, from the position of its name.
+func LocalKey(filePath string, at parse.Point) string {
+	return fmt.Sprintf("local:%s:%d:%d", filePath, at.Row, at.Col)
+}
+
+// buildReferences collects the keyed records of a span index into
+// references, in source order.
+func buildReferences(filePath string, spans *SpanIndex) []Reference {
+	var refs []Reference
+	seen := map[Reference]bool{}
+	for _, rec := range spans.Records() {
+		ref, ok := referenceFor(filePath, rec)
+		if !ok || seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+func referenceFor(filePath string, rec SpanRecord) (Reference, bool) {
+	ref := Reference{FilePath: filePath, Location: rec.Loc, IsDef: rec.IsDef}
+	switch key := rec.Key.(type) {
+	case *Symbol:
+		if key.declaredAt.FilePath == "" {
+			return Reference{}, false
+		}
+		ref.Key = LocalKey(key.declaredAt.FilePath, key.declaredAt.Location.Start)
+		ref.Name = key.Name
+		if rec.IsDef {
+			// The binding's record spans its whole statement; the name's own
+			// span is kept on the symbol.
+			ref.Location = key.declaredAt.Location
+		}
+	case string:
+		ref.Key = key
+		name := key[strings.LastIndex(key, ":")+1:]
+		if _, member, ok := strings.Cut(name, "."); ok {
+			name = member
+		}
+		ref.Name = name
+	case nil:
+		if rec.Target == nil {
+			return Reference{}, false
+		}
+		target := rec.Target
+		switch target.Kind {
+		case TargetFunction:
+			ref.Key = FunctionKey(target.Module, target.Symbol)
+		case TargetType:
+			ref.Key = TypeKey(target.Module, target.Symbol)
+		case TargetValue:
+			ref.Key = ValueKey(target.Module, target.Symbol)
+		default:
+			ref.Key = MemberKey(target.Kind, target.Module, target.Owner, target.Symbol)
+		}
+		ref.Name = target.Symbol
+	default:
+		return Reference{}, false
+	}
+	return ref, ref.Name != ""
+}
+
+// References returns the sites in this module that name the declaration
+// identified by key, definition included, in source order. It returns nil
+// when the module was checked without CheckOptions.RecordSpans.
+func (m *UserModule) References(key string) []Reference {
+	var out []Reference
+	for _, ref := range m.references {
+		if ref.Key == key {
+			out = append(out, ref)
+		}
+	}
+	return out
+}
+
+// SymbolAt returns the identity key of the innermost reference containing p,
+// for looking up the declaration under a cursor.
+func (m *UserModule) SymbolAt(p parse.Point) (string, bool) {
+	best := -1
+	for i, ref := range m.references {
+		if !spanContains(ref.Location, p) {
+			continue
+		}
+		if best < 0 || spanSize(ref.Location) < spanSize(m.references[best].Location) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	return m.references[best].Key, true
+}
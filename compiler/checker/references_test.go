@@ -0,0 +1,127 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+func referencesModule(t *testing.T, dir, filePath, source string) (checker.Module, *checker.Program) {
+	t.Helper()
+	result := parse.Parse([]byte(source), filePath)
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	resolver, err := checker.NewModuleResolver(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checker.New(filePath, result.Program, resolver, checker.CheckOptions{RecordSpans: true})
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("check errors: %v", c.Diagnostics())
+	}
+	module := c.Module()
+	return module, module.Program()
+}
+
+func TestReferencesOfLocalBinding(t *testing.T) {
+	source := `fn main() {
+  let count = 1
+  let doubled = count + count
+}
+`
+	module, _ := referencesModule(t, t.TempDir(), "test.ard", source)
+	key, ok := module.(*checker.UserModule).SymbolAt(parse.Point{Row: 3, Col: 18})
+	if !ok {
+		t.Fatal("no symbol at a use of count")
+	}
+	refs := module.References(key)
+	if len(refs) != 3 {
+		t.Fatalf("expected the definition and 2 uses of count, got %+v", refs)
+	}
+	def := refs[0]
+	if !def.IsDef || def.Name != "count" {
+		t.Fatalf("first reference is %+v, want the definition of count", def)
+	}
+	if want := (parse.Point{Row: 2, Col: 7}); def.Location.Start != want {
+		t.Fatalf("definition starts at %v, want the name at %v", def.Location.Start, want)
+	}
+	for _, ref := range refs[1:] {
+		if ref.IsDef || ref.Location.Start.Row != 3 {
+			t.Fatalf("unexpected use %+v", ref)
+		}
+	}
+}
+
+func TestReferencesOfFunctionIncludeDefinitionAndCalls(t *testing.T) {
+	source := `fn helper() Int {
+  1
+}
+
+fn main() {
+  let a = helper()
+  let b = helper()
+}
+`
+	module, _ := referencesModule(t, t.TempDir(), "test.ard", source)
+	refs := module.References(checker.FunctionKey("test.ard", "helper"))
+	defs, uses := 0, 0
+	for _, ref := range refs {
+		if ref.Name != "helper" {
+			t.Fatalf("reference named %q, want helper", ref.Name)
+		}
+		if ref.IsDef {
+			defs++
+			loc, ok := ref.NameLocation("fn helper() Int {")
+			if !ok || loc.Start.Col != 4 || loc.End.Col != 10 {
+				t.Fatalf("definition name located at %v (found %v)", loc, ok)
+			}
+		} else {
+			uses++
+		}
+	}
+	if defs != 1 || uses != 2 {
+		t.Fatalf("got %d definitions and %d calls, want 1 and 2: %+v", defs, uses, refs)
+	}
+}
+
+func TestReferencesShareKeysAcrossModules(t *testing.T) {
+	dir := t.TempDir()
+	writeFileT(t, dir, "ard.toml", "name = \"proj\"\nard = \">= 0.1.0\"\n")
+	writeFileT(t, dir, "lib.ard", "fn helper() Int {\n  1\n}\n")
+	source := "use proj/lib\n\nfn main() {\n  let x = lib::helper()\n}\n"
+
+	module, program := referencesModule(t, dir, "main.ard", source)
+	key, ok := module.(*checker.UserModule).SymbolAt(parse.Point{Row: 4, Col: 16})
+	if !ok {
+		t.Fatal("no symbol at lib::helper")
+	}
+	uses := module.References(key)
+	if len(uses) != 1 || uses[0].IsDef || uses[0].FilePath != "main.ard" {
+		t.Fatalf("unexpected uses in main.ard: %+v", uses)
+	}
+
+	lib := program.Imports["proj/lib"]
+	if lib == nil {
+		t.Fatal("lib was not imported")
+	}
+	defs := lib.References(key)
+	if len(defs) != 1 || !defs[0].IsDef {
+		t.Fatalf("expected the definition of helper in lib under key %q, got %+v", key, defs)
+	}
+}
+
+func TestReferencesRequireRecordSpans(t *testing.T) {
+	result := parse.Parse([]byte("fn main() {\n  let x = 1\n}\n"), "test.ard")
+	resolver, err := checker.NewModuleResolver(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checker.New("test.ard", result.Program, resolver)
+	c.Check()
+	if refs := c.Module().References(checker.FunctionKey("test.ard", "main")); refs != nil {
+		t.Fatalf("references recorded without RecordSpans: %+v", refs)
+	}
+}
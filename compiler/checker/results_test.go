@@ -318,6 +318,19 @@ func TestResults(t *testing.T) {
 				{Kind: checker.Error, Message: "type mismatch: expected Int, got Str"},
 			},
 		},
+		{
+			name: "Result::ok wraps a struct that refers to itself",
+			input: `
+				struct Tree {
+					value: Int,
+					children: [Tree],
+				}
+
+				fn leaf(value: Int) Tree!Str {
+					Result::ok(Tree{value: value, children: []})
+				}
+			`,
+		},
 	})
 }
 func TestTry(t *testing.T) {
@@ -347,7 +360,7 @@ func TestTry(t *testing.T) {
 												Module: "ard/result",
 												Call: &checker.FunctionCall{
 													Name: "ok",
-													Args: []checker.Expression{&checker.IntLiteral{2}},
+													Args: []checker.Expression{&checker.IntLiteral{Value: 2}},
 												},
 											},
 										},
@@ -466,6 +479,40 @@ func TestTry(t *testing.T) {
 				{Kind: checker.Error, Message: "Error type mismatch: Expected Int, got Str"},
 			},
 		},
+		{
+			name: "try widens errors into a union error type",
+			input: `
+				struct ParseError { msg: Str }
+				type AppError = ParseError | Int
+
+				fn parse() Int!ParseError { Result::ok(1) }
+				fn read() Int!Int { Result::ok(2) }
+
+				fn named() Int!AppError {
+					let a = try parse()
+					let b = try read()
+					Result::ok(a + b)
+				}
+				fn inline() Int!(ParseError | Str) {
+					let a = try parse()
+					Result::ok(a)
+				}`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "try does not widen errors outside the union error type",
+			input: `
+				type AppError = Int | Bool
+
+				fn test_func() Int!AppError {
+					let res: Int!Str = Result::ok(42)
+					let int = try res
+					Result::ok(int)
+				}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Error type mismatch: Expected AppError, got Str"},
+			},
+		},
 		{
 			name: "try-catch in for loop",
 			input: `
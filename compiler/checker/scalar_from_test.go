@@ -18,6 +18,21 @@ func TestScalarFrom(t *testing.T) {
   Uint32::from(n)
 }`,
 		},
+		{
+			name: "from a sized scalar back into Int and Float64",
+			input: `fn f(n: Int64, x: Float32) Float64 {
+  let whole: Int = Int::from(n)
+  Float64::from(x) + Float64::from(whole)
+}`,
+		},
+		{
+			name: "Int::from rejects a float",
+			input: `let f = 2.5
+let x = Int::from(f)`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Int::from expects a numeric value, got Float64"},
+			},
+		},
 		{
 			name:  "fitting literal adopts the target",
 			input: `let b: Uint8 = Uint8::from(200)`,
@@ -1,6 +1,11 @@
 package checker
 
-import "fmt"
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
 
 type TypedIntLiteral struct {
 	Value int
@@ -29,3 +34,38 @@ func (f *TypedFloatLiteral) String() string {
 	return fmt.Sprintf("%g", f.Value)
 }
 func (f *TypedFloatLiteral) Type() Type { return f.Typed }
+
+// hasRadixPrefix reports whether numeric literal text starts with 0x, 0b, or
+// 0o, after an optional minus sign.
+func hasRadixPrefix(text string) bool {
+	text = strings.TrimPrefix(text, "-")
+	return len(text) > 1 && text[0] == '0' && strings.ContainsRune("xXbBoO", rune(text[1]))
+}
+
+// isFloatLiteralText reports whether numeric literal text is a float, which
+// needs a decimal point or an exponent.
+func isFloatLiteralText(text string) bool {
+	return !hasRadixPrefix(text) && strings.ContainsAny(text, ".eE")
+}
+
+// integerLiteralBase is the base to parse integer literal text in. Without a
+// radix prefix a literal is decimal, so 010 is ten rather than Go's octal.
+func integerLiteralBase(text string) int {
+	if hasRadixPrefix(text) {
+		return 0
+	}
+	return 10
+}
+
+// parseIntLiteral parses integer literal text, ignoring _ separators.
+func parseIntLiteral(text string) (int64, error) {
+	clean := strings.ReplaceAll(text, "_", "")
+	return strconv.ParseInt(clean, integerLiteralBase(clean), 64)
+}
+
+// parseBigIntLiteral parses integer literal text that may not fit an int64,
+// ignoring _ separators.
+func parseBigIntLiteral(text string) (*big.Int, bool) {
+	clean := strings.ReplaceAll(text, "_", "")
+	return new(big.Int).SetString(clean, integerLiteralBase(clean))
+}
@@ -41,6 +41,68 @@ let b: Uint16 = 65535`,
 	})
 }
 
+func TestNumericLiteralForms(t *testing.T) {
+	run(t, []test{
+		{
+			name: "radix literals are integers",
+			input: `let a: Int = 0xFF + 0b1010 + 0o17
+let b: Uint8 = 0xFF
+let c: Int8 = -0x80`,
+		},
+		{
+			name: "exponent literals are floats",
+			input: `let a: Float64 = 1e9
+let b: Float32 = 2.5E-3`,
+		},
+		{
+			name:        "radix literals are range checked",
+			input:       `let a: Uint8 = 0x100`,
+			diagnostics: []checker.Diagnostic{{Kind: checker.Error, Message: "Integer literal 0x100 overflows Uint8"}},
+		},
+		{
+			name:        "malformed radix literal",
+			input:       `let a = 0b102`,
+			diagnostics: []checker.Diagnostic{{Kind: checker.Error, Message: "Invalid int: 0b102"}},
+		},
+		{
+			name: "radix literals as match patterns",
+			input: `fn name(code: Int) Str {
+  match code {
+    0x1F => "unit separator",
+    -0b1 => "minus one",
+    _ => "other",
+  }
+}`,
+		},
+	})
+}
+
+func TestNumericConversions(t *testing.T) {
+	run(t, []test{
+		{
+			name: "explicit Int and Float64 conversions",
+			input: `let n = 3
+let half: Float64 = n.to_float() / 2.0
+let rounded: Int = half.round() + half.floor() + half.ceil() + half.to_int()`,
+		},
+		{
+			name: "parsing numbers returns a Result",
+			input: `let n: Int!Str = Int::parse("42")
+let f: Float64!Str = Float64::parse("1.5e3")`,
+		},
+		{
+			name:        "parse requires a Str",
+			input:       `let n = Int::parse(42)`,
+			diagnostics: []checker.Diagnostic{{Kind: checker.Error, Message: "Type mismatch: Expected Str, got Int"}},
+		},
+		{
+			name:        "Int does not widen to Float64",
+			input:       `let f: Float64 = 1.5 + 2`,
+			diagnostics: []checker.Diagnostic{{Kind: checker.Error, Message: "Cannot add different types"}},
+		},
+	})
+}
+
 func TestExplicitScalarComparisons(t *testing.T) {
 	run(t, []test{
 		{
@@ -45,6 +45,9 @@ type GenericContext = map[string]*TypeVar
 type genericBindingOrigin struct {
 	Span SourceSpan
 	Kind string
+	// Argument is the 1-based source position of the call argument that
+	// established the binding, or 0 for receiver and explicit type arguments.
+	Argument int
 }
 
 type Symbol struct {
@@ -52,6 +55,10 @@ type Symbol struct {
 	Type       Type
 	declaredAt SourceSpan
 	mutable    bool
+	// constant is the folded literal of a `const` declaration.
+	constant Expression
+	// shadows is the enclosing local binding this symbol hides, if any.
+	shadows *Symbol
 }
 
 func (s Symbol) IsZero() bool {
@@ -65,16 +72,34 @@ func makeScope(parent *SymbolTable) SymbolTable {
 	}
 }
 
+// add binds name in this scope. Binding a name this scope already holds
+// rebinds it: the new symbol replaces the old one for the rest of the scope.
+// Binding a name held by an enclosing local scope shadows it until this scope
+// ends, and the new symbol records the binding it hides.
 func (st *SymbolTable) add(name string, type_ Type, mutable bool) *Symbol {
 	sym := Symbol{
 		Name:    name,
 		Type:    type_,
 		mutable: mutable,
 	}
+	if _, rebound := st.symbols[name]; !rebound {
+		sym.shadows = st.enclosingLocal(name)
+	}
 	st.symbols[name] = &sym
 	return &sym
 }
 
+// enclosingLocal finds name in the enclosing scopes below module scope,
+// ignoring isolation since shadowing is about names rather than access.
+func (st *SymbolTable) enclosingLocal(name string) *Symbol {
+	for scope := st.parent; scope != nil && scope.parent != nil; scope = scope.parent {
+		if sym, ok := scope.symbols[name]; ok {
+			return sym
+		}
+	}
+	return nil
+}
+
 func (st SymbolTable) get(name string) (*Symbol, bool) {
 	if sym, ok := st.symbols[name]; ok {
 		return sym, true
@@ -316,6 +341,9 @@ func extractGenericNames(t Type, names map[string]bool) {
 // hasGenericsInType checks if a type contains any generic parameters.
 // Used for quick detection before generic handling.
 func hasGenericsInType(t Type) bool {
+	if isClosedType(t) {
+		return false
+	}
 	return hasGenericsInTypeSeen(t, map[Type]struct{}{})
 }
 
@@ -750,7 +778,7 @@ func replaceGeneric(t Type, genericName string, concreteType Type) Type {
 		if newOf == t.of {
 			return t
 		}
-		return &List{of: newOf}
+		return MakeList(newOf)
 	case *Chan:
 		newOf := replaceGeneric(t.of, genericName, concreteType)
 		if newOf == t.of {
@@ -775,16 +803,13 @@ func replaceGeneric(t Type, genericName string, concreteType Type) Type {
 		if newKey == t.key && newValue == t.value {
 			return t
 		}
-		return &Map{
-			key:   newKey,
-			value: newValue,
-		}
+		return MakeMap(newKey, newValue)
 	case *Maybe:
 		newOf := replaceGeneric(t.of, genericName, concreteType)
 		if newOf == t.of {
 			return t
 		}
-		return &Maybe{of: newOf}
+		return MakeMaybe(newOf)
 	case *Result:
 		newVal := replaceGeneric(t.val, genericName, concreteType)
 		newErr := replaceGeneric(t.err, genericName, concreteType)
@@ -792,10 +817,7 @@ func replaceGeneric(t Type, genericName string, concreteType Type) Type {
 		if newVal == t.val && newErr == t.err {
 			return t
 		}
-		return &Result{
-			val: newVal,
-			err: newErr,
-		}
+		return MakeResult(newVal, newErr)
 	case *MutableRef:
 		newOf := replaceGeneric(t.of, genericName, concreteType)
 		if newOf == t.of {
@@ -823,6 +845,7 @@ func replaceGeneric(t Type, genericName string, concreteType Type) Type {
 			Mutates:                 t.Mutates,
 			Body:                    t.Body,
 			Private:                 t.Private,
+			Deprecated:              t.Deprecated,
 		}
 	case *ForeignType:
 		args := make([]Type, len(t.TypeArgs))
@@ -861,6 +884,7 @@ func replaceGeneric(t Type, genericName string, concreteType Type) Type {
 			Name:             t.Name,
 			ModulePath:       t.ModulePath,
 			Fields:           newFields,
+			FieldOrder:       t.FieldOrder,
 			Self:             t.Self,
 			Traits:           t.Traits,
 			GenericParams:    append([]string(nil), t.GenericParams...),
@@ -868,6 +892,8 @@ func replaceGeneric(t Type, genericName string, concreteType Type) Type {
 			TypeArgs:         newTypeArgs,
 			Definition:       t.Definition,
 			Private:          t.Private,
+			Deprecated:       t.Deprecated,
+			PrivateFields:    t.PrivateFields,
 		}
 	default:
 		return t
@@ -923,6 +949,17 @@ func StructFields(def *StructDef) map[string]Type {
 	return structFields(def)
 }
 
+// StructFieldOrder returns the struct's field names in declaration order.
+func StructFieldOrder(def *StructDef) []string {
+	if definition := canonicalStructDefinition(def); definition != nil && len(definition.FieldOrder) > 0 {
+		return definition.FieldOrder
+	}
+	if def == nil {
+		return nil
+	}
+	return def.FieldOrder
+}
+
 // StructField returns one declaration field specialized for the struct type's
 // ordered arguments.
 func StructField(def *StructDef, name string) (Type, bool) {
@@ -954,6 +991,8 @@ func newStructApplication(def *StructDef, typeArgs []Type) *StructDef {
 		TypeArgs:         append([]Type(nil), typeArgs...),
 		Definition:       definition,
 		Private:          definition.Private,
+		Deprecated:       definition.Deprecated,
+		PrivateFields:    definition.PrivateFields,
 	}
 }
 
@@ -1135,6 +1174,7 @@ func copyFunctionWithTypeVarMap(fnDef *FunctionDef, typeVarMap map[string]*TypeV
 		Body:                    fnDef.Body,
 		Mutates:                 fnDef.Mutates,
 		Private:                 fnDef.Private,
+		Deprecated:              fnDef.Deprecated,
 		GenericBindings:         cloneTypeMap(fnDef.GenericBindings),
 	}
 	if bindings := concreteTypeVarBindings(typeVarMap); bindings != nil {
@@ -1168,12 +1208,15 @@ func copyStructWithTypeVarMapSeen(structDef *StructDef, typeVarMap map[string]*T
 		Name:             structDef.Name,
 		ModulePath:       structDef.ModulePath,
 		Fields:           newFields,
+		FieldOrder:       structDef.FieldOrder,
 		Self:             structDef.Self,
 		Traits:           structDef.Traits,
 		GenericParams:    append([]string(nil), structDef.GenericParams...),
 		DeclaredGenerics: structDef.DeclaredGenerics,
 		Definition:       structDef.Definition,
 		Private:          structDef.Private,
+		Deprecated:       structDef.Deprecated,
+		PrivateFields:    structDef.PrivateFields,
 	}
 	seen[structDef] = structCopy
 	for name, fieldType := range structDef.Fields {
@@ -1275,7 +1318,7 @@ func copyTypeWithTypeVarMapSeen(t Type, typeVarMap map[string]*TypeVar, seenStru
 		}
 		return typ // Keep as-is if not a generic parameter
 	case *List:
-		return &List{of: copyTypeWithTypeVarMapSeen(typ.of, typeVarMap, seenStructs)}
+		return MakeList(copyTypeWithTypeVarMapSeen(typ.of, typeVarMap, seenStructs))
 	case *FixedArray:
 		return MakeFixedArray(copyTypeWithTypeVarMapSeen(typ.of, typeVarMap, seenStructs), typ.length)
 	case *Chan:
@@ -1285,17 +1328,17 @@ func copyTypeWithTypeVarMapSeen(t Type, typeVarMap map[string]*TypeVar, seenStru
 	case *Sender:
 		return &Sender{of: copyTypeWithTypeVarMapSeen(typ.of, typeVarMap, seenStructs)}
 	case *Map:
-		return &Map{
-			key:   copyTypeWithTypeVarMapSeen(typ.key, typeVarMap, seenStructs),
-			value: copyTypeWithTypeVarMapSeen(typ.value, typeVarMap, seenStructs),
-		}
+		return MakeMap(
+			copyTypeWithTypeVarMapSeen(typ.key, typeVarMap, seenStructs),
+			copyTypeWithTypeVarMapSeen(typ.value, typeVarMap, seenStructs),
+		)
 	case *Maybe:
-		return &Maybe{of: copyTypeWithTypeVarMapSeen(typ.of, typeVarMap, seenStructs)}
+		return MakeMaybe(copyTypeWithTypeVarMapSeen(typ.of, typeVarMap, seenStructs))
 	case *Result:
-		return &Result{
-			val: copyTypeWithTypeVarMapSeen(typ.val, typeVarMap, seenStructs),
-			err: copyTypeWithTypeVarMapSeen(typ.err, typeVarMap, seenStructs),
-		}
+		return MakeResult(
+			copyTypeWithTypeVarMapSeen(typ.val, typeVarMap, seenStructs),
+			copyTypeWithTypeVarMapSeen(typ.err, typeVarMap, seenStructs),
+		)
 	case *MutableRef:
 		return MakeMutableRef(copyTypeWithTypeVarMapSeen(typ.of, typeVarMap, seenStructs))
 	case *Union:
@@ -3,6 +3,7 @@ package checker_test
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/akonwi/ard/checker"
@@ -330,3 +331,38 @@ func TestSpansDedupExpectedTypeContexts(t *testing.T) {
 		t.Fatalf("expected exactly 2 uses of n, got %d (double recording?)", refs)
 	}
 }
+
+func TestLineTypesListsBindingsOrOutermostExpressions(t *testing.T) {
+	spans := checkWithSpans(t, `fn add(a: Int, b: Int) Int {
+  a + b
+}
+
+fn main() {
+  let found = [1, 2].at(0)
+  match found {
+    n => add(n, 1),
+    _ => 0,
+  }
+}
+`)
+	got := spans.LineTypes()
+	want := map[int]string{
+		1: "add: fn(Int, Int) Int, a: Int, b: Int",
+		2: "Int",
+		5: "main: fn()",
+		6: "found: Int?",
+		7: "Int",
+		8: "Int",
+		9: "Int",
+	}
+	for row, types := range want {
+		if line := strings.Join(got[row], ", "); line != types {
+			t.Fatalf("line %d types = %q, want %q", row, line, types)
+		}
+	}
+	for _, row := range []int{3, 4, 10, 11} {
+		if len(got[row]) != 0 {
+			t.Fatalf("line %d should have no types, got %v", row, got[row])
+		}
+	}
+}
@@ -15,8 +15,6 @@ func findInStdLib(path string) (Module, bool) {
 	switch path {
 	case "ard/result":
 		return ResultPkg{}, true
-	case "ard/async":
-		return AsyncPkg{}, true
 	case "ard/unsafe":
 		return UnsafePkg{}, true
 	}
@@ -38,7 +36,7 @@ func (pkg MaybePkg) Get(name string) Symbol {
 	switch name {
 	case "new":
 		typeVar := &TypeVar{name: "T"}
-		maybeType := &Maybe{typeVar}
+		maybeType := &Maybe{of: typeVar}
 		return Symbol{
 			Name: name,
 			Type: &FunctionDef{
@@ -67,6 +65,18 @@ func (pkg ErrorPkg) Get(name string) Symbol {
 			Parameters: []Parameter{{Name: "message", Type: Str}},
 			ReturnType: BuiltinError,
 		}}
+	case "wrap":
+		return Symbol{Name: name, Type: &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{{Name: "err", Type: BuiltinError}, {Name: "context", Type: Str}},
+			ReturnType: BuiltinError,
+		}}
+	case "cause":
+		return Symbol{Name: name, Type: &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{{Name: "err", Type: BuiltinError}},
+			ReturnType: MakeMaybe(BuiltinError),
+		}}
 	default:
 		return Symbol{}
 	}
@@ -119,22 +129,6 @@ func (pkg ResultPkg) Get(name string) Symbol {
 	}
 }
 
-/* ard/async */
-type AsyncPkg struct{}
-
-func (pkg AsyncPkg) Path() string { return "ard/async" }
-
-func (pkg AsyncPkg) Program() *Program { return nil }
-
-func (pkg AsyncPkg) Get(name string) Symbol {
-	switch name {
-	case "start":
-		return Symbol{Name: name, Type: &FunctionDef{Name: name, Parameters: []Parameter{{Name: "task", Type: &FunctionDef{Name: "<function>", ReturnType: Void}}}, ReturnType: Void}}
-	default:
-		return Symbol{}
-	}
-}
-
 /* ard/unsafe */
 type UnsafePkg struct{}
 
@@ -189,9 +183,8 @@ func (pkg ChannelStaticPkg) Get(name string) Symbol {
 // Symbols stay in sync.
 var BuiltinPkgNames = map[string][]string{
 	"builtin/Maybe": {"new"},
-	"builtin/Error": {"new"},
+	"builtin/Error": {"new", "wrap", "cause"},
 	"ard/result":    {"ok", "err"},
-	"ard/async":     {"start"},
 	"ard/unsafe":    {"cast", "is_nil"},
 	"builtin/Chan":  {"new"},
 }
@@ -208,10 +201,6 @@ func (pkg ResultPkg) Symbols() map[string]Symbol {
 	return symbolsByName(pkg, BuiltinPkgNames[pkg.Path()]...)
 }
 
-func (pkg AsyncPkg) Symbols() map[string]Symbol {
-	return symbolsByName(pkg, BuiltinPkgNames[pkg.Path()]...)
-}
-
 func (pkg UnsafePkg) Symbols() map[string]Symbol {
 	return symbolsByName(pkg, BuiltinPkgNames[pkg.Path()]...)
 }
@@ -222,6 +211,14 @@ func (pkg ChannelStaticPkg) Symbols() map[string]Symbol {
 	return symbolsByName(pkg, BuiltinPkgNames[pkg.Path()]...)
 }
 
+// Builtin packages have no source, so nothing in them references anything.
+func (pkg MaybePkg) References(string) []Reference         { return nil }
+func (pkg ErrorPkg) References(string) []Reference         { return nil }
+func (pkg ResultPkg) References(string) []Reference        { return nil }
+func (pkg UnsafePkg) References(string) []Reference        { return nil }
+func (pkg EmptyBuiltinPkg) References(string) []Reference  { return nil }
+func (pkg ChannelStaticPkg) References(string) []Reference { return nil }
+
 func symbolsByName(mod Module, names ...string) map[string]Symbol {
 	out := make(map[string]Symbol, len(names))
 	for _, name := range names {
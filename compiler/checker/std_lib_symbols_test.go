@@ -6,7 +6,7 @@ import "testing"
 // resolves through the package's Get, and every Symbols entry is non-zero —
 // guarding drift between the Get switches and the shared name lists.
 func TestBuiltinPkgSymbolsMatchGet(t *testing.T) {
-	pkgs := []Module{MaybePkg{}, ResultPkg{}, AsyncPkg{}, UnsafePkg{}, ChannelStaticPkg{}}
+	pkgs := []Module{MaybePkg{}, ResultPkg{}, UnsafePkg{}, ChannelStaticPkg{}}
 	for _, pkg := range pkgs {
 		names, ok := BuiltinPkgNames[pkg.Path()]
 		if !ok {
@@ -0,0 +1,130 @@
+package checker
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// strictUsage tracks which local bindings and private functions are read so
+// that `ard check --strict` can report code nothing uses. It is nil unless
+// CheckOptions.Strict is set.
+type strictUsage struct {
+	used   map[*Symbol]bool
+	locals []strictBinding
+	// shadows are the local bindings that hide one from an enclosing block,
+	// keyed by where they are declared.
+	shadows map[SourceSpan]*Symbol
+}
+
+type strictBinding struct {
+	sym  *Symbol
+	span SourceSpan
+}
+
+// markUsed records a read of sym.
+func (c *Checker) markUsed(sym *Symbol) {
+	if c.strict == nil || sym == nil {
+		return
+	}
+	c.strict.used[sym] = true
+}
+
+// trackLocal registers a local `let` or `mut` binding to be reported if it is
+// never read. Names starting with `_` opt out.
+func (c *Checker) trackLocal(sym *Symbol, loc parse.Location) {
+	if c.strict == nil || sym == nil || c.scope.parent == nil || strings.HasPrefix(sym.Name, "_") {
+		return
+	}
+	c.strict.locals = append(c.strict.locals, strictBinding{sym: sym, span: c.sourceSpan(loc)})
+}
+
+// trackShadowing registers a local `let` or `mut` binding that hides a binding
+// of an enclosing block. Names starting with `_` opt out.
+func (c *Checker) trackShadowing(sym *Symbol, loc parse.Location) {
+	if c.strict == nil || sym == nil || sym.shadows == nil || strings.HasPrefix(sym.Name, "_") {
+		return
+	}
+	if c.strict.shadows == nil {
+		c.strict.shadows = map[SourceSpan]*Symbol{}
+	}
+	c.strict.shadows[c.sourceSpan(loc)] = sym
+}
+
+// reportUnreachable warns about the first statement of a block that follows a
+// `break` or a `panic` call.
+func (c *Checker) reportUnreachable(stmts []parse.Statement) {
+	if c.strict == nil {
+		return
+	}
+	exit, exitLoc := "", parse.Location{}
+	for _, stmt := range stmts {
+		if stmt == nil {
+			continue
+		}
+		if _, ok := stmt.(*parse.Comment); ok {
+			continue
+		}
+		if exit != "" {
+			c.warnings = append(c.warnings, unreachableCodeDiagnostic{
+				Exit:     exit,
+				Span:     c.sourceSpan(stmt.GetLocation()),
+				ExitSpan: c.sourceSpan(exitLoc),
+			}.build())
+			return
+		}
+		switch s := stmt.(type) {
+		case *parse.Break:
+			exit, exitLoc = "break", s.GetLocation()
+		case *parse.FunctionCall:
+			if s.Name == "panic" {
+				exit, exitLoc = "panic", s.GetLocation()
+			}
+		}
+	}
+}
+
+// reportUnusedCode gathers the strict-mode warnings once the module is
+// checked: unused imports, never-read locals, uncalled private functions, and
+// locals that shadow an enclosing binding. All warnings are then ordered by
+// source position.
+func (c *Checker) reportUnusedCode() {
+	if c.strict == nil {
+		return
+	}
+	used := parse.ImportUses(c.input)
+	for _, imp := range c.input.Imports {
+		// `#deny(warnings)` already reports unused imports.
+		if c.denyWarnings != nil || imp.ReExport != "" || used[imp.Name] {
+			continue
+		}
+		c.warnings = append(c.warnings, unusedImportDiagnostic{Name: imp.Name, Span: c.sourceSpan(imp.Location)}.build())
+	}
+	for _, local := range c.strict.locals {
+		if !c.strict.used[local.sym] {
+			c.warnings = append(c.warnings, unusedVariableDiagnostic{Name: local.sym.Name, Span: local.span}.build())
+		}
+	}
+	for span, sym := range c.strict.shadows {
+		c.warnings = append(c.warnings, shadowedVariableDiagnostic{
+			Name:         sym.Name,
+			Span:         span,
+			ShadowedSpan: sourceSpanIfPresent(sym.shadows.declaredAt),
+		}.build())
+	}
+	for _, stmt := range c.input.Statements {
+		fn, ok := stmt.(*parse.FunctionDeclaration)
+		if !ok || !fn.Private || fn.IsTest {
+			continue
+		}
+		if sym, ok := c.scope.symbols[fn.Name]; ok && !c.strict.used[sym] {
+			c.warnings = append(c.warnings, unusedFunctionDiagnostic{Name: fn.Name, Span: c.sourceSpan(fn.GetLocation())}.build())
+		}
+	}
+	slices.SortStableFunc(c.warnings, func(a, b Diagnostic) int {
+		start, other := a.Location().Start, b.Location().Start
+		return cmp.Or(cmp.Compare(start.Row, other.Row), cmp.Compare(start.Col, other.Col))
+	})
+}
@@ -0,0 +1,84 @@
+package checker_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+func strictWarnings(t *testing.T, input string, strict bool) []string {
+	t.Helper()
+	const filePath = "main.ard"
+	result := parse.Parse([]byte(input), filePath)
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	c := checker.New(filePath, result.Program, nil, checker.CheckOptions{Strict: strict})
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("unexpected diagnostics: %v", c.Diagnostics())
+	}
+	messages := []string{}
+	for _, warning := range c.Warnings() {
+		if warning.Kind != checker.Warn {
+			t.Fatalf("%q is reported as %q, want a warning", warning.Message, warning.Kind)
+		}
+		messages = append(messages, warning.Message)
+	}
+	return messages
+}
+
+func TestStrictModeReportsUnusedCode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "unused imports",
+			input: "use ard/list\nuse ard/map as dict\n\nfn main() {\n  let size = list::new<Int>().size()\n  size\n}",
+			want:  []string{"Unused import: dict"},
+		},
+		{
+			name:  "locals that are never read",
+			input: "fn main() {\n  let used = 1\n  let unused = 2\n  let _ignored = 3\n  mut written = 0\n  written = used\n}",
+			want:  []string{"Unused variable: unused", "Unused variable: written"},
+		},
+		{
+			name:  "reads through closures, interpolation, and mutable references count",
+			input: "fn bump(n: mut Int) { n = n + 1 }\n\nfn main() Str {\n  let items = [1, 2]\n  let size = fn() Int { items.size() }\n  mut count = 0\n  bump(mut count)\n  \"{size()}\"\n}",
+			want:  []string{},
+		},
+		{
+			name:  "private functions that are never called",
+			input: "private fn helper() Int { 1 }\nprivate fn double(n: Int) Int { n * 2 }\nprivate fn unused() Int { 2 }\n\nfn main() Int {\n  helper() |> double\n}",
+			want:  []string{"Unused private function: unused"},
+		},
+		{
+			name:  "locals that shadow a binding of an enclosing block",
+			input: "fn main(count: Int) Int {\n  let total = count\n  let total = total + 1\n  if total > 1 {\n    let total = 2\n    mut count = total\n    count = count + 1\n    count\n  } else {\n    total\n  }\n}",
+			want:  []string{"Shadowed variable: total", "Shadowed variable: count"},
+		},
+		{
+			name:  "statements after break and panic",
+			input: "fn main() {\n  while true {\n    break\n    // comments are not code\n    let x = 1\n    x\n  }\n  panic(\"boom\")\n  main()\n}",
+			want:  []string{"Unreachable code after break", "Unreachable code after panic"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strictWarnings(t, tt.input, true); !slices.Equal(got, tt.want) {
+				t.Fatalf("warnings = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnusedCodeIsOnlyReportedInStrictMode(t *testing.T) {
+	input := "use ard/list\n\nprivate fn unused() {}\n\nfn main() {\n  let x = 1\n  if true {\n    let x = 2\n  }\n  panic(\"boom\")\n  main()\n}"
+	if got := strictWarnings(t, input, false); len(got) != 0 {
+		t.Fatalf("warnings = %q, want none", got)
+	}
+}
@@ -0,0 +1,197 @@
+package checker
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// structPatternArm is what one struct pattern tests and binds: every
+// condition must hold for the arm to match, and each binding names a field of
+// the matched value inside the arm's body.
+type structPatternArm struct {
+	conditions []Expression
+	bindings   []structPatternBinding
+}
+
+type structPatternBinding struct {
+	name     string
+	location parse.Location
+	value    Expression
+}
+
+// checkStructMatch checks a match over a struct value whose arms are struct
+// patterns like `Point{x: 0, y}`. The subject is stored in a hidden local and
+// the match becomes a conditional match over it: each arm compares its
+// constant fields with == and binds the named ones before running its body.
+// Struct fields range over too many values to enumerate, so the match needs an
+// arm that matches everything, either `_` or a pattern with nothing to test.
+func (c *Checker) checkStructMatch(s *parse.MatchExpression, subject Expression, def *StructDef, allowMixedVoid bool) Expression {
+	start := s.GetLocation().Start
+	name := fmt.Sprintf("match$subject%d_%d", start.Row, start.Col)
+	subjectVar := &Variable{sym: Symbol{Name: name, Type: subject.Type()}}
+
+	var cases []ConditionalCase
+	var catchAll *Block
+	var catchAllSpan *SourceSpan
+	var resultType Type
+	for _, matchCase := range s.Cases {
+		arm := structPatternArm{}
+		switch p := matchCase.Pattern.(type) {
+		case *parse.Identifier:
+			if p.Name != "_" {
+				legacy := fmt.Sprintf("Pattern in %s match must be a struct pattern or '_'", def.Name)
+				c.addInvalidMatchPattern(legacy, p.GetLocation(), fmt.Sprintf("expected `%s{...}` or `_`", def.Name))
+				continue
+			}
+		case *parse.StructPattern:
+			if !c.checkStructPattern(p, subjectVar, def, &arm) {
+				continue
+			}
+		default:
+			legacy := fmt.Sprintf("Pattern in %s match must be a struct pattern or '_'", def.Name)
+			c.addInvalidMatchPattern(legacy, matchCase.Pattern.GetLocation(), fmt.Sprintf("expected `%s{...}` or `_`", def.Name))
+			continue
+		}
+
+		span := c.sourceSpan(matchCase.Pattern.GetLocation())
+		if catchAll != nil {
+			c.addUnreachableMatchArm("Unreachable case: an earlier arm matches every value", matchCase.Pattern.GetLocation(), catchAllSpan, "this arm can never match")
+			continue
+		}
+
+		body := c.checkMatchArmBlock(matchCase.Body, func() {
+			for _, binding := range arm.bindings {
+				c.recordBinding(binding.location, c.scope.add(binding.name, binding.value.Type(), false))
+			}
+		})
+		bindings := make([]Statement, 0, len(arm.bindings)+len(body.Stmts))
+		for _, binding := range arm.bindings {
+			bindings = append(bindings, Statement{Stmt: &VariableDef{Name: binding.name, __type: binding.value.Type(), Value: binding.value}})
+		}
+		body.Stmts = append(bindings, body.Stmts...)
+
+		var ok bool
+		resultType, ok = mergeMatchResultType(c, resultType, body.Type(), matchCase.Pattern.GetLocation(), allowMixedVoid)
+		if !ok {
+			return nil
+		}
+
+		if len(arm.conditions) == 0 {
+			catchAll = body
+			catchAllSpan = &span
+			continue
+		}
+		condition := arm.conditions[0]
+		for _, next := range arm.conditions[1:] {
+			condition = &And{Left: condition, Right: next}
+		}
+		cases = append(cases, ConditionalCase{Condition: condition, Body: body})
+	}
+
+	if catchAll == nil {
+		legacy := fmt.Sprintf("Incomplete match: missing catch-all case for %s match", def.Name)
+		c.addNonExhaustiveMatch(legacy, s.GetLocation(), "add a catch-all `_` case", "_")
+	}
+
+	return &Block{Stmts: []Statement{
+		{Stmt: &VariableDef{Name: name, __type: subject.Type(), Value: subject}},
+		{Expr: &ConditionalMatch{Cases: cases, CatchAll: catchAll, ResultType: resultType}},
+	}}
+}
+
+// checkStructPattern collects the tests and bindings of pattern against value,
+// a struct of type def, into arm. It reports false when the pattern is
+// invalid.
+func (c *Checker) checkStructPattern(pattern *parse.StructPattern, value Expression, def *StructDef, arm *structPatternArm) bool {
+	patternName := pattern.Name.Name
+	if i := strings.LastIndex(patternName, "::"); i >= 0 {
+		patternName = patternName[i+2:]
+	}
+	if patternName != def.Name {
+		legacy := fmt.Sprintf("Pattern %s does not match %s", pattern.Name.Name, def.Name)
+		c.addInvalidMatchPattern(legacy, pattern.Name.GetLocation(), fmt.Sprintf("expected a `%s` pattern", def.Name))
+		return false
+	}
+
+	valid := true
+	seen := map[string]bool{}
+	for _, field := range pattern.Fields {
+		fieldName := field.Name.Name
+		fieldType, ok := structField(def, fieldName)
+		if !ok {
+			c.addDiagnostic(undefinedMemberDiagnostic{
+				Kind:       undefinedField,
+				Receiver:   def.Name,
+				Member:     fieldName,
+				Span:       c.sourceSpan(field.Name.GetLocation()),
+				Suggestion: suggestName(fieldName, fieldNames(def)),
+			}.build())
+			valid = false
+			continue
+		}
+		if seen[fieldName] {
+			c.addInvalidMatchPattern(fmt.Sprintf("Duplicate field in pattern: %s", fieldName), field.Name.GetLocation(), "this field is already matched")
+			valid = false
+			continue
+		}
+		seen[fieldName] = true
+		if !c.checkFieldVisible(def, fieldName, field.Name.GetLocation()) {
+			valid = false
+			continue
+		}
+
+		access := &InstanceProperty{Subject: value, Property: fieldName, _type: fieldType, Kind: StructSubject}
+		switch p := field.Pattern.(type) {
+		case nil:
+			arm.bindings = append(arm.bindings, structPatternBinding{name: fieldName, location: field.Name.GetLocation(), value: access})
+		case *parse.StructPattern:
+			nested, ok := access.Type().(*StructDef)
+			if !ok {
+				legacy := fmt.Sprintf("Field %s is %s, not a struct", fieldName, access.Type())
+				c.addInvalidMatchPattern(legacy, p.GetLocation(), "a struct pattern needs a struct field")
+				valid = false
+				continue
+			}
+			valid = c.checkStructPattern(p, access, nested, arm) && valid
+		case *parse.Identifier:
+			if p.Name == "_" {
+				continue
+			}
+			// A constant compares against the field; any other name binds it.
+			if sym, ok := c.scope.get(p.Name); ok && sym.constant != nil {
+				valid = c.addStructFieldTest(field, access, arm) && valid
+				continue
+			}
+			arm.bindings = append(arm.bindings, structPatternBinding{name: p.Name, location: p.GetLocation(), value: access})
+		default:
+			valid = c.addStructFieldTest(field, access, arm) && valid
+		}
+	}
+	return valid
+}
+
+// addStructFieldTest adds a check that the field equals the value in its
+// pattern, which must be a constant or an enum variant.
+func (c *Checker) addStructFieldTest(field parse.StructPatternField, access *InstanceProperty, arm *structPatternArm) bool {
+	if !isComparableValueType(access.Type()) {
+		legacy := fmt.Sprintf("Field %s of type %s cannot be compared in a pattern", field.Name.Name, access.Type())
+		c.addInvalidMatchPattern(legacy, field.Pattern.GetLocation(), "bind this field to a name instead")
+		return false
+	}
+	expected := c.checkExprAs(field.Pattern, access.Type())
+	if expected == nil {
+		return false
+	}
+	if _, isVariant := expected.(*EnumVariant); !isVariant {
+		constant, err := evalConstant(expected)
+		if err != nil {
+			c.addInvalidMatchPattern("Field patterns must be constants", field.Pattern.GetLocation(), "expected a literal, constant, or enum variant")
+			return false
+		}
+		expected = constant
+	}
+	arm.conditions = append(arm.conditions, &Equality{Left: access, Right: expected})
+	return true
+}
@@ -0,0 +1,115 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+const structMatchPrelude = `struct Point { x: Int, y: Int }
+
+enum Kind { circle, square }
+
+struct Shape { kind: Kind, origin: Point, label: Str }
+
+const ZERO = 0
+`
+
+func TestStructPatterns(t *testing.T) {
+	run(t, []test{
+		{
+			name: "struct patterns test constant fields and bind the rest",
+			input: structMatchPrelude + `
+fn describe(p: Point) Str {
+  match p {
+    Point{x: ZERO, y: 0} => "origin",
+    Point{x: 0, y} => "on the y axis at {y}",
+    Point{x: px, y: _} => "x is {px}",
+  }
+}
+
+fn shape(s: Shape) Str {
+  match s {
+    Shape{kind: Kind::circle, origin: Point{x: 0, y: 0}} => "circle at the origin",
+    Shape{label: "big", origin} => "big at {origin.x}",
+    _ => "something else",
+  }
+}`,
+		},
+		{
+			name: "a struct match needs an arm that matches every value",
+			input: structMatchPrelude + `
+fn describe(p: Point) Str {
+  match p {
+    Point{x: 0, y} => "on the y axis at {y}",
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Incomplete match: missing catch-all case for Point match"},
+			},
+		},
+		{
+			name: "arms after a catch-all can never match",
+			input: structMatchPrelude + `
+fn describe(p: Point) Str {
+  match p {
+    Point{x, y} => "at {x},{y}",
+    Point{x: 0, y: 0} => "origin",
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Warn, Message: "Unreachable case: an earlier arm matches every value"},
+			},
+		},
+		{
+			name: "bindings are only in scope in their arm",
+			input: structMatchPrelude + `
+fn describe(p: Point) {
+  let found = match p {
+    Point{x: 0, y} => y,
+    _ => 0,
+  }
+  y
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Undefined variable: y"},
+			},
+		},
+		{
+			name: "field patterns are checked against the struct",
+			input: structMatchPrelude + `
+fn describe(p: Point) Str {
+  match p {
+    Shape{label} => label,
+    Point{z: 0} => "z",
+    Point{x: 0, x: 1} => "twice",
+    Point{x: "zero"} => "zero",
+    _ => "other",
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Pattern Shape does not match Point"},
+				{Kind: checker.Error, Message: "Undefined: Point.z"},
+				{Kind: checker.Error, Message: "Duplicate field in pattern: x"},
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Str"},
+			},
+		},
+		{
+			name: "field patterns must be constants",
+			input: structMatchPrelude + `
+fn describe(p: Point, limit: Int) Str {
+  match p {
+    Point{x: limit + 1} => "at the limit",
+    Point{origin: 1} => "origin",
+    1 => "one",
+    _ => "other",
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Field patterns must be constants"},
+				{Kind: checker.Error, Message: "Undefined: Point.origin"},
+				{Kind: checker.Error, Message: "Pattern in Point match must be a struct pattern or '_'"},
+			},
+		},
+	})
+}
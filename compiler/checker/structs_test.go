@@ -27,6 +27,39 @@ fn main() {}
 	}})
 }
 
+func TestRecursiveGenericStructLiterals(t *testing.T) {
+	run(t, []test{{
+		name: "nested generic literal infers its empty list field",
+		input: `
+struct Tree<$T> {
+  value: $T,
+  kids: [Tree<$T>],
+}
+
+fn main() {
+  let tree = Tree{value: 1, kids: [Tree{value: 2, kids: []}]}
+}
+`,
+	}, {
+		name: "mutually recursive generic structs",
+		input: `
+struct Even<$T> {
+  value: $T,
+  next: Odd<$T>?,
+}
+
+struct Odd<$T> {
+  value: $T,
+  next: Even<$T>?,
+}
+
+fn main() {
+  let chain = Even{value: "a", next: Odd{value: "b", next: Even{value: "c"}}}
+}
+`,
+	}})
+}
+
 func TestStructs(t *testing.T) {
 	personStructInput := strings.Join([]string{
 		"struct Person {",
@@ -70,6 +103,7 @@ func TestStructs(t *testing.T) {
 								"age":      checker.Int,
 								"employed": checker.Bool,
 							},
+							FieldOrder: []string{"name", "age", "employed"},
 						},
 					},
 					{
@@ -79,7 +113,7 @@ func TestStructs(t *testing.T) {
 								Name: "Person",
 								Fields: map[string]checker.Expression{
 									"name":     &checker.StrLiteral{"Alice"},
-									"age":      &checker.IntLiteral{30},
+									"age":      &checker.IntLiteral{Value: 30},
 									"employed": &checker.BoolLiteral{true},
 								},
 								FieldTypes: map[string]checker.Type{
@@ -264,6 +298,113 @@ func TestStructsWithMaybeFields(t *testing.T) {
 		},
 	})
 }
+func TestStructFieldDefaults(t *testing.T) {
+	run(t, []test{
+		{
+			name: "Fields with defaults can be omitted or overridden",
+			input: `const BASE = 10
+			enum Level { Debug, Info }
+			struct Config {
+				host: Str,
+				retries: Int = 3,
+				timeout: Int = BASE * 2,
+				level: Level = Level::Info,
+			}
+			let defaults = Config{host: "localhost"}
+			let custom = Config{host: "localhost", retries: 5}
+			`,
+		},
+		{
+			name: "Instances before the declaration see its defaults",
+			input: `fn make() Config {
+				Config{host: "localhost"}
+			}
+			struct Config {
+				host: Str,
+				retries: Int = 3,
+			}
+			`,
+		},
+		{
+			name: "Defaults must be constant",
+			input: `fn three() Int { 3 }
+			struct Config {
+				retries: Int = three(),
+			}
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Default for field retries is not computable at compile time: only literals, constants, arithmetic, and string concatenation are allowed"},
+			},
+		},
+		{
+			name: "Defaults match the field type",
+			input: `struct Config {
+				retries: Int = "3",
+			}
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Str"},
+			},
+		},
+		{
+			name: "Only scalar and enum fields take defaults",
+			input: `struct Config {
+				hosts: [Str] = [],
+			}
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Default for field hosts must be Int, Float64, Str, Bool, or an enum, not [Str]"},
+			},
+		},
+		{
+			name: "Fields without defaults are still required",
+			input: `struct Config {
+				host: Str,
+				retries: Int = 3,
+			}
+			let config = Config{retries: 1}
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Missing field: host"},
+			},
+		},
+	})
+}
+
+func TestStructUpdates(t *testing.T) {
+	config := `struct Config {
+				host: Str,
+				retries: Int,
+			}
+			`
+	run(t, []test{
+		{
+			name: "Remaining fields come from the base",
+			input: config + `let base = Config{host: "localhost", retries: 1}
+			let custom = Config{..base, retries: 5}
+			let copy = Config{..base}
+			`,
+		},
+		{
+			name: "The base must be the same struct",
+			input: config + `struct Other { host: Str }
+			let other = Other{host: "localhost"}
+			let custom = Config{..other, retries: 5}
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Config, got Other"},
+			},
+		},
+		{
+			name: "Generic bases bind the type arguments",
+			input: `struct Box<$T> { item: $T, count: Int }
+			let base = Box{item: "a", count: 1}
+			let next: Box<Str> = Box{..base, count: 2}
+			`,
+		},
+	})
+}
+
 func TestStructsWithStaticFunctions(t *testing.T) {
 	run(t, []test{
 		{
@@ -279,6 +420,75 @@ func TestStructsWithStaticFunctions(t *testing.T) {
 			`,
 			diagnostics: []checker.Diagnostic{},
 		},
+		{
+			name:  "Static functions must be declared on a type",
+			input: `fn Nope::origin() Int { 1 }`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Undefined type: Nope"},
+			},
+		},
+		{
+			name: "Self refers to the type in static functions and impl blocks",
+			input: `struct Point {
+				x: Int,
+				y: Int,
+			}
+			fn Point::origin() Self {
+				Self{x: 0, y: 0}
+			}
+			impl Point {
+				fn moved(dx: Int) Self {
+					Self{x: self.x + dx, y: self.y}
+				}
+				fn same(other: Self) Bool {
+					self.x == other.x and self.y == other.y
+				}
+			}
+			let p: Point = Point::origin().moved(1)
+			p.same(Point::origin())
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "Self applies a generic struct to its own parameters",
+			input: `struct Box<$T> {
+				item: $T,
+			}
+			impl Box {
+				fn copy() Self {
+					Self{item: self.item}
+				}
+			}
+			let b = Box{item: "x"}
+			let copied: Box<Str> = b.copy()
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "Self is checked against the type",
+			input: `struct Point {
+				x: Int,
+			}
+			impl Point {
+				fn count() Self {
+					1
+				}
+			}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Point, got Int"},
+			},
+		},
+		{
+			name: "Self is only available within a type",
+			input: `fn make() Self { 1 }
+			trait Make {
+				fn make() Self
+			}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Self can only be used inside an impl block or a static function"},
+				{Kind: checker.Error, Message: "Self can only be used inside an impl block or a static function"},
+			},
+		},
 	})
 }
 
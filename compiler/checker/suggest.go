@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"maps"
+	"slices"
+	"strings"
+)
+
+// suggestName returns the candidate closest to name by edit distance, for a
+// "did you mean" hint on an unknown identifier or field. Candidates further
+// than a third of the name's length away are not similar enough to suggest,
+// nor is anything for a one-letter name, and ties go to the alphabetically
+// first candidate so hints are stable.
+func suggestName(name string, candidates []string) string {
+	limit := min(max(1, len(name)/3), len(name)-1)
+	best, bestDistance := "", limit+1
+	for _, candidate := range candidates {
+		if candidate == name || candidate == "_" {
+			continue
+		}
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b, counting an
+// adjacent transposition as a single edit so `lenght` is one away from
+// `length`.
+func editDistance(a string, b string) int {
+	x, y := []rune(a), []rune(b)
+	rows := make([][]int, len(x)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(y)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(x); i++ {
+		for j := 1; j <= len(y); j++ {
+			cost := 1
+			if x[i-1] == y[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && x[i-1] == y[j-2] && x[i-2] == y[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(x)][len(y)]
+}
+
+// visibleNames lists the names in scope, including those of enclosing scopes.
+func (st SymbolTable) visibleNames() []string {
+	names := slices.Collect(maps.Keys(st.symbols))
+	if st.parent != nil {
+		names = append(names, st.parent.visibleNames()...)
+	}
+	return names
+}
+
+// fieldNames lists the fields of a struct type, or nil for other types.
+func fieldNames(t Type) []string {
+	def, ok := t.(*StructDef)
+	if !ok {
+		return nil
+	}
+	return slices.Collect(maps.Keys(structFields(def)))
+}
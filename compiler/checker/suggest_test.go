@@ -0,0 +1,25 @@
+package checker
+
+import "testing"
+
+func TestSuggestName(t *testing.T) {
+	tests := []struct {
+		name       string
+		candidates []string
+		want       string
+	}{
+		{name: "lenght", candidates: []string{"list", "length", "height"}, want: "length"},
+		{name: "nme", candidates: []string{"age", "name"}, want: "name"},
+		{name: "Count", candidates: []string{"count"}, want: "count"},
+		{name: "x", candidates: []string{"y"}, want: ""},
+		{name: "total", candidates: []string{"items", "size"}, want: ""},
+		{name: "cat", candidates: []string{"car", "bat"}, want: "bat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := suggestName(tt.name, tt.candidates); got != tt.want {
+				t.Fatalf("suggestName(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
@@ -1,17 +1,21 @@
 package checker
 
 import (
+	"slices"
 	"sort"
 
 	"github.com/akonwi/ard/parse"
 )
 
+// hoistTopLevelTypeDeclarations adds every top-level type to module scope
+// ahead of checking. It also reports top-level names declared more than once,
+// functions included, and marks each repeat so later passes skip it.
 func (c *Checker) hoistTopLevelTypeDeclarations() {
 	seen := map[string]parse.Location{}
 	for i := range c.input.Statements {
 		stmt := c.input.Statements[i]
-		name, loc, ok := topLevelTypeDeclarationName(stmt)
-		if !ok {
+		name, loc, ok := topLevelDeclarationName(stmt)
+		if _, isVariable := stmt.(*parse.VariableDeclaration); !ok || isVariable {
 			continue
 		}
 		if original, dup := seen[name]; dup {
@@ -20,16 +24,19 @@ func (c *Checker) hoistTopLevelTypeDeclarations() {
 				DuplicateSpan: c.sourceSpan(loc),
 				OriginalSpan:  c.sourceSpan(original),
 			}.build())
-			c.markDuplicateTopLevelTypeDeclaration(stmt)
+			c.markDuplicateTopLevelDeclaration(stmt)
 			continue
 		}
 		seen[name] = loc
+		if !isTopLevelTypeDeclaration(stmt) {
+			continue
+		}
 		if isReservedBuiltinTypeName(name) {
 			c.addDiagnostic(builtInTypeRedeclarationDiagnostic{
 				Name: name,
 				Span: c.sourceSpan(loc),
 			}.build())
-			c.markDuplicateTopLevelTypeDeclaration(stmt)
+			c.markDuplicateTopLevelDeclaration(stmt)
 			continue
 		}
 
@@ -46,6 +53,7 @@ func (c *Checker) hoistTopLevelTypeDeclarations() {
 				Fields:        make(map[string]Type),
 				GenericParams: genericParams,
 				Private:       s.Private,
+				Deprecated:    deprecationNote(s.Deprecated),
 			}, false)
 		case *parse.TraitDefinition:
 			c.scope.add(name, &Trait{Name: s.Name.Name, ModulePath: c.typeOwnerPath(), private: s.Private}, false)
@@ -65,45 +73,202 @@ func (c *Checker) hoistTopLevelTypeDeclarations() {
 }
 
 // hoistTopLevelFunctionSignatures pre-resolves the signatures of top-level
-// function declarations and adds them to module scope so functions can be
-// referenced before their declaration within a module.
+// function and static function declarations and adds them to module scope so
+// functions can be referenced before their declaration within a module.
 func (c *Checker) hoistTopLevelFunctionSignatures() {
 	c.hoistedTopLevelFunctions = map[*parse.FunctionDeclaration]*FunctionDef{}
 	for i := range c.input.Statements {
-		def, ok := c.input.Statements[i].(*parse.FunctionDeclaration)
-		if !ok {
+		if c.isDuplicateTopLevelDeclaration(c.input.Statements[i]) {
 			continue
 		}
-		params := c.resolveParametersWithContext(def.Parameters, nil)
-		returnType := c.resolveReturnTypeWithContext(def.ReturnType, nil)
-		resolved := true
-		for i, param := range def.Parameters {
-			if param.Type != nil && params[i].Type == nil {
-				resolved = false
-				break
+		switch s := c.input.Statements[i].(type) {
+		case *parse.FunctionDeclaration:
+			if fn := c.hoistFunctionSignature(s); fn != nil {
+				c.scope.add(s.Name, fn, false)
+			}
+		case *parse.StaticFunctionDeclaration:
+			target, ok := s.Path.Target.(*parse.Identifier)
+			if !ok {
+				continue
+			}
+			sym, found := c.scope.get(target.Name)
+			if !found || !isNominalType(sym.Type) {
+				continue
+			}
+			var fn *FunctionDef
+			c.withSelfType(sym.Type, func() {
+				fn = c.hoistFunctionSignature(&s.FunctionDeclaration)
+			})
+			if fn != nil {
+				fn.Name = s.Path.String()
+				c.scope.add(fn.Name, fn, false)
 			}
 		}
-		if !resolved {
-			// Leave unresolvable signatures to the in-order pass so its
-			// diagnostics and panics behave as before.
-			continue
+	}
+}
+
+func (c *Checker) hoistFunctionSignature(def *parse.FunctionDeclaration) *FunctionDef {
+	params := c.resolveParametersWithContext(def.Parameters, nil)
+	returnType := c.resolveReturnTypeWithContext(def.ReturnType, nil)
+	if !parametersResolved(def.Parameters, params) {
+		// Leave unresolvable signatures to the in-order pass so its
+		// diagnostics and panics behave as before.
+		return nil
+	}
+	fn := &FunctionDef{
+		Name:          def.Name,
+		GenericParams: append([]string(nil), def.TypeParams...),
+		Parameters:    params,
+		ReturnType:    returnType,
+		Body:          nil,
+		Private:       def.Private,
+		IsTest:        def.IsTest,
+		Deprecated:    deprecationNote(def.Deprecated),
+	}
+	// Source functions introduce every generic visible in their signature.
+	// Recording ownership here lets calls distinguish those variables from
+	// generics merely captured by nested closures or receiver methods.
+	fn.CallGenericParams = append([]string{}, genericParamsForFunction(fn)...)
+	c.hoistedTopLevelFunctions[def] = fn
+	return fn
+}
+
+func parametersResolved(declared []parse.Parameter, params []Parameter) bool {
+	for i, param := range declared {
+		if param.Type != nil && params[i].Type == nil {
+			return false
 		}
-		fn := &FunctionDef{
-			Name:          def.Name,
-			GenericParams: append([]string(nil), def.TypeParams...),
-			Parameters:    params,
-			ReturnType:    returnType,
-			Body:          nil,
-			Private:       def.Private,
-			IsTest:        def.IsTest,
-		}
-		// Source functions introduce every generic visible in their signature.
-		// Recording ownership here lets calls distinguish those variables from
-		// generics merely captured by nested closures or receiver methods.
-		fn.CallGenericParams = append([]string{}, genericParamsForFunction(fn)...)
-		c.hoistedTopLevelFunctions[def] = fn
-		c.scope.add(def.Name, fn, false)
 	}
+	return true
+}
+
+// hoistTopLevelImplSignatures registers the methods of top-level impl blocks
+// and trait implementations, and the traits each type implements, before any
+// body is checked so a method or trait conformance can be used above the block
+// declaring it. Diagnostics are left to the in-order pass, which fills these
+// definitions in place through adoptHoistedMethod.
+func (c *Checker) hoistTopLevelImplSignatures() {
+	c.hoistedMethodSignatures = map[*parse.FunctionDeclaration]*FunctionDef{}
+	diagnosticsLen := len(c.diagnostics)
+	warningsLen := len(c.warnings)
+	spansMark := c.spansMark()
+	halted := c.halted
+	defer func() {
+		c.diagnostics = c.diagnostics[:diagnosticsLen]
+		c.warnings = c.warnings[:warningsLen]
+		c.spansTruncate(spansMark)
+		c.halted = halted
+	}()
+
+	for i := range c.input.Statements {
+		switch s := c.input.Statements[i].(type) {
+		case *parse.ImplBlock:
+			if sym, ok := c.scope.get(s.Target.Name); ok {
+				c.hoistMethodSignatures(sym.Type, s.Receiver.Name, s.Methods)
+			}
+		case *parse.TraitImplementation:
+			trait := c.hoistedImplementedTrait(s.Trait)
+			sym, ok := c.scope.get(s.ForType.Name)
+			if trait == nil || !ok {
+				continue
+			}
+			switch target := sym.Type.(type) {
+			case *StructDef:
+				if !slices.Contains(target.Traits, trait) {
+					target.Traits = append(target.Traits, trait)
+				}
+			case *Enum:
+				if IsBuiltinError(trait) {
+					continue
+				}
+				if !slices.Contains(target.Traits, trait) {
+					target.Traits = append(target.Traits, trait)
+				}
+			default:
+				continue
+			}
+			c.hoistMethodSignatures(sym.Type, s.Receiver.Name, s.Methods)
+		}
+	}
+}
+
+func (c *Checker) hoistMethodSignatures(target Type, receiver string, methods []parse.FunctionDeclaration) {
+	structDef, isStruct := target.(*StructDef)
+	enum, isEnum := target.(*Enum)
+	if !isStruct && !isEnum {
+		return
+	}
+	receiverGenerics := genericParamsForType(target)
+	c.withSelfType(target, func() {
+		for i := range methods {
+			method := &methods[i]
+			if len(method.TypeParams) > 0 {
+				continue
+			}
+			c.pushMethodGenericAllowlist(receiverGenerics)
+			params := c.resolveParametersWithContext(method.Parameters, nil)
+			returnType := c.resolveReturnTypeWithContext(method.ReturnType, nil)
+			c.popMethodGenericAllowlist()
+			if !parametersResolved(method.Parameters, params) {
+				continue
+			}
+			fn := &FunctionDef{
+				Name:       method.Name,
+				Parameters: params,
+				ReturnType: returnType,
+				Private:    method.Private,
+				Deprecated: deprecationNote(method.Deprecated),
+				Receiver:   receiver,
+			}
+			c.hoistedMethodSignatures[method] = fn
+			if isStruct {
+				fn.Mutates = method.Mutates
+				c.addStructMethod(structDef, fn)
+				continue
+			}
+			if enum.Methods == nil {
+				enum.Methods = make(map[string]*FunctionDef)
+			}
+			enum.Methods[method.Name] = fn
+		}
+	})
+}
+
+// hoistedImplementedTrait resolves the trait named by a trait implementation
+// without reporting anything, returning nil when it is not an Ard trait.
+func (c *Checker) hoistedImplementedTrait(name parse.Expression) *Trait {
+	var sym Symbol
+	switch name := name.(type) {
+	case parse.Identifier:
+		if s, ok := c.scope.get(name.Name); ok {
+			sym = *s
+		} else if name.Name == "Error" {
+			sym = Symbol{Name: "Error", Type: BuiltinError}
+		}
+	case parse.StaticProperty:
+		target, ok := name.Target.(*parse.Identifier)
+		property, isIdentifier := name.Property.(*parse.Identifier)
+		if !ok || !isIdentifier {
+			return nil
+		}
+		if mod := c.resolveModule(target.Name); mod != nil {
+			sym = mod.Get(property.Name)
+		}
+	}
+	trait, _ := sym.Type.(*Trait)
+	return trait
+}
+
+// adoptHoistedMethod copies a method definition checked by the in-order pass
+// into the definition hoisted for it, so call sites checked earlier share the
+// checked signature and body.
+func (c *Checker) adoptHoistedMethod(method *parse.FunctionDeclaration, fnDef *FunctionDef) *FunctionDef {
+	hoisted := c.hoistedMethodSignatures[method]
+	if hoisted == nil || fnDef == nil {
+		return fnDef
+	}
+	*hoisted = *fnDef
+	return hoisted
 }
 
 func (c *Checker) populateTopLevelTypeDefinitions() {
@@ -121,7 +286,7 @@ func isTopLevelTypeDeclaration(stmt parse.Statement) bool {
 }
 
 func (c *Checker) checkedTopLevelTypeStatement(stmt parse.Statement) *Statement {
-	if c.isDuplicateTopLevelTypeDeclaration(stmt) {
+	if c.isDuplicateTopLevelDeclaration(stmt) {
 		return nil
 	}
 	switch s := stmt.(type) {
@@ -130,6 +295,7 @@ func (c *Checker) checkedTopLevelTypeStatement(stmt parse.Statement) *Statement
 		if !ok {
 			return nil
 		}
+		c.resolveStructDefaults(def, s)
 		return &Statement{Stmt: def}
 	case *parse.TypeDeclaration:
 		if len(s.Type) <= 1 {
@@ -224,6 +390,10 @@ func collectGenericParamsFromDeclaredType(t parse.DeclaredType, params *[]string
 			collectGenericParamsFromDeclaredType(param, params, seen)
 		}
 		collectGenericParamsFromDeclaredType(typ.Return, params, seen)
+	case *parse.UnionType:
+		for _, member := range typ.Members {
+			collectGenericParamsFromDeclaredType(member, params, seen)
+		}
 	case *parse.CustomType:
 		for _, arg := range typ.TypeArgs {
 			collectGenericParamsFromDeclaredType(arg, params, seen)
@@ -235,15 +405,15 @@ func collectGenericParamsFromDeclaredType(t parse.DeclaredType, params *[]string
 	}
 }
 
-func (c *Checker) markDuplicateTopLevelTypeDeclaration(stmt parse.Statement) {
-	if c.duplicateTopLevelTypeDeclarations == nil {
-		c.duplicateTopLevelTypeDeclarations = map[parse.Statement]bool{}
+func (c *Checker) markDuplicateTopLevelDeclaration(stmt parse.Statement) {
+	if c.duplicateTopLevelDeclarations == nil {
+		c.duplicateTopLevelDeclarations = map[parse.Statement]bool{}
 	}
-	c.duplicateTopLevelTypeDeclarations[stmt] = true
+	c.duplicateTopLevelDeclarations[stmt] = true
 }
 
-func (c *Checker) isDuplicateTopLevelTypeDeclaration(stmt parse.Statement) bool {
-	return c.duplicateTopLevelTypeDeclarations != nil && c.duplicateTopLevelTypeDeclarations[stmt]
+func (c *Checker) isDuplicateTopLevelDeclaration(stmt parse.Statement) bool {
+	return c.duplicateTopLevelDeclarations != nil && c.duplicateTopLevelDeclarations[stmt]
 }
 
 func (c *Checker) isResolvingStructDefinition(def *StructDef) bool {
@@ -318,7 +488,16 @@ func (c *Checker) populateStructDefinition(def *StructDef, decl *parse.StructDef
 			continue
 		}
 		fieldLocations[field.Name.Name] = field.Name.GetLocation()
+		if _, declared := def.Fields[field.Name.Name]; !declared {
+			def.FieldOrder = append(def.FieldOrder, field.Name.Name)
+		}
 		def.Fields[field.Name.Name] = fieldType
+		if field.Private {
+			if def.PrivateFields == nil {
+				def.PrivateFields = map[string]bool{}
+			}
+			def.PrivateFields[field.Name.Name] = true
+		}
 		if c.spans != nil {
 			c.spans.add(SpanRecord{
 				Loc:   field.Name.GetLocation(),
@@ -1,10 +1,16 @@
 package checker
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+)
 
+// typeEqualKey identifies a pair of types already being compared. Each side
+// is a pointer to the type where there is one, so recording a pair does not
+// mean formatting either type.
 type typeEqualKey struct {
-	left  string
-	right string
+	left  any
+	right any
 }
 
 func equalTypes(left Type, right Type) bool {
@@ -15,6 +21,11 @@ func equalTypesSeen(left Type, right Type, seen map[typeEqualKey]struct{}) bool
 	if left == nil || right == nil {
 		return left == right
 	}
+	// shared shapes and named types are compared by identity first, which
+	// settles most comparisons without walking either type
+	if isPointerType(left) && left == right {
+		return true
+	}
 	key := typeEqualKey{left: typeEqualID(left), right: typeEqualID(right)}
 	if _, ok := seen[key]; ok {
 		return true
@@ -214,7 +225,7 @@ func namedTypeOwnersDiffer(left string, right string) bool {
 
 func equalUnionSeen(left Union, right Type, seen map[typeEqualKey]struct{}) bool {
 	if r, ok := right.(*Union); ok {
-		if namedTypeOwnersDiffer(left.ModulePath, r.ModulePath) || len(left.Types) != len(r.Types) {
+		if !(left.Inline && r.Inline) && namedTypeOwnersDiffer(left.ModulePath, r.ModulePath) || len(left.Types) != len(r.Types) {
 			return false
 		}
 		for _, leftType := range left.Types {
@@ -239,54 +250,35 @@ func equalUnionSeen(left Union, right Type, seen map[typeEqualKey]struct{}) bool
 	return false
 }
 
-func typeEqualID(t Type) string {
+func isPointerType(t Type) bool {
+	return reflect.ValueOf(t).Kind() == reflect.Pointer
+}
+
+func typeEqualID(t Type) any {
 	switch v := t.(type) {
-	case *Trait:
-		return fmt.Sprintf("Trait:%p", v)
 	case Trait:
-		return fmt.Sprintf("Trait:%s:%s", v.ModulePath, v.Name)
-	case *List:
-		return fmt.Sprintf("List:%p", v)
-	case *FixedArray:
-		return fmt.Sprintf("FixedArray:%p", v)
-	case *Chan:
-		return fmt.Sprintf("Chan:%p", v)
-	case *Receiver:
-		return fmt.Sprintf("Receiver:%p", v)
-	case *Sender:
-		return fmt.Sprintf("Sender:%p", v)
-	case *Map:
-		return fmt.Sprintf("Map:%p", v)
+		return "Trait:" + v.ModulePath + ":" + v.Name
 	case Map:
-		return fmt.Sprintf("Map:%p", &v)
-	case *Maybe:
-		return fmt.Sprintf("Maybe:%p", v)
-	case *TypeVar:
-		return fmt.Sprintf("TypeVar:%p", v)
-	case *Result:
-		return fmt.Sprintf("Result:%p", v)
-	case *MutableRef:
-		return fmt.Sprintf("MutableRef:%p", v)
-	case *FunctionDef:
-		return fmt.Sprintf("Function:%p", v)
+		return &v
 	case FunctionDef:
-		return fmt.Sprintf("Function:%s", v.Name)
+		return "Function:" + v.Name
 	case *Enum:
-		return fmt.Sprintf("Enum:%s:%s", v.ModulePath, v.Name)
+		return "Enum:" + v.ModulePath + ":" + v.Name
 	case Enum:
-		return fmt.Sprintf("Enum:%s:%s", v.ModulePath, v.Name)
-	case *StructDef:
-		return fmt.Sprintf("Struct:%p", v)
+		return "Enum:" + v.ModulePath + ":" + v.Name
 	case StructDef:
-		return fmt.Sprintf("Struct:%s:%s", v.ModulePath, v.Name)
+		return "Struct:" + v.ModulePath + ":" + v.Name
 	case *Union:
 		if v.Name != "" {
-			return fmt.Sprintf("Union:%s:%s", v.ModulePath, v.Name)
+			return "Union:" + v.ModulePath + ":" + v.Name
 		}
-		return fmt.Sprintf("Union:%p", v)
+		return v
 	case Union:
-		return fmt.Sprintf("Union:%s:%s", v.ModulePath, v.Name)
+		return "Union:" + v.ModulePath + ":" + v.Name
 	default:
+		if isPointerType(t) {
+			return t
+		}
 		return fmt.Sprintf("%T:%s", t, t.String())
 	}
 }
@@ -10,6 +10,12 @@ func areCompatible(expected Type, actual Type) bool {
 	if trait, ok := expected.(*Trait); ok {
 		return actual.hasTrait(trait)
 	}
+	if union, ok := expected.(*Union); ok {
+		return unionAccepts(union, actual)
+	}
+	if unionNarrows(expected, actual) {
+		return false
+	}
 	return expected.equal(actual)
 }
 
@@ -66,10 +72,10 @@ func MakeMutableRef(of Type) *MutableRef {
 
 func (m *MutableRef) Of() Type { return m.of }
 func (m *MutableRef) String() string {
-	if m == nil || m.of == nil {
+	if m == nil {
 		return "mut ?"
 	}
-	return "mut " + m.of.String()
+	return formatType(m)
 }
 func (m *MutableRef) get(name string) Type {
 	if m == nil || m.of == nil {
@@ -219,12 +225,48 @@ func (s str) get(name string) Type {
 			Parameters: []Parameter{},
 			ReturnType: Str,
 		}
-	case "trim":
+	case "trim", "trim_start", "trim_end", "to_upper", "to_lower", "reverse":
 		return &FunctionDef{
 			Name:       name,
 			Parameters: []Parameter{},
 			ReturnType: Str,
 		}
+	case "slice":
+		return &FunctionDef{
+			Name: name,
+			Parameters: []Parameter{
+				{Name: "start", Type: Int},
+				{Name: "end", Type: Int},
+			},
+			ReturnType: Str,
+		}
+	case "index_of":
+		return &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{{Name: "sub", Type: Str}},
+			ReturnType: MakeMaybe(Int),
+		}
+	case "pad_left", "pad_right":
+		return &FunctionDef{
+			Name: name,
+			Parameters: []Parameter{
+				{Name: "width", Type: Int},
+				{Name: "fill", Type: Str},
+			},
+			ReturnType: Str,
+		}
+	case "repeat":
+		return &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{{Name: "count", Type: Int}},
+			ReturnType: Str,
+		}
+	case "chars", "graphemes":
+		return &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{},
+			ReturnType: MakeList(Str),
+		}
 	default:
 		return nil
 	}
@@ -346,12 +388,30 @@ func (i _int) get(name string) Type {
 			Parameters: []Parameter{},
 			ReturnType: Str,
 		}
-	case "to_f64":
+	case "to_f64", "to_float":
 		return &FunctionDef{
 			Name:       name,
 			Parameters: []Parameter{},
 			ReturnType: Float64,
 		}
+	case "abs":
+		return &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{},
+			ReturnType: Int,
+		}
+	case "pow":
+		return &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{{Name: "exponent", Type: Int}},
+			ReturnType: Int,
+		}
+	case "clamp":
+		return &FunctionDef{
+			Name:       name,
+			Parameters: []Parameter{{Name: "low", Type: Int}, {Name: "high", Type: Int}},
+			ReturnType: Int,
+		}
 	default:
 		return nil
 	}
@@ -440,7 +500,7 @@ func (f float) get(name string) Type {
 			Parameters: []Parameter{},
 			ReturnType: Str,
 		}
-	case "to_int":
+	case "to_int", "round", "floor", "ceil":
 		return &FunctionDef{
 			Name:       name,
 			Parameters: []Parameter{},
@@ -525,14 +585,18 @@ func (v *void) hasTrait(trait *Trait) bool { return false }
 var Void = &void{}
 
 type List struct {
-	of Type
+	of     Type
+	closed bool
 }
 
 func MakeList(of Type) *List {
-	return &List{of}
+	if isClosedType(of) {
+		return internType(internKey{kind: internList, left: of}, func() *List { return &List{of: of, closed: true} })
+	}
+	return &List{of: of}
 }
 func (l List) String() string {
-	return "[" + l.of.String() + "]"
+	return formatType(&l)
 }
 func (l List) get(name string) Type {
 	switch name {
@@ -622,7 +686,7 @@ func MakeFixedArray(of Type, length int) *FixedArray {
 }
 
 func (a FixedArray) String() string {
-	return fmt.Sprintf("[%s; %d]", typeSyntaxString(a.of), a.length)
+	return formatType(&a)
 }
 
 func (a FixedArray) get(name string) Type {
@@ -666,7 +730,7 @@ func MakeChan(of Type) *Chan {
 	return &Chan{of}
 }
 func (c Chan) String() string {
-	return "Chan<" + c.of.String() + ">"
+	return formatType(&c)
 }
 func (c Chan) get(name string) Type {
 	switch name {
@@ -731,7 +795,7 @@ func chanSendMethod(of Type) Type {
 	return &FunctionDef{Name: "send", Parameters: []Parameter{{Name: "value", Type: of}}, ReturnType: Void}
 }
 func chanRecvMethod(of Type) Type {
-	return &FunctionDef{Name: "recv", ReturnType: &Maybe{of}}
+	return &FunctionDef{Name: "recv", ReturnType: &Maybe{of: of}}
 }
 func chanCloseMethod() Type {
 	return &FunctionDef{Name: "close", ReturnType: Void}
@@ -747,7 +811,7 @@ func MakeReceiver(of Type) *Receiver {
 	return &Receiver{of}
 }
 func (c Receiver) String() string {
-	return "Receiver<" + c.of.String() + ">"
+	return formatType(&c)
 }
 func (c Receiver) get(name string) Type {
 	if name == "recv" {
@@ -775,7 +839,7 @@ func MakeSender(of Type) *Sender {
 	return &Sender{of}
 }
 func (c Sender) String() string {
-	return "Sender<" + c.of.String() + ">"
+	return formatType(&c)
 }
 func (c Sender) get(name string) Type {
 	switch name {
@@ -797,16 +861,20 @@ func (c *Sender) Of() Type {
 }
 
 type Map struct {
-	key   Type
-	value Type
+	key    Type
+	value  Type
+	closed bool
 }
 
 func MakeMap(key, value Type) *Map {
-	return &Map{key, value}
+	if isClosedType(key) && isClosedType(value) {
+		return internType(internKey{kind: internMap, left: key, right: value}, func() *Map { return &Map{key: key, value: value, closed: true} })
+	}
+	return &Map{key: key, value: value}
 }
 
 func (m Map) String() string {
-	return fmt.Sprintf("[%s: %s]", m.key.String(), m.value.String())
+	return formatType(&m)
 }
 func (m Map) equal(other Type) bool {
 	return equalTypes(m, other)
@@ -821,7 +889,7 @@ func (m Map) get(name string) Type {
 		return &FunctionDef{
 			Name:       name,
 			Parameters: []Parameter{{Name: "key", Type: m.key}},
-			ReturnType: &Maybe{m.value},
+			ReturnType: &Maybe{of: m.value},
 		}
 	case "keys":
 		return &FunctionDef{
@@ -869,7 +937,8 @@ func (m *Map) Value() Type {
 }
 
 type Maybe struct {
-	of Type
+	of     Type
+	closed bool
 }
 
 func IsMaybe(t Type) bool {
@@ -878,42 +947,87 @@ func IsMaybe(t Type) bool {
 }
 
 func MakeMaybe(of Type) *Maybe {
-	return &Maybe{of}
-}
-
-func (m *Maybe) String() string {
-	switch m.of.(type) {
-	case *FunctionDef, FunctionDef, *Result, *MutableRef:
-		return "(" + typeSyntaxString(m.of) + ")?"
-	default:
-		return typeSyntaxString(m.of) + "?"
+	if isClosedType(of) {
+		return internType(internKey{kind: internMaybe, left: of}, func() *Maybe { return &Maybe{of: of, closed: true} })
 	}
+	return &Maybe{of: of}
 }
 
-func typeSyntaxString(t Type) string {
-	switch typ := t.(type) {
+func (m *Maybe) String() string {
+	return formatType(m)
+}
+
+// formatType renders t exactly as it is written in Ard source: `[Int]`,
+// `Str?`, `Int!Str`, `Box<Int>`, `fn(Str) Int`. Every type's String method
+// and every diagnostic goes through it, so a type reads the same everywhere.
+func formatType(t Type) string {
+	switch typ := deref(t).(type) {
+	case nil:
+		return "?"
+	case *TypeVar:
+		return "$" + typ.name
 	case *FunctionDef:
 		return functionTypeString(*typ)
 	case FunctionDef:
 		return functionTypeString(typ)
 	case *Maybe:
-		return typ.String()
+		switch deref(typ.of).(type) {
+		case *FunctionDef, FunctionDef, *Result, *MutableRef:
+			return "(" + formatType(typ.of) + ")?"
+		default:
+			return formatType(typ.of) + "?"
+		}
 	case *Result:
 		return resultOperandSyntax(typ.val) + "!" + resultOperandSyntax(typ.err)
 	case *List:
-		return "[" + typeSyntaxString(typ.of) + "]"
+		return "[" + formatType(typ.of) + "]"
 	case *FixedArray:
-		return fmt.Sprintf("[%s; %d]", typeSyntaxString(typ.of), typ.length)
+		return fmt.Sprintf("[%s; %d]", formatType(typ.of), typ.length)
 	case *Map:
-		return "[" + typeSyntaxString(typ.key) + ": " + typeSyntaxString(typ.value) + "]"
+		return "[" + formatType(typ.key) + ": " + formatType(typ.value) + "]"
+	case *Chan:
+		return "Chan<" + formatType(typ.of) + ">"
+	case *Receiver:
+		return "Receiver<" + formatType(typ.of) + ">"
+	case *Sender:
+		return "Sender<" + formatType(typ.of) + ">"
+	case *MutableRef:
+		return "mut " + formatType(typ.of)
+	case *StructDef:
+		// Specialized copies of imported generic structs already carry their
+		// arguments in the name.
+		if len(typ.TypeArgs) == 0 || strings.Contains(typ.Name, "<") {
+			return typ.Name
+		}
+		return typ.Name + "<" + formatTypeList(typ.TypeArgs) + ">"
+	case *ForeignType:
+		name := typ.Name
+		if typ.Qualifier != "" {
+			name = typ.Qualifier + "::" + typ.Name
+		}
+		if len(typ.TypeArgs) > 0 {
+			name += "<" + formatTypeList(typ.TypeArgs) + ">"
+		}
+		if typ.Pointer {
+			return "mut " + name
+		}
+		return name
 	default:
-		return t.String()
+		return typ.String()
+	}
+}
+
+func formatTypeList(types []Type) string {
+	parts := make([]string, len(types))
+	for i, t := range types {
+		parts[i] = formatType(t)
 	}
+	return strings.Join(parts, ", ")
 }
 
 func resultOperandSyntax(t Type) string {
-	value := typeSyntaxString(t)
-	switch t.(type) {
+	value := formatType(t)
+	switch deref(t).(type) {
 	case *FunctionDef, FunctionDef, *Result:
 		return "(" + value + ")"
 	default:
@@ -929,18 +1043,19 @@ func callableTypeString(params []Parameter, returnType Type) string {
 	paramStrs := make([]string, len(params))
 	for i := range params {
 		mutable, paramType := normalizedParamMutability(params[i])
-		paramStrs[i] = typeSyntaxString(paramType)
+		paramStrs[i] = formatType(paramType)
 		// A pointer-shaped foreign type renders its own `mut` prefix.
 		if foreign, ok := paramType.(*ForeignType); mutable && (!ok || !foreign.Pointer) {
 			paramStrs[i] = "mut " + paramStrs[i]
 		}
 	}
 	rendered := fmt.Sprintf("fn(%s)", strings.Join(paramStrs, ", "))
-	// Ard syntax omits the return type for non-returning functions.
-	if returnType == nil || returnType.equal(Void) {
+	// Ard syntax omits the return type for non-returning functions. An
+	// unbound generic equals anything, so compare the resolved type itself.
+	if _, isVoid := deref(returnType).(*void); returnType == nil || isVoid {
 		return rendered
 	}
-	return rendered + " " + typeSyntaxString(returnType)
+	return rendered + " " + formatType(returnType)
 }
 func (m *Maybe) get(name string) Type {
 	switch name {
@@ -1038,10 +1153,7 @@ type TypeVar struct {
 }
 
 func (a TypeVar) String() string {
-	if a.bound && a.actual != nil {
-		return a.actual.String()
-	}
-	return "$" + a.name
+	return formatType(&a)
 }
 
 func (a *TypeVar) Name() string {
@@ -1070,16 +1182,20 @@ func (a *TypeVar) hasTrait(trait *Trait) bool {
 }
 
 type Result struct {
-	val Type
-	err Type
+	val    Type
+	err    Type
+	closed bool
 }
 
 func MakeResult(val, err Type) *Result {
-	return &Result{val, err}
+	if isClosedType(val) && isClosedType(err) {
+		return internType(internKey{kind: internResult, left: val, right: err}, func() *Result { return &Result{val: val, err: err, closed: true} })
+	}
+	return &Result{val: val, err: err}
 }
 
 func (r Result) String() string {
-	return fmt.Sprintf("%s!%s", r.val.String(), r.err.String())
+	return formatType(&r)
 }
 
 func (r Result) get(name string) Type {
@@ -136,7 +136,65 @@ func TestTypeRenderingIsFormatterCanonical(t *testing.T) {
 	if got, want := MakeMap(Str, Int).String(), "[Str: Int]"; got != want {
 		t.Fatalf("map type = %q, want %q", got, want)
 	}
-	if got, want := typeSyntaxString(MakeMap(Str, MakeList(Int))), "[Str: [Int]]"; got != want {
+	if got, want := formatType(MakeMap(Str, MakeList(Int))), "[Str: [Int]]"; got != want {
 		t.Fatalf("map syntax = %q, want %q", got, want)
 	}
 }
+
+// Nested types render through the same formatter as their containers, so a
+// type reads the same at any depth.
+func TestFormatTypeRendersSourceSyntax(t *testing.T) {
+	returnsResult := &FunctionDef{ReturnType: MakeResult(Int, Str)}
+	bound := &TypeVar{name: "T", actual: Int, bound: true}
+	unbound := &TypeVar{name: "T"}
+	box := &StructDef{Name: "Box", TypeArgs: []Type{MakeMaybe(Str)}}
+	tests := []struct {
+		typ  Type
+		want string
+	}{
+		{MakeList(MakeMaybe(Int)), "[Int?]"},
+		{MakeMaybe(returnsResult), "(fn() Int!Str)?"},
+		{MakeResult(returnsResult, Str), "(fn() Int!Str)!Str"},
+		{MakeMap(Str, MakeResult(MakeList(Int), Str)), "[Str: [Int]!Str]"},
+		{box, "Box<Str?>"},
+		{MakeList(bound), "[Int]"},
+		{MakeMaybe(&TypeVar{name: "F", actual: returnsResult, bound: true}), "(fn() Int!Str)?"},
+		{&FunctionDef{Parameters: []Parameter{{Name: "x", Type: unbound}}, ReturnType: unbound}, "fn($T) $T"},
+		{MakeChan(MakeMap(Str, bound)), "Chan<[Str: Int]>"},
+		{&MutableRef{of: MakeList(bound)}, "mut [Int]"},
+	}
+	for _, test := range tests {
+		if got := test.typ.String(); got != test.want {
+			t.Errorf("String() = %q, want %q", got, test.want)
+		}
+		if got := formatType(test.typ); got != test.want {
+			t.Errorf("formatType() = %q, want %q", got, test.want)
+		}
+	}
+}
+
+func TestClosedCompositeTypesAreShared(t *testing.T) {
+	if MakeMap(Str, MakeList(Int)) != MakeMap(Str, MakeList(Int)) {
+		t.Fatalf("[Str: [Int]] should be one shared instance")
+	}
+	if MakeResult(MakeMaybe(Int), Str) != MakeResult(MakeMaybe(Int), Str) {
+		t.Fatalf("Int?!Str should be one shared instance")
+	}
+	if !isClosedType(MakeList(MakeMaybe(Bool))) {
+		t.Fatalf("[Bool?] should be closed")
+	}
+
+	typeVar := &TypeVar{name: "T"}
+	open := MakeList(typeVar)
+	if open == MakeList(typeVar) || isClosedType(open) {
+		t.Fatalf("a list of a type variable should not be shared")
+	}
+	if derefType(MakeList(Int)) != MakeList(Int) {
+		t.Fatalf("dereferencing a closed type should return it as is")
+	}
+	typeVar.actual = Int
+	typeVar.bound = true
+	if derefType(open) != MakeList(Int) {
+		t.Fatalf("a list of a bound type variable should dereference to the shared [Int]")
+	}
+}
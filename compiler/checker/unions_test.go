@@ -108,3 +108,147 @@ func TestTypeUnions(t *testing.T) {
 		},
 	})
 }
+
+func TestUnionWidening(t *testing.T) {
+	run(t, []test{
+		{
+			name: "Members of nested unions widen in every position",
+			input: `
+				type Num = Int | Float64
+				type Value = Num | Str
+
+				struct Cell { value: Value }
+
+				fn show(value: Value) Str { "" }
+				fn make() Value { 1.5 }
+
+				let a: Value = 1
+				let list: [Value] = [1, 2.5, "three"]
+				let cell = Cell{value: 4}
+				show(5)
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "Unions widen into unions that contain them",
+			input: `
+				type Num = Int | Float64
+				type Small = Int | Str
+				type Value = Num | Str
+
+				struct Cell { value: Value }
+
+				fn show(value: Value) Str { "" }
+				fn widen(n: Num) Value { n }
+
+				let n: Num = 1
+				let small: Small = "s"
+				let a: Value = n
+				let b: Value = small
+				let list: [Value] = [n, small]
+				let map: [Str: Value] = ["n": n]
+				let cell = Cell{value: small}
+				show(n)
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "Unions do not narrow to a member",
+			input: `
+				type Num = Int | Float64
+				fn take(i: Int) Int { i }
+				let n: Num = 1
+				let i: Int = n
+				take(n)
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Num"},
+				{Kind: checker.Error, Message: "Type mismatch: Expected Int, got Num"},
+			},
+		},
+		{
+			name: "Unions with a member outside the expected union do not widen",
+			input: `
+				type Num = Int | Float64
+				type Flag = Int | Bool
+				let f: Flag = true
+				let n: Num = f
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Num, got Flag"},
+			},
+		},
+	})
+}
+
+func TestInlineUnions(t *testing.T) {
+	run(t, []test{
+		{
+			name: "Inline unions are usable in every annotation position",
+			input: `
+				struct Cell { value: Int | Str }
+
+				fn show(value: Int | Str) Str {
+				  match value {
+				    Int(i) => "int",
+				    Str(s) => s,
+				  }
+				}
+				fn make(flag: Bool) Int | Str {
+				  match flag {
+				    true => 1,
+				    false => "one",
+				  }
+				}
+
+				let a: Str | Int = make(true)
+				let list: [Int | Str] = [1, "two"]
+				let cell = Cell{value: a}
+				show(a)
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "Inline unions widen into named unions and back",
+			input: `
+				type Value = Int | Str
+				fn take(value: Value) Value { value }
+				let inline: Int | Str = 1
+				let named: Value = take(inline)
+				let again: Str | Int = named
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+		{
+			name: "Inline union matches must be exhaustive",
+			input: `
+				fn show(value: Int | Str) Str {
+				  match value {
+				    Int(i) => "int",
+				  }
+				}
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Incomplete match: missing case for 'Str'"},
+			},
+		},
+		{
+			name: "Inline unions print their members in canonical order",
+			input: `
+				let a: Str | Int = 1
+				let b: Bool = a
+			`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Type mismatch: Expected Bool, got Int | Str"},
+			},
+		},
+		{
+			name: "Repeated members collapse",
+			input: `
+				let a: Int | Int = 1
+				let b: Int = a
+			`,
+			diagnostics: []checker.Diagnostic{},
+		},
+	})
+}
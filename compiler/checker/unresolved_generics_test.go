@@ -0,0 +1,48 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestUninferredGenericsAreReportedAtTheirBindingSite(t *testing.T) {
+	prelude := "struct Box<$T> {\n  value: $T?,\n}\n\nfn main() {\n  "
+	for _, tc := range []struct {
+		name string
+		body string
+		row  int
+		col  int
+	}{
+		{name: "loop iterable", body: "for b in [Box{}] {\n  }", row: 6, col: 13},
+		{name: "nested in an expression", body: "let n = [Box{}].size()", row: 6, col: 12},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			diagnostic := requireDiagnosticCode(t, checkSource(t, prelude+tc.body+"\n}\n"), checker.DiagnosticCodeUnresolvedGeneric)
+			if diagnostic.Message != "cannot infer type for generic $T here" {
+				t.Fatalf("message = %q", diagnostic.Message)
+			}
+			if start := diagnostic.Primary.Span.Location.Start; start.Row != tc.row || start.Col != tc.col {
+				t.Fatalf("reported at %d:%d, want %d:%d", start.Row, start.Col, tc.row, tc.col)
+			}
+		})
+	}
+}
+
+func TestStructLiteralGenericOnlyInMaybeFieldMustBeInferred(t *testing.T) {
+	source := "struct Box<$T> {\n  value: $T?,\n}\n\nlet b = Box{value: Result::ok(1)}\n"
+	diagnostics := checkSource(t, source)
+	requireDiagnosticCode(t, diagnostics, checker.DiagnosticCodeUnresolvedGeneric)
+	if len(diagnostics) != 1 {
+		t.Fatalf("expected one diagnostic for one unresolved generic, got %v", diagnostics)
+	}
+
+	for _, source := range []string{
+		"struct Box<$T> {\n  value: $T?,\n}\n\nlet b: Box<Int> = Box{}\n",
+		"struct Box<$T> {\n  value: $T?,\n}\n\nfn empty() Box<$T> {\n  Box{}\n}\n\nfn ints() Box<Int> {\n  Box{}\n}\n",
+	} {
+		if diagnostics := checkSource(t, source); len(diagnostics) > 0 {
+			t.Fatalf("unexpected diagnostics %v", diagnostics)
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package checker
 
+import "strings"
+
 // UserModule represents a user-defined module that implements the Module interface
 type UserModule struct {
-	filePath      string
-	publicSymbols map[string]Symbol // only public symbols from the checked program
-	program       *Program
+	filePath       string
+	publicSymbols  map[string]Symbol // only public symbols from the checked program
+	privateSymbols map[string]bool   // declarations marked `private`, for diagnostics
+	program        *Program
+	references     []Reference // recorded with CheckOptions.RecordSpans
 }
 
 // Path returns the file path for this module
@@ -12,9 +16,36 @@ func (m *UserModule) Path() string {
 	return m.filePath
 }
 
-// Get returns a public symbol by name, or nil if not found or private
+// Get returns a public symbol by name, or nil if not found or private.
+// Names re-exported with `pub use` resolve through the declaring module.
 func (m *UserModule) Get(name string) Symbol {
-	return m.publicSymbols[name] // returns nil if not found
+	if sym, ok := m.publicSymbols[name]; ok {
+		return sym
+	}
+	if origin := m.Origin(name); origin != m {
+		return origin.Get(name)
+	}
+	return Symbol{}
+}
+
+// Origin returns the module that declares name: the module a `pub use`
+// re-exported it from, or m itself. Static members such as `Box::new`
+// follow their type.
+func (m *UserModule) Origin(name string) Module {
+	root, _, _ := strings.Cut(name, "::")
+	origin, ok := m.program.ReExports[root]
+	if !ok {
+		return m
+	}
+	if user, ok := origin.(*UserModule); ok {
+		return user.Origin(name)
+	}
+	return origin
+}
+
+// IsPrivate reports whether name is a declaration marked `private`.
+func (m *UserModule) IsPrivate(name string) bool {
+	return m.privateSymbols[name]
 }
 
 // Program returns the checked program for this module
@@ -30,52 +61,67 @@ func (m *UserModule) setFilePath(path string) {
 // NewUserModule creates a UserModule from a checked program, extracting only public symbols
 func NewUserModule(filePath string, program *Program, globalScope *SymbolTable) *UserModule {
 	publicSymbols := make(map[string]Symbol)
+	privateSymbols := make(map[string]bool)
 
 	// Extract public symbols from the global scope
 	for name, sym := range globalScope.symbols {
+		var private bool
 		switch s := sym.Type.(type) {
 		case *FunctionDef:
-			if !s.Private {
-				publicSymbols[name] = *sym
-			}
+			private = s.Private
 		case *StructDef:
-			if !s.Private {
-				publicSymbols[name] = *sym
-			}
+			private = s.Private
 		case *Trait:
-			if !s.private {
-				publicSymbols[name] = *sym
-			}
+			private = s.private
 		case *Enum:
-			if !s.Private {
-				publicSymbols[name] = *sym
-			}
+			private = s.Private
 		case *Union:
-			if !s.Private {
-				publicSymbols[name] = *sym
-			}
+			private = s.Private
+		default:
+			continue
+		}
+		if private {
+			privateSymbols[name] = true
+		} else {
+			publicSymbols[name] = *sym
 		}
 	}
 
 	// Extract public variables from program statements
-	// Immutable variables are public, mutable variables are private
+	// Immutable variables are public unless marked `private`, mutable
+	// variables are private
 	for _, stmt := range program.Statements {
 		if s, ok := stmt.Stmt.(*VariableDef); ok {
+			if s.Private {
+				privateSymbols[s.Name] = true
+				continue
+			}
 			if !s.Mutable { // Only immutable variables are public
 				// Create a symbol for the public variable
+				var constant Expression
+				if s.Const {
+					constant = s.Value
+				}
 				publicSymbols[s.Name] = Symbol{
-					Name:    s.Name,
-					Type:    s.__type,
-					mutable: s.Mutable,
+					Name:     s.Name,
+					Type:     s.__type,
+					mutable:  s.Mutable,
+					constant: constant,
 				}
 			}
 		}
 	}
 
+	// Names re-exported with `pub use` are public here too
+	for name, origin := range program.ReExports {
+		publicSymbols[name] = origin.Get(name)
+	}
+
 	return &UserModule{
-		filePath:      filePath,
-		publicSymbols: publicSymbols,
-		program:       program,
+		filePath:       filePath,
+		publicSymbols:  publicSymbols,
+		privateSymbols: privateSymbols,
+		program:        program,
 	}
 }
 
@@ -460,13 +460,13 @@ let x = c::make().secret()`
 		}
 		found := false
 		for _, diag := range diagnostics {
-			if strings.Contains(diag.Message, "Undefined:") && strings.Contains(diag.Message, ".secret") {
+			if diag.Code == checker.DiagnosticCodePrivateMember && diag.Message == "Private method: C.secret" {
 				found = true
 				break
 			}
 		}
 		if !found {
-			t.Fatalf("expected Undefined diagnostic for private method, got: %v", diagnostics)
+			t.Fatalf("expected private method diagnostic, got: %v", diagnostics)
 		}
 	})
 
@@ -537,18 +537,222 @@ fn main() Int {
 		t.Error("Expected error when accessing private function")
 	}
 
-	// Should contain "Undefined" error for the private function
 	found := false
 	for _, diag := range diagnostics {
-		if strings.Contains(diag.Message, "Undefined: utils::private_helper") {
+		if diag.Code == checker.DiagnosticCodePrivateMember && diag.Message == "Private: utils::private_helper" {
 			found = true
 			break
 		}
 	}
 	if !found {
-		t.Errorf("Expected 'Undefined: utils::private_helper' error, got: %v", diagnostics)
+		t.Errorf("Expected 'Private: utils::private_helper' error, got: %v", diagnostics)
 	}
 }
+func TestUserModulePrivateVariableAccessError(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "ard.toml"), []byte("name = \"test_project\"\nard = \">= 0.1.0\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	utilsContent := `private let secret = 42
+let shared = secret + 1
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "utils.ard"), []byte(utilsContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mainContent := `use test_project/utils
+fn main() {
+    let shared = utils::shared
+    let secret = utils::secret
+}`
+
+	result := parse.Parse([]byte(mainContent), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatal(result.Errors[0].Message)
+	}
+	resolver, err := checker.NewModuleResolver(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := checker.New("main.ard", result.Program, resolver)
+	c.Check()
+	diagnostics := c.Diagnostics()
+	if len(diagnostics) != 1 || diagnostics[0].Code != checker.DiagnosticCodePrivateMember || diagnostics[0].Message != "Private: utils::secret" {
+		t.Fatalf("Expected only 'Private: utils::secret', got: %v", diagnostics)
+	}
+}
+func TestUserModulePrivateFieldAccessError(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "ard.toml"), []byte("name = \"tmp_project\"\nard = \">= 0.1.0\""), 0644); err != nil {
+		t.Fatal(err)
+	}
+	moduleContent := `struct Account {
+  private balance: Int,
+  owner: Str,
+}
+
+fn open(owner: Str) Account {
+  Account{balance: 0, owner: owner}
+}
+
+impl Account {
+  fn balance() Int { self.balance }
+}`
+	if err := os.WriteFile(filepath.Join(tempDir, "bank.ard"), []byte(moduleContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+	resolver, err := checker.NewModuleResolver(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "public field and method",
+			input: "let a = bank::open(\"ada\")\nlet owner = a.owner\nlet total = a.balance()",
+		},
+		{
+			name:  "read private field",
+			input: "let a = bank::open(\"ada\")\nlet total = a.balance",
+			want:  "Private field: Account.balance",
+		},
+		{
+			name:  "assign private field",
+			input: "mut a = bank::open(\"ada\")\na.balance = 100",
+			want:  "Private field: Account.balance",
+		},
+		{
+			name:  "construct with private fields",
+			input: "let a = bank::Account{balance: 100, owner: \"ada\"}",
+			want:  "Cannot construct bank::Account outside its module",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parse.Parse([]byte("use tmp_project/bank\n\n"+tt.input), filepath.Join(tempDir, "main.ard"))
+			if len(result.Errors) > 0 {
+				t.Fatal(result.Errors[0].Message)
+			}
+			c := checker.New(filepath.Join(tempDir, "main.ard"), result.Program, resolver)
+			c.Check()
+			diagnostics := c.Diagnostics()
+			if tt.want == "" {
+				if len(diagnostics) > 0 {
+					t.Fatalf("unexpected diagnostics: %v", diagnostics)
+				}
+				return
+			}
+			if len(diagnostics) != 1 || diagnostics[0].Code != checker.DiagnosticCodePrivateMember || diagnostics[0].Message != tt.want {
+				t.Fatalf("expected %q, got: %v", tt.want, diagnostics)
+			}
+		})
+	}
+}
+
+func TestUserModuleReExports(t *testing.T) {
+	tempDir := t.TempDir()
+	files := map[string]string{
+		"ard.toml": "name = \"tmp_project\"\nard = \">= 0.1.0\"",
+		"shapes/box.ard": `struct Box {
+  size: Int,
+}
+
+fn Box::new(size: Int) Box {
+  Box{size: size}
+}
+
+fn area(b: Box) Int {
+  b.size * b.size
+}
+
+private fn helper() Int {
+  1
+}`,
+		"shapes.ard": `use tmp_project/shapes/box
+pub use tmp_project/shapes/box::Box
+pub use tmp_project/shapes/box::area
+
+fn unit() box::Box {
+  box::Box::new(1)
+}`,
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	resolver, err := checker.NewModuleResolver(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check := func(t *testing.T, path string, input string) *checker.Checker {
+		t.Helper()
+		result := parse.Parse([]byte(input), filepath.Join(tempDir, path))
+		if len(result.Errors) > 0 {
+			t.Fatal(result.Errors[0].Message)
+		}
+		c := checker.New(filepath.Join(tempDir, path), result.Program, resolver)
+		c.Check()
+		return c
+	}
+
+	t.Run("importers see re-exported symbols", func(t *testing.T) {
+		c := check(t, "main.ard", `use tmp_project/shapes
+
+fn main() Int {
+  let b: shapes::Box = shapes::Box::new(2)
+  shapes::area(b) + shapes::unit().size
+}`)
+		if c.HasErrors() {
+			t.Fatalf("unexpected diagnostics: %v", c.Diagnostics())
+		}
+		shapes := c.Module().Program().Imports["tmp_project/shapes"].(*checker.UserModule)
+		if shapes.Get("Box").IsZero() || shapes.Get("Box::new").IsZero() {
+			t.Fatalf("expected Box and Box::new through shapes, got %v", shapes.Symbols())
+		}
+		if origin := shapes.Origin("Box::new").Path(); origin != "tmp_project/shapes/box" {
+			t.Fatalf("Box::new origin = %q", origin)
+		}
+		if origin := shapes.Origin("unit").Path(); origin != "tmp_project/shapes" {
+			t.Fatalf("unit origin = %q", origin)
+		}
+	})
+
+	t.Run("re-export does not bind the module name", func(t *testing.T) {
+		c := check(t, "other.ard", `pub use tmp_project/shapes/box::Box
+
+fn make() Int {
+  box::area(Box::new(1))
+}`)
+		if !c.HasErrors() || c.Diagnostics()[0].Message != "Undefined module: box" {
+			t.Fatalf("expected undefined module box, got: %v", c.Diagnostics())
+		}
+	})
+
+	t.Run("private symbols cannot be re-exported", func(t *testing.T) {
+		c := check(t, "other.ard", "pub use tmp_project/shapes/box::helper\n")
+		diagnostics := c.Diagnostics()
+		if len(diagnostics) != 1 || diagnostics[0].Message != "Private: tmp_project/shapes/box::helper" {
+			t.Fatalf("expected private re-export error, got: %v", diagnostics)
+		}
+	})
+
+	t.Run("re-exports conflict with declarations", func(t *testing.T) {
+		c := check(t, "other.ard", "pub use tmp_project/shapes/box::Box\n\nstruct Box {}\n")
+		diagnostics := c.Diagnostics()
+		if len(diagnostics) == 0 || diagnostics[0].Code != checker.DiagnosticCodeDuplicateDeclaration {
+			t.Fatalf("expected duplicate declaration, got: %v", diagnostics)
+		}
+	})
+}
+
 func TestUserModulePrivateUnionAccessError(t *testing.T) {
 	tempDir := t.TempDir()
 	if err := os.WriteFile(filepath.Join(tempDir, "ard.toml"), []byte("name = \"test_project\"\nard = \">= 0.1.0\"\n"), 0o644); err != nil {
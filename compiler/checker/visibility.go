@@ -0,0 +1,175 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// fieldOwner returns the struct declaring the fields of subject, looking
+// through mutable references and bound type variables.
+func fieldOwner(subject Type) *StructDef {
+	typ := deref(subject)
+	if ref, ok := typ.(*MutableRef); ok {
+		typ = deref(ref.Of())
+	}
+	def, _ := typ.(*StructDef)
+	return def
+}
+
+// checkFieldVisible reports a diagnostic and returns false when field is a
+// private field of a struct declared in another module.
+func (c *Checker) checkFieldVisible(subject Type, field string, location parse.Location) bool {
+	def := fieldOwner(subject)
+	if def == nil || !def.PrivateFields[field] || def.ModulePath == c.typeOwnerPath() {
+		return true
+	}
+	c.addDiagnostic(privateMemberDiagnostic{
+		Kind:   privateField,
+		Name:   fmt.Sprintf("%s.%s", def.Name, field),
+		Module: def.ModulePath,
+		Span:   c.sourceSpan(location),
+	}.build())
+	return false
+}
+
+// checkStructLiteralVisible reports a diagnostic and returns false when def
+// has private fields and is declared in another module. Such structs can only
+// be built through functions their module exports.
+func (c *Checker) checkStructLiteralVisible(def *StructDef, name string, location parse.Location) bool {
+	if def == nil || len(def.PrivateFields) == 0 || def.ModulePath == c.typeOwnerPath() {
+		return true
+	}
+	c.addDiagnostic(privateMemberDiagnostic{
+		Kind:   privateFieldsLiteral,
+		Name:   name,
+		Module: def.ModulePath,
+		Span:   c.sourceSpan(location),
+	}.build())
+	return false
+}
+
+// checkPrivateMethod reports a private method of a struct declared in another
+// module. It returns true when a diagnostic was added, so callers can skip
+// their generic "undefined method" error.
+func (c *Checker) checkPrivateMethod(subject Type, method string, location parse.Location) bool {
+	def := fieldOwner(subject)
+	if def == nil {
+		return false
+	}
+	owner := StructMethodOwner(def)
+	fn, ok := StructMethodInModules(c.program.Imports, owner, method)
+	if !ok || c.canAccessStructMethod(owner, fn) {
+		return false
+	}
+	c.addDiagnostic(privateMemberDiagnostic{
+		Kind:   privateMethod,
+		Name:   fmt.Sprintf("%s.%s", def.Name, method),
+		Module: def.ModulePath,
+		Span:   c.sourceSpan(location),
+	}.build())
+	return true
+}
+
+// checkPrivateModuleMember reports a reference to a private declaration of
+// an imported module. It returns true when a diagnostic was added, so callers
+// can skip their generic "undefined" error.
+func (c *Checker) checkPrivateModuleMember(mod Module, member string, qualified string, location parse.Location) bool {
+	user, ok := mod.(*UserModule)
+	if !ok || !user.IsPrivate(member) {
+		return false
+	}
+	c.addDiagnostic(privateMemberDiagnostic{
+		Kind:   privateDeclaration,
+		Name:   qualified,
+		Module: mod.Path(),
+		Span:   c.sourceSpan(location),
+	}.build())
+	return true
+}
+
+// addUndefinedModuleMember reports member as private when mod declares it
+// `private`, and as undefined otherwise.
+func (c *Checker) addUndefinedModuleMember(mod Module, member string, qualified string, location parse.Location) {
+	if !c.checkPrivateModuleMember(mod, member, qualified, location) {
+		c.addUnresolvedReference(undefinedQualifiedMember, qualified, location)
+	}
+}
+
+// memberOrigin returns the module that declares member of mod, following
+// `pub use` re-exports so checked nodes name the module that owns the code.
+func memberOrigin(mod Module, member string) Module {
+	if user, ok := mod.(*UserModule); ok {
+		return user.Origin(member)
+	}
+	return mod
+}
+
+// checkReExports loads the modules named by `pub use path::Name` statements
+// and records each Name as part of this module's public API. A re-exported
+// name may not collide with another re-export or a top-level declaration.
+func (c *Checker) checkReExports(imports []parse.Import) {
+	if len(imports) == 0 {
+		return
+	}
+	declared := map[string]parse.Location{}
+	for _, stmt := range c.input.Statements {
+		if name, loc, ok := topLevelDeclarationName(stmt); ok {
+			if _, dup := declared[name]; !dup {
+				declared[name] = loc
+			}
+		}
+	}
+
+	seen := map[string]parse.Location{}
+	for _, imp := range imports {
+		if original, dup := seen[imp.ReExport]; dup {
+			c.addDiagnostic(duplicateDeclarationDiagnostic{
+				Name:          imp.ReExport,
+				DuplicateSpan: c.sourceSpan(imp.GetLocation()),
+				OriginalSpan:  c.sourceSpan(original),
+			}.build())
+			continue
+		}
+		seen[imp.ReExport] = imp.GetLocation()
+		if loc, dup := declared[imp.ReExport]; dup {
+			c.addDiagnostic(duplicateDeclarationDiagnostic{
+				Name:          imp.ReExport,
+				DuplicateSpan: c.sourceSpan(loc),
+				OriginalSpan:  c.sourceSpan(imp.GetLocation()),
+			}.build())
+			continue
+		}
+
+		mod := c.loadImport(imp)
+		if mod == nil {
+			continue
+		}
+		qualified := imp.Path + "::" + imp.ReExport
+		if mod.Get(imp.ReExport).IsZero() {
+			c.addUndefinedModuleMember(mod, imp.ReExport, qualified, imp.GetLocation())
+			continue
+		}
+		// Key the module by the qualified path so it is reachable for lowering
+		// without binding a name importers of this module could refer to.
+		c.program.Imports[qualified] = mod
+		if c.program.ReExports == nil {
+			c.program.ReExports = map[string]Module{}
+		}
+		c.program.ReExports[imp.ReExport] = mod
+	}
+}
+
+// topLevelDeclarationName returns the name a top-level statement declares.
+func topLevelDeclarationName(stmt parse.Statement) (string, parse.Location, bool) {
+	switch s := stmt.(type) {
+	case *parse.FunctionDeclaration:
+		return s.Name, s.NameLocation, true
+	case *parse.StaticFunctionDeclaration:
+		return s.Path.String(), s.NameLocation, true
+	case *parse.VariableDeclaration:
+		return s.Name, s.NameLocation, true
+	default:
+		return topLevelTypeDeclarationName(stmt)
+	}
+}
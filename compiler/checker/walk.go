@@ -0,0 +1,429 @@
+package checker
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+)
+
+// Node is a node of a checked program: an Expression (including *Block and
+// *FunctionDef) or a NonProducing statement such as *VariableDef or
+// *WhileLoop. Every Expression reports its resolved type through Type.
+type Node any
+
+// A Visitor's Visit method is invoked for each node encountered by Walk. If
+// the result visitor w is not nil, Walk visits each of the children of node
+// with w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses a checked program in depth-first order, like ast.Walk. It
+// starts by calling v.Visit(node); node must not be nil. Children are visited
+// in source order where the checker keeps it; the cases of a match on
+// integers or strings and the fields of a struct literal are visited in
+// sorted order.
+//
+// Walk does not descend into declarations that a node only refers to, such
+// as the function a call resolves to or the struct an instance is built from.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Block:
+		walkStatements(v, n.Stmts)
+	case *UnsafeBlock:
+		walkBlock(v, n.Body)
+	case *FunctionDef:
+		walkBlock(v, n.Body)
+
+	// statements
+	case *VariableDef:
+		walkExpr(v, n.Value)
+	case *Reassignment:
+		walkExpr(v, n.Target)
+		walkExpr(v, n.Value)
+	case *Assert:
+		walkStatements(v, n.Body)
+	case *Defer:
+		walkExpr(v, n.Expr)
+		walkBlock(v, n.Body)
+	case *ForIntRange:
+		walkExpr(v, n.Start)
+		walkExpr(v, n.End)
+		walkExpr(v, n.Step)
+		walkBlock(v, n.Body)
+	case *ForInStr:
+		walkExpr(v, n.Value)
+		walkBlock(v, n.Body)
+	case *ForInList:
+		walkExpr(v, n.List)
+		walkBlock(v, n.Body)
+	case *ForInMap:
+		walkExpr(v, n.Map)
+		walkBlock(v, n.Body)
+	case *ForLoop:
+		if n.Init != nil {
+			Walk(v, n.Init)
+		}
+		walkExpr(v, n.Condition)
+		if n.Update != nil {
+			Walk(v, n.Update)
+		}
+		walkBlock(v, n.Body)
+	case *WhileLoop:
+		walkExpr(v, n.Condition)
+		walkBlock(v, n.Body)
+
+	// collections and strings
+	case *TemplateStr:
+		walkExprs(v, n.Chunks)
+	case *StrFormat:
+		walkExpr(v, n.Template)
+		walkExprs(v, n.Args)
+		for _, arg := range n.Named {
+			walkExpr(v, arg.Value)
+		}
+	case *InspectValue:
+		walkExpr(v, n.Value)
+	case *ListLiteral:
+		walkExprs(v, n.Elements)
+	case *MapLiteral:
+		for i := range n.Keys {
+			walkExpr(v, n.Keys[i])
+			if i < len(n.Values) {
+				walkExpr(v, n.Values[i])
+			}
+		}
+	case *StructInstance:
+		walkFields(v, n.Fields)
+		walkExpr(v, n.Base)
+	case *ModuleStructInstance:
+		if n.Property != nil {
+			Walk(v, n.Property)
+		}
+	case *ForeignStructInstance:
+		walkFields(v, n.Fields)
+	case *EnumVariant:
+		walkExprs(v, n.Args)
+
+	// member access and conversions
+	case *InstanceProperty:
+		walkExpr(v, n.Subject)
+	case *ForeignFieldAccess:
+		walkExpr(v, n.Subject)
+	case *MutableRefExpr:
+		walkExpr(v, n.Operand)
+	case *ForeignScalarConvert:
+		walkExpr(v, n.Value)
+	case *ScalarFrom:
+		walkExpr(v, n.Value)
+	case *NumberParse:
+		walkExpr(v, n.Text)
+	case *EnumFromInt:
+		walkExpr(v, n.Value)
+	case *ForeignInterfaceUpcast:
+		walkExpr(v, n.Value)
+	case *DiscardingFunctionCoercion:
+		walkExpr(v, n.Value)
+	case *UnsafeCast:
+		walkExpr(v, n.Value)
+	case *UnsafeIsNil:
+		walkExpr(v, n.Value)
+	case *BoundMethod:
+		walkExpr(v, n.Receiver)
+	case *ForeignMethodValue:
+		walkExpr(v, n.Subject)
+
+	// calls
+	case *FunctionCall:
+		walkExprs(v, n.Args)
+	case *FunctionValueCall:
+		walkExpr(v, n.Callee)
+		walkExprs(v, n.Args)
+	case *ModuleFunctionCall:
+		walkCall(v, n.Call)
+	case *ForeignFunctionCall:
+		walkCall(v, n.Call)
+	case *ForeignMethodCall:
+		walkExpr(v, n.Subject)
+		walkCall(v, n.Call)
+	case *InstanceMethod:
+		walkExpr(v, n.Subject)
+		walkCall(v, n.Method)
+	case *StrMethod:
+		walkExpr(v, n.Subject)
+		walkExprs(v, n.Args)
+	case *IntMethod:
+		walkExpr(v, n.Subject)
+		walkExprs(v, n.Args)
+	case *ListMethod:
+		walkExpr(v, n.Subject)
+		walkExprs(v, n.Args)
+	case *MapMethod:
+		walkExpr(v, n.Subject)
+		walkExprs(v, n.Args)
+	case *MaybeMethod:
+		walkExpr(v, n.Subject)
+		walkExprs(v, n.Args)
+	case *ResultMethod:
+		walkExpr(v, n.Subject)
+		walkExprs(v, n.Args)
+	case *ByteMethod:
+		walkExpr(v, n.Subject)
+	case *RuneMethod:
+		walkExpr(v, n.Subject)
+	case *FloatMethod:
+		walkExpr(v, n.Subject)
+	case *BoolMethod:
+		walkExpr(v, n.Subject)
+	case *EnumMethod:
+		walkExpr(v, n.Subject)
+	case *Panic:
+		walkExpr(v, n.Message)
+	case *TryOp:
+		walkExpr(v, n.expr)
+		walkBlock(v, n.CatchBlock)
+
+	// operators
+	case *Negation:
+		walkExpr(v, n.Value)
+	case *Not:
+		walkExpr(v, n.Value)
+	case *IntAddition:
+		walkBinary(v, n.Left, n.Right)
+	case *IntSubtraction:
+		walkBinary(v, n.Left, n.Right)
+	case *IntMultiplication:
+		walkBinary(v, n.Left, n.Right)
+	case *IntDivision:
+		walkBinary(v, n.Left, n.Right)
+	case *IntModulo:
+		walkBinary(v, n.Left, n.Right)
+	case *IntGreater:
+		walkBinary(v, n.Left, n.Right)
+	case *IntGreaterEqual:
+		walkBinary(v, n.Left, n.Right)
+	case *IntLess:
+		walkBinary(v, n.Left, n.Right)
+	case *IntLessEqual:
+		walkBinary(v, n.Left, n.Right)
+	case *FloatAddition:
+		walkBinary(v, n.Left, n.Right)
+	case *FloatSubtraction:
+		walkBinary(v, n.Left, n.Right)
+	case *FloatMultiplication:
+		walkBinary(v, n.Left, n.Right)
+	case *FloatDivision:
+		walkBinary(v, n.Left, n.Right)
+	case *FloatGreater:
+		walkBinary(v, n.Left, n.Right)
+	case *FloatGreaterEqual:
+		walkBinary(v, n.Left, n.Right)
+	case *FloatLess:
+		walkBinary(v, n.Left, n.Right)
+	case *FloatLessEqual:
+		walkBinary(v, n.Left, n.Right)
+	case *StrAddition:
+		walkBinary(v, n.Left, n.Right)
+	case *Equality:
+		walkBinary(v, n.Left, n.Right)
+	case *Inequality:
+		walkBinary(v, n.Left, n.Right)
+	case *And:
+		walkBinary(v, n.Left, n.Right)
+	case *Or:
+		walkBinary(v, n.Left, n.Right)
+
+	// control flow
+	case *If:
+		for _, branch := range n.Branches {
+			walkExpr(v, branch.Condition)
+			walkBlock(v, branch.Body)
+		}
+		walkBlock(v, n.Else)
+	case *BoolMatch:
+		walkExpr(v, n.Subject)
+		walkBlock(v, n.True)
+		walkBlock(v, n.False)
+	case *OptionMatch:
+		walkExpr(v, n.Subject)
+		walkMatch(v, n.Some)
+		walkBlock(v, n.None)
+	case *ResultMatch:
+		walkExpr(v, n.Subject)
+		walkMatch(v, n.Ok)
+		walkMatch(v, n.Err)
+	case *EnumMatch:
+		walkExpr(v, n.Subject)
+		walkArmBlocks(v, n.Cases)
+		walkBlock(v, n.CatchAll)
+	case *IntMatch:
+		walkExpr(v, n.Subject)
+		values := slices.Sorted(maps.Keys(n.IntCases))
+		blocks := make([]*Block, len(values))
+		for i, value := range values {
+			blocks[i] = n.IntCases[value]
+		}
+		walkArmBlocks(v, blocks)
+		ranges := slices.SortedFunc(maps.Keys(n.RangeCases), func(a, b IntRange) int {
+			return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.End, b.End))
+		})
+		for _, r := range ranges {
+			walkBlock(v, n.RangeCases[r])
+		}
+		walkBlock(v, n.CatchAll)
+	case *StrMatch:
+		walkExpr(v, n.Subject)
+		values := slices.Sorted(maps.Keys(n.Cases))
+		blocks := make([]*Block, len(values))
+		for i, value := range values {
+			blocks[i] = n.Cases[value]
+		}
+		walkArmBlocks(v, blocks)
+		walkBlock(v, n.CatchAll)
+	case *UnionMatch:
+		walkExpr(v, n.Subject)
+		for _, name := range slices.Sorted(maps.Keys(n.TypeCases)) {
+			walkMatch(v, n.TypeCases[name])
+		}
+		walkBlock(v, n.CatchAll)
+	case *ForeignTypeMatch:
+		walkExpr(v, n.Subject)
+		for _, c := range n.Cases {
+			walkBlock(v, c.Body)
+		}
+		walkBlock(v, n.CatchAll)
+	case *ConditionalMatch:
+		for _, c := range n.Cases {
+			walkExpr(v, c.Condition)
+			walkBlock(v, c.Body)
+		}
+		walkBlock(v, n.CatchAll)
+	case *Select:
+		for _, arm := range n.Arms {
+			walkExpr(v, arm.Channel)
+			walkExpr(v, arm.Value)
+			walkBlock(v, arm.Body)
+		}
+	}
+
+	v.Visit(nil)
+}
+
+// WalkProgram walks each top-level statement of program, then the methods
+// declared for its structs and enums, ordered by type and method name.
+func WalkProgram(v Visitor, program *Program) {
+	walkStatements(v, program.Statements)
+
+	owners := slices.SortedFunc(maps.Keys(program.StructMethods), func(a, b MethodOwner) int {
+		return cmp.Or(cmp.Compare(a.ModulePath, b.ModulePath), cmp.Compare(a.TypeName, b.TypeName))
+	})
+	for _, owner := range owners {
+		walkMethods(v, program.StructMethods[owner])
+	}
+	seen := map[*Enum]bool{}
+	for _, stmt := range program.Statements {
+		if enum, ok := stmt.Stmt.(*Enum); ok && !seen[enum] {
+			seen[enum] = true
+			walkMethods(v, enum.Methods)
+		}
+	}
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses node like Walk, calling f(node) for each node. If f
+// returns true, Inspect visits the node's children, followed by a call of
+// f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+func walkStatements(v Visitor, stmts []Statement) {
+	for _, stmt := range stmts {
+		switch {
+		case stmt.Expr != nil:
+			Walk(v, stmt.Expr)
+		case stmt.Stmt != nil:
+			Walk(v, stmt.Stmt)
+		}
+	}
+}
+
+func walkMethods(v Visitor, methods map[string]*FunctionDef) {
+	for _, name := range slices.Sorted(maps.Keys(methods)) {
+		Walk(v, methods[name])
+	}
+}
+
+func walkFields(v Visitor, fields map[string]Expression) {
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		walkExpr(v, fields[name])
+	}
+}
+
+func walkExprs(v Visitor, exprs []Expression) {
+	for _, expr := range exprs {
+		walkExpr(v, expr)
+	}
+}
+
+func walkBinary(v Visitor, left, right Expression) {
+	walkExpr(v, left)
+	walkExpr(v, right)
+}
+
+// walkExpr, walkBlock, walkCall, and walkMatch skip absent children, such as
+// the else block of an if without one.
+func walkExpr(v Visitor, expr Expression) {
+	switch e := expr.(type) {
+	case nil:
+	case *Block:
+		walkBlock(v, e)
+	case *FunctionCall:
+		walkCall(v, e)
+	default:
+		Walk(v, expr)
+	}
+}
+
+func walkBlock(v Visitor, block *Block) {
+	if block != nil {
+		Walk(v, block)
+	}
+}
+
+// walkArmBlocks walks the bodies of match arms in order, visiting a body that
+// several or-pattern values share only once.
+func walkArmBlocks(v Visitor, blocks []*Block) {
+	seen := map[*Block]bool{}
+	for _, block := range blocks {
+		if block == nil || seen[block] {
+			continue
+		}
+		seen[block] = true
+		walkBlock(v, block)
+	}
+}
+
+func walkCall(v Visitor, call *FunctionCall) {
+	if call != nil {
+		Walk(v, call)
+	}
+}
+
+func walkMatch(v Visitor, match *Match) {
+	if match != nil {
+		walkBlock(v, match.Body)
+	}
+}
@@ -0,0 +1,184 @@
+package checker_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+func checkedProgram(t *testing.T, source string) *checker.Program {
+	t.Helper()
+	result := parse.Parse([]byte(source), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	c := checker.New("main.ard", result.Program, nil)
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("diagnostics: %v", c.Diagnostics())
+	}
+	return c.Module().Program()
+}
+
+// recorder logs each visited node by type and checks that every node's
+// children are followed by exactly one Visit(nil).
+type recorder struct {
+	nodes []string
+	depth int
+	skip  string
+}
+
+func (r *recorder) Visit(node checker.Node) checker.Visitor {
+	if node == nil {
+		r.depth--
+		return nil
+	}
+	name := strings.TrimPrefix(fmt.Sprintf("%T", node), "*checker.")
+	if expr, ok := node.(checker.Expression); ok {
+		name += ":" + expr.Type().String()
+	}
+	r.nodes = append(r.nodes, strings.Repeat("  ", r.depth)+name)
+	if name == r.skip {
+		return nil
+	}
+	r.depth++
+	return r
+}
+
+func TestWalkVisitsNodesInSourceOrderWithTypes(t *testing.T) {
+	program := checkedProgram(t, `fn pick(flag: Bool, n: Int) Int {
+  let doubled = n * 2
+  match flag {
+    true => doubled + 1,
+    false => 0,
+  }
+}
+`)
+	r := &recorder{}
+	checker.WalkProgram(r, program)
+	want := []string{
+		"FunctionDef:fn(Bool, Int) Int",
+		"  Block:Int",
+		"    VariableDef:Int",
+		"      IntMultiplication:Int",
+		"        Variable:Int",
+		"        IntLiteral:Int",
+		"    BoolMatch:Int",
+		"      Variable:Bool",
+		"      Block:Int",
+		"        IntAddition:Int",
+		"          Variable:Int",
+		"          IntLiteral:Int",
+		"      Block:Int",
+		"        IntLiteral:Int",
+	}
+	if got := strings.Join(r.nodes, "\n"); got != strings.Join(want, "\n") {
+		t.Fatalf("visited:\n%s\n\nwant:\n%s", got, strings.Join(want, "\n"))
+	}
+	if r.depth != 0 {
+		t.Fatalf("depth = %d after walk, want every node closed by Visit(nil)", r.depth)
+	}
+}
+
+func TestWalkSkipsChildrenWhenVisitReturnsNil(t *testing.T) {
+	program := checkedProgram(t, "fn f() Int {\n  1 + 2\n}\n\nlet x = 3\n")
+	r := &recorder{skip: "FunctionDef:fn() Int"}
+	checker.WalkProgram(r, program)
+	want := "FunctionDef:fn() Int\nVariableDef:Int\n  IntLiteral:Int"
+	if got := strings.Join(r.nodes, "\n"); got != want {
+		t.Fatalf("visited:\n%s\n\nwant:\n%s", got, want)
+	}
+}
+
+func TestWalkProgramIncludesMethods(t *testing.T) {
+	program := checkedProgram(t, `struct Counter {
+  n: Int,
+}
+
+impl Counter {
+  fn next() Int {
+    self.n + 1
+  }
+}
+
+enum Light {
+  Red,
+  Green,
+}
+
+impl Light {
+  fn is_red() Bool {
+    self == Light::Red
+  }
+}
+`)
+	var calls []string
+	checker.WalkProgram(&inspectFuncs{on: func(node checker.Node) {
+		if fn, ok := node.(*checker.FunctionDef); ok {
+			calls = append(calls, fn.Name)
+		}
+	}}, program)
+	if got := strings.Join(calls, ","); got != "next,is_red" {
+		t.Fatalf("methods = %s, want next,is_red", got)
+	}
+
+	visits := 0
+	checker.Inspect(program.Statements[0].Stmt, func(node checker.Node) bool {
+		visits++
+		return true
+	})
+	if visits != 2 {
+		t.Fatalf("Inspect made %d calls for a struct declaration, want the node and a nil", visits)
+	}
+}
+
+type inspectFuncs struct{ on func(checker.Node) }
+
+func (f *inspectFuncs) Visit(node checker.Node) checker.Visitor {
+	if node != nil {
+		f.on(node)
+	}
+	return f
+}
+
+func TestWalkHandlesEverySample(t *testing.T) {
+	root, err := filepath.Abs("../samples")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := filepath.Glob(filepath.Join(root, "*.ard"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolver, err := checker.NewModuleResolver(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range paths {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			source, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result := parse.Parse(source, path)
+			if len(result.Errors) > 0 {
+				t.Skip("sample does not parse")
+			}
+			c := checker.New(filepath.Base(path), result.Program, resolver)
+			c.Check()
+			if c.HasErrors() {
+				t.Skip("sample does not check without its Go dependencies")
+			}
+			nodes := 0
+			checker.WalkProgram(&inspectFuncs{on: func(checker.Node) { nodes++ }}, c.Module().Program())
+			if nodes == 0 {
+				t.Fatal("walked no nodes")
+			}
+		})
+	}
+}
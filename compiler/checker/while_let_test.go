@@ -0,0 +1,64 @@
+package checker_test
+
+import (
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+)
+
+func TestWhileLet(t *testing.T) {
+	run(t, []test{
+		{
+			name: "while let unwraps a Maybe or a Result on each iteration",
+			input: `struct Queue { items: [Int], next: Int }
+
+impl Queue {
+  fn mut pop() Int? {
+    let item = self.items.at(self.next)
+    self.next =+ 1
+    item
+  }
+}
+
+fn parse(s: Str) Int!Str {
+  Result::ok(1)
+}
+
+fn main() {
+  mut queue = Queue{items: [1, 2], next: 0}
+  mut total = 0
+  while let item = queue.pop() {
+    total =+ item
+  }
+  while let parsed = parse("1") {
+    let doubled: Int = parsed * 2
+    break
+  }
+}`,
+		},
+		{
+			name: "the binding is only in scope in the body",
+			input: `fn main() {
+  let maybe: Int? = Maybe::new()
+  while let found = maybe {
+    break
+  }
+  found
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "Undefined variable: found"},
+			},
+		},
+		{
+			name: "while let needs a Maybe or Result",
+			input: `fn main() {
+  while let found = 1 {
+    break
+  }
+}`,
+			diagnostics: []checker.Diagnostic{
+				{Kind: checker.Error, Message: "while let expects a Maybe or Result, got Int"},
+			},
+		},
+	})
+}
@@ -0,0 +1,361 @@
+package diagnostics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/akonwi/ard/checker"
+)
+
+// Explanation is the extended description of a diagnostic code printed by
+// `ard explain`. Example is a program that produces the diagnostic and Fixed
+// is the same program with the problem resolved.
+type Explanation struct {
+	Code        checker.DiagnosticCode
+	Title       string
+	Description string
+	Example     string
+	Fixed       string
+}
+
+var explanations = []Explanation{
+	{
+		Code:  checker.DiagnosticCodeTypeMismatch,
+		Title: "Type mismatch",
+		Description: `A value was used where a different type is required, such as assigning a Str
+to an Int variable or returning the wrong type from a function. Ard never
+converts between types implicitly; convert the value explicitly or change the
+annotation that requires the other type.`,
+		Example: `fn count() Int {
+  "3"
+}
+`,
+		Fixed: `fn count() Int {
+  3
+}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeUndefinedName,
+		Title: "Undefined name",
+		Description: `A name was used that is not declared in the current scope. Check the spelling,
+declare the variable or function before using it, or qualify a name that
+comes from an imported module with the module's name, as in ` + "`shapes::area`" + `.`,
+		Example: `let total = 10
+let doubled = totl * 2
+`,
+		Fixed: `let total = 10
+let doubled = total * 2
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeUndefinedMember,
+		Title: "Undefined member",
+		Description: `A field or method was accessed on a value whose type has no member by that
+name. Check the spelling against the type's declaration, or add the method in
+an impl block.`,
+		Example: `struct User {
+  name: Str,
+}
+
+let user = User{name: "Ada"}
+let n = user.nme
+`,
+		Fixed: `struct User {
+  name: Str,
+}
+
+let user = User{name: "Ada"}
+let n = user.name
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeUnknownField,
+		Title: "Unknown field",
+		Description: `A struct literal sets a field that the struct does not declare. Remove the
+field, fix its spelling, or add it to the struct.`,
+		Example: `struct Point {
+  x: Int,
+  y: Int,
+}
+
+let p = Point{x: 1, y: 2, z: 3}
+`,
+		Fixed: `struct Point {
+  x: Int,
+  y: Int,
+}
+
+let p = Point{x: 1, y: 2}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeMissingStructFields,
+		Title: "Missing struct fields",
+		Description: `A struct literal must set every field that has no default value. Set the
+missing fields, or give them a default in the struct declaration.`,
+		Example: `struct Point {
+  x: Int,
+  y: Int,
+}
+
+let p = Point{x: 1}
+`,
+		Fixed: `struct Point {
+  x: Int,
+  y: Int,
+}
+
+let p = Point{x: 1, y: 0}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeNonExhaustiveMatch,
+		Title: "Non-exhaustive match",
+		Description: `A match must handle every possible value of its subject: every variant of an
+enum, both true and false, both ok and err of a Result. Add an arm for each
+missing case, or a catch-all ` + "`_`" + ` arm when the remaining cases share a result.`,
+		Example: `enum Light {
+  Red,
+  Yellow,
+  Green,
+}
+
+fn next(light: Light) Light {
+  match light {
+    Light::Red => Light::Green,
+    Light::Green => Light::Yellow,
+  }
+}
+`,
+		Fixed: `enum Light {
+  Red,
+  Yellow,
+  Green,
+}
+
+fn next(light: Light) Light {
+  match light {
+    Light::Red => Light::Green,
+    Light::Green => Light::Yellow,
+    Light::Yellow => Light::Red,
+  }
+}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeImmutableAssignment,
+		Title: "Immutable assignment",
+		Description: `A variable declared with ` + "`let`" + ` cannot be reassigned. Declare it with ` + "`mut`" + `
+if it needs to change, or bind the new value to a new name.`,
+		Example: `let count = 0
+count = 1
+`,
+		Fixed: `mut count = 0
+count = 1
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeIncorrectArgumentCount,
+		Title: "Incorrect argument count",
+		Description: `A function was called with more arguments than it declares parameters.
+Remove the extra arguments or add the parameters to the function.`,
+		Example: `fn add(a: Int, b: Int) Int {
+  a + b
+}
+
+let sum = add(1, 2, 3)
+`,
+		Fixed: `fn add(a: Int, b: Int) Int {
+  a + b
+}
+
+let sum = add(1, 2)
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeMissingArgument,
+		Title: "Missing argument",
+		Description: `A function was called without a value for one of its required parameters.
+Pass the missing argument. Only parameters with a Maybe type may be omitted.`,
+		Example: `fn greet(greeting: Str, name: Str) Str {
+  "{greeting}, {name}"
+}
+
+let message = greet("Hello")
+`,
+		Fixed: `fn greet(greeting: Str, name: Str) Str {
+  "{greeting}, {name}"
+}
+
+let message = greet("Hello", "Ada")
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeDuplicateDeclaration,
+		Title: "Duplicate declaration",
+		Description: `Two types in the same module share a name, so a reference to it would be
+ambiguous. Rename or remove one of them.`,
+		Example: `struct User {
+  name: Str,
+}
+
+enum User {
+  Guest,
+  Admin,
+}
+`,
+		Fixed: `struct User {
+  name: Str,
+}
+
+enum Role {
+  Guest,
+  Admin,
+}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeUndefinedType,
+		Title: "Undefined type",
+		Description: `A type annotation names a type that is not declared or imported. Check the
+spelling, declare the type, or import the module that declares it.`,
+		Example: `fn label(n: Integer) Str {
+  "number"
+}
+`,
+		Fixed: `fn label(n: Int) Str {
+  "number"
+}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeNotCallable,
+		Title: "Not callable",
+		Description: `A value that is not a function was called. Check that the name refers to a
+function and not a variable that shadows it.`,
+		Example: `let limit = 10
+let value = limit()
+`,
+		Fixed: `let limit = 10
+let value = limit
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeNonBooleanIfCondition,
+		Title: "Non-boolean if condition",
+		Description: `An if condition must be a Bool. Ard has no truthy values, so compare the value
+explicitly.`,
+		Example: `let count = 3
+if count {
+  panic("not empty")
+}
+`,
+		Fixed: `let count = 3
+if count > 0 {
+  panic("not empty")
+}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeUnusedVariable,
+		Title: "Unused variable",
+		Description: `Reported by ` + "`ard check --strict`" + ` for a variable that is never read.
+Assigning to a variable does not count as reading it. Remove the variable, or
+prefix its name with ` + "`_`" + ` to keep it without a warning.`,
+		Example: `fn main() {
+  let answer = 42
+}
+`,
+		Fixed: `fn main() {
+  let _answer = 42
+}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeShadowedVariable,
+		Title: "Shadowed variable",
+		Description: `Reported by ` + "`ard check --strict`" + ` for a ` + "`let`" + ` or ` + "`mut`" + ` binding that reuses
+the name of a binding from an enclosing block. The outer value is hidden until
+the inner block ends. Declaring the same name again in the same block rebinds
+it and is not reported.`,
+		Example: `fn main() Int {
+  let total = 1
+  if total > 0 {
+    let total = 2
+    total
+  } else {
+    total
+  }
+}
+`,
+		Fixed: `fn main() Int {
+  let total = 1
+  if total > 0 {
+    let doubled = 2
+    doubled
+  } else {
+    total
+  }
+}
+`,
+	},
+	{
+		Code:  checker.DiagnosticCodeUnusedFunction,
+		Title: "Unused function",
+		Description: `Reported by ` + "`ard check --strict`" + ` for a private function that nothing
+calls. Remove it, or call it.`,
+		Example: `private fn helper() Int {
+  1
+}
+
+fn main() {}
+`,
+		Fixed: `private fn helper() Int {
+  1
+}
+
+fn main() {
+  helper()
+}
+`,
+	},
+}
+
+// Explain returns the extended description of a diagnostic code.
+func Explain(code string) (Explanation, bool) {
+	for _, explanation := range explanations {
+		if string(explanation.Code) == code {
+			return explanation, true
+		}
+	}
+	return Explanation{}, false
+}
+
+// Explanations returns every diagnostic code with an extended description, in
+// the order `ard explain` lists them.
+func Explanations() []Explanation {
+	return explanations
+}
+
+// WriteExplanation prints an explanation for the terminal.
+func WriteExplanation(w io.Writer, explanation Explanation) error {
+	var out strings.Builder
+	fmt.Fprintf(&out, "%s: %s\n\n%s\n", explanation.Code, explanation.Title, explanation.Description)
+	fmt.Fprintf(&out, "\nExample:\n\n%s", indentBlock(explanation.Example))
+	fmt.Fprintf(&out, "\nFixed:\n\n%s", indentBlock(explanation.Fixed))
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+func indentBlock(source string) string {
+	var out strings.Builder
+	for _, line := range strings.SplitAfter(source, "\n") {
+		if strings.TrimSpace(line) != "" {
+			out.WriteString("    ")
+		}
+		out.WriteString(line)
+	}
+	return out.String()
+}
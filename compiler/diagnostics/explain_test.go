@@ -0,0 +1,65 @@
+package diagnostics_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/diagnostics"
+	"github.com/akonwi/ard/parse"
+)
+
+func strictDiagnostics(t *testing.T, source string) []checker.Diagnostic {
+	t.Helper()
+	result := parse.Parse([]byte(source), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors in:\n%s\n%v", source, result.Errors)
+	}
+	c := checker.New("main.ard", result.Program, nil, checker.CheckOptions{Strict: true})
+	c.Check()
+	return append(c.Diagnostics(), c.Warnings()...)
+}
+
+// The examples are compiled into the binary, so they must stay accurate as
+// the checker changes.
+func TestExplanationExamplesProduceTheirCode(t *testing.T) {
+	seen := map[checker.DiagnosticCode]bool{}
+	for _, explanation := range diagnostics.Explanations() {
+		if seen[explanation.Code] {
+			t.Fatalf("%s is explained twice", explanation.Code)
+		}
+		seen[explanation.Code] = true
+		t.Run(string(explanation.Code), func(t *testing.T) {
+			found := false
+			for _, diagnostic := range strictDiagnostics(t, explanation.Example) {
+				found = found || diagnostic.Code == explanation.Code
+			}
+			if !found {
+				t.Fatalf("example does not produce %s:\n%s", explanation.Code, explanation.Example)
+			}
+			if fixed := strictDiagnostics(t, explanation.Fixed); len(fixed) > 0 {
+				t.Fatalf("fixed example still has diagnostics: %v", fixed)
+			}
+		})
+	}
+}
+
+func TestWriteExplanation(t *testing.T) {
+	explanation, ok := diagnostics.Explain("immutable_assignment")
+	if !ok {
+		t.Fatal("expected an explanation for immutable_assignment")
+	}
+	var output bytes.Buffer
+	if err := diagnostics.WriteExplanation(&output, explanation); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"immutable_assignment: Immutable assignment\n\n", "\nExample:\n\n    let count = 0\n", "\nFixed:\n\n    mut count = 0\n"} {
+		if !strings.Contains(output.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, output.String())
+		}
+	}
+	if _, ok := diagnostics.Explain("no_such_code"); ok {
+		t.Fatal("expected no explanation for an unknown code")
+	}
+}
@@ -0,0 +1,119 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+// jsonDiagnostic is the machine-readable form of a diagnostic written by
+// RenderJSON. Lines and columns are 1-based. Label ranges are inclusive, like
+// the rendered carets; fix edit ranges are exclusive, so an edit whose start
+// and end are equal is an insertion.
+type jsonDiagnostic struct {
+	Severity string      `json:"severity"`
+	Code     string      `json:"code,omitempty"`
+	Title    string      `json:"title,omitempty"`
+	Message  string      `json:"message"`
+	File     string      `json:"file"`
+	Range    jsonRange   `json:"range"`
+	Labels   []jsonLabel `json:"labels,omitempty"`
+	Note     string      `json:"note,omitempty"`
+	Help     string      `json:"help,omitempty"`
+	Fixes    []jsonFix   `json:"fixes,omitempty"`
+}
+
+type jsonLabel struct {
+	File    string    `json:"file"`
+	Range   jsonRange `json:"range"`
+	Message string    `json:"message,omitempty"`
+	Primary bool      `json:"primary"`
+}
+
+type jsonFix struct {
+	Title string     `json:"title"`
+	Edits []jsonEdit `json:"edits"`
+}
+
+type jsonEdit struct {
+	File    string    `json:"file"`
+	Range   jsonRange `json:"range"`
+	NewText string    `json:"new_text"`
+}
+
+type jsonRange struct {
+	Start jsonPosition `json:"start"`
+	End   jsonPosition `json:"end"`
+}
+
+type jsonPosition struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// RenderJSON writes diagnostics as a single JSON array, for editors and
+// scripts. An empty list is written as [].
+func RenderJSON(w io.Writer, diagnostics []checker.Diagnostic) error {
+	out := make([]jsonDiagnostic, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		out = append(out, toJSONDiagnostic(diagnostic))
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// RenderRelativeJSON is RenderJSON with every path, including those of fix
+// edits, rebased from sourceRoot to displayRoot like RenderRelative.
+func RenderRelativeJSON(w io.Writer, diagnostics []checker.Diagnostic, sourceRoot, displayRoot string) error {
+	return RenderJSON(w, rebaseDiagnostics(diagnostics, sourceRoot, displayRoot))
+}
+
+func toJSONDiagnostic(diagnostic checker.Diagnostic) jsonDiagnostic {
+	out := jsonDiagnostic{
+		Severity: diagnosticLevelLabel(diagnostic.Kind),
+		Code:     string(diagnostic.Code),
+		Title:    diagnostic.Title,
+		Message:  diagnostic.Message,
+		File:     diagnostic.FilePath(),
+		Range:    toJSONRange(diagnostic.Location()),
+		Note:     diagnostic.Text,
+		Help:     diagnostic.Help,
+	}
+	if diagnostic.Code != "" {
+		out.Labels = append(out.Labels, toJSONLabel(diagnostic.Primary, true))
+		for _, label := range diagnostic.Secondary {
+			out.Labels = append(out.Labels, toJSONLabel(label, false))
+		}
+	}
+	for _, fix := range diagnostic.Fixes {
+		edits := make([]jsonEdit, 0, len(fix.Edits))
+		for _, edit := range fix.Edits {
+			edits = append(edits, jsonEdit{
+				File:    edit.FilePath,
+				Range:   toJSONRange(parse.Location{Start: edit.Start, End: edit.End}),
+				NewText: edit.NewText,
+			})
+		}
+		out.Fixes = append(out.Fixes, jsonFix{Title: fix.Title, Edits: edits})
+	}
+	return out
+}
+
+func toJSONLabel(label checker.DiagnosticLabel, primary bool) jsonLabel {
+	return jsonLabel{
+		File:    label.Span.FilePath,
+		Range:   toJSONRange(label.Span.Location),
+		Message: label.Message,
+		Primary: primary,
+	}
+}
+
+func toJSONRange(location parse.Location) jsonRange {
+	return jsonRange{
+		Start: jsonPosition{Line: location.Start.Row, Column: location.Start.Col},
+		End:   jsonPosition{Line: location.End.Row, Column: location.End.Col},
+	}
+}
@@ -0,0 +1,72 @@
+package diagnostics_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/diagnostics"
+	"github.com/akonwi/ard/parse"
+)
+
+func TestRenderJSONIncludesFixes(t *testing.T) {
+	source := "fn label(ok: Bool) Str {\n  match ok {\n    true => \"yes\",\n  }\n}\n"
+	result := parse.Parse([]byte(source), "main.ard")
+	c := checker.New("main.ard", result.Program, nil)
+	c.Check()
+
+	workingDir := t.TempDir()
+	var output bytes.Buffer
+	if err := diagnostics.RenderRelativeJSON(&output, c.Diagnostics(), filepath.Join(workingDir, "app"), workingDir); err != nil {
+		t.Fatal(err)
+	}
+	var got []struct {
+		Severity string
+		Code     string
+		File     string
+		Range    struct{ Start, End struct{ Line, Column int } }
+		Fixes    []struct {
+			Title string
+			Edits []struct {
+				File    string
+				Range   struct{ Start, End struct{ Line, Column int } }
+				NewText string `json:"new_text"`
+			}
+		}
+	}
+	if err := json.Unmarshal(output.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, output.String())
+	}
+	if len(got) == 0 {
+		t.Fatalf("no diagnostics in:\n%s", output.String())
+	}
+	diagnostic := got[0]
+	if diagnostic.Severity != "error" || diagnostic.Code != string(checker.DiagnosticCodeNonExhaustiveMatch) || diagnostic.File != "app/main.ard" {
+		t.Fatalf("diagnostic = %+v", diagnostic)
+	}
+	if diagnostic.Range.Start.Line != 2 || diagnostic.Range.Start.Column != 3 {
+		t.Fatalf("range = %+v, want a start of 2:3", diagnostic.Range)
+	}
+	if len(diagnostic.Fixes) != 1 || len(diagnostic.Fixes[0].Edits) != 1 {
+		t.Fatalf("fixes = %+v, want one fix with one edit", diagnostic.Fixes)
+	}
+	edit := diagnostic.Fixes[0].Edits[0]
+	if edit.File != "app/main.ard" || edit.Range.Start != edit.Range.End || edit.Range.Start.Line != 4 || edit.Range.Start.Column != 3 {
+		t.Fatalf("edit = %+v, want an insertion at app/main.ard:4:3", edit)
+	}
+	if edit.NewText != "  false => panic(\"TODO\"),\n  " {
+		t.Fatalf("new text = %q", edit.NewText)
+	}
+}
+
+func TestRenderJSONWritesEmptyArray(t *testing.T) {
+	var output bytes.Buffer
+	if err := diagnostics.RenderJSON(&output, nil); err != nil {
+		t.Fatal(err)
+	}
+	if output.String() != "[]\n" {
+		t.Fatalf("output = %q, want []", output.String())
+	}
+}
@@ -97,6 +97,10 @@ func RenderRelative(w io.Writer, diagnostics []checker.Diagnostic, sourceRoot, d
 }
 
 func RenderRelativeWithOptions(w io.Writer, diagnostics []checker.Diagnostic, sourceRoot, displayRoot string, options RenderOptions) error {
+	return RenderWithOptions(w, rebaseDiagnostics(diagnostics, sourceRoot, displayRoot), FileSourceProvider(displayRoot), options)
+}
+
+func rebaseDiagnostics(diagnostics []checker.Diagnostic, sourceRoot, displayRoot string) []checker.Diagnostic {
 	rebased := make([]checker.Diagnostic, len(diagnostics))
 	for i, diagnostic := range diagnostics {
 		rebased[i] = diagnostic
@@ -105,24 +109,36 @@ func RenderRelativeWithOptions(w io.Writer, diagnostics []checker.Diagnostic, so
 		for j, label := range diagnostic.Secondary {
 			rebased[i].Secondary[j] = rebaseLabel(label, sourceRoot, displayRoot)
 		}
+		if len(diagnostic.Fixes) > 0 {
+			rebased[i].Fixes = make([]checker.DiagnosticFix, len(diagnostic.Fixes))
+			for j, fix := range diagnostic.Fixes {
+				rebased[i].Fixes[j] = checker.DiagnosticFix{Title: fix.Title, Edits: make([]checker.TextEdit, len(fix.Edits))}
+				for k, edit := range fix.Edits {
+					edit.FilePath = rebasePath(edit.FilePath, sourceRoot, displayRoot)
+					rebased[i].Fixes[j].Edits[k] = edit
+				}
+			}
+		}
 	}
-	return RenderWithOptions(w, rebased, FileSourceProvider(displayRoot), options)
+	return rebased
 }
 
 func rebaseLabel(label checker.DiagnosticLabel, sourceRoot, displayRoot string) checker.DiagnosticLabel {
-	path := label.Span.FilePath
+	label.Span.FilePath = rebasePath(label.Span.FilePath, sourceRoot, displayRoot)
+	return label
+}
+
+func rebasePath(path, sourceRoot, displayRoot string) string {
 	if path == "" {
-		return label
+		return path
 	}
 	if !filepath.IsAbs(path) {
 		path = filepath.Join(sourceRoot, path)
 	}
 	if relative, err := filepath.Rel(displayRoot, path); err == nil {
-		label.Span.FilePath = relative
-	} else {
-		label.Span.FilePath = path
+		return relative
 	}
-	return label
+	return path
 }
 
 func RenderDiagnostic(w io.Writer, diagnostic checker.Diagnostic, source SourceProvider) error {
@@ -138,7 +154,13 @@ func renderDiagnostic(w io.Writer, diagnostic checker.Diagnostic, source SourceP
 	if title == "" {
 		title = diagnostic.Message
 	}
-	if _, err := fmt.Fprintf(w, "%s%s: %s%s\n", style.header, diagnosticLevelLabel(diagnostic.Kind), title, style.reset()); err != nil {
+	level := diagnosticLevelLabel(diagnostic.Kind)
+	if diagnostic.Code != "" {
+		// The code is what `ard explain` and `ard check --json` identify the
+		// diagnostic by.
+		level += "[" + string(diagnostic.Code) + "]"
+	}
+	if _, err := fmt.Fprintf(w, "%s%s: %s%s\n", style.header, level, title, style.reset()); err != nil {
 		return err
 	}
 
@@ -167,7 +189,22 @@ func renderDiagnostic(w io.Writer, diagnostic checker.Diagnostic, source SourceP
 	}
 
 	if diagnostic.Text != "" {
-		if _, err := fmt.Fprintf(w, "%s%*s |%s\n%s%*s =%s %s\n", style.gutter, gutterWidth, "", style.reset(), style.gutter, gutterWidth, "", style.reset(), diagnostic.Text); err != nil {
+		if _, err := fmt.Fprintf(w, "%s%*s |%s\n", style.gutter, gutterWidth, "", style.reset()); err != nil {
+			return err
+		}
+		for _, line := range strings.Split(diagnostic.Text, "\n") {
+			if _, err := fmt.Fprintf(w, "%s%*s =%s %s\n", style.gutter, gutterWidth, "", style.reset(), line); err != nil {
+				return err
+			}
+		}
+	}
+	if diagnostic.Help != "" {
+		if diagnostic.Text == "" {
+			if _, err := fmt.Fprintf(w, "%s%*s |%s\n", style.gutter, gutterWidth, "", style.reset()); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "%s%*s =%s %shelp:%s %s\n", style.gutter, gutterWidth, "", style.reset(), style.secondary, style.reset(), diagnostic.Help); err != nil {
 			return err
 		}
 	}
@@ -90,6 +90,7 @@ func TestRenderColorDetailsAndNeverMode(t *testing.T) {
 func TestRenderLabeledDiagnostic(t *testing.T) {
 	diagnostic := checker.Diagnostic{
 		Kind:  checker.Error,
+		Code:  checker.DiagnosticCodeTypeMismatch,
 		Title: "Type mismatch",
 		Primary: checker.DiagnosticLabel{
 			Span: checker.SourceSpan{FilePath: "main.ard", Location: parse.Location{
@@ -112,7 +113,7 @@ func TestRenderLabeledDiagnostic(t *testing.T) {
 	}
 
 	want := "" +
-		"error: Type mismatch\n" +
+		"error[type_mismatch]: Type mismatch\n" +
 		" --> main.ard:1:17\n" +
 		"  |\n" +
 		"1 | let name: Str = 42\n" +
@@ -154,7 +155,7 @@ func TestRenderUsesParserProducedStringSpans(t *testing.T) {
 				t.Fatal(err)
 			}
 			want := "" +
-				"error: Type mismatch\n" +
+				"error[type_mismatch]: Type mismatch\n" +
 				" --> main.ard:1:18\n" +
 				"  |\n" +
 				"1 | " + strings.TrimSuffix(source, "\n") + "\n" +
@@ -195,6 +196,89 @@ func TestRenderAlignsMultiDigitLineGutter(t *testing.T) {
 	}
 }
 
+func TestRenderHelpLine(t *testing.T) {
+	diagnostic := checker.Diagnostic{
+		Kind:  checker.Warn,
+		Code:  checker.DiagnosticCodeUnusedVariable,
+		Title: "Unused variable",
+		Help:  "prefix the name with `_` if the value is intentionally unused",
+		Primary: checker.DiagnosticLabel{
+			Span: checker.SourceSpan{FilePath: "main.ard", Location: parse.Location{
+				Start: parse.Point{Row: 1, Col: 5}, End: parse.Point{Row: 1, Col: 8},
+			}},
+			Message: "`size` is never read",
+		},
+	}
+	provider := func(string) ([]byte, error) { return []byte("let size = 3\n"), nil }
+
+	var output bytes.Buffer
+	if err := diagnostics.RenderDiagnostic(&output, diagnostic, provider); err != nil {
+		t.Fatal(err)
+	}
+	want := "" +
+		"warning[unused_variable]: Unused variable\n" +
+		" --> main.ard:1:5\n" +
+		"  |\n" +
+		"1 | let size = 3\n" +
+		"  |     ^^^^ `size` is never read\n" +
+		"  |\n" +
+		"  = help: prefix the name with `_` if the value is intentionally unused\n"
+	if output.String() != want {
+		t.Fatalf("output:\n%s\nwant:\n%s", output.String(), want)
+	}
+
+	diagnostic.Text = "`size` is declared but never read"
+	output.Reset()
+	if err := diagnostics.RenderDiagnosticWithOptions(&output, diagnostic, provider, diagnostics.RenderOptions{Color: diagnostics.ColorAlways}); err != nil {
+		t.Fatal(err)
+	}
+	wantTail := "\x1b[2m  =\x1b[0m `size` is declared but never read\n\x1b[2m  =\x1b[0m \x1b[36mhelp:\x1b[0m prefix the name"
+	if !strings.Contains(output.String(), wantTail) {
+		t.Fatalf("output missing %q:\n%q", wantTail, output.String())
+	}
+}
+
+func TestRenderParseErrors(t *testing.T) {
+	source := "fn main( {\n}\n"
+	parsed := parse.Parse([]byte(source), "main.ard")
+	if len(parsed.Errors) == 0 {
+		t.Fatalf("expected parse errors")
+	}
+	provider := func(string) ([]byte, error) { return []byte(source), nil }
+
+	var output bytes.Buffer
+	if err := diagnostics.Render(&output, checker.ParseErrorDiagnostics("main.ard", parsed.Errors), provider); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"error[syntax_error]: Syntax error\n", " --> main.ard:1:", "1 | fn main( {\n", "^ " + parsed.Errors[0].Message} {
+		if !strings.Contains(output.String(), want) {
+			t.Fatalf("output missing %q:\n%s", want, output.String())
+		}
+	}
+}
+
+func TestRenderPrintsEachTextLineAsNote(t *testing.T) {
+	diagnostic := checker.Diagnostic{
+		Kind:  checker.Error,
+		Title: "Type mismatch",
+		Text:  "note: `$K` was bound to `Str` because of argument 1 at main.ard:1:6\nnote: `$V` was bound to `Int` because of argument 2 at main.ard:1:13",
+		Primary: checker.DiagnosticLabel{
+			Span:    checker.SourceSpan{FilePath: "main.ard", Location: parse.Location{Start: parse.Point{Row: 1, Col: 16}, End: parse.Point{Row: 1, Col: 18}}},
+			Message: "expected `Int`, but this expression has type `Str`",
+		},
+	}
+	provider := func(string) ([]byte, error) { return []byte("both(\"a\", 1, \"b\")\n"), nil }
+
+	var output bytes.Buffer
+	if err := diagnostics.RenderDiagnostic(&output, diagnostic, provider); err != nil {
+		t.Fatal(err)
+	}
+	want := "  |\n  = note: `$K` was bound to `Str` because of argument 1 at main.ard:1:6\n  = note: `$V` was bound to `Int` because of argument 2 at main.ard:1:13\n"
+	if !strings.HasSuffix(output.String(), want) {
+		t.Fatalf("output:\n%s\nwant suffix:\n%s", output.String(), want)
+	}
+}
+
 func TestRenderRelativeRebasesProjectPathsToWorkingDirectory(t *testing.T) {
 	workingDir := t.TempDir()
 	projectRoot := filepath.Join(workingDir, "samples")
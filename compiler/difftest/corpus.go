@@ -0,0 +1,59 @@
+package difftest
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/air"
+	"github.com/akonwi/ard/frontend"
+)
+
+// RunCorpus compares every .ard program in dir across the backends, one
+// subtest per file. A program whose name ends in `.fail.ard` is expected to
+// exit with a non-zero status; any other program is expected to exit cleanly.
+// Either way, every backend must observe the same behavior.
+func RunCorpus(t *testing.T, dir string) {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join(dir, "*.ard"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("no .ard programs in %s", dir)
+	}
+	for _, path := range paths {
+		t.Run(strings.TrimSuffix(filepath.Base(path), ".ard"), func(t *testing.T) {
+			t.Parallel()
+			CheckProgram(t, path)
+		})
+	}
+}
+
+// CheckProgram loads the program at path and fails t unless every available
+// backend observes the same behavior for it.
+func CheckProgram(t *testing.T, path string) {
+	t.Helper()
+	loaded, err := frontend.LoadModule(path)
+	if err != nil {
+		t.Fatalf("load %s: %v", path, err)
+	}
+	program, err := air.Lower(loaded.Module)
+	if err != nil {
+		t.Fatalf("lower %s: %v", path, err)
+	}
+	report, err := Compare(program, loaded.ProjectInfo, t.TempDir())
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	if len(report.Outcomes) < 2 {
+		t.Skipf("fewer than two backends can run here:\n%s", report)
+	}
+	if !report.Agree() {
+		t.Fatalf("backends disagree on %s:\n%s", path, report)
+	}
+	failing := strings.HasSuffix(path, ".fail.ard")
+	if exited := report.Outcomes[0].ExitCode != 0; exited != failing {
+		t.Fatalf("%s exited with status %d:\n%s", path, report.Outcomes[0].ExitCode, report)
+	}
+}
@@ -0,0 +1,154 @@
+// Package difftest runs one checked program through every backend and
+// reports where their observable behavior differs.
+//
+// Each backend builds the program the way `ard build` does and runs the
+// result. The observable behavior is what a user of the program can see and
+// script against: its standard output and its exit status. Standard error is
+// left out because each runtime formats panics its own way.
+package difftest
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+	"github.com/akonwi/ard/checker"
+	gotarget "github.com/akonwi/ard/go"
+	jstarget "github.com/akonwi/ard/js"
+)
+
+// ErrUnavailable reports a backend that cannot run on this machine, such as
+// the JavaScript backend when node is not installed.
+var ErrUnavailable = errors.New("backend unavailable")
+
+// Outcome is what one backend observably did with a program.
+type Outcome struct {
+	Backend  string
+	Stdout   string
+	ExitCode int
+	// Stderr is kept for reporting differences but is never compared.
+	Stderr string
+}
+
+// Backend builds a program into dir and runs it.
+type Backend struct {
+	Name string
+	Run  func(program *air.Program, info *checker.ProjectInfo, dir string) (Outcome, error)
+}
+
+// Backends are the backends Compare runs, in report order.
+var Backends = []Backend{
+	{Name: "go", Run: runGo},
+	{Name: "js", Run: runJS},
+}
+
+// Report holds the outcome of every available backend for one program.
+type Report struct {
+	Outcomes []Outcome
+	// Skipped names the backends that returned ErrUnavailable.
+	Skipped []string
+}
+
+// Agree reports whether every backend that ran observed the same behavior.
+func (r Report) Agree() bool {
+	for _, outcome := range r.Outcomes[min(1, len(r.Outcomes)):] {
+		if outcome.Stdout != r.Outcomes[0].Stdout || outcome.ExitCode != r.Outcomes[0].ExitCode {
+			return false
+		}
+	}
+	return true
+}
+
+// String describes each backend's outcome, for failure messages.
+func (r Report) String() string {
+	var out strings.Builder
+	for _, outcome := range r.Outcomes {
+		fmt.Fprintf(&out, "%s: exit status %d\n", outcome.Backend, outcome.ExitCode)
+		fmt.Fprintf(&out, "  stdout:\n%s", indent(outcome.Stdout))
+		if outcome.Stderr != "" {
+			fmt.Fprintf(&out, "  stderr:\n%s", indent(outcome.Stderr))
+		}
+	}
+	for _, name := range r.Skipped {
+		fmt.Fprintf(&out, "%s: skipped, %v\n", name, ErrUnavailable)
+	}
+	return out.String()
+}
+
+// Compare runs program through every backend, each in its own directory
+// under dir. Build failures are errors; a program that runs and exits with a
+// non-zero status is an outcome like any other.
+func Compare(program *air.Program, info *checker.ProjectInfo, dir string) (Report, error) {
+	var report Report
+	for _, backend := range Backends {
+		outcome, err := backend.Run(program, info, filepath.Join(dir, backend.Name))
+		if errors.Is(err, ErrUnavailable) {
+			report.Skipped = append(report.Skipped, backend.Name)
+			continue
+		}
+		if err != nil {
+			return Report{}, fmt.Errorf("%s backend: %w", backend.Name, err)
+		}
+		outcome.Backend = backend.Name
+		report.Outcomes = append(report.Outcomes, outcome)
+	}
+	return report, nil
+}
+
+func runGo(program *air.Program, info *checker.ProjectInfo, dir string) (Outcome, error) {
+	binary, err := gotarget.BuildProgram(program, filepath.Join(dir, "main"), info)
+	if err != nil {
+		return Outcome{}, err
+	}
+	return run(exec.Command(binary))
+}
+
+func runJS(program *air.Program, _ *checker.ProjectInfo, dir string) (Outcome, error) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		return Outcome{}, ErrUnavailable
+	}
+	entry, err := jstarget.BuildProgram(program, dir)
+	if err != nil {
+		return Outcome{}, err
+	}
+	return run(exec.Command(node, entry))
+}
+
+func run(cmd *exec.Cmd) (Outcome, error) {
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	outcome := Outcome{}
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return Outcome{}, err
+		}
+		outcome.ExitCode = exitErr.ExitCode()
+	}
+	outcome.Stdout = stdout.String()
+	outcome.Stderr = stderr.String()
+	return outcome, nil
+}
+
+func indent(text string) string {
+	if text == "" {
+		return ""
+	}
+	lines := strings.SplitAfter(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "    " + line
+		}
+	}
+	joined := strings.Join(lines, "")
+	if !strings.HasSuffix(joined, "\n") {
+		joined += "\n"
+	}
+	return joined
+}
@@ -0,0 +1,29 @@
+package difftest
+
+import "testing"
+
+func TestCorpus(t *testing.T) {
+	RunCorpus(t, "testdata")
+}
+
+func TestReportNamesDisagreeingBackends(t *testing.T) {
+	report := Report{Outcomes: []Outcome{
+		{Backend: "go", Stdout: "1\n"},
+		{Backend: "js", Stdout: "1\n", Stderr: "warning\n"},
+	}}
+	if !report.Agree() {
+		t.Fatalf("outcomes differing only in stderr disagree:\n%s", report)
+	}
+	report.Outcomes[1].ExitCode = 2
+	if report.Agree() {
+		t.Fatal("outcomes with different exit statuses agree")
+	}
+	report.Skipped = []string{"wasm"}
+	want := "go: exit status 0\n  stdout:\n    1\njs: exit status 2\n  stdout:\n    1\n  stderr:\n    warning\nwasm: skipped, backend unavailable\n"
+	if got := report.String(); got != want {
+		t.Fatalf("report =\n%s\nwant:\n%s", got, want)
+	}
+	if !(Report{}).Agree() {
+		t.Fatal("empty report disagrees")
+	}
+}
@@ -39,7 +39,7 @@ func (p printer) printDocAtColumn(root doc, baseColumn int) string {
 				stack = append(stack, printCmd{indent: cmd.indent, mode: cmd.mode, doc: node.parts[i]})
 			}
 		case docIndent:
-			stack = append(stack, printCmd{indent: cmd.indent + indentWidth, mode: cmd.mode, doc: node.content})
+			stack = append(stack, printCmd{indent: cmd.indent + p.indentWidth, mode: cmd.mode, doc: node.content})
 		case docIfBreak:
 			if cmd.mode == modeBreak {
 				stack = append(stack, printCmd{indent: cmd.indent, mode: cmd.mode, doc: node.broken})
@@ -76,7 +76,7 @@ func (p printer) printDocAtColumn(root doc, baseColumn int) string {
 		case docGroup:
 			testStack := append([]printCmd(nil), stack...)
 			testStack = append(testStack, printCmd{indent: cmd.indent, mode: modeFlat, doc: node.content})
-			if fits(p.maxLineWidth-column, testStack) {
+			if p.fits(p.maxLineWidth-column, testStack) {
 				stack = append(stack, printCmd{indent: cmd.indent, mode: modeFlat, doc: node.content})
 			} else {
 				stack = append(stack, printCmd{indent: cmd.indent, mode: modeBreak, doc: node.content})
@@ -87,7 +87,7 @@ func (p printer) printDocAtColumn(root doc, baseColumn int) string {
 	return out.String()
 }
 
-func fits(remaining int, stack []printCmd) bool {
+func (p printer) fits(remaining int, stack []printCmd) bool {
 	for remaining >= 0 && len(stack) > 0 {
 		cmd := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
@@ -100,7 +100,7 @@ func fits(remaining int, stack []printCmd) bool {
 				stack = append(stack, printCmd{indent: cmd.indent, mode: cmd.mode, doc: node.parts[i]})
 			}
 		case docIndent:
-			stack = append(stack, printCmd{indent: cmd.indent + indentWidth, mode: cmd.mode, doc: node.content})
+			stack = append(stack, printCmd{indent: cmd.indent + p.indentWidth, mode: cmd.mode, doc: node.content})
 		case docIfBreak:
 			if cmd.mode == modeBreak {
 				stack = append(stack, printCmd{indent: cmd.indent, mode: cmd.mode, doc: node.broken})
@@ -7,29 +7,70 @@ import (
 	"github.com/akonwi/ard/parse"
 )
 
-// Format applies Ard formatting rules.
-func Format(input []byte, fileName string) ([]byte, error) {
+// Format applies Ard formatting rules. An optional Options adjusts the
+// layout; see FindOptions for a project's configuration.
+//
+// Source with parse errors is formatted region by region (see formatPartial):
+// the result has its well-formed top-level regions formatted and the broken
+// ones left untouched, and the error lists the parse errors.
+func Format(input []byte, fileName string, options ...Options) ([]byte, error) {
 	normalized := normalizeWhitespace(string(input))
 	if strings.TrimSpace(normalized) == "" {
 		return []byte(normalized), nil
 	}
 
+	printOptions := normalizeOptions(options)
 	result := parse.Parse([]byte(normalized), fileName)
 	if len(result.Errors) > 0 {
 		lines := make([]string, 0, len(result.Errors))
 		for _, err := range result.Errors {
 			lines = append(lines, fmt.Sprintf("%s %s", err.Location.Start, err.Message))
 		}
-		return nil, fmt.Errorf("cannot format invalid Ard source:\n%s", strings.Join(lines, "\n"))
+		partial := formatPartial(normalized, fileName, printOptions)
+		return []byte(partial), fmt.Errorf("cannot format invalid Ard source:\n%s", strings.Join(lines, "\n"))
 	}
 
-	removeUnusedImports(result.Program)
+	if printOptions.FixImports {
+		removeUnusedImports(result.Program)
+	}
 
-	printer := newPrinter(100)
+	printer := newPrinter(printOptions)
 	formatted := printer.program(result.Program)
 	return []byte(normalizeWhitespace(formatted)), nil
 }
 
+// Verify formats input twice and reports an error when the second pass
+// changes the output of the first, i.e. when formatting is not idempotent.
+// Invalid source is rejected with the parse errors.
+func Verify(input []byte, fileName string, options ...Options) error {
+	first, err := Format(input, fileName, options...)
+	if err != nil {
+		return err
+	}
+	second, err := Format(first, fileName, options...)
+	if err != nil {
+		return fmt.Errorf("formatted output no longer parses: %w", err)
+	}
+	if string(first) == string(second) {
+		return nil
+	}
+
+	firstLines := strings.Split(string(first), "\n")
+	secondLines := strings.Split(string(second), "\n")
+	line := 0
+	for line < len(firstLines) && line < len(secondLines) && firstLines[line] == secondLines[line] {
+		line++
+	}
+	return fmt.Errorf("formatting is not idempotent at line %d: %q became %q", line+1, lineAt(firstLines, line), lineAt(secondLines, line))
+}
+
+func lineAt(lines []string, i int) string {
+	if i < len(lines) {
+		return lines[i]
+	}
+	return ""
+}
+
 func normalizeWhitespace(source string) string {
 	if source == "" {
 		return ""
@@ -36,6 +36,18 @@ func TestFormatIsIdempotent(t *testing.T) {
 			name:  "go import",
 			input: "use go:fmt\n\nfn main() {\n  fmt::Println(\"hello\")\n}\n",
 		},
+		{
+			name:  "struct literal with a base",
+			input: "let custom = Config{ ..base,retries: 5}\nlet copy = Config{..base}\n",
+		},
+		{
+			name:  "struct field defaults",
+			input: "struct Config {\n  host: Str,\n  retries: Int=3,\n  name: Str = \"svc\" + \"-a\", // shown in logs\n}\n",
+		},
+		{
+			name:  "enum variants with associated data",
+			input: "enum Event {\n  Click(Int,Int),\n  Key( Str ),\n  Quit\n}\n",
+		},
 	}
 
 	for _, tt := range inputs {
@@ -177,7 +189,7 @@ func TestUnusedImportRemovalKeepsTypeArgUses(t *testing.T) {
 
 	for _, tt := range inputs {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := Format([]byte(tt.input), "test.ard")
+			got, err := Format([]byte(tt.input), "test.ard", Options{FixImports: true})
 			if err != nil {
 				t.Fatalf("format failed: %v", err)
 			}
@@ -191,6 +203,28 @@ func TestUnusedImportRemovalKeepsTypeArgUses(t *testing.T) {
 	}
 }
 
+func TestFormatOrganizesImports(t *testing.T) {
+	input := "use app/util\nuse ard/list\nuse app/util\nuse ard/io\nuse ard/list as list\n\nlet size = list::new<Int>().size()\n"
+
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "use ard/io\nuse ard/list\n\nuse app/util\n\nlet size = list::new<Int>().size()\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+
+	fixed, err := Format([]byte(input), "test.ard", Options{FixImports: true})
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want = "use ard/list\n\nlet size = list::new<Int>().size()\n"
+	if string(fixed) != want {
+		t.Fatalf("fixed = %q, want %q", string(fixed), want)
+	}
+}
+
 func TestFormatMutRefExpressions(t *testing.T) {
 	input := "mut counter = 0\nlet r = mut counter\nbump(mut counter)\nlet fresh = mut Point{x: 1}\n"
 	formatted, err := Format([]byte(input), "test.ard")
@@ -203,6 +237,90 @@ func TestFormatMutRefExpressions(t *testing.T) {
 	}
 }
 
+func TestFormatReExportsAndPrivateFields(t *testing.T) {
+	input := "pub use app/shapes::Circle\nuse app/util\npub use app/shapes::Box\nuse ard/io\n\nstruct Account {\n  private  balance: Int,\n  owner: Str,\n}\n"
+	formatted, err := Format([]byte(input), "test.ard", Options{FixImports: true})
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "pub use app/shapes::Box\npub use app/shapes::Circle\n\nstruct Account {\n  private balance: Int,\n  owner: Str,\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatConstants(t *testing.T) {
+	input := "const  MAX:Int = 10 *  10\nconst NAME = \"ard\"\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "const MAX: Int = 10 * 10\nconst NAME = \"ard\"\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatKeepsStrFormatTemplatesRaw(t *testing.T) {
+	input := "let a = Str::format(\"\\{0:>8\\} {1:.2} {{x}}\", 1, 2.0)\nlet b = \"\\{literal\\} {name}\"\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "let a = Str::format(\"{0:>8} {1:.2} \\{\\{x\\}\\}\", 1, 2.0)\nlet b = \"\\{literal\\} {name}\"\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatPrivateVariables(t *testing.T) {
+	input := "private  let cache =  1\nprivate   const LIMIT = 2\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "private let cache = 1\nprivate const LIMIT = 2\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatPragmas(t *testing.T) {
+	input := "#deny( warnings )\nuse ard/io\n#forbid(dynamic)\nfn main() {\n  io::print(\"hi\")\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "#deny(warnings)\n#forbid(dynamic)\n\nuse ard/io\n\nfn main() {\n  io::print(\"hi\")\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatAssert(t *testing.T) {
+	input := "fn main() {\n  assert  total==3\n  ensure ready ,  \"not ready\"\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn main() {\n  assert total == 3\n  ensure ready, \"not ready\"\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatTryReceivers(t *testing.T) {
+	input := "fn next() [Str]?!Str {\n  let row = (try read()).or([])\n  let size = (try read() -> _ { [] }).size()\n  Result::ok(row)\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn next() [Str]?!Str {\n  let row = (try read()).or([])\n  let size = (try read() -> _ { [] }).size()\n  Result::ok(row)\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
 func TestFormatDefer(t *testing.T) {
 	input := "fn main() {\n  defer cleanup(  value )\n  defer {\n  cleanup()\n  log(\"done\")\n}\n}\n"
 	formatted, err := Format([]byte(input), "test.ard")
@@ -215,6 +333,140 @@ func TestFormatDefer(t *testing.T) {
 	}
 }
 
+func TestFormatPipes(t *testing.T) {
+	input := "fn main() {\n  let n = 3|>double   |> add( 1 )\n  let result = some_value_with_a_long_name |> transform_the_value(first_argument) |> validate(second, third)\n  let s = (1 |> double()) + 1\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn main() {\n  let n = 3 |> double |> add(1)\n  let result = some_value_with_a_long_name\n    |> transform_the_value(first_argument)\n    |> validate(second, third)\n  let s = (1 |> double()) + 1\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+	again, err := Format(formatted, "test.ard")
+	if err != nil {
+		t.Fatalf("second format: %v", err)
+	}
+	if string(again) != want {
+		t.Fatalf("format is not idempotent: %q", string(again))
+	}
+}
+
+func TestFormatAsExpressions(t *testing.T) {
+	input := "fn main() {\n  let c = shape   as   Circle\n  let r = (shape as Circle).is_some()\n  let n = a + b as Int\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn main() {\n  let c = shape as Circle\n  let r = (shape as Circle).is_some()\n  let n = a + b as Int\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatDeprecatedAttributes(t *testing.T) {
+	input := "@deprecated(\"use add\")\n\nfn plus(a: Int, b: Int) Int { a + b }\n\nimpl Point {\n  @deprecated(\"read x\")\n  fn get() Int { self.x }\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "@deprecated(\"use add\")\nfn plus(a: Int, b: Int) Int {\n  a + b\n}\n\nimpl Point {\n  @deprecated(\"read x\")\n  fn get() Int {\n    self.x\n  }\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatIfLetAndLetElse(t *testing.T) {
+	input := "fn show(m: Int?) Int {\n  let x = m else {0}\n  let y: Int = m else {\n    let fallback = 2\n    fallback\n  }\n  if let  z =m { z } else if let w = m { w } else { x + y }\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn show(m: Int?) Int {\n  let x = m else { 0 }\n  let y: Int = m else {\n    let fallback = 2\n    fallback\n  }\n  if let z = m {\n    z\n  } else if let w = m {\n    w\n  } else {\n    x + y\n  }\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+	again, err := Format(formatted, "test.ard")
+	if err != nil {
+		t.Fatalf("second format: %v", err)
+	}
+	if string(again) != want {
+		t.Fatalf("format is not idempotent: %q", string(again))
+	}
+}
+
+func TestFormatIfAsValue(t *testing.T) {
+	input := "fn pick(n: Int) Int? {\n  let x = if n > 1 {n * 2} else if n > 0 { 1 }\n  x\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn pick(n: Int) Int? {\n  let x = if n > 1 {\n    n * 2\n  } else if n > 0 {\n    1\n  }\n  x\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatCompoundAssignments(t *testing.T) {
+	input := "fn main() {\n  mut x = 1\n  x =+ 1\n  x =-2\n  x =*3\n  x =/ 4\n  x = x * 5\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn main() {\n  mut x = 1\n  x =+ 1\n  x =- 2\n  x =* 3\n  x =/ 4\n  x = x * 5\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatRangeLoopStep(t *testing.T) {
+	input := "fn main() {\n  for i in 10..0   by  -2 {\n    i\n  }\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn main() {\n  for i in 10..0 by -2 {\n    i\n  }\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatWhileLet(t *testing.T) {
+	input := "fn drain(q: Queue) {\n  while let  item =q.pop() { item }\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn drain(q: Queue) {\n  while let item = q.pop() {\n    item\n  }\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatStructPatterns(t *testing.T) {
+	input := "fn f(p: Point) Int {\n  match p {\n    Point{x:0,  y} => y,\n    Point{ x: px, y: Point{a: _} } => px,\n    _ => 0,\n  }\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn f(p: Point) Int {\n  match p {\n    Point{x: 0, y} => y,\n    Point{x: px, y: Point{a: _}} => px,\n    _ => 0,\n  }\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestFormatOrPatterns(t *testing.T) {
+	input := "fn f(n: Int) Str {\n  match n {\n    1|2 |  3 => \"few\",\n    _ => \"many\",\n  }\n}\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn f(n: Int) Str {\n  match n {\n    1 | 2 | 3 => \"few\",\n    _ => \"many\",\n  }\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
 func TestFormatInlineBreakMatchArms(t *testing.T) {
 	input := "fn main() {\n  for i in 1..3 {\n    match i {\n      2 => break,\n      _ => (),\n    }\n    match {\n      i == 1 => break,\n      _ => (),\n    }\n  }\n}\n"
 	formatted, err := Format([]byte(input), "test.ard")
@@ -242,3 +494,110 @@ func TestFormatMutRefMatchArmStaysInline(t *testing.T) {
 		t.Fatalf("formatted output does not re-parse: %v", res.Errors)
 	}
 }
+
+func TestMigrateRewritesLegacyMutParameters(t *testing.T) {
+	input := "fn bump(mut m: Model) {\n  m.n = 1\n}\n\nlet reset = fn(mut m: Model) { m.n = 0 }\n"
+	migrated, legacy, err := Migrate([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	if len(legacy) != 2 {
+		t.Fatalf("rewrites = %d, want 2", len(legacy))
+	}
+	want := "fn bump(m: mut Model) {\n  m.n = 1\n}\n\nlet reset = fn(m: mut Model) {\n  m.n = 0\n}\n"
+	if string(migrated) != want {
+		t.Fatalf("migrated = %q, want %q", string(migrated), want)
+	}
+}
+
+func TestMigrateRejectsOtherParseErrors(t *testing.T) {
+	if _, _, err := Migrate([]byte("fn bump(mut m: Model {\n}\n"), "test.ard"); err == nil {
+		t.Fatalf("expected migrate to reject invalid source")
+	}
+}
+
+func TestFormatLeavesBrokenRegionsUntouched(t *testing.T) {
+	input := "use ard/io\n\nfn   add(a:Int,b:Int) Int { a+b }\n\n// broken\nfn broken(a: Int {\n  a +\n}\n\nstruct Point { x:Int, y:Int }\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err == nil {
+		t.Fatalf("expected the parse errors to be reported")
+	}
+	if !strings.Contains(err.Error(), "cannot format invalid Ard source") {
+		t.Fatalf("error = %v", err)
+	}
+	want := "use ard/io\n\nfn add(a: Int, b: Int) Int {\n  a + b\n}\n\n// broken\nfn broken(a: Int {\n  a +\n}\n\nstruct Point {\n  x: Int,\n  y: Int,\n}\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	if err := Verify([]byte("fn main() {\n  let x = [1,2,3]\n}\n"), "test.ard"); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if err := Verify([]byte("fn main( {\n"), "test.ard"); err == nil {
+		t.Fatalf("expected verify to reject invalid source")
+	}
+}
+
+func TestFormatRange(t *testing.T) {
+	input := "use ard/io\n\nlet   a  =  1\nfn   inc( x:Int ) Int {\n  x+1\n}\nlet b=[1,2]"
+	tests := []struct {
+		name      string
+		startLine int
+		endLine   int
+		want      string
+	}{
+		{
+			name:      "single statement",
+			startLine: 3,
+			endLine:   3,
+			want:      "use ard/io\n\nlet a = 1\nfn   inc( x:Int ) Int {\n  x+1\n}\nlet b=[1,2]",
+		},
+		{
+			name:      "selection inside a function formats the whole function",
+			startLine: 5,
+			endLine:   5,
+			want:      "use ard/io\n\nlet   a  =  1\nfn inc(x: Int) Int {\n  x + 1\n}\nlet b=[1,2]",
+		},
+		{
+			name:      "several statements up to the end of the file",
+			startLine: 4,
+			endLine:   7,
+			want:      "use ard/io\n\nlet   a  =  1\nfn inc(x: Int) Int {\n  x + 1\n}\nlet b = [1, 2]",
+		},
+		{
+			name:      "imports are left alone",
+			startLine: 1,
+			endLine:   2,
+			want:      input,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatRange([]byte(input), tt.startLine, tt.endLine)
+			if err != nil {
+				t.Fatalf("format range: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Fatalf("formatted = %q, want %q", string(got), tt.want)
+			}
+		})
+	}
+
+	if _, err := FormatRange([]byte("fn main( {\n"), 1, 1); err == nil {
+		t.Fatalf("expected invalid source to be rejected")
+	}
+}
+
+func TestFormatInlineUnions(t *testing.T) {
+	input := "fn show(value:Int|Str)  Bool|Str {}\nlet f: fn() Int | Str = make\nlet g: (fn() Int) | Str = make\nlet v: (Int|Str)? = 1\n"
+	formatted, err := Format([]byte(input), "test.ard")
+	if err != nil {
+		t.Fatalf("format: %v", err)
+	}
+	want := "fn show(value: Int | Str) Bool | Str {}\nlet f: fn() Int | Str = make\nlet g: (fn() Int) | Str = make\nlet v: (Int | Str)? = 1\n"
+	if string(formatted) != want {
+		t.Fatalf("formatted = %q, want %q", string(formatted), want)
+	}
+}
@@ -0,0 +1,41 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+	"github.com/akonwi/ard/version"
+)
+
+// Migrate rewrites legacy spellings (see parse.LegacySyntax) into the syntax
+// of the current language version and returns the canonically formatted
+// source along with the spellings it rewrote. Sources with other parse
+// errors are rejected, like Format.
+func Migrate(input []byte, fileName string, options ...Options) ([]byte, []parse.LegacySyntax, error) {
+	normalized := normalizeWhitespace(string(input))
+	if strings.TrimSpace(normalized) == "" {
+		return []byte(normalized), nil, nil
+	}
+
+	result := parse.Parse([]byte(normalized), fileName)
+	// Every legacy spelling predates the current language, so allowing the
+	// oldest language accepts all of them.
+	migrated := result.AllowLegacy(version.Semver{})
+	if len(result.Errors) > 0 {
+		lines := make([]string, 0, len(result.Errors))
+		for _, err := range result.Errors {
+			lines = append(lines, fmt.Sprintf("%s %s", err.Location.Start, err.Message))
+		}
+		return nil, nil, fmt.Errorf("cannot migrate invalid Ard source:\n%s", strings.Join(lines, "\n"))
+	}
+
+	printOptions := normalizeOptions(options)
+	if printOptions.FixImports {
+		removeUnusedImports(result.Program)
+	}
+
+	printer := newPrinter(printOptions)
+	formatted := printer.program(result.Program)
+	return []byte(normalizeWhitespace(formatted)), migrated, nil
+}
@@ -0,0 +1,121 @@
+package formatter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultMaxWidth   = 100
+	defaultIndentSize = 2
+)
+
+// Options controls the formatter's layout. The zero value formats with the
+// defaults: 100 columns, two-space indentation, and trailing commas.
+type Options struct {
+	// MaxWidth is the line width the formatter tries to stay within.
+	MaxWidth int
+	// IndentSize is the number of spaces per indentation level.
+	IndentSize int
+	// OmitTrailingCommas leaves out the comma after the last item of a list,
+	// map, argument list, or parameter list that is split across lines.
+	OmitTrailingCommas bool
+	// FixImports removes the imports nothing in the file refers to, which are
+	// the imports the checker reports as unused. It is not read from
+	// ard.toml, since removing code is a per-run decision.
+	FixImports bool
+}
+
+func normalizeOptions(options []Options) Options {
+	var opts Options
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = defaultMaxWidth
+	}
+	if opts.IndentSize <= 0 {
+		opts.IndentSize = defaultIndentSize
+	}
+	return opts
+}
+
+// FindOptions reads the `[format]` section of the ard.toml that governs path,
+// found by walking up from it. Without a project file, or without the
+// section, it returns the zero Options.
+//
+//	[format]
+//	max_width = 80
+//	indent_size = 4
+//	trailing_commas = false
+func FindOptions(path string) (Options, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return Options{}, err
+	}
+	for current := absPath; ; {
+		tomlPath := filepath.Join(current, "ard.toml")
+		if content, err := os.ReadFile(tomlPath); err == nil {
+			options, err := parseFormatSection(string(content))
+			if err != nil {
+				return Options{}, fmt.Errorf("failed to parse %s: %w", tomlPath, err)
+			}
+			return options, nil
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return Options{}, nil
+		}
+		current = parent
+	}
+}
+
+func parseFormatSection(content string) (Options, error) {
+	options := Options{}
+	section := ""
+	sectionRe := regexp.MustCompile(`^\s*\[([^\]]+)\]\s*$`)
+	entryRe := regexp.MustCompile(`^\s*([A-Za-z_]+)\s*=\s*([^#]*?)\s*(?:#.*)?$`)
+	for _, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		if matches := sectionRe.FindStringSubmatch(line); len(matches) == 2 {
+			section = matches[1]
+			continue
+		}
+		if section != "format" {
+			continue
+		}
+		matches := entryRe.FindStringSubmatch(line)
+		if len(matches) != 3 {
+			return Options{}, fmt.Errorf("invalid [format] entry: %s", trimmed)
+		}
+		key, value := matches[1], matches[2]
+		switch key {
+		case "max_width", "indent_size":
+			n, err := strconv.Atoi(value)
+			if err != nil || n <= 0 {
+				return Options{}, fmt.Errorf("[format].%s must be a positive integer", key)
+			}
+			if key == "max_width" {
+				options.MaxWidth = n
+			} else {
+				options.IndentSize = n
+			}
+		case "trailing_commas":
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				return Options{}, fmt.Errorf("[format].trailing_commas must be true or false")
+			}
+			options.OmitTrailingCommas = !enabled
+		default:
+			return Options{}, fmt.Errorf("unknown [format] option: %s", key)
+		}
+	}
+	return options, nil
+}
@@ -0,0 +1,81 @@
+package formatter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFormatOptions(t *testing.T) {
+	input := "fn main() {\n  let p = Point{x: first_value, y: second_value, z: third_value}\n  let total = add(first_value, second_value)\n}\n"
+	tests := []struct {
+		name    string
+		options Options
+		want    string
+	}{
+		{
+			name:    "zero options use the defaults",
+			options: Options{},
+			want:    "fn main() {\n  let p = Point{\n    x: first_value,\n    y: second_value,\n    z: third_value,\n  }\n  let total = add(first_value, second_value)\n}\n",
+		},
+		{
+			name:    "narrow width and four-space indent",
+			options: Options{MaxWidth: 30, IndentSize: 4},
+			want:    "fn main() {\n    let p = Point{\n        x: first_value,\n        y: second_value,\n        z: third_value,\n    }\n    let total = add(\n        first_value,\n        second_value,\n    )\n}\n",
+		},
+		{
+			name:    "without trailing commas",
+			options: Options{MaxWidth: 30, OmitTrailingCommas: true},
+			want:    "fn main() {\n  let p = Point{\n    x: first_value,\n    y: second_value,\n    z: third_value\n  }\n  let total = add(\n    first_value,\n    second_value\n  )\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			formatted, err := Format([]byte(input), "test.ard", tt.options)
+			if err != nil {
+				t.Fatalf("format: %v", err)
+			}
+			if string(formatted) != tt.want {
+				t.Fatalf("formatted = %q, want %q", string(formatted), tt.want)
+			}
+			again, err := Format(formatted, "test.ard", tt.options)
+			if err != nil {
+				t.Fatalf("second format: %v", err)
+			}
+			if string(again) != tt.want {
+				t.Fatalf("format is not idempotent: %q", string(again))
+			}
+		})
+	}
+}
+
+func TestFindOptions(t *testing.T) {
+	dir := t.TempDir()
+	nested := filepath.Join(dir, "src", "nested")
+	if err := os.MkdirAll(nested, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	manifest := "name = \"demo\"\nard = \">= 0.1.0\"\n\n[go]\nbuild_tags = [\"x\"]\n\n[format]\nmax_width = 80 # columns\nindent_size = 4\ntrailing_commas = false\n"
+	if err := os.WriteFile(filepath.Join(dir, "ard.toml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	options, err := FindOptions(filepath.Join(nested, "main.ard"))
+	if err != nil {
+		t.Fatalf("FindOptions: %v", err)
+	}
+	want := Options{MaxWidth: 80, IndentSize: 4, OmitTrailingCommas: true}
+	if options != want {
+		t.Fatalf("options = %+v, want %+v", options, want)
+	}
+
+	for _, bad := range []struct{ section, err string }{
+		{"[format]\nmax_width = wide\n", "[format].max_width must be a positive integer"},
+		{"[format]\ntrailing_commas = maybe\n", "[format].trailing_commas must be true or false"},
+		{"[format]\ntabs = true\n", "unknown [format] option: tabs"},
+	} {
+		if _, err := parseFormatSection(bad.section); err == nil || err.Error() != bad.err {
+			t.Fatalf("parseFormatSection(%q) error = %v, want %q", bad.section, err, bad.err)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package formatter
+
+import (
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// formatPartial formats source that does not parse as a whole. The source is
+// split into top-level regions (see splitRegions) and each region that parses
+// on its own is formatted; the rest are kept verbatim so a broken span never
+// loses text. Unused imports are kept even with Options.FixImports, because
+// usage can't be known without the whole program.
+func formatPartial(source string, fileName string, options Options) string {
+	regions := splitRegions(source)
+	var out strings.Builder
+	for i, region := range regions {
+		text := strings.TrimRight(region, "\n")
+		result := parse.Parse([]byte(text+"\n"), fileName)
+		if len(result.Errors) > 0 {
+			out.WriteString(region)
+			continue
+		}
+		printer := newPrinter(options)
+		out.WriteString(strings.TrimRight(printer.program(result.Program), "\n"))
+		out.WriteString("\n")
+		if i < len(regions)-1 {
+			out.WriteString("\n")
+		}
+	}
+	return normalizeWhitespace(out.String())
+}
+
+// splitRegions splits source into top-level regions. A region starts at a
+// line that begins in the first column, is not a closing bracket, and follows
+// a blank line, which is how formatted declarations are laid out. Each region
+// keeps its trailing blank lines so concatenating the regions restores the
+// source.
+func splitRegions(source string) []string {
+	lines := strings.SplitAfter(source, "\n")
+	regions := []string{}
+	var current strings.Builder
+	previousBlank := true
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		blank := strings.TrimSpace(line) == ""
+		if !blank && previousBlank && startsRegion(line) && current.Len() > 0 {
+			regions = append(regions, current.String())
+			current.Reset()
+		}
+		current.WriteString(line)
+		previousBlank = blank
+	}
+	if current.Len() > 0 {
+		regions = append(regions, current.String())
+	}
+	return regions
+}
+
+func startsRegion(line string) bool {
+	switch line[0] {
+	case ' ', '\t', '}', ')', ']':
+		return false
+	}
+	return true
+}
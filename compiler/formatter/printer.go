@@ -10,14 +10,35 @@ import (
 	"github.com/akonwi/ard/parse"
 )
 
-const indentWidth = 2
-
 type printer struct {
-	maxLineWidth int
+	maxLineWidth   int
+	indentWidth    int
+	trailingCommas bool
+}
+
+func newPrinter(options Options) printer {
+	return printer{
+		maxLineWidth:   options.MaxWidth,
+		indentWidth:    options.IndentSize,
+		trailingCommas: !options.OmitTrailingCommas,
+	}
 }
 
-func newPrinter(maxLineWidth int) printer {
-	return printer{maxLineWidth: maxLineWidth}
+// separator is the comma after item i of n items printed one per line.
+func (p printer) separator(i int, n int) string {
+	if i == n-1 && !p.trailingCommas {
+		return ""
+	}
+	return ","
+}
+
+// trailingComma is the comma after the last item of a comma-separated group,
+// printed only when the group breaks across lines.
+func (p printer) trailingComma() doc {
+	if !p.trailingCommas {
+		return dText("")
+	}
+	return dIfBreak(dText(","), dText(""))
 }
 
 func (p printer) program(program *parse.Program) string {
@@ -26,8 +47,14 @@ func (p printer) program(program *parse.Program) string {
 	}
 
 	lines := make([]string, 0)
+	for _, pragma := range program.Pragmas {
+		lines = append(lines, pragma.String())
+	}
 	importLines := p.renderImports(program.Imports)
 	if len(importLines) > 0 {
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
 		lines = append(lines, importLines...)
 	}
 
@@ -64,11 +91,21 @@ func (p printer) renderImports(imports []parse.Import) []string {
 	}
 	for _, key := range []int{0, 1, 2} {
 		sort.Slice(groups[key], func(i, j int) bool {
-			return groups[key][i].Path < groups[key][j].Path
+			left, right := groups[key][i], groups[key][j]
+			if left.Path != right.Path {
+				return left.Path < right.Path
+			}
+			if left.ReExport != right.ReExport {
+				return left.ReExport < right.ReExport
+			}
+			return left.Name < right.Name
 		})
 	}
 
+	// Imports that render the same are duplicates, e.g. `use ard/list` and
+	// `use ard/list as list`, and are printed once.
 	lines := make([]string, 0, len(imports)+2)
+	seen := map[string]bool{}
 	for _, key := range []int{0, 1, 2} {
 		if len(groups[key]) == 0 {
 			continue
@@ -77,7 +114,12 @@ func (p printer) renderImports(imports []parse.Import) []string {
 			lines = append(lines, "")
 		}
 		for _, item := range groups[key] {
-			lines = append(lines, p.renderImport(item))
+			line := p.renderImport(item)
+			if seen[line] {
+				continue
+			}
+			seen[line] = true
+			lines = append(lines, line)
 		}
 	}
 
@@ -86,6 +128,9 @@ func (p printer) renderImports(imports []parse.Import) []string {
 
 func (p printer) renderImport(item parse.Import) string {
 	path := renderImportPath(item)
+	if item.ReExport != "" {
+		return fmt.Sprintf("pub use %s", path)
+	}
 	defaultName := defaultImportName(item.Path)
 	if item.Name != "" && item.Name != defaultName {
 		return fmt.Sprintf("use %s as %s", path, item.Name)
@@ -97,10 +142,18 @@ func renderImportPath(item parse.Import) string {
 	if item.Kind == parse.ImportKindGo {
 		return "go:" + item.Path
 	}
+	if item.ReExport != "" {
+		return item.Path + "::" + item.ReExport
+	}
 	return item.Path
 }
 
+// importGroup orders standard library imports first, then other imports,
+// then `pub use` re-exports.
 func importGroup(item parse.Import) int {
+	if item.ReExport != "" {
+		return 2
+	}
 	if strings.HasPrefix(item.Path, "ard/") {
 		return 0
 	}
@@ -130,14 +183,16 @@ func (p printer) renderStatementDoc(statement parse.Statement) doc {
 		return p.renderVariableDeclarationDoc(node)
 	case *parse.VariableAssignment:
 		return p.renderVariableAssignmentDoc(node)
+	case *parse.Assert:
+		return p.renderAssertDoc(node)
 	case *parse.Defer:
 		return p.renderDeferDoc(node)
 	case *parse.FunctionDeclaration:
-		return p.renderFunctionDeclarationDoc(node, false)
+		return p.renderDeprecatedDoc(node.Deprecated, p.renderFunctionDeclarationDoc(node, false))
 	case *parse.StaticFunctionDeclaration:
-		return p.renderStaticFunctionDeclarationDoc(node)
+		return p.renderDeprecatedDoc(node.Deprecated, p.renderStaticFunctionDeclarationDoc(node))
 	case *parse.StructDefinition:
-		return p.renderStructDefinitionDoc(node)
+		return p.renderDeprecatedDoc(node.Deprecated, p.renderStructDefinitionDoc(node))
 	case *parse.TraitDefinition:
 		return p.renderTraitDefinitionDoc(node)
 	case *parse.ImplBlock:
@@ -168,6 +223,14 @@ func (p printer) renderStatementDoc(statement parse.Statement) doc {
 	}
 }
 
+func (p printer) renderAssertDoc(node *parse.Assert) doc {
+	text := node.Keyword() + " " + p.renderExpression(node.Condition, 0)
+	if node.Message != nil {
+		text += ", " + p.renderExpression(node.Message, 0)
+	}
+	return dText(text)
+}
+
 func (p printer) renderDeferDoc(node *parse.Defer) doc {
 	if node.Expr != nil {
 		return dConcat(dText("defer "), dText(p.renderExpression(node.Expr, 0)))
@@ -184,12 +247,20 @@ func (p printer) renderVariableDeclarationDoc(node *parse.VariableDeclaration) d
 	binding := "let"
 	if node.Mutable {
 		binding = "mut"
+	} else if node.Const {
+		binding = "const"
+	}
+	if node.Private {
+		binding = "private " + binding
 	}
 	prefix := binding + " " + node.Name
 	if node.Type != nil {
 		prefix += ": " + p.renderType(node.Type)
 	}
 	prefix += " = "
+	if node.Else != nil {
+		return p.renderShortBlockDoc(prefix+p.renderExpression(node.Value, 0)+" else", node.Else)
+	}
 	return dConcat(dText(prefix), p.renderExpressionValueDoc(node.Value, 0))
 }
 
@@ -230,11 +301,15 @@ func (p printer) assignmentParts(node *parse.VariableAssignment) (string, parse.
 	if node.Operator == parse.Assign {
 		if bin, ok := node.Value.(*parse.BinaryExpression); ok {
 			if sameLocation(bin.Left, node.Target) {
-				if bin.Operator == parse.Plus {
+				switch bin.Operator {
+				case parse.Plus:
 					return "=+", bin.Right
-				}
-				if bin.Operator == parse.Minus {
+				case parse.Minus:
 					return "=-", bin.Right
+				case parse.Multiply:
+					return "=*", bin.Right
+				case parse.Divide:
+					return "=/", bin.Right
 				}
 			}
 		}
@@ -249,6 +324,15 @@ func sameLocation(left parse.Expression, right parse.Expression) bool {
 	return l.Start.Row == r.Start.Row && l.Start.Col == r.Start.Col && l.End.Row == r.End.Row && l.End.Col == r.End.Col
 }
 
+// renderDeprecatedDoc puts a declaration's `@deprecated` attribute on its own
+// line above it.
+func (p printer) renderDeprecatedDoc(deprecation *parse.Deprecation, declaration doc) doc {
+	if deprecation == nil {
+		return declaration
+	}
+	return dConcat(dText("@deprecated("+quoteArdString(deprecation.Note)+")"), dHardLine(), declaration)
+}
+
 func (p printer) renderFunctionDeclarationDoc(node *parse.FunctionDeclaration, traitSignatureOnly bool) doc {
 	prefix := ""
 	if node.Private {
@@ -325,8 +409,19 @@ func (p printer) renderStructDefinitionDoc(node *parse.StructDefinition) doc {
 		if fieldEnd <= 0 {
 			fieldEnd = field.Name.Location.End.Row
 		}
+		fieldPrefix := ""
+		if field.Private {
+			fieldPrefix = "private "
+		}
+		fieldText := fmt.Sprintf("%s%s: %s", fieldPrefix, field.Name.Name, p.renderType(field.Type))
+		if field.Default != nil {
+			fieldText += " = " + p.renderExpression(field.Default, 0)
+			if end := field.Default.GetLocation().End.Row; end > fieldEnd {
+				fieldEnd = end
+			}
+		}
 		items = append(items, structItem{
-			doc:      dText(fmt.Sprintf("%s: %s,", field.Name.Name, p.renderType(field.Type))),
+			doc:      dText(fieldText + ","),
 			startRow: field.Name.Location.Start.Row,
 			endRow:   fieldEnd,
 		})
@@ -375,10 +470,18 @@ func (p printer) renderEnumDefinitionDoc(node *parse.EnumDefinition) doc {
 		items = append(items, dText(p.renderComment(comment.Value)))
 	}
 	for _, variant := range node.Variants {
+		name := variant.Name
+		if len(variant.Payload) > 0 {
+			payload := make([]string, len(variant.Payload))
+			for i, t := range variant.Payload {
+				payload[i] = p.renderType(t)
+			}
+			name += "(" + strings.Join(payload, ", ") + ")"
+		}
 		if variant.Value == nil {
-			items = append(items, dText(variant.Name+","))
+			items = append(items, dText(name+","))
 		} else {
-			items = append(items, dText(fmt.Sprintf("%s = %s,", variant.Name, p.renderExpression(variant.Value, 0))))
+			items = append(items, dText(fmt.Sprintf("%s = %s,", name, p.renderExpression(variant.Value, 0))))
 		}
 	}
 	body := dJoin(dHardLine(), items)
@@ -469,7 +572,7 @@ func (p printer) interleaveMethodDocs(methods []parse.FunctionDeclaration, comme
 		if lastKind == "method" {
 			items = append(items, dText(""))
 		}
-		items = append(items, p.renderFunctionDeclarationDoc(&method, traitSignatureOnly))
+		items = append(items, p.renderDeprecatedDoc(method.Deprecated, p.renderFunctionDeclarationDoc(&method, traitSignatureOnly)))
 		lastKind = "method"
 
 	}
@@ -525,6 +628,9 @@ func (p printer) renderTypeDeclarationDoc(node *parse.TypeDeclaration) doc {
 
 func (p printer) renderWhileLoopDoc(node *parse.WhileLoop) doc {
 	header := "while"
+	if node.Let != nil {
+		header += " let " + node.Let.Name + " ="
+	}
 	if node.Condition != nil {
 		header += " " + p.renderExpression(node.Condition, 0)
 	}
@@ -537,6 +643,9 @@ func (p printer) renderRangeLoopDoc(node *parse.RangeLoop) doc {
 		header += fmt.Sprintf(", %s", node.Cursor2.Name)
 	}
 	header += fmt.Sprintf(" in %s..%s", p.renderExpression(node.Start, 0), p.renderExpression(node.End, 0))
+	if node.Step != nil {
+		header += " by " + p.renderExpression(node.Step, 0)
+	}
 	return p.renderBlockDoc(header, node.Body)
 }
 
@@ -582,7 +691,11 @@ func (p printer) renderIfStatementDoc(node *parse.IfStatement) doc {
 		return p.renderBlockDoc("else", node.Body)
 	}
 
-	head := "if " + p.renderExpression(node.Condition, 0)
+	head := "if "
+	if node.Let != nil {
+		head += "let " + node.Let.Name + " = "
+	}
+	head += p.renderExpression(node.Condition, 0)
 	current := p.renderBlockDoc(head, node.Body)
 	if node.Else == nil {
 		return current
@@ -654,7 +767,7 @@ func (p printer) renderStatementsDoc(statements []parse.Statement) doc {
 }
 
 func (p printer) renderDocAtIndent(document doc, indent int) []string {
-	rendered := p.printDocAtColumn(document, indent*indentWidth)
+	rendered := p.printDocAtColumn(document, indent*p.indentWidth)
 	if rendered == "" {
 		return nil
 	}
@@ -696,8 +809,8 @@ func (p printer) renderParameterList(params []parse.Parameter, indent int, heade
 	}
 
 	lines := []string{"("}
-	for _, part := range parts {
-		lines = append(lines, p.indent(indent+1)+part+",")
+	for i, part := range parts {
+		lines = append(lines, p.indent(indent+1)+part+p.separator(i, len(parts)))
 	}
 	lines = append(lines, p.indent(indent)+")")
 	return strings.Join(lines, "\n")
@@ -770,6 +883,24 @@ func (p printer) renderType(declared parse.DeclaredType) string {
 			return "(" + name + ")?"
 		}
 		return maybeNullable(name, node.IsNullable())
+	case *parse.UnionType:
+		members := make([]string, 0, len(node.Members))
+		for _, member := range node.Members {
+			rendered := p.renderType(member)
+			// These members would otherwise absorb the `| ...` that follows.
+			switch member.(type) {
+			case *parse.UnionType, *parse.ResultType, *parse.FunctionType, *parse.MutableType:
+				if !member.IsNullable() {
+					rendered = "(" + rendered + ")"
+				}
+			}
+			members = append(members, rendered)
+		}
+		name := strings.Join(members, " | ")
+		if node.IsNullable() {
+			return "(" + name + ")?"
+		}
+		return name
 	default:
 		return declared.GetName()
 	}
@@ -801,6 +932,8 @@ func renderableExpressionStatement(statement parse.Statement) (parse.Expression,
 		*parse.BinaryExpression, parse.BinaryExpression,
 		*parse.ChainedComparison,
 		*parse.RangeExpression, parse.RangeExpression,
+		*parse.PipeExpression,
+		*parse.AsExpression,
 		*parse.ListLiteral, parse.ListLiteral,
 		*parse.MapLiteral, parse.MapLiteral,
 		*parse.StructInstance, parse.StructInstance,
@@ -837,9 +970,9 @@ func (p printer) renderExpressionDoc(expression parse.Expression, parentPreceden
 	case parse.Identifier:
 		return dText(node.Name)
 	case *parse.StrLiteral:
-		return dText(quoteArdString(node.Value))
+		return dText(quoteStrLiteral(*node))
 	case parse.StrLiteral:
-		return dText(quoteArdString(node.Value))
+		return dText(quoteStrLiteral(node))
 	case *parse.RuneLiteral:
 		return dText(quoteArdRune(node.Value))
 	case parse.RuneLiteral:
@@ -894,6 +1027,10 @@ func (p printer) renderExpressionDoc(expression parse.Expression, parentPreceden
 		return dConcat(p.renderExpressionDoc(node.Start, precedenceCompare), dText(".."), p.renderExpressionDoc(node.End, precedenceCompare))
 	case parse.RangeExpression:
 		return dConcat(p.renderExpressionDoc(node.Start, precedenceCompare), dText(".."), p.renderExpressionDoc(node.End, precedenceCompare))
+	case *parse.PipeExpression:
+		return p.renderPipeDoc(node, parentPrecedence)
+	case *parse.AsExpression:
+		return dText(p.renderAs(node, parentPrecedence))
 	case *parse.ListLiteral:
 		return p.renderListLiteralDoc(node)
 	case parse.ListLiteral:
@@ -909,6 +1046,14 @@ func (p printer) renderExpressionDoc(expression parse.Expression, parentPreceden
 	case parse.StructInstance:
 		copy := node
 		return p.renderStructInstanceDoc(&copy)
+	case *parse.StructPattern:
+		return dText(p.renderStructPattern(node))
+	case *parse.OrPattern:
+		patterns := make([]string, 0, len(node.Patterns))
+		for _, pattern := range node.Patterns {
+			patterns = append(patterns, p.renderExpression(pattern, 0))
+		}
+		return dText(strings.Join(patterns, " | "))
 	case *parse.FunctionCall:
 		return p.renderFunctionCallDoc(node)
 	case parse.FunctionCall:
@@ -951,6 +1096,8 @@ func (p printer) renderExpressionDoc(expression parse.Expression, parentPreceden
 		return p.renderExpressionDoc(&copy, parentPrecedence)
 	case *parse.MatchExpression:
 		return p.renderMatchExpressionDoc(node)
+	case *parse.IfStatement:
+		return p.renderIfStatementDoc(node)
 	case *parse.SelectExpression:
 		return p.renderSelectExpressionDoc(node)
 	case *parse.ConditionalMatchExpression:
@@ -958,6 +1105,11 @@ func (p printer) renderExpressionDoc(expression parse.Expression, parentPreceden
 	case *parse.AnonymousFunction:
 		return p.renderAnonymousFunctionDoc(node)
 	case *parse.Try:
+		// a receiver keeps its parentheses, or `(try x).or(y)` would become
+		// `try x.or(y)`
+		if parentPrecedence >= precedenceCall {
+			return dConcat(dText("("), p.renderTryDoc(node), dText(")"))
+		}
 		return p.renderTryDoc(node)
 	case *parse.BlockExpression:
 		return p.renderBlockExpressionDoc(node)
@@ -990,11 +1142,17 @@ func (p printer) renderTryDoc(node *parse.Try) doc {
 		return dText(prefix)
 	}
 	prefix += " -> " + node.CatchVar.Name
-	if len(node.CatchBlock) == 0 {
+	return p.renderShortBlockDoc(prefix, node.CatchBlock)
+}
+
+// renderShortBlockDoc renders `prefix { ... }`, keeping a block of a single
+// expression on one line when it fits.
+func (p printer) renderShortBlockDoc(prefix string, statements []parse.Statement) doc {
+	if len(statements) == 0 {
 		return dText(prefix + " {}")
 	}
-	if len(node.CatchBlock) == 1 {
-		if expr, ok := renderableExpressionStatement(node.CatchBlock[0]); ok {
+	if len(statements) == 1 {
+		if expr, ok := renderableExpressionStatement(statements[0]); ok {
 			rendered := p.renderExpression(expr, 0)
 			if !strings.Contains(rendered, "\n") {
 				oneLine := prefix + " { " + rendered + " }"
@@ -1006,7 +1164,7 @@ func (p printer) renderTryDoc(node *parse.Try) doc {
 	}
 	return dGroup(dConcat(
 		dText(prefix+" {"),
-		dIndent(dConcat(dHardLine(), p.renderStatementsDoc(node.CatchBlock))),
+		dIndent(dConcat(dHardLine(), p.renderStatementsDoc(statements))),
 		dHardLine(),
 		dText("}"),
 	))
@@ -1067,6 +1225,38 @@ func escapeInterpolatedText(value string) string {
 	return escapeArdBraces(quoted)
 }
 
+func quoteStrLiteral(node parse.StrLiteral) string {
+	if node.Template {
+		return quoteFormatTemplate(node.Value)
+	}
+	return quoteArdString(node.Value)
+}
+
+// quoteFormatTemplate quotes the raw template of a Str::format call. Its
+// placeholders stay as written, and the doubled braces that stand for
+// literal braces are escaped so they are not read as interpolations.
+func quoteFormatTemplate(value string) string {
+	quoted := strconv.Quote(value)
+	inner := quoted[1 : len(quoted)-1]
+	var builder strings.Builder
+	inPlaceholder := false
+	for i := 0; i < len(inner); i++ {
+		ch := inner[i]
+		switch {
+		case inPlaceholder:
+			inPlaceholder = ch != '}'
+		case (ch == '{' || ch == '}') && i+1 < len(inner) && inner[i+1] == ch:
+			builder.WriteString(`\` + string(ch) + `\` + string(ch))
+			i++
+			continue
+		case ch == '{':
+			inPlaceholder = true
+		}
+		builder.WriteByte(ch)
+	}
+	return `"` + builder.String() + `"`
+}
+
 func quoteArdString(value string) string {
 	quoted := strconv.Quote(value)
 	if len(quoted) < 2 {
@@ -1130,7 +1320,7 @@ func (p printer) renderListLiteralDoc(list *parse.ListLiteral) doc {
 		itemDocs = append(itemDocs, dText(item))
 	}
 	body := dJoin(dConcat(dText(","), dLine()), itemDocs)
-	body = dConcat(body, dIfBreak(dText(","), dText("")))
+	body = dConcat(body, p.trailingComma())
 
 	if len(list.Comments) > 0 {
 		commentDocs := make([]doc, 0, len(list.Comments))
@@ -1163,7 +1353,7 @@ func (p printer) renderMapLiteralDoc(m *parse.MapLiteral) doc {
 		partDocs = append(partDocs, dText(part))
 	}
 	body := dJoin(dConcat(dText(","), dLine()), partDocs)
-	body = dConcat(body, dIfBreak(dText(","), dText("")))
+	body = dConcat(body, p.trailingComma())
 	if len(m.Comments) > 0 {
 		commentDocs := make([]doc, 0, len(m.Comments))
 		for _, comment := range m.Comments {
@@ -1180,6 +1370,18 @@ func (p printer) renderMapLiteralDoc(m *parse.MapLiteral) doc {
 	))
 }
 
+func (p printer) renderStructPattern(node *parse.StructPattern) string {
+	fields := make([]string, 0, len(node.Fields))
+	for _, field := range node.Fields {
+		if field.Pattern == nil {
+			fields = append(fields, field.Name.Name)
+			continue
+		}
+		fields = append(fields, field.Name.Name+": "+p.renderExpression(field.Pattern, 0))
+	}
+	return node.Name.Name + "{" + strings.Join(fields, ", ") + "}"
+}
+
 func (p printer) renderStructInstanceDoc(node *parse.StructInstance) doc {
 	head := node.Name.Name
 	if len(node.TypeArgs) > 0 {
@@ -1189,16 +1391,19 @@ func (p printer) renderStructInstanceDoc(node *parse.StructInstance) doc {
 		}
 		head += "<" + strings.Join(types, ", ") + ">"
 	}
-	if len(node.Properties) == 0 && len(node.Comments) == 0 {
+	if len(node.Properties) == 0 && len(node.Comments) == 0 && node.Base == nil {
 		return dText(head + "{}")
 	}
 
-	parts := make([]string, 0, len(node.Properties))
+	parts := make([]string, 0, len(node.Properties)+1)
+	if node.Base != nil {
+		parts = append(parts, ".."+p.renderExpression(node.Base, 0))
+	}
 	for _, property := range node.Properties {
 		parts = append(parts, property.Name.Name+": "+p.renderExpression(property.Value, 0))
 	}
 	oneLine := head + "{" + strings.Join(parts, ", ") + "}"
-	if len(node.Properties) <= 2 && len(node.Comments) == 0 && len(oneLine) <= p.maxLineWidth {
+	if len(parts) <= 2 && len(node.Comments) == 0 && len(oneLine) <= p.maxLineWidth {
 		return dText(oneLine)
 	}
 
@@ -1206,8 +1411,8 @@ func (p printer) renderStructInstanceDoc(node *parse.StructInstance) doc {
 	for _, comment := range node.Comments {
 		items = append(items, dText(p.renderComment(comment.Value)))
 	}
-	for _, part := range parts {
-		items = append(items, dText(part+","))
+	for i, part := range parts {
+		items = append(items, dText(part+p.separator(i, len(parts))))
 	}
 	body := dJoin(dHardLine(), items)
 
@@ -1249,7 +1454,7 @@ func (p printer) renderCallDoc(head string, typeArgs []parse.DeclaredType, args
 	}
 
 	body := dJoin(dConcat(dText(","), dLine()), argDocs)
-	body = dConcat(body, dIfBreak(dText(","), dText("")))
+	body = dConcat(body, p.trailingComma())
 
 	if len(comments) > 0 {
 		commentDocs := make([]doc, 0, len(comments))
@@ -1471,6 +1676,7 @@ const (
 	precedenceCompare
 	precedenceAdd
 	precedenceMul
+	precedenceAs
 	precedenceUnary
 	precedenceCall
 )
@@ -1495,6 +1701,14 @@ func (p printer) renderUnary(node *parse.UnaryExpression, parentPrecedence int)
 	return text
 }
 
+func (p printer) renderAs(node *parse.AsExpression, parentPrecedence int) string {
+	text := p.renderExpression(node.Value, precedenceAs) + " as " + p.renderType(node.Type)
+	if precedenceAs < parentPrecedence {
+		return "(" + text + ")"
+	}
+	return text
+}
+
 func (p printer) renderBinary(node *parse.BinaryExpression, parentPrecedence int) string {
 	precedence := p.binaryPrecedence(node.Operator)
 	left := p.renderExpression(node.Left, precedence)
@@ -1530,6 +1744,30 @@ func (p printer) renderBinary(node *parse.BinaryExpression, parentPrecedence int
 	return text
 }
 
+// renderPipeDoc keeps a pipeline on one line when it fits and otherwise puts
+// each `|> call` stage on its own indented line.
+func (p printer) renderPipeDoc(node *parse.PipeExpression, parentPrecedence int) doc {
+	stages := []doc{}
+	value := parse.Expression(node)
+	for {
+		pipe, ok := value.(*parse.PipeExpression)
+		if !ok {
+			break
+		}
+		stages = append([]doc{dConcat(dLine(), dText("|> "), p.renderExpressionDoc(pipe.Call, precedenceOr))}, stages...)
+		value = pipe.Value
+	}
+	first := p.renderExpressionDoc(value, precedenceOr)
+	if isTryExpression(value) {
+		first = dConcat(dText("("), first, dText(")"))
+	}
+	pipeline := dGroup(dConcat(first, dIndent(dConcat(stages...))))
+	if parentPrecedence > precedenceLowest {
+		return dConcat(dText("("), pipeline, dText(")"))
+	}
+	return pipeline
+}
+
 func isTryExpression(expression parse.Expression) bool {
 	switch expression.(type) {
 	case *parse.Try, parse.Try:
@@ -1624,7 +1862,7 @@ func (p printer) indent(level int) string {
 	if level <= 0 {
 		return ""
 	}
-	return strings.Repeat(" ", level*indentWidth)
+	return strings.Repeat(" ", level*p.indentWidth)
 }
 
 func isMutRefExpression(expression parse.Expression) bool {
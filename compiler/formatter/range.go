@@ -0,0 +1,119 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akonwi/ard/parse"
+)
+
+// FormatRange formats the top-level statements overlapping lines startLine
+// through endLine (1-based, inclusive) and returns src with every byte outside
+// those statements unchanged. Statements are formatted whole, so a selection
+// inside a function reformats the entire function. Imports and pragmas are
+// never touched, because unused imports can only be judged for the whole file.
+func FormatRange(src []byte, startLine int, endLine int, options ...Options) ([]byte, error) {
+	if startLine < 1 || endLine < startLine {
+		return src, fmt.Errorf("invalid line range %d-%d", startLine, endLine)
+	}
+
+	result := parse.Parse(src, "")
+	if len(result.Errors) > 0 {
+		lines := make([]string, 0, len(result.Errors))
+		for _, err := range result.Errors {
+			lines = append(lines, fmt.Sprintf("%s %s", err.Location.Start, err.Message))
+		}
+		return src, fmt.Errorf("cannot format invalid Ard source:\n%s", strings.Join(lines, "\n"))
+	}
+
+	first, last, ok := statementRows(result.Program.Statements, startLine, endLine)
+	if !ok {
+		return src, nil
+	}
+
+	start, end := lineOffsets(src, first, last)
+	span := string(src[start:end])
+	spanResult := parse.Parse([]byte(span), "")
+	if len(spanResult.Errors) > 0 {
+		// The span parsed in context but not on its own; leave it alone
+		// rather than guess.
+		return src, nil
+	}
+	printer := newPrinter(normalizeOptions(options))
+	formatted := normalizeWhitespace(printer.program(spanResult.Program))
+	if !strings.HasSuffix(span, "\n") {
+		formatted = strings.TrimSuffix(formatted, "\n")
+	}
+
+	out := make([]byte, 0, len(src)-len(span)+len(formatted))
+	out = append(out, src[:start]...)
+	out = append(out, formatted...)
+	out = append(out, src[end:]...)
+	return out, nil
+}
+
+// statementRows returns the rows spanned by the statements overlapping
+// startLine through endLine, widened until no statement straddles either end.
+func statementRows(statements []parse.Statement, startLine int, endLine int) (int, int, bool) {
+	first, last := 0, 0
+	found := false
+	for _, statement := range statements {
+		if statement == nil {
+			continue
+		}
+		location := statement.GetLocation()
+		if location.Start.Row > endLine || location.End.Row < startLine {
+			continue
+		}
+		if !found || location.Start.Row < first {
+			first = location.Start.Row
+		}
+		if !found || location.End.Row > last {
+			last = location.End.Row
+		}
+		found = true
+	}
+	for changed := found; changed; {
+		changed = false
+		for _, statement := range statements {
+			if statement == nil {
+				continue
+			}
+			location := statement.GetLocation()
+			if location.Start.Row > last || location.End.Row < first {
+				continue
+			}
+			if location.Start.Row < first {
+				first, changed = location.Start.Row, true
+			}
+			if location.End.Row > last {
+				last, changed = location.End.Row, true
+			}
+		}
+	}
+	return first, last, found
+}
+
+// lineOffsets returns the byte offsets of the start of line first and the end
+// of line last, including its newline.
+func lineOffsets(src []byte, first int, last int) (int, int) {
+	start, end := len(src), len(src)
+	row := 1
+	if first == 1 {
+		start = 0
+	}
+	for i, b := range src {
+		if b != '\n' {
+			continue
+		}
+		if row == last {
+			end = i + 1
+			break
+		}
+		row++
+		if row == first {
+			start = i + 1
+		}
+	}
+	return start, end
+}
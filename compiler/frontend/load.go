@@ -2,6 +2,7 @@ package frontend
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -13,21 +14,37 @@ import (
 type LoadResult struct {
 	Module      checker.Module
 	ProjectInfo *checker.ProjectInfo
+	// Spans is the entry module's span index. It is empty unless
+	// LoadOptions.RecordSpans was set.
+	Spans *checker.SpanIndex
 }
 
-func LoadModule(inputPath string) (*LoadResult, error) {
+type LoadOptions struct {
+	// RecordSpans keeps the checker's resolved source spans for the entry
+	// module, for tooling such as `ard check --types`.
+	RecordSpans bool
+	// Strict reports unused code and unreachable statements in the entry
+	// module as warnings, for `ard check --strict`.
+	Strict bool
+	// JSON writes the diagnostics to stdout as a single JSON array instead
+	// of rendering them, for `ard check --json`. The array is written even
+	// when it is empty.
+	JSON bool
+	// GoEnv holds extra environment entries for resolving Go imports, such
+	// as GOOS=js and GOARCH=wasm when building for the browser.
+	GoEnv []string
+}
+
+func LoadModule(inputPath string, options ...LoadOptions) (*LoadResult, error) {
+	var opts LoadOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
 	sourceCode, err := os.ReadFile(inputPath)
 	if err != nil {
 		return nil, fmt.Errorf("error reading file %s - %v", inputPath, err)
 	}
 
-	result := parse.Parse(sourceCode, inputPath)
-	if len(result.Errors) > 0 {
-		result.PrintErrors()
-		return nil, fmt.Errorf("parse errors")
-	}
-	program := result.Program
-
 	workingDir := filepath.Dir(inputPath)
 	moduleResolver, err := checker.NewModuleResolver(workingDir)
 	if err != nil {
@@ -45,25 +62,60 @@ func LoadModule(inputPath string) (*LoadResult, error) {
 		}
 	}
 
+	displayRoot, err := os.Getwd()
+	if err != nil {
+		displayRoot = projectInfo.RootPath
+	}
+
+	report := func(w io.Writer, found []checker.Diagnostic) error {
+		render := diagnostics.RenderRelative
+		if opts.JSON {
+			w, render = os.Stdout, diagnostics.RenderRelativeJSON
+		}
+		if err := render(w, found, projectInfo.RootPath, displayRoot); err != nil {
+			return fmt.Errorf("render diagnostics: %w", err)
+		}
+		return nil
+	}
+
+	result := parse.Parse(sourceCode, inputPath)
+	deprecations := checker.DeprecatedSyntaxDiagnostics(relPath, result.AllowLegacy(projectInfo.LanguageVersion()))
+	if len(result.Errors) > 0 {
+		if err := report(os.Stdout, checker.ParseErrorDiagnostics(relPath, result.Errors)); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("parse errors")
+	}
+	program := result.Program
+	if program.HasPragma("deny", "warnings") {
+		// the checker reports them as errors for this module
+		deprecations = nil
+	}
+
 	// The checker primes the resolver with the program's whole Go import
 	// closure before binding imports, so all Go types share a single
 	// go/types universe (ADR 0044).
 	goResolver := checker.NewGoPackagesResolver(projectInfo.RootPath, projectInfo.Go.BuildTags)
-	c := checker.New(relPath, program, moduleResolver, checker.CheckOptions{GoResolver: goResolver})
+	goResolver.Env = opts.GoEnv
+	c := checker.New(relPath, program, moduleResolver, checker.CheckOptions{GoResolver: goResolver, RecordSpans: opts.RecordSpans, Strict: opts.Strict})
 	c.Check()
 	if c.HasErrors() {
-		displayRoot, err := os.Getwd()
-		if err != nil {
-			displayRoot = projectInfo.RootPath
-		}
-		if err := diagnostics.RenderRelative(os.Stdout, c.Diagnostics(), projectInfo.RootPath, displayRoot); err != nil {
-			return nil, fmt.Errorf("render diagnostics: %w", err)
+		if err := report(os.Stdout, append(deprecations, c.Diagnostics()...)); err != nil {
+			return nil, err
 		}
 		return nil, fmt.Errorf("type errors")
 	}
+	// Deprecations and other warnings alone do not stop the pipeline, so they
+	// go to stderr to keep a program's own output clean.
+	if warnings := append(deprecations, c.Warnings()...); len(warnings) > 0 || opts.JSON {
+		if err := report(os.Stderr, warnings); err != nil {
+			return nil, err
+		}
+	}
 
 	return &LoadResult{
 		Module:      c.Module(),
 		ProjectInfo: projectInfo,
+		Spans:       c.Spans(),
 	}, nil
 }
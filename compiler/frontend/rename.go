@@ -0,0 +1,280 @@
+package frontend
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/diagnostics"
+	"github.com/akonwi/ard/parse"
+)
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RenameResult is a verified rename: the rewritten source of every file that
+// names the declaration. Nothing is written until Write is called.
+type RenameResult struct {
+	OldName string
+	NewName string
+	// Files maps each changed file's path to its rewritten source.
+	Files map[string][]byte
+}
+
+// Rename renames the declaration named at position at in the program rooted
+// at inputPath to newName, rewriting its definition and every reference in
+// the module graph. The rewritten program is checked before the result is
+// returned, so a rename that would introduce errors fails instead.
+func Rename(inputPath string, at parse.Point, newName string) (*RenameResult, error) {
+	if !identifierPattern.MatchString(newName) {
+		return nil, fmt.Errorf("%q is not a valid name", newName)
+	}
+	loaded, err := LoadModule(inputPath, LoadOptions{RecordSpans: true})
+	if err != nil {
+		return nil, err
+	}
+	root := loaded.ProjectInfo.RootPath
+	entry, ok := loaded.Module.(*checker.UserModule)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ard module", inputPath)
+	}
+
+	target, ok := referenceAt(entry, inputPath, at)
+	if !ok {
+		return nil, fmt.Errorf("no renameable name at %s:%d:%d", inputPath, at.Row, at.Col)
+	}
+	if target.Name == newName {
+		return nil, fmt.Errorf("%s is already named %s", target.Name, newName)
+	}
+
+	modules := moduleGraph(entry)
+	var refs []checker.Reference
+	defined := false
+	for _, module := range modules {
+		found := module.References(target.Key)
+		for _, ref := range found {
+			if !ref.IsDef {
+				continue
+			}
+			defined = true
+			if relative, err := filepath.Rel(root, diskPath(root, ref.FilePath)); err != nil || strings.HasPrefix(relative, "..") {
+				return nil, fmt.Errorf("cannot rename %s: it is declared outside this project", target.Name)
+			}
+			if isModuleLevel(target.Key) && declares(module, newName) {
+				return nil, fmt.Errorf("cannot rename %s: %s already declares %s", target.Name, module.Path(), newName)
+			}
+		}
+		refs = append(refs, found...)
+	}
+	if !defined {
+		return nil, fmt.Errorf("cannot rename %s: it is not declared in this project", target.Name)
+	}
+
+	edits := map[string][]parse.Location{}
+	sources := map[string][]byte{}
+	for _, ref := range refs {
+		path := diskPath(root, ref.FilePath)
+		if _, ok := sources[path]; !ok {
+			source, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("error reading file %s - %w", path, err)
+			}
+			sources[path] = source
+		}
+		loc, ok := ref.NameLocation(sourceLine(sources[path], ref.Location.Start.Row))
+		if !ok {
+			continue
+		}
+		edits[path] = append(edits[path], loc)
+	}
+
+	result := &RenameResult{OldName: target.Name, NewName: newName, Files: map[string][]byte{}}
+	for path, locs := range edits {
+		result.Files[path] = replaceAll(sources[path], locs, newName)
+	}
+	if err := verifyRename(inputPath, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Write saves every rewritten file and returns their paths in order.
+func (r *RenameResult) Write() ([]string, error) {
+	paths := make([]string, 0, len(r.Files))
+	for path := range r.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file info %s - %w", path, err)
+		}
+		if err := os.WriteFile(path, r.Files[path], info.Mode()); err != nil {
+			return nil, fmt.Errorf("error writing file %s - %w", path, err)
+		}
+	}
+	return paths, nil
+}
+
+// referenceAt finds the reference whose name covers at. Definitions of
+// functions and types span their whole body, so only the name itself counts.
+func referenceAt(module *checker.UserModule, inputPath string, at parse.Point) (checker.Reference, bool) {
+	key, ok := module.SymbolAt(at)
+	if !ok {
+		return checker.Reference{}, false
+	}
+	source, err := os.ReadFile(inputPath)
+	if err != nil {
+		return checker.Reference{}, false
+	}
+	for _, ref := range module.References(key) {
+		if ref.Location.Start.Row != at.Row {
+			continue
+		}
+		loc, ok := ref.NameLocation(sourceLine(source, at.Row))
+		if ok && loc.Start.Col <= at.Col && at.Col < loc.End.Col {
+			return ref, true
+		}
+	}
+	return checker.Reference{}, false
+}
+
+// moduleGraph returns the user modules reachable from entry, entry first.
+func moduleGraph(entry *checker.UserModule) []*checker.UserModule {
+	seen := map[*checker.UserModule]bool{}
+	var modules []*checker.UserModule
+	var visit func(module *checker.UserModule)
+	visit = func(module *checker.UserModule) {
+		if seen[module] {
+			return
+		}
+		seen[module] = true
+		modules = append(modules, module)
+		imports := make([]string, 0, len(module.Program().Imports))
+		for path := range module.Program().Imports {
+			imports = append(imports, path)
+		}
+		sort.Strings(imports)
+		for _, path := range imports {
+			if imported, ok := module.Program().Imports[path].(*checker.UserModule); ok {
+				visit(imported)
+			}
+		}
+	}
+	visit(entry)
+	return modules
+}
+
+// diskPath maps a reference's file path to the file on disk. The entry
+// module's references carry its project-relative path and imported modules'
+// their resolved one.
+func diskPath(root, path string) string {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path)
+	}
+	return filepath.Join(root, path)
+}
+
+func isModuleLevel(key string) bool {
+	return strings.HasPrefix(key, "fn:") || strings.HasPrefix(key, "type:") || strings.HasPrefix(key, "val:")
+}
+
+// declares reports whether module already has a top-level declaration named
+// name. Duplicate functions are not diagnosed, so this can't be left to the
+// check of the rewritten program.
+func declares(module *checker.UserModule, name string) bool {
+	return module.Get(name).Name != "" || module.IsPrivate(name)
+}
+
+func sourceLine(source []byte, row int) string {
+	lines := bytes.Split(source, []byte("\n"))
+	if row < 1 || row > len(lines) {
+		return ""
+	}
+	return string(lines[row-1])
+}
+
+// replaceAll replaces each single-line location, whose End is exclusive, with
+// name.
+func replaceAll(source []byte, locs []parse.Location, name string) []byte {
+	lines := strings.Split(string(source), "\n")
+	sort.Slice(locs, func(i, j int) bool {
+		if locs[i].Start.Row != locs[j].Start.Row {
+			return locs[i].Start.Row > locs[j].Start.Row
+		}
+		return locs[i].Start.Col > locs[j].Start.Col
+	})
+	var last parse.Point
+	for _, loc := range locs {
+		if loc.Start == last {
+			continue
+		}
+		last = loc.Start
+		line := lines[loc.Start.Row-1]
+		lines[loc.Start.Row-1] = line[:loc.Start.Col-1] + name + line[loc.End.Col-1:]
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// verifyRename checks the program with the rewritten sources in place of the
+// files on disk and reports any diagnostics as an error.
+func verifyRename(inputPath string, result *RenameResult) error {
+	workingDir := filepath.Dir(inputPath)
+	resolver, err := checker.NewModuleResolver(workingDir)
+	if err != nil {
+		return fmt.Errorf("error initializing module resolver: %w", err)
+	}
+	projectInfo := resolver.GetProjectInfo()
+	entryPath, err := filepath.Abs(inputPath)
+	if err != nil {
+		return err
+	}
+	for path, source := range result.Files {
+		resolver.SetOverlay(path, string(source))
+	}
+
+	source, ok := result.Files[entryPath]
+	if !ok {
+		if source, err = os.ReadFile(inputPath); err != nil {
+			return fmt.Errorf("error reading file %s - %w", inputPath, err)
+		}
+	}
+	relPath := inputPath
+	if projectRelative, err := filepath.Rel(projectInfo.RootPath, entryPath); err == nil {
+		relPath = projectRelative
+	}
+	parsed := parse.Parse(source, inputPath)
+	parsed.AllowLegacy(projectInfo.LanguageVersion())
+	var found []checker.Diagnostic
+	if len(parsed.Errors) > 0 {
+		found = checker.ParseErrorDiagnostics(relPath, parsed.Errors)
+	} else {
+		goResolver := checker.NewGoPackagesResolver(projectInfo.RootPath, projectInfo.Go.BuildTags)
+		c := checker.New(relPath, parsed.Program, resolver, checker.CheckOptions{GoResolver: goResolver})
+		c.Check()
+		found = c.Diagnostics()
+	}
+	if len(found) == 0 {
+		return nil
+	}
+
+	rewritten := func(path string) ([]byte, error) {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(projectInfo.RootPath, path)
+		}
+		if source, ok := result.Files[filepath.Clean(path)]; ok {
+			return source, nil
+		}
+		return os.ReadFile(path)
+	}
+	var rendered bytes.Buffer
+	if err := diagnostics.RenderWithOptions(&rendered, found, rewritten, diagnostics.RenderOptions{Color: diagnostics.ColorNever}); err != nil {
+		return fmt.Errorf("render diagnostics: %w", err)
+	}
+	return fmt.Errorf("renaming %s to %s would not type-check; no files were changed\n\n%s", result.OldName, result.NewName, strings.TrimRight(rendered.String(), "\n"))
+}
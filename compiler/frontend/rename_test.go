@@ -0,0 +1,105 @@
+package frontend
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/parse"
+)
+
+func writeRenameProject(t *testing.T, files map[string]string) string {
+	t.Helper()
+	projectDir := t.TempDir()
+	files["ard.toml"] = "name = \"proj\"\nard = \">= 0.1.0\"\n"
+	for path, contents := range files {
+		if err := os.WriteFile(filepath.Join(projectDir, path), []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return projectDir
+}
+
+func TestRenameRewritesReferencesAcrossModules(t *testing.T) {
+	projectDir := writeRenameProject(t, map[string]string{
+		"lib.ard":  "fn helper() Int {\n  1\n}\n\nfn twice() Int {\n  helper() + helper()\n}\n",
+		"main.ard": "use proj/lib\n\nfn main() {\n  let x = lib::helper()\n  let y = lib::twice()\n}\n",
+	})
+	mainPath := filepath.Join(projectDir, "main.ard")
+
+	result, err := Rename(mainPath, parse.Point{Row: 4, Col: 16}, "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths, err := result.Write()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected both modules to change, got %v", paths)
+	}
+
+	want := map[string]string{
+		"lib.ard":  "fn one() Int {\n  1\n}\n\nfn twice() Int {\n  one() + one()\n}\n",
+		"main.ard": "use proj/lib\n\nfn main() {\n  let x = lib::one()\n  let y = lib::twice()\n}\n",
+	}
+	for path, expected := range want {
+		got, err := os.ReadFile(filepath.Join(projectDir, path))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != expected {
+			t.Fatalf("%s after rename:\n%s\nwant:\n%s", path, got, expected)
+		}
+	}
+}
+
+func TestRenameLocalBinding(t *testing.T) {
+	projectDir := writeRenameProject(t, map[string]string{
+		"main.ard": "fn main() {\n  let count = 1\n  let doubled = count + count\n}\n",
+	})
+	mainPath := filepath.Join(projectDir, "main.ard")
+
+	result, err := Rename(mainPath, parse.Point{Row: 2, Col: 8}, "total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "fn main() {\n  let total = 1\n  let doubled = total + total\n}\n"
+	if got := string(result.Files[mainPath]); got != want {
+		t.Fatalf("rename produced:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRenameRefusesConflictsAndLeavesFilesUnchanged(t *testing.T) {
+	source := "fn helper() Int {\n  1\n}\n\nfn other() Int {\n  2\n}\n\nfn main() {\n  let label = 1\n  let n = helper() + label\n}\n"
+	projectDir := writeRenameProject(t, map[string]string{"main.ard": source})
+	mainPath := filepath.Join(projectDir, "main.ard")
+
+	for _, tc := range []struct {
+		name    string
+		at      parse.Point
+		newName string
+		wantErr string
+	}{
+		{name: "existing declaration", at: parse.Point{Row: 1, Col: 5}, newName: "other", wantErr: "already declares other"},
+		{name: "invalid name", at: parse.Point{Row: 1, Col: 5}, newName: "not-a-name", wantErr: "not a valid name"},
+		{name: "no name at position", at: parse.Point{Row: 2, Col: 1}, newName: "value", wantErr: "no renameable name"},
+		{name: "introduces errors", at: parse.Point{Row: 10, Col: 7}, newName: "helper", wantErr: "would not type-check"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := Rename(mainPath, tc.at, tc.newName)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Fatalf("expected error containing %q, got %v", tc.wantErr, err)
+			}
+		})
+	}
+
+	got, err := os.ReadFile(mainPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != source {
+		t.Fatalf("a refused rename changed the file:\n%s", got)
+	}
+}
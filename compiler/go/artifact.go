@@ -0,0 +1,93 @@
+package gotarget
+
+import (
+	"debug/buildinfo"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+	"github.com/akonwi/ard/checker"
+)
+
+// BuiltProgram describes a binary written by BuildProgram.
+type BuiltProgram struct {
+	air.ProgramInfo
+	GoVersion string
+}
+
+// buildMetadataSymbol is the runtime variable the linker sets to a built
+// program's encoded air.ProgramInfo.
+const buildMetadataSymbol = "ard.BuildMetadata"
+
+// buildMetadataFlags links program's description into the binary. The go
+// command also records the flags in the binary's build info, which is where
+// ReadBuildMetadata finds them without running the program.
+func buildMetadataFlags(program *air.Program, info *checker.ProjectInfo) (string, error) {
+	encoded, err := json.Marshal(air.Describe(program))
+	if err != nil {
+		return "", err
+	}
+	symbol := path.Join(generatedModulePath(info), "internal", buildMetadataSymbol)
+	return "-X " + symbol + "=" + base64.StdEncoding.EncodeToString(encoded), nil
+}
+
+// ReadBuildMetadata reads the description BuildProgram linked into the
+// binary at path.
+func ReadBuildMetadata(binaryPath string) (BuiltProgram, error) {
+	info, err := buildinfo.ReadFile(binaryPath)
+	if err != nil {
+		return BuiltProgram{}, fmt.Errorf("%s is not a program built by ard", binaryPath)
+	}
+	for _, setting := range info.Settings {
+		if setting.Key != "-ldflags" {
+			continue
+		}
+		for _, field := range strings.Fields(setting.Value) {
+			symbol, value, ok := strings.Cut(field, "=")
+			if !ok || !strings.HasSuffix(symbol, "/internal/"+buildMetadataSymbol) {
+				continue
+			}
+			decoded, err := base64.StdEncoding.DecodeString(value)
+			if err != nil {
+				return BuiltProgram{}, fmt.Errorf("%s has corrupted build metadata: %w", binaryPath, err)
+			}
+			built := BuiltProgram{GoVersion: info.GoVersion}
+			if err := json.Unmarshal(decoded, &built.ProgramInfo); err != nil {
+				return BuiltProgram{}, fmt.Errorf("%s has corrupted build metadata: %w", binaryPath, err)
+			}
+			return built, nil
+		}
+	}
+	return BuiltProgram{}, fmt.Errorf("%s is not a program built by ard", binaryPath)
+}
+
+// RunBinaryWithLimits runs a binary written by BuildProgram the way
+// RunProgramWithLimits runs a program it has just built. A sandboxed run
+// checks the Go packages listed in the binary's build metadata and relies on
+// the binary to enforce its grants, so it is only as safe as the binary is
+// trustworthy: a binary ard did not build, or one built from other sources,
+// can misreport its imports and ignore the grants.
+func RunBinaryWithLimits(binaryPath string, args []string, limits RunLimits) error {
+	if limits.Sandboxed {
+		built, err := ReadBuildMetadata(binaryPath)
+		if err != nil {
+			return err
+		}
+		if built.GoImports == nil {
+			return fmt.Errorf("%s was built before ard recorded its Go imports; rebuild it to run it in a sandbox", binaryPath)
+		}
+		if err := checkSandboxPackages(binaryPath, built.GoImports); err != nil {
+			return err
+		}
+	}
+	cmd := exec.Command(binaryPath, args...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Stdin = os.Stdin
+	return runBuiltProgram(cmd, limits)
+}
@@ -3,6 +3,7 @@ package gotarget
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go/token"
 	"os"
@@ -56,7 +57,18 @@ func GenerateSources(program *air.Program, options Options) (map[string][]byte,
 }
 
 func RunProgram(program *air.Program, args []string, projectInfo ...*checker.ProjectInfo) error {
+	return RunProgramWithLimits(program, args, RunLimits{}, projectInfo...)
+}
+
+// RunProgramWithLimits is RunProgram for hosts that embed Ard: the program is
+// stopped with a RuntimeLimitExceeded error once it exceeds limits.
+func RunProgramWithLimits(program *air.Program, args []string, limits RunLimits, projectInfo ...*checker.ProjectInfo) error {
 	info := optionalProjectInfo(projectInfo)
+	if limits.Sandboxed {
+		if err := checkSandboxImports(program); err != nil {
+			return err
+		}
+	}
 	workspaceDir, err := artifactWorkspace(inputPathFromCLIArgs(args), "run")
 	if err != nil {
 		return err
@@ -75,12 +87,32 @@ func RunProgram(program *air.Program, args []string, projectInfo ...*checker.Pro
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	cmd.Stdin = os.Stdin
-	if err := cmd.Run(); err != nil {
+	return runBuiltProgram(cmd, limits)
+}
+
+// runBuiltProgram runs cmd within limits, reporting a non-zero exit as an
+// ExitError.
+func runBuiltProgram(cmd *exec.Cmd, limits RunLimits) error {
+	if err := runWithLimits(cmd, limits); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return ExitError{Code: exitErr.ExitCode()}
+		}
 		return err
 	}
 	return nil
 }
 
+// ExitError reports that a program ran and exited with a non-zero status,
+// either from a panic or from the Int returned by main.
+type ExitError struct {
+	Code int
+}
+
+func (e ExitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
 func BuildProgram(program *air.Program, outputPath string, projectInfo ...*checker.ProjectInfo) (string, error) {
 	info := optionalProjectInfo(projectInfo)
 	workspaceDir, err := artifactWorkspace(outputPath, "build")
@@ -97,7 +129,11 @@ func BuildProgram(program *air.Program, outputPath string, projectInfo ...*check
 	if err != nil {
 		return "", err
 	}
-	if err := buildGeneratedProgram(workspaceDir, absOutput, goBuildTags(info)...); err != nil {
+	ldflags, err := buildMetadataFlags(program, info)
+	if err != nil {
+		return "", err
+	}
+	if err := buildGeneratedProgramWithFlags(workspaceDir, absOutput, nil, ldflags, goBuildTags(info)...); err != nil {
 		return "", err
 	}
 	return absOutput, nil
@@ -956,6 +992,18 @@ func writeGeneratedRuntimePackage(dir string) error {
 }
 
 func buildGeneratedProgram(dir string, outputPath string, buildTags ...string) error {
+	return buildGeneratedProgramWithEnv(dir, outputPath, nil, buildTags...)
+}
+
+// buildGeneratedProgramWithEnv is buildGeneratedProgram with extra
+// environment entries for the go command, such as GOOS and GOARCH.
+func buildGeneratedProgramWithEnv(dir string, outputPath string, env []string, buildTags ...string) error {
+	return buildGeneratedProgramWithFlags(dir, outputPath, env, "", buildTags...)
+}
+
+// buildGeneratedProgramWithFlags is buildGeneratedProgramWithEnv with flags
+// for the linker.
+func buildGeneratedProgramWithFlags(dir string, outputPath string, env []string, ldflags string, buildTags ...string) error {
 	// The generated output imports encoding/json/v2 (union marshalling), so
 	// the jsonv2 experiment tag is part of the output contract and always
 	// applied here, regardless of caller or environment. The checker's
@@ -968,9 +1016,15 @@ func buildGeneratedProgram(dir string, outputPath string, buildTags ...string) e
 		}
 	}
 	args := []string{"build", "-mod=mod", "-o", outputPath, "-tags=" + strings.Join(tags, ",")}
+	if ldflags != "" {
+		args = append(args, "-ldflags="+ldflags)
+	}
 	args = append(args, ".")
 	cmd := exec.Command("go", args...)
 	cmd.Dir = dir
+	if len(env) > 0 {
+		cmd.Env = append(os.Environ(), env...)
+	}
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
@@ -1,9 +1,12 @@
 package gotarget
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/token"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sort"
@@ -14,6 +17,7 @@ import (
 	"github.com/akonwi/ard/checker"
 	"github.com/akonwi/ard/frontend"
 	"github.com/akonwi/ard/parse"
+	"github.com/akonwi/ard/version"
 )
 
 func TestZeroValueForForeignNumericTypeUsesUnderlyingZero(t *testing.T) {
@@ -878,6 +882,21 @@ func TestGenerateSourcesFormatsSimpleProgram(t *testing.T) {
 		t.Fatalf("synthetic main does not call the entry Main:\n%s", mainGot)
 	}
 }
+func TestGenerateSourcesKeepsWholeFloatsFloat(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() Float64 {
+			10.0 / 4.0
+		}
+	`)
+
+	sources, err := GenerateSources(program, Options{PackageName: "main"})
+	if err != nil {
+		t.Fatalf("GenerateSources error = %v", err)
+	}
+	if got := string(sources["test/test.go"]); !strings.Contains(got, "return 10.0 / 4.0") {
+		t.Fatalf("generated source divides whole floats as ints:\n%s", got)
+	}
+}
 func TestLowerProgramOmitsTestsUnlessIncluded(t *testing.T) {
 	result := parse.Parse([]byte(`
 		fn main() Int { 1 }
@@ -3821,10 +3840,14 @@ func TestLowerProgramSupportsListSwapAndMapKeys(t *testing.T) {
 		t.Fatal("generated AST missing list swap lowering")
 	}
 	if !astFilesContain(files, func(node ast.Node) bool {
-		_, ok := node.(*ast.RangeStmt)
-		return ok
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return false
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		return ok && sel.Sel.Name == "SortedKeys"
 	}) {
-		t.Fatal("generated AST missing map keys range lowering")
+		t.Fatal("generated AST missing sorted map keys lowering")
 	}
 }
 func TestLowerProgramEmitsOnlyUsedImports(t *testing.T) {
@@ -3973,6 +3996,31 @@ func TestBuildProgramProducesBinary(t *testing.T) {
 		t.Fatalf("built binary stat error = %v", err)
 	}
 }
+func TestBuiltBinariesDescribeThemselvesAndRunDirectly(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() Int {
+			4
+		}
+	`)
+	builtPath, err := BuildProgram(program, filepath.Join(t.TempDir(), "app"))
+	if err != nil {
+		t.Fatalf("BuildProgram error = %v", err)
+	}
+	built, err := ReadBuildMetadata(builtPath)
+	if err != nil {
+		t.Fatalf("ReadBuildMetadata error = %v", err)
+	}
+	if built.Compiler != version.Get() || built.Entry != "main" || len(built.Modules) == 0 || built.GoVersion == "" {
+		t.Fatalf("metadata = %+v", built)
+	}
+	var exit ExitError
+	if err := RunBinaryWithLimits(builtPath, nil, RunLimits{}); !errors.As(err, &exit) || exit.Code != 4 {
+		t.Fatalf("RunBinaryWithLimits error = %v, want exit status 4", err)
+	}
+	if _, err := ReadBuildMetadata(os.Args[0]); err == nil {
+		t.Fatal("ReadBuildMetadata accepted a binary ard did not build")
+	}
+}
 func TestRunProgramPreservesArtifactsUnderArdOut(t *testing.T) {
 	program := lowerSource(t, `
 		fn main() Void {
@@ -4033,7 +4081,7 @@ func TestRunProgramNamesBinaryAfterProject(t *testing.T) {
 		t.Fatal(err)
 	}
 	mainPath := filepath.Join(projectDir, "main.ard")
-	if err := os.WriteFile(mainPath, []byte(`fn main() Int { 1 }
+	if err := os.WriteFile(mainPath, []byte(`fn main() Int { 0 }
 `), 0o644); err != nil {
 		t.Fatal(err)
 	}
@@ -5098,26 +5146,82 @@ func TestRunProgramStdlibListFindThroughABI(t *testing.T) {
 	}
 }
 
-// TestRunProgramReturnsGenericResultThroughABI covers the Result half for
-// generic functions: the (T, error) unpacking temp at a call site must use
-// the instantiated type, not the callee's declared type parameter.
-func TestRunProgramReturnsGenericResultThroughABI(t *testing.T) {
+// TestRunProgramStdlibCacheBoundsEntries covers the generic cache structs
+// from ard/cache, including the go:time import inside the stdlib module.
+func TestRunProgramStdlibCacheBoundsEntries(t *testing.T) {
 	program := lowerSource(t, `
-		fn require_first(items: [$T]) $T!Str {
-			match items.at(0) {
-				item => Result::ok(item),
-				_ => Result::err("empty"),
+		use ard/cache
+		use go:time
+
+		fn main() {
+			mut lru = cache::Lru::new<Str, Int>(2)
+			lru.set("a", 1)
+			lru.set("b", 2)
+			if lru.get("a").or(0) != 1 {
+				panic("lru get failed")
+			}
+			lru.set("c", 3)
+			if lru.size() != 2 or lru.has("b") {
+				panic("lru eviction failed")
+			}
+
+			mut ttl = cache::Ttl::new<Str, Int>(2, time::Duration::from(1) * time::Hour)
+			ttl.set("x", 1)
+			ttl.set("y", 2)
+			ttl.set("z", 3)
+			if ttl.size() != 2 or ttl.has("x") or ttl.get("z").or(0) != 3 {
+				panic("ttl eviction failed")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibCacheRejectsNonPositiveCapacity checks that a cache
+// that could never evict fails at construction instead of growing unbounded.
+func TestRunProgramStdlibCacheRejectsNonPositiveCapacity(t *testing.T) {
+	for _, construct := range []string{
+		"cache::Lru::new<Str, Int>(0)",
+		"cache::Ttl::new<Str, Int>(-1, time::Hour)",
+	} {
+		program := lowerSource(t, `
+			use ard/cache
+			use go:time
+
+			fn main() {
+				mut c = `+construct+`
+				c.set("a", 1)
 			}
+		`)
+
+		if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err == nil {
+			t.Fatalf("RunProgram(%s) succeeded, want a capacity panic", construct)
 		}
+	}
+}
+
+// TestRunProgramStdlibMathAndIntMethods covers ard/math's functions and
+// constants alongside the Int abs, pow, and clamp methods.
+func TestRunProgramStdlibMathAndIntMethods(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/math
 
 		fn main() {
-			let n = try require_first([7]) -> err { panic(err) }
-			if n != 7 {
-				panic("wrong value")
+			if math::round(-2.5) != -3.0 or math::clamp(12.0, 0.0, 10.0) != 10.0 {
+				panic("float helpers failed")
 			}
-			let empty: [Int] = []
-			if require_first(empty).is_ok() {
-				panic("expected err")
+			if math::abs(math::cos(math::PI) + 1.0) > 0.000001 or math::E < 2.7 {
+				panic("trig or constants failed")
+			}
+			let n = -3
+			if n.abs() != 3 or n.pow(3) != -27 or 2.pow(0) != 1 {
+				panic("Int abs or pow failed")
+			}
+			if 12.clamp(0, 10) != 10 or n.clamp(0, 10) != 0 {
+				panic("Int clamp failed")
 			}
 		}
 	`)
@@ -5127,27 +5231,88 @@ func TestRunProgramReturnsGenericResultThroughABI(t *testing.T) {
 	}
 }
 
-// TestRunProgramForwardReferencesGenericFunctions covers calling a generic
-// function declared after the caller: the call site's specialized copy of
-// the hoisted signature has no body yet, and lowering must resolve the
-// original definition instead of failing the call target lookup.
-func TestRunProgramForwardReferencesGenericFunctions(t *testing.T) {
+// TestRunProgramIntOverflowWrapsAndCheckedOpsDetectIt covers the prelude
+// Int constants and checked arithmetic, and that runtime Int arithmetic
+// wraps.
+func TestRunProgramIntOverflowWrapsAndCheckedOpsDetectIt(t *testing.T) {
 	program := lowerSource(t, `
 		fn main() {
-			if head([1, 2]).or(-1) != 1 {
-				panic("public forward generic failed")
+			let max = Int::MAX
+			if max + 1 != Int::MIN or max * 2 != -2 {
+				panic("Int arithmetic should wrap")
 			}
-			if tail([1, 2]).or(-1) != 2 {
-				panic("private forward generic failed")
+			if Int::checked_add(max, 1).is_some() or Int::checked_sub(Int::MIN, 1).is_some() {
+				panic("checked add/sub should detect overflow")
+			}
+			if Int::checked_mul(max, 2).is_some() or Int::checked_mul(-4, 5).or(0) != -20 {
+				panic("checked mul failed")
 			}
 		}
+	`)
 
-		fn head(items: [$T]) $T? {
-			items.at(0)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStrMethodsCountRunes covers the Str slicing, search, case,
+// padding, and splitting methods on text with multi-byte characters.
+func TestRunProgramStrMethodsCountRunes(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() {
+			let word = "héllo wörld"
+			if word.slice(1, 4) != "éll" or word.slice(8, 100) != "rld" or word.slice(5, 2) != "" {
+				panic("slice failed")
+			}
+			if word.index_of("wö").or(-1) != 6 or word.index_of("xyz").is_some() {
+				panic("index_of failed")
+			}
+			if word.to_upper() != "HÉLLO WÖRLD" or "ÀB".to_lower() != "àb" {
+				panic("case conversion failed")
+			}
+			if "  hi  ".trim_start() != "hi  " or "  hi  ".trim_end() != "  hi" {
+				panic("trim_start or trim_end failed")
+			}
+			if "é".pad_left(3, "*") != "**é" or "ab".pad_right(5, "-=") != "ab-=-" {
+				panic("padding failed")
+			}
+			if "ab".repeat(3) != "ababab" or "añb".reverse() != "bña" {
+				panic("repeat or reverse failed")
+			}
+			let chars = "añ😀".chars()
+			if chars.size() != 3 or chars.at(2).or("") != "😀" {
+				panic("chars failed")
+			}
 		}
+	`)
 
-		private fn tail(items: [$T]) $T? {
-			items.at(items.size() - 1)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStrCountsBytesRunesAndGraphemes covers the three ways to
+// measure a Str: size() in bytes, for loops over runes, and graphemes().
+func TestRunProgramStrCountsBytesRunesAndGraphemes(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() {
+			let text = "né👍🏽"
+			mut runes = 0
+			mut last = 0
+			for ch, i in text {
+				runes = runes + 1
+				last = i
+			}
+			if text.size() != 11 or runes != 4 or last != 3 {
+				panic("bytes or rune iteration failed")
+			}
+			let graphemes = text.graphemes()
+			if graphemes.size() != 3 or graphemes.at(2).or("") != "👍🏽" {
+				panic("graphemes failed")
+			}
+			if "🇫🇷🇩🇪".graphemes().size() != 2 or "e\u0301".graphemes().size() != 1 {
+				panic("flag or combining mark graphemes failed")
+			}
 		}
 	`)
 
@@ -5156,36 +5321,75 @@ func TestRunProgramForwardReferencesGenericFunctions(t *testing.T) {
 	}
 }
 
-// TestRunProgramMarshalsUnionsThroughGoJSON pins the union marshalling
-// contract: generated unions carry a MarshalJSON method so Go JSON APIs
-// encode the active member unwrapped (no ArdTag on the wire). This is the
-// reason generated output imports encoding/json/v2 (ADR 0031/0035); the
-// built-in ard/json module was removed, so Go interop is the consumer.
-func TestRunProgramMarshalsUnionsThroughGoJSON(t *testing.T) {
+// TestRunProgramRuneConversions covers the Rune prelude's checked
+// conversions from code points and strings alongside Rune::from.
+func TestRunProgramRuneConversions(t *testing.T) {
 	program := lowerSource(t, `
-		use go:encoding/json
+		fn main() {
+			let e = Rune::from_int(233).expect("233 is a scalar value")
+			if e != 'é' or e.to_int() != 233 or e.to_str() != "é" {
+				panic("from_int failed")
+			}
+			if Rune::from_int(55296).is_some() or Rune::from_int(Rune::MAX + 1).is_some() {
+				panic("invalid code points should be rejected")
+			}
+			if Rune::from_str("x").or(' ') != 'x' or Rune::from_str("xy").is_some() {
+				panic("from_str failed")
+			}
+			if Rune::from(97) != 'a' or not ('a' < 'b') {
+				panic("Rune::from or comparison failed")
+			}
+		}
+	`)
 
-		type Value = Str | Int
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
 
-		fn encode(v: Value) Str {
-			let bytes = try json::Marshal(v) -> err { panic(err) }
-			mut out = ""
-			for b in bytes {
-				out = "{out}{b.to_str()},"
+// TestRunProgramEnumPayloads covers enum variants carrying data: building
+// them, binding their payload in a match, and recursive payloads.
+func TestRunProgramEnumPayloads(t *testing.T) {
+	program := lowerSource(t, `
+		enum Event {
+			Click(Int, Int),
+			Key(Str),
+			Quit,
+		}
+
+		enum Expr {
+			Num(Int),
+			Add(Expr, Expr),
+			Neg(Expr),
+		}
+
+		fn eval(expr: Expr) Int {
+			match expr {
+				Expr::Num(n) => n,
+				Expr::Add(left, right) => eval(left) + eval(right),
+				Expr::Neg(inner) => 0 - eval(inner),
+			}
+		}
+
+		fn describe(event: Event) Str {
+			match event {
+				Event::Click(x, _) => "click {x}",
+				Event::Key(key) => "key {key}",
+				Event::Quit => "quit",
 			}
-			out
 		}
 
 		fn main() {
-			let s: Value = "hi"
-			let n: Value = 42
-			// "hi" encodes as the JSON string "hi" -> bytes 34,104,105,34
-			if encode(s) != "34,104,105,34," {
-				panic("union Str member did not marshal unwrapped: {encode(s)}")
+			let events = [Event::Click(3, 4), Event::Key("a"), Event::Quit]
+			mut seen = ""
+			for event in events {
+				seen = seen + describe(event) + ";"
 			}
-			// 42 encodes as the JSON number 42 -> bytes 52,50
-			if encode(n) != "52,50," {
-				panic("union Int member did not marshal unwrapped: {encode(n)}")
+			if seen != "click 3;key a;quit;" {
+				panic("unexpected events: {seen}")
+			}
+			if eval(Expr::Add(Expr::Num(2), Expr::Neg(Expr::Num(5)))) != -3 {
+				panic("recursive payloads evaluated wrong")
 			}
 		}
 	`)
@@ -5195,49 +5399,124 @@ func TestRunProgramMarshalsUnionsThroughGoJSON(t *testing.T) {
 	}
 }
 
-// TestRunProgramCompositeMarshalsThroughGoJSON pins the wider FFI marshalling
-// contract from ADR 0031: struct field tags preserve Ard names, Maybe fields
-// marshal as value-or-null, and enums marshal as their integer discriminants,
-// all through a real Go JSON API.
-func TestRunProgramCompositeMarshalsThroughGoJSON(t *testing.T) {
+// TestRunProgramEnumUtilities covers the helpers built in to enums: listing
+// and counting variants, converting from Int, and naming a variant.
+func TestRunProgramEnumUtilities(t *testing.T) {
 	program := lowerSource(t, `
-		use go:encoding/json
-		use go:fmt
+		enum Status {
+			Active,
+			Paused = 5,
+			Done,
+		}
 
-		enum Color {
-			Red,
-			Green,
+		fn main() {
+			mut names = ""
+			for status in Status::values() {
+				names = names + status.name() + ";"
+			}
+			if names != "Active;Paused;Done;" {
+				panic("unexpected names: {names}")
+			}
+			if Status::count != 3 {
+				panic("expected 3 variants")
+			}
+			match Status::from_int(6) {
+				status => {
+					if status.name() != "Done" {
+						panic("expected Done from 6")
+					}
+				},
+				_ => panic("expected a variant for 6"),
+			}
+			if Status::from_int(1).is_some() {
+				panic("expected no variant for 1")
+			}
 		}
+	`)
 
-		struct Task {
-			task_name: Str,
-			due_note: Str?,
-			color: Color,
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStructFieldDefaults covers struct literals that omit fields
+// declared with a default value.
+func TestRunProgramStructFieldDefaults(t *testing.T) {
+	program := lowerSource(t, `
+		const BASE = 10
+
+		enum Level {
+			Debug,
+			Info,
 		}
 
-		fn encode(task: Task) Str {
-			let bytes = try json::Marshal(task) -> err { panic(err) }
-			fmt::Sprintf("%s", bytes)
+		struct Config {
+			host: Str,
+			retries: Int = 3,
+			timeout: Int = BASE * 2,
+			level: Level = Level::Info,
+			verbose: Bool = false,
 		}
 
-		fn check(encoded: Str, fragment: Str) {
-			if not encoded.contains(fragment) {
-				panic("missing {fragment} in {encoded}")
+		fn main() {
+			let config = Config{host: "localhost"}
+			if config.retries != 3 or config.timeout != 20 or config.verbose {
+				panic("defaults were not applied")
+			}
+			if config.level != Level::Info {
+				panic("enum default was not applied")
+			}
+			let custom = Config{host: "localhost", retries: 5, verbose: true}
+			if custom.retries != 5 or not custom.verbose {
+				panic("provided fields should override defaults")
 			}
 		}
+	`)
 
-		fn main() {
-			let with_note = Task{task_name: "write", due_note: "soon", color: Color::Green}
-			let encoded = encode(with_note)
-			// Field tags keep Ard names; Maybe marshals unwrapped; enums as ints.
-			check(encoded, "\"task_name\":\"write\"")
-			check(encoded, "\"due_note\":\"soon\"")
-			check(encoded, "\"color\":1")
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
 
-			let without = Task{task_name: "rest", due_note: Maybe::new(), color: Color::Red}
-			let encoded_null = encode(without)
-			check(encoded_null, "\"due_note\":null")
-			check(encoded_null, "\"color\":0")
+// TestRunProgramStructUpdates covers `Name{..base, field: value}`: the base
+// is evaluated once and supplies every field the literal leaves out.
+func TestRunProgramStructUpdates(t *testing.T) {
+	program := lowerSource(t, `
+		struct Config {
+			host: Str,
+			retries: Int,
+			tags: [Str],
+		}
+
+		struct Box<$T> {
+			item: $T,
+			count: Int,
+		}
+
+		mut built = 0
+
+		fn make_base() Config {
+			built = built + 1
+			Config{host: "localhost", retries: 1, tags: ["a"]}
+		}
+
+		fn main() {
+			let custom = Config{..make_base(), retries: 5}
+			if built != 1 {
+				panic("the base should be evaluated once")
+			}
+			if custom.host != "localhost" or custom.retries != 5 or custom.tags.size() != 1 {
+				panic("unexpected update: {custom.host} {custom.retries}")
+			}
+			let renamed = Config{..custom, host: "remote"}
+			if renamed.host != "remote" or renamed.retries != 5 or custom.host != "localhost" {
+				panic("updates should not change the base")
+			}
+			let box = Box{item: "x", count: 1}
+			let next = Box{..box, count: 2}
+			if next.item != "x" or next.count != 2 {
+				panic("generic update failed")
+			}
 		}
 	`)
 
@@ -5246,35 +5525,1407 @@ func TestRunProgramCompositeMarshalsThroughGoJSON(t *testing.T) {
 	}
 }
 
-// TestUnionDeclsCarryOnlyMarshalJSON pins the ADR 0031 claim that unions have
-// a MarshalJSON method and deliberately no UnmarshalJSON: decoding into a
-// union is ambiguous and must stay unsupported unless decided otherwise.
-func TestUnionDeclsCarryOnlyMarshalJSON(t *testing.T) {
+// TestRunProgramSelfType covers `Self` in static functions and impl blocks,
+// including a generic struct whose `Self` carries its type parameters.
+func TestRunProgramSelfType(t *testing.T) {
 	program := lowerSource(t, `
-		type Value = Str | Int
+		struct Point {
+			x: Int,
+			y: Int,
+		}
+
+		fn Point::origin() Self {
+			Self{x: 0, y: 0}
+		}
+
+		impl Point {
+			fn moved(dx: Int) Self {
+				Self{..self, x: self.x + dx}
+			}
+		}
+
+		struct Box<$T> {
+			item: $T,
+		}
+
+		impl Box {
+			fn copy() Self {
+				Self{item: self.item}
+			}
+		}
 
 		fn main() {
-			let v: Value = "x"
+			let p = Point::origin().moved(3)
+			if p.x != 3 or p.y != 0 {
+				panic("unexpected point: {p.x}, {p.y}")
+			}
+			let b = Box{item: "x"}
+			if b.copy().item != "x" {
+				panic("unexpected copy")
+			}
 		}
 	`)
 
-	sources, err := GenerateSources(program, Options{PackageName: "main"})
-	if err != nil {
-		t.Fatalf("GenerateSources error = %v", err)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
 	}
-	var unionFile string
-	for _, src := range sources {
-		if strings.Contains(string(src), "type Value struct") {
-			unionFile = string(src)
+}
+
+// TestRunProgramBoundMethods covers methods used as function values: a
+// receiver that is a variable is read on each call, any other receiver is
+// evaluated once, and `Type::method` takes the receiver first.
+func TestRunProgramBoundMethods(t *testing.T) {
+	program := lowerSource(t, `
+		struct Scale {
+			factor: Int,
 		}
-	}
-	if unionFile == "" {
-		t.Fatal("union decl not found in generated sources")
-	}
-	if !strings.Contains(unionFile, "func (u Value) MarshalJSON()") {
-		t.Fatal("union MarshalJSON method missing")
-	}
+
+		impl Scale {
+			fn apply(n: Int) Int {
+				n * self.factor
+			}
+		}
+
+		struct Counter {
+			count: Int,
+		}
+
+		impl Counter {
+			fn mut bump(by: Int) {
+				self.count = self.count + by
+			}
+		}
+
+		mut made = 0
+
+		fn make_scale(factor: Int) Scale {
+			made = made + 1
+			Scale{factor: factor}
+		}
+
+		fn apply_all(nums: [Int], f: fn(Int) Int) Int {
+			mut total = 0
+			for n in nums {
+				total = total + f(n)
+			}
+			total
+		}
+
+		fn main() {
+			let scale = Scale{factor: 2}
+			if apply_all([1, 2, 3], scale.apply) != 12 {
+				panic("bound method applied incorrectly")
+			}
+			let triple = make_scale(3).apply
+			if triple(1) + triple(2) != 9 or made != 1 {
+				panic("receiver should be evaluated once")
+			}
+			let apply = Scale::apply
+			if apply(scale, 5) != 10 {
+				panic("method reference applied incorrectly")
+			}
+			mut counter = Counter{count: 0}
+			let bump = counter.bump
+			bump(2)
+			bump(3)
+			if counter.count != 5 {
+				panic("mutating method should update the receiver: {counter.count}")
+			}
+			let size = "hello".size
+			if size() != 5 {
+				panic("built-in method value failed")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramClosureMutableCaptures covers closures capturing `mut`
+// variables by reference: every kind of value, including lists rebound by
+// push or assignment, is shared with the enclosing function and with nested
+// closures.
+func TestRunProgramClosureMutableCaptures(t *testing.T) {
+	program := lowerSource(t, `
+		struct Point {
+			x: Int,
+		}
+
+		fn fill(items: mut [Int]) Int {
+			let add = fn(n: Int) { items.push(n) }
+			add(1)
+			add(2)
+			items.size()
+		}
+
+		fn main() {
+			mut count = 0
+			let inc = fn() { count = count + 1 }
+			inc()
+			inc()
+			if count != 2 {
+				panic("captured Int: {count}")
+			}
+
+			mut items: [Int] = []
+			let push = fn(n: Int) { items.push(n) }
+			push(1)
+			push(2)
+			if items.size() != 2 {
+				panic("captured list push: {items.size()}")
+			}
+
+			mut reset = [1, 2, 3]
+			let clear = fn() { reset = [] }
+			clear()
+			if reset.size() != 0 {
+				panic("captured list assignment: {reset.size()}")
+			}
+
+			mut seen: [Str: Int] = [:]
+			let replace = fn() { seen = ["a": 1, "b": 2] }
+			replace()
+			if seen.size() != 2 {
+				panic("captured map assignment: {seen.size()}")
+			}
+
+			mut point = Point{x: 1}
+			let bump = fn() { point.x = point.x + 1 }
+			bump()
+			if point.x != 2 {
+				panic("captured struct field: {point.x}")
+			}
+
+			mut name = "a"
+			let read = fn() Str { name }
+			name = "b"
+			if read() != "b" {
+				panic("closure should read the current value")
+			}
+
+			mut log: [Str] = []
+			let outer = fn() {
+				let inner = fn() { log.push("inner") }
+				inner()
+				log.push("outer")
+			}
+			outer()
+			if log.size() != 2 {
+				panic("nested capture: {log.size()}")
+			}
+
+			mut xs: [Int] = []
+			if fill(xs) != 2 {
+				panic("captured mutable parameter")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramPipes covers `|>`, which passes the value on its left as
+// the first argument of the call on its right.
+func TestRunProgramPipes(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/list
+
+		fn double(x: Int) Int { x * 2 }
+		fn add(x: Int, y: Int) Int { x + y }
+
+		fn main() {
+			if (3 |> double |> add(1) |> double()) != 14 {
+				panic("stages should apply left to right")
+			}
+			let sizes = ["a", "bb"]
+				|> list::map(fn(s: Str) Int { s.size() })
+			if sizes.at(1).or(0) != 2 {
+				panic("module functions should take the piped value")
+			}
+			let inc = fn(x: Int) Int { x + 1 }
+			if (1 |> inc) != 2 {
+				panic("function values should take the piped value")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramIfLetAndLetElse covers `if let`, which runs a block with
+// the unwrapped value, and `let ... else`, which returns early without one.
+func TestRunProgramIfLetAndLetElse(t *testing.T) {
+	program := lowerSource(t, `
+		fn find(n: Int) Int? {
+			match n > 0 {
+				true => Maybe::new(n * 10),
+				false => Maybe::new(),
+			}
+		}
+
+		fn parse(s: Str) Int!Str {
+			match s == "1" {
+				true => Result::ok(1),
+				false => Result::err("bad"),
+			}
+		}
+
+		fn describe(n: Int) Str {
+			let found = find(n) else { "nothing" }
+			"found {found}"
+		}
+
+		fn total(a: Str, b: Str) Int!Str {
+			let x = parse(a) else { Result::err("first") }
+			let y = parse(b) else { Result::err("second") }
+			Result::ok(x + y)
+		}
+
+		fn label(s: Str) Str {
+			if let v = parse(s) {
+				"ok {v}"
+			} else if let w = find(3) {
+				"fallback {w}"
+			} else {
+				"none"
+			}
+		}
+
+		fn main() {
+			if label("1") != "ok 1" or label("x") != "fallback 30" {
+				panic("if let should pick the first present value")
+			}
+			mut seen = 0
+			if let v = find(0) {
+				seen = v
+			}
+			if seen != 0 {
+				panic("if let body should not run for none")
+			}
+			if describe(2) != "found 20" or describe(0) != "nothing" {
+				panic("let else should return the else block's value")
+			}
+			if total("1", "1").or(-1) != 2 or total("1", "x").is_ok() {
+				panic("let else should return early from Result functions")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramUnionAs covers narrowing a union value with `as`.
+func TestRunProgramUnionAs(t *testing.T) {
+	program := lowerSource(t, `
+		struct Circle { radius: Int }
+		struct Square { side: Int }
+		type Shape = Circle | Square
+
+		fn main() {
+			let it = 7
+			let shape: Shape = Circle{radius: 2}
+			if (shape as Circle).expect("not a circle").radius != 2 {
+				panic("as Circle")
+			}
+			if (shape as Square).is_some() {
+				panic("as Square should be none")
+			}
+			if it != 7 {
+				panic("as should not shadow it")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramNumericLiteralForms covers hex, binary, and octal literals
+// and float exponents.
+func TestRunProgramNumericLiteralForms(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() {
+			if 0xFF != 255 or 0b1010 != 10 or 0o17 != 15 or 0xdead_beef != 3735928559 {
+				panic("radix literals")
+			}
+			if 010 != 10 {
+				panic("leading zeros should stay decimal")
+			}
+			let byte: Uint8 = 0xFF
+			let low: Int8 = -0x80
+			if byte != 255 or low != -128 {
+				panic("typed radix literals")
+			}
+			if 1.5e9 != 1500000000.0 or 2E-3 != 0.002 or 1e3 != 1000.0 {
+				panic("float exponents")
+			}
+			let small: Float32 = 2.5e2
+			if small != 250.0 {
+				panic("typed float exponents")
+			}
+			mut bytes: [Byte] = []
+			let zero: Byte = 0
+			bytes.push(zero)
+			if bytes.size() != 1 {
+				panic("typed literal bindings")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramNumericConversions covers the explicit Int and Float64
+// conversions and Int::parse and Float64::parse.
+func TestRunProgramNumericConversions(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() {
+			let n = 3
+			if n.to_float() / 2.0 != 1.5 {
+				panic("to_float")
+			}
+			let x = -2.5
+			if x.round() != -3 or x.floor() != -3 or x.ceil() != -2 or x.to_int() != -2 {
+				panic("float to int")
+			}
+			if Int::parse("-42").or(0) != -42 or Int::parse("4.2").is_ok() {
+				panic("Int::parse")
+			}
+			match Int::parse("9223372036854775808") {
+				ok => panic("out of range Int parsed"),
+				err(message) => {
+					if message != "\"9223372036854775808\" is out of range for Int" {
+						panic(message)
+					}
+				},
+			}
+			if Float64::parse("1.5e3").or(0.0) != 1500.0 or Float64::parse("NaN").is_ok() {
+				panic("Float64::parse")
+			}
+			let wide = 300
+			let low: Byte = Byte::from(wide)
+			if low.to_int() != 44 {
+				panic("Byte::from should truncate")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibBigInt covers ard/bigint arithmetic and operators past
+// the Int range and its conversions back to Int.
+func TestRunProgramStdlibBigInt(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/bigint
+
+		fn main() {
+			let two = bigint::BigInt::new(2)
+			let big = two.pow(100)
+			if big.to_str() != "1267650600228229401496703205376" {
+				panic("pow failed")
+			}
+			let parsed = bigint::BigInt::parse("-1267650600228229401496703205377").expect("parse failed")
+			if parsed + big != -bigint::BigInt::new(1) {
+				panic("add failed")
+			}
+			if (parsed % bigint::BigInt::new(1024)).to_int().or(0) != -1 or big.to_int().is_some() {
+				panic("rem or to_int failed")
+			}
+			if not (parsed < two and big >= two * two) {
+				panic("compare failed")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibHashAndUUID covers ard/hash digests and the layout of
+// ard/uuid ids.
+func TestRunProgramStdlibHashAndUUID(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/hash
+		use ard/uuid
+
+		fn main() {
+			if hash::sha256("abc") != "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad" {
+				panic("sha256")
+			}
+			if hash::md5_bytes("".bytes()) != "d41d8cd98f00b204e9800998ecf8427e" or hash::crc32("abc") != "352441c2" or hash::fnv("") != "cbf29ce484222325" {
+				panic("md5, crc32, or fnv")
+			}
+			let id = uuid::v7()
+			if id.size() != 36 or id.slice(14, 15) != "7" or uuid::v4().slice(14, 15) != "4" {
+				panic(id)
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibBase64AndHex covers ard/base64 and ard/hex round
+// trips and their errors for invalid input.
+func TestRunProgramStdlibBase64AndHex(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/base64
+		use ard/hex
+
+		fn main() {
+			if base64::encode("hi?>") != "aGk/Pg==" or base64::url_encode("hi?>") != "aGk_Pg" {
+				panic("base64 encode")
+			}
+			if base64::url_decode("aGk_Pg").or("") != "hi?>" or base64::decode("aGk_Pg").is_ok() {
+				panic("base64 decode")
+			}
+			if hex::encode("hi") != "6869" or hex::decode("6869").or("") != "hi" or hex::decode_bytes("6g").is_ok() {
+				panic("hex")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibCSV covers ard/csv reading a file row by row and
+// formatting rows with quoting.
+func TestRunProgramStdlibCSV(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/csv
+		use go:os
+
+		fn main() {
+			let path = os::TempDir() + "/ard-csv-test.csv"
+			mut data = "id\tname\n1\tada\n".bytes()
+			os::WriteFile(path, data, 420).expect("write")
+			mut reader = csv::Reader::open(path, '\t').expect("open")
+			let record = reader.next_record().expect("read").expect("missing record")
+			if record.get("name").or("") != "ada" or reader.next().expect("read").is_some() {
+				panic("records")
+			}
+			reader.close().expect("close")
+			os::Remove(path).expect("remove")
+			if csv::format([["a", "b c", "d,e"]]) != "a,b c,\"d,e\"\n" {
+				panic("format")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibNet covers an ard/net echo over a local TCP
+// connection.
+func TestRunProgramStdlibNet(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/async
+		use ard/net
+
+		fn main() {
+			let listener = net::listen(0).expect("listen")
+			async::start(fn() {
+				listener.serve(fn(conn) {
+					conn.write(conn.read(16).expect("server read")).expect("server write")
+					conn.close()
+				})
+			})
+			let client = net::dial("127.0.0.1", listener.port()).expect("dial")
+			client.write("echo".bytes()).expect("client write")
+			if Str::from(client.read(16).expect("client read")) != "echo" {
+				panic("echo")
+			}
+			listener.close().expect("close")
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibURL covers ard/url parsing, rendering, and joining.
+func TestRunProgramStdlibURL(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/url
+
+		fn main() {
+			let parsed = url::parse("HTTP://Example.com:80/docs/../api?q=a+b").expect("parse")
+			if parsed.query.get("q").or("") != "a b" or parsed.port.or(0) != 80 {
+				panic("parse")
+			}
+			if parsed.normalize().to_str() != "http://example.com/api?q=a+b" {
+				panic(parsed.normalize().to_str())
+			}
+			if url::join("https://a.com/x/y", "z").expect("join") != "https://a.com/x/z" {
+				panic("join")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibWebSocket covers ard/http's websocket handshake
+// against a server that refuses the upgrade.
+func TestRunProgramStdlibWebSocket(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Upgrade") != "websocket" || r.Header.Get("Sec-WebSocket-Key") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	program := lowerSource(t, fmt.Sprintf(`
+		use ard/http
+
+		fn main() {
+			match http::websocket("ws://%s/chat") {
+				ok => panic("expected the upgrade to be refused"),
+				err(message) => {
+					if not message.contains("200 OK") {
+						panic(message)
+					}
+				},
+			}
+			if http::websocket("ftp://example.com/").is_ok() {
+				panic("expected other schemes to be rejected")
+			}
+		}
+	`, strings.TrimPrefix(server.URL, "http://")))
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibSQL covers ard/sql against SQLite: the driver package
+// is linked by a `use go:` import nothing references, and queries, prepared
+// statements, and decoding run through it.
+func TestRunProgramStdlibSQL(t *testing.T) {
+	projectDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(projectDir, "ard.toml"), []byte("name = \"sqldemo\"\nard = \">= 0.1.0\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module sqldemo\n\ngo 1.26\n\nrequire github.com/mattn/go-sqlite3 v1.14.28\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	sums, err := os.ReadFile(filepath.Join("..", "go.sum"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var goSum strings.Builder
+	for _, line := range strings.Split(string(sums), "\n") {
+		if strings.HasPrefix(line, "github.com/mattn/go-sqlite3 v1.14.28") {
+			goSum.WriteString(line + "\n")
+		}
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "go.sum"), []byte(goSum.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mainPath := filepath.Join(projectDir, "main.ard")
+	if err := os.WriteFile(mainPath, []byte(`use ard/dynamic
+use ard/sql
+use go:github.com/mattn/go-sqlite3
+
+struct Item {
+  name: Str,
+  price: Float64,
+}
+
+fn item(row: [Str: Any]) Item!Str {
+  let name = dynamic::as_str(row.get("name").or(0)).or("")
+  match dynamic::as_float(row.get("price").or(0)) {
+    price => Result::ok(Item{name: name, price: price}),
+    _ => Result::err("price is not a Float64"),
+  }
+}
+
+fn main() {
+  let db = sql::open("sqlite3", ":memory:").expect("open")
+  db.exec("CREATE TABLE items (name TEXT, price REAL, stock INTEGER)", []).expect("create")
+  let insert = db.prepare("INSERT INTO items (name, price, stock) VALUES (?, ?, ?)").expect("prepare")
+  insert.exec(["pen", 1.5, 10]).expect("insert pen")
+  insert.exec(["ink", 4.25, 3]).expect("insert ink")
+  insert.close().expect("close statement")
+  if db.exec("UPDATE items SET stock = stock + 1", []).expect("update") != 2 {
+    panic("rows affected")
+  }
+  let rows = db.query("SELECT name, price, stock FROM items WHERE price > ?", [2]).expect("query")
+  if rows.size() != 1 or dynamic::as_int(rows.at(0).expect("row").get("stock").or("")).or(0) != 4 {
+    panic("query")
+  }
+  let items = sql::decode(rows, item).expect("decode")
+  if items.at(0).expect("item").name != "ink" {
+    panic("decode")
+  }
+  if db.query("SELECT ?", []).is_ok() {
+    panic("a missing parameter should fail")
+  }
+  db.close().expect("close")
+}
+`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := frontend.LoadModule(mainPath)
+	if err != nil {
+		t.Fatalf("load module: %v", err)
+	}
+	program, err := air.Lower(loaded.Module)
+	if err != nil {
+		t.Fatalf("lower: %v", err)
+	}
+	if err := RunProgram(program, []string{"ard", "run", mainPath}, loaded.ProjectInfo); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibDecode covers building a recursive ard/decode decoder
+// with its combinators, running it on a JSON document, and the path a
+// failure reports.
+func TestRunProgramStdlibDecode(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/decode
+		use ard/json
+
+		struct Node {
+			name: Str,
+			size: Int,
+			children: [Node],
+		}
+
+		fn node(value: Any) Node!decode::DecodeError {
+			let size = decode::one_of([decode::int, decode::map(decode::str, fn(text: Str) Int { text.size() })])
+			let children = decode::lazy(fn() fn(Any) Node!decode::DecodeError { node })
+			Result::ok(Node{
+				name: try decode::run(value, decode::field("name", decode::str)),
+				size: try decode::run(value, decode::optional_field("size", size, 0)),
+				children: try decode::run(value, decode::optional_field("children", decode::list(children), [])),
+			})
+		}
+
+		fn main() {
+			let doc = json::decode("\{\"name\": \"root\", \"children\": [\{\"name\": \"a\", \"size\": 2}, \{\"name\": \"b\", \"size\": \"xyz\"}]}").expect("json")
+			let root = decode::run(doc, node).expect("decode")
+			mut total = root.size
+			for child in root.children {
+				total = total + child.size
+			}
+			if total != 5 {
+				panic("total: {total}")
+			}
+			let positive = decode::and_then(decode::int, fn(n: Int) Int!Str {
+				match n > 0 {
+					true => Result::ok(n),
+					false => Result::err("expected a positive Int"),
+				}
+			})
+			let bad = json::decode("\{\"name\": \"x\", \"children\": [\{\"size\": 1}]}").expect("json")
+			match decode::run(bad, node) {
+				ok => panic("a child without a name should fail"),
+				err(failure) => {
+					if failure.path != "children[0].name" or failure.error() != "children[0].name: missing" {
+						panic(failure.error())
+					}
+				},
+			}
+			if decode::run(json::decode("-1").expect("json"), positive).is_ok() {
+				panic("and_then should reject -1")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibJSON covers streaming the items of a JSON array from
+// a file with ard/json.
+func TestRunProgramStdlibJSON(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/dynamic
+		use ard/json
+		use go:os
+
+		fn main() {
+			let path = os::TempDir() + "/ard-json-test.json"
+			mut data = "[\{\"id\": 1}, \{\"id\": 2}, \{\"id\": 3.5}]".bytes()
+			os::WriteFile(path, data, 420).expect("write")
+			mut items = json::list_stream(json::Decoder::open(path).expect("open"))
+			mut ids = 0
+			mut done = false
+			while not done {
+				match items.next().expect("read") {
+					item => {
+						let id = dynamic::as_map(item).expect("object").get("id").expect("id")
+						ids = ids + dynamic::as_int(id).or(10)
+					},
+					_ => { done = true },
+				}
+			}
+			items.close().expect("close")
+			os::Remove(path).expect("remove")
+			if ids != 13 {
+				panic("ids: {ids}")
+			}
+			if not json::decode("[1,").is_err() {
+				panic("truncated input")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibDynamic covers ard/dynamic's as_* accessors and kind.
+func TestRunProgramStdlibDynamic(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/dynamic
+
+		fn main() {
+			let items: [Any] = [1, "two", 3.5]
+			let value: Any = items
+			let list = dynamic::as_list(value).expect("not a list")
+			if dynamic::as_int(list.at(0).expect("")).or(0) != 1 or dynamic::as_str(list.at(1).expect("")).or("") != "two" {
+				panic("as_list elements")
+			}
+			if dynamic::kind(list.at(2).expect("")) != dynamic::Kind::Float or dynamic::kind(value) != dynamic::Kind::List {
+				panic("kind")
+			}
+			if dynamic::as_map(value).is_some() or dynamic::as_float(list.at(0).expect("")).is_some() {
+				panic("mismatched accessors should return none")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramStdlibLazyMemoizesModuleValues covers a module-level
+// lazy::new whose init must run once, on first access rather than at startup.
+func TestRunProgramStdlibLazyMemoizesModuleValues(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/lazy
+
+		mut loads = 0
+
+		let config = lazy::new(fn() Str {
+			loads = loads + 1
+			"loaded"
+		})
+
+		fn main() {
+			if loads != 0 {
+				panic("lazy init ran before first access")
+			}
+			let copy = config
+			if config.get() != "loaded" or copy.get() != "loaded" {
+				panic("lazy get failed")
+			}
+			if loads != 1 {
+				panic("lazy init ran more than once")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramReturnsGenericResultThroughABI covers the Result half for
+// generic functions: the (T, error) unpacking temp at a call site must use
+// the instantiated type, not the callee's declared type parameter.
+func TestRunProgramReturnsGenericResultThroughABI(t *testing.T) {
+	program := lowerSource(t, `
+		fn require_first(items: [$T]) $T!Str {
+			match items.at(0) {
+				item => Result::ok(item),
+				_ => Result::err("empty"),
+			}
+		}
+
+		fn main() {
+			let n = try require_first([7]) -> err { panic(err) }
+			if n != 7 {
+				panic("wrong value")
+			}
+			let empty: [Int] = []
+			if require_first(empty).is_ok() {
+				panic("expected err")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramForwardReferencesGenericFunctions covers calling a generic
+// function declared after the caller: the call site's specialized copy of
+// the hoisted signature has no body yet, and lowering must resolve the
+// original definition instead of failing the call target lookup.
+func TestRunProgramForwardReferencesGenericFunctions(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() {
+			if head([1, 2]).or(-1) != 1 {
+				panic("public forward generic failed")
+			}
+			if tail([1, 2]).or(-1) != 2 {
+				panic("private forward generic failed")
+			}
+		}
+
+		fn head(items: [$T]) $T? {
+			items.at(0)
+		}
+
+		private fn tail(items: [$T]) $T? {
+			items.at(items.size() - 1)
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramForwardReferencesMethodsAndTraitImpls covers methods, static
+// functions, and trait implementations declared below their first use. Earlier
+// call sites hold the hoisted definitions, which must end up with the bodies
+// checked later.
+func TestRunProgramForwardReferencesMethodsAndTraitImpls(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() {
+			let shape = Shape::unit()
+			if shape.area() != 1 {
+				panic("forward method failed")
+			}
+			if describe(shape) != "shape" {
+				panic("forward trait implementation failed")
+			}
+			if is_even(10) == false {
+				panic("mutual recursion failed")
+			}
+		}
+
+		fn describe(value: Describe) Str {
+			value.describe()
+		}
+
+		fn is_even(n: Int) Bool {
+			if n == 0 { true } else { is_odd(n - 1) }
+		}
+
+		fn is_odd(n: Int) Bool {
+			if n == 0 { false } else { is_even(n - 1) }
+		}
+
+		fn Shape::unit() Shape {
+			Shape{size: 1}
+		}
+
+		impl Shape {
+			fn area() Int {
+				self.size * self.size
+			}
+		}
+
+		impl Describe for Shape {
+			fn describe() Str {
+				"shape"
+			}
+		}
+
+		trait Describe {
+			fn describe() Str
+		}
+
+		struct Shape {
+			size: Int,
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramMarshalsUnionsThroughGoJSON pins the union marshalling
+// contract: generated unions carry a MarshalJSON method so Go JSON APIs
+// encode the active member unwrapped (no ArdTag on the wire). This is the
+// reason generated output imports encoding/json/v2 (ADR 0031/0035); the
+// built-in ard/json module was removed, so Go interop is the consumer.
+func TestRunProgramMarshalsUnionsThroughGoJSON(t *testing.T) {
+	program := lowerSource(t, `
+		use go:encoding/json
+
+		type Value = Str | Int
+
+		fn encode(v: Value) Str {
+			let bytes = try json::Marshal(v) -> err { panic(err) }
+			mut out = ""
+			for b in bytes {
+				out = "{out}{b.to_str()},"
+			}
+			out
+		}
+
+		fn main() {
+			let s: Value = "hi"
+			let n: Value = 42
+			// "hi" encodes as the JSON string "hi" -> bytes 34,104,105,34
+			if encode(s) != "34,104,105,34," {
+				panic("union Str member did not marshal unwrapped: {encode(s)}")
+			}
+			// 42 encodes as the JSON number 42 -> bytes 52,50
+			if encode(n) != "52,50," {
+				panic("union Int member did not marshal unwrapped: {encode(n)}")
+			}
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestRunProgramCompositeMarshalsThroughGoJSON pins the wider FFI marshalling
+// contract from ADR 0031: struct field tags preserve Ard names, Maybe fields
+// marshal as value-or-null, and enums marshal as their integer discriminants,
+// all through a real Go JSON API.
+func TestRunProgramCompositeMarshalsThroughGoJSON(t *testing.T) {
+	program := lowerSource(t, `
+		use go:encoding/json
+		use go:fmt
+
+		enum Color {
+			Red,
+			Green,
+		}
+
+		struct Task {
+			task_name: Str,
+			due_note: Str?,
+			color: Color,
+		}
+
+		fn encode(task: Task) Str {
+			let bytes = try json::Marshal(task) -> err { panic(err) }
+			fmt::Sprintf("%s", bytes)
+		}
+
+		fn check(encoded: Str, fragment: Str) {
+			if not encoded.contains(fragment) {
+				panic("missing {fragment} in {encoded}")
+			}
+		}
+
+		fn main() {
+			let with_note = Task{task_name: "write", due_note: "soon", color: Color::Green}
+			let encoded = encode(with_note)
+			// Field tags keep Ard names; Maybe marshals unwrapped; enums as ints.
+			check(encoded, "\"task_name\":\"write\"")
+			check(encoded, "\"due_note\":\"soon\"")
+			check(encoded, "\"color\":1")
+
+			let without = Task{task_name: "rest", due_note: Maybe::new(), color: Color::Red}
+			let encoded_null = encode(without)
+			check(encoded_null, "\"due_note\":null")
+			check(encoded_null, "\"color\":0")
+		}
+	`)
+
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+// TestUnionDeclsCarryOnlyMarshalJSON pins the ADR 0031 claim that unions have
+// a MarshalJSON method and deliberately no UnmarshalJSON: decoding into a
+// union is ambiguous and must stay unsupported unless decided otherwise.
+func TestUnionDeclsCarryOnlyMarshalJSON(t *testing.T) {
+	program := lowerSource(t, `
+		type Value = Str | Int
+
+		fn main() {
+			let v: Value = "x"
+		}
+	`)
+
+	sources, err := GenerateSources(program, Options{PackageName: "main"})
+	if err != nil {
+		t.Fatalf("GenerateSources error = %v", err)
+	}
+	var unionFile string
+	for _, src := range sources {
+		if strings.Contains(string(src), "type Value struct") {
+			unionFile = string(src)
+		}
+	}
+	if unionFile == "" {
+		t.Fatal("union decl not found in generated sources")
+	}
+	if !strings.Contains(unionFile, "func (u Value) MarshalJSON()") {
+		t.Fatal("union MarshalJSON method missing")
+	}
 	if strings.Contains(unionFile, "UnmarshalJSON") {
 		t.Fatal("unions must not carry UnmarshalJSON (decoding into a union is ambiguous)")
 	}
 }
+
+func TestRunProgramIfWithoutElseProducesMaybe(t *testing.T) {
+	program := lowerSource(t, `
+		fn half(n: Int) Int? {
+			if n % 2 == 0 {
+				n / 2
+			}
+		}
+
+		fn main() {
+			if half(8).or(0) != 4 {
+				panic("expected some from the taken branch")
+			}
+			if half(7).is_some() {
+				panic("expected none when no branch runs")
+			}
+			let n = 3
+			let bigger = if n > 5 {
+				n
+			} else if n > 1 {
+				n * 10
+			}
+			if bigger.or(0) != 30 {
+				panic("expected the else-if branch value")
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+func TestRunProgramChainedInequality(t *testing.T) {
+	program := lowerSource(t, `
+		fn within(x: Int) Bool {
+			0 <= x != 5 and x < 10
+		}
+
+		fn main() {
+			if not within(3) {
+				panic("3 should be within")
+			}
+			if within(5) {
+				panic("5 is excluded")
+			}
+			if within(-1) {
+				panic("-1 is below the range")
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+func TestRunProgramRangeLoopsWithStep(t *testing.T) {
+	program := lowerSource(t, `
+		fn sum(start: Int, end: Int, step: Int) Int {
+			mut total = 0
+			for i in start..end by step {
+				total =+ i
+			}
+			total
+		}
+
+		fn main() {
+			if sum(0, 10, 5) != 15 {
+				panic("expected 0 + 5 + 10")
+			}
+			if sum(10, 1, -4) != 18 {
+				panic("expected 10 + 6 + 2 counting down")
+			}
+			if sum(0, 10, 0) != 0 {
+				panic("a zero step should run nothing")
+			}
+			if sum(1, 10, -1) != 0 {
+				panic("counting down from below the end should run nothing")
+			}
+			mut count = 0
+			for i in 6..0 by -2 {
+				count =+ 1
+			}
+			if count != 4 {
+				panic("expected 6, 4, 2, 0")
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+func TestRunProgramWhileLetDrainsUntilEmpty(t *testing.T) {
+	program := lowerSource(t, `
+		struct Queue {
+			items: [Int],
+			next: Int,
+		}
+
+		impl Queue {
+			fn mut pop() Int? {
+				let item = self.items.at(self.next)
+				self.next =+ 1
+				item
+			}
+		}
+
+		fn main() {
+			mut queue = Queue{items: [1, 2, 3, 4], next: 0}
+			mut total = 0
+			while let item = queue.pop() {
+				if item == 3 {
+					break
+				}
+				total =+ item
+			}
+			if total != 3 {
+				panic("expected 1 + 2 before the break")
+			}
+			while let item = queue.pop() {
+				total =+ item
+			}
+			if total != 7 {
+				panic("expected the second loop to drain the last item")
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+func TestRunProgramStructPatterns(t *testing.T) {
+	program := lowerSource(t, `
+		struct Point {
+			x: Int,
+			y: Int,
+		}
+
+		enum Kind { circle, square }
+
+		struct Shape {
+			kind: Kind,
+			origin: Point,
+			label: Str,
+		}
+
+		fn describe(p: Point) Str {
+			match p {
+				Point{x: 0, y: 0} => "origin",
+				Point{x: 0, y} => "y={y}",
+				Point{x, y: 0} => "x={x}",
+				_ => "elsewhere",
+			}
+		}
+
+		fn name(s: Shape) Str {
+			match s {
+				Shape{kind: Kind::circle, origin: Point{x: 0, y: 0}} => "centered circle",
+				Shape{label: "big", kind} => "big",
+				Shape{label} => label,
+			}
+		}
+
+		fn main() {
+			let got = [
+				describe(Point{x: 0, y: 0}),
+				describe(Point{x: 0, y: 2}),
+				describe(Point{x: 3, y: 0}),
+				describe(Point{x: 3, y: 4}),
+				name(Shape{kind: Kind::circle, origin: Point{x: 0, y: 0}, label: "c"}),
+				name(Shape{kind: Kind::square, origin: Point{x: 0, y: 0}, label: "big"}),
+				name(Shape{kind: Kind::circle, origin: Point{x: 1, y: 0}, label: "small"}),
+			]
+			let want = ["origin", "y=2", "x=3", "elsewhere", "centered circle", "big", "small"]
+			for item, i in got {
+				if item != want.at(i).or("") {
+					panic("case {i}: got {item}")
+				}
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+func TestRunProgramNestedMatchPatterns(t *testing.T) {
+	program := lowerSource(t, `
+		fn lookup(id: Int) Int?!Str {
+			match id {
+				0 => Result::err("bad id"),
+				1 => Result::ok(Maybe::new()),
+				_ => Result::ok(Maybe::new(id * 10)),
+			}
+		}
+
+		fn find(id: Int) (Int!Str)? {
+			match id {
+				0 => Maybe::new(),
+				1 => Maybe::new(Result::err("nope")),
+				_ => Maybe::new(Result::ok(id)),
+			}
+		}
+
+		fn main() {
+			mut got: [Str] = []
+			for id in 0..2 {
+				got.push(match lookup(id) {
+					ok(some(x)) => "found {x}",
+					ok(_) => "missing",
+					err(e) => e,
+				})
+				got.push(match find(id) {
+					some(ok(x)) => "ok {x}",
+					some(err(e)) => e,
+					_ => "none",
+				})
+			}
+			let want = ["bad id", "none", "missing", "nope", "found 20", "ok 2"]
+			for item, i in got {
+				if item != want.at(i).or("") {
+					panic("case {i}: got {item}")
+				}
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+func TestRunProgramOrPatterns(t *testing.T) {
+	program := lowerSource(t, `
+		enum Color { red, green, blue, black }
+
+		fn size(n: Int) Str {
+			match n {
+				0 => "none",
+				1 | 2 | -1 => "few",
+				11..20 => "many",
+				_ => "lots",
+			}
+		}
+
+		fn kind(s: Str) Str {
+			match s {
+				"a" | "e" | "i" | "o" | "u" => "vowel",
+				_ => "consonant",
+			}
+		}
+
+		fn warm(c: Color) Bool {
+			match c {
+				Color::red | Color::green => true,
+				Color::blue | Color::black => false,
+			}
+		}
+
+		fn main() {
+			if size(2) != "few" or size(-1) != "few" or size(15) != "many" or size(3) != "lots" {
+				panic("Int or-pattern")
+			}
+			if kind("e") != "vowel" or kind("x") != "consonant" {
+				panic("Str or-pattern")
+			}
+			if not warm(Color::green) or warm(Color::black) {
+				panic("enum or-pattern")
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+func TestRunProgramInspect(t *testing.T) {
+	program := lowerSource(t, `
+		enum Color { red, green }
+		enum Shape { circle(Float64), dot }
+		struct Node { value: Int, next: Node? }
+
+		fn main() {
+			let node = Node{value: 1, next: Maybe::new(Node{value: 2, next: Maybe::new<Node>()})}
+			if "{node}" != "Node\{value: 1, next: some(Node\{value: 2, next: none})}" {
+				panic("struct: {node}")
+			}
+			let shapes = inspect([Shape::circle(1.5), Shape::dot])
+			if shapes != "[Shape::circle(1.50), Shape::dot]" {
+				panic("enum: {shapes}")
+			}
+			let counts: [Str: Color] = ["b": Color::green, "a": Color::red]
+			let empty: [Str: Int] = [:]
+			if inspect(counts) != "[\"a\": Color::red, \"b\": Color::green]" or inspect(empty) != "[:]" {
+				panic("map: {counts}")
+			}
+			let r: Int!Str = Result::err("bad")
+			if inspect(r) != "err(\"bad\")" or inspect('z') != "'z'" {
+				panic("result: {r}")
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
+
+func TestRunProgramInspectRendersCyclesOnce(t *testing.T) {
+	program := lowerSource(t, `
+		struct Node { name: Str, children: [Str: Node] }
+
+		fn main() {
+			mut kids: [Str: Node] = [:]
+			let root = Node{name: "root", children: kids}
+			kids.set("self", root)
+			let want = "Node\{name: \"root\", children: [\"self\": Node\{name: \"root\", children: <cycle>}]}"
+			if "{root}" != want {
+				panic("cycle: {root}")
+			}
+		}
+	`)
+	if err := RunProgram(program, []string{"ard", "run", "sample.ard"}); err != nil {
+		t.Fatalf("RunProgram error = %v", err)
+	}
+}
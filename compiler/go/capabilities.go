@@ -0,0 +1,528 @@
+package gotarget
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+)
+
+// Capability names a kind of access a sandboxed program must be granted
+// before it may call the Go functions that perform it.
+type Capability string
+
+const (
+	// CapabilityRead covers opening, listing, and inspecting files.
+	CapabilityRead Capability = "read"
+	// CapabilityWrite covers creating, changing, and removing files.
+	CapabilityWrite Capability = "write"
+	// CapabilityNet covers the functions of the networking packages: net,
+	// net/http and its subpackages, net/rpc, net/smtp, and crypto/tls.
+	CapabilityNet Capability = "net"
+	// CapabilityEnv covers reading and changing environment variables.
+	CapabilityEnv Capability = "env"
+	// CapabilityRun covers starting other processes and making system calls.
+	CapabilityRun Capability = "run"
+)
+
+// Capabilities lists the capabilities, in the order `--allow-<name>` flags
+// are documented.
+var Capabilities = []Capability{CapabilityRead, CapabilityWrite, CapabilityNet, CapabilityEnv, CapabilityRun}
+
+// Grant gives a sandboxed program one capability. A read or write grant with
+// a Path only covers files under that directory.
+type Grant struct {
+	Capability Capability
+	Path       string
+}
+
+// capabilityEnv renders grants for the generated program's runtime, which
+// reads them from ard.CapabilitiesEnv. Paths are made absolute against the
+// host's working directory.
+func capabilityEnv(grants []Grant) (string, error) {
+	lines := make([]string, 0, len(grants))
+	for _, grant := range grants {
+		if grant.Path == "" {
+			lines = append(lines, string(grant.Capability))
+			continue
+		}
+		root, err := filepath.Abs(grant.Path)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, string(grant.Capability)+"="+root)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// gatedFunction describes the capability a Go symbol needs. Its first paths
+// arguments are the files it touches. A zero gatedFunction is always safe.
+type gatedFunction struct {
+	capability Capability
+	paths      int
+}
+
+// sandboxPackage is what a sandboxed program may use from one Go package.
+// When symbols is nil, every function, value, and method of the package needs
+// capability, or nothing when capability is empty. Otherwise only the listed
+// symbols may be used. Methods are listed as `Type.Method`, or as `Type` for
+// every method of the type.
+type sandboxPackage struct {
+	capability Capability
+	symbols    map[string]gatedFunction
+}
+
+var (
+	safe      = sandboxPackage{}
+	netAccess = sandboxPackage{capability: CapabilityNet}
+	runAccess = sandboxPackage{capability: CapabilityRun}
+)
+
+// sandboxPackages are the Go packages a sandboxed program may import.
+// Anything else might reach the file system, network, or other processes in
+// a way the sandbox cannot check, so it is rejected.
+var sandboxPackages = map[string]sandboxPackage{
+	"bufio":              safe,
+	"bytes":              safe,
+	"cmp":                safe,
+	"compress/bzip2":     safe,
+	"compress/flate":     safe,
+	"compress/gzip":      safe,
+	"compress/lzw":       safe,
+	"compress/zlib":      safe,
+	"container/heap":     safe,
+	"container/list":     safe,
+	"container/ring":     safe,
+	"context":            safe,
+	"crypto":             safe,
+	"crypto/aes":         safe,
+	"crypto/cipher":      safe,
+	"crypto/des":         safe,
+	"crypto/ecdh":        safe,
+	"crypto/ecdsa":       safe,
+	"crypto/ed25519":     safe,
+	"crypto/elliptic":    safe,
+	"crypto/hkdf":        safe,
+	"crypto/hmac":        safe,
+	"crypto/md5":         safe,
+	"crypto/mlkem":       safe,
+	"crypto/pbkdf2":      safe,
+	"crypto/rand":        safe,
+	"crypto/rc4":         safe,
+	"crypto/rsa":         safe,
+	"crypto/sha1":        safe,
+	"crypto/sha256":      safe,
+	"crypto/sha3":        safe,
+	"crypto/sha512":      safe,
+	"crypto/subtle":      safe,
+	"crypto/tls":         netAccess,
+	"encoding":           safe,
+	"encoding/ascii85":   safe,
+	"encoding/asn1":      safe,
+	"encoding/base32":    safe,
+	"encoding/base64":    safe,
+	"encoding/binary":    safe,
+	"encoding/csv":       safe,
+	"encoding/gob":       safe,
+	"encoding/hex":       safe,
+	"encoding/json":      safe,
+	"encoding/pem":       safe,
+	"encoding/xml":       safe,
+	"errors":             safe,
+	"fmt":                safe,
+	"hash":               safe,
+	"hash/adler32":       safe,
+	"hash/crc32":         safe,
+	"hash/crc64":         safe,
+	"hash/fnv":           safe,
+	"hash/maphash":       safe,
+	"html":               safe,
+	"io":                 safe,
+	"io/fs":              {symbols: fsSymbols},
+	"io/ioutil":          {symbols: ioutilSymbols},
+	"iter":               safe,
+	"maps":               safe,
+	"math":               safe,
+	"math/big":           safe,
+	"math/bits":          safe,
+	"math/cmplx":         safe,
+	"math/rand":          safe,
+	"math/rand/v2":       safe,
+	"net":                netAccess,
+	"net/http":           netAccess,
+	"net/http/cookiejar": netAccess,
+	"net/http/httptest":  netAccess,
+	"net/http/httputil":  netAccess,
+	"net/mail":           safe,
+	"net/netip":          safe,
+	"net/rpc":            netAccess,
+	"net/rpc/jsonrpc":    netAccess,
+	"net/smtp":           netAccess,
+	"net/textproto":      netAccess,
+	"net/url":            safe,
+	"os":                 {symbols: osSymbols},
+	"os/exec":            runAccess,
+	"os/signal":          runAccess,
+	"path":               safe,
+	"path/filepath":      {symbols: filepathSymbols},
+	"regexp":             safe,
+	"regexp/syntax":      safe,
+	"slices":             safe,
+	"sort":               safe,
+	"strconv":            safe,
+	"strings":            safe,
+	"sync":               safe,
+	"sync/atomic":        safe,
+	"syscall":            runAccess,
+	"text/scanner":       safe,
+	"text/tabwriter":     safe,
+	"time":               safe,
+	"unicode":            safe,
+	"unicode/utf16":      safe,
+	"unicode/utf8":       safe,
+	"unique":             safe,
+}
+
+var (
+	readPath   = gatedFunction{CapabilityRead, 1}
+	writePath  = gatedFunction{CapabilityWrite, 1}
+	writePaths = gatedFunction{CapabilityWrite, 2}
+	// anyRead and anyWrite need an unscoped grant, because the files they
+	// touch are not among their arguments.
+	anyRead  = gatedFunction{capability: CapabilityRead}
+	anyWrite = gatedFunction{capability: CapabilityWrite}
+	env      = gatedFunction{capability: CapabilityEnv}
+	run      = gatedFunction{capability: CapabilityRun}
+)
+
+// osSymbols cover package os. Standard streams, exiting, and the process's
+// own state stay available to every program.
+var osSymbols = map[string]gatedFunction{
+	"Args":                {},
+	"Stdin":               {},
+	"Stdout":              {},
+	"Stderr":              {},
+	"Exit":                {},
+	"Getpid":              {},
+	"Getppid":             {},
+	"Getuid":              {},
+	"Geteuid":             {},
+	"Getgid":              {},
+	"Getegid":             {},
+	"Getgroups":           {},
+	"Getpagesize":         {},
+	"Getwd":               {},
+	"TempDir":             {},
+	"Expand":              {},
+	"NewFile":             {},
+	"Pipe":                {},
+	"SameFile":            {},
+	"NewSyscallError":     {},
+	"IsExist":             {},
+	"IsNotExist":          {},
+	"IsPermission":        {},
+	"IsTimeout":           {},
+	"IsPathSeparator":     {},
+	"PathSeparator":       {},
+	"PathListSeparator":   {},
+	"DevNull":             {},
+	"ErrInvalid":          {},
+	"ErrPermission":       {},
+	"ErrExist":            {},
+	"ErrNotExist":         {},
+	"ErrClosed":           {},
+	"ErrNoDeadline":       {},
+	"ErrDeadlineExceeded": {},
+	"ErrProcessDone":      {},
+	"O_RDONLY":            {},
+	"O_WRONLY":            {},
+	"O_RDWR":              {},
+	"O_APPEND":            {},
+	"O_CREATE":            {},
+	"O_EXCL":              {},
+	"O_SYNC":              {},
+	"O_TRUNC":             {},
+	"ModeDir":             {},
+	"ModeAppend":          {},
+	"ModeExclusive":       {},
+	"ModeTemporary":       {},
+	"ModeSymlink":         {},
+	"ModeDevice":          {},
+	"ModeNamedPipe":       {},
+	"ModeSocket":          {},
+	"ModeSetuid":          {},
+	"ModeSetgid":          {},
+	"ModeCharDevice":      {},
+	"ModeSticky":          {},
+	"ModeIrregular":       {},
+	"ModeType":            {},
+	"ModePerm":            {},
+	"Open":                readPath,
+	"ReadFile":            readPath,
+	"ReadDir":             readPath,
+	"Stat":                readPath,
+	"Lstat":               readPath,
+	"Readlink":            readPath,
+	"DirFS":               readPath,
+	"Chdir":               readPath,
+	"OpenFile":            writePath,
+	"Create":              writePath,
+	"CreateTemp":          writePath,
+	"MkdirTemp":           writePath,
+	"WriteFile":           writePath,
+	"Mkdir":               writePath,
+	"MkdirAll":            writePath,
+	"Remove":              writePath,
+	"RemoveAll":           writePath,
+	"Rename":              writePaths,
+	"Chmod":               writePath,
+	"Chown":               writePath,
+	"Lchown":              writePath,
+	"Chtimes":             writePath,
+	"Truncate":            writePath,
+	"Link":                writePaths,
+	"Symlink":             writePaths,
+	"CopyFS":              writePath,
+	"Getenv":              env,
+	"LookupEnv":           env,
+	"Environ":             env,
+	"ExpandEnv":           env,
+	"Setenv":              env,
+	"Unsetenv":            env,
+	"Clearenv":            env,
+	"Hostname":            env,
+	"UserHomeDir":         env,
+	"UserCacheDir":        env,
+	"UserConfigDir":       env,
+	"StartProcess":        run,
+	"FindProcess":         run,
+	"Process":             run,
+	"ProcessState":        {},
+	"Signal":              {},
+	"FileInfo":            {},
+	"FileMode":            {},
+	"DirEntry":            {},
+	"PathError":           {},
+	"LinkError":           {},
+	"SyscallError":        {},
+	// a File was opened by a gated call, or is a standard stream, so
+	// reading and writing it are safe. Changing its metadata is not
+	// covered by the grant that opened it.
+	"File.Read":             {},
+	"File.ReadAt":           {},
+	"File.ReadFrom":         {},
+	"File.ReadDir":          {},
+	"File.Readdir":          {},
+	"File.Readdirnames":     {},
+	"File.Write":            {},
+	"File.WriteAt":          {},
+	"File.WriteString":      {},
+	"File.WriteTo":          {},
+	"File.Seek":             {},
+	"File.Close":            {},
+	"File.Name":             {},
+	"File.Fd":               {},
+	"File.Stat":             {},
+	"File.Sync":             {},
+	"File.SetDeadline":      {},
+	"File.SetReadDeadline":  {},
+	"File.SetWriteDeadline": {},
+	"File.Chdir":            anyRead,
+	"File.Chmod":            anyWrite,
+	"File.Chown":            anyWrite,
+	"File.Truncate":         anyWrite,
+}
+
+// fsSymbols cover package io/fs. A file system can lead anywhere, such as
+// through a symlink inside an os.DirFS root, so reading one needs an unscoped
+// grant.
+var fsSymbols = map[string]gatedFunction{
+	"ValidPath":          {},
+	"FileInfoToDirEntry": {},
+	"FormatFileInfo":     {},
+	"FormatDirEntry":     {},
+	"ErrInvalid":         {},
+	"ErrPermission":      {},
+	"ErrExist":           {},
+	"ErrNotExist":        {},
+	"ErrClosed":          {},
+	"SkipDir":            {},
+	"SkipAll":            {},
+	"ModeDir":            {},
+	"ModeSymlink":        {},
+	"ModeType":           {},
+	"ModePerm":           {},
+	"FileInfo":           {},
+	"FileMode":           {},
+	"DirEntry":           {},
+	"File":               {},
+	"ReadDirFile":        {},
+	"PathError":          {},
+	"ReadFile":           anyRead,
+	"ReadDir":            anyRead,
+	"ReadLink":           anyRead,
+	"Stat":               anyRead,
+	"Lstat":              anyRead,
+	"Glob":               anyRead,
+	"WalkDir":            anyRead,
+	"Sub":                anyRead,
+	"FS":                 anyRead,
+	"ReadFileFS":         anyRead,
+	"ReadDirFS":          anyRead,
+	"ReadLinkFS":         anyRead,
+	"StatFS":             anyRead,
+	"GlobFS":             anyRead,
+	"SubFS":              anyRead,
+}
+
+// ioutilSymbols cover package io/ioutil, whose file functions predate the
+// ones in os.
+var ioutilSymbols = map[string]gatedFunction{
+	"ReadAll":   {},
+	"NopCloser": {},
+	"Discard":   {},
+	"ReadFile":  readPath,
+	"ReadDir":   readPath,
+	"WriteFile": writePath,
+	"TempFile":  writePath,
+	"TempDir":   writePath,
+}
+
+// filepathSymbols cover package path/filepath. Only the functions that read
+// the file system are gated; the rest work on strings.
+var filepathSymbols = map[string]gatedFunction{
+	"Abs":           {},
+	"Base":          {},
+	"Clean":         {},
+	"Dir":           {},
+	"Ext":           {},
+	"FromSlash":     {},
+	"IsAbs":         {},
+	"IsLocal":       {},
+	"Join":          {},
+	"Localize":      {},
+	"Match":         {},
+	"Rel":           {},
+	"Split":         {},
+	"SplitList":     {},
+	"ToSlash":       {},
+	"VolumeName":    {},
+	"Separator":     {},
+	"ListSeparator": {},
+	"SkipDir":       {},
+	"SkipAll":       {},
+	"ErrBadPattern": {},
+	"Glob":          readPath,
+	"Walk":          readPath,
+	"WalkDir":       readPath,
+	"EvalSymlinks":  readPath,
+}
+
+// sandboxAccess reports what using symbol from importPath needs in a
+// sandbox. It reports false for symbols the sandbox rejects outright. A
+// method's symbol is `Type.Method`.
+func sandboxAccess(importPath string, symbol string) (gatedFunction, bool) {
+	pkg, ok := sandboxPackages[importPath]
+	if !ok {
+		return gatedFunction{}, false
+	}
+	if pkg.symbols == nil {
+		return gatedFunction{capability: pkg.capability}, true
+	}
+	if gated, ok := pkg.symbols[symbol]; ok {
+		return gated, true
+	}
+	typeName, _, isMethod := strings.Cut(symbol, ".")
+	if !isMethod {
+		return gatedFunction{}, false
+	}
+	gated, ok := pkg.symbols[typeName]
+	return gated, ok
+}
+
+// checkSandboxImports rejects a program that imports a Go package the
+// sandbox does not know, before it runs. The package's init functions would
+// otherwise run before any call could be checked.
+func checkSandboxImports(program *air.Program) error {
+	for _, module := range program.Modules {
+		if err := checkSandboxPackages(module.Path, module.GoImports); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkSandboxPackages rejects the first of importPaths that the sandbox
+// does not know, naming owner as the importer.
+func checkSandboxPackages(owner string, importPaths []string) error {
+	for _, importPath := range importPaths {
+		if _, ok := sandboxPackages[importPath]; !ok {
+			return fmt.Errorf("%s: go:%s is not allowed in a sandbox", owner, importPath)
+		}
+	}
+	return nil
+}
+
+// runtimeSettingPrefix marks environment variables that configure Ard
+// programs themselves, such as ARD_LOG_LEVEL. The runtime reads
+// ARD_CAPABILITIES the same way, so no grant is needed for them.
+const runtimeSettingPrefix = "ARD_"
+
+// readsRuntimeSetting reports an os.Getenv or os.LookupEnv call whose name is
+// a constant runtime setting, which stays available to sandboxed programs.
+func readsRuntimeSetting(importPath string, symbol string, args []ast.Expr) bool {
+	if importPath != "os" || (symbol != "Getenv" && symbol != "LookupEnv") || len(args) != 1 {
+		return false
+	}
+	lit, ok := args[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return false
+	}
+	name, err := strconv.Unquote(lit.Value)
+	return err == nil && strings.HasPrefix(name, runtimeSettingPrefix)
+}
+
+// sandboxChecks returns the statements that run before a use of symbol from
+// importPath to stop a sandboxed program that may not use it.
+func (l *lowerer) sandboxChecks(importPath string, symbol string, args []ast.Expr) []ast.Stmt {
+	gated, ok := sandboxAccess(importPath, symbol)
+	if !ok {
+		return []ast.Stmt{&ast.ExprStmt{X: &ast.CallExpr{
+			Fun:  l.runtimeQualified("Forbid"),
+			Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote("go:" + importPath + "::" + symbol)}},
+		}}}
+	}
+	if gated.capability == "" || readsRuntimeSetting(importPath, symbol, args) {
+		return nil
+	}
+	return l.requireCapability(gated, args)
+}
+
+// requireCapability checks, before a gated call, that the host granted what
+// it needs. Path arguments are bound to temporaries so they are evaluated
+// once, in order, and checked against the grant's directories.
+func (l *lowerer) requireCapability(gated gatedFunction, args []ast.Expr) []ast.Stmt {
+	require := func(path ast.Expr) ast.Stmt {
+		return &ast.ExprStmt{X: &ast.CallExpr{
+			Fun:  l.runtimeQualified("Require"),
+			Args: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(string(gated.capability))}, path},
+		}}
+	}
+	if gated.paths == 0 || len(args) == 0 {
+		return []ast.Stmt{require(&ast.BasicLit{Kind: token.STRING, Value: `""`})}
+	}
+	var stmts []ast.Stmt
+	for i := 0; i < gated.paths && i < len(args); i++ {
+		temp := l.nextTemp()
+		stmts = append(stmts, &ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent(temp)}, Tok: token.DEFINE, Rhs: []ast.Expr{args[i]}})
+		args[i] = ast.NewIdent(temp)
+	}
+	for i := 0; i < gated.paths && i < len(args); i++ {
+		stmts = append(stmts, require(args[i]))
+	}
+	return stmts
+}
@@ -0,0 +1,253 @@
+package gotarget
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/air"
+)
+
+func TestRunProgramWithLimitsGatesCallsOnGrantedCapabilities(t *testing.T) {
+	program := lowerSource(t, `
+		use go:os
+
+		fn main() Int {
+			match os::LookupEnv("HOME") {
+				v => 7,
+				_ => 7,
+			}
+		}
+	`)
+	args := []string{"ard", "run", "sample.ard"}
+	cases := []struct {
+		name   string
+		limits RunLimits
+		want   int
+	}{
+		{name: "unsandboxed", limits: RunLimits{}, want: 7},
+		{name: "granted", limits: RunLimits{Sandboxed: true, Grants: []Grant{{Capability: CapabilityEnv}}}, want: 7},
+		{name: "not granted", limits: RunLimits{Sandboxed: true, Grants: []Grant{{Capability: CapabilityNet}}}, want: 2},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := RunProgramWithLimits(program, args, tc.limits)
+			var exit ExitError
+			if !errors.As(err, &exit) || exit.Code != tc.want {
+				t.Fatalf("RunProgramWithLimits error = %v, want exit status %d", err, tc.want)
+			}
+		})
+	}
+}
+
+// runSandboxed runs program and returns what it wrote to stderr.
+func runSandboxed(t *testing.T, program *air.Program, limits RunLimits) (string, error) {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	outputCh := make(chan []byte, 1)
+	go func() {
+		output, _ := io.ReadAll(reader)
+		outputCh <- output
+	}()
+	originalStderr := os.Stderr
+	os.Stderr = writer
+	runErr := RunProgramWithLimits(program, []string{"ard", "run", "sample.ard"}, limits)
+	os.Stderr = originalStderr
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	output := <-outputCh
+	_ = reader.Close()
+	return string(output), runErr
+}
+
+func TestRunProgramWithLimitsDeniesFileAccessOutsideGrants(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "granted")
+	outside := filepath.Join(dir, "outside")
+	for _, path := range []string{root, outside} {
+		if err := os.Mkdir(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	secret := filepath.Join(outside, "secret.txt")
+
+	cases := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name: "io/ioutil",
+			source: `
+				use go:io/ioutil
+
+				fn main() Int {
+					match ioutil::ReadFile("SECRET") {
+						ok(data) => 0,
+						err(e) => 1,
+					}
+				}
+			`,
+			want: "runtime error: capability not granted: read access to SECRET",
+		},
+		{
+			name: "path/filepath",
+			source: `
+				use go:path/filepath
+
+				fn main() Int {
+					match filepath::Glob("OUTSIDE/*") {
+						ok(matches) => 0,
+						err(e) => 1,
+					}
+				}
+			`,
+			want: "runtime error: capability not granted: read access to OUTSIDE/*",
+		},
+		{
+			name: "symlink",
+			source: `
+				use go:os
+
+				fn main() Int {
+					match os::ReadFile("ROOT/link/secret.txt") {
+						ok(data) => 0,
+						err(e) => 1,
+					}
+				}
+			`,
+			want: "runtime error: capability not granted: read access to ROOT/link/secret.txt",
+		},
+		{
+			name: "io/fs",
+			source: `
+				use go:io/fs
+				use go:os
+
+				fn main() Int {
+					match fs::ReadFile(os::DirFS("ROOT"), "link/secret.txt") {
+						ok(data) => 0,
+						err(e) => 1,
+					}
+				}
+			`,
+			want: "runtime error: capability not granted: read (run with --allow-read)",
+		},
+		{
+			name: "unlisted os function",
+			source: `
+				use go:os
+
+				fn main() Int {
+					match os::OpenRoot("ROOT") {
+						ok(dir) => 0,
+						err(e) => 1,
+					}
+				}
+			`,
+			want: "runtime error: go:os::OpenRoot is not allowed in a sandbox",
+		},
+	}
+	replacer := strings.NewReplacer("SECRET", secret, "OUTSIDE", outside, "ROOT", root)
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			program := lowerSource(t, replacer.Replace(tc.source))
+			stderr, err := runSandboxed(t, program, RunLimits{Sandboxed: true, Grants: []Grant{{Capability: CapabilityRead, Path: root}}})
+			var exit ExitError
+			if !errors.As(err, &exit) || exit.Code != 2 {
+				t.Fatalf("RunProgramWithLimits error = %v, want exit status 2", err)
+			}
+			if want := replacer.Replace(tc.want); !strings.HasPrefix(stderr, want) {
+				t.Fatalf("stderr = %q, want it to start with %q", stderr, want)
+			}
+			if strings.Contains(stderr, "goroutine") {
+				t.Fatalf("stderr = %q, want no goroutine trace", stderr)
+			}
+		})
+	}
+}
+
+func TestRunProgramWithLimitsRejectsUnknownGoPackages(t *testing.T) {
+	program := lowerSource(t, `
+		use go:runtime/debug
+
+		fn main() {
+			debug::FreeOSMemory()
+		}
+	`)
+	err := RunProgramWithLimits(program, []string{"ard", "run", "sample.ard"}, RunLimits{Sandboxed: true})
+	if err == nil || !strings.HasSuffix(err.Error(), "go:runtime/debug is not allowed in a sandbox") {
+		t.Fatalf("RunProgramWithLimits error = %v, want the import rejected", err)
+	}
+	if err := RunProgramWithLimits(program, []string{"ard", "run", "sample.ard"}, RunLimits{}); err != nil {
+		t.Fatalf("RunProgramWithLimits error = %v, want unsandboxed programs to run", err)
+	}
+}
+
+func TestRunBinaryWithLimitsChecksRecordedGoPackages(t *testing.T) {
+	program := lowerSource(t, `
+		use go:runtime/debug
+
+		fn main() {
+			debug::FreeOSMemory()
+		}
+	`)
+	builtPath, err := BuildProgram(program, filepath.Join(t.TempDir(), "app"))
+	if err != nil {
+		t.Fatalf("BuildProgram error = %v", err)
+	}
+	built, err := ReadBuildMetadata(builtPath)
+	if err != nil || !slices.Contains(built.GoImports, "runtime/debug") {
+		t.Fatalf("metadata = %+v, error = %v, want runtime/debug recorded", built, err)
+	}
+	err = RunBinaryWithLimits(builtPath, nil, RunLimits{Sandboxed: true})
+	if err == nil || !strings.HasSuffix(err.Error(), "go:runtime/debug is not allowed in a sandbox") {
+		t.Fatalf("RunBinaryWithLimits error = %v, want the import rejected", err)
+	}
+	if err := RunBinaryWithLimits(builtPath, nil, RunLimits{}); err != nil {
+		t.Fatalf("RunBinaryWithLimits error = %v, want unsandboxed binaries to run", err)
+	}
+}
+
+func TestSandboxAccessOnlyAllowsKnownSymbols(t *testing.T) {
+	cases := []struct {
+		importPath, symbol string
+		want               gatedFunction
+		allowed            bool
+	}{
+		{"strings", "ToUpper", gatedFunction{}, true},
+		{"net/url", "Parse", gatedFunction{}, true},
+		{"net/http", "Get", gatedFunction{capability: CapabilityNet}, true},
+		{"os", "Stdout", gatedFunction{}, true},
+		{"os", "File.Write", gatedFunction{}, true},
+		{"os", "File.Chmod", gatedFunction{capability: CapabilityWrite}, true},
+		{"os", "FileInfo.Name", gatedFunction{}, true},
+		{"os", "Process.Kill", gatedFunction{capability: CapabilityRun}, true},
+		{"io/ioutil", "WriteFile", gatedFunction{CapabilityWrite, 1}, true},
+		{"path/filepath", "Join", gatedFunction{}, true},
+		{"path/filepath", "WalkDir", gatedFunction{CapabilityRead, 1}, true},
+		{"os", "OpenRoot", gatedFunction{}, false},
+		{"runtime/debug", "FreeOSMemory", gatedFunction{}, false},
+		{"github.com/example/fs", "ReadFile", gatedFunction{}, false},
+	}
+	for _, tc := range cases {
+		got, ok := sandboxAccess(tc.importPath, tc.symbol)
+		if ok != tc.allowed || got != tc.want {
+			t.Errorf("sandboxAccess(%q, %q) = %v, %v, want %v, %v", tc.importPath, tc.symbol, got, ok, tc.want, tc.allowed)
+		}
+	}
+}
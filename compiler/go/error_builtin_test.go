@@ -22,6 +22,12 @@ func TestRunProgramBuiltinErrorInterop(t *testing.T) {
 	}
 	if err := os.WriteFile(filepath.Join(projectDir, "ffi", "ffi.go"), []byte(`package ffi
 
+import "errors"
+
+var Root = errors.New("root")
+
+func IsRoot(err error) bool { return errors.Is(err, Root) }
+
 func Message(err error) string { return err.Error() }
 
 var remembered error
@@ -115,6 +121,14 @@ fn main() {
     err(_) => false,
   }
   if not succeeded { panic("expected void success") }
+  let wrapped = Error::wrap(ffi::Root, "context")
+  if not ffi::Message(wrapped) == "context: root" { panic("Error::wrap message failed") }
+  if not ffi::IsRoot(wrapped) { panic("Error::wrap hid its cause from Go") }
+  let unwrapped = match Error::cause(wrapped) {
+    cause => ffi::IsRoot(cause),
+    _ => false,
+  }
+  if not unwrapped { panic("Error::cause lost the wrapped error") }
 }
 `), 0o644); err != nil {
 		t.Fatal(err)
@@ -104,3 +104,25 @@ fn main() Bool {
 		t.Fatalf("got %s, want true", got)
 	}
 }
+
+// A Result- or Maybe-returning closure passed where a generic parameter
+// returns a type parameter must be adapted from the tuple ABI to the packed
+// value Go instantiates the parameter with.
+func TestGoTargetGenericFunctionArgPacksTupleABI(t *testing.T) {
+	src := `fn call(f: fn() $T) $T { f() }
+
+fn main() Bool {
+  let ok = call(fn() Int!Str { Result::ok(3) })
+  let failed = call(fn() Int!Str { Result::err("boom") })
+  let some = call(fn() Str? { Maybe::new("x") })
+  let msg = match failed {
+    ok(_) => "",
+    err(e) => e,
+  }
+  ok.or(0) == 3 and msg == "boom" and some.or("") == "x"
+}`
+	program := lowerParitySource(t, src)
+	if got := runGoTargetParityJSON(t, program); got != "true" {
+		t.Fatalf("got %s, want true", got)
+	}
+}
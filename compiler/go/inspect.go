@@ -0,0 +1,274 @@
+package gotarget
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"path"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+)
+
+// lowerInspect renders a value the way it is written in Ard source. User types
+// and containers render through package-level functions generated per type,
+// so recursive types render through recursive calls.
+func (l *lowerer) lowerInspect(fn air.Function, expr air.Expr) (loweredExpr, error) {
+	if expr.Target == nil {
+		return loweredExpr{}, fmt.Errorf("inspect missing target")
+	}
+	target, err := l.lowerExpr(fn, *expr.Target)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	typeID := expr.Target.Type
+	if l.hasTypeParam(typeID, map[air.TypeID]bool{}) {
+		// generated functions cannot name a generic function's type
+		// parameters, so these values render as to_str does
+		return loweredExpr{stmts: target.stmts, expr: &ast.CallExpr{Fun: l.qualified("fmt", "fmt", "Sprint"), Args: []ast.Expr{target.expr}}}, nil
+	}
+	const placeholder = "ardInspectTarget"
+	rendered, err := l.inspectValue(typeID, placeholder, l.inspectRuntime()+".InspectSeen{}")
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	parsed, err := parser.ParseExpr(rendered)
+	if err != nil {
+		return loweredExpr{}, fmt.Errorf("inspect %s: %w", rendered, err)
+	}
+	// renderings read their value once, so the target replaces the
+	// placeholder in place rather than being spilled to a temp
+	ast.Inspect(parsed, func(node ast.Node) bool {
+		if call, ok := node.(*ast.CallExpr); ok {
+			for i, arg := range call.Args {
+				if ident, ok := arg.(*ast.Ident); ok && ident.Name == placeholder {
+					call.Args[i] = target.expr
+				}
+			}
+		}
+		return true
+	})
+	return loweredExpr{stmts: target.stmts, expr: parsed}, nil
+}
+
+// inspectValue is the Go expression that renders value, a value of typeID,
+// passing seen along to the inspectors it calls. Primitives render inline and
+// read value once.
+func (l *lowerer) inspectValue(typeID air.TypeID, value string, seen string) (string, error) {
+	strconvPkg := func() string { return l.registerImport("strconv", "strconv") }
+	switch l.typeKind(typeID) {
+	case air.TypeVoid:
+		return `"void"`, nil
+	case air.TypeInt:
+		return fmt.Sprintf("%s.Itoa(%s)", strconvPkg(), value), nil
+	case air.TypeByte:
+		return fmt.Sprintf("%s.FormatUint(uint64(%s), 10)", strconvPkg(), value), nil
+	case air.TypeBool:
+		return fmt.Sprintf("%s.FormatBool(%s)", strconvPkg(), value), nil
+	case air.TypeFloat64:
+		return fmt.Sprintf("%s.FormatFloat(%s, 'f', 2, 64)", strconvPkg(), value), nil
+	case air.TypeStr:
+		return fmt.Sprintf("%s.Quote(%s)", strconvPkg(), value), nil
+	case air.TypeRune:
+		return fmt.Sprintf("%s.QuoteRune(%s)", strconvPkg(), value), nil
+	case air.TypeStruct, air.TypeEnum, air.TypeMaybe, air.TypeResult, air.TypeUnion, air.TypeList, air.TypeFixedArray, air.TypeMap:
+		name, err := l.inspector(typeID)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s(%s, %s)", name, value, seen), nil
+	default:
+		return fmt.Sprintf("%s.Sprint(%s)", l.registerImport("fmt", "fmt"), value), nil
+	}
+}
+
+// inspector names the function of the current package that renders values of
+// typeID, generating it and the inspectors it calls on first use.
+func (l *lowerer) inspector(typeID air.TypeID) (string, error) {
+	if name, ok := l.inspectors[typeID]; ok {
+		return name, nil
+	}
+	name := fmt.Sprintf("ardInspect%d", typeID)
+	l.inspectors[typeID] = name
+	goType, err := l.goType(typeID)
+	if err != nil {
+		return "", err
+	}
+	var typeText bytes.Buffer
+	if err := format.Node(&typeText, token.NewFileSet(), goType); err != nil {
+		return "", err
+	}
+	body, err := l.inspectorBody(l.program.Types[typeID-1])
+	if err != nil {
+		return "", err
+	}
+	src := fmt.Sprintf("package p\n\nfunc %s(value %s, seen %s.InspectSeen) string {\n%s\n}\n", name, typeText.String(), l.inspectRuntime(), strings.Join(body, "\n"))
+	file, err := parser.ParseFile(token.NewFileSet(), "inspect.go", src, 0)
+	if err != nil {
+		return "", fmt.Errorf("inspect %s: %w", l.program.Types[typeID-1].Name, err)
+	}
+	l.inspectorDecls = append(l.inspectorDecls, file.Decls...)
+	return name, nil
+}
+
+// inspectorBody renders value, a value of the type info describes. Maps,
+// lists, and mutable fields can be shared, and so can form cycles, so they
+// render through seen.Once.
+func (l *lowerer) inspectorBody(info air.TypeInfo) ([]string, error) {
+	var err error
+	render := func(typeID air.TypeID, value string) string {
+		rendered, renderErr := l.inspectValue(typeID, value, "seen")
+		if renderErr != nil && err == nil {
+			err = renderErr
+		}
+		return rendered
+	}
+	var lines []string
+	switch info.Kind {
+	case air.TypeStruct:
+		parts := make([]string, len(info.Fields))
+		for i, field := range info.DeclaredFields() {
+			value := "value." + l.goFieldName(info, field.Name)
+			var rendered string
+			switch {
+			case field.Mutable && l.isTraitObjectType(field.Type):
+				rendered = fmt.Sprintf("%s.Sprint(%s)", l.registerImport("fmt", "fmt"), value)
+			case field.Mutable:
+				rendered = fmt.Sprintf("seen.Once(%s, func() string { return %s })", value, render(field.Type, "*"+value))
+			default:
+				rendered = render(field.Type, value)
+			}
+			parts[i] = fmt.Sprintf("%q + %s", field.Name+": ", rendered)
+		}
+		lines = []string{"return " + joinRendered(fmt.Sprintf("%q", l.inspectTypeName(info)+"{"), parts, `"}"`)}
+	case air.TypeEnum:
+		name := l.inspectTypeName(info)
+		subject := "value"
+		if info.HasPayload() {
+			subject = "value." + enumTagFieldName(info)
+		}
+		lines = []string{fmt.Sprintf("switch %s {", subject)}
+		for i, variant := range info.Variants {
+			rendered := fmt.Sprintf("%q", name+"::"+variant.Name)
+			if len(variant.Payload) > 0 {
+				values := make([]string, len(variant.Payload))
+				for j, payload := range variant.Payload {
+					values[j] = render(payload, fmt.Sprintf("value.%s.%s", enumPayloadFieldName(info, i), enumPayloadValueFieldName(j)))
+				}
+				rendered = joinRendered(fmt.Sprintf("%q", name+"::"+variant.Name+"("), values, `")"`)
+			}
+			lines = append(lines, fmt.Sprintf("case %d:", variant.Discriminant), "return "+rendered)
+		}
+		lines = append(lines, "}", fmt.Sprintf("return %s.Itoa(int(%s))", l.registerImport("strconv", "strconv"), subject))
+	case air.TypeMaybe:
+		value := "value.Value()"
+		if info.ElemMutable {
+			value = "*" + value
+		}
+		lines = []string{
+			"if value.IsNone() {",
+			`return "none"`,
+			"}",
+			"return " + joinRendered(`"some("`, []string{render(info.Elem, value)}, `")"`),
+		}
+	case air.TypeResult:
+		lines = []string{
+			"if value.Ok {",
+			"return " + joinRendered(`"ok("`, []string{render(info.Value, "value.Value")}, `")"`),
+			"}",
+			"return " + joinRendered(`"err("`, []string{render(info.Error, "value.Err")}, `")"`),
+		}
+	case air.TypeUnion:
+		lines = []string{fmt.Sprintf("switch value.%s {", unionTagFieldName(info))}
+		for _, member := range info.Members {
+			lines = append(lines, fmt.Sprintf("case %d:", member.Tag), "return "+render(member.Type, "value."+unionMemberFieldName(info, member)))
+		}
+		lines = append(lines, "}", fmt.Sprintf("return %s.Sprint(value)", l.registerImport("fmt", "fmt")))
+	case air.TypeList, air.TypeFixedArray:
+		lines = []string{
+			"parts := make([]string, 0, len(value))",
+			"for _, item := range value {",
+			"parts = append(parts, " + render(info.Elem, "item") + ")",
+			"}",
+			fmt.Sprintf(`return "[" + %s.Join(parts, ", ") + "]"`, l.registerImport("strings", "strings")),
+		}
+		if info.Kind == air.TypeList {
+			lines = inspectOnce(lines)
+		}
+	case air.TypeMap:
+		lines = inspectOnce([]string{
+			"if len(value) == 0 {",
+			`return "[:]"`,
+			"}",
+			"parts := make([]string, 0, len(value))",
+			fmt.Sprintf("for _, key := range %s.SortedKeys(value) {", l.inspectRuntime()),
+			fmt.Sprintf(`parts = append(parts, %s + ": " + %s)`, render(info.Key, "key"), render(info.Value, "value[key]")),
+			"}",
+			fmt.Sprintf(`return "[" + %s.Join(parts, ", ") + "]"`, l.registerImport("strings", "strings")),
+		})
+	default:
+		lines = []string{fmt.Sprintf("return %s.Sprint(value)", l.registerImport("fmt", "fmt"))}
+	}
+	return lines, err
+}
+
+// inspectOnce wraps the lines rendering value so a value nested in itself
+// renders as `<cycle>`.
+func inspectOnce(lines []string) []string {
+	wrapped := append([]string{"return seen.Once(value, func() string {"}, lines...)
+	return append(wrapped, "})")
+}
+
+func (l *lowerer) inspectRuntime() string {
+	return l.registerImport("ard", path.Join(generatedModulePath(l.projectInfo), "internal", "ard"))
+}
+
+// hasTypeParam reports whether typeID refers to a generic type parameter
+// anywhere in its structure.
+func (l *lowerer) hasTypeParam(typeID air.TypeID, seen map[air.TypeID]bool) bool {
+	if !validTypeID(l.program, typeID) || seen[typeID] {
+		return false
+	}
+	seen[typeID] = true
+	info := l.program.Types[typeID-1]
+	if info.Kind == air.TypeParam {
+		return true
+	}
+	children := append([]air.TypeID{info.Elem, info.Key, info.Value, info.Error}, info.GenericArgs...)
+	for _, field := range info.Fields {
+		children = append(children, field.Type)
+	}
+	for _, variant := range info.Variants {
+		children = append(children, variant.Payload...)
+	}
+	for _, member := range info.Members {
+		children = append(children, member.Type)
+	}
+	for _, child := range children {
+		if l.hasTypeParam(child, seen) {
+			return true
+		}
+	}
+	return false
+}
+
+// inspectTypeName is the name a user type is written with in Ard source. An
+// instance of a generic type uses its definition's name.
+func (l *lowerer) inspectTypeName(info air.TypeInfo) string {
+	if validTypeID(l.program, info.Generic) {
+		return l.program.Types[info.Generic-1].Name
+	}
+	return info.Name
+}
+
+// joinRendered concatenates open, the rendered parts separated by commas, and
+// close.
+func joinRendered(open string, parts []string, close string) string {
+	if len(parts) == 0 {
+		return open + " + " + close
+	}
+	return open + " + " + strings.Join(parts, ` + ", " + `) + " + " + close
+}
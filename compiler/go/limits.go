@@ -0,0 +1,166 @@
+package gotarget
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	runtimesrc "github.com/akonwi/ard/runtime"
+)
+
+// RunLimits bounds a program run by RunProgramWithLimits. A zero field leaves
+// that resource unbounded. Programs run as native Go processes, so there is no
+// instruction count to cap; Timeout bounds how long they may compute.
+type RunLimits struct {
+	// Timeout is the wall-clock time the program may run.
+	Timeout time.Duration
+	// MaxMemory is the resident memory, in bytes, the program may use. The
+	// program's Go runtime is also asked to collect garbage before reaching it
+	// (GOMEMLIMIT). Resident memory is only measured on Linux; elsewhere that
+	// request is the whole limit.
+	MaxMemory int64
+	// Sandboxed refuses to run a program that imports a Go package the
+	// sandbox does not know, and stops the program with a runtime error when
+	// it uses a Go symbol that needs a Capability missing from Grants.
+	Sandboxed bool
+	Grants    []Grant
+	// Logs receives the records the program writes with ard/log. When nil,
+	// records go to the program's stderr.
+	Logs io.Writer
+}
+
+// logFDEnv tells ard/log which file descriptor the host opened for records.
+const logFDEnv = "ARD_LOG_FD"
+
+// RuntimeLimit names a bound in RunLimits.
+type RuntimeLimit string
+
+const (
+	LimitTimeout RuntimeLimit = "timeout"
+	LimitMemory  RuntimeLimit = "memory"
+)
+
+// RuntimeLimitExceeded reports that a program was stopped because it
+// exceeded one of its RunLimits.
+type RuntimeLimitExceeded struct {
+	Limit  RuntimeLimit
+	Limits RunLimits
+}
+
+func (e RuntimeLimitExceeded) Error() string {
+	if e.Limit == LimitMemory {
+		return fmt.Sprintf("runtime limit exceeded: used more than %d bytes of memory", e.Limits.MaxMemory)
+	}
+	return fmt.Sprintf("runtime limit exceeded: ran longer than %s", e.Limits.Timeout)
+}
+
+// memoryPollInterval is how often a program's resident memory is measured
+// against RunLimits.MaxMemory.
+const memoryPollInterval = 10 * time.Millisecond
+
+// runWithLimits runs cmd to completion, killing it once it exceeds limits.
+func runWithLimits(cmd *exec.Cmd, limits RunLimits) error {
+	if limits.MaxMemory > 0 {
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, "GOMEMLIMIT="+strconv.FormatInt(limits.MaxMemory, 10))
+	}
+	if limits.Sandboxed {
+		grants, err := capabilityEnv(limits.Grants)
+		if err != nil {
+			return err
+		}
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.Env = append(cmd.Env, runtimesrc.CapabilitiesEnv+"="+grants)
+	}
+	var logsCopied chan struct{}
+	if limits.Logs != nil {
+		reader, writer, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+		defer reader.Close()
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
+		}
+		cmd.ExtraFiles = append(cmd.ExtraFiles, writer)
+		cmd.Env = append(cmd.Env, logFDEnv+"="+strconv.Itoa(2+len(cmd.ExtraFiles)))
+		if err := cmd.Start(); err != nil {
+			writer.Close()
+			return err
+		}
+		// The program holds its own copy of the write end, so the copy below
+		// ends once the program exits.
+		writer.Close()
+		logsCopied = make(chan struct{})
+		go func() {
+			_, _ = io.Copy(limits.Logs, reader)
+			close(logsCopied)
+		}()
+	} else if err := cmd.Start(); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() {
+		err := cmd.Wait()
+		if logsCopied != nil {
+			<-logsCopied
+		}
+		done <- err
+	}()
+
+	var timeout <-chan time.Time
+	if limits.Timeout > 0 {
+		timer := time.NewTimer(limits.Timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var poll <-chan time.Time
+	if limits.MaxMemory > 0 {
+		ticker := time.NewTicker(memoryPollInterval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+	stop := func(limit RuntimeLimit) error {
+		_ = cmd.Process.Kill()
+		<-done
+		return RuntimeLimitExceeded{Limit: limit, Limits: limits}
+	}
+	for {
+		select {
+		case err := <-done:
+			return err
+		case <-timeout:
+			return stop(LimitTimeout)
+		case <-poll:
+			if used, ok := residentMemory(cmd.Process.Pid); ok && used > limits.MaxMemory {
+				return stop(LimitMemory)
+			}
+		}
+	}
+}
+
+// residentMemory is the resident set size of process pid in bytes. It
+// reports false where /proc is unavailable.
+func residentMemory(pid int) (int64, bool) {
+	statm, err := os.ReadFile(fmt.Sprintf("/proc/%d/statm", pid))
+	if err != nil {
+		return 0, false
+	}
+	fields := strings.Fields(string(statm))
+	if len(fields) < 2 {
+		return 0, false
+	}
+	pages, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return pages * int64(os.Getpagesize()), true
+}
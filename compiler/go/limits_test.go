@@ -0,0 +1,97 @@
+package gotarget
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunProgramWithLimitsStopsLongRunningPrograms(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() {
+			mut n = 0
+			while true {
+				n = n + 1
+			}
+		}
+	`)
+	limits := RunLimits{Timeout: 200 * time.Millisecond}
+	err := RunProgramWithLimits(program, []string{"ard", "run", "sample.ard"}, limits)
+	var exceeded RuntimeLimitExceeded
+	if !errors.As(err, &exceeded) || exceeded.Limit != LimitTimeout {
+		t.Fatalf("RunProgramWithLimits error = %v, want a timeout", err)
+	}
+	if err.Error() != "runtime limit exceeded: ran longer than 200ms" {
+		t.Fatalf("error message = %q", err.Error())
+	}
+}
+
+func TestRunProgramWithLimitsStopsProgramsThatUseTooMuchMemory(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("resident memory is only measured on Linux")
+	}
+	program := lowerSource(t, `
+		fn main() {
+			mut chunks: [[Int]] = []
+			while true {
+				mut chunk: [Int] = []
+				for i in 0..100000 {
+					chunk.push(i)
+				}
+				chunks.push(chunk)
+			}
+		}
+	`)
+	limits := RunLimits{Timeout: time.Minute, MaxMemory: 64 << 20}
+	err := RunProgramWithLimits(program, []string{"ard", "run", "sample.ard"}, limits)
+	var exceeded RuntimeLimitExceeded
+	if !errors.As(err, &exceeded) || exceeded.Limit != LimitMemory {
+		t.Fatalf("RunProgramWithLimits error = %v, want a memory limit", err)
+	}
+}
+
+func TestRunProgramWithLimitsLeavesQuickProgramsAlone(t *testing.T) {
+	program := lowerSource(t, `
+		fn main() Int {
+			3
+		}
+	`)
+	limits := RunLimits{Timeout: time.Minute, MaxMemory: 1 << 30}
+	err := RunProgramWithLimits(program, []string{"ard", "run", "sample.ard"}, limits)
+	var exit ExitError
+	if !errors.As(err, &exit) || exit.Code != 3 {
+		t.Fatalf("RunProgramWithLimits error = %v, want exit status 3", err)
+	}
+}
+
+func TestRunProgramWithLimitsCapturesLogs(t *testing.T) {
+	program := lowerSource(t, `
+		use ard/log
+
+		fn main() {
+			log::debug("hidden")
+			log::info("started", ["port": "80", "host": "local host"])
+			log::error("stopped")
+		}
+	`)
+	t.Setenv("ARD_LOG_LEVEL", "info")
+	t.Setenv("ARD_LOG_FORMAT", "text")
+	var logs bytes.Buffer
+	limits := RunLimits{Sandboxed: true, Logs: &logs}
+	if err := RunProgramWithLimits(program, []string{"ard", "run", "sample.ard"}, limits); err != nil {
+		t.Fatalf("RunProgramWithLimits error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("captured %d records, want 2:\n%s", len(lines), logs.String())
+	}
+	for i, want := range []string{` INFO started host="local host" port=80`, ` ERROR stopped`} {
+		timestamp, record, _ := strings.Cut(lines[i], " ")
+		if _, err := time.Parse(time.RFC3339, timestamp); err != nil || " "+record != want {
+			t.Fatalf("record %d = %q, want a timestamp then %q", i, lines[i], want)
+		}
+	}
+}
@@ -44,6 +44,10 @@ type lowerer struct {
 	useModulePackages       bool
 	forceValueResultReturns bool
 	namePlan                *namePlan
+	// inspectors names the current module's generated inspect functions by
+	// the type they render; inspectorDecls holds their declarations.
+	inspectors     map[air.TypeID]string
+	inspectorDecls []ast.Decl
 
 	// When the entry root lives in a module named `main` (main.ard) that no
 	// other module imports, that module is emitted as the root `package main`
@@ -78,6 +82,7 @@ func lowerProgram(program *air.Program, options Options) (map[string]*ast.File,
 		rootModuleID := program.Functions[rootID].Module
 		if strings.TrimSuffix(filepath.Base(program.Modules[rootModuleID].Path), filepath.Ext(program.Modules[rootModuleID].Path)) == "main" &&
 			l.isVoidType(program.Functions[rootID].Signature.Return) &&
+			len(program.Functions[rootID].Signature.Params) == 0 &&
 			!moduleIsImported(program, rootModuleID) {
 			l.entryAsMainPackage = true
 			l.entryMainModuleID = rootModuleID
@@ -131,22 +136,36 @@ func lowerProgram(program *air.Program, options Options) (map[string]*ast.File,
 // as an ordinary package; `main` is never a transpiled Ard module (ADR 0031).
 func (l *lowerer) synthesizeEntryMain(rootID air.FunctionID, entryModuleID air.ModuleID) (*ast.File, error) {
 	fn := l.program.Functions[rootID]
-	if len(fn.Signature.Params) != 0 {
-		return nil, fmt.Errorf("entry function parameters are not supported yet")
+	if len(fn.Signature.Params) > 1 {
+		return nil, fmt.Errorf("entry function can only take a single [Str] parameter")
 	}
 	alias := modulePackageName(l.program, entryModuleID)
 	importPath := l.moduleImportPath(entryModuleID)
 	call := &ast.CallExpr{Fun: &ast.SelectorExpr{X: ast.NewIdent(alias), Sel: ast.NewIdent(l.functionName(fn))}}
+	// `fn main(args: [Str])` receives the arguments after the program name,
+	// and `fn main() Int` exits with the returned code.
+	usesOS := false
+	if len(fn.Signature.Params) == 1 {
+		call.Args = []ast.Expr{&ast.SliceExpr{X: &ast.SelectorExpr{X: ast.NewIdent("os"), Sel: ast.NewIdent("Args")}, Low: &ast.BasicLit{Kind: token.INT, Value: "1"}}}
+		usesOS = true
+	}
 	var stmt ast.Stmt
-	if l.isVoidType(fn.Signature.Return) {
+	switch {
+	case l.isVoidType(fn.Signature.Return):
 		stmt = &ast.ExprStmt{X: call}
-	} else {
+	case l.typeKind(fn.Signature.Return) == air.TypeInt:
+		stmt = &ast.ExprStmt{X: &ast.CallExpr{Fun: &ast.SelectorExpr{X: ast.NewIdent("os"), Sel: ast.NewIdent("Exit")}, Args: []ast.Expr{call}}}
+		usesOS = true
+	default:
 		stmt = &ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent("_")}, Tok: token.ASSIGN, Rhs: []ast.Expr{call}}
 	}
 	importDecl := &ast.GenDecl{Tok: token.IMPORT, Specs: []ast.Spec{&ast.ImportSpec{
 		Name: ast.NewIdent(alias),
 		Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(importPath)},
 	}}}
+	if usesOS {
+		importDecl.Specs = append(importDecl.Specs, &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote("os")}})
+	}
 	mainDecl := &ast.FuncDecl{Name: ast.NewIdent("main"), Type: &ast.FuncType{Params: &ast.FieldList{}}, Body: &ast.BlockStmt{List: []ast.Stmt{stmt}}}
 	return &ast.File{Name: ast.NewIdent("main"), Decls: []ast.Decl{importDecl, mainDecl}}, nil
 }
@@ -160,6 +179,8 @@ func (l *lowerer) lowerModule(module air.Module) (*ast.File, error) {
 	l.runtimeHelpers = map[string]bool{}
 	l.mutableTraitRefs = map[air.TraitID]bool{}
 	l.emittedMutableTraitRefs = map[air.TraitID]bool{}
+	l.inspectors = map[air.TypeID]string{}
+	l.inspectorDecls = nil
 	decls := []ast.Decl{}
 	rootID, hasRoot := findRootFunction(l.program)
 	mainModuleID := l.mainModuleID(rootID, hasRoot)
@@ -203,6 +224,7 @@ func (l *lowerer) lowerModule(module air.Module) (*ast.File, error) {
 			decls = append(decls, methodDecl)
 		}
 	}
+	decls = append(decls, l.inspectorDecls...)
 	mutableDecls, err := l.markedMutableTraitRefDecls()
 	if err != nil {
 		return nil, err
@@ -212,23 +234,38 @@ func (l *lowerer) lowerModule(module air.Module) (*ast.File, error) {
 	if l.importErr != nil {
 		return nil, l.importErr
 	}
+	usedImports := map[string]string{}
 	if len(l.currentImports) > 0 {
-		usedImports := l.usedImports(decls)
-		if len(usedImports) > 0 {
-			importDecl := &ast.GenDecl{Tok: token.IMPORT}
-			aliases := make([]string, 0, len(usedImports))
-			for alias := range usedImports {
-				aliases = append(aliases, alias)
-			}
-			sort.Strings(aliases)
-			for _, alias := range aliases {
-				importDecl.Specs = append(importDecl.Specs, &ast.ImportSpec{
-					Name: ast.NewIdent(alias),
-					Path: &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", usedImports[alias])},
-				})
-			}
-			decls = append([]ast.Decl{importDecl}, decls...)
+		usedImports = l.usedImports(decls)
+	}
+	importDecl := &ast.GenDecl{Tok: token.IMPORT}
+	aliases := make([]string, 0, len(usedImports))
+	usedPaths := map[string]bool{}
+	for alias, importPath := range usedImports {
+		aliases = append(aliases, alias)
+		usedPaths[importPath] = true
+	}
+	sort.Strings(aliases)
+	for _, alias := range aliases {
+		importDecl.Specs = append(importDecl.Specs, &ast.ImportSpec{
+			Name: ast.NewIdent(alias),
+			Path: &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", usedImports[alias])},
+		})
+	}
+	// A `use go:` package stays linked when nothing from it is referenced, so
+	// its init side effects, such as registering a database/sql driver, run.
+	for _, importPath := range module.GoImports {
+		if usedPaths[importPath] {
+			continue
 		}
+		usedPaths[importPath] = true
+		importDecl.Specs = append(importDecl.Specs, &ast.ImportSpec{
+			Name: ast.NewIdent("_"),
+			Path: &ast.BasicLit{Kind: token.STRING, Value: fmt.Sprintf("%q", importPath)},
+		})
+	}
+	if len(importDecl.Specs) > 0 {
+		decls = append([]ast.Decl{importDecl}, decls...)
 	}
 	return &ast.File{Name: ast.NewIdent(l.modulePackageName(module.ID, mainModuleID)), Decls: decls}, nil
 }
@@ -610,6 +647,9 @@ func (l *lowerer) lowerTypeDecls(typ air.TypeInfo) ([]ast.Decl, error) {
 		}
 		return l.lowerTraitObjectDecls(typ)
 	case air.TypeEnum:
+		if typ.HasPayload() {
+			return l.lowerPayloadEnumDecls(typ)
+		}
 		typeSpec := &ast.TypeSpec{Name: ast.NewIdent(l.typeName(typ)), Type: ast.NewIdent("int")}
 		specs := []ast.Spec{typeSpec}
 		for _, variant := range typ.Variants {
@@ -626,6 +666,69 @@ func (l *lowerer) lowerTypeDecls(typ air.TypeInfo) ([]ast.Decl, error) {
 	}
 }
 
+// lowerPayloadEnumDecls declares an enum whose variants carry data as a
+// struct of the variant's tag and, per payload variant, a pointer to its
+// values. The variant constants name the tags.
+func (l *lowerer) lowerPayloadEnumDecls(typ air.TypeInfo) ([]ast.Decl, error) {
+	fields := []*ast.Field{{Names: []*ast.Ident{ast.NewIdent(enumTagFieldName(typ))}, Type: ast.NewIdent("int")}}
+	specs := []ast.Spec{}
+	for i, variant := range typ.Variants {
+		value := ast.Expr(&ast.BasicLit{Kind: token.INT, Value: fmt.Sprintf("%d", variant.Discriminant)})
+		specs = append(specs, &ast.ValueSpec{Names: []*ast.Ident{ast.NewIdent(l.enumVariantName(typ, variant))}, Values: []ast.Expr{value}})
+		if len(variant.Payload) == 0 {
+			continue
+		}
+		payloadType, err := l.enumPayloadType(variant)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, &ast.Field{Names: []*ast.Ident{ast.NewIdent(enumPayloadFieldName(typ, i))}, Type: &ast.StarExpr{X: payloadType}})
+	}
+	return []ast.Decl{
+		&ast.GenDecl{Tok: token.TYPE, Specs: []ast.Spec{&ast.TypeSpec{Name: ast.NewIdent(l.typeName(typ)), Type: &ast.StructType{Fields: &ast.FieldList{List: fields}}}}},
+		&ast.GenDecl{Tok: token.CONST, Specs: specs},
+	}, nil
+}
+
+func (l *lowerer) enumPayloadType(variant air.VariantInfo) (*ast.StructType, error) {
+	fields := make([]*ast.Field, len(variant.Payload))
+	for i, payload := range variant.Payload {
+		valueType, err := l.goType(payload)
+		if err != nil {
+			return nil, err
+		}
+		fields[i] = &ast.Field{Names: []*ast.Ident{ast.NewIdent(enumPayloadValueFieldName(i))}, Type: valueType}
+	}
+	return &ast.StructType{Fields: &ast.FieldList{List: fields}}, nil
+}
+
+// payloadEnumValue builds a value of an enum with associated data.
+func (l *lowerer) payloadEnumValue(fn air.Function, typ air.TypeInfo, variantIndex int, args []air.Expr) (loweredExpr, error) {
+	variant := typ.Variants[variantIndex]
+	elts := []ast.Expr{&ast.KeyValueExpr{Key: ast.NewIdent(enumTagFieldName(typ)), Value: l.enumVariantExpr(typ, variant)}}
+	stmts := []ast.Stmt{}
+	if len(variant.Payload) > 0 {
+		payloadType, err := l.enumPayloadType(variant)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		values := make([]ast.Expr, len(args))
+		for i, arg := range args {
+			lowered, err := l.lowerExpr(fn, arg)
+			if err != nil {
+				return loweredExpr{}, err
+			}
+			stmts = append(stmts, lowered.stmts...)
+			values[i] = &ast.KeyValueExpr{Key: ast.NewIdent(enumPayloadValueFieldName(i)), Value: lowered.expr}
+		}
+		elts = append(elts, &ast.KeyValueExpr{
+			Key:   ast.NewIdent(enumPayloadFieldName(typ, variantIndex)),
+			Value: &ast.UnaryExpr{Op: token.AND, X: &ast.CompositeLit{Type: payloadType, Elts: values}},
+		})
+	}
+	return loweredExpr{stmts: stmts, expr: &ast.CompositeLit{Type: l.namedTypeExpr(typ), Elts: elts}}, nil
+}
+
 func (l *lowerer) markedMutableTraitRefDecls() ([]ast.Decl, error) {
 	traitIDs := make([]int, 0, len(l.mutableTraitRefs))
 	for traitID := range l.mutableTraitRefs {
@@ -1069,6 +1172,9 @@ func (l *lowerer) lowerFunction(fn air.Function) (ast.Decl, error) {
 		if err != nil {
 			return nil, err
 		}
+		if int(capture.Local) >= 0 && int(capture.Local) < len(fn.Locals) && l.captureUsesDescriptorPointer(fn.Locals[capture.Local]) {
+			captureType = &ast.StarExpr{X: captureType}
+		}
 		params = append(params, &ast.Field{
 			Names: []*ast.Ident{ast.NewIdent(l.localName(fn, capture.Local))},
 			Type:  captureType,
@@ -1663,6 +1769,76 @@ func concreteCallParams(expr air.Expr, target air.Function) []air.Param {
 	return params
 }
 
+// adaptGenericFunctionArgs wraps function arguments whose Result or Maybe
+// return uses the (T, error)/(T, bool) ABI when the generic parameter they are
+// passed to returns a type parameter. Go instantiates such a parameter as a
+// function returning the Result or Maybe value, so the tuple must be packed.
+func (l *lowerer) adaptGenericFunctionArgs(expr air.Expr, target air.Function, args []ast.Expr) ([]ast.Expr, error) {
+	if len(expr.TypeArgs) == 0 {
+		return args, nil
+	}
+	for i, param := range target.Signature.Params {
+		if i >= len(args) || i >= len(expr.Args) || !validTypeID(l.program, param.Type) || !validTypeID(l.program, expr.Args[i].Type) {
+			continue
+		}
+		declared := l.program.Types[param.Type-1]
+		actual := l.program.Types[expr.Args[i].Type-1]
+		if declared.Kind != air.TypeFunction || actual.Kind != air.TypeFunction || !validTypeID(l.program, declared.Return) {
+			continue
+		}
+		if l.program.Types[declared.Return-1].Kind != air.TypeParam || !l.usesABIResultReturn(actual.Return) {
+			continue
+		}
+		adapted, err := l.packedReturnFunction(expr.Args[i].Type, args[i])
+		if err != nil {
+			return nil, err
+		}
+		args[i] = adapted
+	}
+	return args, nil
+}
+
+// packedReturnFunction converts a Go function value using the tuple return ABI
+// into one returning the Result or Maybe value.
+func (l *lowerer) packedReturnFunction(typeID air.TypeID, original ast.Expr) (ast.Expr, error) {
+	info := l.program.Types[typeID-1]
+	actualTypeExpr, err := l.goType(typeID)
+	if err != nil {
+		return nil, err
+	}
+	actualType, ok := actualTypeExpr.(*ast.FuncType)
+	if !ok {
+		return nil, fmt.Errorf("packed return function type %d is not a function", typeID)
+	}
+	returnType, err := l.goType(info.Return)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]ast.Expr, 0, len(actualType.Params.List))
+	params := make([]*ast.Field, 0, len(actualType.Params.List))
+	for i, field := range actualType.Params.List {
+		name := fmt.Sprintf("arg%d", i)
+		args = append(args, ast.NewIdent(name))
+		params = append(params, &ast.Field{Names: []*ast.Ident{ast.NewIdent(name)}, Type: field.Type})
+	}
+	packed, err := l.packABICallResult(info.Return, info.Return, nil, &ast.CallExpr{Fun: ast.NewIdent("original"), Args: args})
+	if err != nil {
+		return nil, err
+	}
+	wrapper := &ast.FuncLit{
+		Type: &ast.FuncType{Params: &ast.FieldList{List: params}, Results: &ast.FieldList{List: []*ast.Field{{Type: returnType}}}},
+		Body: &ast.BlockStmt{List: append(packed.stmts, &ast.ReturnStmt{Results: []ast.Expr{packed.expr}})},
+	}
+	adapter := &ast.FuncLit{
+		Type: &ast.FuncType{
+			Params:  &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{ast.NewIdent("original")}, Type: actualTypeExpr}}},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: wrapper.Type}}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{&ast.ReturnStmt{Results: []ast.Expr{wrapper}}}},
+	}
+	return &ast.CallExpr{Fun: adapter, Args: []ast.Expr{original}}, nil
+}
+
 func (l *lowerer) lowerRawCall(fn air.Function, expr air.Expr) (loweredExpr, error) {
 	if expr.Kind != air.ExprCall || !validFunctionID(l.program, expr.Function) {
 		return loweredExpr{}, fmt.Errorf("not a valid call")
@@ -1672,6 +1848,9 @@ func (l *lowerer) lowerRawCall(fn air.Function, expr air.Expr) (loweredExpr, err
 	if err != nil {
 		return loweredExpr{}, err
 	}
+	if args, err = l.adaptGenericFunctionArgs(expr, target, args); err != nil {
+		return loweredExpr{}, err
+	}
 	fun := l.functionExpr(target)
 	if len(expr.TypeArgs) > 0 {
 		fun = l.indexWithTypeArgs(fun, expr.TypeArgs)
@@ -1727,6 +1906,11 @@ func (l *lowerer) lowerStmt(fn air.Function, stmt air.Stmt) ([]ast.Stmt, error)
 			// referenced storage instead of aliasing it.
 			value.expr = &ast.StarExpr{X: value.expr}
 		}
+		if typed, err := l.typedNumericLiteral(value.expr, localType); err != nil {
+			return nil, err
+		} else {
+			value.expr = typed
+		}
 		out := append([]ast.Stmt{}, value.stmts...)
 		name := l.localName(fn, stmt.Local)
 		tok := token.DEFINE
@@ -1972,12 +2156,32 @@ func (l *lowerer) lowerStmt(fn air.Function, stmt air.Stmt) ([]ast.Stmt, error)
 		if err != nil {
 			return nil, err
 		}
+		// Go randomizes map iteration, so walk the keys in sorted order and
+		// skip entries the body has deleted, as a native range would.
+		var stmts []ast.Stmt
+		mapExpr := target.expr
+		if _, ok := mapExpr.(*ast.Ident); !ok {
+			name := l.nextTemp()
+			stmts = append(stmts, &ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent(name)}, Tok: token.DEFINE, Rhs: []ast.Expr{mapExpr}})
+			mapExpr = ast.NewIdent(name)
+		}
+		keys, err := l.mapKeysExpr(stmt.Target.Type, mapExpr)
+		if err != nil {
+			return nil, err
+		}
+		present := l.nextTemp()
 		body.List = append([]ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{ast.NewIdent(valueName), ast.NewIdent(present)},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{&ast.IndexExpr{X: mapExpr, Index: ast.NewIdent(keyName)}},
+			},
+			&ast.IfStmt{Cond: &ast.UnaryExpr{Op: token.NOT, X: ast.NewIdent(present)}, Body: &ast.BlockStmt{List: []ast.Stmt{&ast.BranchStmt{Tok: token.CONTINUE}}}},
 			&ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent("_")}, Tok: token.ASSIGN, Rhs: []ast.Expr{ast.NewIdent(keyName)}},
 			&ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent("_")}, Tok: token.ASSIGN, Rhs: []ast.Expr{ast.NewIdent(valueName)}},
 		}, body.List...)
-		rangeStmt := &ast.RangeStmt{Key: ast.NewIdent(keyName), Value: ast.NewIdent(valueName), Tok: token.DEFINE, X: target.expr, Body: body}
-		return []ast.Stmt{l.labelLoopBreaks(rangeStmt, body)}, nil
+		rangeStmt := &ast.RangeStmt{Key: ast.NewIdent("_"), Value: ast.NewIdent(keyName), Tok: token.DEFINE, X: keys, Body: body}
+		return append(stmts, l.labelLoopBreaks(rangeStmt, body)), nil
 	case air.StmtBreak:
 		return []ast.Stmt{&ast.BranchStmt{Tok: token.BREAK}}, nil
 	case air.StmtDefer:
@@ -2052,6 +2256,38 @@ func (l *lowerer) labelLoopBreaks(loop ast.Stmt, body *ast.BlockStmt) ast.Stmt {
 	return &ast.LabeledStmt{Label: ast.NewIdent(label), Stmt: loop}
 }
 
+// goFloatLiteral keeps a float constant a float in Go. AIR spells whole
+// floats such as 10.0 as "10", which Go reads as an untyped int, so 10.0 / 4.0
+// would divide as integers.
+func goFloatLiteral(value string) string {
+	if strings.ContainsAny(value, ".eE") {
+		return value
+	}
+	return value + ".0"
+}
+
+// typedNumericLiteral converts a numeric literal bound with `:=` to the Go
+// type of its local. Go gives an untyped constant its default type there, so
+// `let zero: Byte = 0` would otherwise declare an int.
+func (l *lowerer) typedNumericLiteral(value ast.Expr, typeID air.TypeID) (ast.Expr, error) {
+	lit := value
+	if unary, ok := value.(*ast.UnaryExpr); ok && unary.Op == token.SUB {
+		lit = unary.X
+	}
+	basic, ok := lit.(*ast.BasicLit)
+	if !ok || (basic.Kind != token.INT && basic.Kind != token.FLOAT) {
+		return value, nil
+	}
+	goType, err := l.goType(typeID)
+	if err != nil {
+		return nil, err
+	}
+	if ident, ok := goType.(*ast.Ident); ok && (ident.Name == "int" && basic.Kind == token.INT || ident.Name == "float64" && basic.Kind == token.FLOAT) {
+		return value, nil
+	}
+	return &ast.CallExpr{Fun: goType, Args: []ast.Expr{value}}, nil
+}
+
 func (l *lowerer) lowerExpr(fn air.Function, expr air.Expr) (loweredExpr, error) {
 	switch expr.Kind {
 	case air.ExprConstVoid:
@@ -2059,7 +2295,7 @@ func (l *lowerer) lowerExpr(fn air.Function, expr air.Expr) (loweredExpr, error)
 	case air.ExprConstInt:
 		return loweredExpr{expr: &ast.BasicLit{Kind: token.INT, Value: expr.Int}}, nil
 	case air.ExprConstFloat:
-		return loweredExpr{expr: &ast.BasicLit{Kind: token.FLOAT, Value: expr.Float}}, nil
+		return loweredExpr{expr: &ast.BasicLit{Kind: token.FLOAT, Value: goFloatLiteral(expr.Float)}}, nil
 	case air.ExprConstBool:
 		if expr.Bool {
 			return loweredExpr{expr: ast.NewIdent("true")}, nil
@@ -2201,6 +2437,10 @@ func (l *lowerer) lowerExpr(fn air.Function, expr air.Expr) (loweredExpr, error)
 			return loweredExpr{}, err
 		}
 		return loweredExpr{stmts: message.stmts, expr: &ast.CallExpr{Fun: l.qualified("errors", "errors", "New"), Args: []ast.Expr{message.expr}}}, nil
+	case air.ExprWrapError:
+		return l.lowerRuntimeMethod(fn, expr, "WrapError", 1)
+	case air.ExprErrorCause:
+		return l.lowerRuntimeMethod(fn, expr, "ErrorCause", 0)
 	case air.ExprMakeMaybeSome:
 		if expr.Target == nil {
 			return loweredExpr{}, fmt.Errorf("maybe some missing target")
@@ -2348,6 +2588,46 @@ func (l *lowerer) lowerExpr(fn air.Function, expr air.Expr) (loweredExpr, error)
 		return l.lowerMatchStr(fn, expr)
 	case air.ExprMakeList:
 		return l.lowerMakeList(fn, expr)
+	case air.ExprStrFormat:
+		return l.lowerStrFormat(fn, expr)
+	case air.ExprInspect:
+		return l.lowerInspect(fn, expr)
+	case air.ExprIntAbs:
+		return l.lowerRuntimeMethod(fn, expr, "IntAbs", 0)
+	case air.ExprIntPow:
+		return l.lowerRuntimeMethod(fn, expr, "IntPow", 1)
+	case air.ExprIntClamp:
+		return l.lowerRuntimeMethod(fn, expr, "IntClamp", 2)
+	case air.ExprFloatRound:
+		return l.lowerRuntimeMethod(fn, expr, "FloatRound", 0)
+	case air.ExprFloatFloor:
+		return l.lowerRuntimeMethod(fn, expr, "FloatFloor", 0)
+	case air.ExprFloatCeil:
+		return l.lowerRuntimeMethod(fn, expr, "FloatCeil", 0)
+	case air.ExprIntParse:
+		return l.lowerRuntimeMethod(fn, expr, "IntParse", 0)
+	case air.ExprFloatParse:
+		return l.lowerRuntimeMethod(fn, expr, "FloatParse", 0)
+	case air.ExprStrTrimStart:
+		return l.lowerRuntimeMethod(fn, expr, "StrTrimStart", 0)
+	case air.ExprStrTrimEnd:
+		return l.lowerRuntimeMethod(fn, expr, "StrTrimEnd", 0)
+	case air.ExprStrSlice:
+		return l.lowerRuntimeMethod(fn, expr, "StrSlice", 2)
+	case air.ExprStrIndexOf:
+		return l.lowerRuntimeMethod(fn, expr, "StrIndexOf", 1)
+	case air.ExprStrPadLeft:
+		return l.lowerRuntimeMethod(fn, expr, "StrPadLeft", 2)
+	case air.ExprStrPadRight:
+		return l.lowerRuntimeMethod(fn, expr, "StrPadRight", 2)
+	case air.ExprStrRepeat:
+		return l.lowerRuntimeMethod(fn, expr, "StrRepeat", 1)
+	case air.ExprStrReverse:
+		return l.lowerRuntimeMethod(fn, expr, "StrReverse", 0)
+	case air.ExprStrChars:
+		return l.lowerRuntimeMethod(fn, expr, "StrChars", 0)
+	case air.ExprStrGraphemes:
+		return l.lowerRuntimeMethod(fn, expr, "StrGraphemes", 0)
 	case air.ExprMakeFixedArray:
 		return l.lowerMakeList(fn, expr)
 	case air.ExprAsyncStart:
@@ -2462,6 +2742,19 @@ func (l *lowerer) lowerExpr(fn air.Function, expr air.Expr) (loweredExpr, error)
 			return loweredExpr{}, err
 		}
 		return loweredExpr{stmts: target.stmts, expr: &ast.CallExpr{Fun: l.qualified("strings", "strings", "Trim"), Args: []ast.Expr{target.expr, &ast.BasicLit{Kind: token.STRING, Value: `" "`}}}}, nil
+	case air.ExprStrToUpper, air.ExprStrToLower:
+		if expr.Target == nil {
+			return loweredExpr{}, fmt.Errorf("str case conversion missing target")
+		}
+		target, err := l.lowerExpr(fn, *expr.Target)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		name := "ToUpper"
+		if expr.Kind == air.ExprStrToLower {
+			name = "ToLower"
+		}
+		return loweredExpr{stmts: target.stmts, expr: &ast.CallExpr{Fun: l.qualified("strings", "strings", name), Args: []ast.Expr{target.expr}}}, nil
 	case air.ExprStrIsEmpty:
 		if expr.Target == nil {
 			return loweredExpr{}, fmt.Errorf("str is_empty missing target")
@@ -2668,6 +2961,9 @@ func (l *lowerer) lowerExpr(fn air.Function, expr air.Expr) (loweredExpr, error)
 		if typ.Kind != air.TypeEnum || expr.Variant < 0 || expr.Variant >= len(typ.Variants) {
 			return loweredExpr{}, fmt.Errorf("invalid enum variant %d for type %s", expr.Variant, typ.Name)
 		}
+		if typ.HasPayload() {
+			return l.payloadEnumValue(fn, typ, expr.Variant, expr.Args)
+		}
 		return loweredExpr{expr: l.enumVariantExpr(typ, typ.Variants[expr.Variant])}, nil
 	case air.ExprMakeStruct:
 		if !validTypeID(l.program, expr.Type) {
@@ -2818,6 +3114,9 @@ func (l *lowerer) lowerExpr(fn air.Function, expr air.Expr) (loweredExpr, error)
 		if err != nil {
 			return loweredExpr{}, err
 		}
+		if args, err = l.adaptGenericFunctionArgs(expr, target, args); err != nil {
+			return loweredExpr{}, err
+		}
 		fun := l.functionExpr(target)
 		if len(expr.TypeArgs) > 0 {
 			fun = l.indexWithTypeArgs(fun, expr.TypeArgs)
@@ -2896,13 +3195,15 @@ func (l *lowerer) lowerExpr(fn air.Function, expr air.Expr) (loweredExpr, error)
 	}
 }
 
+// lowerBlockExpr lowers a block used as a value. The body is emitted as a Go
+// block so its locals stay scoped to it, as the local namer assumes.
 func (l *lowerer) lowerBlockExpr(fn air.Function, expr air.Expr) (loweredExpr, error) {
 	if l.isVoidType(expr.Type) {
 		body, err := l.lowerValueBlock(fn, expr.Body, expr.Type, nil)
 		if err != nil {
 			return loweredExpr{}, err
 		}
-		return loweredExpr{stmts: body, expr: ast.NewIdent("nil")}, nil
+		return loweredExpr{stmts: scopedStmts(body), expr: ast.NewIdent("nil")}, nil
 	}
 	temp := l.nextTemp()
 	decls, err := l.declareTemp(expr.Type, temp)
@@ -2913,7 +3214,22 @@ func (l *lowerer) lowerBlockExpr(fn air.Function, expr air.Expr) (loweredExpr, e
 	if err != nil {
 		return loweredExpr{}, err
 	}
-	return loweredExpr{stmts: append(decls, body...), expr: ast.NewIdent(temp)}, nil
+	return loweredExpr{stmts: append(decls, scopedStmts(body)...), expr: ast.NewIdent(temp)}, nil
+}
+
+// scopedStmts wraps stmts in a Go block when any of them declares a name.
+func scopedStmts(stmts []ast.Stmt) []ast.Stmt {
+	for _, stmt := range stmts {
+		switch s := stmt.(type) {
+		case *ast.DeclStmt:
+			return []ast.Stmt{&ast.BlockStmt{List: stmts}}
+		case *ast.AssignStmt:
+			if s.Tok == token.DEFINE {
+				return []ast.Stmt{&ast.BlockStmt{List: stmts}}
+			}
+		}
+	}
+	return stmts
 }
 
 func (l *lowerer) lowerUnsafeBlockExpr(fn air.Function, expr air.Expr) (loweredExpr, error) {
@@ -3293,7 +3609,10 @@ func (l *lowerer) lowerForeignValue(expr air.Expr) (loweredExpr, error) {
 			qualifier = qualifier[slash+1:]
 		}
 	}
-	return loweredExpr{expr: l.qualified(qualifier, expr.ForeignNamespace, expr.ForeignSymbol)}, nil
+	return loweredExpr{
+		stmts: l.sandboxChecks(expr.ForeignNamespace, expr.ForeignSymbol, nil),
+		expr:  l.qualified(qualifier, expr.ForeignNamespace, expr.ForeignSymbol),
+	}, nil
 }
 
 func (l *lowerer) lowerForeignInterfaceUpcast(fn air.Function, expr air.Expr) (loweredExpr, error) {
@@ -3337,6 +3656,7 @@ func (l *lowerer) lowerForeignCall(fn air.Function, expr air.Expr) (loweredExpr,
 			pkgName = pkgName[slash+1:]
 		}
 	}
+	stmts = append(stmts, l.sandboxChecks(importPath, functionName, args)...)
 	fun := l.qualified(pkgName, importPath, functionName)
 	if len(expr.TypeArgs) > 0 {
 		fun = l.indexWithTypeArgs(fun, expr.TypeArgs)
@@ -3446,6 +3766,7 @@ func (l *lowerer) lowerForeignMethodValue(fn air.Function, expr air.Expr) (lower
 	if err != nil {
 		return loweredExpr{}, err
 	}
+	target.stmts = append(target.stmts, l.sandboxChecks(expr.ForeignNamespace, expr.ForeignReceiver+"."+expr.ForeignSymbol, nil)...)
 	selector := &ast.SelectorExpr{X: target.expr, Sel: ast.NewIdent(expr.ForeignSymbol)}
 	if !validTypeID(l.program, expr.Type) {
 		return loweredExpr{stmts: target.stmts, expr: selector}, nil
@@ -3556,6 +3877,7 @@ func (l *lowerer) lowerForeignMethodCall(fn air.Function, expr air.Expr) (lowere
 		stmts = append(stmts, arg.stmts...)
 		args = append(args, arg.expr)
 	}
+	stmts = append(stmts, l.sandboxChecks(expr.ForeignNamespace, expr.ForeignReceiver+"."+expr.ForeignSymbol, args)...)
 	call := &ast.CallExpr{Fun: &ast.SelectorExpr{X: target.expr, Sel: ast.NewIdent(expr.ForeignSymbol)}, Args: args}
 	if validTypeID(l.program, expr.Type) {
 		if info := l.program.Types[expr.Type-1]; info.Kind == air.TypeResult {
@@ -3726,11 +4048,14 @@ func (l *lowerer) zeroValueExpr(typeID air.TypeID) (ast.Expr, error) {
 		return ast.NewIdent("nil"), nil
 	}
 	info := l.program.Types[typeID-1]
+	if info.Kind == air.TypeEnum && info.HasPayload() {
+		return &ast.CompositeLit{Type: l.namedTypeExpr(info)}, nil
+	}
 	switch info.Kind {
 	case air.TypeInt, air.TypeScalar, air.TypeByte, air.TypeRune, air.TypeEnum:
 		return &ast.BasicLit{Kind: token.INT, Value: "0"}, nil
 	case air.TypeForeignType:
-		if info.ForeignPointer {
+		if info.ForeignPointer || info.ForeignInterface {
 			return ast.NewIdent("nil"), nil
 		}
 		if validTypeID(l.program, info.Value) && !validTypeID(l.program, info.Key) {
@@ -5119,6 +5444,14 @@ func (l *lowerer) localIsReference(fn air.Function, local air.LocalID) bool {
 }
 
 func (l *lowerer) localIsPointerParam(fn air.Function, local air.LocalID) bool {
+	idx := int(local)
+	for _, capture := range fn.Captures {
+		if capture.Local != local || idx < 0 || idx >= len(fn.Locals) {
+			continue
+		}
+		captured := fn.Locals[idx]
+		return l.captureUsesDescriptorPointer(captured) || (captured.Mutable || captured.Reference) && l.mutableParamUsesPointer(captured.Type)
+	}
 	if l.localIsReference(fn, local) {
 		// Reference locals hold a Go pointer only when the referent's
 		// representation requires one; descriptor-backed referents are
@@ -5129,21 +5462,21 @@ func (l *lowerer) localIsPointerParam(fn air.Function, local air.LocalID) bool {
 		}
 		return true
 	}
-	idx := int(local)
 	if idx >= 0 && idx < len(fn.Signature.Params) {
 		param := fn.Signature.Params[idx]
 		return param.Mutable && l.mutableParamUsesPointer(param.Type)
 	}
-	for _, capture := range fn.Captures {
-		if capture.Local != local || idx < 0 || idx >= len(fn.Locals) {
-			continue
-		}
-		captured := fn.Locals[idx]
-		return captured.Mutable && l.mutableParamUsesPointer(captured.Type)
-	}
 	return false
 }
 
+// captureUsesDescriptorPointer reports whether a closure shares the captured
+// local through a pointer to its descriptor. Closures capture mutable
+// variables by reference, so unlike a mutable parameter (ADR 0040) a captured
+// list or map must also see the variable rebound, e.g. by `push` or assignment.
+func (l *lowerer) captureUsesDescriptorPointer(local air.Local) bool {
+	return (local.Mutable || local.Reference) && !l.isVoidType(local.Type) && !l.mutableParamUsesPointer(local.Type)
+}
+
 func (l *lowerer) runtimeQualified(name string) ast.Expr {
 	return l.qualified("ard", path.Join(generatedModulePath(l.projectInfo), "internal", "ard"), name)
 }
@@ -5505,7 +5838,11 @@ func (l *lowerer) lowerMatchInt(fn air.Function, expr air.Expr) (loweredExpr, er
 		if err != nil {
 			return loweredExpr{}, err
 		}
-		cases = append(cases, &ast.CaseClause{List: []ast.Expr{&ast.BinaryExpr{X: target.expr, Op: token.EQL, Y: &ast.BasicLit{Kind: token.INT, Value: fmt.Sprintf("%d", intCase.Value)}}}, Body: body})
+		list := make([]ast.Expr, 0, 1+len(intCase.Also))
+		for _, value := range append([]int{intCase.Value}, intCase.Also...) {
+			list = append(list, &ast.BinaryExpr{X: target.expr, Op: token.EQL, Y: &ast.BasicLit{Kind: token.INT, Value: fmt.Sprintf("%d", value)}})
+		}
+		cases = append(cases, &ast.CaseClause{List: list, Body: body})
 	}
 	for _, rangeCase := range expr.RangeCases {
 		body, err := l.lowerValueBlock(fn, rangeCase.Body, resultTypeID, assignTarget)
@@ -5554,7 +5891,11 @@ func (l *lowerer) lowerMatchStr(fn air.Function, expr air.Expr) (loweredExpr, er
 		if err != nil {
 			return loweredExpr{}, err
 		}
-		cases = append(cases, &ast.CaseClause{List: []ast.Expr{&ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(strCase.Value)}}, Body: body})
+		list := make([]ast.Expr, 0, 1+len(strCase.Also))
+		for _, value := range append([]string{strCase.Value}, strCase.Also...) {
+			list = append(list, &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(value)})
+		}
+		cases = append(cases, &ast.CaseClause{List: list, Body: body})
 	}
 	body, err := l.lowerValueBlock(fn, expr.CatchAll, resultTypeID, assignTarget)
 	if err != nil {
@@ -5586,16 +5927,39 @@ func (l *lowerer) lowerMatchEnum(fn air.Function, expr air.Expr) (loweredExpr, e
 		assignTarget = ast.NewIdent(temp)
 		resultExpr = ast.NewIdent(temp)
 	}
+	tag := target.expr
+	var enumType air.TypeInfo
+	if validTypeID(l.program, expr.Target.Type) {
+		enumType = l.program.Types[expr.Target.Type-1]
+	}
+	if enumType.HasPayload() {
+		// Payload bindings read the subject again, so evaluate it once.
+		subject := l.nextTemp()
+		stmts = append(stmts, &ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent(subject)}, Tok: token.DEFINE, Rhs: []ast.Expr{target.expr}})
+		target.expr = ast.NewIdent(subject)
+		tag = &ast.SelectorExpr{X: target.expr, Sel: ast.NewIdent(enumTagFieldName(enumType))}
+	}
 	cases := make([]ast.Stmt, 0, len(expr.EnumCases)+1)
 	for _, enumCase := range expr.EnumCases {
+		binds := []ast.Stmt{}
+		for _, binding := range enumCase.Bindings {
+			localName := l.localName(fn, binding.Local)
+			l.declaredLocals[binding.Local] = true
+			payload := &ast.SelectorExpr{X: target.expr, Sel: ast.NewIdent(enumPayloadFieldName(enumType, enumCase.Variant))}
+			binds = append(binds,
+				&ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent(localName)}, Tok: token.DEFINE, Rhs: []ast.Expr{&ast.SelectorExpr{X: payload, Sel: ast.NewIdent(enumPayloadValueFieldName(binding.Index))}}},
+				&ast.AssignStmt{Lhs: []ast.Expr{ast.NewIdent("_")}, Tok: token.ASSIGN, Rhs: []ast.Expr{ast.NewIdent(localName)}},
+			)
+		}
 		body, err := l.lowerValueBlock(fn, enumCase.Body, expr.Type, assignTarget)
 		if err != nil {
 			return loweredExpr{}, err
 		}
-		cases = append(cases, &ast.CaseClause{
-			List: []ast.Expr{&ast.BasicLit{Kind: token.INT, Value: fmt.Sprintf("%d", enumCase.Discriminant)}},
-			Body: body,
-		})
+		list := make([]ast.Expr, 0, 1+len(enumCase.Also))
+		for _, discriminant := range append([]int{enumCase.Discriminant}, enumCase.Also...) {
+			list = append(list, &ast.BasicLit{Kind: token.INT, Value: fmt.Sprintf("%d", discriminant)})
+		}
+		cases = append(cases, &ast.CaseClause{List: list, Body: append(binds, body...)})
 	}
 	if len(expr.CatchAll.Stmts) > 0 || expr.CatchAll.Result != nil {
 		body, err := l.lowerValueBlock(fn, expr.CatchAll, expr.Type, assignTarget)
@@ -5604,7 +5968,7 @@ func (l *lowerer) lowerMatchEnum(fn air.Function, expr air.Expr) (loweredExpr, e
 		}
 		cases = append(cases, &ast.CaseClause{Body: body})
 	}
-	stmts = append(stmts, &ast.SwitchStmt{Tag: target.expr, Body: &ast.BlockStmt{List: cases}})
+	stmts = append(stmts, &ast.SwitchStmt{Tag: tag, Body: &ast.BlockStmt{List: cases}})
 	return loweredExpr{stmts: stmts, expr: resultExpr}, nil
 }
 
@@ -6555,6 +6919,69 @@ func (l *lowerer) lowerMakeList(fn air.Function, expr air.Expr) (loweredExpr, er
 	return loweredExpr{stmts: stmts, expr: &ast.CompositeLit{Type: typ, Elts: elts}}, nil
 }
 
+// lowerStrFormat lowers Str::format to the runtime formatter, passing the
+// positional arguments as a []any and the named ones as a map[string]any.
+func (l *lowerer) lowerStrFormat(fn air.Function, expr air.Expr) (loweredExpr, error) {
+	if expr.Target == nil {
+		return loweredExpr{}, fmt.Errorf("str format missing template")
+	}
+	template, err := l.lowerExpr(fn, *expr.Target)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	stmts := template.stmts
+	anyType := ast.NewIdent("any")
+	args := &ast.CompositeLit{Type: &ast.ArrayType{Elt: anyType}}
+	for _, arg := range expr.Args {
+		value, err := l.lowerExpr(fn, arg)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		stmts = append(stmts, value.stmts...)
+		args.Elts = append(args.Elts, value.expr)
+	}
+	var named ast.Expr = ast.NewIdent("nil")
+	if len(expr.Entries) > 0 {
+		entries := &ast.CompositeLit{Type: &ast.MapType{Key: ast.NewIdent("string"), Value: anyType}}
+		for _, entry := range expr.Entries {
+			value, err := l.lowerExpr(fn, entry.Value)
+			if err != nil {
+				return loweredExpr{}, err
+			}
+			stmts = append(stmts, value.stmts...)
+			entries.Elts = append(entries.Elts, &ast.KeyValueExpr{
+				Key:   &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(entry.Key.Str)},
+				Value: value.expr,
+			})
+		}
+		named = entries
+	}
+	return loweredExpr{stmts: stmts, expr: &ast.CallExpr{Fun: l.runtimeQualified("Format"), Args: []ast.Expr{template.expr, args, named}}}, nil
+}
+
+// lowerRuntimeMethod calls the runtime helper that implements a builtin method,
+// passing the receiver before the method arguments.
+func (l *lowerer) lowerRuntimeMethod(fn air.Function, expr air.Expr, helper string, arity int) (loweredExpr, error) {
+	if expr.Target == nil || len(expr.Args) != arity {
+		return loweredExpr{}, fmt.Errorf("%s expects a target and %d args", helper, arity)
+	}
+	target, err := l.lowerExpr(fn, *expr.Target)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	stmts := target.stmts
+	args := []ast.Expr{target.expr}
+	for _, arg := range expr.Args {
+		value, err := l.lowerExpr(fn, arg)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		stmts = append(stmts, value.stmts...)
+		args = append(args, value.expr)
+	}
+	return loweredExpr{stmts: stmts, expr: &ast.CallExpr{Fun: l.runtimeQualified(helper), Args: args}}, nil
+}
+
 func (l *lowerer) lowerMakeClosure(fn air.Function, expr air.Expr) (loweredExpr, error) {
 	if !validFunctionID(l.program, expr.Function) {
 		return loweredExpr{}, fmt.Errorf("invalid closure function %d", expr.Function)
@@ -6579,9 +7006,14 @@ func (l *lowerer) lowerMakeClosure(fn air.Function, expr air.Expr) (loweredExpr,
 				local := closureFn.Locals[capture.Local]
 				captureParam.Mutable = local.Mutable || local.Reference
 			}
+			load := air.Expr{Kind: air.ExprLoadLocal, Type: capture.Type, Local: local}
+			if int(capture.Local) >= 0 && int(capture.Local) < len(closureFn.Locals) && l.captureUsesDescriptorPointer(closureFn.Locals[capture.Local]) {
+				callArgs = append(callArgs, l.mutableReferenceArg(fn, load, argExpr))
+				continue
+			}
 			var setup []ast.Stmt
 			var post []ast.Stmt
-			argExpr, setup, post, err = l.adaptCallArgWithStmts(fn, air.Expr{Kind: air.ExprLoadLocal, Type: capture.Type, Local: local}, argExpr, captureParam)
+			argExpr, setup, post, err = l.adaptCallArgWithStmts(fn, load, argExpr, captureParam)
 			if err != nil {
 				return loweredExpr{}, err
 			}
@@ -7407,42 +7839,7 @@ func (l *lowerer) mapKeysExpr(typeID air.TypeID, mapExpr ast.Expr) (ast.Expr, er
 	if info.Kind != air.TypeMap && !(info.Kind == air.TypeForeignType && validTypeID(l.program, info.Key) && validTypeID(l.program, info.Value)) {
 		return nil, fmt.Errorf("type %s is not a map", info.Name)
 	}
-	mapType, err := l.goType(typeID)
-	if err != nil {
-		return nil, err
-	}
-	keyType, err := l.goType(info.Key)
-	if err != nil {
-		return nil, err
-	}
-	keysType := &ast.ArrayType{Elt: keyType}
-	return &ast.CallExpr{
-		Fun: &ast.FuncLit{
-			Type: &ast.FuncType{
-				Params:  &ast.FieldList{List: []*ast.Field{{Names: []*ast.Ident{ast.NewIdent("m")}, Type: mapType}}},
-				Results: &ast.FieldList{List: []*ast.Field{{Type: keysType}}},
-			},
-			Body: &ast.BlockStmt{List: []ast.Stmt{
-				&ast.AssignStmt{
-					Lhs: []ast.Expr{ast.NewIdent("keys")},
-					Tok: token.DEFINE,
-					Rhs: []ast.Expr{&ast.CallExpr{Fun: ast.NewIdent("make"), Args: []ast.Expr{keysType, &ast.BasicLit{Kind: token.INT, Value: "0"}, &ast.CallExpr{Fun: ast.NewIdent("len"), Args: []ast.Expr{ast.NewIdent("m")}}}}},
-				},
-				&ast.RangeStmt{
-					Key: ast.NewIdent("k"),
-					Tok: token.DEFINE,
-					X:   ast.NewIdent("m"),
-					Body: &ast.BlockStmt{List: []ast.Stmt{&ast.AssignStmt{
-						Lhs: []ast.Expr{ast.NewIdent("keys")},
-						Tok: token.ASSIGN,
-						Rhs: []ast.Expr{&ast.CallExpr{Fun: ast.NewIdent("append"), Args: []ast.Expr{ast.NewIdent("keys"), ast.NewIdent("k")}}},
-					}}},
-				},
-				&ast.ReturnStmt{Results: []ast.Expr{ast.NewIdent("keys")}},
-			}},
-		},
-		Args: []ast.Expr{mapExpr},
-	}, nil
+	return &ast.CallExpr{Fun: l.runtimeQualified("SortedKeys"), Args: []ast.Expr{mapExpr}}, nil
 }
 
 func mustTypeExpr(l *lowerer, typeID air.TypeID) ast.Expr {
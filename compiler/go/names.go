@@ -1091,7 +1091,9 @@ func sanitizeGoPackageIdentifier(raw string) string {
 	if name == "" || name == "_" {
 		return "module"
 	}
-	if token.Lookup(name) != token.IDENT || name == "main" {
+	// a package named after a predeclared identifier, like ard/int, would
+	// shadow it in every file that imports the package
+	if token.Lookup(name) != token.IDENT || name == "main" || slices.Contains(predeclaredGoIdentifiers(), name) {
 		name += "_"
 	}
 	return name
@@ -1191,3 +1193,51 @@ func lowerFirst(s string) string {
 	runes[0] = unicode.ToLower(runes[0])
 	return string(runes)
 }
+
+// enumTagFieldName names the discriminant field of an enum with associated
+// data, avoiding the variants' payload fields.
+func enumTagFieldName(typ air.TypeInfo) string {
+	base := "ArdTag"
+	candidate := base
+	for i := 1; enumTagFieldNameCollides(typ, candidate); i++ {
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+	return candidate
+}
+
+func enumTagFieldNameCollides(typ air.TypeInfo, candidate string) bool {
+	for i := range typ.Variants {
+		if len(typ.Variants[i].Payload) > 0 && enumPayloadFieldName(typ, i) == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// enumPayloadFieldName names the field holding the payload of the variant at
+// index. It is a pointer to a struct with one field per payload value, so
+// enums may refer to themselves.
+func enumPayloadFieldName(typ air.TypeInfo, index int) string {
+	base := fmt.Sprintf("Variant%d", index)
+	if len(goIdentifierParts(typ.Variants[index].Name)) > 0 {
+		base = naturalGoIdentifier(typ.Variants[index].Name, true)
+	}
+	candidate := base
+	for i := 1; enumPayloadFieldNameCollidesEarlier(typ, index, candidate); i++ {
+		candidate = fmt.Sprintf("%s%d", base, i)
+	}
+	return candidate
+}
+
+func enumPayloadFieldNameCollidesEarlier(typ air.TypeInfo, index int, candidate string) bool {
+	for i := 0; i < index; i++ {
+		if len(typ.Variants[i].Payload) > 0 && enumPayloadFieldName(typ, i) == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+func enumPayloadValueFieldName(index int) string {
+	return fmt.Sprintf("V%d", index)
+}
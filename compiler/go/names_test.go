@@ -11,6 +11,7 @@ func TestModulePackageHelpersSanitizePaths(t *testing.T) {
 		{ID: 0, Path: "accounts/foo_bar.ard"},
 		{ID: 1, Path: "123-api/type.ard"},
 		{ID: 2, Path: "v1.0/foo.ard"},
+		{ID: 3, Path: "ard/int.ard"},
 	}}
 	if got := modulePackageName(program, 0); got != "foo_bar" {
 		t.Fatalf("modulePackageName = %q, want foo_bar", got)
@@ -24,6 +25,9 @@ func TestModulePackageHelpersSanitizePaths(t *testing.T) {
 	if got := modulePackageDir(program, 2); got != "v1_0/foo" {
 		t.Fatalf("modulePackageDir dotted directory = %q, want v1_0/foo", got)
 	}
+	if got := modulePackageName(program, 3); got != "int_" {
+		t.Fatalf("modulePackageName predeclared identifier = %q, want int_", got)
+	}
 }
 
 func TestModulePackageHelpersStripProjectName(t *testing.T) {
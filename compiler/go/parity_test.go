@@ -2623,7 +2623,7 @@ func TestGoTargetParityMatching(t *testing.T) {
 				fn main() Str {
 					let value = 80
 					match value {
-						-100..0 => "how?",
+						-100..-1 => "how?",
 						0..60 => "F",
 						_ => "pass"
 					}
@@ -2811,6 +2811,31 @@ fn main() Bool {
     done.send(true)
   })
   done.recv().expect("done")
+}`,
+			want: "true",
+		},
+		{
+			name: "try awaits a spawned fiber's result",
+			input: `use ard/async
+fn half(n: Int) Int!Str {
+  match n % 2 == 0 {
+    true => Result::ok(n / 2),
+    false => Result::err("{n} is odd"),
+  }
+}
+
+fn run(n: Int) Int!Str {
+  let fiber = async::spawn(fn() Int!Str { half(n) })
+  let value = try fiber.await()
+  Result::ok(value + 1)
+}
+
+fn main() Bool {
+  let failed = match run(3) {
+    ok(_) => "",
+    err(e) => e,
+  }
+  run(10).or(0) == 6 and failed == "3 is odd"
 }`,
 			want: "true",
 		},
@@ -3444,3 +3469,66 @@ func normalizeJSONValue(value any) any {
 		return value
 	}
 }
+
+func TestGoTargetParityConstants(t *testing.T) {
+	program := lowerParitySource(t, `const LOW = 10
+const HIGH = LOW * 10
+const LABEL = "grade: "
+
+fn grade(n: Int) Str {
+  match n {
+    0..LOW => LABEL + "low",
+    HIGH => LABEL + "max",
+    _ => LABEL + "mid",
+  }
+}
+
+fn main() Bool {
+  grade(5) == "grade: low" and grade(100) == "grade: max" and grade(50) == "grade: mid"
+}`)
+	if got := strings.TrimSpace(runGoTargetParityJSON(t, program)); got != "true" {
+		t.Fatalf("go output = %s, want true", got)
+	}
+}
+
+func TestGoTargetParityMapIterationOrder(t *testing.T) {
+	program := lowerParitySource(t, `struct Point {
+  x: Int,
+  y: Int,
+}
+
+fn main() Str {
+  mut scores: [Str: Int] = ["zed": 1, "amy": 2, "moe": 3, "bob": 4]
+  mut out = ""
+  for name, score in scores {
+    if name == "amy" {
+      scores.delete("moe")
+    }
+    out = out + "{name}={score} "
+  }
+  let ids: [Int: Str] = [30: "c", 10: "a", 20: "b"]
+  for id in ids.keys() {
+    out = out + "{id} "
+  }
+  mut points: [Point: Str] = [:]
+  points.set(Point{x: 2, y: 1}, "b")
+  points.set(Point{x: 1, y: 5}, "a")
+  for point, label in points {
+    out = out + "{point.x}{label}"
+  }
+  out
+}`)
+	if got := strings.TrimSpace(runGoTargetParityJSON(t, program)); got != `"amy=2 bob=4 zed=1 10 20 30 1a2b"` {
+		t.Fatalf("go output = %s, want keys in sorted order", got)
+	}
+}
+
+func TestGoTargetParityStrFormat(t *testing.T) {
+	program := lowerParitySource(t, `fn main() Str {
+  let template = "\{0:05}|\{1:.1}"
+  Str::format("[\{0:>5}] [\{1:.3}] [\{label:*^7}] ", 42, 3.14159, label: "ard") + Str::format(template, -7, 2.26)
+}`)
+	if got := strings.TrimSpace(runGoTargetParityJSON(t, program)); got != `"[   42] [3.142] [**ard**] -0007|2.3"` {
+		t.Fatalf("go output = %s", got)
+	}
+}
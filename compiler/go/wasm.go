@@ -0,0 +1,85 @@
+package gotarget
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+	"github.com/akonwi/ard/checker"
+)
+
+//go:embed wasm_glue.mjs
+var wasmGlueSource []byte
+
+const (
+	wasmModuleName  = "main.wasm"
+	wasmExecName    = "wasm_exec.js"
+	wasmGlueName    = "index.mjs"
+	wasmBuildTarget = "wasm"
+)
+
+// WasmEnv is the environment the go command needs, on top of the process's
+// own, to resolve and build packages for the browser. Callers pass it
+// explicitly so a wasm build never changes the environment of later builds
+// in the same process.
+var WasmEnv = []string{"GOOS=js", "GOARCH=wasm"}
+
+// BuildWasmProgram compiles the program's Go output with GOOS=js GOARCH=wasm
+// and writes main.wasm, Go's wasm_exec.js support file, and the index.mjs
+// glue module into outputDir. It returns the path of the glue module.
+func BuildWasmProgram(program *air.Program, outputDir string, projectInfo ...*checker.ProjectInfo) (string, error) {
+	info := optionalProjectInfo(projectInfo)
+	workspaceDir, err := artifactWorkspace(filepath.Join(outputDir, wasmModuleName), wasmBuildTarget)
+	if err != nil {
+		return "", err
+	}
+	if err := writeProgram(workspaceDir, program, Options{PackageName: "main", ProjectInfo: info}); err != nil {
+		return "", err
+	}
+	absDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(absDir, 0o755); err != nil {
+		return "", err
+	}
+	if err := buildGeneratedProgramWithEnv(workspaceDir, filepath.Join(absDir, wasmModuleName), WasmEnv, goBuildTags(info)...); err != nil {
+		return "", err
+	}
+	wasmExec, err := readWasmExec()
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(absDir, wasmExecName), wasmExec, 0o644); err != nil {
+		return "", err
+	}
+	gluePath := filepath.Join(absDir, wasmGlueName)
+	if err := os.WriteFile(gluePath, wasmGlueSource, 0o644); err != nil {
+		return "", err
+	}
+	return gluePath, nil
+}
+
+// readWasmExec returns the wasm_exec.js that matches the Go toolchain used
+// to build main.wasm. Go 1.24 moved it from misc/wasm to lib/wasm.
+func readWasmExec() ([]byte, error) {
+	out, err := exec.Command("go", "env", "GOROOT").Output()
+	if err != nil {
+		return nil, fmt.Errorf("locate GOROOT: %w", err)
+	}
+	goroot := strings.TrimSpace(string(out))
+	for _, dir := range []string{"lib", "misc"} {
+		data, err := os.ReadFile(filepath.Join(goroot, dir, "wasm", wasmExecName))
+		if err == nil {
+			return data, nil
+		}
+		if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%s not found under %s", wasmExecName, goroot)
+}
@@ -0,0 +1,40 @@
+// Code generated by ard. DO NOT EDIT.
+//
+// Glue for an Ard program built with `ard build --target wasm`. The `hooks`
+// passed to main are published as the `ard` global, so programs reach them
+// (and the console/DOM defaults) through Go's syscall/js:
+//
+//   use go:syscall/js
+//   js::Global().Get("ard").Get("console")
+import "./wasm_exec.js";
+
+export async function main({ args = [], hooks = {}, wasm } = {}) {
+  globalThis.ard = { console: globalThis.console, document: globalThis.document, ...hooks };
+  const go = new globalThis.Go();
+  go.argv = ["main", ...args];
+  let exitCode = 0;
+  go.exit = (code) => {
+    exitCode = code;
+  };
+  const source = wasm ?? (await loadWasm(new URL("./main.wasm", import.meta.url)));
+  const { instance } = await WebAssembly.instantiate(source, go.importObject);
+  await go.run(instance);
+  return exitCode;
+}
+
+async function loadWasm(url) {
+  if (url.protocol === "file:") {
+    const { readFile } = await import("node:fs/promises");
+    return readFile(url);
+  }
+  const response = await fetch(url);
+  return response.arrayBuffer();
+}
+
+// Running the glue directly with Node (`node index.mjs args...`) runs main.
+if (typeof process !== "undefined" && process.argv?.[1]) {
+  const { pathToFileURL } = await import("node:url");
+  if (import.meta.url === pathToFileURL(process.argv[1]).href) {
+    process.exitCode = await main({ args: process.argv.slice(2) });
+  }
+}
@@ -0,0 +1,44 @@
+package gotarget
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuildWasmProgramRunsWithGlue(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a js/wasm binary")
+	}
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	program := lowerSource(t, `
+use go:fmt
+
+fn main() {
+  for i in 1..3 {
+    fmt::Println("line {i}")
+  }
+}
+`)
+	outputDir := filepath.Join(t.TempDir(), "web")
+	gluePath, err := BuildWasmProgram(program, outputDir)
+	if err != nil {
+		t.Fatalf("build wasm: %v", err)
+	}
+	for _, name := range []string{wasmModuleName, wasmExecName, wasmGlueName} {
+		if _, err := os.Stat(filepath.Join(outputDir, name)); err != nil {
+			t.Fatalf("expected %s in output: %v", name, err)
+		}
+	}
+	out, err := exec.Command(node, gluePath).CombinedOutput()
+	if err != nil {
+		t.Fatalf("run glue: %v\n%s", err, out)
+	}
+	if got, want := string(out), "line 1\nline 2\nline 3\n"; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,1103 @@
+// Runtime support for JavaScript modules generated by the Ard compiler.
+// Generated modules import this file as `$ard`; keep exports stable with
+// compiler/js/lower.go.
+
+export class Maybe {
+  constructor(some, value) {
+    this.some = some;
+    this.value = value;
+  }
+
+  static some(value) {
+    return new Maybe(true, value);
+  }
+
+  static none() {
+    return NONE;
+  }
+
+  isSome() {
+    return this.some;
+  }
+
+  isNone() {
+    return !this.some;
+  }
+
+  expect(message) {
+    if (!this.some) {
+      panic(message);
+    }
+    return this.value;
+  }
+
+  or(fallback) {
+    return this.some ? this.value : fallback;
+  }
+
+  map(fn) {
+    return this.some ? Maybe.some(fn(this.value)) : NONE;
+  }
+
+  andThen(fn) {
+    return this.some ? fn(this.value) : NONE;
+  }
+}
+
+const NONE = Object.freeze(new Maybe(false, undefined));
+
+export class Result {
+  constructor(ok, value, error) {
+    this.ok = ok;
+    this.value = value;
+    this.error = error;
+  }
+
+  static ok(value) {
+    return new Result(true, value, undefined);
+  }
+
+  static err(error) {
+    return new Result(false, undefined, error);
+  }
+
+  isOk() {
+    return this.ok;
+  }
+
+  isErr() {
+    return !this.ok;
+  }
+
+  expect(message) {
+    if (!this.ok) {
+      panic(`${message}: ${toStr(this.error)}`);
+    }
+    return this.value;
+  }
+
+  or(fallback) {
+    return this.ok ? this.value : fallback;
+  }
+
+  map(fn) {
+    return this.ok ? Result.ok(fn(this.value)) : this;
+  }
+
+  mapErr(fn) {
+    return this.ok ? this : Result.err(fn(this.error));
+  }
+
+  andThen(fn) {
+    return this.ok ? fn(this.value) : this;
+  }
+}
+
+// Union values carry the member tag assigned in AIR so matches can
+// discriminate members that share a JavaScript representation.
+export class Union {
+  constructor(tag, value) {
+    this.tag = tag;
+    this.value = value;
+  }
+}
+
+export function union(tag, value) {
+  return new Union(tag, value);
+}
+
+// Trait objects pair a value with the implementation's methods, ordered like
+// the trait's method table. Each method takes the receiver first.
+export class TraitObject {
+  constructor(value, methods) {
+    this.value = value;
+    this.methods = methods;
+  }
+
+  call(method, ...args) {
+    return this.methods[method](this.value, ...args);
+  }
+}
+
+export function traitObject(value, methods) {
+  return new TraitObject(value, methods);
+}
+
+// unsafeCast implements unsafe::cast by checking the value's runtime shape.
+export function unsafeCast(value, shape) {
+  let matches;
+  switch (shape) {
+    case "int":
+      matches = Number.isInteger(value);
+      break;
+    case "float":
+      matches = typeof value === "number";
+      break;
+    case "str":
+      matches = typeof value === "string";
+      break;
+    case "bool":
+      matches = typeof value === "boolean";
+      break;
+    case "list":
+      matches = Array.isArray(value);
+      break;
+    case "map":
+      matches = value instanceof Map;
+      break;
+    default:
+      matches = value != null;
+  }
+  return matches ? Maybe.some(value) : NONE;
+}
+
+export function makeError(message) {
+  return new TraitObject(message, [(value) => value]);
+}
+
+// WrappedError is the value behind an Error::wrap result. Its cause is the
+// wrapped Error trait object.
+class WrappedError {
+  constructor(message, cause) {
+    this.message = message;
+    this.cause = cause;
+  }
+}
+
+export function wrapError(err, context) {
+  return new TraitObject(new WrappedError(`${context}: ${err.call(0)}`, err), [(value) => value.message]);
+}
+
+export function errorCause(err) {
+  return err.value instanceof WrappedError ? Maybe.some(err.value.cause) : NONE;
+}
+
+export class Panic extends Error {
+  constructor(message) {
+    super(message);
+    this.name = "Panic";
+  }
+}
+
+export function panic(message) {
+  throw new Panic(toStr(message));
+}
+
+export function intDiv(left, right) {
+  if (right === 0) {
+    panic("integer divide by zero");
+  }
+  return Math.trunc(left / right);
+}
+
+export function intMod(left, right) {
+  if (right === 0) {
+    panic("integer divide by zero");
+  }
+  return left % right;
+}
+
+export function intAbs(value) {
+  return Math.abs(value);
+}
+
+// intPow matches the Go runtime's IntPow, including the panic on a negative
+// exponent.
+export function intPow(base, exponent) {
+  if (exponent < 0) {
+    panic(`Int.pow: negative exponent ${exponent}`);
+  }
+  let result = 1;
+  while (exponent > 0) {
+    if (exponent % 2 === 1) {
+      result *= base;
+    }
+    base *= base;
+    exponent = Math.floor(exponent / 2);
+  }
+  return result;
+}
+
+export function intClamp(value, low, high) {
+  if (low > high) {
+    panic(`Int.clamp: low ${low} is greater than high ${high}`);
+  }
+  return Math.min(Math.max(value, low), high);
+}
+
+// floatRound matches Go's math.Round, which rounds halves away from zero.
+export function floatRound(value) {
+  return Math.sign(value) * Math.round(Math.abs(value));
+}
+
+const INT_MIN = -(2n ** 63n);
+const INT_MAX = 2n ** 63n - 1n;
+
+// intParse matches the Go runtime's IntParse: a base-10 integer with an
+// optional sign that fits in 64 bits.
+export function intParse(text) {
+  if (!/^[+-]?[0-9]+$/.test(text)) {
+    return Result.err(`${JSON.stringify(text)} is not a valid Int`);
+  }
+  const value = BigInt(text);
+  if (value < INT_MIN || value > INT_MAX) {
+    return Result.err(`${JSON.stringify(text)} is out of range for Int`);
+  }
+  return Result.ok(Number(value));
+}
+
+// floatParse matches the Go runtime's FloatParse: decimal notation with an
+// optional exponent.
+export function floatParse(text) {
+  if (!/^[+-]?([0-9]+\.?[0-9]*|\.[0-9]+)([eE][+-]?[0-9]+)?$/.test(text)) {
+    return Result.err(`${JSON.stringify(text)} is not a valid Float64`);
+  }
+  const value = Number(text);
+  if (!Number.isFinite(value)) {
+    return Result.err(`${JSON.stringify(text)} is out of range for Float64`);
+  }
+  return Result.ok(value);
+}
+
+// eq compares values structurally. It walks both values with an explicit
+// work list instead of recursion so deep values cannot overflow the stack,
+// and it treats a pair it is already comparing as equal so cyclic values
+// (possible through shared mutable fields) terminate.
+export function eq(left, right) {
+  const pending = [[left, right]];
+  const comparing = new Map();
+  while (pending.length > 0) {
+    const [a, b] = pending.pop();
+    if (a === b) {
+      continue;
+    }
+    if (a === null || b === null || typeof a !== "object" || typeof b !== "object") {
+      return false;
+    }
+    if (a.constructor !== b.constructor) {
+      return false;
+    }
+    let seen = comparing.get(a);
+    if (seen === undefined) {
+      seen = new Set();
+      comparing.set(a, seen);
+    }
+    if (seen.has(b)) {
+      continue;
+    }
+    seen.add(b);
+    if (a instanceof Maybe) {
+      if (a.some !== b.some) {
+        return false;
+      }
+      pending.push([a.value, b.value]);
+    } else if (a instanceof Result) {
+      if (a.ok !== b.ok) {
+        return false;
+      }
+      pending.push([a.value, b.value], [a.error, b.error]);
+    } else if (a instanceof Union) {
+      if (a.tag !== b.tag) {
+        return false;
+      }
+      pending.push([a.value, b.value]);
+    } else if (a instanceof TraitObject) {
+      pending.push([a.value, b.value]);
+    } else if (Array.isArray(a)) {
+      if (a.length !== b.length) {
+        return false;
+      }
+      for (let i = 0; i < a.length; i++) {
+        pending.push([a[i], b[i]]);
+      }
+    } else if (a instanceof Map) {
+      if (a.size !== b.size) {
+        return false;
+      }
+      for (const [key, value] of a) {
+        if (!b.has(key)) {
+          return false;
+        }
+        pending.push([value, b.get(key)]);
+      }
+    } else {
+      const keys = Object.keys(a);
+      if (keys.length !== Object.keys(b).length) {
+        return false;
+      }
+      for (const key of keys) {
+        if (!Object.prototype.hasOwnProperty.call(b, key)) {
+          return false;
+        }
+        pending.push([a[key], b[key]]);
+      }
+    }
+  }
+  return true;
+}
+
+// copyStruct gives struct values Ard's value semantics when they are stored
+// from an existing place. Nested lists and maps stay shared, as in Go.
+export function copyStruct(value) {
+  return value === null || typeof value !== "object" ? value : { ...value };
+}
+
+const inspecting = new Set();
+
+// inspectOnce renders value with render unless value is already being
+// inspected further up. Lists, maps, and structs reached through mutable
+// fields are shared, so they can form cycles; a repeat renders as `<cycle>`.
+export function inspectOnce(value, render) {
+  if (inspecting.has(value)) {
+    return "<cycle>";
+  }
+  inspecting.add(value);
+  try {
+    return render();
+  } finally {
+    inspecting.delete(value);
+  }
+}
+
+const LEAVE = Symbol("leave");
+
+// toStr renders a value the way Go's fmt.Sprint renders the Go target's
+// representation. Like eq it uses an explicit work list; a value that is
+// reached again while it is still being printed renders as `<cycle>`.
+export function toStr(value) {
+  const out = [];
+  const work = [value];
+  const printing = new Set();
+  while (work.length > 0) {
+    const item = work.pop();
+    if (item === LEAVE) {
+      printing.delete(work.pop());
+      continue;
+    }
+    if (item instanceof Text) {
+      out.push(item.text);
+      continue;
+    }
+    if (item === undefined) {
+      continue;
+    }
+    if (item === null || typeof item !== "object") {
+      out.push(String(item));
+      continue;
+    }
+    if (item instanceof Maybe) {
+      work.push(item.some ? item.value : new Text("none"));
+      continue;
+    }
+    if (item instanceof Result) {
+      work.push(item.ok ? item.value : item.error);
+      continue;
+    }
+    if (item instanceof WrappedError) {
+      out.push(item.message);
+      continue;
+    }
+    if (item instanceof Union || item instanceof TraitObject) {
+      work.push(item.value);
+      continue;
+    }
+    if (printing.has(item)) {
+      out.push("<cycle>");
+      continue;
+    }
+    printing.add(item);
+    work.push(item, LEAVE);
+    let open = "{";
+    let parts;
+    if (Array.isArray(item)) {
+      open = "[";
+      parts = item;
+    } else if (item instanceof Map) {
+      open = "map[";
+      parts = [];
+      for (const [key, entry] of item) {
+        parts.push(new Entry(key, entry));
+      }
+    } else {
+      parts = Object.values(item);
+    }
+    work.push(new Text(open === "{" ? "}" : "]"));
+    for (let i = parts.length - 1; i >= 0; i--) {
+      const part = parts[i];
+      if (part instanceof Entry) {
+        work.push(part.value, new Text(":"), part.key);
+      } else {
+        work.push(part);
+      }
+      if (i > 0) {
+        work.push(new Text(" "));
+      }
+    }
+    out.push(open);
+  }
+  return out.join("");
+}
+
+class Text {
+  constructor(text) {
+    this.text = text;
+  }
+}
+
+class Entry {
+  constructor(key, value) {
+    this.key = key;
+    this.value = value;
+  }
+}
+
+export function floatToStr(value) {
+  return value.toFixed(2);
+}
+
+const encoder = new TextEncoder();
+
+export function strSize(value) {
+  return encoder.encode(value).length;
+}
+
+export function strBytes(value) {
+  return Array.from(encoder.encode(value));
+}
+
+export function strRunes(value) {
+  return Array.from(value, (char) => char.codePointAt(0));
+}
+
+export function strAt(value, index) {
+  const chars = Array.from(value);
+  return index < 0 || index >= chars.length ? NONE : Maybe.some(chars[index]);
+}
+
+export function strTrim(value) {
+  let start = 0;
+  let end = value.length;
+  while (start < end && value[start] === " ") {
+    start++;
+  }
+  while (end > start && value[end - 1] === " ") {
+    end--;
+  }
+  return value.slice(start, end);
+}
+
+export function strTrimStart(value) {
+  let start = 0;
+  while (start < value.length && value[start] === " ") {
+    start++;
+  }
+  return value.slice(start);
+}
+
+export function strTrimEnd(value) {
+  let end = value.length;
+  while (end > 0 && value[end - 1] === " ") {
+    end--;
+  }
+  return value.slice(0, end);
+}
+
+// The Str helpers below count code points, matching the Go runtime's runes,
+// rather than the UTF-16 units JavaScript strings index by.
+
+export function strSlice(value, start, end) {
+  const chars = Array.from(value);
+  const from = Math.min(Math.max(start, 0), chars.length);
+  const to = Math.min(Math.max(end, from), chars.length);
+  return chars.slice(from, to).join("");
+}
+
+export function strIndexOf(value, search) {
+  const index = value.indexOf(search);
+  return index < 0 ? NONE : Maybe.some(Array.from(value.slice(0, index)).length);
+}
+
+// strToUpper and strToLower convert one code point at a time, keeping any
+// character whose conversion would change its length (such as "ß"). This
+// tracks Go's single-rune case mapping for all but a few characters.
+export function strToUpper(value) {
+  return strMapCase(value, (char) => char.toUpperCase());
+}
+
+export function strToLower(value) {
+  return strMapCase(value, (char) => char.toLowerCase());
+}
+
+function strMapCase(value, convert) {
+  return Array.from(value, (char) => {
+    const converted = convert(char);
+    return Array.from(converted).length === 1 ? converted : char;
+  }).join("");
+}
+
+export function strPadLeft(value, width, fill) {
+  return strPadding(value, width, fill) + value;
+}
+
+export function strPadRight(value, width, fill) {
+  return value + strPadding(value, width, fill);
+}
+
+function strPadding(value, width, fill) {
+  const missing = width - Array.from(value).length;
+  const fillChars = Array.from(fill);
+  if (missing <= 0 || fillChars.length === 0) {
+    return "";
+  }
+  let padding = "";
+  for (let i = 0; i < missing; i++) {
+    padding += fillChars[i % fillChars.length];
+  }
+  return padding;
+}
+
+export function strRepeat(value, count) {
+  if (count < 0) {
+    panic(`Str.repeat: negative count ${count}`);
+  }
+  return value.repeat(count);
+}
+
+export function strReverse(value) {
+  return Array.from(value).reverse().join("");
+}
+
+export function strChars(value) {
+  return Array.from(value);
+}
+
+const graphemeSegmenter = new Intl.Segmenter(undefined, { granularity: "grapheme" });
+
+// strGraphemes splits text into extended grapheme clusters (UAX #29), like
+// the Go runtime's StrGraphemes.
+export function strGraphemes(value) {
+  return Array.from(graphemeSegmenter.segment(value), (part) => part.segment);
+}
+
+export function strReplace(value, search, replacement) {
+  return value.replace(search, () => replacement);
+}
+
+export function strReplaceAll(value, search, replacement) {
+  return value.split(search).join(replacement);
+}
+
+export function listAt(list, index) {
+  if (index < 0 || index >= list.length) {
+    panic(`index out of range [${index}] with length ${list.length}`);
+  }
+  return list[index];
+}
+
+export function listAtChecked(list, index) {
+  return index < 0 || index >= list.length ? NONE : Maybe.some(list[index]);
+}
+
+export function listSort(list, less) {
+  list.sort((left, right) => (less(left, right) ? -1 : less(right, left) ? 1 : 0));
+}
+
+export function listSwap(list, left, right) {
+  const value = list[left];
+  list[left] = list[right];
+  list[right] = value;
+}
+
+// HashMap is the Map behind Ard maps. Struct and fixed array keys are equal
+// when their contents are, as in Go, so each entry is stored under a hash of
+// its key and keeps the key itself beside the value.
+export class HashMap extends Map {
+  constructor(entries) {
+    super();
+    for (const [key, value] of entries ?? []) {
+      this.set(key, value);
+    }
+  }
+
+  get(key) {
+    return super.get(hashKey(key))?.[1];
+  }
+
+  set(key, value) {
+    super.set(hashKey(key), [key, value]);
+    return this;
+  }
+
+  has(key) {
+    return super.has(hashKey(key));
+  }
+
+  delete(key) {
+    return super.delete(hashKey(key));
+  }
+
+  *keys() {
+    for (const [key] of super.values()) {
+      yield key;
+    }
+  }
+
+  *values() {
+    for (const [, value] of super.values()) {
+      yield value;
+    }
+  }
+
+  *entries() {
+    for (const [key, value] of super.values()) {
+      yield [key, value];
+    }
+  }
+
+  [Symbol.iterator]() {
+    return this.entries();
+  }
+
+  forEach(callback, thisArg) {
+    for (const [key, value] of this.entries()) {
+      callback.call(thisArg, value, key, this);
+    }
+  }
+}
+
+// hashKey is the key a HashMap stores key under. Primitives key themselves;
+// map keys are checked to hold only primitives, structs, and fixed arrays, so
+// JSON is a faithful encoding of a composite key.
+function hashKey(key) {
+  return key !== null && typeof key === "object" ? JSON.stringify(key) : key;
+}
+
+export function mapGet(map, key) {
+  return map.has(key) ? Maybe.some(map.get(key)) : NONE;
+}
+
+// mapKeys returns the keys of map in ascending order, matching the Go
+// target so iteration and key positions agree across backends.
+export function mapKeys(map) {
+  return Array.from(map.keys()).sort(compareKeys);
+}
+
+// mapEntries walks map in key order, skipping entries deleted mid-iteration.
+export function* mapEntries(map) {
+  for (const key of mapKeys(map)) {
+    if (map.has(key)) {
+      yield [key, map.get(key)];
+    }
+  }
+}
+
+function compareKeys(left, right) {
+  if (typeof left !== typeof right) {
+    return typeof left < typeof right ? -1 : 1;
+  }
+  if (typeof left === "object" && left !== null && right !== null) {
+    // Struct keys compare field by field in declaration order, like Go.
+    const a = Object.values(left);
+    const b = Object.values(right);
+    for (let i = 0; i < Math.min(a.length, b.length); i++) {
+      const order = compareKeys(a[i], b[i]);
+      if (order !== 0) {
+        return order;
+      }
+    }
+    return a.length - b.length;
+  }
+  return left < right ? -1 : left > right ? 1 : 0;
+}
+
+class FormatFloat {
+  constructor(value) {
+    this.value = value;
+  }
+}
+
+export function formatFloat(value) {
+  return new FormatFloat(value);
+}
+
+// format implements Str::format, matching the Go runtime's Format: `{key}` or
+// `{key:[[fill]align][0][width][.precision]}` placeholders, with `{{` and `}}`
+// for literal braces.
+export function format(template, args, named) {
+  let out = "";
+  let next = 0;
+  for (let i = 0; i < template.length; i++) {
+    const ch = template[i];
+    if (ch === "{" && template[i + 1] === "{") {
+      out += "{";
+      i++;
+    } else if (ch === "}" && template[i + 1] === "}") {
+      out += "}";
+      i++;
+    } else if (ch === "}") {
+      panic(`Str::format: unmatched '}' at offset ${i}, write '}}' for a literal brace`);
+    } else if (ch === "{") {
+      const end = template.indexOf("}", i + 1);
+      if (end < 0) {
+        panic(`Str::format: unclosed placeholder at offset ${i}`);
+      }
+      const body = template.slice(i + 1, end);
+      const part = parsePlaceholder(body, next);
+      if (part.auto) {
+        next++;
+      }
+      let value;
+      if (part.name !== "") {
+        if (named === null || !Object.hasOwn(named, part.name)) {
+          panic(`Str::format: no argument named ${part.name}`);
+        }
+        value = named[part.name];
+      } else {
+        if (part.index >= args.length) {
+          panic(`Str::format: no argument at position ${part.index}`);
+        }
+        value = args[part.index];
+      }
+      out += formatValue(part, value);
+      i = end;
+    } else {
+      out += ch;
+    }
+  }
+  return out;
+}
+
+function parsePlaceholder(body, next) {
+  const match = /^([0-9]*|[A-Za-z_][A-Za-z0-9_]*)(?::(?:(.)?([<>^]))?(0(?=[0-9]))?([0-9]*)(?:\.([0-9]+))?)?$/su.exec(body);
+  if (match === null) {
+    const [key] = body.split(":", 1);
+    if (!/^([0-9]*|[A-Za-z_][A-Za-z0-9_]*)$/.test(key)) {
+      panic(`Str::format: invalid placeholder {${body}}`);
+    }
+    if (/\.(?![0-9])/.test(body.slice(key.length))) {
+      panic(`Str::format: missing precision after '.' in {${body}}`);
+    }
+    panic(`Str::format: invalid format spec in {${body}}`);
+  }
+  const [, key, fill, align, zero, width, precision] = match;
+  const part = {
+    auto: key === "",
+    index: -1,
+    name: "",
+    fill: fill ?? " ",
+    align: align ?? "",
+    zero: zero !== undefined && align === undefined,
+    width: Number((zero ?? "") + width),
+    precision: precision === undefined ? -1 : Number(precision),
+  };
+  if (key === "") {
+    part.index = next;
+  } else if (/^[0-9]+$/.test(key)) {
+    part.index = Number(key);
+  } else {
+    part.name = key;
+  }
+  return part;
+}
+
+function formatValue(part, value) {
+  let text;
+  let numeric = true;
+  if (value instanceof FormatFloat) {
+    text = value.value.toFixed(part.precision < 0 ? 2 : part.precision);
+  } else if (typeof value === "number" || typeof value === "bigint") {
+    text = String(value);
+  } else {
+    text = String(value);
+    numeric = false;
+  }
+  if (part.precision >= 0 && !(value instanceof FormatFloat)) {
+    const key = part.name !== "" ? part.name : String(part.index);
+    panic(`Str::format: precision in {${key}} needs a Float64 argument`);
+  }
+  const count = Array.from(text).length;
+  if (count >= part.width) {
+    return text;
+  }
+  const missing = part.width - count;
+  if (part.zero && numeric && part.align === "") {
+    const sign = text.startsWith("-") ? "-" : "";
+    return sign + "0".repeat(missing) + text.slice(sign.length);
+  }
+  const align = part.align !== "" ? part.align : numeric ? ">" : "<";
+  if (align === ">") {
+    return part.fill.repeat(missing) + text;
+  }
+  if (align === "^") {
+    const left = Math.floor(missing / 2);
+    return part.fill.repeat(left) + text + part.fill.repeat(missing - left);
+  }
+  return text + part.fill.repeat(missing);
+}
+
+export function runeToStr(value) {
+  return String.fromCodePoint(value);
+}
+
+function writeStdout(text) {
+  if (typeof process !== "undefined" && process.stdout) {
+    process.stdout.write(text);
+  } else {
+    console.log(text.replace(/\n$/, ""));
+  }
+  return Result.ok(encoder.encode(text).length);
+}
+
+function writeStderr(text) {
+  if (typeof process !== "undefined" && process.stderr) {
+    process.stderr.write(text);
+  } else {
+    console.error(text.replace(/\n$/, ""));
+  }
+}
+
+// StdinReader buffers standard input for ard/io. Outside Node there is no
+// standard input, so it reads as empty.
+class StdinReader {
+  constructor() {
+    this.buffered = new Uint8Array(0);
+    this.done = false;
+    this.decoder = new TextDecoder();
+  }
+
+  // fill reads the next chunk into the buffer and reports whether there was
+  // one
+  fill() {
+    const fs = typeof process !== "undefined" && process.getBuiltinModule ? process.getBuiltinModule("node:fs") : null;
+    if (this.done || fs === null) {
+      this.done = true;
+      return false;
+    }
+    const chunk = new Uint8Array(65536);
+    let count;
+    for (;;) {
+      try {
+        count = fs.readSync(0, chunk, 0, chunk.length, null);
+        break;
+      } catch (error) {
+        // a non-blocking stdin has nothing to read yet
+        if (error.code === "EAGAIN") {
+          continue;
+        }
+        if (error.code === "EOF") {
+          count = 0;
+          break;
+        }
+        throw error;
+      }
+    }
+    if (count === 0) {
+      this.done = true;
+      return false;
+    }
+    const next = new Uint8Array(this.buffered.length + count);
+    next.set(this.buffered);
+    next.set(chunk.subarray(0, count), this.buffered.length);
+    this.buffered = next;
+    return true;
+  }
+
+  take(count) {
+    const bytes = this.buffered.subarray(0, count);
+    this.buffered = this.buffered.subarray(count);
+    return this.decoder.decode(bytes);
+  }
+
+  readLine() {
+    for (;;) {
+      const end = this.buffered.indexOf(10);
+      if (end >= 0) {
+        const line = this.take(end + 1).slice(0, -1);
+        return Maybe.some(line.endsWith("\r") ? line.slice(0, -1) : line);
+      }
+      if (!this.fill()) {
+        break;
+      }
+    }
+    if (this.buffered.length === 0) {
+      return NONE;
+    }
+    const line = this.take(this.buffered.length);
+    return Maybe.some(line.endsWith("\r") ? line.slice(0, -1) : line);
+  }
+
+  readAll() {
+    while (this.fill()) {}
+    return this.take(this.buffered.length);
+  }
+}
+
+// checkedInt returns an exact BigInt result as an Int, or none when it is
+// outside Int::MIN to Int::MAX on this target: -2^53 to 2^53 - 1, the range
+// in which a JavaScript number holds integers exactly.
+function checkedInt(value) {
+  if (value > BigInt(Number.MAX_SAFE_INTEGER) || value < -BigInt(Number.MAX_SAFE_INTEGER) - 1n) {
+    return NONE;
+  }
+  return Maybe.some(Number(value));
+}
+
+// HostWriter holds text for an ard/io Writer until it is flushed.
+class HostWriter {
+  constructor(write) {
+    this.write = write;
+    this.text = "";
+  }
+}
+
+// host maps the Go symbols that have a JavaScript equivalent. Entries return
+// Ard-shaped values: callers see the same Maybe/Result shape the checker
+// assigned to the Go signature.
+export const host = {
+  "fmt.Println": (...args) => writeStdout(args.map(toStr).join(" ") + "\n"),
+  "fmt.Print": (...args) => writeStdout(args.map(toStr).join("")),
+  "fmt.Sprint": (...args) => args.map(toStr).join(""),
+  "fmt.Sprintln": (...args) => args.map(toStr).join(" ") + "\n",
+  "strings.ToUpper": (value) => value.toUpperCase(),
+  "strings.ToLower": (value) => value.toLowerCase(),
+  "strings.TrimSpace": (value) => value.trim(),
+  "strings.Split": (value, separator) => value.split(separator),
+  "strings.Join": (values, separator) => values.join(separator),
+  "strings.Repeat": (value, count) => value.repeat(count),
+  "strings.Contains": (value, search) => value.includes(search),
+  "strings.HasPrefix": (value, prefix) => value.startsWith(prefix),
+  "strings.HasSuffix": (value, suffix) => value.endsWith(suffix),
+  "strings.Index": (value, search) => value.indexOf(search),
+  "strconv.Itoa": (value) => String(value),
+  "math.Sqrt": Math.sqrt,
+  "math.Floor": Math.floor,
+  "math.Ceil": Math.ceil,
+  "math.Abs": Math.abs,
+  "math.Pow": Math.pow,
+  // Go rounds halves away from zero; Math.round rounds them up
+  "math.Round": (value) => Math.sign(value) * Math.round(Math.abs(value)),
+  "math.Min": Math.min,
+  "math.Max": Math.max,
+  "math.Sin": Math.sin,
+  "math.Cos": Math.cos,
+  "math.Tan": Math.tan,
+  "math.Asin": Math.asin,
+  "math.Acos": Math.acos,
+  "math.Atan": Math.atan,
+  "math.Atan2": Math.atan2,
+  // ard/io functions that use Go interop, see hostFunctions in backend.go
+  "ard/io.print": (text) => {
+    writeStdout(text + "\n");
+  },
+  "ard/io.eprint": (text) => {
+    writeStderr(text + "\n");
+  },
+  "ard/io.open_stdin": () => new StdinReader(),
+  "ard/io.read_line_from": (reader) => reader.readLine(),
+  "ard/io.read_all_from": (reader) => reader.readAll(),
+  "ard/io.stdout": () => ({ raw: new HostWriter(writeStdout) }),
+  "ard/io.stderr": () => ({ raw: new HostWriter(writeStderr) }),
+  "ard/io.Writer.write": (self, text) => {
+    self.raw.text += text;
+  },
+  "ard/io.Writer.write_line": (self, text) => {
+    self.raw.text += text + "\n";
+  },
+  "ard/io.Writer.flush": (self) => {
+    if (self.raw.text !== "") {
+      self.raw.write(self.raw.text);
+      self.raw.text = "";
+    }
+    return Result.ok(undefined);
+  },
+  // ard/int's checked operations compute exactly instead of in floating point
+  "ard/int.checked_add": (a, b) => checkedInt(BigInt(a) + BigInt(b)),
+  "ard/int.checked_sub": (a, b) => checkedInt(BigInt(a) - BigInt(b)),
+  "ard/int.checked_mul": (a, b) => checkedInt(BigInt(a) * BigInt(b)),
+  // ard/bigint wraps a native BigInt, whose `/` and `%` truncate like Go's
+  "ard/bigint.BigInt::new": (value) => ({ raw: BigInt(value) }),
+  "ard/bigint.BigInt::parse": (text) => {
+    if (!/^[+-]?[0-9]+$/.test(text)) {
+      return Result.err(`invalid integer: "${text}"`);
+    }
+    return Result.ok({ raw: BigInt(text) });
+  },
+  "ard/bigint.BigInt.is_zero": (self) => self.raw === 0n,
+  "ard/bigint.BigInt.is_negative": (self) => self.raw < 0n,
+  "ard/bigint.BigInt.neg": (self) => ({ raw: -self.raw }),
+  "ard/bigint.BigInt.abs": (self) => ({ raw: self.raw < 0n ? -self.raw : self.raw }),
+  "ard/bigint.BigInt.compare": (self, other) => (self.raw < other.raw ? -1 : self.raw > other.raw ? 1 : 0),
+  "ard/bigint.BigInt.equals": (self, other) => self.raw === other.raw,
+  "ard/bigint.BigInt.add": (self, other) => ({ raw: self.raw + other.raw }),
+  "ard/bigint.BigInt.sub": (self, other) => ({ raw: self.raw - other.raw }),
+  "ard/bigint.BigInt.mul": (self, other) => ({ raw: self.raw * other.raw }),
+  "ard/bigint.BigInt.div": (self, other) => {
+    if (other.raw === 0n) {
+      panic("BigInt.div: division by zero");
+    }
+    return { raw: self.raw / other.raw };
+  },
+  "ard/bigint.BigInt.rem": (self, other) => {
+    if (other.raw === 0n) {
+      panic("BigInt.rem: division by zero");
+    }
+    return { raw: self.raw % other.raw };
+  },
+  "ard/bigint.BigInt.mod_pow": (self, exponent, modulus) => {
+    if (exponent.raw < 0n) {
+      panic(`BigInt.mod_pow: negative exponent ${exponent.raw}`);
+    }
+    if (modulus.raw <= 0n) {
+      panic(`BigInt.mod_pow: modulus ${modulus.raw} is not positive`);
+    }
+    let result = 1n % modulus.raw;
+    let base = ((self.raw % modulus.raw) + modulus.raw) % modulus.raw;
+    for (let rest = exponent.raw; rest > 0n; rest >>= 1n) {
+      if (rest & 1n) {
+        result = (result * base) % modulus.raw;
+      }
+      base = (base * base) % modulus.raw;
+    }
+    return { raw: result };
+  },
+  "ard/bigint.BigInt.to_str": (self) => String(self.raw),
+  "ard/bigint.BigInt.to_int": (self) => checkedInt(self.raw),
+  "sync.OnceValue": (init) => {
+    let state = null;
+    return () => {
+      if (state === null) {
+        try {
+          state = { value: init() };
+        } catch (error) {
+          state = { error };
+        }
+      }
+      if ("error" in state) {
+        throw state.error;
+      }
+      return state.value;
+    };
+  },
+};
+
+// runMain invokes a program root and reports Ard panics the way the Go target
+// does: a `panic:` line on stderr and a non-zero exit status. With `args`, the
+// root receives the command-line arguments after the script; with `exitCode`,
+// its returned Int becomes the exit status.
+export function runMain(root, { args = false, exitCode = false } = {}) {
+  try {
+    const argv = typeof process !== "undefined" ? process.argv.slice(2) : [];
+    const code = args ? root(argv) : root();
+    if (exitCode && typeof process !== "undefined") {
+      process.exitCode = code;
+    }
+  } catch (error) {
+    if (!(error instanceof Panic)) {
+      throw error;
+    }
+    console.error(`panic: ${error.message}`);
+    if (typeof process !== "undefined") {
+      process.exitCode = 2;
+    }
+  }
+}
@@ -0,0 +1,181 @@
+// Package jstarget lowers AIR programs to ES modules. Each Ard module becomes
+// one `.mjs` file that imports the shared runtime shim (ard.runtime.mjs) for
+// Maybe, Result, unions, and trait objects.
+package jstarget
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+)
+
+//go:embed ard.runtime.mjs
+var runtimeSource []byte
+
+const (
+	runtimeFileName = "ard.runtime.mjs"
+	entryFileName   = "index.mjs"
+	generatedHeader = "// Code generated by ard. DO NOT EDIT.\n\n"
+)
+
+// hostSymbols lists the Go functions that the runtime's `host` table
+// provides. Calls to any other Go function are rejected at build time.
+var hostSymbols = map[string]bool{
+	"fmt.Println":       true,
+	"fmt.Print":         true,
+	"fmt.Sprint":        true,
+	"fmt.Sprintln":      true,
+	"strings.ToUpper":   true,
+	"strings.ToLower":   true,
+	"strings.TrimSpace": true,
+	"strings.Split":     true,
+	"strings.Join":      true,
+	"strings.Repeat":    true,
+	"strings.Contains":  true,
+	"strings.HasPrefix": true,
+	"strings.HasSuffix": true,
+	"strings.Index":     true,
+	"strconv.Itoa":      true,
+	"math.Sqrt":         true,
+	"math.Floor":        true,
+	"math.Ceil":         true,
+	"math.Abs":          true,
+	"math.Pow":          true,
+	"math.Round":        true,
+	"math.Min":          true,
+	"math.Max":          true,
+	"math.Sin":          true,
+	"math.Cos":          true,
+	"math.Tan":          true,
+	"math.Asin":         true,
+	"math.Acos":         true,
+	"math.Atan":         true,
+	"math.Atan2":        true,
+	"sync.OnceValue":    true,
+}
+
+// hostFunctions lists the standard library functions whose bodies use Go
+// interop and are replaced by an entry of the same name in the runtime's
+// `host` table, keyed by module path and function name.
+var hostFunctions = map[string]bool{
+	"ard/io.print":                  true,
+	"ard/io.eprint":                 true,
+	"ard/io.open_stdin":             true,
+	"ard/io.read_line_from":         true,
+	"ard/io.read_all_from":          true,
+	"ard/io.stdout":                 true,
+	"ard/io.stderr":                 true,
+	"ard/io.Writer.write":           true,
+	"ard/io.Writer.write_line":      true,
+	"ard/io.Writer.flush":           true,
+	"ard/int.checked_add":           true,
+	"ard/int.checked_sub":           true,
+	"ard/int.checked_mul":           true,
+	"ard/bigint.BigInt::new":        true,
+	"ard/bigint.BigInt::parse":      true,
+	"ard/bigint.BigInt.is_zero":     true,
+	"ard/bigint.BigInt.is_negative": true,
+	"ard/bigint.BigInt.neg":         true,
+	"ard/bigint.BigInt.abs":         true,
+	"ard/bigint.BigInt.compare":     true,
+	"ard/bigint.BigInt.equals":      true,
+	"ard/bigint.BigInt.add":         true,
+	"ard/bigint.BigInt.sub":         true,
+	"ard/bigint.BigInt.mul":         true,
+	"ard/bigint.BigInt.div":         true,
+	"ard/bigint.BigInt.rem":         true,
+	"ard/bigint.BigInt.mod_pow":     true,
+	"ard/bigint.BigInt.to_str":      true,
+	"ard/bigint.BigInt.to_int":      true,
+}
+
+type Options struct {
+	IncludeTests bool
+}
+
+// GenerateSources returns the generated files keyed by their name inside
+// the output directory.
+func GenerateSources(program *air.Program, options Options) (map[string][]byte, error) {
+	if program == nil {
+		return nil, fmt.Errorf("nil program")
+	}
+	l := newLowerer(program, options.IncludeTests)
+	out := map[string][]byte{runtimeFileName: runtimeSource}
+	for _, module := range program.Modules {
+		source, err := l.lowerModule(module, options.IncludeTests)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", module.Path, err)
+		}
+		out[l.files[module.ID]] = []byte(source)
+	}
+	if root, ok := findRootFunction(program); ok {
+		fn := program.Functions[root]
+		out[entryFileName] = []byte(fmt.Sprintf("%simport { runMain } from %s;\nimport { %s } from %s;\n\nrunMain(%s%s);\n",
+			generatedHeader, quote("./"+runtimeFileName), l.functions[root], quote("./"+l.files[fn.Module]), l.functions[root], runMainOptions(program, fn)))
+	}
+	return out, nil
+}
+
+// BuildProgram writes the generated modules to outputDir and returns the
+// absolute path of the entry module.
+func BuildProgram(program *air.Program, outputDir string) (string, error) {
+	if _, ok := findRootFunction(program); !ok {
+		return "", fmt.Errorf("program has no main function or top-level statements to run")
+	}
+	sources, err := GenerateSources(program, Options{})
+	if err != nil {
+		return "", err
+	}
+	absDir, err := filepath.Abs(outputDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(absDir, 0o755); err != nil {
+		return "", err
+	}
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(absDir, name), sources[name], 0o644); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(absDir, entryFileName), nil
+}
+
+// runMainOptions passes `fn main(args: [Str])` its arguments and makes an
+// Int returned by main the exit status.
+func runMainOptions(program *air.Program, fn air.Function) string {
+	var options []string
+	if len(fn.Signature.Params) == 1 {
+		options = append(options, "args: true")
+	}
+	if ret := fn.Signature.Return; ret > 0 && int(ret) <= len(program.Types) && program.Types[ret-1].Kind == air.TypeInt {
+		options = append(options, "exitCode: true")
+	}
+	if len(options) == 0 {
+		return ""
+	}
+	return ", { " + strings.Join(options, ", ") + " }"
+}
+
+func findRootFunction(program *air.Program) (air.FunctionID, bool) {
+	if program == nil {
+		return air.NoFunction, false
+	}
+	if program.Entry != air.NoFunction {
+		return program.Entry, true
+	}
+	if program.Script != air.NoFunction {
+		return program.Script, true
+	}
+	return air.NoFunction, false
+}
@@ -0,0 +1,978 @@
+package jstarget
+
+import (
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/akonwi/ard/air"
+	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/parse"
+)
+
+func lowerSource(t *testing.T, input string) *air.Program {
+	t.Helper()
+	result := parse.Parse([]byte(input), "test.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse error: %s", result.Errors[0].Message)
+	}
+	c := checker.New("test.ard", result.Program, nil, checker.CheckOptions{})
+	c.Check()
+	if c.HasErrors() {
+		t.Fatalf("checker diagnostics: %v", c.Diagnostics())
+	}
+	program, err := air.Lower(c.Module())
+	if err != nil {
+		t.Fatalf("lower error: %v", err)
+	}
+	return program
+}
+
+// runNode builds the program into a temp directory and runs its entry
+// module, returning stdout, stderr, and the process exit code.
+func runNode(t *testing.T, input string) (string, string, int) {
+	t.Helper()
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	entry, err := BuildProgram(lowerSource(t, input), filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	cmd := exec.Command(node, entry)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	exitCode := 0
+	if err := cmd.Run(); err != nil {
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			t.Fatalf("run node: %v", err)
+		}
+		exitCode = exitErr.ExitCode()
+	}
+	return stdout.String(), stderr.String(), exitCode
+}
+
+func TestGenerateSourcesEmitsRuntimeEntryAndModule(t *testing.T) {
+	sources, err := GenerateSources(lowerSource(t, `
+use go:fmt
+
+fn main() {
+  fmt::Println("hi")
+}
+`), Options{})
+	if err != nil {
+		t.Fatalf("generate: %v", err)
+	}
+	for _, name := range []string{runtimeFileName, entryFileName, "test.mjs"} {
+		if _, ok := sources[name]; !ok {
+			t.Fatalf("missing %s in %v", name, keys(sources))
+		}
+	}
+	entry := string(sources[entryFileName])
+	if !strings.Contains(entry, `import { main } from "./test.mjs";`) || !strings.Contains(entry, "runMain(main);") {
+		t.Fatalf("entry does not run main:\n%s", entry)
+	}
+	module := string(sources["test.mjs"])
+	if !strings.Contains(module, `import * as $ard from "./ard.runtime.mjs";`) || !strings.Contains(module, "export function main() {") {
+		t.Fatalf("unexpected module source:\n%s", module)
+	}
+}
+
+func TestJSTargetPassesArgsAndExitCode(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	entry, err := BuildProgram(lowerSource(t, `
+use go:fmt
+
+fn main(args: [Str]) Int {
+  for arg in args {
+    fmt::Println(arg)
+  }
+  args.size()
+}
+`), filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	out, err := exec.Command(node, entry, "one", "two").Output()
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok || exitErr.ExitCode() != 2 {
+		t.Fatalf("run error = %v, want exit status 2", err)
+	}
+	if string(out) != "one\ntwo\n" {
+		t.Fatalf("stdout = %q, want the program arguments", string(out))
+	}
+}
+
+func TestGenerateSourcesRejectsUnsupportedGoInterop(t *testing.T) {
+	_, err := GenerateSources(lowerSource(t, `
+use go:os
+
+fn main() {
+  os::Exit(1)
+}
+`), Options{})
+	if err == nil || !strings.Contains(err.Error(), "JavaScript target does not support Go function os.Exit") {
+		t.Fatalf("expected unsupported interop error, got %v", err)
+	}
+}
+
+func TestGenerateSourcesRejectsInexactIntLiterals(t *testing.T) {
+	_, err := GenerateSources(lowerSource(t, `
+use go:fmt
+
+fn main() {
+  fmt::Println("{9223372036854775807}")
+}
+`), Options{})
+	if err == nil || !strings.Contains(err.Error(), "Int literal 9223372036854775807 is outside the range JavaScript numbers hold exactly") {
+		t.Fatalf("expected inexact literal error, got %v", err)
+	}
+}
+
+func TestJSTargetRunsPrograms(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name: "maybe and result",
+			input: `
+use go:fmt
+
+fn find(n: Int) Int? {
+  match n > 2 {
+    true => Maybe::new(n * 10),
+    false => Maybe::new<Int>(),
+  }
+}
+
+fn half(n: Int) Int!Str {
+  match n % 2 == 0 {
+    true => Result::ok(n / 2),
+    false => Result::err("odd"),
+  }
+}
+
+fn sum_halves(a: Int, b: Int) Int!Str {
+  let x = try half(a)
+  let y = try half(b)
+  Result::ok(x + y)
+}
+
+fn main() {
+  fmt::Println(find(3).or(0))
+  fmt::Println(find(1).or(-1))
+  match sum_halves(4, 6) {
+    ok => fmt::Println(ok),
+    err => fmt::Println(err),
+  }
+  match sum_halves(4, 5) {
+    ok => fmt::Println(ok),
+    err => fmt::Println(err),
+  }
+}
+`,
+			want: "30\n-1\n5\nodd\n",
+		},
+		{
+			name: "traits and unions",
+			input: `
+use go:fmt
+
+trait Named {
+  fn name() Str
+}
+
+struct Dog { nick: Str }
+struct Cat { lives: Int }
+
+impl Named for Dog {
+  fn name() Str { "dog {self.nick}" }
+}
+
+type Pet = Dog | Cat
+
+fn describe(item: Named) Str {
+  item.name()
+}
+
+fn kind(pet: Pet) Str {
+  match pet {
+    Dog => "dog",
+    Cat => "cat with {it.lives} lives",
+  }
+}
+
+fn main() {
+  fmt::Println(describe(Dog{nick: "rex"}))
+  let pets: [Pet] = [Dog{nick: "a"}, Cat{lives: 9}]
+  for pet in pets {
+    fmt::Println(kind(pet))
+  }
+}
+`,
+			want: "dog rex\ndog\ncat with 9 lives\n",
+		},
+		{
+			name: "closures and struct value semantics",
+			input: `
+use go:fmt
+
+struct Point { x: Int, y: Int }
+
+fn main() {
+  mut count = 0
+  let bump = fn() { count = count + 1 }
+  bump()
+  bump()
+  fmt::Println(count)
+
+  mut a = Point{x: 1, y: 2}
+  mut b = a
+  b.x = 10
+  fmt::Println(a.x)
+  fmt::Println(b.x)
+
+  mut nums = [3, 1, 2]
+  nums.sort(fn(l: Int, r: Int) Bool { l < r })
+  for n in nums {
+    fmt::Println(n)
+  }
+}
+`,
+			want: "2\n1\n10\n1\n2\n3\n",
+		},
+		{
+			name: "lazy values",
+			input: `
+use go:fmt
+use ard/lazy
+
+let config = lazy::new(fn() Str {
+  fmt::Println("loading")
+  "config"
+})
+
+fn main() {
+  fmt::Println("start")
+  let copy = config
+  fmt::Println(config.get())
+  fmt::Println(copy.get())
+}
+`,
+			want: "start\nloading\nconfig\nconfig\n",
+		},
+		{
+			name: "math module and int methods",
+			input: `
+use go:fmt
+use ard/math
+
+fn main() {
+  fmt::Println(math::round(-2.5))
+  fmt::Println(math::max(math::floor(1.7), math::ceil(0.2)))
+  fmt::Println(math::abs(math::atan2(1.0, 1.0) - math::PI / 4.0) < 0.000001)
+  let n = -3
+  fmt::Println(n.abs())
+  fmt::Println(n.pow(3))
+  fmt::Println(12.clamp(0, 10))
+}
+`,
+			want: "-3\n1\ntrue\n3\n-27\n10\n",
+		},
+		{
+			name: "checked int arithmetic",
+			input: `
+use go:fmt
+
+fn main() {
+  fmt::Println(Int::checked_add(2, 3).or(0))
+  fmt::Println(Int::checked_mul(-4, 5).or(0))
+  fmt::Println(Int::checked_add(9007199254740991, 1).is_none())
+  fmt::Println(Int::checked_add(Int::MAX, 1).is_none())
+  fmt::Println(Int::checked_sub(Int::MIN, 1).is_none())
+  fmt::Println(Int::checked_add(Int::MIN, -1).is_none())
+  fmt::Println(Int::checked_mul(Int::MAX, 2).is_none())
+  fmt::Println(Int::checked_add(Int::MAX, -1).is_some())
+  fmt::Println(Int::MAX == 9007199254740991 and Int::MIN == -9007199254740991 - 1)
+  fmt::Println(Int::checked_add(Int::MAX, Int::MIN).or(0), "{Int::MIN}")
+}
+`,
+			want: "5\n-20\ntrue\ntrue\ntrue\ntrue\ntrue\ntrue\ntrue\n-1 -9007199254740992\n",
+		},
+		{
+			name: "str methods count runes",
+			input: `
+use go:fmt
+
+fn main() {
+  let word = "héllo wörld"
+  fmt::Println(word.slice(1, 4))
+  fmt::Println(word.index_of("wö").or(-1))
+  fmt::Println(word.to_upper())
+  fmt::Println("é".pad_left(3, "*") + "|" + "ab".pad_right(5, "-="))
+  fmt::Println("  hi  ".trim_start() + "|" + "  hi  ".trim_end() + "|")
+  fmt::Println("ab".repeat(3) + " " + "a😀b".reverse())
+  fmt::Println("añ😀".chars().size())
+}
+`,
+			want: "éll\n6\nHÉLLO WÖRLD\n**é|ab-=-\nhi  |  hi|\nababab b😀a\n3\n",
+		},
+		{
+			name: "str counts bytes runes and graphemes",
+			input: `
+use go:fmt
+
+fn main() {
+  let text = "né👍🏽"
+  mut runes = 0
+  for ch in text {
+    runes = runes + 1
+  }
+  fmt::Println(text.size())
+  fmt::Println(runes)
+  fmt::Println(text.graphemes().size())
+  fmt::Println("🇫🇷🇩🇪".graphemes().size())
+}
+`,
+			want: "11\n4\n3\n2\n",
+		},
+		{
+			name: "rune conversions",
+			input: `
+use go:fmt
+
+fn main() {
+  fmt::Println(Rune::from_int(233).or(' ').to_str())
+  fmt::Println(Rune::from_int(55296).is_none())
+  fmt::Println(Rune::from_str("x").or(' ') < 'y')
+  fmt::Println(Rune::from(97).to_str())
+}
+`,
+			want: "é\ntrue\ntrue\na\n",
+		},
+		{
+			name: "enum payloads",
+			input: `
+use go:fmt
+
+enum Shape {
+  Circle(Int),
+  Rect(Int, Int),
+  Empty,
+}
+
+fn area(shape: Shape) Int {
+  match shape {
+    Shape::Circle(r) => 3 * r * r,
+    Shape::Rect(w, h) => w * h,
+    Shape::Empty => 0,
+  }
+}
+
+fn main() {
+  for shape in [Shape::Circle(2), Shape::Rect(3, 4), Shape::Empty] {
+    fmt::Println(area(shape))
+  }
+}
+`,
+			want: "12\n12\n0\n",
+		},
+		{
+			name: "enum utilities",
+			input: `
+use go:fmt
+
+enum Status {
+  Active,
+  Paused = 5,
+  Done,
+}
+
+fn main() {
+  for status in Status::values() {
+    fmt::Println(status.name())
+  }
+  fmt::Println(Status::count)
+  fmt::Println(Status::from_int(6).is_some())
+  fmt::Println(Status::from_int(1).is_some())
+}
+`,
+			want: "Active\nPaused\nDone\n3\ntrue\nfalse\n",
+		},
+		{
+			name: "struct field defaults",
+			input: `
+use go:fmt
+
+struct Config {
+  host: Str,
+  retries: Int = 3,
+  label: Str = "svc-" + "a",
+}
+
+fn main() {
+  let config = Config{host: "localhost"}
+  fmt::Println(config.retries)
+  fmt::Println(config.label)
+  let custom = Config{host: "h", retries: 5}
+  fmt::Println(custom.retries)
+}
+`,
+			want: "3\nsvc-a\n5\n",
+		},
+		{
+			name: "struct updates",
+			input: `
+use go:fmt
+
+struct Config {
+  host: Str,
+  retries: Int,
+}
+
+fn main() {
+  let base = Config{host: "localhost", retries: 1}
+  let custom = Config{..base, retries: 5}
+  fmt::Println(custom.host)
+  fmt::Println(custom.retries)
+  fmt::Println(base.retries)
+}
+`,
+			want: "localhost\n5\n1\n",
+		},
+		{
+			name: "self type",
+			input: `
+use go:fmt
+
+struct Point {
+  x: Int,
+  y: Int,
+}
+
+fn Point::origin() Self {
+  Self{x: 0, y: 0}
+}
+
+impl Point {
+  fn moved(dx: Int) Self {
+    Self{x: self.x + dx, y: self.y}
+  }
+}
+
+fn main() {
+  let p = Point::origin().moved(2)
+  fmt::Println(p.x)
+}
+`,
+			want: "2\n",
+		},
+		{
+			name: "bound methods",
+			input: `
+use go:fmt
+
+struct Scale {
+  factor: Int,
+}
+
+impl Scale {
+  fn apply(n: Int) Int {
+    n * self.factor
+  }
+}
+
+fn run(f: fn(Int) Int) Int {
+  f(5)
+}
+
+fn main() {
+  let scale = Scale{factor: 2}
+  fmt::Println(run(scale.apply))
+  let apply = Scale::apply
+  fmt::Println(apply(Scale{factor: 3}, 2))
+  mut items: [Int] = []
+  let push = items.push
+  push(1)
+  fmt::Println(items.size())
+}
+`,
+			want: "10\n6\n1\n",
+		},
+		{
+			name: "closure mutable captures",
+			input: `
+use go:fmt
+
+fn main() {
+  mut count = 0
+  let inc = fn() { count = count + 1 }
+  inc()
+  inc()
+  mut items: [Int] = []
+  let push = fn(n: Int) { items.push(n) }
+  push(1)
+  push(2)
+  mut reset = [1, 2, 3]
+  let clear = fn() { reset = [] }
+  clear()
+  mut name = "a"
+  let read = fn() Str { name }
+  name = "b"
+  fmt::Println("{count} {items.size()} {reset.size()} {read()}")
+}
+`,
+			want: "2 2 0 b\n",
+		},
+		{
+			name: "pipes",
+			input: `
+use go:fmt
+use ard/list
+
+fn double(x: Int) Int { x * 2 }
+fn add(x: Int, y: Int) Int { x + y }
+
+fn main() {
+  fmt::Println(3 |> double |> add(1) |> double())
+  let sizes = ["a", "bb"]
+    |> list::map(fn(s: Str) Int { s.size() })
+  fmt::Println(sizes.at(1).or(0))
+}
+`,
+			want: "14\n2\n",
+		},
+		{
+			name: "if let and let else",
+			input: `
+use go:fmt
+
+fn find(n: Int) Int? {
+  match n > 0 {
+    true => Maybe::new(n * 10),
+    false => Maybe::new(),
+  }
+}
+
+fn parse(s: Str) Int!Str {
+  match s == "1" {
+    true => Result::ok(1),
+    false => Result::err("bad"),
+  }
+}
+
+fn describe(n: Int) Str {
+  let found = find(n) else { "nothing" }
+  "found {found}"
+}
+
+fn label(s: Str) Str {
+  if let v = parse(s) {
+    "ok {v}"
+  } else if let w = find(3) {
+    "fallback {w}"
+  } else {
+    "none"
+  }
+}
+
+fn main() {
+  fmt::Println(label("1"))
+  fmt::Println(label("x"))
+  fmt::Println(describe(2))
+  fmt::Println(describe(0))
+  for i in 0..2 {
+    let v = find(i) else { fmt::Println("skip") }
+    fmt::Println(v)
+  }
+}
+`,
+			want: "ok 1\nfallback 30\nfound 20\nnothing\nskip\n",
+		},
+		{
+			name: "union as",
+			input: `
+use go:fmt
+
+struct Circle { radius: Int }
+struct Square { side: Int }
+type Shape = Circle | Square
+
+fn main() {
+  let shape: Shape = Square{side: 3}
+  fmt::Println((shape as Square).expect("not a square").side)
+  fmt::Println((shape as Circle).is_none())
+}
+`,
+			want: "3\ntrue\n",
+		},
+		{
+			name: "numeric literal forms",
+			input: `
+use go:fmt
+
+fn main() {
+  fmt::Println(0xFF + 0b1010 + 0o17)
+  fmt::Println(010)
+  let low: Int8 = -0x80
+  fmt::Println(low)
+  fmt::Println(1.5e9)
+  fmt::Println(2E-3)
+}
+`,
+			want: "280\n10\n-128\n1500000000\n0.002\n",
+		},
+		{
+			name: "numeric conversions",
+			input: `
+use go:fmt
+
+fn main() {
+  let n = 3
+  fmt::Println(n.to_float() / 2.0)
+  let x = -2.5
+  fmt::Println(x.round(), x.floor(), x.ceil(), x.to_int())
+  fmt::Println(Int::parse("-42").or(0), Int::parse("4.2").is_ok())
+  match Int::parse("9223372036854775808") {
+    ok(value) => fmt::Println(value),
+    err(message) => fmt::Println(message),
+  }
+  fmt::Println(Float64::parse("1.5e3").or(0.0), Float64::parse("NaN").is_ok())
+}
+`,
+			want: "1.5\n-3 -3 -2 -2\n-42 false\n\"9223372036854775808\" is out of range for Int\n1500 false\n",
+		},
+		{
+			name: "dynamic inspection",
+			input: `
+use go:fmt
+use ard/dynamic
+use ard/unsafe
+
+fn main() {
+  let items: [Any] = [1, "two", true]
+  let value: Any = items
+  let list = dynamic::as_list(value).expect("not a list")
+  fmt::Println(dynamic::as_int(list.at(0).expect("")).or(0), dynamic::as_str(list.at(1).expect("")).or(""))
+  fmt::Println(dynamic::kind(list.at(2).expect("")) == dynamic::Kind::Bool, dynamic::kind(value) == dynamic::Kind::List)
+  fmt::Println(dynamic::as_map(value).is_some(), unsafe::cast<Str>(list.at(0).expect("")).is_some())
+}
+`,
+			want: "1 two\ntrue true\nfalse false\n",
+		},
+		{
+			name: "bigint arithmetic",
+			input: `
+use go:fmt
+use ard/bigint
+
+fn main() {
+  let big = bigint::BigInt::new(2).pow(100)
+  fmt::Println(big.to_str())
+  fmt::Println((big * -big / big).to_str())
+  let prime = bigint::BigInt::parse("170141183460469231731687303715884105727").expect("parse failed")
+  let one = bigint::BigInt::new(1)
+  fmt::Println(bigint::BigInt::new(3).mod_pow(prime - one, prime) == one, big > prime, (-big % prime).to_str())
+  fmt::Println(bigint::BigInt::parse("12a").is_err(), (big + one).to_int().is_none())
+}
+`,
+			want: "1267650600228229401496703205376\n-1267650600228229401496703205376\ntrue false -1267650600228229401496703205376\ntrue true\n",
+		},
+		{
+			name: "maps iterate in key order",
+			input: `
+use go:fmt
+
+fn main() {
+  mut scores: [Str: Int] = ["zed": 1, "amy": 2, "moe": 3, "bob": 4]
+  for name, score in scores {
+    if name == "amy" {
+      scores.delete("moe")
+    }
+    fmt::Println("{name}={score}")
+  }
+  let ids: [Int: Str] = [30: "c", 10: "a", 20: "b"]
+  fmt::Println(ids.keys().at(0).expect("key"))
+}
+`,
+			want: "amy=2\nbob=4\nzed=1\n10\n",
+		},
+		{
+			name: "str format",
+			input: `
+use go:fmt
+
+fn main() {
+  let template = "\{0:05}|\{1:.1}"
+  fmt::Println(Str::format("[\{0:>5}] [\{1:.3}] [\{label:*^7}]", 42, 3.14159, label: "ard"))
+  fmt::Println(Str::format(template, -7, 2.26))
+  fmt::Println(Str::format("\{} \{}", 2.0, true))
+}
+`,
+			want: "[   42] [3.142] [**ard**]\n-0007|2.3\n2.00 true\n",
+		},
+		{
+			name: "range loops with a step",
+			input: `
+use go:fmt
+
+fn main() {
+  for i in 10..0 by -4 {
+    fmt::Println(i)
+  }
+  let step = 3
+  for i, n in 1..7 by step {
+    fmt::Println("{n}:{i}")
+  }
+}
+`,
+			want: "10\n6\n2\n0:1\n1:4\n2:7\n",
+		},
+		{
+			name: "while let",
+			input: `
+use go:fmt
+
+struct Queue { items: [Int], next: Int }
+
+impl Queue {
+  fn mut pop() Int? {
+    let item = self.items.at(self.next)
+    self.next =+ 1
+    item
+  }
+}
+
+fn main() {
+  mut queue = Queue{items: [1, 2, 3], next: 0}
+  while let item = queue.pop() {
+    fmt::Println(item)
+  }
+}
+`,
+			want: "1\n2\n3\n",
+		},
+		{
+			name: "struct patterns",
+			input: `
+use go:fmt
+
+struct Point { x: Int, y: Int }
+
+fn describe(p: Point) Str {
+  match p {
+    Point{x: 0, y: 0} => "origin",
+    Point{x: 0, y} => "y={y}",
+    Point{x, y: _} => "x={x}",
+  }
+}
+
+fn main() {
+  fmt::Println(describe(Point{x: 0, y: 0}))
+  fmt::Println(describe(Point{x: 0, y: 2}))
+  fmt::Println(describe(Point{x: 3, y: 4}))
+}
+`,
+			want: "origin\ny=2\nx=3\n",
+		},
+		{
+			name: "nested match patterns",
+			input: `
+use go:fmt
+
+fn lookup(id: Int) Int?!Str {
+  match id {
+    0 => Result::err("bad id"),
+    1 => Result::ok(Maybe::new()),
+    _ => Result::ok(Maybe::new(id * 10)),
+  }
+}
+
+fn main() {
+  for id in 0..2 {
+    let text = match lookup(id) {
+      ok(some(x)) => "found {x}",
+      ok(_) => "missing",
+      err(e) => e,
+    }
+    fmt::Println(text)
+  }
+}
+`,
+			want: "bad id\nmissing\nfound 20\n",
+		},
+		{
+			name: "or-patterns",
+			input: `
+use go:fmt
+
+enum Color { red, green, blue }
+
+fn main() {
+  for n in [1, 3, 7] {
+    let size = match n {
+      1 | 2 | 3 => "few",
+      _ => "many",
+    }
+    fmt::Println(size)
+  }
+  for s in ["a", "x"] {
+    let kind = match s {
+      "a" | "e" => "vowel",
+      _ => "consonant",
+    }
+    fmt::Println(kind)
+  }
+  let warm = match Color::green {
+    Color::red | Color::green => "warm",
+    Color::blue => "cool",
+  }
+  fmt::Println(warm)
+}
+`,
+			want: "few\nfew\nmany\nvowel\nconsonant\nwarm\n",
+		},
+		{
+			name: "inspect",
+			input: `
+use go:fmt
+
+enum Shape { circle(Float64), dot }
+struct Node { value: Int, next: Node? }
+
+fn main() {
+  let node = Node{value: 1, next: Maybe::new(Node{value: 2, next: Maybe::new<Node>()})}
+  fmt::Println("{node}")
+  fmt::Println(inspect([Shape::circle(1.5), Shape::dot]))
+  let counts: [Str: Int] = ["b": 2, "a": 1]
+  let empty: [Str: Int] = [:]
+  fmt::Println("{counts} {empty}")
+  let r: Int!Str = Result::err("bad")
+  fmt::Println(inspect(r))
+}
+`,
+			want: "Node{value: 1, next: some(Node{value: 2, next: none})}\n[Shape::circle(1.50), Shape::dot]\n[\"a\": 1, \"b\": 2] [:]\nerr(\"bad\")\n",
+		},
+		{
+			name: "struct map keys compare by value",
+			input: `
+use go:fmt
+
+struct Point { x: Int, y: Int }
+
+fn main() {
+  mut names: [Point: Str] = [:]
+  names.set(Point{x: 1, y: 2}, "a")
+  names.set(Point{x: 1, y: 2}, "b")
+  names.set(Point{x: 0, y: 5}, "c")
+  fmt::Println(names.size())
+  fmt::Println(names.get(Point{x: 1, y: 2}).or("missing"))
+  names.delete(Point{x: 0, y: 5})
+  fmt::Println(names.has(Point{x: 0, y: 5}))
+  for point, name in names {
+    fmt::Println("{point.x},{point.y} {name}")
+  }
+}
+`,
+			want: "2\nb\nfalse\n1,2 b\n",
+		},
+		{
+			name: "inspect renders cycles once",
+			input: `
+use go:fmt
+
+struct Node { name: Str, children: [Str: Node] }
+
+fn main() {
+  mut kids: [Str: Node] = [:]
+  let root = Node{name: "root", children: kids}
+  kids.set("self", root)
+  fmt::Println("{root}")
+}
+`,
+			want: "Node{name: \"root\", children: [\"self\": Node{name: \"root\", children: <cycle>}]}\n",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stdout, stderr, code := runNode(t, tc.input)
+			if code != 0 {
+				t.Fatalf("exit code %d\nstderr:\n%s", code, stderr)
+			}
+			if stdout != tc.want {
+				t.Fatalf("stdout mismatch\nwant:\n%s\ngot:\n%s", tc.want, stdout)
+			}
+		})
+	}
+}
+
+func TestJSTargetRunsArdIO(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	entry, err := BuildProgram(lowerSource(t, `
+use ard/io
+
+fn main() {
+  io::print("hi")
+  io::eprint("oops")
+  let first = io::read_line().or("none")
+  io::print("first: {first}")
+  mut out = io::stdout()
+  out.write("rest: ")
+  out.write_line(io::read_all())
+  out.flush().expect("flush")
+  io::print("done: {io::read_line().is_none()}")
+}
+`), filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	cmd := exec.Command(node, entry)
+	cmd.Stdin = strings.NewReader("one\r\ntwo\nthree")
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("run node: %v\nstderr:\n%s", err, stderr.String())
+	}
+	if want := "hi\nfirst: one\nrest: two\nthree\ndone: true\n"; stdout.String() != want {
+		t.Fatalf("stdout = %q, want %q", stdout.String(), want)
+	}
+	if stderr.String() != "oops\n" {
+		t.Fatalf("stderr = %q, want %q", stderr.String(), "oops\n")
+	}
+}
+
+func TestJSTargetReportsPanics(t *testing.T) {
+	_, stderr, code := runNode(t, `
+fn main() {
+  panic("boom")
+}
+`)
+	if code == 0 {
+		t.Fatalf("expected non-zero exit code")
+	}
+	if !strings.Contains(stderr, "panic: boom") {
+		t.Fatalf("expected panic message, got:\n%s", stderr)
+	}
+}
+
+func keys(sources map[string][]byte) []string {
+	out := make([]string, 0, len(sources))
+	for name := range sources {
+		out = append(out, name)
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package jstarget
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+)
+
+// lowerInspect renders a value the way it is written in Ard source. User types
+// and containers render through module-level functions generated per type, so
+// recursive types render through recursive calls.
+func (l *lowerer) lowerInspect(sc *scope, expr air.Expr) (loweredExpr, error) {
+	return l.mapTarget(sc, expr, "inspect", func(target string) string {
+		return l.inspectValue(expr.Target.Type, target)
+	})
+}
+
+// inspectValue is the JavaScript expression that renders value, a value of
+// typeID. Primitives render inline and read value once.
+func (l *lowerer) inspectValue(typeID air.TypeID, value string) string {
+	switch l.kind(typeID) {
+	case air.TypeVoid:
+		return quote("void")
+	case air.TypeInt, air.TypeByte, air.TypeBool:
+		return fmt.Sprintf("String(%s)", value)
+	case air.TypeFloat64:
+		return fmt.Sprintf("$ard.floatToStr(%s)", value)
+	case air.TypeStr:
+		return fmt.Sprintf("JSON.stringify(%s)", value)
+	case air.TypeRune:
+		return fmt.Sprintf("(\"'\" + $ard.runeToStr(%s) + \"'\")", value)
+	case air.TypeStruct, air.TypeEnum, air.TypeMaybe, air.TypeResult, air.TypeUnion, air.TypeList, air.TypeFixedArray, air.TypeMap:
+		return fmt.Sprintf("%s(%s)", l.inspector(typeID), value)
+	default:
+		return fmt.Sprintf("$ard.toStr(%s)", value)
+	}
+}
+
+// inspector names the function of the current module that renders values of
+// typeID, generating it and the inspectors it calls on first use.
+func (l *lowerer) inspector(typeID air.TypeID) string {
+	if name, ok := l.inspectors[typeID]; ok {
+		return name
+	}
+	name := fmt.Sprintf("$inspect%d", typeID)
+	l.inspectors[typeID] = name
+	info, _ := l.typeInfo(typeID)
+	body := l.inspectorBody(info)
+	lines := []string{"", fmt.Sprintf("function %s(value) {", name)}
+	if info.Kind == air.TypeStruct || info.Kind == air.TypeList || info.Kind == air.TypeFixedArray || info.Kind == air.TypeMap {
+		// these values can be shared, and so can contain themselves
+		body = append([]string{"return $ard.inspectOnce(value, () => {"}, append(indent(body), "});")...)
+	}
+	lines = append(lines, indent(body)...)
+	l.inspectorLines = append(l.inspectorLines, append(lines, "}")...)
+	return name
+}
+
+func (l *lowerer) inspectorBody(info air.TypeInfo) []string {
+	switch info.Kind {
+	case air.TypeStruct:
+		parts := make([]string, len(info.Fields))
+		for i, field := range info.DeclaredFields() {
+			parts[i] = fmt.Sprintf("%s + %s", quote(field.Name+": "), l.inspectValue(field.Type, propertyAccess("value", field.Name)))
+		}
+		return []string{fmt.Sprintf("return %s;", joinRendered(quote(l.inspectTypeName(info)+"{"), parts, quote("}")))}
+	case air.TypeEnum:
+		name := l.inspectTypeName(info)
+		subject := "value"
+		if info.HasPayload() {
+			subject = "value.tag"
+		}
+		lines := []string{fmt.Sprintf("switch (%s) {", subject)}
+		for _, variant := range info.Variants {
+			rendered := quote(name + "::" + variant.Name)
+			if len(variant.Payload) > 0 {
+				values := make([]string, len(variant.Payload))
+				for i, payload := range variant.Payload {
+					values[i] = l.inspectValue(payload, fmt.Sprintf("value.values[%d]", i))
+				}
+				rendered = joinRendered(quote(name+"::"+variant.Name+"("), values, quote(")"))
+			}
+			lines = append(lines, fmt.Sprintf("  case %d:", variant.Discriminant), fmt.Sprintf("    return %s;", rendered))
+		}
+		return append(lines, "}", fmt.Sprintf("return String(%s);", subject))
+	case air.TypeMaybe:
+		return []string{fmt.Sprintf("return value.some ? %s : %s;", joinRendered(quote("some("), []string{l.inspectValue(info.Elem, "value.value")}, quote(")")), quote("none"))}
+	case air.TypeResult:
+		ok := joinRendered(quote("ok("), []string{l.inspectValue(info.Value, "value.value")}, quote(")"))
+		err := joinRendered(quote("err("), []string{l.inspectValue(info.Error, "value.error")}, quote(")"))
+		return []string{fmt.Sprintf("return value.ok ? %s : %s;", ok, err)}
+	case air.TypeUnion:
+		lines := []string{"switch (value.tag) {"}
+		for _, member := range info.Members {
+			lines = append(lines, fmt.Sprintf("  case %d:", member.Tag), fmt.Sprintf("    return %s;", l.inspectValue(member.Type, "value.value")))
+		}
+		return append(lines, "}", "return $ard.toStr(value);")
+	case air.TypeList, air.TypeFixedArray:
+		return []string{fmt.Sprintf("return \"[\" + value.map((item) => %s).join(\", \") + \"]\";", l.inspectValue(info.Elem, "item"))}
+	case air.TypeMap:
+		entry := fmt.Sprintf("%s + \": \" + %s", l.inspectValue(info.Key, "key"), l.inspectValue(info.Value, "value.get(key)"))
+		return []string{
+			"if (value.size === 0) {",
+			"  return \"[:]\";",
+			"}",
+			fmt.Sprintf("return \"[\" + $ard.mapKeys(value).map((key) => %s).join(\", \") + \"]\";", entry),
+		}
+	default:
+		return []string{"return $ard.toStr(value);"}
+	}
+}
+
+// inspectTypeName is the name a user type is written with in Ard source. An
+// instance of a generic type uses its definition's name.
+func (l *lowerer) inspectTypeName(info air.TypeInfo) string {
+	if generic, ok := l.typeInfo(info.Generic); ok {
+		return generic.Name
+	}
+	return info.Name
+}
+
+// joinRendered concatenates open, the rendered parts separated by commas, and
+// close.
+func joinRendered(open string, parts []string, close string) string {
+	if len(parts) == 0 {
+		return open + " + " + close
+	}
+	return open + " + " + strings.Join(parts, " + \", \" + ") + " + " + close
+}
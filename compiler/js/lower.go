@@ -0,0 +1,1872 @@
+package jstarget
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+)
+
+type loweredExpr struct {
+	stmts []string
+	expr  string
+}
+
+// blockSink decides what happens to the result of a lowered block: it is
+// returned, assigned to a temp, or evaluated for effects only.
+type blockSink func(expr string) []string
+
+type lowerer struct {
+	program     *air.Program
+	files       map[air.ModuleID]string
+	functions   map[air.FunctionID]string
+	globals     map[air.GlobalID]string
+	moduleNames map[air.ModuleID]map[string]bool
+	closures    map[air.FunctionID]bool
+	module      air.ModuleID
+	modulePath  string
+	imports     map[air.ModuleID]bool
+	tempCounter int
+	// inspectors names the current module's generated inspect functions by
+	// the type they render; inspectorLines holds their definitions.
+	inspectors     map[air.TypeID]string
+	inspectorLines []string
+}
+
+// scope tracks the JavaScript names of one AIR function's locals. Closures
+// share their parent's used-name set, so an inner binding never shadows an
+// outer one that a capture still refers to.
+type scope struct {
+	fn        air.Function
+	names     map[air.LocalID]string
+	used      map[string]bool
+	declared  map[air.LocalID]bool
+	hoisted   []string
+	usesDefer bool
+}
+
+func newLowerer(program *air.Program, includeTests bool) *lowerer {
+	l := &lowerer{
+		program:     program,
+		files:       map[air.ModuleID]string{},
+		functions:   map[air.FunctionID]string{},
+		globals:     map[air.GlobalID]string{},
+		moduleNames: map[air.ModuleID]map[string]bool{},
+		closures:    map[air.FunctionID]bool{},
+	}
+	usedFiles := map[string]bool{"index.mjs": true, runtimeFileName: true}
+	for _, module := range program.Modules {
+		name := moduleFileName(module)
+		base := strings.TrimSuffix(name, ".mjs")
+		for i := 2; usedFiles[name]; i++ {
+			name = fmt.Sprintf("%s_%d.mjs", base, i)
+		}
+		usedFiles[name] = true
+		l.files[module.ID] = name
+		l.moduleNames[module.ID] = map[string]bool{}
+	}
+	for _, fn := range program.Functions {
+		for _, block := range functionBlocks(fn) {
+			walkBlock(block, func(expr *air.Expr) {
+				if expr.Kind == air.ExprMakeClosure {
+					l.closures[expr.Function] = true
+				}
+			})
+		}
+	}
+	for _, global := range program.Globals {
+		walkExpr(&global.Value, func(expr *air.Expr) {
+			if expr.Kind == air.ExprMakeClosure {
+				l.closures[expr.Function] = true
+			}
+		})
+	}
+	for _, fn := range program.Functions {
+		if !l.emitsFunction(fn, includeTests) {
+			continue
+		}
+		l.functions[fn.ID] = uniqueName(l.namesFor(fn.Module), jsName(fn.Name))
+	}
+	for _, global := range program.Globals {
+		l.globals[global.ID] = uniqueName(l.namesFor(global.Module), jsName(global.Name))
+	}
+	return l
+}
+
+func (l *lowerer) namesFor(module air.ModuleID) map[string]bool {
+	names, ok := l.moduleNames[module]
+	if !ok {
+		names = map[string]bool{}
+		l.moduleNames[module] = names
+	}
+	return names
+}
+
+func (l *lowerer) emitsFunction(fn air.Function, includeTests bool) bool {
+	if l.closures[fn.ID] {
+		return false
+	}
+	return !fn.IsTest || includeTests
+}
+
+func functionBlocks(fn air.Function) []air.Block {
+	return []air.Block{fn.Body}
+}
+
+func walkBlock(block air.Block, visit func(*air.Expr)) {
+	for i := range block.Stmts {
+		stmt := &block.Stmts[i]
+		for _, expr := range []*air.Expr{stmt.Value, stmt.Expr, stmt.Target, stmt.Condition} {
+			walkExpr(expr, visit)
+		}
+		walkBlock(stmt.Body, visit)
+	}
+	walkExpr(block.Result, visit)
+}
+
+func walkExpr(expr *air.Expr, visit func(*air.Expr)) {
+	if expr == nil {
+		return
+	}
+	visit(expr)
+	for _, child := range []*air.Expr{expr.Target, expr.Left, expr.Right, expr.Condition} {
+		walkExpr(child, visit)
+	}
+	for i := range expr.Args {
+		walkExpr(&expr.Args[i], visit)
+	}
+	for i := range expr.Entries {
+		walkExpr(&expr.Entries[i].Key, visit)
+		walkExpr(&expr.Entries[i].Value, visit)
+	}
+	for i := range expr.Fields {
+		walkExpr(&expr.Fields[i].Value, visit)
+	}
+	for _, block := range []air.Block{expr.Body, expr.Then, expr.Else, expr.CatchAll, expr.Some, expr.None, expr.Ok, expr.Err, expr.Catch} {
+		walkBlock(block, visit)
+	}
+	for _, c := range expr.EnumCases {
+		walkBlock(c.Body, visit)
+	}
+	for _, c := range expr.IntCases {
+		walkBlock(c.Body, visit)
+	}
+	for _, c := range expr.StrCases {
+		walkBlock(c.Body, visit)
+	}
+	for _, c := range expr.RangeCases {
+		walkBlock(c.Body, visit)
+	}
+	for _, c := range expr.UnionCases {
+		walkBlock(c.Body, visit)
+	}
+	for _, c := range expr.ForeignCases {
+		walkBlock(c.Body, visit)
+	}
+	for _, c := range expr.SelectCases {
+		walkExpr(c.Channel, visit)
+		walkExpr(c.Value, visit)
+		walkBlock(c.Body, visit)
+	}
+}
+
+func (l *lowerer) lowerModule(module air.Module, includeTests bool) (string, error) {
+	l.module = module.ID
+	l.modulePath = strings.TrimSuffix(module.Path, ".ard")
+	l.imports = map[air.ModuleID]bool{}
+	l.tempCounter = 0
+	l.inspectors = map[air.TypeID]string{}
+	l.inspectorLines = nil
+
+	body := []string{}
+	moduleScope := l.newScope(air.Function{ID: air.NoFunction, Module: module.ID}, nil)
+	for _, global := range l.program.Globals {
+		if global.Module != module.ID {
+			continue
+		}
+		lines, err := l.lowerGlobal(moduleScope, global)
+		if err != nil {
+			return "", fmt.Errorf("global %s: %w", global.Name, err)
+		}
+		body = append(body, lines...)
+	}
+	for _, fn := range l.program.Functions {
+		if fn.Module != module.ID || !l.emitsFunction(fn, includeTests) {
+			continue
+		}
+		lines, err := l.lowerFunction(fn)
+		if err != nil {
+			return "", fmt.Errorf("function %s: %w", fn.Name, err)
+		}
+		body = append(append(body, ""), lines...)
+	}
+	body = append(body, l.inspectorLines...)
+
+	var out bytes.Buffer
+	out.WriteString(generatedHeader)
+	fmt.Fprintf(&out, "import * as $ard from %s;\n", quote("./"+runtimeFileName))
+	imported := make([]air.ModuleID, 0, len(l.imports))
+	for id := range l.imports {
+		imported = append(imported, id)
+	}
+	sort.Slice(imported, func(i, j int) bool { return imported[i] < imported[j] })
+	for _, id := range imported {
+		fmt.Fprintf(&out, "import * as %s from %s;\n", moduleAlias(id), quote("./"+l.files[id]))
+	}
+	if len(moduleScope.hoisted) > 0 {
+		fmt.Fprintf(&out, "\nlet %s;\n", strings.Join(moduleScope.hoisted, ", "))
+	}
+	if len(body) > 0 && body[0] != "" {
+		out.WriteString("\n")
+	}
+	for _, line := range body {
+		out.WriteString(line)
+		out.WriteString("\n")
+	}
+	return out.String(), nil
+}
+
+func moduleAlias(id air.ModuleID) string {
+	return fmt.Sprintf("$m%d", id)
+}
+
+func (l *lowerer) lowerGlobal(sc *scope, global air.Global) ([]string, error) {
+	name := l.globals[global.ID]
+	value, err := l.lowerExpr(sc, global.Value)
+	if err != nil {
+		return nil, err
+	}
+	lines := append([]string{}, value.stmts...)
+	keyword := "const"
+	if global.Mutable {
+		keyword = "let"
+	}
+	lines = append(lines, fmt.Sprintf("export %s %s = %s;", keyword, name, value.expr))
+	if global.Mutable {
+		// ES module bindings are read-only to importers, so other modules
+		// assign through this setter.
+		lines = append(lines, fmt.Sprintf("export function $set_%s(value) {", name), fmt.Sprintf("  %s = value;", name), "}")
+	}
+	return lines, nil
+}
+
+func (l *lowerer) lowerFunction(fn air.Function) ([]string, error) {
+	sc := l.newScope(fn, nil)
+	params := sc.params()
+	if key := l.modulePath + "." + fn.Name; hostFunctions[key] {
+		return []string{
+			fmt.Sprintf("export function %s(%s) {", l.functions[fn.ID], strings.Join(params, ", ")),
+			fmt.Sprintf("  return $ard.host[%s](%s);", quote(key), strings.Join(params, ", ")),
+			"}",
+		}, nil
+	}
+	body, err := l.lowerFunctionBody(sc)
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{fmt.Sprintf("export function %s(%s) {", l.functions[fn.ID], strings.Join(params, ", "))}
+	lines = append(lines, indent(body)...)
+	return append(lines, "}"), nil
+}
+
+func (l *lowerer) newScope(fn air.Function, parent *scope) *scope {
+	sc := &scope{fn: fn, names: map[air.LocalID]string{}, declared: map[air.LocalID]bool{}}
+	if parent != nil {
+		sc.used = parent.used
+		return sc
+	}
+	sc.used = map[string]bool{}
+	for name := range l.namesFor(fn.Module) {
+		sc.used[name] = true
+	}
+	return sc
+}
+
+func (sc *scope) local(id air.LocalID) string {
+	if name, ok := sc.names[id]; ok {
+		return name
+	}
+	base := "v"
+	if int(id) >= 0 && int(id) < len(sc.fn.Locals) && sc.fn.Locals[id].Name != "" {
+		base = sc.fn.Locals[id].Name
+	}
+	name := uniqueName(sc.used, jsName(base))
+	sc.names[id] = name
+	return name
+}
+
+func (sc *scope) params() []string {
+	params := make([]string, len(sc.fn.Signature.Params))
+	for i := range sc.fn.Signature.Params {
+		params[i] = sc.local(air.LocalID(i))
+		sc.declared[air.LocalID(i)] = true
+	}
+	return params
+}
+
+// load names a local for reading. A local that is read before any visible
+// declaration is hoisted to the top of the function.
+func (sc *scope) load(id air.LocalID) string {
+	name := sc.local(id)
+	if !sc.declared[id] {
+		sc.hoisted = append(sc.hoisted, name)
+		sc.declared[id] = true
+	}
+	return name
+}
+
+// declare returns the left-hand side that introduces id in the current block.
+func (sc *scope) declare(id air.LocalID) string {
+	name := sc.local(id)
+	if sc.declared[id] {
+		return name
+	}
+	sc.declared[id] = true
+	return "let " + name
+}
+
+func (sc *scope) isWritebackLocal(id air.LocalID) bool {
+	if int(id) < len(sc.fn.Signature.Params) && sc.fn.Signature.Params[id].Mutable {
+		return true
+	}
+	return int(id) >= 0 && int(id) < len(sc.fn.Locals) && sc.fn.Locals[id].Reference
+}
+
+func (l *lowerer) lowerFunctionBody(sc *scope) ([]string, error) {
+	sink := discardSink
+	if !l.isVoid(sc.fn.Signature.Return) {
+		sink = returnSink
+	}
+	body, err := l.lowerBlock(sc, sc.fn.Body, sink)
+	if err != nil {
+		return nil, err
+	}
+	lines := []string{}
+	if len(sc.hoisted) > 0 {
+		lines = append(lines, fmt.Sprintf("let %s;", strings.Join(sc.hoisted, ", ")))
+	}
+	if !sc.usesDefer {
+		return append(lines, body...), nil
+	}
+	lines = append(lines, "const $defers = [];", "try {")
+	lines = append(lines, indent(body)...)
+	return append(lines,
+		"} finally {",
+		"  for (const deferred of $defers.reverse()) {",
+		"    deferred();",
+		"  }",
+		"}",
+	), nil
+}
+
+func returnSink(expr string) []string {
+	return []string{fmt.Sprintf("return %s;", expr)}
+}
+
+func discardSink(expr string) []string {
+	if isSimpleExpr(expr) {
+		return nil
+	}
+	return []string{expr + ";"}
+}
+
+func assignSink(name string) blockSink {
+	return func(expr string) []string {
+		return []string{fmt.Sprintf("%s = %s;", name, expr)}
+	}
+}
+
+func (l *lowerer) lowerBlock(sc *scope, block air.Block, sink blockSink) ([]string, error) {
+	saved := make(map[air.LocalID]bool, len(sc.declared))
+	for id, declared := range sc.declared {
+		saved[id] = declared
+	}
+	defer func() {
+		for id := range sc.declared {
+			if !saved[id] && !sc.isHoisted(id) {
+				delete(sc.declared, id)
+			}
+		}
+	}()
+	lines := []string{}
+	for _, stmt := range block.Stmts {
+		stmtLines, err := l.lowerStmt(sc, stmt)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, stmtLines...)
+	}
+	if block.Result != nil {
+		result, err := l.lowerExpr(sc, *block.Result)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, result.stmts...)
+		lines = append(lines, sink(result.expr)...)
+	}
+	return lines, nil
+}
+
+func (sc *scope) isHoisted(id air.LocalID) bool {
+	name, ok := sc.names[id]
+	if !ok {
+		return false
+	}
+	for _, hoisted := range sc.hoisted {
+		if hoisted == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *lowerer) lowerStmt(sc *scope, stmt air.Stmt) ([]string, error) {
+	switch stmt.Kind {
+	case air.StmtLet:
+		if stmt.Value == nil {
+			return []string{sc.declare(stmt.Local) + ";"}, nil
+		}
+		value, err := l.lowerExpr(sc, *stmt.Value)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, value.stmts...)
+		return append(lines, fmt.Sprintf("%s = %s;", sc.declare(stmt.Local), l.storedValue(*stmt.Value, value.expr))), nil
+	case air.StmtAssign:
+		if stmt.Value == nil {
+			return nil, fmt.Errorf("assignment missing value")
+		}
+		value, err := l.lowerExpr(sc, *stmt.Value)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, value.stmts...)
+		if !sc.isWritebackLocal(stmt.Local) {
+			return append(lines, fmt.Sprintf("%s = %s;", sc.load(stmt.Local), l.storedValue(*stmt.Value, value.expr))), nil
+		}
+		writeback, err := l.writeback(sc.load(stmt.Local), stmt.Value.Type, value.expr)
+		if err != nil {
+			return nil, err
+		}
+		return append(lines, writeback), nil
+	case air.StmtAssignGlobal:
+		if stmt.Value == nil {
+			return nil, fmt.Errorf("global assignment missing value")
+		}
+		if stmt.Global < 0 || int(stmt.Global) >= len(l.program.Globals) {
+			return nil, fmt.Errorf("invalid global %d", stmt.Global)
+		}
+		value, err := l.lowerExpr(sc, *stmt.Value)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, value.stmts...)
+		global := l.program.Globals[stmt.Global]
+		stored := l.storedValue(*stmt.Value, value.expr)
+		if global.Module == l.module {
+			return append(lines, fmt.Sprintf("%s = %s;", l.globals[global.ID], stored)), nil
+		}
+		l.imports[global.Module] = true
+		return append(lines, fmt.Sprintf("%s.$set_%s(%s);", moduleAlias(global.Module), l.globals[global.ID], stored)), nil
+	case air.StmtSetField:
+		if stmt.Target == nil || stmt.Value == nil {
+			return nil, fmt.Errorf("field assignment missing target or value")
+		}
+		operands, exprs, err := l.lowerOperands(sc, *stmt.Target, *stmt.Value)
+		if err != nil {
+			return nil, err
+		}
+		field, ok := l.field(stmt.Target.Type, stmt.Field)
+		if !ok {
+			return nil, fmt.Errorf("invalid field %d", stmt.Field)
+		}
+		value := exprs[1]
+		if !field.Mutable {
+			value = l.storedValue(*stmt.Value, value)
+		}
+		return append(operands, fmt.Sprintf("%s = %s;", propertyAccess(exprs[0], field.Name), value)), nil
+	case air.StmtExpr:
+		if stmt.Expr == nil {
+			return nil, nil
+		}
+		value, err := l.lowerExpr(sc, *stmt.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return append(value.stmts, discardSink(value.expr)...), nil
+	case air.StmtWhile:
+		if stmt.Condition == nil {
+			return nil, fmt.Errorf("while statement missing condition")
+		}
+		condition, err := l.lowerExpr(sc, *stmt.Condition)
+		if err != nil {
+			return nil, err
+		}
+		body, err := l.lowerBlock(sc, stmt.Body, discardSink)
+		if err != nil {
+			return nil, err
+		}
+		if len(condition.stmts) == 0 {
+			lines := []string{fmt.Sprintf("while (%s) {", condition.expr)}
+			lines = append(lines, indent(body)...)
+			return append(lines, "}"), nil
+		}
+		lines := []string{"while (true) {"}
+		lines = append(lines, indent(condition.stmts)...)
+		lines = append(lines, indent([]string{fmt.Sprintf("if (!%s) {", condition.expr), "  break;", "}"})...)
+		lines = append(lines, indent(body)...)
+		return append(lines, "}"), nil
+	case air.StmtForMap:
+		if stmt.Target == nil {
+			return nil, fmt.Errorf("map for statement missing target")
+		}
+		target, err := l.lowerExpr(sc, *stmt.Target)
+		if err != nil {
+			return nil, err
+		}
+		key := sc.local(stmt.Local)
+		value := sc.local(stmt.ValueLocal)
+		sc.declared[stmt.Local] = true
+		sc.declared[stmt.ValueLocal] = true
+		body, err := l.lowerBlock(sc, stmt.Body, discardSink)
+		delete(sc.declared, stmt.Local)
+		delete(sc.declared, stmt.ValueLocal)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, target.stmts...)
+		lines = append(lines, fmt.Sprintf("for (const [%s, %s] of $ard.mapEntries(%s)) {", key, value, target.expr))
+		lines = append(lines, indent(body)...)
+		return append(lines, "}"), nil
+	case air.StmtBreak:
+		return []string{"break;"}, nil
+	case air.StmtDefer:
+		bodyBlock := stmt.Body
+		if stmt.Expr != nil {
+			bodyBlock = air.Block{Stmts: []air.Stmt{{Kind: air.StmtExpr, Expr: stmt.Expr}}}
+		}
+		body, err := l.lowerBlock(sc, bodyBlock, discardSink)
+		if err != nil {
+			return nil, err
+		}
+		sc.usesDefer = true
+		lines := []string{"$defers.push(() => {"}
+		lines = append(lines, indent(body)...)
+		return append(lines, "});"), nil
+	case air.StmtSetForeignField, air.StmtSetForeignValue:
+		return nil, fmt.Errorf("JavaScript target does not support assigning Go value %s.%s", stmt.ForeignNamespace, stmt.ForeignSymbol)
+	default:
+		return nil, fmt.Errorf("unsupported statement kind %d", stmt.Kind)
+	}
+}
+
+// writeback assigns through a mutable parameter or reference so the caller
+// observes the new value. Only reference-shaped values can be updated in
+// place from JavaScript.
+func (l *lowerer) writeback(target string, typeID air.TypeID, value string) (string, error) {
+	switch l.kind(typeID) {
+	case air.TypeStruct:
+		return fmt.Sprintf("Object.assign(%s, %s);", target, value), nil
+	case air.TypeList:
+		return fmt.Sprintf("%s.splice(0, %s.length, ...%s);", target, target, value), nil
+	default:
+		return "", fmt.Errorf("JavaScript target cannot reassign mutable reference %s of type %s", target, l.typeName(typeID))
+	}
+}
+
+// storedValue copies struct values read from an existing place so that the
+// new binding does not alias the old one, matching Go's value semantics.
+func (l *lowerer) storedValue(expr air.Expr, lowered string) string {
+	if l.kind(expr.Type) != air.TypeStruct {
+		return lowered
+	}
+	switch expr.Kind {
+	case air.ExprLoadLocal, air.ExprLoadGlobal, air.ExprGetField, air.ExprListAt:
+		return fmt.Sprintf("$ard.copyStruct(%s)", lowered)
+	default:
+		return lowered
+	}
+}
+
+func (l *lowerer) typeInfo(id air.TypeID) (air.TypeInfo, bool) {
+	if id <= 0 || int(id) > len(l.program.Types) {
+		return air.TypeInfo{}, false
+	}
+	return l.program.Types[id-1], true
+}
+
+func (l *lowerer) kind(id air.TypeID) air.TypeKind {
+	info, ok := l.typeInfo(id)
+	if !ok {
+		return air.TypeVoid
+	}
+	if info.Kind == air.TypeReference {
+		return l.kind(info.Elem)
+	}
+	return info.Kind
+}
+
+func (l *lowerer) typeName(id air.TypeID) string {
+	info, ok := l.typeInfo(id)
+	if !ok {
+		return "Void"
+	}
+	return info.Name
+}
+
+func (l *lowerer) isVoid(id air.TypeID) bool {
+	return l.kind(id) == air.TypeVoid
+}
+
+func (l *lowerer) field(structType air.TypeID, index int) (air.FieldInfo, bool) {
+	info, ok := l.typeInfo(structType)
+	if ok && info.Kind == air.TypeReference {
+		info, ok = l.typeInfo(info.Elem)
+	}
+	if !ok {
+		return air.FieldInfo{}, false
+	}
+	for _, field := range info.Fields {
+		if field.Index == index {
+			return field, true
+		}
+	}
+	if index >= 0 && index < len(info.Fields) {
+		return info.Fields[index], true
+	}
+	return air.FieldInfo{}, false
+}
+
+func (l *lowerer) nextTemp() string {
+	l.tempCounter++
+	return fmt.Sprintf("$t%d", l.tempCounter)
+}
+
+// spill binds a non-trivial expression to a temp so it is evaluated once.
+func (l *lowerer) spill(value loweredExpr) ([]string, string) {
+	if isSimpleExpr(value.expr) {
+		return value.stmts, value.expr
+	}
+	temp := l.nextTemp()
+	return append(value.stmts, fmt.Sprintf("const %s = %s;", temp, value.expr)), temp
+}
+
+// lowerOperands lowers exprs left to right. When a later operand needs setup
+// statements, earlier operands are bound to temps first so side effects keep
+// their source order.
+func (l *lowerer) lowerOperands(sc *scope, exprs ...air.Expr) ([]string, []string, error) {
+	lowered := make([]loweredExpr, len(exprs))
+	last := -1
+	for i, expr := range exprs {
+		value, err := l.lowerExpr(sc, expr)
+		if err != nil {
+			return nil, nil, err
+		}
+		lowered[i] = value
+		if len(value.stmts) > 0 {
+			last = i
+		}
+	}
+	stmts := []string{}
+	out := make([]string, len(lowered))
+	for i, value := range lowered {
+		stmts = append(stmts, value.stmts...)
+		out[i] = value.expr
+		if i < last && !isLiteralExpr(value.expr) {
+			out[i] = l.nextTemp()
+			stmts = append(stmts, fmt.Sprintf("const %s = %s;", out[i], value.expr))
+		}
+	}
+	return stmts, out, nil
+}
+
+func (l *lowerer) lowerCallArgs(sc *scope, target *air.Expr, args []air.Expr) ([]string, string, []string, error) {
+	exprs := append([]air.Expr{}, args...)
+	if target != nil {
+		exprs = append([]air.Expr{*target}, exprs...)
+	}
+	stmts, lowered, err := l.lowerOperands(sc, exprs...)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if target == nil {
+		return stmts, "", lowered, nil
+	}
+	return stmts, lowered[0], lowered[1:], nil
+}
+
+// valueBlocks lowers a construct whose branches each produce the value of
+// the whole expression into a temp declared up front.
+func (l *lowerer) valueBlocks(typeID air.TypeID, build func(sink blockSink) ([]string, error)) (loweredExpr, error) {
+	if l.isVoid(typeID) {
+		lines, err := build(discardSink)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		return loweredExpr{stmts: lines, expr: "undefined"}, nil
+	}
+	temp := l.nextTemp()
+	lines, err := build(assignSink(temp))
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return loweredExpr{stmts: append([]string{fmt.Sprintf("let %s;", temp)}, lines...), expr: temp}, nil
+}
+
+// ifChain renders `if (c0) {...} else if (c1) {...} else {...}`.
+func ifChain(conditions []string, bodies [][]string, otherwise []string, hasOtherwise bool) []string {
+	lines := []string{}
+	for i, condition := range conditions {
+		if i == 0 {
+			lines = append(lines, fmt.Sprintf("if (%s) {", condition))
+		} else {
+			lines[len(lines)-1] = fmt.Sprintf("} else if (%s) {", condition)
+		}
+		lines = append(lines, indent(bodies[i])...)
+		lines = append(lines, "}")
+	}
+	if hasOtherwise {
+		if len(conditions) == 0 {
+			return otherwise
+		}
+		lines[len(lines)-1] = "} else {"
+		lines = append(lines, indent(otherwise)...)
+		lines = append(lines, "}")
+	}
+	return lines
+}
+
+func (l *lowerer) functionRef(id air.FunctionID) (string, error) {
+	if id < 0 || int(id) >= len(l.program.Functions) {
+		return "", fmt.Errorf("invalid function %d", id)
+	}
+	name, ok := l.functions[id]
+	if !ok {
+		return "", fmt.Errorf("function %s is not emitted", l.program.Functions[id].Name)
+	}
+	fn := l.program.Functions[id]
+	if fn.Module == l.module {
+		return name, nil
+	}
+	l.imports[fn.Module] = true
+	return moduleAlias(fn.Module) + "." + name, nil
+}
+
+func (l *lowerer) globalRef(id air.GlobalID) (string, error) {
+	if id < 0 || int(id) >= len(l.program.Globals) {
+		return "", fmt.Errorf("invalid global %d", id)
+	}
+	global := l.program.Globals[id]
+	if global.Module == l.module {
+		return l.globals[id], nil
+	}
+	l.imports[global.Module] = true
+	return moduleAlias(global.Module) + "." + l.globals[id], nil
+}
+
+func (l *lowerer) lowerExpr(sc *scope, expr air.Expr) (loweredExpr, error) {
+	switch expr.Kind {
+	case air.ExprConstVoid:
+		return loweredExpr{expr: "undefined"}, nil
+	case air.ExprConstInt:
+		value, err := intLiteral(expr)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		return loweredExpr{expr: value}, nil
+	case air.ExprConstFloat:
+		return loweredExpr{expr: numberLiteral(expr.Float)}, nil
+	case air.ExprConstBool:
+		if expr.Bool {
+			return loweredExpr{expr: "true"}, nil
+		}
+		return loweredExpr{expr: "false"}, nil
+	case air.ExprConstStr:
+		return loweredExpr{expr: quote(expr.Str)}, nil
+	case air.ExprPanic:
+		if expr.Target == nil {
+			return loweredExpr{}, fmt.Errorf("panic missing message")
+		}
+		message, err := l.lowerExpr(sc, *expr.Target)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		return loweredExpr{stmts: append(message.stmts, fmt.Sprintf("$ard.panic(%s);", message.expr)), expr: "undefined"}, nil
+	case air.ExprLoadLocal:
+		return loweredExpr{expr: sc.load(expr.Local)}, nil
+	case air.ExprLoadGlobal:
+		name, err := l.globalRef(expr.Global)
+		return loweredExpr{expr: name}, err
+	case air.ExprFunctionRef:
+		name, err := l.functionRef(expr.Function)
+		return loweredExpr{expr: name}, err
+	case air.ExprCall:
+		callee, err := l.functionRef(expr.Function)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		stmts, _, args, err := l.lowerCallArgs(sc, nil, expr.Args)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		return loweredExpr{stmts: stmts, expr: fmt.Sprintf("%s(%s)", callee, strings.Join(args, ", "))}, nil
+	case air.ExprForeignCall:
+		return l.lowerForeignCall(sc, expr)
+	case air.ExprForeignMethodCall, air.ExprForeignMethodValue, air.ExprForeignFieldAccess,
+		air.ExprForeignStructInstance, air.ExprForeignValue, air.ExprForeignInterfaceUpcast,
+		air.ExprMatchForeignType:
+		return loweredExpr{}, fmt.Errorf("JavaScript target does not support Go interop (%s.%s)", expr.ForeignNamespace, expr.ForeignSymbol)
+	case air.ExprAsyncStart, air.ExprMakeChannel, air.ExprChannelSend, air.ExprChannelRecv,
+		air.ExprChannelClose, air.ExprChannelNarrow, air.ExprSelect:
+		return loweredExpr{}, fmt.Errorf("JavaScript target does not support fibers or channels")
+	case air.ExprDiscardingFunctionCoercion:
+		target, err := l.lowerRequiredTarget(sc, expr, "function coercion")
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		stmts, callee := l.spill(target)
+		return loweredExpr{stmts: stmts, expr: fmt.Sprintf("((...args) => { %s(...args); })", callee)}, nil
+	case air.ExprUnsafeCast:
+		return l.lowerUnsafeCast(sc, expr)
+	case air.ExprScalarConvert, air.ExprToAny, air.ExprToF64:
+		return l.lowerRequiredTarget(sc, expr, "conversion")
+	case air.ExprMutRef:
+		return l.lowerRequiredTarget(sc, expr, "mut reference")
+	case air.ExprUnsafeIsNil:
+		return l.mapTarget(sc, expr, "nil check", func(target string) string { return fmt.Sprintf("(%s == null)", target) })
+	case air.ExprStrFormat:
+		return l.lowerStrFormat(sc, expr)
+	case air.ExprInspect:
+		return l.lowerInspect(sc, expr)
+	case air.ExprMakeClosure:
+		return l.lowerMakeClosure(sc, expr)
+	case air.ExprCallClosure:
+		stmts, callee, args, err := l.lowerCallArgs(sc, expr.Target, expr.Args)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		if !isSimpleExpr(callee) {
+			callee = "(" + callee + ")"
+		}
+		return loweredExpr{stmts: stmts, expr: fmt.Sprintf("%s(%s)", callee, strings.Join(args, ", "))}, nil
+	case air.ExprUnionWrap:
+		return l.mapTarget(sc, expr, "union wrap", func(target string) string {
+			return fmt.Sprintf("$ard.union(%d, %s)", expr.Tag, target)
+		})
+	case air.ExprMatchUnion:
+		return l.lowerMatchUnion(sc, expr)
+	case air.ExprTraitUpcast:
+		return l.lowerTraitUpcast(sc, expr)
+	case air.ExprCallTrait:
+		return l.lowerCallTrait(sc, expr)
+	case air.ExprMakeList, air.ExprMakeFixedArray:
+		stmts, items, err := l.lowerOperands(sc, expr.Args...)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		for i := range items {
+			items[i] = l.storedValue(expr.Args[i], items[i])
+		}
+		return loweredExpr{stmts: stmts, expr: "[" + strings.Join(items, ", ") + "]"}, nil
+	case air.ExprListAt:
+		return l.targetCall(sc, expr, "list index", func(target string, args []string) string {
+			return fmt.Sprintf("%s[%s]", target, args[0])
+		}, 1)
+	case air.ExprListAtChecked:
+		return l.targetCall(sc, expr, "list at", runtimeCall("listAtChecked"), 1)
+	case air.ExprListPrepend:
+		return l.lowerListMutation(sc, expr, "unshift")
+	case air.ExprListPush:
+		return l.lowerListMutation(sc, expr, "push")
+	case air.ExprListSet:
+		if expr.Target == nil || len(expr.Args) != 2 {
+			return loweredExpr{}, fmt.Errorf("list set expects target and two args")
+		}
+		stmts, target, args, err := l.lowerCallArgs(sc, expr.Target, expr.Args)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		value := l.storedValue(expr.Args[1], args[1])
+		return loweredExpr{stmts: append(stmts, fmt.Sprintf("%s[%s] = %s;", target, args[0], value)), expr: "true"}, nil
+	case air.ExprListSize:
+		return l.mapTarget(sc, expr, "list size", func(target string) string { return target + ".length" })
+	case air.ExprListSort:
+		return l.targetStatement(sc, expr, "list sort", runtimeCall("listSort"), 1)
+	case air.ExprListSwap:
+		return l.targetStatement(sc, expr, "list swap", runtimeCall("listSwap"), 2)
+	case air.ExprMakeMap:
+		exprs := make([]air.Expr, 0, len(expr.Entries)*2)
+		for _, entry := range expr.Entries {
+			exprs = append(exprs, entry.Key, entry.Value)
+		}
+		stmts, lowered, err := l.lowerOperands(sc, exprs...)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		entries := make([]string, 0, len(expr.Entries))
+		for i := 0; i < len(lowered); i += 2 {
+			entries = append(entries, fmt.Sprintf("[%s, %s]", lowered[i], l.storedValue(exprs[i+1], lowered[i+1])))
+		}
+		return loweredExpr{stmts: stmts, expr: "new $ard.HashMap([" + strings.Join(entries, ", ") + "])"}, nil
+	case air.ExprMapKeys:
+		return l.targetCall(sc, expr, "map keys", runtimeCall("mapKeys"), 0)
+	case air.ExprMapSize:
+		return l.mapTarget(sc, expr, "map size", func(target string) string { return target + ".size" })
+	case air.ExprMapGet:
+		return l.targetCall(sc, expr, "map get", runtimeCall("mapGet"), 1)
+	case air.ExprMapSet:
+		if expr.Target == nil || len(expr.Args) != 2 {
+			return loweredExpr{}, fmt.Errorf("map set expects target and two args")
+		}
+		stmts, target, args, err := l.lowerCallArgs(sc, expr.Target, expr.Args)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		value := l.storedValue(expr.Args[1], args[1])
+		return loweredExpr{stmts: append(stmts, fmt.Sprintf("%s.set(%s, %s);", target, args[0], value)), expr: "undefined"}, nil
+	case air.ExprMapDelete:
+		return l.targetStatement(sc, expr, "map delete", func(target string, args []string) string {
+			return fmt.Sprintf("%s.delete(%s)", target, args[0])
+		}, 1)
+	case air.ExprMapHas:
+		return l.targetCall(sc, expr, "map has", func(target string, args []string) string {
+			return fmt.Sprintf("%s.has(%s)", target, args[0])
+		}, 1)
+	case air.ExprMapKeyAt:
+		return l.targetCall(sc, expr, "map key at", func(target string, args []string) string {
+			return fmt.Sprintf("$ard.mapKeys(%s)[%s]", target, args[0])
+		}, 1)
+	case air.ExprMapValueAt:
+		return l.targetCall(sc, expr, "map value at", func(target string, args []string) string {
+			return fmt.Sprintf("%s.get($ard.mapKeys(%s)[%s])", target, target, args[0])
+		}, 1)
+	case air.ExprMakeStruct:
+		return l.lowerMakeStruct(sc, expr)
+	case air.ExprGetField:
+		field, ok := l.field(targetType(expr), expr.Field)
+		if !ok {
+			return loweredExpr{}, fmt.Errorf("invalid field %d", expr.Field)
+		}
+		return l.mapTarget(sc, expr, "field access", func(target string) string { return propertyAccess(target, field.Name) })
+	case air.ExprIntAdd, air.ExprFloatAdd, air.ExprStrConcat:
+		return l.binary(sc, expr, "+")
+	case air.ExprIntSub, air.ExprFloatSub:
+		return l.binary(sc, expr, "-")
+	case air.ExprIntMul, air.ExprFloatMul:
+		return l.binary(sc, expr, "*")
+	case air.ExprFloatDiv:
+		return l.binary(sc, expr, "/")
+	case air.ExprIntDiv:
+		return l.binaryCall(sc, expr, "$ard.intDiv")
+	case air.ExprIntMod:
+		return l.binaryCall(sc, expr, "$ard.intMod")
+	case air.ExprToStr:
+		targetKind := l.kind(targetType(expr))
+		return l.mapTarget(sc, expr, "to_str", func(target string) string { return toStrExpr(targetKind, target) })
+	case air.ExprToInt:
+		if l.kind(targetType(expr)) == air.TypeFloat64 {
+			return l.mapTarget(sc, expr, "to_int", func(target string) string { return fmt.Sprintf("Math.trunc(%s)", target) })
+		}
+		return l.lowerRequiredTarget(sc, expr, "to_int")
+	case air.ExprStrAt:
+		return l.lowerStrAt(sc, expr)
+	case air.ExprStrBytes:
+		return l.targetCall(sc, expr, "str bytes", runtimeCall("strBytes"), 0)
+	case air.ExprStrRunes:
+		return l.targetCall(sc, expr, "str runes", runtimeCall("strRunes"), 0)
+	case air.ExprStrSize:
+		return l.targetCall(sc, expr, "str size", runtimeCall("strSize"), 0)
+	case air.ExprStrIsEmpty:
+		return l.mapTarget(sc, expr, "str is_empty", func(target string) string { return fmt.Sprintf("(%s.length === 0)", target) })
+	case air.ExprStrContains:
+		return l.targetCall(sc, expr, "str contains", methodCall("includes"), 1)
+	case air.ExprStrStartsWith:
+		return l.targetCall(sc, expr, "str starts_with", methodCall("startsWith"), 1)
+	case air.ExprStrEndsWith:
+		return l.targetCall(sc, expr, "str ends_with", methodCall("endsWith"), 1)
+	case air.ExprStrReplace:
+		return l.targetCall(sc, expr, "str replace", runtimeCall("strReplace"), 2)
+	case air.ExprStrReplaceAll:
+		return l.targetCall(sc, expr, "str replace_all", runtimeCall("strReplaceAll"), 2)
+	case air.ExprStrTrim:
+		return l.targetCall(sc, expr, "str trim", runtimeCall("strTrim"), 0)
+	case air.ExprStrTrimStart:
+		return l.targetCall(sc, expr, "str trim_start", runtimeCall("strTrimStart"), 0)
+	case air.ExprStrTrimEnd:
+		return l.targetCall(sc, expr, "str trim_end", runtimeCall("strTrimEnd"), 0)
+	case air.ExprStrSlice:
+		return l.targetCall(sc, expr, "str slice", runtimeCall("strSlice"), 2)
+	case air.ExprStrIndexOf:
+		return l.targetCall(sc, expr, "str index_of", runtimeCall("strIndexOf"), 1)
+	case air.ExprStrToUpper:
+		return l.targetCall(sc, expr, "str to_upper", runtimeCall("strToUpper"), 0)
+	case air.ExprStrToLower:
+		return l.targetCall(sc, expr, "str to_lower", runtimeCall("strToLower"), 0)
+	case air.ExprStrPadLeft:
+		return l.targetCall(sc, expr, "str pad_left", runtimeCall("strPadLeft"), 2)
+	case air.ExprStrPadRight:
+		return l.targetCall(sc, expr, "str pad_right", runtimeCall("strPadRight"), 2)
+	case air.ExprStrRepeat:
+		return l.targetCall(sc, expr, "str repeat", runtimeCall("strRepeat"), 1)
+	case air.ExprStrReverse:
+		return l.targetCall(sc, expr, "str reverse", runtimeCall("strReverse"), 0)
+	case air.ExprStrChars:
+		return l.targetCall(sc, expr, "str chars", runtimeCall("strChars"), 0)
+	case air.ExprStrGraphemes:
+		return l.targetCall(sc, expr, "str graphemes", runtimeCall("strGraphemes"), 0)
+	case air.ExprIntAbs:
+		return l.targetCall(sc, expr, "int abs", runtimeCall("intAbs"), 0)
+	case air.ExprIntPow:
+		return l.targetCall(sc, expr, "int pow", runtimeCall("intPow"), 1)
+	case air.ExprIntClamp:
+		return l.targetCall(sc, expr, "int clamp", runtimeCall("intClamp"), 2)
+	case air.ExprFloatRound:
+		return l.targetCall(sc, expr, "float round", runtimeCall("floatRound"), 0)
+	case air.ExprFloatFloor:
+		return l.mapTarget(sc, expr, "float floor", func(target string) string { return fmt.Sprintf("Math.floor(%s)", target) })
+	case air.ExprFloatCeil:
+		return l.mapTarget(sc, expr, "float ceil", func(target string) string { return fmt.Sprintf("Math.ceil(%s)", target) })
+	case air.ExprIntParse:
+		return l.targetCall(sc, expr, "int parse", runtimeCall("intParse"), 0)
+	case air.ExprFloatParse:
+		return l.targetCall(sc, expr, "float parse", runtimeCall("floatParse"), 0)
+	case air.ExprEq, air.ExprNotEq:
+		return l.lowerEquality(sc, expr)
+	case air.ExprLt:
+		return l.binary(sc, expr, "<")
+	case air.ExprLte:
+		return l.binary(sc, expr, "<=")
+	case air.ExprGt:
+		return l.binary(sc, expr, ">")
+	case air.ExprGte:
+		return l.binary(sc, expr, ">=")
+	case air.ExprAnd, air.ExprOr:
+		return l.lowerLogical(sc, expr)
+	case air.ExprNot:
+		return l.mapTarget(sc, expr, "not", func(target string) string { return "!" + wrap(target) })
+	case air.ExprNeg:
+		return l.mapTarget(sc, expr, "negation", func(target string) string { return "(-" + wrap(target) + ")" })
+	case air.ExprBlock, air.ExprUnsafeBlock:
+		return l.valueBlocks(expr.Type, func(sink blockSink) ([]string, error) {
+			lines, err := l.lowerBlock(sc, expr.Body, sink)
+			if err != nil {
+				return nil, err
+			}
+			return append([]string{"{"}, append(indent(lines), "}")...), nil
+		})
+	case air.ExprIf:
+		return l.lowerIf(sc, expr)
+	case air.ExprMakeResultOk:
+		return l.mapTarget(sc, expr, "ok", func(target string) string { return fmt.Sprintf("$ard.Result.ok(%s)", target) })
+	case air.ExprMakeResultErr:
+		return l.mapTarget(sc, expr, "err", func(target string) string { return fmt.Sprintf("$ard.Result.err(%s)", target) })
+	case air.ExprEnumVariant:
+		if info, ok := l.typeInfo(expr.Type); ok && info.HasPayload() {
+			// Enums with associated data are tagged values.
+			stmts, values, err := l.lowerOperands(sc, expr.Args...)
+			if err != nil {
+				return loweredExpr{}, err
+			}
+			for i := range values {
+				values[i] = l.storedValue(expr.Args[i], values[i])
+			}
+			return loweredExpr{stmts: stmts, expr: fmt.Sprintf("{ tag: %d, values: [%s] }", expr.Discriminant, strings.Join(values, ", "))}, nil
+		}
+		return loweredExpr{expr: fmt.Sprintf("%d", expr.Discriminant)}, nil
+	case air.ExprMatchEnum, air.ExprMatchInt, air.ExprMatchStr:
+		return l.lowerMatchValue(sc, expr)
+	case air.ExprMakeMaybeSome:
+		return l.mapTarget(sc, expr, "some", func(target string) string { return fmt.Sprintf("$ard.Maybe.some(%s)", target) })
+	case air.ExprMakeMaybeNone:
+		return loweredExpr{expr: "$ard.Maybe.none()"}, nil
+	case air.ExprMakeMaybeNew:
+		if len(expr.Args) == 1 {
+			return l.lowerExpr(sc, expr.Args[0])
+		}
+		if expr.Target != nil {
+			return l.lowerExpr(sc, *expr.Target)
+		}
+		return loweredExpr{expr: "$ard.Maybe.none()"}, nil
+	case air.ExprMakeError:
+		return l.mapTarget(sc, expr, "error constructor", func(target string) string { return fmt.Sprintf("$ard.makeError(%s)", target) })
+	case air.ExprWrapError:
+		return l.targetCall(sc, expr, "error wrap", runtimeCall("wrapError"), 1)
+	case air.ExprErrorCause:
+		return l.targetCall(sc, expr, "error cause", runtimeCall("errorCause"), 0)
+	case air.ExprMatchMaybe:
+		return l.lowerMatchMaybe(sc, expr)
+	case air.ExprMatchResult:
+		return l.lowerMatchResult(sc, expr)
+	case air.ExprMaybeExpect, air.ExprResultExpect:
+		return l.targetCall(sc, expr, "expect", methodCall("expect"), 1)
+	case air.ExprMaybeIsNone:
+		return l.targetCall(sc, expr, "is_none", methodCall("isNone"), 0)
+	case air.ExprMaybeIsSome:
+		return l.targetCall(sc, expr, "is_some", methodCall("isSome"), 0)
+	case air.ExprResultIsOk:
+		return l.targetCall(sc, expr, "is_ok", methodCall("isOk"), 0)
+	case air.ExprResultIsErr:
+		return l.targetCall(sc, expr, "is_err", methodCall("isErr"), 0)
+	case air.ExprMaybeOr, air.ExprResultOr:
+		return l.targetCall(sc, expr, "or", methodCall("or"), 1)
+	case air.ExprMaybeMap, air.ExprResultMap:
+		return l.targetCall(sc, expr, "map", methodCall("map"), 1)
+	case air.ExprResultMapErr:
+		return l.targetCall(sc, expr, "map_err", methodCall("mapErr"), 1)
+	case air.ExprMaybeAndThen, air.ExprResultAndThen:
+		return l.targetCall(sc, expr, "and_then", methodCall("andThen"), 1)
+	case air.ExprMaybeSet, air.ExprMaybeClear:
+		return l.lowerMaybeAssign(sc, expr)
+	case air.ExprTryResult, air.ExprTryMaybe:
+		return l.lowerTry(sc, expr)
+	default:
+		return loweredExpr{}, fmt.Errorf("unsupported expression kind %d", expr.Kind)
+	}
+}
+
+func targetType(expr air.Expr) air.TypeID {
+	if expr.Target == nil {
+		return air.NoType
+	}
+	return expr.Target.Type
+}
+
+func runtimeCall(name string) func(string, []string) string {
+	return func(target string, args []string) string {
+		return fmt.Sprintf("$ard.%s(%s)", name, strings.Join(append([]string{target}, args...), ", "))
+	}
+}
+
+func methodCall(name string) func(string, []string) string {
+	return func(target string, args []string) string {
+		return fmt.Sprintf("%s.%s(%s)", wrap(target), name, strings.Join(args, ", "))
+	}
+}
+
+func (l *lowerer) lowerRequiredTarget(sc *scope, expr air.Expr, what string) (loweredExpr, error) {
+	if expr.Target == nil {
+		return loweredExpr{}, fmt.Errorf("%s missing target", what)
+	}
+	return l.lowerExpr(sc, *expr.Target)
+}
+
+// lowerUnsafeCast checks the runtime shape of an Any value against the cast's
+// target type. JavaScript values carry less type information than Go values,
+// so only primitives, lists, and maps can be checked; a whole-number Float64
+// also passes as an Int.
+func (l *lowerer) lowerUnsafeCast(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if len(expr.TypeArgs) != 1 {
+		return loweredExpr{}, fmt.Errorf("unsafe::cast expects one target type, got %d", len(expr.TypeArgs))
+	}
+	var shape string
+	switch l.kind(expr.TypeArgs[0]) {
+	case air.TypeInt, air.TypeByte, air.TypeRune:
+		shape = "int"
+	case air.TypeFloat64:
+		shape = "float"
+	case air.TypeStr:
+		shape = "str"
+	case air.TypeBool:
+		shape = "bool"
+	case air.TypeList:
+		shape = "list"
+	case air.TypeMap:
+		shape = "map"
+	case air.TypeAny:
+		shape = "any"
+	default:
+		return loweredExpr{}, fmt.Errorf("JavaScript target cannot check unsafe::cast to %s", l.typeName(expr.TypeArgs[0]))
+	}
+	return l.mapTarget(sc, expr, "unsafe::cast", func(target string) string {
+		return fmt.Sprintf("$ard.unsafeCast(%s, %q)", target, shape)
+	})
+}
+
+func (l *lowerer) mapTarget(sc *scope, expr air.Expr, what string, render func(string) string) (loweredExpr, error) {
+	target, err := l.lowerRequiredTarget(sc, expr, what)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return loweredExpr{stmts: target.stmts, expr: render(target.expr)}, nil
+}
+
+func (l *lowerer) targetCall(sc *scope, expr air.Expr, what string, render func(string, []string) string, arity int) (loweredExpr, error) {
+	if expr.Target == nil || len(expr.Args) != arity {
+		return loweredExpr{}, fmt.Errorf("%s expects a target and %d args", what, arity)
+	}
+	stmts, target, args, err := l.lowerCallArgs(sc, expr.Target, expr.Args)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return loweredExpr{stmts: stmts, expr: render(target, args)}, nil
+}
+
+// targetStatement is targetCall for operations that produce Void.
+func (l *lowerer) targetStatement(sc *scope, expr air.Expr, what string, render func(string, []string) string, arity int) (loweredExpr, error) {
+	call, err := l.targetCall(sc, expr, what, render, arity)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return loweredExpr{stmts: append(call.stmts, call.expr+";"), expr: "undefined"}, nil
+}
+
+func (l *lowerer) lowerListMutation(sc *scope, expr air.Expr, method string) (loweredExpr, error) {
+	if expr.Target == nil || len(expr.Args) != 1 {
+		return loweredExpr{}, fmt.Errorf("list %s expects target and one arg", method)
+	}
+	stmts, target, args, err := l.lowerCallArgs(sc, expr.Target, expr.Args)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	value := l.storedValue(expr.Args[0], args[0])
+	return loweredExpr{stmts: stmts, expr: fmt.Sprintf("%s.%s(%s)", target, method, value)}, nil
+}
+
+func (l *lowerer) binary(sc *scope, expr air.Expr, op string) (loweredExpr, error) {
+	if expr.Left == nil || expr.Right == nil {
+		return loweredExpr{}, fmt.Errorf("binary expression missing operand")
+	}
+	stmts, operands, err := l.lowerOperands(sc, *expr.Left, *expr.Right)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return loweredExpr{stmts: stmts, expr: fmt.Sprintf("(%s %s %s)", operands[0], op, operands[1])}, nil
+}
+
+func (l *lowerer) binaryCall(sc *scope, expr air.Expr, callee string) (loweredExpr, error) {
+	if expr.Left == nil || expr.Right == nil {
+		return loweredExpr{}, fmt.Errorf("binary expression missing operand")
+	}
+	stmts, operands, err := l.lowerOperands(sc, *expr.Left, *expr.Right)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return loweredExpr{stmts: stmts, expr: fmt.Sprintf("%s(%s, %s)", callee, operands[0], operands[1])}, nil
+}
+
+func (l *lowerer) lowerEquality(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Left == nil || expr.Right == nil {
+		return loweredExpr{}, fmt.Errorf("equality missing operand")
+	}
+	if isPrimitiveKind(l.kind(expr.Left.Type)) {
+		op := "==="
+		if expr.Kind == air.ExprNotEq {
+			op = "!=="
+		}
+		return l.binary(sc, expr, op)
+	}
+	eq, err := l.binaryCall(sc, expr, "$ard.eq")
+	if err != nil || expr.Kind == air.ExprEq {
+		return eq, err
+	}
+	return loweredExpr{stmts: eq.stmts, expr: "!" + eq.expr}, nil
+}
+
+func isPrimitiveKind(kind air.TypeKind) bool {
+	switch kind {
+	case air.TypeVoid, air.TypeInt, air.TypeFloat64, air.TypeBool, air.TypeByte, air.TypeRune, air.TypeStr, air.TypeEnum, air.TypeScalar:
+		return true
+	default:
+		return false
+	}
+}
+
+// lowerLogical keeps && and || short-circuiting when the right operand needs
+// setup statements of its own.
+func (l *lowerer) lowerLogical(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Left == nil || expr.Right == nil {
+		return loweredExpr{}, fmt.Errorf("logical expression missing operand")
+	}
+	op := "&&"
+	if expr.Kind == air.ExprOr {
+		op = "||"
+	}
+	left, err := l.lowerExpr(sc, *expr.Left)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	right, err := l.lowerExpr(sc, *expr.Right)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	if len(right.stmts) == 0 {
+		return loweredExpr{stmts: left.stmts, expr: fmt.Sprintf("(%s %s %s)", left.expr, op, right.expr)}, nil
+	}
+	temp := l.nextTemp()
+	condition := temp
+	if expr.Kind == air.ExprOr {
+		condition = "!" + temp
+	}
+	stmts := append(left.stmts, fmt.Sprintf("let %s = %s;", temp, left.expr), fmt.Sprintf("if (%s) {", condition))
+	stmts = append(stmts, indent(right.stmts)...)
+	stmts = append(stmts, fmt.Sprintf("  %s = %s;", temp, right.expr), "}")
+	return loweredExpr{stmts: stmts, expr: temp}, nil
+}
+
+// lowerStrFormat calls the runtime formatter. Floats are tagged so they keep
+// their decimals, since JavaScript numbers do not tell them apart from Ints.
+func (l *lowerer) lowerStrFormat(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Target == nil {
+		return loweredExpr{}, fmt.Errorf("str format missing template")
+	}
+	values := append([]air.Expr{}, expr.Args...)
+	for _, entry := range expr.Entries {
+		values = append(values, entry.Value)
+	}
+	stmts, template, lowered, err := l.lowerCallArgs(sc, expr.Target, values)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	for i, value := range values {
+		if l.kind(value.Type) == air.TypeFloat64 {
+			lowered[i] = fmt.Sprintf("$ard.formatFloat(%s)", lowered[i])
+		}
+	}
+	named := "null"
+	if len(expr.Entries) > 0 {
+		fields := make([]string, len(expr.Entries))
+		for i, entry := range expr.Entries {
+			fields[i] = fmt.Sprintf("%s: %s", quote(entry.Key.Str), lowered[len(expr.Args)+i])
+		}
+		named = "{ " + strings.Join(fields, ", ") + " }"
+	}
+	args := "[" + strings.Join(lowered[:len(expr.Args)], ", ") + "]"
+	return loweredExpr{stmts: stmts, expr: fmt.Sprintf("$ard.format(%s, %s, %s)", template, args, named)}, nil
+}
+
+func toStrExpr(kind air.TypeKind, target string) string {
+	switch kind {
+	case air.TypeStr:
+		return target
+	case air.TypeFloat64:
+		return fmt.Sprintf("$ard.floatToStr(%s)", target)
+	case air.TypeRune:
+		return fmt.Sprintf("$ard.runeToStr(%s)", target)
+	case air.TypeInt, air.TypeBool, air.TypeByte:
+		return fmt.Sprintf("String(%s)", target)
+	default:
+		return fmt.Sprintf("$ard.toStr(%s)", target)
+	}
+}
+
+func (l *lowerer) lowerStrAt(sc *scope, expr air.Expr) (loweredExpr, error) {
+	info, ok := l.typeInfo(expr.Type)
+	if !ok || info.Kind != air.TypeMaybe {
+		return l.targetCall(sc, expr, "str at", func(target string, args []string) string {
+			return fmt.Sprintf("String.fromCharCode($ard.strBytes(%s)[%s])", target, args[0])
+		}, 1)
+	}
+	if l.kind(info.Elem) == air.TypeRune {
+		return l.targetCall(sc, expr, "str at", func(target string, args []string) string {
+			return fmt.Sprintf("$ard.strAt(%s, %s).map((char) => char.codePointAt(0))", target, args[0])
+		}, 1)
+	}
+	return l.targetCall(sc, expr, "str at", runtimeCall("strAt"), 1)
+}
+
+func (l *lowerer) lowerMakeStruct(sc *scope, expr air.Expr) (loweredExpr, error) {
+	fields := append([]air.StructFieldValue{}, expr.Fields...)
+	sort.SliceStable(fields, func(i, j int) bool { return fields[i].Index < fields[j].Index })
+	values := make([]air.Expr, len(fields))
+	for i, field := range fields {
+		values[i] = field.Value
+	}
+	stmts, lowered, err := l.lowerOperands(sc, values...)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		name := field.Name
+		value := lowered[i]
+		if info, ok := l.field(expr.Type, field.Index); ok {
+			name = info.Name
+			if !info.Mutable {
+				value = l.storedValue(field.Value, value)
+			}
+		}
+		parts[i] = fmt.Sprintf("%s: %s", propertyKey(name), value)
+	}
+	if len(parts) == 0 {
+		return loweredExpr{stmts: stmts, expr: "{}"}, nil
+	}
+	return loweredExpr{stmts: stmts, expr: "{ " + strings.Join(parts, ", ") + " }"}, nil
+}
+
+func (l *lowerer) lowerMakeClosure(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Function < 0 || int(expr.Function) >= len(l.program.Functions) {
+		return loweredExpr{}, fmt.Errorf("invalid closure function %d", expr.Function)
+	}
+	closureFn := l.program.Functions[expr.Function]
+	child := l.newScope(closureFn, sc)
+	for i, local := range expr.CaptureLocals {
+		if i >= len(closureFn.Captures) {
+			break
+		}
+		capture := closureFn.Captures[i].Local
+		child.names[capture] = sc.local(local)
+		child.declared[capture] = true
+	}
+	params := child.params()
+	body, err := l.lowerFunctionBody(child)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	lines := []string{fmt.Sprintf("((%s) => {", strings.Join(params, ", "))}
+	lines = append(lines, indent(body)...)
+	lines = append(lines, "})")
+	return loweredExpr{expr: strings.Join(lines, "\n")}, nil
+}
+
+func (l *lowerer) lowerTraitUpcast(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Impl < 0 || int(expr.Impl) >= len(l.program.Impls) {
+		return loweredExpr{}, fmt.Errorf("invalid impl %d", expr.Impl)
+	}
+	impl := l.program.Impls[expr.Impl]
+	methods := make([]string, len(impl.Methods))
+	for i, method := range impl.Methods {
+		ref, err := l.functionRef(method)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		methods[i] = ref
+	}
+	return l.mapTarget(sc, expr, "trait upcast", func(target string) string {
+		return fmt.Sprintf("$ard.traitObject(%s, [%s])", target, strings.Join(methods, ", "))
+	})
+}
+
+func (l *lowerer) lowerCallTrait(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Target == nil {
+		return loweredExpr{}, fmt.Errorf("trait call missing target")
+	}
+	if expr.Trait < 0 || int(expr.Trait) >= len(l.program.Traits) {
+		return loweredExpr{}, fmt.Errorf("invalid trait id %d", expr.Trait)
+	}
+	trait := l.program.Traits[expr.Trait]
+	if expr.Method < 0 || expr.Method >= len(trait.Methods) {
+		return loweredExpr{}, fmt.Errorf("invalid trait method %d for %s", expr.Method, trait.Name)
+	}
+	method := trait.Methods[expr.Method]
+	if l.kind(expr.Target.Type) != air.TypeTraitObject {
+		if trait.Name == "ToString" && method.Name == "to_str" {
+			kind := l.kind(expr.Target.Type)
+			return l.mapTarget(sc, expr, "to_str", func(target string) string { return toStrExpr(kind, target) })
+		}
+		return loweredExpr{}, fmt.Errorf("unsupported trait call %s.%s", trait.Name, method.Name)
+	}
+	stmts, target, args, err := l.lowerCallArgs(sc, expr.Target, expr.Args)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	callArgs := append([]string{fmt.Sprintf("%d", expr.Method)}, args...)
+	return loweredExpr{stmts: stmts, expr: fmt.Sprintf("%s.call(%s)", wrap(target), strings.Join(callArgs, ", "))}, nil
+}
+
+func (l *lowerer) lowerForeignCall(sc *scope, expr air.Expr) (loweredExpr, error) {
+	key := expr.ForeignNamespace + "." + expr.ForeignSymbol
+	if expr.ForeignTarget != "go" || !hostSymbols[key] {
+		return loweredExpr{}, fmt.Errorf("JavaScript target does not support Go function %s", key)
+	}
+	stmts, _, args, err := l.lowerCallArgs(sc, nil, expr.Args)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return loweredExpr{stmts: stmts, expr: fmt.Sprintf("$ard.host[%s](%s)", quote(key), strings.Join(args, ", "))}, nil
+}
+
+func (l *lowerer) lowerIf(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Condition == nil {
+		return loweredExpr{}, fmt.Errorf("if expression missing condition")
+	}
+	condition, err := l.lowerExpr(sc, *expr.Condition)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return l.valueBlocks(expr.Type, func(sink blockSink) ([]string, error) {
+		then, err := l.lowerBlock(sc, expr.Then, sink)
+		if err != nil {
+			return nil, err
+		}
+		otherwise, err := l.lowerBlock(sc, expr.Else, sink)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, condition.stmts...)
+		return append(lines, ifChain([]string{condition.expr}, [][]string{then}, otherwise, len(otherwise) > 0)...), nil
+	})
+}
+
+// lowerSubject evaluates a match subject once and returns the name that the
+// arms test against.
+func (l *lowerer) lowerSubject(sc *scope, expr air.Expr) ([]string, string, error) {
+	if expr.Target == nil {
+		return nil, "", fmt.Errorf("match missing subject")
+	}
+	subject, err := l.lowerExpr(sc, *expr.Target)
+	if err != nil {
+		return nil, "", err
+	}
+	stmts, name := l.spill(subject)
+	return stmts, name, nil
+}
+
+func (l *lowerer) lowerMatchValue(sc *scope, expr air.Expr) (loweredExpr, error) {
+	setup, subject, err := l.lowerSubject(sc, expr)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return l.valueBlocks(expr.Type, func(sink blockSink) ([]string, error) {
+		conditions := []string{}
+		bodies := [][]string{}
+		add := func(condition string, block air.Block) error {
+			body, err := l.lowerBlock(sc, block, sink)
+			if err != nil {
+				return err
+			}
+			conditions = append(conditions, condition)
+			bodies = append(bodies, body)
+			return nil
+		}
+		if info, ok := l.typeInfo(expr.Target.Type); ok && info.HasPayload() {
+			for _, c := range expr.EnumCases {
+				body, err := l.lowerPayloadBoundBlock(sc, c.Bindings, subject+".values", c.Body, sink)
+				if err != nil {
+					return nil, err
+				}
+				conditions = append(conditions, anyOf(subject+".tag", intCodes(c.Discriminant, c.Also)))
+				bodies = append(bodies, body)
+			}
+		} else {
+			for _, c := range expr.EnumCases {
+				if err := add(anyOf(subject, intCodes(c.Discriminant, c.Also)), c.Body); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for _, c := range expr.IntCases {
+			if err := add(anyOf(subject, intCodes(c.Value, c.Also)), c.Body); err != nil {
+				return nil, err
+			}
+		}
+		for _, c := range expr.RangeCases {
+			if err := add(fmt.Sprintf("%s >= %d && %s <= %d", subject, c.Start, subject, c.End), c.Body); err != nil {
+				return nil, err
+			}
+		}
+		for _, c := range expr.StrCases {
+			values := []string{quote(c.Value)}
+			for _, value := range c.Also {
+				values = append(values, quote(value))
+			}
+			if err := add(anyOf(subject, values), c.Body); err != nil {
+				return nil, err
+			}
+		}
+		otherwise, err := l.lowerBlock(sc, expr.CatchAll, sink)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, setup...)
+		return append(lines, ifChain(conditions, bodies, otherwise, len(otherwise) > 0)...), nil
+	})
+}
+
+// anyOf is the condition that subject equals one of values, the JavaScript
+// spellings of the values of a match arm.
+func anyOf(subject string, values []string) string {
+	conditions := make([]string, len(values))
+	for i, value := range values {
+		conditions[i] = fmt.Sprintf("%s === %s", subject, value)
+	}
+	return strings.Join(conditions, " || ")
+}
+
+// intCodes spells out value and the others that share its match arm.
+func intCodes(value int, also []int) []string {
+	codes := []string{fmt.Sprintf("%d", value)}
+	for _, other := range also {
+		codes = append(codes, fmt.Sprintf("%d", other))
+	}
+	return codes
+}
+
+// lowerBoundBlock lowers an arm body that first binds local to value.
+func (l *lowerer) lowerBoundBlock(sc *scope, local air.LocalID, value string, block air.Block, sink blockSink) ([]string, error) {
+	name := sc.local(local)
+	wasDeclared := sc.declared[local]
+	sc.declared[local] = true
+	body, err := l.lowerBlock(sc, block, sink)
+	if !wasDeclared {
+		delete(sc.declared, local)
+	}
+	if err != nil {
+		return nil, err
+	}
+	binding := fmt.Sprintf("const %s = %s;", name, value)
+	if wasDeclared {
+		binding = fmt.Sprintf("%s = %s;", name, value)
+	}
+	return append([]string{binding}, body...), nil
+}
+
+// lowerPayloadBoundBlock lowers an enum match arm body that first binds the
+// matched variant's payload values, read from the values array.
+func (l *lowerer) lowerPayloadBoundBlock(sc *scope, bindings []air.EnumPayloadBinding, values string, block air.Block, sink blockSink) ([]string, error) {
+	wasDeclared := make([]bool, len(bindings))
+	for i, binding := range bindings {
+		wasDeclared[i] = sc.declared[binding.Local]
+		sc.declared[binding.Local] = true
+	}
+	body, err := l.lowerBlock(sc, block, sink)
+	for i, binding := range bindings {
+		if !wasDeclared[i] {
+			delete(sc.declared, binding.Local)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]string, 0, len(bindings)+len(body))
+	for i, binding := range bindings {
+		keyword := "const "
+		if wasDeclared[i] {
+			keyword = ""
+		}
+		lines = append(lines, fmt.Sprintf("%s%s = %s[%d];", keyword, sc.local(binding.Local), values, binding.Index))
+	}
+	return append(lines, body...), nil
+}
+
+func (l *lowerer) lowerMatchUnion(sc *scope, expr air.Expr) (loweredExpr, error) {
+	setup, subject, err := l.lowerSubject(sc, expr)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return l.valueBlocks(expr.Type, func(sink blockSink) ([]string, error) {
+		conditions := []string{}
+		bodies := [][]string{}
+		for _, c := range expr.UnionCases {
+			body, err := l.lowerBoundBlock(sc, c.Local, subject+".value", c.Body, sink)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, fmt.Sprintf("%s.tag === %d", subject, c.Tag))
+			bodies = append(bodies, body)
+		}
+		otherwise, err := l.lowerBlock(sc, expr.CatchAll, sink)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, setup...)
+		return append(lines, ifChain(conditions, bodies, otherwise, len(otherwise) > 0)...), nil
+	})
+}
+
+func (l *lowerer) lowerMatchMaybe(sc *scope, expr air.Expr) (loweredExpr, error) {
+	setup, subject, err := l.lowerSubject(sc, expr)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return l.valueBlocks(expr.Type, func(sink blockSink) ([]string, error) {
+		some, err := l.lowerBoundBlock(sc, expr.SomeLocal, subject+".value", expr.Some, sink)
+		if err != nil {
+			return nil, err
+		}
+		none, err := l.lowerBlock(sc, expr.None, sink)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, setup...)
+		return append(lines, ifChain([]string{subject + ".some"}, [][]string{some}, none, len(none) > 0)...), nil
+	})
+}
+
+func (l *lowerer) lowerMatchResult(sc *scope, expr air.Expr) (loweredExpr, error) {
+	setup, subject, err := l.lowerSubject(sc, expr)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	return l.valueBlocks(expr.Type, func(sink blockSink) ([]string, error) {
+		ok, err := l.lowerBoundBlock(sc, expr.OkLocal, subject+".value", expr.Ok, sink)
+		if err != nil {
+			return nil, err
+		}
+		failed, err := l.lowerBoundBlock(sc, expr.ErrLocal, subject+".error", expr.Err, sink)
+		if err != nil {
+			return nil, err
+		}
+		lines := append([]string{}, setup...)
+		return append(lines, ifChain([]string{subject + ".ok"}, [][]string{ok}, failed, true)...), nil
+	})
+}
+
+func (l *lowerer) lowerMaybeAssign(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Target == nil {
+		return loweredExpr{}, fmt.Errorf("maybe assignment missing target")
+	}
+	target := *expr.Target
+	var place string
+	var stmts []string
+	switch target.Kind {
+	case air.ExprLoadLocal:
+		if sc.isWritebackLocal(target.Local) {
+			return loweredExpr{}, fmt.Errorf("JavaScript target cannot reassign mutable reference %s of type %s", sc.local(target.Local), l.typeName(target.Type))
+		}
+		place = sc.load(target.Local)
+	case air.ExprLoadGlobal:
+		if target.Global < 0 || int(target.Global) >= len(l.program.Globals) {
+			return loweredExpr{}, fmt.Errorf("invalid global %d", target.Global)
+		}
+		if l.program.Globals[target.Global].Module != l.module {
+			return loweredExpr{}, fmt.Errorf("JavaScript target cannot assign global %s from another module", l.program.Globals[target.Global].Name)
+		}
+		place = l.globals[target.Global]
+	case air.ExprGetField:
+		lowered, err := l.lowerExpr(sc, target)
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		stmts, place = lowered.stmts, lowered.expr
+	default:
+		return loweredExpr{}, fmt.Errorf("maybe assignment requires an addressable local, field, or global target")
+	}
+	value := "$ard.Maybe.none()"
+	if expr.Kind == air.ExprMaybeSet {
+		if len(expr.Args) != 1 {
+			return loweredExpr{}, fmt.Errorf("maybe set expects one arg")
+		}
+		arg, err := l.lowerExpr(sc, expr.Args[0])
+		if err != nil {
+			return loweredExpr{}, err
+		}
+		stmts = append(stmts, arg.stmts...)
+		value = fmt.Sprintf("$ard.Maybe.some(%s)", l.storedValue(expr.Args[0], arg.expr))
+	}
+	return loweredExpr{stmts: append(stmts, fmt.Sprintf("%s = %s;", place, value)), expr: "undefined"}, nil
+}
+
+// lowerTry unwraps a Result or Maybe, returning early from the enclosing
+// function on failure, or running the catch block and returning its value.
+func (l *lowerer) lowerTry(sc *scope, expr air.Expr) (loweredExpr, error) {
+	if expr.Target == nil {
+		return loweredExpr{}, fmt.Errorf("try missing target")
+	}
+	target, err := l.lowerExpr(sc, *expr.Target)
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	temp := l.nextTemp()
+	stmts := append(target.stmts, fmt.Sprintf("const %s = %s;", temp, target.expr))
+	failed := "!" + temp + ".some"
+	if expr.Kind == air.ExprTryResult {
+		failed = "!" + temp + ".ok"
+	}
+	var onFailure []string
+	switch {
+	case expr.HasCatch && expr.Kind == air.ExprTryResult:
+		onFailure, err = l.lowerBoundBlock(sc, expr.CatchLocal, temp+".error", expr.Catch, l.catchSink(sc))
+	case expr.HasCatch:
+		onFailure, err = l.lowerBlock(sc, expr.Catch, l.catchSink(sc))
+	case l.kind(sc.fn.Signature.Return) == air.TypeMaybe:
+		onFailure = []string{"return $ard.Maybe.none();"}
+	case expr.Kind == air.ExprTryResult:
+		onFailure = []string{fmt.Sprintf("return $ard.Result.err(%s.error);", temp)}
+	default:
+		return loweredExpr{}, fmt.Errorf("try on Maybe requires a Maybe return type or a catch block")
+	}
+	if err != nil {
+		return loweredExpr{}, err
+	}
+	if expr.HasCatch && l.isVoid(sc.fn.Signature.Return) {
+		onFailure = append(onFailure, "return;")
+	}
+	stmts = append(stmts, fmt.Sprintf("if (%s) {", failed))
+	stmts = append(stmts, indent(onFailure)...)
+	stmts = append(stmts, "}")
+	return loweredExpr{stmts: stmts, expr: temp + ".value"}, nil
+}
+
+// catchSink returns the catch block's value from the enclosing function.
+func (l *lowerer) catchSink(sc *scope) blockSink {
+	if l.isVoid(sc.fn.Signature.Return) {
+		return discardSink
+	}
+	return returnSink
+}
+
+var simpleExprPattern = regexp.MustCompile(`^(?:[A-Za-z_$][A-Za-z0-9_$]*(?:\.[A-Za-z_$][A-Za-z0-9_$]*)?|\(?-?[0-9][0-9A-Za-z_.+]*\)?|"(?:[^"\\]|\\.)*"|true|false|undefined)$`)
+
+// isSimpleExpr reports whether expr is a name or literal that can be
+// repeated or dropped without changing evaluation.
+func isSimpleExpr(expr string) bool {
+	return simpleExprPattern.MatchString(expr)
+}
+
+var literalExprPattern = regexp.MustCompile(`^(?:\(?-?[0-9][0-9A-Za-z_.+]*\)?|"(?:[^"\\]|\\.)*"|true|false|undefined)$`)
+
+func isLiteralExpr(expr string) bool {
+	return literalExprPattern.MatchString(expr)
+}
+
+// intLiteral lowers an Int constant. JavaScript numbers are exact only up to
+// 2^53, so Int::MAX is the largest safe integer and Int::MIN is -Int::MAX - 1,
+// and other literals must fit between them.
+func intLiteral(expr air.Expr) (string, error) {
+	switch expr.IntLimit {
+	case air.IntMax:
+		return "Number.MAX_SAFE_INTEGER", nil
+	case air.IntMin:
+		return "(-Number.MAX_SAFE_INTEGER - 1)", nil
+	}
+	value, err := strconv.ParseInt(strings.ReplaceAll(expr.Int, "_", ""), 10, 64)
+	if err != nil || value > maxSafeInt || value < -maxSafeInt-1 {
+		return "", fmt.Errorf("Int literal %s is outside the range JavaScript numbers hold exactly, %d to %d", expr.Int, -maxSafeInt-1, maxSafeInt)
+	}
+	return numberLiteral(expr.Int), nil
+}
+
+// maxSafeInt is Number.MAX_SAFE_INTEGER.
+const maxSafeInt = 1<<53 - 1
+
+func numberLiteral(value string) string {
+	value = strings.ReplaceAll(value, "_", "")
+	if strings.HasPrefix(value, "-") {
+		return "(" + value + ")"
+	}
+	return value
+}
+
+func wrap(expr string) string {
+	if isSimpleExpr(expr) || strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") && !strings.Contains(expr, "\n") {
+		return expr
+	}
+	return "(" + expr + ")"
+}
+
+func quote(value string) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(value)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+func indent(lines []string) []string {
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		for _, part := range strings.Split(line, "\n") {
+			if part == "" {
+				out = append(out, "")
+				continue
+			}
+			out = append(out, "  "+part)
+		}
+	}
+	return out
+}
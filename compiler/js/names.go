@@ -0,0 +1,81 @@
+package jstarget
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/akonwi/ard/air"
+)
+
+var jsReservedWords = map[string]bool{
+	"arguments": true, "await": true, "break": true, "case": true, "catch": true,
+	"class": true, "const": true, "continue": true, "debugger": true, "default": true,
+	"delete": true, "do": true, "else": true, "enum": true, "eval": true,
+	"export": true, "extends": true, "false": true, "finally": true, "for": true,
+	"function": true, "if": true, "implements": true, "import": true, "in": true,
+	"instanceof": true, "interface": true, "let": true, "new": true, "null": true,
+	"package": true, "private": true, "protected": true, "public": true, "return": true,
+	"static": true, "super": true, "switch": true, "this": true, "throw": true,
+	"true": true, "try": true, "typeof": true, "undefined": true, "var": true,
+	"void": true, "while": true, "with": true, "yield": true,
+}
+
+var jsIdentifierPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// jsName turns an Ard name into a valid JavaScript identifier. Generated
+// helpers all start with `$`, so sanitized names never collide with them.
+func jsName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	out := b.String()
+	if out == "" {
+		out = "v"
+	}
+	if out[0] >= '0' && out[0] <= '9' {
+		out = "_" + out
+	}
+	if jsReservedWords[out] {
+		out += "_"
+	}
+	return out
+}
+
+// uniqueName returns base, or base with a numeric suffix, whichever is not
+// yet in used, and records the result.
+func uniqueName(used map[string]bool, base string) string {
+	name := base
+	for i := 2; used[name]; i++ {
+		name = fmt.Sprintf("%s_%d", base, i)
+	}
+	used[name] = true
+	return name
+}
+
+// moduleFileName maps an Ard module path onto a flat file name inside the
+// output directory, e.g. `ard/list` becomes `ard_list.mjs`.
+func moduleFileName(module air.Module) string {
+	path := strings.TrimSuffix(module.Path, ".ard")
+	return jsName(path) + ".mjs"
+}
+
+func propertyAccess(target string, name string) string {
+	if jsIdentifierPattern.MatchString(name) {
+		return target + "." + name
+	}
+	return fmt.Sprintf("%s[%s]", target, quote(name))
+}
+
+func propertyKey(name string) string {
+	if jsIdentifierPattern.MatchString(name) {
+		return name
+	}
+	return quote(name)
+}
@@ -0,0 +1,71 @@
+package jstarget
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// runRuntimeScript runs a module that imports the runtime as `$ard` and
+// returns its stdout.
+func runRuntimeScript(t *testing.T, script string) string {
+	t.Helper()
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is not installed")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, runtimeFileName), runtimeSource, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	source := "import * as $ard from \"./" + runtimeFileName + "\";\n" + script
+	if err := os.WriteFile(filepath.Join(dir, "script.mjs"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := exec.Command(node, filepath.Join(dir, "script.mjs")).CombinedOutput()
+	if err != nil {
+		t.Fatalf("run script: %v\n%s", err, out)
+	}
+	return string(out)
+}
+
+func TestRuntimeToStrMatchesGoFormatting(t *testing.T) {
+	got := runRuntimeScript(t, `
+console.log($ard.toStr([1, [2, 3], new Map([["a", 1]]), { x: 1, y: "s" }, $ard.Maybe.none(), $ard.Result.err("bad"), $ard.union(1, 5)]));
+`)
+	if want := "[1 [2 3] map[a:1] {1 s} none bad 5]\n"; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
+
+func TestRuntimeValueUtilitiesHandleCyclicValues(t *testing.T) {
+	got := runRuntimeScript(t, `
+const a = { name: "a", next: null };
+a.next = a;
+const b = { name: "a", next: null };
+b.next = b;
+console.log($ard.toStr(a));
+console.log($ard.eq(a, b), $ard.eq(a, { name: "b", next: a }));
+`)
+	if want := "{a <cycle>}\ntrue false\n"; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
+
+func TestRuntimeValueUtilitiesHandleDeepValues(t *testing.T) {
+	got := runRuntimeScript(t, `
+const nest = () => {
+  let value = [];
+  for (let i = 0; i < 200000; i++) {
+    value = [value];
+  }
+  return value;
+};
+const left = nest();
+console.log($ard.toStr(left).length, $ard.eq(left, nest()));
+`)
+	if want := "400002 true\n"; got != want {
+		t.Fatalf("want %q, got %q", want, got)
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -39,6 +40,9 @@ type Engine struct {
 	goModHash  string
 	goPrimed   map[string]bool        // go import paths primed into the session
 	parseCache map[string]*parseEntry // content hash -> parse result
+	// lastParses holds each file's latest parse, which the next version of
+	// the file is reparsed from
+	lastParses map[string]lastParse // file path -> latest parse
 	checkCache map[string]*FileAnalysis
 	// insertion order for simple bounded eviction
 	parseOrder []string
@@ -59,6 +63,12 @@ type parseEntry struct {
 	errors  []parse.ParseError
 }
 
+// lastParse is a file's content and the full result of parsing it.
+type lastParse struct {
+	content []byte
+	result  parse.ParseResult
+}
+
 // FileAnalysis is the immutable result of analyzing one file. It retains
 // only what features consume — not the whole Checker — so the bounded cache
 // stays small.
@@ -84,6 +94,7 @@ func NewEngine(projectRoot string) *Engine {
 	return &Engine{
 		projectRoot: projectRoot,
 		parseCache:  map[string]*parseEntry{},
+		lastParses:  map[string]lastParse{},
 		checkCache:  map[string]*FileAnalysis{},
 	}
 }
@@ -195,7 +206,9 @@ func (e *Engine) goModSignature() string {
 
 // --- memoized parsing ---
 
-// parseFile parses source, memoized by content hash.
+// parseFile parses source, memoized by content hash. A file that changed
+// since it was last parsed is reparsed from that parse, so an edit only
+// parses the declarations it touched again.
 func (e *Engine) parseFile(content []byte, filePath string) *parseEntry {
 	key := hashBytes(content)
 	e.mu.Lock()
@@ -203,12 +216,20 @@ func (e *Engine) parseFile(content []byte, filePath string) *parseEntry {
 		e.mu.Unlock()
 		return entry
 	}
+	last, hasLast := e.lastParses[filePath]
 	e.mu.Unlock()
 
-	result := parse.Parse(content, filePath)
+	var result parse.ParseResult
+	if hasLast {
+		edit := parse.EditBetween(last.content, content)
+		result = parse.Reparse(last.result, []parse.Edit{edit}, content)
+	} else {
+		result = parse.Parse(content, filePath)
+	}
 	entry := &parseEntry{program: result.Program, errors: result.Errors}
 
 	e.mu.Lock()
+	e.lastParses[filePath] = lastParse{content: content, result: result}
 	if _, ok := e.parseCache[key]; !ok {
 		e.parseCache[key] = entry
 		e.parseOrder = append(e.parseOrder, key)
@@ -391,19 +412,6 @@ func (s *Snapshot) analyze(ctx context.Context, filePath string, cache bool) (an
 	if entry.program == nil {
 		return nil, fmt.Errorf("parse returned no program for %s", filePath)
 	}
-	if len(entry.errors) > 0 && cache {
-		// Parse errors block checking on the cached path; not cached in
-		// checkCache because the parse cache already makes this cheap.
-		// Ephemeral (tooling-patched) analyses continue: signature help and
-		// completion need best-effort checking of partial ASTs, and Analyze
-		// recovers checker panics.
-		return &FileAnalysis{
-			FilePath:    filePath,
-			Program:     entry.program,
-			ParseErrors: entry.errors,
-		}, nil
-	}
-
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -472,7 +480,10 @@ func (s *Snapshot) newModuleResolver(filePath string) (*checker.ModuleResolver,
 	return moduleResolver, relPath, nil
 }
 
-// check runs the checker for the file with snapshot overlays applied.
+// check runs the checker for the file with snapshot overlays applied. A file
+// with parse errors is still checked: the parser recovers at statement and
+// declaration boundaries, so hover, completion, and diagnostics keep working
+// in the declarations that parsed cleanly.
 func (s *Snapshot) check(filePath string, relPath string, program *parse.Program, parseErrors []parse.ParseError, moduleResolver *checker.ModuleResolver, sig string) (*FileAnalysis, error) {
 	projectInfo := moduleResolver.GetProjectInfo()
 	// Pre-scan this check's import closure for Go paths so the shared
@@ -493,7 +504,7 @@ func (s *Snapshot) check(filePath string, relPath string, program *parse.Program
 		FilePath:    filePath,
 		Program:     program,
 		ParseErrors: parseErrors,
-		Diagnostics: c.Diagnostics(),
+		Diagnostics: recoverableDiagnostics(program, parseErrors, relPath, slices.Concat(c.Diagnostics(), c.Warnings())),
 		Spans:       c.Spans(),
 		Checked:     module.Program(),
 		Module:      module,
@@ -501,6 +512,41 @@ func (s *Snapshot) check(filePath string, relPath string, program *parse.Program
 	}, nil
 }
 
+// recoverableDiagnostics drops the checker's diagnostics for this file that
+// fall in a top-level declaration with a parse error. Recovery leaves holes in
+// such a declaration, so what the checker says about it is noise; the parse
+// errors already point at the problem.
+func recoverableDiagnostics(program *parse.Program, parseErrors []parse.ParseError, relPath string, diags []checker.Diagnostic) []checker.Diagnostic {
+	if len(parseErrors) == 0 || len(program.Statements) == 0 {
+		return diags
+	}
+	starts := make([]int, len(program.Statements))
+	for i, stmt := range program.Statements {
+		starts[i] = stmt.GetLocation().Start.Row
+	}
+	// declaration returns the index of the declaration holding row. Errors at
+	// the end of the input carry no row and belong to the last declaration.
+	declaration := func(row int) int {
+		if row <= 0 {
+			return len(starts) - 1
+		}
+		return sort.Search(len(starts), func(i int) bool { return starts[i] > row }) - 1
+	}
+	broken := map[int]bool{}
+	for _, parseErr := range parseErrors {
+		broken[declaration(parseErr.Location.Start.Row)] = true
+	}
+	kept := make([]checker.Diagnostic, 0, len(diags))
+	for _, diag := range diags {
+		span := diag.Primary.Span
+		if span.FilePath == relPath && broken[declaration(span.Location.Start.Row)] {
+			continue
+		}
+		kept = append(kept, diag)
+	}
+	return kept
+}
+
 // signature computes a content signature over the file and its transitive
 // import closure, resolved through the real module resolver so dependency
 // aliases and package deps participate. Check results are reusable across
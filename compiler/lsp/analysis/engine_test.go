@@ -87,6 +87,38 @@ func TestOverlayChangeInvalidatesAnalysis(t *testing.T) {
 	}
 }
 
+func TestOverlayChangeReparsesOnlyTheEditedDeclaration(t *testing.T) {
+	root := writeProject(t, map[string]string{
+		"main.ard": "fn one() Int {\n  1\n}\n\nfn two() Int {\n  2\n}\n\nfn main() {\n}\n",
+	})
+	engine := NewEngine(root)
+	ws := NewWorkspace(engine)
+	path := filepath.Join(root, "main.ard")
+
+	fa1, err := ws.Snapshot().Analyze(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ws.SetOverlay(path, "fn one() Int {\n  1\n}\n\nfn two() Int {\n  let x = 2\n  x\n}\n\nfn main() {\n}\n")
+	fa2, err := ws.Snapshot().Analyze(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before, after := fa1.Program.Statements, fa2.Program.Statements
+	if len(after) != 3 {
+		t.Fatalf("got %d declarations, want 3", len(after))
+	}
+	if after[0] != before[0] {
+		t.Error("the declaration before the edit was parsed again")
+	}
+	if after[1] == before[1] {
+		t.Error("the edited declaration was not parsed again")
+	}
+	if row := after[2].GetLocation().Start.Row; row != 10 {
+		t.Errorf("main starts on line %d, want 10 after the added line", row)
+	}
+}
+
 func TestDependencyOverlayInvalidatesDependent(t *testing.T) {
 	root := writeProject(t, map[string]string{
 		"ard.toml": "name = \"proj\"\nard = \">= 0.1.0\"\n",
@@ -145,9 +177,9 @@ func TestUnrelatedOverlayKeepsMemoizedAnalysis(t *testing.T) {
 	}
 }
 
-func TestParseErrorsShortCircuitChecking(t *testing.T) {
+func TestParseErrorsStillCheckRecoverableDeclarations(t *testing.T) {
 	root := writeProject(t, map[string]string{
-		"main.ard": "fn main( {\n}\n",
+		"main.ard": "fn broken() Int {\n  let x: Str = 1 + * 2\n  x\n}\n\nfn main() {\n  let y: Str = 42\n}\n",
 	})
 	engine := NewEngine(root)
 	ws := NewWorkspace(engine)
@@ -155,11 +187,14 @@ func TestParseErrorsShortCircuitChecking(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	if len(fa.ParseErrors) == 0 {
-		t.Fatal("expected parse errors")
+	if len(fa.ParseErrors) != 1 {
+		t.Fatalf("parse errors = %v, want one", fa.ParseErrors)
+	}
+	if fa.Checked == nil {
+		t.Fatal("the checker should run on the declarations that parsed")
 	}
-	if fa.Checked != nil {
-		t.Fatal("checker should not run on parse errors")
+	if len(fa.Diagnostics) != 1 || fa.Diagnostics[0].Primary.Span.Location.Start.Row != 7 {
+		t.Fatalf("diagnostics = %v, want only the type error in main", fa.Diagnostics)
 	}
 }
 
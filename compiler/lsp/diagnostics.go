@@ -2,6 +2,7 @@ package lsp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -17,7 +18,32 @@ import (
 type diagnosticAnalyzer func(source string, filePath string, overlays map[string]string) ([]checker.Diagnostic, error)
 
 func formatSource(source string, filePath string) (string, error) {
-	formatted, err := formatter.Format([]byte(source), filePath)
+	return formatSourceFixingImports(source, filePath, false)
+}
+
+// formatSourceFixingImports formats source and, when fixImports is set, also
+// removes its unused imports.
+func formatSourceFixingImports(source string, filePath string, fixImports bool) (string, error) {
+	options, err := formatter.FindOptions(filePath)
+	if err != nil {
+		return source, err
+	}
+	options.FixImports = fixImports
+	formatted, err := formatter.Format([]byte(source), filePath, options)
+	if err != nil {
+		return source, err
+	}
+	return string(formatted), nil
+}
+
+// formatSourceRange formats the statements overlapping lines startLine
+// through endLine (1-based, inclusive), leaving the rest of source as is.
+func formatSourceRange(source string, filePath string, startLine int, endLine int) (string, error) {
+	options, err := formatter.FindOptions(filePath)
+	if err != nil {
+		return source, err
+	}
+	formatted, err := formatter.FormatRange([]byte(source), startLine, endLine, options)
 	if err != nil {
 		return source, err
 	}
@@ -44,6 +70,9 @@ func checkerDiagnosticsToLSP(
 			Source:   "ard",
 			Message:  checkerDiagnosticMessage(d),
 		}
+		if d.Code != "" {
+			lspDiag.Code = string(d.Code)
+		}
 		for _, secondary := range d.Secondary {
 			path := resolvePath(secondary.Span.FilePath)
 			lspDiag.RelatedInformation = append(lspDiag.RelatedInformation, protocol.DiagnosticRelatedInformation{
@@ -54,11 +83,77 @@ func checkerDiagnosticsToLSP(
 				Message: secondary.Message,
 			})
 		}
+		if fixes := quickFixesFor(d.Fixes, rangeFor, resolvePath); len(fixes) > 0 {
+			lspDiag.Data = fixes
+		}
 		result = append(result, lspDiag)
 	}
 	return result
 }
 
+// quickFix is a checker fix carried in a published diagnostic's Data field,
+// so a later textDocument/codeAction request can offer it without
+// re-checking the document.
+type quickFix struct {
+	Title   string                                       `json:"title"`
+	Changes map[protocol.DocumentURI][]protocol.TextEdit `json:"changes"`
+}
+
+func quickFixesFor(
+	fixes []checker.DiagnosticFix,
+	rangeFor func(string, parse.Location) protocol.Range,
+	resolvePath func(string) string,
+) []quickFix {
+	// rangeFor treats a location's end as inclusive, but only the start of the
+	// range it returns is used, so each edit endpoint is converted on its own.
+	position := func(path string, point parse.Point) protocol.Position {
+		return rangeFor(path, parse.Location{Start: point, End: point}).Start
+	}
+	result := make([]quickFix, 0, len(fixes))
+	for _, fix := range fixes {
+		changes := map[protocol.DocumentURI][]protocol.TextEdit{}
+		for _, edit := range fix.Edits {
+			path := resolvePath(edit.FilePath)
+			docURI := protocol.DocumentURI(uri.File(path))
+			changes[docURI] = append(changes[docURI], protocol.TextEdit{
+				Range:   protocol.Range{Start: position(path, edit.Start), End: position(path, edit.End)},
+				NewText: edit.NewText,
+			})
+		}
+		result = append(result, quickFix{Title: fix.Title, Changes: changes})
+	}
+	return result
+}
+
+// quickFixActions turns the fixes carried by the client's diagnostics back
+// into code actions.
+func quickFixActions(diagnostics []protocol.Diagnostic) []protocol.CodeAction {
+	var actions []protocol.CodeAction
+	for _, diagnostic := range diagnostics {
+		if diagnostic.Source != "ard" || diagnostic.Data == nil {
+			continue
+		}
+		data, err := json.Marshal(diagnostic.Data)
+		if err != nil {
+			continue
+		}
+		var fixes []quickFix
+		if err := json.Unmarshal(data, &fixes); err != nil {
+			continue
+		}
+		for _, fix := range fixes {
+			actions = append(actions, protocol.CodeAction{
+				Title:       fix.Title,
+				Kind:        protocol.QuickFix,
+				Diagnostics: []protocol.Diagnostic{diagnostic},
+				IsPreferred: len(fixes) == 1,
+				Edit:        &protocol.WorkspaceEdit{Changes: fix.Changes},
+			})
+		}
+	}
+	return actions
+}
+
 func checkerDiagnosticMessage(d checker.Diagnostic) string {
 	if d.Code == "" {
 		return d.Message
@@ -75,6 +170,9 @@ func checkerDiagnosticMessage(d checker.Diagnostic) string {
 			parts = append(parts, secondary.Message)
 		}
 	}
+	if d.Help != "" {
+		parts = append(parts, "help: "+d.Help)
+	}
 	return strings.Join(parts, ": ")
 }
 
@@ -184,6 +282,7 @@ func (s *Server) publishDiagnostics(ctx context.Context, docURI uri.URI) {
 		return
 	}
 	if err != nil {
+		fmt.Fprintf(os.Stderr, "ard-lsp analysis failed for %s: %v\n", docURI, err)
 		// If we can't analyze, publish the error as a diagnostic so stale diagnostics
 		// are replaced instead of lingering until the server is restarted.
 		s.sendDiagnostics(ctx, docURI, doc.Version, []protocol.Diagnostic{analysisErrorDiagnostic(err)})
@@ -228,11 +327,11 @@ func (s *Server) analyzeDiagnostics(doc *Doc, docs []Doc) (diagnostics []checker
 		return nil, err
 	}
 	if len(fa.ParseErrors) > 0 {
-		diags := make([]checker.Diagnostic, 0, len(fa.ParseErrors))
+		diags := make([]checker.Diagnostic, 0, len(fa.ParseErrors)+len(fa.Diagnostics))
 		for _, perr := range fa.ParseErrors {
 			diags = append(diags, checker.NewDiagnostic(checker.Error, perr.Message, filePath, perr.Location))
 		}
-		return diags, nil
+		return append(diags, fa.Diagnostics...), nil
 	}
 	return fa.Diagnostics, nil
 }
@@ -563,3 +563,67 @@ func TestPublishDiagnosticsEnginePathTypeErrors(t *testing.T) {
 		t.Fatal("expected type-error diagnostics from engine path")
 	}
 }
+
+func TestDiagnosticFixesAreOfferedAsQuickFixes(t *testing.T) {
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "ard.toml"), []byte("name = \"app\"\nard = \">= 0.1.0\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mainPath := filepath.Join(root, "main.ard")
+	source := "fn label(ok: Bool) Str {\n  match ok {\n    true => \"yes\",\n  }\n}\n"
+	if err := os.WriteFile(mainPath, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer()
+	docURI := uri.File(mainPath)
+	server.cache.Open(docURI, "ard", 1, source)
+	doc := server.cache.Get(docURI)
+	diagnostics, err := server.analyzeDiagnostics(doc, server.cache.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	published := server.checkerDiagnosticsToLSP(diagnostics, docURI)
+	if len(published) == 0 {
+		t.Fatal("expected a non-exhaustive match diagnostic")
+	}
+
+	// Send the diagnostic back the way a client would, through JSON.
+	req, err := jsonrpc2.NewCall(jsonrpc2.NewNumberID(1), protocol.MethodTextDocumentCodeAction, protocol.CodeActionParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: docURI},
+		Range:        published[0].Range,
+		Context: protocol.CodeActionContext{
+			Diagnostics: published,
+			Only:        []protocol.CodeActionKind{protocol.QuickFix},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []protocol.CodeAction
+	reply := jsonrpc2.Replier(func(ctx context.Context, result interface{}, err error) error {
+		if err != nil {
+			return err
+		}
+		actions = result.([]protocol.CodeAction)
+		return nil
+	})
+	if err := server.handleCodeAction(context.Background(), reply, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(actions) != 1 {
+		t.Fatalf("actions = %#v, want one quick fix", actions)
+	}
+	action := actions[0]
+	if action.Kind != protocol.QuickFix || action.Title != "Add missing match arm `false`" {
+		t.Fatalf("action = %q (%s)", action.Title, action.Kind)
+	}
+	edits := action.Edit.Changes[protocol.DocumentURI(docURI)]
+	want := []protocol.TextEdit{{
+		Range:   protocol.Range{Start: protocol.Position{Line: 3, Character: 2}, End: protocol.Position{Line: 3, Character: 2}},
+		NewText: "  false => panic(\"TODO\"),\n  ",
+	}}
+	if len(edits) != 1 || edits[0] != want[0] {
+		t.Fatalf("edits = %#v, want %#v", edits, want)
+	}
+}
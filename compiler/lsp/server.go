@@ -284,6 +284,7 @@ func (s *Server) registerHandlers() {
 	s.handlers[protocol.MethodTextDocumentDocumentSymbol] = s.handleDocumentSymbol
 	s.handlers[protocol.MethodTextDocumentCompletion] = s.handleCompletion
 	s.handlers[protocol.MethodTextDocumentFormatting] = s.handleFormatting
+	s.handlers[protocol.MethodTextDocumentRangeFormatting] = s.handleRangeFormatting
 	s.handlers[protocol.MethodTextDocumentCodeAction] = s.handleCodeAction
 	s.handlers[protocol.MethodTextDocumentSignatureHelp] = s.handleSignatureHelp
 	s.handlers[protocol.MethodTextDocumentDocumentHighlight] = s.handleDocumentHighlight
@@ -325,10 +326,11 @@ func (s *Server) handleInitialize(ctx context.Context, reply jsonrpc2.Replier, r
 				TriggerCharacters:   []string{"(", ",", ":"},
 				RetriggerCharacters: []string{",", ":"},
 			},
-			DocumentHighlightProvider:  true,
-			DocumentFormattingProvider: true,
-			CodeActionProvider:         true,
-			RenameProvider:             &protocol.RenameOptions{PrepareProvider: true},
+			DocumentHighlightProvider:       true,
+			DocumentFormattingProvider:      true,
+			DocumentRangeFormattingProvider: true,
+			CodeActionProvider:              true,
+			RenameProvider:                  &protocol.RenameOptions{PrepareProvider: true},
 		},
 		ServerInfo: &protocol.ServerInfo{
 			Name:    "ard-lsp",
@@ -657,36 +659,76 @@ func (s *Server) handleFormatting(ctx context.Context, reply jsonrpc2.Replier, r
 	return reply(ctx, []protocol.TextEdit{fullDocumentEdit(doc.Text, string(formatted))}, nil)
 }
 
+// handleRangeFormatting formats the statements overlapping the requested
+// range, which editors also send for format-on-paste.
+func (s *Server) handleRangeFormatting(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
+	var params protocol.DocumentRangeFormattingParams
+	if err := json.Unmarshal(req.Params(), &params); err != nil {
+		return reply(ctx, nil, fmt.Errorf("%s: %w", jsonrpc2.ErrParse, err))
+	}
+
+	doc := s.cache.Get(params.TextDocument.URI)
+	if doc == nil {
+		return reply(ctx, []protocol.TextEdit{}, nil)
+	}
+
+	filePath, ok := docFilePath(doc)
+	if !ok {
+		return reply(ctx, []protocol.TextEdit{}, nil)
+	}
+	// LSP lines are 0-based, and a selection ending at the start of a line
+	// does not include that line.
+	startLine := int(params.Range.Start.Line) + 1
+	endLine := int(params.Range.End.Line) + 1
+	if params.Range.End.Character == 0 && endLine > startLine {
+		endLine--
+	}
+	formatted, err := formatSourceRange(doc.Text, filePath, startLine, endLine)
+	if err != nil || formatted == doc.Text {
+		return reply(ctx, []protocol.TextEdit{}, nil)
+	}
+
+	return reply(ctx, []protocol.TextEdit{fullDocumentEdit(doc.Text, formatted)}, nil)
+}
+
 func (s *Server) handleCodeAction(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
 	var params protocol.CodeActionParams
 	if err := json.Unmarshal(req.Params(), &params); err != nil {
 		return reply(ctx, nil, fmt.Errorf("%s: %w", jsonrpc2.ErrParse, err))
 	}
 
+	wantQuickFixes, wantOrganizeImports := true, true
 	if len(params.Context.Only) > 0 {
-		allowed := false
+		wantQuickFixes, wantOrganizeImports = false, false
 		for _, kind := range params.Context.Only {
-			if kind == protocol.Source || kind == protocol.SourceOrganizeImports {
-				allowed = true
-				break
+			switch kind {
+			case protocol.QuickFix:
+				wantQuickFixes = true
+			case protocol.Source, protocol.SourceOrganizeImports:
+				wantOrganizeImports = true
 			}
 		}
-		if !allowed {
-			return reply(ctx, []protocol.CodeAction{}, nil)
-		}
+	}
+
+	actions := []protocol.CodeAction{}
+	if wantQuickFixes {
+		actions = append(actions, quickFixActions(params.Context.Diagnostics)...)
+	}
+	if !wantOrganizeImports {
+		return reply(ctx, actions, nil)
 	}
 
 	doc := s.cache.Get(params.TextDocument.URI)
 	if doc == nil {
-		return reply(ctx, []protocol.CodeAction{}, nil)
+		return reply(ctx, actions, nil)
 	}
 	filePath, ok := docFilePath(doc)
 	if !ok {
-		return reply(ctx, []protocol.CodeAction{}, nil)
+		return reply(ctx, actions, nil)
 	}
-	formatted, err := formatSource(doc.Text, filePath)
+	formatted, err := formatSourceFixingImports(doc.Text, filePath, true)
 	if err != nil || formatted == doc.Text {
-		return reply(ctx, []protocol.CodeAction{}, nil)
+		return reply(ctx, actions, nil)
 	}
 
 	action := protocol.CodeAction{
@@ -697,7 +739,7 @@ func (s *Server) handleCodeAction(ctx context.Context, reply jsonrpc2.Replier, r
 			params.TextDocument.URI: {fullDocumentEdit(doc.Text, string(formatted))},
 		}},
 	}
-	return reply(ctx, []protocol.CodeAction{action}, nil)
+	return reply(ctx, append(actions, action), nil)
 }
 
 func (s *Server) handleSignatureHelp(ctx context.Context, reply jsonrpc2.Replier, req jsonrpc2.Request) error {
@@ -55,6 +55,7 @@ func TestServerInitializes(t *testing.T) {
 		"textDocument/documentSymbol",
 		"textDocument/completion",
 		"textDocument/formatting",
+		"textDocument/rangeFormatting",
 		"textDocument/codeAction",
 		"textDocument/signatureHelp",
 		"textDocument/documentHighlight",
@@ -792,6 +793,44 @@ func TestFormattingHandler(t *testing.T) {
 	}
 }
 
+func TestRangeFormattingHandler(t *testing.T) {
+	server := NewServer()
+	docURI := uri.New("file:///test.ard")
+	server.cache.Open(docURI, "ard", 1, "let   a  =  1\nlet   b  =  2\nlet   c  =  3\n")
+
+	req, err := jsonrpc2.NewCall(jsonrpc2.NewNumberID(1), protocol.MethodTextDocumentRangeFormatting, protocol.DocumentRangeFormattingParams{
+		TextDocument: protocol.TextDocumentIdentifier{URI: docURI},
+		Range: protocol.Range{
+			Start: protocol.Position{Line: 1, Character: 0},
+			End:   protocol.Position{Line: 2, Character: 0},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var edits []protocol.TextEdit
+	reply := jsonrpc2.Replier(func(ctx context.Context, result interface{}, err error) error {
+		if err != nil {
+			return err
+		}
+		var ok bool
+		edits, ok = result.([]protocol.TextEdit)
+		if !ok {
+			t.Fatalf("result = %T, want []protocol.TextEdit", result)
+		}
+		return nil
+	})
+	if err := server.handleRangeFormatting(context.Background(), reply, req); err != nil {
+		t.Fatal(err)
+	}
+	if len(edits) != 1 {
+		t.Fatalf("expected 1 edit, got %d", len(edits))
+	}
+	if want := "let   a  =  1\nlet b = 2\nlet   c  =  3\n"; edits[0].NewText != want {
+		t.Fatalf("formatted = %q, want %q", edits[0].NewText, want)
+	}
+}
+
 func requireDefinition(t *testing.T, source string, filePath string, line uint32, char uint32) protocol.Location {
 	t.Helper()
 	srv, docURI := spanServer(t, source, filePath)
@@ -1900,13 +1939,14 @@ fn main() {
   }
 }
 `
-	// Sweep every position; hover must never panic and may return nil.
+	// Sweep every position; hover must never panic and may return nil. One
+	// server serves the sweep, as in an editor, so the file is checked once.
+	srv, docURI := spanServer(t, source, "test.ard")
 	lines := strings.Split(source, "\n")
 	for row, line := range lines {
 		for col := 0; col <= len(line); col++ {
 			pt := protocol.Position{Line: uint32(row), Character: uint32(col)}
-			info := spanHoverAt(t, source, "test.ard", pt)
-			_ = info
+			_ = srv.hoverFromSpans(context.Background(), docURI, pt)
 		}
 	}
 }
@@ -76,6 +76,16 @@ func renderEnumDecl(decl *parse.EnumDefinition) string {
 	for _, variant := range decl.Variants {
 		b.WriteString("  ")
 		b.WriteString(variant.Name)
+		if len(variant.Payload) > 0 {
+			b.WriteString("(")
+			for i, t := range variant.Payload {
+				if i > 0 {
+					b.WriteString(", ")
+				}
+				b.WriteString(typeDeclString(t))
+			}
+			b.WriteString(")")
+		}
 		b.WriteString(",\n")
 	}
 	b.WriteString("}")
@@ -150,6 +160,15 @@ func typeDeclString(t parse.DeclaredType) string {
 		}
 	case *parse.ResultType:
 		s = typeDeclString(tt.Val) + "!" + typeDeclString(tt.Err)
+	case *parse.UnionType:
+		members := make([]string, len(tt.Members))
+		for i, member := range tt.Members {
+			members[i] = typeDeclString(member)
+		}
+		s = strings.Join(members, " | ")
+		if t.IsNullable() {
+			s = "(" + s + ")"
+		}
 	case *parse.CustomType:
 		s = t.GetName()
 		if len(tt.TypeArgs) > 0 {
@@ -3,12 +3,20 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -18,6 +26,7 @@ import (
 	"github.com/akonwi/ard/formatter"
 	"github.com/akonwi/ard/frontend"
 	gotarget "github.com/akonwi/ard/go"
+	jstarget "github.com/akonwi/ard/js"
 	"github.com/akonwi/ard/lsp"
 	"github.com/akonwi/ard/parse"
 	"github.com/akonwi/ard/version"
@@ -36,64 +45,113 @@ func main() {
 	case "version":
 		fmt.Println(version.Get())
 		os.Exit(0)
+	case "explain":
+		if err := explain(os.Stdout, os.Args[2:]); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		os.Exit(0)
 	case "check":
 		{
-			if len(os.Args) < 3 {
-				fmt.Println("Expected filepath argument")
+			inputPath, showTypes, options, err := parseCheckArgs(os.Args[2:])
+			if err != nil {
+				fmt.Println(err)
 				os.Exit(1)
 			}
 
-			inputPath := os.Args[2]
-			if !check(inputPath) {
+			if showTypes {
+				if err := printLineTypes(os.Stdout, inputPath); err != nil {
+					os.Exit(1)
+				}
+				os.Exit(0)
+			}
+			if !check(inputPath, options) {
 				os.Exit(1)
 			}
 
-			fmt.Println("✅ No errors found")
+			if !options.JSON {
+				fmt.Println("✅ No errors found")
+			}
 			os.Exit(0)
 		}
 	case "run":
 		{
-			inputPath, err := parseRunArgs(os.Args[2:])
+			inputPath, programArgs, limits, err := parseRunArgs(os.Args[2:])
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
+			if isBuiltBinary(inputPath) {
+				exitWithRunError(gotarget.RunBinaryWithLimits(inputPath, programArgs, limits))
+				os.Exit(0)
+			}
 			profile := newPipelineProfile("run go")
 			defer profile.Print()
-			var loaded *frontend.LoadResult
-			if err := profile.Time("frontend.load_module", func() error {
-				var loadErr error
-				loaded, loadErr = frontend.LoadModule(inputPath)
-				return loadErr
-			}); err != nil {
-				os.Exit(1)
-			}
 			var program *air.Program
-			if err := profile.Time("air.lower", func() error {
-				var lowerErr error
-				program, lowerErr = air.Lower(loaded.Module)
-				return lowerErr
-			}); err != nil {
-				fmt.Println(err)
-				os.Exit(1)
+			var projectInfo *checker.ProjectInfo
+			if filepath.Ext(inputPath) == airExtension {
+				if err := profile.Time("air.load", func() error {
+					var loadErr error
+					program, loadErr = loadAIRProgram(inputPath)
+					return loadErr
+				}); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+			} else {
+				var loaded *frontend.LoadResult
+				if err := profile.Time("frontend.load_module", func() error {
+					var loadErr error
+					loaded, loadErr = frontend.LoadModule(inputPath)
+					return loadErr
+				}); err != nil {
+					os.Exit(1)
+				}
+				if err := profile.Time("air.lower", func() error {
+					var lowerErr error
+					program, lowerErr = air.Lower(loaded.Module)
+					return lowerErr
+				}); err != nil {
+					fmt.Println(err)
+					os.Exit(1)
+				}
+				projectInfo = loaded.ProjectInfo
 			}
 			if err := validateEntrypointSignature(profile, program); err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
-			if err := gotarget.RunProgram(program, os.Args, loaded.ProjectInfo); err != nil {
+			runArgs := append([]string{os.Args[0], "run", inputPath}, programArgs...)
+			exitWithRunError(gotarget.RunProgramWithLimits(program, runArgs, limits, projectInfo))
+		}
+	case "inspect":
+		{
+			if len(os.Args) != 3 {
+				fmt.Println("expected the path of a built program")
+				os.Exit(1)
+			}
+			if err := inspectArtifact(os.Stdout, os.Args[2]); err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
 		}
 	case "build":
 		{
-			inputPath, outputPath, err := parseBuildArgs(os.Args[2:])
+			inputPath, outputPath, target, release, err := parseBuildArgs(os.Args[2:])
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
-			if _, err := buildGoBinary(inputPath, outputPath); err != nil {
+			build := buildGoBinary
+			switch target {
+			case buildTargetJS:
+				build = buildJSProgram
+			case buildTargetWasm:
+				build = buildWasmProgram
+			case buildTargetAIR:
+				build = buildAIRProgram
+			}
+			if _, err := build(inputPath, outputPath, air.LowerOptions{Release: release}); err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
@@ -110,6 +168,23 @@ func main() {
 			}
 			os.Exit(0)
 		}
+	case "bench":
+		{
+			opts, err := parseBenchArgs(os.Args[2:])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			ok, err := runBench(os.Stdout, opts)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if !ok {
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 	case "add":
 		{
 			if err := runAddCommand(os.Args[2:]); err != nil {
@@ -126,6 +201,14 @@ func main() {
 			}
 			os.Exit(0)
 		}
+	case "fetch":
+		{
+			if err := runDepsCommand(append([]string{"fetch"}, os.Args[2:]...)); err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
 	case "deps":
 		{
 			if err := runDepsCommand(os.Args[2:]); err != nil {
@@ -134,6 +217,56 @@ func main() {
 			}
 			os.Exit(0)
 		}
+	case "migrate":
+		{
+			inputPath, checkOnly, overrides, err := parseFormatArgs(os.Args[2:])
+			if err == nil && overrides.MaxWidth > 0 {
+				err = fmt.Errorf("unknown flag: --width")
+			}
+			if err == nil && overrides.FixImports {
+				err = fmt.Errorf("unknown flag: --fix-imports")
+			}
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			migratedPaths, err := migratePath(inputPath, checkOnly)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			if checkOnly && len(migratedPaths) > 0 {
+				fmt.Println("files with deprecated syntax:")
+				for _, migratedPath := range migratedPaths {
+					fmt.Println(migratedPath)
+				}
+				os.Exit(1)
+			}
+			os.Exit(0)
+		}
+	case "rename":
+		{
+			inputPath, at, newName, err := parseRenameArgs(os.Args[2:])
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			result, err := frontend.Rename(inputPath, at, newName)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			paths, err := result.Write()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Printf("Renamed %s to %s in:\n", result.OldName, result.NewName)
+			for _, path := range paths {
+				fmt.Println(displayPath(path))
+			}
+			os.Exit(0)
+		}
 	case "lsp":
 		{
 			ctx := context.Background()
@@ -145,12 +278,12 @@ func main() {
 		}
 	case "format":
 		{
-			inputPath, checkOnly, err := parseFormatArgs(os.Args[2:])
+			inputPath, checkOnly, overrides, err := parseFormatArgs(os.Args[2:])
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
 			}
-			changedPaths, err := formatPath(inputPath, checkOnly)
+			changedPaths, err := formatPath(inputPath, checkOnly, overrides)
 			if err != nil {
 				fmt.Println(err)
 				os.Exit(1)
@@ -181,15 +314,33 @@ func printUsage() {
 	fmt.Print(`Usage: ard <command> [args]
 
 Commands:
-  check <file.ard>                  Type-check a program
-  run <file.ard>                    Run a program
-  build <file.ard> [--out <path>]    Build a program
+  check <file.ard> [--types] [--strict] [--json]
+                                    Type-check a program (--types prints inferred types per line,
+                                    --strict warns about unused and unreachable code,
+                                    --json prints diagnostics and their fixes as JSON)
+  run [--sandbox] [--allow-read[=<dir>]] [--allow-write[=<dir>]] [--allow-net] [--allow-env] [--allow-run] <file.ard|file.air|binary>
+                                    Run a program (any of these flags sandboxes it, granting only
+                                    the listed capabilities)
+  build <file.ard> [--out <path>] [--target go|js|wasm|air] [--release]
+                                    Build a program (js and wasm write a directory; air writes
+                                    a file that run executes with the same ard release)
   test [path] [--filter <pattern>]   Run Ard tests
+  bench <file.ard> [--runs <n>] [--json <path>] [--compare <baseline.json>] [--threshold <percent>]
+                                    Time a program and compare against a baseline
   add <git-source@ref> [as alias]    Add or update a Git dependency and lock it
   remove <alias>                     Remove a direct dependency
-  deps fetch                         Restore locked Git dependencies into the cache
+  fetch, deps fetch                  Restore locked Git dependencies into the cache
   deps verify                        Verify cached dependencies against ard.lock
-  format [--check] <path>            Format Ard source
+  format [--check] [--width <n>] [--fix-imports] <path>
+                                    Format Ard source (ard.toml [format] sets defaults)
+  migrate [--check] <path>           Rewrite deprecated syntax for the current language version
+  rename <file.ard>:<line>:<col> <new-name>
+                                    Rename the declaration at a position and every reference to it
+                                    (the program must still check cleanly afterwards)
+  inspect <program>                  Print what a program built with --target go or air records:
+                                    compiler version, modules, entry point, size, and checksum
+  explain [code]                     Describe a diagnostic code with an example and its fix
+                                    (lists the explained codes when no code is given)
   lsp                                Start the language server
   version                            Print compiler version
 `)
@@ -632,11 +783,134 @@ func dependencyManifestEntry(dep checker.DependencyInfo) string {
 	return fmt.Sprintf("%s = { git = %q, commit = %q }", dep.Alias, dep.Git, dep.Commit)
 }
 
-func check(inputPath string) bool {
-	_, err := loadModule(inputPath)
+// explain prints the extended description of the diagnostic code in args, or
+// lists every explained code when args is empty.
+func explain(w io.Writer, args []string) error {
+	switch len(args) {
+	case 0:
+		explanations := diagnostics.Explanations()
+		width := 0
+		for _, explanation := range explanations {
+			width = max(width, len(explanation.Code))
+		}
+		for _, explanation := range explanations {
+			if _, err := fmt.Fprintf(w, "%-*s  %s\n", width, explanation.Code, explanation.Title); err != nil {
+				return err
+			}
+		}
+		return nil
+	case 1:
+		explanation, ok := diagnostics.Explain(args[0])
+		if !ok {
+			return fmt.Errorf("no explanation for %q; run `ard explain` to list the explained codes", args[0])
+		}
+		return diagnostics.WriteExplanation(w, explanation)
+	default:
+		return fmt.Errorf("unexpected argument: %s", args[1])
+	}
+}
+
+// check type-checks the program. In strict mode unused code is reported as
+// warnings, which do not fail the check.
+func check(inputPath string, options frontend.LoadOptions) bool {
+	_, err := frontend.LoadModule(inputPath, options)
 	return err == nil
 }
 
+func parseCheckArgs(args []string) (string, bool, frontend.LoadOptions, error) {
+	inputPath := ""
+	showTypes := false
+	var options frontend.LoadOptions
+	for _, arg := range args {
+		switch {
+		case arg == "--types":
+			showTypes = true
+		case arg == "--strict":
+			options.Strict = true
+		case arg == "--json":
+			options.JSON = true
+		case strings.HasPrefix(arg, "-"):
+			return "", false, frontend.LoadOptions{}, fmt.Errorf("unknown flag: %s", arg)
+		case inputPath == "":
+			inputPath = arg
+		default:
+			return "", false, frontend.LoadOptions{}, fmt.Errorf("unexpected argument: %s", arg)
+		}
+	}
+	if inputPath == "" {
+		return "", false, frontend.LoadOptions{}, fmt.Errorf("Expected filepath argument")
+	}
+	if showTypes && options.JSON {
+		return "", false, frontend.LoadOptions{}, fmt.Errorf("--types and --json cannot be combined")
+	}
+	return inputPath, showTypes, options, nil
+}
+
+// printLineTypes checks the program and writes its source with each line's
+// inferred binding or expression types appended as a comment.
+func printLineTypes(w io.Writer, inputPath string) error {
+	result, err := frontend.LoadModule(inputPath, frontend.LoadOptions{RecordSpans: true})
+	if err != nil {
+		return err
+	}
+	source, err := os.ReadFile(inputPath)
+	if err != nil {
+		return err
+	}
+	types := result.Spans.LineTypes()
+	lines := strings.Split(strings.TrimSuffix(string(source), "\n"), "\n")
+	width := 0
+	for i, line := range lines {
+		if len(types[i+1]) > 0 {
+			width = max(width, len(line))
+		}
+	}
+	for i, line := range lines {
+		annotations := types[i+1]
+		if len(annotations) == 0 {
+			fmt.Fprintln(w, line)
+			continue
+		}
+		fmt.Fprintf(w, "%-*s  // %s\n", width, line, strings.Join(annotations, ", "))
+	}
+	return nil
+}
+
+// parseRenameArgs parses `ard rename <file.ard>:<line>:<col> <new-name>`.
+func parseRenameArgs(args []string) (string, parse.Point, string, error) {
+	if len(args) != 2 {
+		return "", parse.Point{}, "", fmt.Errorf("Usage: ard rename <file.ard>:<line>:<col> <new-name>")
+	}
+	position := args[0]
+	colIdx := strings.LastIndex(position, ":")
+	if colIdx < 0 {
+		return "", parse.Point{}, "", fmt.Errorf("expected <file.ard>:<line>:<col>, got %s", position)
+	}
+	rowIdx := strings.LastIndex(position[:colIdx], ":")
+	if rowIdx < 0 {
+		return "", parse.Point{}, "", fmt.Errorf("expected <file.ard>:<line>:<col>, got %s", position)
+	}
+	row, rowErr := strconv.Atoi(position[rowIdx+1 : colIdx])
+	col, colErr := strconv.Atoi(position[colIdx+1:])
+	if rowErr != nil || colErr != nil || row < 1 || col < 1 || rowIdx == 0 {
+		return "", parse.Point{}, "", fmt.Errorf("expected <file.ard>:<line>:<col>, got %s", position)
+	}
+	return position[:rowIdx], parse.Point{Row: row, Col: col}, args[1], nil
+}
+
+// displayPath shortens path relative to the working directory when it is
+// inside it.
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	if relative, err := filepath.Rel(wd, path); err == nil && !strings.HasPrefix(relative, "..") {
+		return relative
+	}
+	return path
+}
+
 func loadModule(inputPath string) (checker.Module, error) {
 	result, err := frontend.LoadModule(inputPath)
 	if err != nil {
@@ -645,46 +919,100 @@ func loadModule(inputPath string) (checker.Module, error) {
 	return result.Module, nil
 }
 
-func parseRunArgs(args []string) (string, error) {
-	// `ard run <file.ard> [program args...]` forwards everything after the input
-	// file to the program verbatim, so only the input path is parsed here.
-	if len(args) == 0 {
-		return "", fmt.Errorf("expected filepath argument")
+// parseRunArgs returns the input path, the program's own arguments, and the
+// sandbox the `--sandbox` and `--allow-<capability>[=<dir>]` flags describe.
+func parseRunArgs(args []string) (string, []string, gotarget.RunLimits, error) {
+	// `ard run [flags] <file.ard> [program args...]` forwards everything after
+	// the input file to the program verbatim, so only flags before it are parsed.
+	var limits gotarget.RunLimits
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--sandbox" {
+			limits.Sandboxed = true
+			continue
+		}
+		grant, ok := parseAllowFlag(arg)
+		if !ok {
+			return "", nil, gotarget.RunLimits{}, fmt.Errorf("unknown flag: %s", arg)
+		}
+		limits.Sandboxed = true
+		limits.Grants = append(limits.Grants, grant)
 	}
-	inputPath := args[0]
-	if strings.HasPrefix(inputPath, "-") {
-		return "", fmt.Errorf("unknown flag: %s", inputPath)
+	if len(args) == 0 || args[0] == "" {
+		return "", nil, gotarget.RunLimits{}, fmt.Errorf("expected filepath argument")
 	}
-	if inputPath == "" {
-		return "", fmt.Errorf("expected filepath argument")
+	return args[0], args[1:], limits, nil
+}
+
+// parseAllowFlag parses `--allow-<capability>`, with a directory after `=`
+// for the file capabilities.
+func parseAllowFlag(arg string) (gotarget.Grant, bool) {
+	name, ok := strings.CutPrefix(arg, "--allow-")
+	if !ok {
+		return gotarget.Grant{}, false
 	}
-	return inputPath, nil
+	name, path, scoped := strings.Cut(name, "=")
+	capability := gotarget.Capability(name)
+	if !slices.Contains(gotarget.Capabilities, capability) {
+		return gotarget.Grant{}, false
+	}
+	if scoped && (path == "" || (capability != gotarget.CapabilityRead && capability != gotarget.CapabilityWrite)) {
+		return gotarget.Grant{}, false
+	}
+	return gotarget.Grant{Capability: capability, Path: path}, true
 }
 
-func parseBuildArgs(args []string) (string, string, error) {
+const (
+	buildTargetGo   = "go"
+	buildTargetJS   = "js"
+	buildTargetWasm = "wasm"
+	buildTargetAIR  = "air"
+)
+
+// parseBuildArgs returns the input path, output path, target, and whether
+// `--release` drops `assert` statements.
+func parseBuildArgs(args []string) (string, string, string, bool, error) {
 	inputPath := ""
 	outputPath := ""
+	target := buildTargetGo
+	release := false
 	for i := 0; i < len(args); i++ {
 		arg := args[i]
 		if arg == "--out" {
 			if i+1 >= len(args) {
-				return "", "", fmt.Errorf("--out requires a path")
+				return "", "", "", false, fmt.Errorf("--out requires a path")
 			}
 			outputPath = args[i+1]
 			i++
 			continue
 		}
+		if arg == "--release" {
+			release = true
+			continue
+		}
+		if arg == "--target" {
+			if i+1 >= len(args) {
+				return "", "", "", false, fmt.Errorf("--target requires a value")
+			}
+			target = args[i+1]
+			if target != buildTargetGo && target != buildTargetJS && target != buildTargetWasm && target != buildTargetAIR {
+				return "", "", "", false, fmt.Errorf("unsupported build target: %s", target)
+			}
+			i++
+			continue
+		}
 		if strings.HasPrefix(arg, "-") {
-			return "", "", fmt.Errorf("unknown flag: %s", arg)
+			return "", "", "", false, fmt.Errorf("unknown flag: %s", arg)
 		}
 		if inputPath == "" {
 			inputPath = arg
 			continue
 		}
-		return "", "", fmt.Errorf("unexpected argument: %s", arg)
+		return "", "", "", false, fmt.Errorf("unexpected argument: %s", arg)
 	}
 	if inputPath == "" {
-		return "", "", fmt.Errorf("expected filepath argument")
+		return "", "", "", false, fmt.Errorf("expected filepath argument")
 	}
 	if outputPath == "" {
 		outputPath = filepath.Base(strings.TrimSuffix(inputPath, filepath.Ext(inputPath)))
@@ -692,31 +1020,51 @@ func parseBuildArgs(args []string) (string, string, error) {
 			outputPath = "main"
 		}
 	}
-	return inputPath, outputPath, nil
+	return inputPath, outputPath, target, release, nil
 }
 
-func parseFormatArgs(args []string) (string, bool, error) {
+// parseFormatArgs parses `[--check] [--width <n>] [--fix-imports] <path>`.
+// The returned options hold only what the flags override; a MaxWidth of 0
+// means --width was not given.
+func parseFormatArgs(args []string) (string, bool, formatter.Options, error) {
 	inputPath := ""
 	checkOnly := false
-	for i := range args {
+	overrides := formatter.Options{}
+	for i := 0; i < len(args); i++ {
 		arg := args[i]
 		if arg == "--check" {
 			checkOnly = true
 			continue
 		}
+		if arg == "--fix-imports" {
+			overrides.FixImports = true
+			continue
+		}
+		if arg == "--width" {
+			if i+1 >= len(args) {
+				return "", false, formatter.Options{}, fmt.Errorf("--width requires a value")
+			}
+			i++
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return "", false, formatter.Options{}, fmt.Errorf("--width must be a positive integer")
+			}
+			overrides.MaxWidth = n
+			continue
+		}
 		if strings.HasPrefix(arg, "-") {
-			return "", false, fmt.Errorf("unknown flag: %s", arg)
+			return "", false, formatter.Options{}, fmt.Errorf("unknown flag: %s", arg)
 		}
 		if inputPath == "" {
 			inputPath = arg
 			continue
 		}
-		return "", false, fmt.Errorf("unexpected argument: %s", arg)
+		return "", false, formatter.Options{}, fmt.Errorf("unexpected argument: %s", arg)
 	}
 	if inputPath == "" {
-		return "", false, fmt.Errorf("expected filepath argument")
+		return "", false, formatter.Options{}, fmt.Errorf("expected filepath argument")
 	}
-	return inputPath, checkOnly, nil
+	return inputPath, checkOnly, overrides, nil
 }
 
 func parseTestArgs(args []string) (string, string, bool, error) {
@@ -751,14 +1099,221 @@ func parseTestArgs(args []string) (string, string, bool, error) {
 	return inputPath, filter, failFast, nil
 }
 
-func formatPath(inputPath string, checkOnly bool) ([]string, error) {
+type benchOptions struct {
+	inputPath string
+	runs      int
+	jsonPath  string
+	baseline  string
+	threshold float64
+}
+
+func parseBenchArgs(args []string) (benchOptions, error) {
+	opts := benchOptions{runs: 10, threshold: 5}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "--runs", "--json", "--compare", "--threshold":
+			if i+1 >= len(args) {
+				return benchOptions{}, fmt.Errorf("%s requires a value", arg)
+			}
+			value := args[i+1]
+			i++
+			switch arg {
+			case "--runs":
+				runs, err := strconv.Atoi(value)
+				if err != nil || runs < 2 {
+					return benchOptions{}, fmt.Errorf("--runs must be an integer of at least 2")
+				}
+				opts.runs = runs
+			case "--json":
+				opts.jsonPath = value
+			case "--compare":
+				opts.baseline = value
+			case "--threshold":
+				threshold, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+				if err != nil || threshold < 0 {
+					return benchOptions{}, fmt.Errorf("--threshold must be a non-negative percentage")
+				}
+				opts.threshold = threshold
+			}
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return benchOptions{}, fmt.Errorf("unknown flag: %s", arg)
+			}
+			if opts.inputPath != "" {
+				return benchOptions{}, fmt.Errorf("unexpected argument: %s", arg)
+			}
+			opts.inputPath = arg
+		}
+	}
+	if opts.inputPath == "" {
+		return benchOptions{}, fmt.Errorf("expected filepath argument")
+	}
+	return opts, nil
+}
+
+// benchResult is the JSON written by `ard bench --json` and read back by
+// `--compare`. Durations are in nanoseconds.
+type benchResult struct {
+	Program string  `json:"program"`
+	Runs    int     `json:"runs"`
+	Samples []int64 `json:"samples_ns"`
+	Min     int64   `json:"min_ns"`
+	Median  int64   `json:"median_ns"`
+	Mean    int64   `json:"mean_ns"`
+	Stddev  int64   `json:"stddev_ns"`
+}
+
+func newBenchResult(program string, samples []time.Duration) benchResult {
+	result := benchResult{Program: program, Runs: len(samples)}
+	if len(samples) == 0 {
+		return result
+	}
+	sorted := make([]int64, len(samples))
+	for i, sample := range samples {
+		result.Samples = append(result.Samples, int64(sample))
+		sorted[i] = int64(sample)
+	}
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+	result.Min = sorted[0]
+	if mid := len(sorted) / 2; len(sorted)%2 == 1 {
+		result.Median = sorted[mid]
+	} else {
+		result.Median = (sorted[mid-1] + sorted[mid]) / 2
+	}
+	var sum float64
+	for _, sample := range sorted {
+		sum += float64(sample)
+	}
+	mean := sum / float64(len(sorted))
+	result.Mean = int64(mean)
+	if len(sorted) > 1 {
+		var squares float64
+		for _, sample := range sorted {
+			squares += (float64(sample) - mean) * (float64(sample) - mean)
+		}
+		result.Stddev = int64(math.Sqrt(squares / float64(len(sorted)-1)))
+	}
+	return result
+}
+
+// benchComparison describes how a result's median moved from a baseline.
+type benchComparison struct {
+	Change      float64 // percent change of the median
+	Significant bool    // the change exceeds the noise in both samples
+	Regression  bool    // significant and slower by more than the threshold
+}
+
+// compareBench compares medians. A change is significant when it exceeds two
+// standard errors of the difference, so noisy runs do not report regressions.
+func compareBench(baseline, current benchResult, threshold float64) benchComparison {
+	if baseline.Median == 0 {
+		return benchComparison{}
+	}
+	delta := float64(current.Median - baseline.Median)
+	comparison := benchComparison{Change: delta / float64(baseline.Median) * 100}
+	noise := 0.0
+	if baseline.Runs > 0 && current.Runs > 0 {
+		noise = 2 * math.Sqrt(math.Pow(float64(baseline.Stddev), 2)/float64(baseline.Runs)+math.Pow(float64(current.Stddev), 2)/float64(current.Runs))
+	}
+	comparison.Significant = math.Abs(delta) > noise
+	comparison.Regression = comparison.Significant && comparison.Change > threshold
+	return comparison
+}
+
+// runBench builds the program once, runs it after a warm-up, and reports
+// timings. It returns false when the result regresses from the baseline.
+func runBench(w io.Writer, opts benchOptions) (bool, error) {
+	var baseline *benchResult
+	if opts.baseline != "" {
+		data, err := os.ReadFile(opts.baseline)
+		if err != nil {
+			return false, err
+		}
+		baseline = &benchResult{}
+		if err := json.Unmarshal(data, baseline); err != nil {
+			return false, fmt.Errorf("invalid baseline %s: %w", opts.baseline, err)
+		}
+	}
+
+	dir, err := os.MkdirTemp("", "ard-bench-")
+	if err != nil {
+		return false, err
+	}
+	defer os.RemoveAll(dir)
+	binary, err := buildGoBinary(opts.inputPath, filepath.Join(dir, "bench"), air.LowerOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	samples := make([]time.Duration, 0, opts.runs)
+	for i := 0; i <= opts.runs; i++ {
+		cmd := exec.Command(binary)
+		cmd.Stdout = io.Discard
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		start := time.Now()
+		if err := cmd.Run(); err != nil {
+			return false, fmt.Errorf("%s failed: %w\n%s", opts.inputPath, err, stderr.String())
+		}
+		elapsed := time.Since(start)
+		if i > 0 { // the first run warms caches and is not measured
+			samples = append(samples, elapsed)
+		}
+	}
+
+	result := newBenchResult(opts.inputPath, samples)
+	fmt.Fprintf(w, "%s: %d runs\n", result.Program, result.Runs)
+	fmt.Fprintf(w, "  min     %s\n", time.Duration(result.Min))
+	fmt.Fprintf(w, "  median  %s\n", time.Duration(result.Median))
+	fmt.Fprintf(w, "  stddev  %s\n", time.Duration(result.Stddev))
+
+	if opts.jsonPath != "" {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return false, err
+		}
+		if err := os.WriteFile(opts.jsonPath, append(data, '\n'), 0o644); err != nil {
+			return false, err
+		}
+	}
+
+	if baseline == nil {
+		return true, nil
+	}
+	comparison := compareBench(*baseline, result, opts.threshold)
+	verdict := "no significant change"
+	switch {
+	case comparison.Regression:
+		verdict = fmt.Sprintf("regression above %.1f%%", opts.threshold)
+	case comparison.Significant && comparison.Change < 0:
+		verdict = "improvement"
+	case comparison.Significant:
+		verdict = fmt.Sprintf("within %.1f%% threshold", opts.threshold)
+	}
+	fmt.Fprintf(w, "baseline median %s -> %s (%+.1f%%): %s\n", time.Duration(baseline.Median), time.Duration(result.Median), comparison.Change, verdict)
+	return !comparison.Regression, nil
+}
+
+// formatPath formats inputPath with the `[format]` options of its project's
+// ard.toml. A positive overrides.MaxWidth replaces the configured line width,
+// and overrides.FixImports removes unused imports.
+func formatPath(inputPath string, checkOnly bool, overrides formatter.Options) ([]string, error) {
 	fileInfo, err := os.Stat(inputPath)
 	if err != nil {
 		return nil, fmt.Errorf("error reading path %s - %w", inputPath, err)
 	}
+	options, err := formatter.FindOptions(inputPath)
+	if err != nil {
+		return nil, err
+	}
+	if overrides.MaxWidth > 0 {
+		options.MaxWidth = overrides.MaxWidth
+	}
+	options.FixImports = overrides.FixImports
 
 	if !fileInfo.IsDir() {
-		changed, err := formatFile(inputPath, checkOnly)
+		changed, err := formatFile(inputPath, checkOnly, options)
 		if err != nil {
 			return nil, err
 		}
@@ -790,7 +1345,7 @@ func formatPath(inputPath string, checkOnly bool) ([]string, error) {
 
 	changedPaths := make([]string, 0)
 	for _, filePath := range ardFiles {
-		changed, fileErr := formatFile(filePath, checkOnly)
+		changed, fileErr := formatFile(filePath, checkOnly, options)
 		if fileErr != nil {
 			return nil, fileErr
 		}
@@ -801,19 +1356,24 @@ func formatPath(inputPath string, checkOnly bool) ([]string, error) {
 	return changedPaths, nil
 }
 
-func formatFile(inputPath string, checkOnly bool) (bool, error) {
+func formatFile(inputPath string, checkOnly bool, options formatter.Options) (bool, error) {
 	sourceCode, err := os.ReadFile(inputPath)
 	if err != nil {
 		return false, fmt.Errorf("error reading file %s - %w", inputPath, err)
 	}
 
-	formatted, err := formatter.Format(sourceCode, inputPath)
-	if err != nil {
-		return false, fmt.Errorf("error formatting file %s - %w", inputPath, err)
+	// Source with parse errors still gets its well-formed regions formatted;
+	// the parse errors are reported once the file is written.
+	formatted, formatErr := formatter.Format(sourceCode, inputPath, options)
+	if formatErr != nil {
+		formatErr = fmt.Errorf("error formatting file %s - %w", inputPath, formatErr)
+		if formatted == nil {
+			return false, formatErr
+		}
 	}
 	changed := !bytes.Equal(sourceCode, formatted)
 	if !changed || checkOnly {
-		return changed, nil
+		return changed, formatErr
 	}
 
 	fileInfo, err := os.Stat(inputPath)
@@ -825,7 +1385,124 @@ func formatFile(inputPath string, checkOnly bool) (bool, error) {
 		return false, fmt.Errorf("error writing file %s - %w", inputPath, err)
 	}
 
-	return true, nil
+	return true, formatErr
+}
+
+// migratePath rewrites deprecated syntax in every Ard file under inputPath
+// and pins the enclosing project's ard.toml to the current language version.
+// Only files containing deprecated syntax are rewritten.
+func migratePath(inputPath string, checkOnly bool) ([]string, error) {
+	fileInfo, err := os.Stat(inputPath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading path %s - %w", inputPath, err)
+	}
+
+	ardFiles := []string{inputPath}
+	if fileInfo.IsDir() {
+		ardFiles, err = discoverTestFiles(inputPath)
+		if err != nil {
+			return nil, fmt.Errorf("error walking directory %s - %w", inputPath, err)
+		}
+	}
+
+	options, err := formatter.FindOptions(inputPath)
+	if err != nil {
+		return nil, err
+	}
+
+	migratedPaths := make([]string, 0)
+	for _, filePath := range ardFiles {
+		migrated, fileErr := migrateFile(filePath, checkOnly, options)
+		if fileErr != nil {
+			return nil, fileErr
+		}
+		if migrated > 0 {
+			migratedPaths = append(migratedPaths, filePath)
+			if !checkOnly {
+				fmt.Printf("Migrated %s (%d rewrites)\n", filePath, migrated)
+			}
+		}
+	}
+	if checkOnly {
+		return migratedPaths, nil
+	}
+
+	startDir := inputPath
+	if !fileInfo.IsDir() {
+		startDir = filepath.Dir(inputPath)
+	}
+	project, err := checker.FindProjectRoot(startDir)
+	if err != nil {
+		return nil, err
+	}
+	manifestPath := filepath.Join(project.RootPath, "ard.toml")
+	if _, err := os.Stat(manifestPath); err == nil {
+		if err := setManifestLanguage(manifestPath, version.Language()); err != nil {
+			return nil, err
+		}
+	}
+	return migratedPaths, nil
+}
+
+func migrateFile(inputPath string, checkOnly bool, options formatter.Options) (int, error) {
+	sourceCode, err := os.ReadFile(inputPath)
+	if err != nil {
+		return 0, fmt.Errorf("error reading file %s - %w", inputPath, err)
+	}
+
+	migrated, legacy, err := formatter.Migrate(sourceCode, inputPath, options)
+	if err != nil {
+		return 0, fmt.Errorf("error migrating file %s - %w", inputPath, err)
+	}
+	if len(legacy) == 0 || checkOnly {
+		return len(legacy), nil
+	}
+
+	fileInfo, err := os.Stat(inputPath)
+	if err != nil {
+		return 0, fmt.Errorf("error reading file info %s - %w", inputPath, err)
+	}
+	if err := os.WriteFile(inputPath, migrated, fileInfo.Mode()); err != nil {
+		return 0, fmt.Errorf("error writing file %s - %w", inputPath, err)
+	}
+	return len(legacy), nil
+}
+
+// setManifestLanguage pins `language` in ard.toml's top-level table, replacing
+// an existing entry or adding one after the `ard` version constraint. Entries
+// in later tables, such as a dependency's `language`, are left alone.
+func setManifestLanguage(path string, language version.Semver) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	entry := fmt.Sprintf("language = %q", version.LanguageString(language))
+	lines := strings.Split(string(data), "\n")
+	languageRe := regexp.MustCompile(`^[ \t]*language[ \t]*=`)
+	ardRe := regexp.MustCompile(`^[ \t]*ard[ \t]*=`)
+	insertAt := 0
+	foundArd := false
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "[") {
+			break
+		}
+		if languageRe.MatchString(line) {
+			lines[i] = entry
+			return os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o644)
+		}
+		if foundArd {
+			continue
+		}
+		if ardRe.MatchString(line) {
+			insertAt = i + 1
+			foundArd = true
+		} else if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			insertAt = i + 1
+		}
+	}
+	updated := append(lines[:insertAt:insertAt], append([]string{entry}, lines[insertAt:]...)...)
+	return os.WriteFile(path, []byte(strings.Join(updated, "\n")), 0o644)
 }
 
 type discoveredTest struct {
@@ -976,8 +1653,11 @@ func loadGoTestModules(inputPath string, files []string, filter string) ([]loade
 			return nil, projectInfo, fmt.Errorf("error reading file %s - %v", path, err)
 		}
 		result := parse.Parse(sourceCode, path)
+		result.AllowLegacy(projectInfo.LanguageVersion())
 		if len(result.Errors) > 0 {
-			result.PrintErrors()
+			if err := diagnostics.Render(os.Stdout, checker.ParseErrorDiagnostics(path, result.Errors), diagnostics.FileSourceProvider()); err != nil {
+				return nil, projectInfo, fmt.Errorf("render diagnostics: %w", err)
+			}
 			return nil, projectInfo, fmt.Errorf("parse errors")
 		}
 		parsedFiles[path] = result.Program
@@ -1205,43 +1885,180 @@ func reportTestSummary(outcomes []testOutcome) {
 	fmt.Printf("\n%d passed; %d failed; %d panicked\n", passed, failed, panicked)
 }
 
-func buildGoBinary(inputPath string, outputPath string) (string, error) {
+func buildGoBinary(inputPath string, outputPath string, options air.LowerOptions) (string, error) {
 	profile := newPipelineProfile("build go")
 	defer profile.Print()
-	var loaded *frontend.LoadResult
-	if err := profile.Time("frontend.load_module", func() error {
-		var loadErr error
-		loaded, loadErr = frontend.LoadModule(inputPath)
-		return loadErr
-	}); err != nil {
+	loaded, program, err := loadBuildProgram(profile, inputPath, options)
+	if err != nil {
 		return "", err
 	}
-	var program *air.Program
-	if err := profile.Time("air.lower", func() error {
-		var lowerErr error
-		program, lowerErr = air.Lower(loaded.Module)
-		return lowerErr
+	if outputPath == "" {
+		outputPath = filepath.Base(strings.TrimSuffix(inputPath, filepath.Ext(inputPath)))
+		if outputPath == "" || outputPath == "." || outputPath == string(filepath.Separator) {
+			outputPath = "main"
+		}
+	}
+	var builtPath string
+	if err := profile.Time("go.build", func() error {
+		var buildErr error
+		builtPath, buildErr = gotarget.BuildProgram(program, outputPath, loaded.ProjectInfo)
+		return buildErr
 	}); err != nil {
 		return "", err
 	}
-	if err := profile.Time("air.validate", func() error {
-		return air.Validate(program)
+	return builtPath, nil
+}
+
+// buildJSProgram writes the program as ES modules into the output directory
+// and returns the path of the generated entry module.
+func buildJSProgram(inputPath string, outputPath string, options air.LowerOptions) (string, error) {
+	profile := newPipelineProfile("build js")
+	defer profile.Print()
+	_, program, err := loadBuildProgram(profile, inputPath, options)
+	if err != nil {
+		return "", err
+	}
+	var builtPath string
+	if err := profile.Time("js.build", func() error {
+		var buildErr error
+		builtPath, buildErr = jstarget.BuildProgram(program, outputPath)
+		return buildErr
 	}); err != nil {
 		return "", err
 	}
-	if err := validateEntrypointSignature(profile, program); err != nil {
+	return builtPath, nil
+}
+
+// airExtension marks a serialized program written by `ard build --target air`.
+const airExtension = ".air"
+
+// buildAIRProgram writes the program's AIR, which `ard run` executes without
+// its sources, and returns the path it wrote.
+func buildAIRProgram(inputPath string, outputPath string, options air.LowerOptions) (string, error) {
+	profile := newPipelineProfile("build air")
+	defer profile.Print()
+	_, program, err := loadBuildProgram(profile, inputPath, options)
+	if err != nil {
 		return "", err
 	}
-	if outputPath == "" {
-		outputPath = filepath.Base(strings.TrimSuffix(inputPath, filepath.Ext(inputPath)))
-		if outputPath == "" || outputPath == "." || outputPath == string(filepath.Separator) {
-			outputPath = "main"
+	if filepath.Ext(outputPath) == "" {
+		outputPath += airExtension
+	}
+	data, err := air.SerializeProgram(program)
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(outputPath, data, 0o644); err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
+
+// loadAIRProgram reads a program written by buildAIRProgram.
+func loadAIRProgram(path string) (*air.Program, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	program, err := air.DeserializeProgram(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return program, nil
+}
+
+// isBuiltBinary reports whether path is a binary written by `ard build`
+// rather than a source file or directory.
+func isBuiltBinary(path string) bool {
+	if filepath.Ext(path) == ".ard" || filepath.Ext(path) == airExtension {
+		return false
+	}
+	if stat, err := os.Stat(path); err != nil || stat.IsDir() {
+		return false
+	}
+	_, err := gotarget.ReadBuildMetadata(path)
+	return err == nil
+}
+
+// exitWithRunError exits with a program's status when it failed, and
+// returns when it succeeded.
+func exitWithRunError(err error) {
+	if err == nil {
+		return
+	}
+	var exit gotarget.ExitError
+	if errors.As(err, &exit) {
+		os.Exit(exit.Code)
+	}
+	fmt.Println(err)
+	os.Exit(1)
+}
+
+// inspectArtifact prints what a built program records about itself: the
+// compiler that built it, its modules and entry points, and its size and
+// checksum.
+func inspectArtifact(w io.Writer, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var info air.ProgramInfo
+	var lines [][2]string
+	if header, err := air.ReadHeader(data); err == nil {
+		lines = append(lines, [2]string{"kind", fmt.Sprintf("AIR (format %d)", header.Format)})
+		program, err := air.DeserializeProgram(data)
+		if err != nil {
+			var incompatible air.IncompatibleProgramError
+			if !errors.As(err, &incompatible) {
+				return fmt.Errorf("%s: %w", path, err)
+			}
+			lines = append(lines, [2]string{"compiler", "ard " + header.Compiler}, [2]string{"note", incompatible.Error()})
+		} else {
+			info = air.Describe(program)
+			info.Compiler = header.Compiler
+		}
+	} else {
+		built, err := gotarget.ReadBuildMetadata(path)
+		if err != nil {
+			return err
 		}
+		lines = append(lines, [2]string{"kind", "native binary (" + built.GoVersion + ")"})
+		info = built.ProgramInfo
+	}
+	if info.Compiler != "" {
+		lines = append(lines, [2]string{"compiler", "ard " + info.Compiler})
+		if info.Entry != "" {
+			lines = append(lines, [2]string{"entry", info.Entry})
+		}
+		lines = append(lines, [2]string{"modules", strings.Join(info.Modules, ", ")})
+		if len(info.Tests) > 0 {
+			lines = append(lines, [2]string{"tests", strings.Join(info.Tests, ", ")})
+		}
+	}
+	sum := sha256.Sum256(data)
+	lines = append(lines, [2]string{"size", fmt.Sprintf("%d bytes", len(data))}, [2]string{"sha256", hex.EncodeToString(sum[:])})
+	fmt.Fprintln(w, path)
+	for _, line := range lines {
+		fmt.Fprintf(w, "  %-9s %s\n", line[0]+":", line[1])
+	}
+	return nil
+}
+
+// buildWasmProgram compiles the program's Go output for js/wasm and writes
+// the module with its JavaScript glue into the output directory.
+func buildWasmProgram(inputPath string, outputPath string, options air.LowerOptions) (string, error) {
+	profile := newPipelineProfile("build wasm")
+	defer profile.Print()
+	// Resolve Go imports for the browser platform too, so programs can use
+	// js/wasm-only packages such as syscall/js to reach the glue's hooks.
+	loaded, program, err := loadBuildProgram(profile, inputPath, options, frontend.LoadOptions{GoEnv: gotarget.WasmEnv})
+	if err != nil {
+		return "", err
 	}
 	var builtPath string
-	if err := profile.Time("go.build", func() error {
+	if err := profile.Time("wasm.build", func() error {
 		var buildErr error
-		builtPath, buildErr = gotarget.BuildProgram(program, outputPath, loaded.ProjectInfo)
+		builtPath, buildErr = gotarget.BuildWasmProgram(program, outputPath, loaded.ProjectInfo)
 		return buildErr
 	}); err != nil {
 		return "", err
@@ -1249,6 +2066,36 @@ func buildGoBinary(inputPath string, outputPath string) (string, error) {
 	return builtPath, nil
 }
 
+// loadBuildProgram runs the shared front half of every build target: load
+// and check the module, lower it to AIR, and validate the result.
+func loadBuildProgram(profile *pipelineProfile, inputPath string, options air.LowerOptions, loadOptions ...frontend.LoadOptions) (*frontend.LoadResult, *air.Program, error) {
+	var loaded *frontend.LoadResult
+	if err := profile.Time("frontend.load_module", func() error {
+		var loadErr error
+		loaded, loadErr = frontend.LoadModule(inputPath, loadOptions...)
+		return loadErr
+	}); err != nil {
+		return nil, nil, err
+	}
+	var program *air.Program
+	if err := profile.Time("air.lower", func() error {
+		var lowerErr error
+		program, lowerErr = air.LowerWithOptions(loaded.Module, options)
+		return lowerErr
+	}); err != nil {
+		return nil, nil, err
+	}
+	if err := profile.Time("air.validate", func() error {
+		return air.Validate(program)
+	}); err != nil {
+		return nil, nil, err
+	}
+	if err := validateEntrypointSignature(profile, program); err != nil {
+		return nil, nil, err
+	}
+	return loaded, program, nil
+}
+
 func validateEntrypointSignature(profile *pipelineProfile, program *air.Program) error {
 	return profile.Time("air.validate_entrypoint", func() error {
 		return air.ValidateEntrypointSignature(program)
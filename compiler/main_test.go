@@ -1,17 +1,25 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/akonwi/ard/air"
 	"github.com/akonwi/ard/checker"
+	"github.com/akonwi/ard/formatter"
 	"github.com/akonwi/ard/frontend"
 	gotarget "github.com/akonwi/ard/go"
+	"github.com/akonwi/ard/parse"
+	"github.com/akonwi/ard/version"
 )
 
 func captureStdout(t *testing.T, fn func()) string {
@@ -45,6 +53,7 @@ func TestParseRunArgs(t *testing.T) {
 		name       string
 		args       []string
 		path       string
+		limits     gotarget.RunLimits
 		expectErr  bool
 		errMessage string
 	}{
@@ -77,11 +86,38 @@ func TestParseRunArgs(t *testing.T) {
 			args: []string{"samples/main.ard", "create", "x", "--dir", "y"},
 			path: "samples/main.ard",
 		},
+		{
+			name:   "sandbox without grants",
+			args:   []string{"--sandbox", "samples/main.ard"},
+			path:   "samples/main.ard",
+			limits: gotarget.RunLimits{Sandboxed: true},
+		},
+		{
+			name: "allow flags sandbox the program",
+			args: []string{"--allow-net", "--allow-read=./data", "samples/main.ard", "--allow-env"},
+			path: "samples/main.ard",
+			limits: gotarget.RunLimits{Sandboxed: true, Grants: []gotarget.Grant{
+				{Capability: gotarget.CapabilityNet},
+				{Capability: gotarget.CapabilityRead, Path: "./data"},
+			}},
+		},
+		{
+			name:       "unknown capability",
+			args:       []string{"--allow-time", "samples/main.ard"},
+			expectErr:  true,
+			errMessage: "unknown flag: --allow-time",
+		},
+		{
+			name:       "directory on an unscoped capability",
+			args:       []string{"--allow-net=example.com", "samples/main.ard"},
+			expectErr:  true,
+			errMessage: "unknown flag: --allow-net=example.com",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path, err := parseRunArgs(tt.args)
+			path, _, limits, err := parseRunArgs(tt.args)
 			if tt.expectErr {
 				if err == nil {
 					t.Fatalf("expected error %q, got nil", tt.errMessage)
@@ -98,9 +134,113 @@ func TestParseRunArgs(t *testing.T) {
 			if path != tt.path {
 				t.Fatalf("expected path %q, got %q", tt.path, path)
 			}
+			if !reflect.DeepEqual(limits, tt.limits) {
+				t.Fatalf("expected limits %#v, got %#v", tt.limits, limits)
+			}
 		})
 	}
 }
+func TestParseCheckArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		path       string
+		types      bool
+		options    frontend.LoadOptions
+		errMessage string
+	}{
+		{name: "input only", args: []string{"main.ard"}, path: "main.ard"},
+		{name: "types before input", args: []string{"--types", "main.ard"}, path: "main.ard", types: true},
+		{name: "types after input", args: []string{"main.ard", "--types"}, path: "main.ard", types: true},
+		{name: "strict", args: []string{"main.ard", "--strict"}, path: "main.ard", options: frontend.LoadOptions{Strict: true}},
+		{name: "types and strict", args: []string{"--strict", "--types", "main.ard"}, path: "main.ard", types: true, options: frontend.LoadOptions{Strict: true}},
+		{name: "json", args: []string{"--json", "--strict", "main.ard"}, path: "main.ard", options: frontend.LoadOptions{Strict: true, JSON: true}},
+		{name: "types and json", args: []string{"--types", "--json", "main.ard"}, errMessage: "--types and --json cannot be combined"},
+		{name: "missing input", args: []string{"--types"}, errMessage: "Expected filepath argument"},
+		{name: "unknown flag", args: []string{"--verbose", "main.ard"}, errMessage: "unknown flag: --verbose"},
+		{name: "extra argument", args: []string{"main.ard", "other.ard"}, errMessage: "unexpected argument: other.ard"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, types, options, err := parseCheckArgs(tt.args)
+			if tt.errMessage != "" {
+				if err == nil || err.Error() != tt.errMessage {
+					t.Fatalf("expected error %q, got %v", tt.errMessage, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if path != tt.path || types != tt.types || !reflect.DeepEqual(options, tt.options) {
+				t.Fatalf("got (%q, %v, %+v), want (%q, %v, %+v)", path, types, options, tt.path, tt.types, tt.options)
+			}
+		})
+	}
+}
+
+func TestParseRenameArgs(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       []string
+		path       string
+		at         parse.Point
+		newName    string
+		errMessage string
+	}{
+		{name: "position and name", args: []string{"src/main.ard:4:16", "one"}, path: "src/main.ard", at: parse.Point{Row: 4, Col: 16}, newName: "one"},
+		{name: "missing name", args: []string{"main.ard:1:4"}, errMessage: "Usage: ard rename <file.ard>:<line>:<col> <new-name>"},
+		{name: "missing column", args: []string{"main.ard:1", "one"}, errMessage: "expected <file.ard>:<line>:<col>, got main.ard:1"},
+		{name: "non-numeric position", args: []string{"main.ard:a:b", "one"}, errMessage: "expected <file.ard>:<line>:<col>, got main.ard:a:b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, at, newName, err := parseRenameArgs(tt.args)
+			if tt.errMessage != "" {
+				if err == nil || err.Error() != tt.errMessage {
+					t.Fatalf("expected error %q, got %v", tt.errMessage, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect error: %v", err)
+			}
+			if path != tt.path || at != tt.at || newName != tt.newName {
+				t.Fatalf("got (%q, %v, %q), want (%q, %v, %q)", path, at, newName, tt.path, tt.at, tt.newName)
+			}
+		})
+	}
+}
+
+func TestPrintLineTypesAnnotatesSource(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.ard")
+	source := `fn main() {
+  let names = ["a", "b"]
+  mut count = names.size()
+  count = count + 1
+}
+`
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	if err := printLineTypes(&out, path); err != nil {
+		t.Fatalf("printLineTypes: %v", err)
+	}
+	want := `fn main() {                 // main: fn()
+  let names = ["a", "b"]    // names: [Str]
+  mut count = names.size()  // count: Int
+  count = count + 1         // Int
+}
+`
+	if out.String() != want {
+		t.Fatalf("unexpected output\nwant:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
 func TestLoadModuleRendersStructuredTypeMismatch(t *testing.T) {
 	projectDir := t.TempDir()
 	if err := os.WriteFile(filepath.Join(projectDir, "ard.toml"), []byte("name = \"example\"\nard = \">= 0.27.0\"\n"), 0o644); err != nil {
@@ -117,7 +257,7 @@ func TestLoadModuleRendersStructuredTypeMismatch(t *testing.T) {
 		}
 	})
 	for _, want := range []string{
-		"error: Type mismatch",
+		"error[type_mismatch]: Type mismatch",
 		"let name: Str = 42",
 		"this expression has type `Int`",
 		"this annotation requires `Str`",
@@ -152,8 +292,10 @@ func TestRunGoProgram(t *testing.T) {
 	if err != nil {
 		t.Fatalf("lower AIR: %v", err)
 	}
-	if err := gotarget.RunProgram(program, []string{"ard", "run", sourcePath}); err != nil {
-		t.Fatalf("run go backend: %v", err)
+	var exit gotarget.ExitError
+	err = gotarget.RunProgram(program, []string{"ard", "run", sourcePath})
+	if !errors.As(err, &exit) || exit.Code != 5 {
+		t.Fatalf("run go backend error = %v, want exit status 5", err)
 	}
 }
 func TestRunGoTargetVariablesSample(t *testing.T) {
@@ -414,17 +556,17 @@ func TestBuildRejectsInvalidMainEntrypointSignature(t *testing.T) {
 		wantErr string
 	}{
 		{
-			name: "main with args",
+			name: "main with a non-[Str] parameter",
 			source: `fn main(name: Str) Void {
 			}`,
-			wantErr: "main entrypoint cannot have parameters",
+			wantErr: "main entrypoint parameter must be [Str], got Str",
 		},
 		{
-			name: "main with non-Void return",
-			source: `fn main() Int {
-			  1
+			name: "main with an unsupported return",
+			source: `fn main() Str {
+			  "done"
 			}`,
-			wantErr: "main entrypoint must return Void, got Int",
+			wantErr: "main entrypoint must return Void or Int, got Str",
 		},
 	}
 
@@ -435,7 +577,7 @@ func TestBuildRejectsInvalidMainEntrypointSignature(t *testing.T) {
 			if err := os.WriteFile(sourcePath, []byte(tt.source), 0o644); err != nil {
 				t.Fatalf("write source: %v", err)
 			}
-			_, err := buildGoBinary(sourcePath, filepath.Join(tempDir, "main-bin"))
+			_, err := buildGoBinary(sourcePath, filepath.Join(tempDir, "main-bin"), air.LowerOptions{})
 			if err == nil {
 				t.Fatalf("buildGoBinary succeeded, want error containing %q", tt.wantErr)
 			}
@@ -458,7 +600,7 @@ func TestBuildGoBinary(t *testing.T) {
 		t.Fatalf("write source: %v", err)
 	}
 
-	builtPath, err := buildGoBinary(sourcePath, outputPath)
+	builtPath, err := buildGoBinary(sourcePath, outputPath, air.LowerOptions{})
 	if err != nil {
 		t.Fatalf("build go backend: %v", err)
 	}
@@ -469,6 +611,247 @@ func TestBuildGoBinary(t *testing.T) {
 		t.Fatalf("stat built binary: %v", err)
 	}
 }
+func TestBuildWasmProgramLeavesEnvironmentAlone(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a js/wasm binary")
+	}
+	goos, goarch := os.Getenv("GOOS"), os.Getenv("GOARCH")
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "main.ard")
+	source := `
+		use go:syscall/js
+
+		fn main() Void {
+			js::Global()
+			()
+		}
+	`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	if _, err := buildWasmProgram(sourcePath, filepath.Join(tempDir, "web"), air.LowerOptions{}); err != nil {
+		t.Fatalf("build wasm: %v", err)
+	}
+	if os.Getenv("GOOS") != goos || os.Getenv("GOARCH") != goarch {
+		t.Fatalf("GOOS/GOARCH = %q/%q after a wasm build, want %q/%q", os.Getenv("GOOS"), os.Getenv("GOARCH"), goos, goarch)
+	}
+	// a later native build in the same process must still target the host
+	if err := os.WriteFile(sourcePath, []byte("fn main() Void { () }\n"), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	outputPath := filepath.Join(tempDir, "main-bin")
+	if _, err := buildGoBinary(sourcePath, outputPath, air.LowerOptions{}); err != nil {
+		t.Fatalf("build go backend after wasm: %v", err)
+	}
+	if out, err := exec.Command(outputPath).CombinedOutput(); err != nil {
+		t.Fatalf("run native binary after a wasm build: %v\n%s", err, out)
+	}
+}
+
+func TestBuildGoBinaryPassesArgsAndExitCode(t *testing.T) {
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "main.ard")
+	source := `use go:fmt
+
+fn main(args: [Str]) Int {
+  for arg in args {
+    fmt::Println(arg)
+  }
+  args.size()
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	builtPath, err := buildGoBinary(sourcePath, filepath.Join(tempDir, "main-bin"), air.LowerOptions{})
+	if err != nil {
+		t.Fatalf("build go backend: %v", err)
+	}
+	out, err := exec.Command(builtPath, "one", "two").Output()
+	exitErr, ok := err.(*exec.ExitError)
+	if !ok || exitErr.ExitCode() != 2 {
+		t.Fatalf("run error = %v, want exit status 2", err)
+	}
+	if string(out) != "one\ntwo\n" {
+		t.Fatalf("stdout = %q, want the program arguments", string(out))
+	}
+}
+
+func TestBuildGoBinaryReadsStdinWithArdIO(t *testing.T) {
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "main.ard")
+	source := `use ard/io
+
+fn main() {
+  mut out = io::stdout()
+  let first = io::read_line().or("none")
+  out.write_line("first: {first}")
+  out.write("rest: " + io::read_all())
+  out.flush().expect("flush stdout")
+  io::eprint("done")
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	builtPath, err := buildGoBinary(sourcePath, filepath.Join(tempDir, "main-bin"), air.LowerOptions{})
+	if err != nil {
+		t.Fatalf("build go backend: %v", err)
+	}
+	cmd := exec.Command(builtPath)
+	cmd.Stdin = strings.NewReader("one\r\ntwo\nthree")
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if string(out) != "first: one\nrest: two\nthree" {
+		t.Fatalf("stdout = %q", string(out))
+	}
+	if stderr.String() != "done\n" {
+		t.Fatalf("stderr = %q, want done", stderr.String())
+	}
+}
+
+func TestBuildGoBinaryAssertFailures(t *testing.T) {
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "main.ard")
+	source := `fn double(n: Int) Int {
+  n * 2
+}
+
+fn main() {
+  assert double(3) == 7, "doubling"
+  ensure double(2) == 5
+}
+`
+	if err := os.WriteFile(sourcePath, []byte(source), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+
+	cases := []struct {
+		name    string
+		options air.LowerOptions
+		want    []string
+	}{
+		{
+			name: "debug build checks assert",
+			want: []string{"assert failed at main.ard:6:3: doubling", "condition: double(3) == 7", "left: 6", "right: 7"},
+		},
+		{
+			name:    "release build still checks ensure",
+			options: air.LowerOptions{Release: true},
+			want:    []string{"ensure failed at main.ard:7:3: double(2) == 5", "left: 4", "right: 5"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			builtPath, err := buildGoBinary(sourcePath, filepath.Join(tempDir, "main-bin"), tc.options)
+			if err != nil {
+				t.Fatalf("build go backend: %v", err)
+			}
+			var stderr strings.Builder
+			cmd := exec.Command(builtPath)
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err == nil {
+				t.Fatalf("expected the program to fail")
+			}
+			for _, line := range tc.want {
+				if !strings.Contains(stderr.String(), line) {
+					t.Fatalf("stderr = %q, want it to contain %q", stderr.String(), line)
+				}
+			}
+		})
+	}
+}
+
+func TestParseBenchArgs(t *testing.T) {
+	opts, err := parseBenchArgs([]string{"demo.ard", "--runs", "20", "--json", "out.json", "--compare", "old.json", "--threshold", "2.5%"})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	want := benchOptions{inputPath: "demo.ard", runs: 20, jsonPath: "out.json", baseline: "old.json", threshold: 2.5}
+	if opts != want {
+		t.Fatalf("options = %+v, want %+v", opts, want)
+	}
+	defaults, err := parseBenchArgs([]string{"demo.ard"})
+	if err != nil {
+		t.Fatalf("parse defaults: %v", err)
+	}
+	if defaults.runs != 10 || defaults.threshold != 5 {
+		t.Fatalf("defaults = %+v, want 10 runs and a 5%% threshold", defaults)
+	}
+	for _, args := range [][]string{{}, {"demo.ard", "--runs", "1"}, {"demo.ard", "--compare"}, {"demo.ard", "--fast"}} {
+		if _, err := parseBenchArgs(args); err == nil {
+			t.Fatalf("parseBenchArgs(%q) should fail", args)
+		}
+	}
+}
+
+func TestBenchResultStatistics(t *testing.T) {
+	result := newBenchResult("demo.ard", []time.Duration{40, 10, 30, 20})
+	if result.Min != 10 || result.Median != 25 || result.Mean != 25 || result.Stddev != 12 {
+		t.Fatalf("result = %+v, want min 10, median 25, mean 25, stddev 12", result)
+	}
+}
+
+func TestCompareBench(t *testing.T) {
+	baseline := benchResult{Runs: 10, Median: 1000, Stddev: 10}
+	slower := compareBench(baseline, benchResult{Runs: 10, Median: 1200, Stddev: 10}, 5)
+	if !slower.Regression || slower.Change != 20 {
+		t.Fatalf("comparison = %+v, want a 20%% regression", slower)
+	}
+	withinThreshold := compareBench(baseline, benchResult{Runs: 10, Median: 1030, Stddev: 10}, 5)
+	if !withinThreshold.Significant || withinThreshold.Regression {
+		t.Fatalf("comparison = %+v, want a significant change under the threshold", withinThreshold)
+	}
+	noisy := compareBench(baseline, benchResult{Runs: 10, Median: 1200, Stddev: 1000}, 5)
+	if noisy.Significant || noisy.Regression {
+		t.Fatalf("comparison = %+v, want noise not to count as a regression", noisy)
+	}
+}
+
+func TestBenchCommandWritesAndComparesJSON(t *testing.T) {
+	tempDir := t.TempDir()
+	sourcePath := filepath.Join(tempDir, "main.ard")
+	if err := os.WriteFile(sourcePath, []byte("fn main() {}\n"), 0o644); err != nil {
+		t.Fatalf("write source: %v", err)
+	}
+	jsonPath := filepath.Join(tempDir, "bench.json")
+	var out strings.Builder
+	if ok, err := runBench(&out, benchOptions{inputPath: sourcePath, runs: 3, jsonPath: jsonPath, threshold: 5}); err != nil || !ok {
+		t.Fatalf("bench = %v, %v\n%s", ok, err, out.String())
+	}
+	data, err := os.ReadFile(jsonPath)
+	if err != nil {
+		t.Fatalf("read json: %v", err)
+	}
+	var result benchResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("decode json: %v", err)
+	}
+	if result.Runs != 3 || len(result.Samples) != 3 || result.Min <= 0 {
+		t.Fatalf("result = %+v, want 3 positive samples", result)
+	}
+
+	// A baseline far faster than any real run makes this one a regression.
+	result.Median, result.Stddev = 1, 0
+	data, _ = json.Marshal(result)
+	if err := os.WriteFile(jsonPath, data, 0o644); err != nil {
+		t.Fatalf("write baseline: %v", err)
+	}
+	out.Reset()
+	ok, err := runBench(&out, benchOptions{inputPath: sourcePath, runs: 3, baseline: jsonPath, threshold: 5})
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if ok || !strings.Contains(out.String(), "regression above 5.0%") {
+		t.Fatalf("compare = %v, output:\n%s", ok, out.String())
+	}
+}
+
 func TestParseTestArgs(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -547,32 +930,72 @@ func TestParseBuildArgs(t *testing.T) {
 		args       []string
 		path       string
 		out        string
+		target     string
+		release    bool
 		expectErr  bool
 		errMessage string
 	}{
 		{
-			name: "input only",
-			args: []string{"demo.ard"},
-			path: "demo.ard",
-			out:  "demo",
+			name:   "input only",
+			args:   []string{"demo.ard"},
+			path:   "demo.ard",
+			out:    "demo",
+			target: "go",
 		},
 		{
-			name: "nested input defaults to file basename",
-			args: []string{"samples/main.ard"},
-			path: "samples/main.ard",
-			out:  "main",
+			name:   "nested input defaults to file basename",
+			args:   []string{"samples/main.ard"},
+			path:   "samples/main.ard",
+			out:    "main",
+			target: "go",
 		},
 		{
-			name: "explicit output",
-			args: []string{"samples/main.ard", "--out", "demo"},
-			path: "samples/main.ard",
-			out:  "demo",
+			name:   "explicit output",
+			args:   []string{"samples/main.ard", "--out", "demo"},
+			path:   "samples/main.ard",
+			out:    "demo",
+			target: "go",
 		},
 		{
-			name:       "removed target flag",
-			args:       []string{"samples/main.ard", "--target", "go"},
+			name:   "js target",
+			args:   []string{"samples/main.ard", "--target", "js", "--out", "dist"},
+			path:   "samples/main.ard",
+			out:    "dist",
+			target: "js",
+		},
+		{
+			name:   "wasm target",
+			args:   []string{"samples/main.ard", "--target", "wasm"},
+			path:   "samples/main.ard",
+			out:    "main",
+			target: "wasm",
+		},
+		{
+			name:   "air target",
+			args:   []string{"samples/main.ard", "--target", "air"},
+			path:   "samples/main.ard",
+			out:    "main",
+			target: "air",
+		},
+		{
+			name:    "release build",
+			args:    []string{"--release", "samples/main.ard", "--target", "js"},
+			path:    "samples/main.ard",
+			out:     "main",
+			target:  "js",
+			release: true,
+		},
+		{
+			name:       "unsupported target",
+			args:       []string{"samples/main.ard", "--target", "llvm"},
 			expectErr:  true,
-			errMessage: "unknown flag: --target",
+			errMessage: "unsupported build target: llvm",
+		},
+		{
+			name:       "target requires value",
+			args:       []string{"samples/main.ard", "--target"},
+			expectErr:  true,
+			errMessage: "--target requires a value",
 		},
 		{
 			name:       "unknown flag",
@@ -584,7 +1007,7 @@ func TestParseBuildArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path, out, err := parseBuildArgs(tt.args)
+			path, out, target, release, err := parseBuildArgs(tt.args)
 			if tt.expectErr {
 				if err == nil {
 					t.Fatalf("expected error %q, got nil", tt.errMessage)
@@ -604,6 +1027,12 @@ func TestParseBuildArgs(t *testing.T) {
 			if out != tt.out {
 				t.Fatalf("expected output %q, got %q", tt.out, out)
 			}
+			if target != tt.target {
+				t.Fatalf("expected target %q, got %q", tt.target, target)
+			}
+			if release != tt.release {
+				t.Fatalf("expected release %t, got %t", tt.release, release)
+			}
 		})
 	}
 }
@@ -613,6 +1042,8 @@ func TestParseFormatArgs(t *testing.T) {
 		args       []string
 		path       string
 		checkOnly  bool
+		width      int
+		fixImports bool
 		expectErr  bool
 		errMessage string
 	}{
@@ -628,6 +1059,30 @@ func TestParseFormatArgs(t *testing.T) {
 			path:      "samples/hello.ard",
 			checkOnly: true,
 		},
+		{
+			name:  "width",
+			args:  []string{"samples/hello.ard", "--width", "80"},
+			path:  "samples/hello.ard",
+			width: 80,
+		},
+		{
+			name:       "fix imports",
+			args:       []string{"--fix-imports", "samples/hello.ard"},
+			path:       "samples/hello.ard",
+			fixImports: true,
+		},
+		{
+			name:       "width without a value",
+			args:       []string{"samples/hello.ard", "--width"},
+			expectErr:  true,
+			errMessage: "--width requires a value",
+		},
+		{
+			name:       "non-numeric width",
+			args:       []string{"--width", "wide", "samples/hello.ard"},
+			expectErr:  true,
+			errMessage: "--width must be a positive integer",
+		},
 		{
 			name:       "unknown flag",
 			args:       []string{"--watch", "samples/hello.ard"},
@@ -650,7 +1105,7 @@ func TestParseFormatArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path, checkOnly, err := parseFormatArgs(tt.args)
+			path, checkOnly, overrides, err := parseFormatArgs(tt.args)
 			if tt.expectErr {
 				if err == nil {
 					t.Fatalf("expected error %q, got nil", tt.errMessage)
@@ -670,6 +1125,12 @@ func TestParseFormatArgs(t *testing.T) {
 			if checkOnly != tt.checkOnly {
 				t.Fatalf("expected checkOnly %t, got %t", tt.checkOnly, checkOnly)
 			}
+			if overrides.MaxWidth != tt.width {
+				t.Fatalf("expected width %d, got %d", tt.width, overrides.MaxWidth)
+			}
+			if overrides.FixImports != tt.fixImports {
+				t.Fatalf("expected fixImports %t, got %t", tt.fixImports, overrides.FixImports)
+			}
 		})
 	}
 }
@@ -681,7 +1142,7 @@ func TestFormatFile(t *testing.T) {
 			t.Fatalf("failed to seed test file: %v", err)
 		}
 
-		changed, err := formatFile(path, false)
+		changed, err := formatFile(path, false, formatter.Options{})
 		if err != nil {
 			t.Fatalf("did not expect error: %v", err)
 		}
@@ -706,7 +1167,7 @@ func TestFormatFile(t *testing.T) {
 			t.Fatalf("failed to seed test file: %v", err)
 		}
 
-		changed, err := formatFile(path, true)
+		changed, err := formatFile(path, true, formatter.Options{})
 		if err != nil {
 			t.Fatalf("did not expect error: %v", err)
 		}
@@ -722,6 +1183,30 @@ func TestFormatFile(t *testing.T) {
 			t.Fatalf("expected file to stay unchanged, got %q", string(out))
 		}
 	})
+
+	t.Run("formats the valid regions of a file with parse errors", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "example.ard")
+		if err := os.WriteFile(path, []byte("let x =   1\n\nfn broken( {\n}\n"), 0o644); err != nil {
+			t.Fatalf("failed to seed test file: %v", err)
+		}
+
+		changed, err := formatFile(path, false, formatter.Options{})
+		if err == nil {
+			t.Fatalf("expected the parse errors to be reported")
+		}
+		if !changed {
+			t.Fatalf("expected file to change")
+		}
+
+		out, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read formatted file: %v", err)
+		}
+		if string(out) != "let x = 1\n\nfn broken( {\n}\n" {
+			t.Fatalf("expected partially formatted content, got %q", string(out))
+		}
+	})
 }
 func TestFormatPath(t *testing.T) {
 	t.Run("formats directories recursively", func(t *testing.T) {
@@ -740,7 +1225,7 @@ func TestFormatPath(t *testing.T) {
 			t.Fatalf("failed to seed second file: %v", err)
 		}
 
-		changedPaths, err := formatPath(dir, false)
+		changedPaths, err := formatPath(dir, false, formatter.Options{})
 		if err != nil {
 			t.Fatalf("did not expect error: %v", err)
 		}
@@ -981,7 +1466,7 @@ test fn private_access() Void!Str {
 	if ok {
 		t.Fatalf("expected private access test behavior to fail\n%s", output)
 	}
-	if !strings.Contains(output, "Undefined qualified member") || !strings.Contains(output, "`utils::private_helper` could not be resolved") {
+	if !strings.Contains(output, "Private declaration") || !strings.Contains(output, "`utils::private_helper` is private to module `demo/utils`") {
 		t.Fatalf("unexpected output:\n%s", output)
 	}
 }
@@ -1151,3 +1636,162 @@ func TestRemoveDependencyFromManifestMissing(t *testing.T) {
 		t.Fatalf("manifest changed unexpectedly:\n%s", data)
 	}
 }
+
+func TestMigratePath(t *testing.T) {
+	dir := t.TempDir()
+	manifest := "name = \"demo\"\nard = \">= 0.1.0\"\nlanguage = \"0.21\"\n"
+	if err := os.WriteFile(filepath.Join(dir, "ard.toml"), []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	legacy := filepath.Join(dir, "legacy.ard")
+	current := filepath.Join(dir, "current.ard")
+	if err := os.WriteFile(legacy, []byte("fn bump(mut m: Model) {\n  m.n = 1\n}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unformatted := "let x = 1  \n"
+	if err := os.WriteFile(current, []byte(unformatted), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	pending, err := migratePath(dir, true)
+	if err != nil {
+		t.Fatalf("migratePath --check: %v", err)
+	}
+	if len(pending) != 1 || pending[0] != legacy {
+		t.Fatalf("pending = %v, want [%s]", pending, legacy)
+	}
+
+	if _, err := migratePath(dir, false); err != nil {
+		t.Fatalf("migratePath: %v", err)
+	}
+	out, err := os.ReadFile(legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "fn bump(m: mut Model) {\n  m.n = 1\n}\n" {
+		t.Fatalf("migrated source = %q", string(out))
+	}
+	out, err = os.ReadFile(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != unformatted {
+		t.Fatalf("files without deprecated syntax must not be rewritten, got %q", string(out))
+	}
+	out, err = os.ReadFile(filepath.Join(dir, "ard.toml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "language = \"0.22\"") {
+		t.Fatalf("expected ard.toml to pin the current language, got %q", string(out))
+	}
+}
+
+func TestSetManifestLanguageAddsEntry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ard.toml")
+	if err := os.WriteFile(path, []byte("name = \"demo\"\nard = \">= 0.1.0\"\n\n[dependencies]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := setManifestLanguage(path, version.Semver{Major: 0, Minor: 22}); err != nil {
+		t.Fatalf("setManifestLanguage: %v", err)
+	}
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name = \"demo\"\nard = \">= 0.1.0\"\nlanguage = \"0.22\"\n\n[dependencies]\n"
+	if string(out) != want {
+		t.Fatalf("manifest = %q, want %q", string(out), want)
+	}
+}
+
+func TestSetManifestLanguageOnlyEditsTopLevelTable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ard.toml")
+	manifest := "name = \"demo\"\nlanguage = \"0.21\"\n\n\n[dependencies.util]\nlanguage = \"0.20\"\n"
+	if err := os.WriteFile(path, []byte(manifest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := setManifestLanguage(path, version.Semver{Major: 0, Minor: 22}); err != nil {
+		t.Fatalf("setManifestLanguage: %v", err)
+	}
+	out, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "name = \"demo\"\nlanguage = \"0.22\"\n\n\n[dependencies.util]\nlanguage = \"0.20\"\n"
+	if string(out) != want {
+		t.Fatalf("manifest = %q, want %q", string(out), want)
+	}
+
+	tableOnly := "name = \"demo\"\nard = \">= 0.1.0\"\n\n[tool]\nlanguage = \"other\"\n"
+	if err := os.WriteFile(path, []byte(tableOnly), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := setManifestLanguage(path, version.Semver{Major: 0, Minor: 22}); err != nil {
+		t.Fatalf("setManifestLanguage: %v", err)
+	}
+	out, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want = "name = \"demo\"\nard = \">= 0.1.0\"\nlanguage = \"0.22\"\n\n[tool]\nlanguage = \"other\"\n"
+	if string(out) != want {
+		t.Fatalf("manifest = %q, want %q", string(out), want)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	var list bytes.Buffer
+	if err := explain(&list, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(list.String(), "non_exhaustive_match") {
+		t.Fatalf("list is missing non_exhaustive_match:\n%s", list.String())
+	}
+
+	var output bytes.Buffer
+	if err := explain(&output, []string{"non_exhaustive_match"}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(output.String(), "non_exhaustive_match: Non-exhaustive match\n") {
+		t.Fatalf("output = %q", output.String())
+	}
+
+	if err := explain(&output, []string{"not_a_code"}); err == nil || !strings.Contains(err.Error(), `no explanation for "not_a_code"`) {
+		t.Fatalf("err = %v", err)
+	}
+	if err := explain(&output, []string{"type_mismatch", "extra"}); err == nil || err.Error() != "unexpected argument: extra" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestInspectArtifactDescribesAIRPrograms(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "main.ard")
+	if err := os.WriteFile(source, []byte("fn main() {}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	built, err := buildAIRProgram(source, filepath.Join(dir, "app"), air.LowerOptions{})
+	if err != nil {
+		t.Fatalf("build: %v", err)
+	}
+	if filepath.Ext(built) != ".air" {
+		t.Fatalf("built path = %q, want an .air file", built)
+	}
+
+	var output bytes.Buffer
+	if err := inspectArtifact(&output, built); err != nil {
+		t.Fatalf("inspect: %v", err)
+	}
+	for _, want := range []string{"kind:     AIR (format 1)\n", "compiler: ard " + version.Get() + "\n", "entry:    main\n", "modules:  main", "sha256:   "} {
+		if !strings.Contains(output.String(), want) {
+			t.Fatalf("inspect output is missing %q:\n%s", want, output.String())
+		}
+	}
+	if isBuiltBinary(built) || isBuiltBinary(source) {
+		t.Fatal("isBuiltBinary accepted a file that is not a built binary")
+	}
+	if err := inspectArtifact(&output, source); err == nil {
+		t.Fatal("inspect accepted a source file")
+	}
+}
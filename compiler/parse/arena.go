@@ -0,0 +1,15 @@
+package parse
+
+import "github.com/akonwi/ard/arena"
+
+// nodeArena holds a slab for each of the nodes a file has the most of. It
+// belongs to one parser, so a file's nodes sit next to each other and no two
+// files share a slab.
+type nodeArena struct {
+	identifiers     arena.Slab[Identifier]
+	numbers         arena.Slab[NumLiteral]
+	strs            arena.Slab[StrLiteral]
+	calls           arena.Slab[FunctionCall]
+	instanceMethods arena.Slab[InstanceMethod]
+	binaries        arena.Slab[BinaryExpression]
+}
@@ -0,0 +1,40 @@
+package parse
+
+import "testing"
+
+func TestAssertStatements(t *testing.T) {
+	t.Run("assert with a message", func(t *testing.T) {
+		program := parseOK(t, "fn main() {\n  assert total  ==  3, \"bad total\"\n}\n")
+		fn := program.Statements[0].(*FunctionDeclaration)
+		stmt, ok := fn.Body[0].(*Assert)
+		if !ok {
+			t.Fatalf("expected *Assert, got %T", fn.Body[0])
+		}
+		if stmt.Ensure || stmt.Message == nil {
+			t.Fatalf("expected an assert with a message, got %#v", stmt)
+		}
+		if stmt.Source != "total == 3" {
+			t.Fatalf("Source = %q, want %q", stmt.Source, "total == 3")
+		}
+	})
+	t.Run("ensure without a message", func(t *testing.T) {
+		program := parseOK(t, "fn main() {\n  ensure (ready)\n}\n")
+		fn := program.Statements[0].(*FunctionDeclaration)
+		stmt, ok := fn.Body[0].(*Assert)
+		if !ok {
+			t.Fatalf("expected *Assert, got %T", fn.Body[0])
+		}
+		if !stmt.Ensure || stmt.Message != nil {
+			t.Fatalf("expected an ensure without a message, got %#v", stmt)
+		}
+	})
+	t.Run("assert stays an ordinary identifier", func(t *testing.T) {
+		program := parseOK(t, "fn main() {\n  let assert = 1\n  assert(x)\n  assert == 1\n}\n")
+		fn := program.Statements[0].(*FunctionDeclaration)
+		for i, stmt := range fn.Body {
+			if _, ok := stmt.(*Assert); ok {
+				t.Fatalf("statement %d parsed as *Assert", i)
+			}
+		}
+	})
+}
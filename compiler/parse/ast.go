@@ -2,6 +2,7 @@ package parse
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 )
 
@@ -63,9 +64,12 @@ const (
 )
 
 type Import struct {
-	Path         string
-	Name         string
-	Kind         ImportKind
+	Path string
+	Name string
+	Kind ImportKind
+	// ReExport is the symbol a `pub use path::Symbol` statement makes
+	// available to importers of this module. It is empty for plain imports.
+	ReExport     string
 	PathLocation Location
 	Location
 }
@@ -86,9 +90,36 @@ func (p Import) Alias() string {
 	return p.Path
 }
 
+// Pragma is a module-level `#name(arg, ...)` directive that opts the module
+// into stricter checking, such as `#deny(warnings)`.
+type Pragma struct {
+	Name         string
+	Args         []string
+	NameLocation Location
+	Location
+}
+
+func (p Pragma) String() string {
+	return fmt.Sprintf("#%s(%s)", p.Name, strings.Join(p.Args, ", "))
+}
+
 type Program struct {
+	Pragmas    []Pragma
 	Imports    []Import
 	Statements []Statement
+	// Legacy holds the deprecated spellings the program was parsed with, as
+	// accepted by ParseResult.AllowLegacy.
+	Legacy []LegacySyntax
+}
+
+// HasPragma reports whether the program declares `#name(arg)`.
+func (p *Program) HasPragma(name string, arg string) bool {
+	for _, pragma := range p.Pragmas {
+		if pragma.Name == name && slices.Contains(pragma.Args, arg) {
+			return true
+		}
+	}
+	return false
 }
 
 type Break struct{ Location }
@@ -111,8 +142,13 @@ type VariableDeclaration struct {
 	Name         string
 	NameLocation Location
 	Mutable      bool
+	Const        bool
+	Private      bool
 	Value        Expression
 	Type         DeclaredType
+	// Else is the block of `let name = value else { ... }`, which runs when
+	// value is an empty Maybe or an error Result. It is nil otherwise.
+	Else []Statement
 }
 
 type DeclaredType interface {
@@ -324,10 +360,30 @@ func (r ResultType) IsNullable() bool {
 	return r.nullable
 }
 
+// UnionType is an inline union such as `Int | Str`, written where a type is
+// expected without declaring a named union first.
+type UnionType struct {
+	Location
+	Members  []DeclaredType
+	nullable bool
+}
+
+func (u UnionType) GetName() string {
+	return "Union"
+}
+func (u UnionType) IsNullable() bool {
+	return u.nullable
+}
+
 func (v VariableDeclaration) String() string {
 	binding := "let"
 	if v.Mutable {
 		binding = "mut"
+	} else if v.Const {
+		binding = "const"
+	}
+	if v.Private {
+		binding = "private " + binding
 	}
 	return fmt.Sprintf("%s %s: %s", binding, v.Name, v.Type)
 }
@@ -339,6 +395,31 @@ type VariableAssignment struct {
 	Value    Expression
 }
 
+// Assert is an `assert` or `ensure` statement. Source is the condition as
+// written, for the failure message.
+type Assert struct {
+	Location
+	Ensure    bool
+	Condition Expression
+	// Message is nil when the statement has no `, message` part.
+	Message Expression
+	Source  string
+}
+
+func (a Assert) Keyword() string {
+	if a.Ensure {
+		return "ensure"
+	}
+	return "assert"
+}
+
+func (a Assert) String() string {
+	if a.Message != nil {
+		return fmt.Sprintf("%s %s, %s", a.Keyword(), a.Condition, a.Message)
+	}
+	return fmt.Sprintf("%s %s", a.Keyword(), a.Condition)
+}
+
 type Defer struct {
 	Location
 	Expr Expression
@@ -369,15 +450,26 @@ func (p Parameter) String() string {
 
 type FunctionDeclaration struct {
 	Location
-	Name       string
-	TypeParams []string // Legacy/constructed generic parameter metadata; source function declaration lists are rejected.
-	Mutates    bool
-	IsTest     bool
-	Parameters []Parameter
-	ReturnType DeclaredType
-	Body       []Statement
-	Private    bool
-	Comments   []Comment // Comments found within the function declaration
+	Name string
+	// NameLocation is the span of the function's name, or of the `Type::name`
+	// path of a static function.
+	NameLocation Location
+	TypeParams   []string // Legacy/constructed generic parameter metadata; source function declaration lists are rejected.
+	Mutates      bool
+	IsTest       bool
+	Parameters   []Parameter
+	ReturnType   DeclaredType
+	Body         []Statement
+	Private      bool
+	Comments     []Comment // Comments found within the function declaration
+	Deprecated   *Deprecation
+}
+
+// Deprecation is a `@deprecated("note")` attribute on the declaration that
+// follows it. The note usually names the replacement.
+type Deprecation struct {
+	Location
+	Note string
 }
 
 func (f FunctionDeclaration) String() string {
@@ -411,11 +503,16 @@ type StructDefinition struct {
 	Fields     []StructField
 	Private    bool
 	Comments   []Comment // Comments found within the struct definition
+	Deprecated *Deprecation
 }
 
 type StructField struct {
-	Name Identifier
-	Type DeclaredType
+	Name    Identifier
+	Type    DeclaredType
+	Private bool
+	// Default is the value used when an instance omits the field, as in
+	// `retries: Int = 3`; nil means the field must be provided.
+	Default Expression
 }
 
 func (s StructDefinition) String() string {
@@ -469,7 +566,10 @@ type StructInstance struct {
 	Name       Identifier
 	TypeArgs   []DeclaredType
 	Properties []StructValue
-	Comments   []Comment // Comments found within the struct instance
+	// Base is the struct named by `..base`, which supplies every field the
+	// literal does not list; nil when there is none.
+	Base     Expression
+	Comments []Comment // Comments found within the struct instance
 }
 
 func (s StructInstance) String() string {
@@ -479,6 +579,9 @@ func (s StructInstance) String() string {
 type EnumVariant struct {
 	Name  string
 	Value Expression // nil means auto-assign (0 or previous+1)
+	// Payload lists the types of the variant's associated data, as in
+	// `Click(Int, Int)`; it is empty for plain variants.
+	Payload []DeclaredType
 }
 
 type EnumDefinition struct {
@@ -499,7 +602,11 @@ func (e EnumDefinition) String() string {
 type WhileLoop struct {
 	Location
 	Condition Expression
-	Body      []Statement
+	// Let is the name bound by `while let name = value`, where Condition is
+	// the Maybe or Result unwrapped on each iteration. It is nil for a plain
+	// condition.
+	Let  *Identifier
+	Body []Statement
 }
 
 func (w WhileLoop) String() string {
@@ -512,10 +619,15 @@ type RangeLoop struct {
 	Cursor2 Identifier
 	Start   Expression
 	End     Expression
-	Body    []Statement
+	// Step is the expression after `by`, or nil when the loop counts up by 1.
+	Step Expression
+	Body []Statement
 }
 
 func (r RangeLoop) String() string {
+	if r.Step != nil {
+		return fmt.Sprintf("for range %s..%s by %s", r.Start, r.End, r.Step)
+	}
 	return fmt.Sprintf("for range %s..%s", r.Start, r.End)
 }
 
@@ -546,8 +658,11 @@ func (f ForLoop) String() string {
 type IfStatement struct {
 	Location
 	Condition Expression
-	Body      []Statement
-	Else      Statement
+	// Let is the name bound by `if let name = value`, where Condition is the
+	// Maybe or Result being unwrapped. It is nil for a plain condition.
+	Let  *Identifier
+	Body []Statement
+	Else Statement
 }
 
 func (i IfStatement) String() string {
@@ -692,6 +807,60 @@ func (c ChainedComparison) String() string {
 	return fmt.Sprintf("ChainedComparison(%v %v)", c.Operands, c.Operators)
 }
 
+// PipeExpression is `value |> call`. Call is a function call, or a bare
+// function name, that value is passed to as the first argument.
+type PipeExpression struct {
+	Location
+	Value Expression
+	Call  Expression
+}
+
+func (p PipeExpression) String() string {
+	return fmt.Sprintf("(%v |> %v)", p.Value, p.Call)
+}
+
+// Desugar returns the call p stands for, with Value inserted as its first
+// argument. A bare function name becomes a call with Value as its only one.
+func (p *PipeExpression) Desugar() Expression {
+	value := Argument{Location: p.Value.GetLocation(), Value: p.Value}
+	withValue := func(call FunctionCall) FunctionCall {
+		call.Args = append([]Argument{value}, call.Args...)
+		return call
+	}
+	switch call := p.Call.(type) {
+	case *FunctionCall:
+		desugared := withValue(*call)
+		return &desugared
+	case *StaticFunction:
+		return &StaticFunction{Location: call.Location, Target: call.Target, Function: withValue(call.Function)}
+	case *InstanceMethod:
+		return &InstanceMethod{Location: call.Location, Target: call.Target, Method: withValue(call.Method)}
+	case *Identifier:
+		return &FunctionCall{Location: call.Location, Name: call.Name, Args: []Argument{value}}
+	case *StaticProperty:
+		if name, ok := call.Property.(*Identifier); ok {
+			return &StaticFunction{
+				Location: call.Location,
+				Target:   call.Target,
+				Function: FunctionCall{Location: name.Location, Name: name.Name, Args: []Argument{value}},
+			}
+		}
+	}
+	return p.Call
+}
+
+// AsExpression is `value as Type`, which narrows a union value to one of its
+// member types.
+type AsExpression struct {
+	Location
+	Value Expression
+	Type  DeclaredType
+}
+
+func (a AsExpression) String() string {
+	return fmt.Sprintf("(%v as %v)", a.Value, a.Type.GetName())
+}
+
 type RangeExpression struct {
 	Location
 	Start, End Expression
@@ -713,6 +882,9 @@ func (i Identifier) String() string {
 type StrLiteral struct {
 	Location
 	Value string
+	// Template marks the literal template of a Str::format call. It is read
+	// as written, so its braces are placeholders rather than interpolations.
+	Template bool
 }
 
 func (s StrLiteral) String() string {
@@ -810,6 +982,38 @@ func (m MatchCase) String() string {
 	return fmt.Sprintf("MatchCase(%s)", m.Pattern)
 }
 
+// OrPattern combines several patterns into one match arm, as in
+// `1 | 2 | 3 => ...`. The arm runs when any of its patterns matches.
+type OrPattern struct {
+	Location
+	Patterns []Expression
+}
+
+func (o OrPattern) String() string {
+	return fmt.Sprintf("OrPattern(%d)", len(o.Patterns))
+}
+
+// StructPattern matches a struct value field by field in a match arm, as in
+// `Point{x: 0, y}`. Fields that are not listed are not tested.
+type StructPattern struct {
+	Location
+	Name   Identifier
+	Fields []StructPatternField
+}
+
+func (s StructPattern) String() string {
+	return fmt.Sprintf("StructPattern(%s)", s.Name.Name)
+}
+
+// StructPatternField is one `name` or `name: pattern` entry of a
+// StructPattern. A nil Pattern binds the field to its own name; otherwise
+// Pattern is `_`, a name to bind, a constant the field must equal, or a nested
+// StructPattern.
+type StructPatternField struct {
+	Name    Identifier
+	Pattern Expression
+}
+
 // SelectExpression multiplexes over several channel operations, running the
 // arm whose operation can proceed first. See ADR 0032.
 type SelectExpression struct {
@@ -861,7 +1065,6 @@ func (c ConditionalMatchCase) String() string {
 
 type Try struct {
 	Location
-	keyword    Identifier
 	Expression Expression
 	CatchVar   *Identifier // nil if no catch clause
 	CatchBlock []Statement // nil if no catch clause
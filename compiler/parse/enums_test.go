@@ -53,6 +53,23 @@ func TestEnumDefinitions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Variants with associated data",
+			input: "enum Event {\n  Click(Int, Int),\n  Key(Str),\n  Quit,\n}",
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&EnumDefinition{
+						Name: "Event",
+						Variants: []EnumVariant{
+							{Name: "Click", Payload: []DeclaredType{&IntType{}, &IntType{}}},
+							{Name: "Key", Payload: []DeclaredType{&StringType{}}},
+							{Name: "Quit"},
+						},
+					},
+				},
+			},
+		},
 		// Error cases
 		{
 			name:     "Missing enum name",
@@ -64,6 +81,11 @@ func TestEnumDefinitions(t *testing.T) {
 			input:    "enum Color A, B }",
 			wantErrs: []string{"Expected '{'"},
 		},
+		{
+			name:     "Unclosed variant payload",
+			input:    "enum Event { Key(Str Quit }",
+			wantErrs: []string{"Expected ',' or ')' in variant payload"},
+		},
 		{
 			name:     "Empty first variant",
 			input:    "enum Color { , B }",
@@ -127,3 +149,45 @@ func TestMatchingOnEnums(t *testing.T) {
 		},
 	})
 }
+
+func TestOrPatterns(t *testing.T) {
+	runTests(t, []test{
+		{
+			name: "patterns joined with |",
+			input: `
+					match light {
+						Color::Yellow | Color::Green => "Go",
+						_ => "Stop",
+					}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&MatchExpression{
+						Subject: &Identifier{Name: "light"},
+						Cases: []MatchCase{
+							{
+								Pattern: &OrPattern{
+									Patterns: []Expression{
+										&StaticProperty{
+											Target:   &Identifier{Name: "Color"},
+											Property: &Identifier{Name: "Yellow"},
+										},
+										&StaticProperty{
+											Target:   &Identifier{Name: "Color"},
+											Property: &Identifier{Name: "Green"},
+										},
+									},
+								},
+								Body: []Statement{&StrLiteral{Value: "Go"}},
+							},
+							{
+								Pattern: &Identifier{Name: "_"},
+								Body:    []Statement{&StrLiteral{Value: "Stop"}},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
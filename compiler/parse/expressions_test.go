@@ -109,6 +109,42 @@ func TestGenericCallLookaheadDoesNotLeakTypeErrorsIntoComparisons(t *testing.T)
 		},
 	})
 }
+func TestNumberLiterals(t *testing.T) {
+	runTests(t, []test{
+		{
+			name:  "Radix prefixes and exponents",
+			input: "[0xFF, 0b1010, 0o17, 0xdead_beef, 1.5e9, 2E-3, 1e+3]",
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&ListLiteral{Items: []Expression{
+						&NumLiteral{Value: "0xFF"},
+						&NumLiteral{Value: "0b1010"},
+						&NumLiteral{Value: "0o17"},
+						&NumLiteral{Value: "0xdead_beef"},
+						&NumLiteral{Value: "1.5e9"},
+						&NumLiteral{Value: "2E-3"},
+						&NumLiteral{Value: "1e+3"},
+					}},
+				},
+			},
+		},
+		{
+			name:  "Radix literals in a range",
+			input: "0x0..0b11",
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&RangeExpression{
+						Start: &NumLiteral{Value: "0x0"},
+						End:   &NumLiteral{Value: "0b11"},
+					},
+				},
+			},
+		},
+	})
+}
+
 func TestListLiterals(t *testing.T) {
 	runTests(t, []test{
 		{
@@ -850,6 +886,37 @@ func TestInterpolatedStrings(t *testing.T) {
 		},
 	})
 }
+func TestOnlyStrFormatTemplatesAreReadRaw(t *testing.T) {
+	result := Parse([]byte(`"{count} {url::escape(name)}"
+Str::format("{0:>8} {name} {1:.2}", a, "{count}", name: b)
+`), "test.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("unexpected parse error: %s", result.Errors[0].Message)
+	}
+	str, ok := result.Program.Statements[0].(*InterpolatedStr)
+	if !ok || len(str.Chunks) < 2 {
+		t.Fatalf("ordinary string = %#v, want it interpolated", result.Program.Statements[0])
+	}
+	if _, ok := str.Chunks[1].(*Identifier); !ok {
+		t.Fatalf("chunk %#v, want the interpolated identifier", str.Chunks[1])
+	}
+	call, ok := result.Program.Statements[1].(*StaticFunction)
+	if !ok {
+		t.Fatalf("parsed %T, want *StaticFunction", result.Program.Statements[1])
+	}
+	template, ok := call.Function.Args[0].Value.(*StrLiteral)
+	if !ok || !template.Template || template.Value != "{0:>8} {name} {1:.2}" {
+		t.Fatalf("template = %#v, want the raw template text", call.Function.Args[0].Value)
+	}
+	if _, ok := call.Function.Args[2].Value.(*InterpolatedStr); !ok {
+		t.Fatalf("argument = %#v, want other arguments interpolated", call.Function.Args[2].Value)
+	}
+
+	result = Parse([]byte(`"{0:>8}"`), "test.ard")
+	if len(result.Errors) == 0 {
+		t.Fatal("a format spec outside Str::format parsed as an interpolation")
+	}
+}
 func TestInterpolatedStringFunctionCallStringArgDoesNotHang(t *testing.T) {
 	assertParseCompletes(t, `"{wrap(\"arg\")}"`, false)
 }
@@ -1131,3 +1198,137 @@ func TestMutRefExpressions(t *testing.T) {
 		},
 	})
 }
+
+func TestPipeExpressions(t *testing.T) {
+	runTests(t, []test{
+		{
+			name:  "stages apply left to right",
+			input: `let n = value |> double |> add(1)`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&VariableDeclaration{
+						Name: "n",
+						Value: &PipeExpression{
+							Value: &PipeExpression{
+								Value: &Identifier{Name: "value"},
+								Call:  &Identifier{Name: "double"},
+							},
+							Call: &FunctionCall{
+								Name:     "add",
+								Args:     []Argument{{Value: &NumLiteral{Value: "1"}}},
+								Comments: []Comment{},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "pipe binds looser than or",
+			input: "a or b\n  |> check()",
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&PipeExpression{
+						Value: &BinaryExpression{
+							Operator: Or,
+							Left:     &Identifier{Name: "a"},
+							Right:    &Identifier{Name: "b"},
+						},
+						Call: &FunctionCall{Name: "check", Args: []Argument{}, Comments: []Comment{}},
+					},
+				},
+			},
+		},
+		{
+			name:     "the right side must be a call",
+			input:    `let n = value |> 1`,
+			wantErrs: []string{"Expected a function call after '|>'"},
+		},
+	})
+}
+
+func TestAsExpressions(t *testing.T) {
+	runTests(t, []test{
+		{
+			name:  "as narrows to a type",
+			input: `let c = shape as Circle`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&VariableDeclaration{
+						Name: "c",
+						Value: &AsExpression{
+							Value: &Identifier{Name: "shape"},
+							Type:  &CustomType{Name: "Circle"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:  "as binds tighter than arithmetic",
+			input: "a + b as Int",
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&BinaryExpression{
+						Operator: Plus,
+						Left:     &Identifier{Name: "a"},
+						Right: &AsExpression{
+							Value: &Identifier{Name: "b"},
+							Type:  &IntType{},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "as needs a type",
+			input:    `let c = shape as`,
+			wantErrs: []string{"Expected a type after 'as'"},
+		},
+	})
+}
+
+func TestPipeExpressionDesugar(t *testing.T) {
+	value := &Identifier{Name: "value"}
+	tests := []struct {
+		name string
+		call Expression
+		want string
+	}{
+		{name: "function call", call: &FunctionCall{Name: "add", Args: []Argument{{Value: &NumLiteral{Value: "1"}}}}, want: "add(value, 1)"},
+		{name: "bare function", call: &Identifier{Name: "double"}, want: "double(value)"},
+		{name: "module function", call: &StaticFunction{Target: &Identifier{Name: "list"}, Function: FunctionCall{Name: "map"}}, want: "list::map(value)"},
+		{name: "bare module function", call: &StaticProperty{Target: &Identifier{Name: "list"}, Property: &Identifier{Name: "size"}}, want: "list::size(value)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := (&PipeExpression{Value: value, Call: tt.call}).Desugar()
+			if rendered := renderCall(got); rendered != tt.want {
+				t.Fatalf("Desugar() = %s, want %s", rendered, tt.want)
+			}
+		})
+	}
+}
+
+func renderCall(expr Expression) string {
+	var target string
+	var call FunctionCall
+	switch e := expr.(type) {
+	case *FunctionCall:
+		call = *e
+	case *StaticFunction:
+		target = e.Target.String() + "::"
+		call = e.Function
+	default:
+		return expr.String()
+	}
+	args := make([]string, len(call.Args))
+	for i, arg := range call.Args {
+		args[i] = arg.Value.String()
+	}
+	return fmt.Sprintf("%s%s(%s)", target, call.Name, strings.Join(args, ", "))
+}
@@ -2,8 +2,31 @@ package parse
 
 import (
 	"testing"
+
+	"github.com/akonwi/ard/version"
 )
 
+func TestFunctionNameLocation(t *testing.T) {
+	result := Parse([]byte("fn load() {}\nfn User::guest() {}\n"), "test.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse error: %s", result.Errors[0].Message)
+	}
+	fn, ok := result.Program.Statements[0].(*FunctionDeclaration)
+	if !ok {
+		t.Fatalf("statement = %T, want *FunctionDeclaration", result.Program.Statements[0])
+	}
+	if got, want := fn.NameLocation.Start, (Point{Row: 1, Col: 4}); got != want {
+		t.Fatalf("name start = %v, want %v", got, want)
+	}
+	static, ok := result.Program.Statements[1].(*StaticFunctionDeclaration)
+	if !ok {
+		t.Fatalf("statement = %T, want *StaticFunctionDeclaration", result.Program.Statements[1])
+	}
+	if got, want := static.NameLocation, static.Path.Location; got != want {
+		t.Fatalf("static name location = %v, want path location %v", got, want)
+	}
+}
+
 func TestFunctionDeclaration(t *testing.T) {
 	tests := []test{
 		{
@@ -620,3 +643,73 @@ func TestIncompleteFunctionCallDoesNotHang(t *testing.T) {
 		t.Fatal("expected parse error for incomplete function call")
 	}
 }
+
+func TestLegacyMutParameterSpelling(t *testing.T) {
+	result := Parse([]byte("fn bump(mut m: Model) {\n  m.n = 1\n}\n"), "test.ard")
+	if len(result.Errors) != 1 || len(result.Legacy) != 1 {
+		t.Fatalf("expected one legacy error, got errors %v and legacy %v", result.Errors, result.Legacy)
+	}
+
+	current := result
+	current.Errors = append([]ParseError(nil), result.Errors...)
+	if allowed := current.AllowLegacy(version.Semver{Major: 0, Minor: 22}); len(allowed) != 0 || len(current.Errors) != 1 {
+		t.Fatalf("language 0.22 must keep rejecting the spelling, got allowed %v and errors %v", allowed, current.Errors)
+	}
+
+	if allowed := result.AllowLegacy(version.Semver{Major: 0, Minor: 21}); len(allowed) != 1 || len(result.Errors) != 0 {
+		t.Fatalf("language 0.21 must accept the spelling, got allowed %v and errors %v", allowed, result.Errors)
+	}
+	fn := result.Program.Statements[0].(*FunctionDeclaration)
+	mutable, ok := fn.Parameters[0].Type.(*MutableType)
+	if !ok {
+		t.Fatalf("parameter type = %T, want *MutableType", fn.Parameters[0].Type)
+	}
+	if mutable.Inner.GetName() != "Model" {
+		t.Fatalf("inner type = %s, want Model", mutable.Inner.GetName())
+	}
+}
+
+func TestDeprecatedAttribute(t *testing.T) {
+	source := "@deprecated(\"use add\")\nfn plus(a: Int, b: Int) Int { a + b }\n\n@deprecated(\"use Point\")\nprivate struct Pt { x: Int }\n\nimpl Pt {\n  @deprecated(\"read x\")\n  fn get() Int { self.x }\n}\n\n@deprecated(\"use Pt::new\")\nfn Pt::make() Pt { Pt{x: 0} }\n"
+	result := Parse([]byte(source), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	stmts := result.Program.Statements
+	fn, ok := stmts[0].(*FunctionDeclaration)
+	if !ok || fn.Deprecated == nil || fn.Deprecated.Note != "use add" {
+		t.Fatalf("function = %#v", stmts[0])
+	}
+	if fn.Location.Start != (Point{Row: 1, Col: 1}) {
+		t.Fatalf("function starts at %v, want the attribute", fn.Location.Start)
+	}
+	if st, ok := stmts[1].(*StructDefinition); !ok || !st.Private || st.Deprecated == nil || st.Deprecated.Note != "use Point" {
+		t.Fatalf("struct = %#v", stmts[1])
+	}
+	if impl, ok := stmts[2].(*ImplBlock); !ok || impl.Methods[0].Deprecated == nil || impl.Methods[0].Deprecated.Note != "read x" {
+		t.Fatalf("impl = %#v", stmts[2])
+	}
+	if static, ok := stmts[3].(*StaticFunctionDeclaration); !ok || static.Deprecated == nil || static.Deprecated.Note != "use Pt::new" {
+		t.Fatalf("static function = %#v", stmts[3])
+	}
+}
+
+func TestDeprecatedAttributeErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "unknown attribute", source: "@inline\nfn f() {}\n", want: "Unknown attribute: only @deprecated(\"note\") is supported"},
+		{name: "missing note", source: "@deprecated\nfn f() {}\n", want: "Expected a note after @deprecated, as in @deprecated(\"use new_fn\")"},
+		{name: "unsupported declaration", source: "@deprecated(\"no\")\nlet x = 1\n", want: "@deprecated can only annotate functions and structs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Parse([]byte(tt.source), "main.ard")
+			if len(result.Errors) == 0 || result.Errors[0].Message != tt.want {
+				t.Fatalf("errors = %v, want %q", result.Errors, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,354 @@
+package parse
+
+import "strings"
+
+// ImportUses returns the names of the imports that program's statements
+// refer to, keyed by the name an import is referenced by (see
+// Import.Alias). It is a syntactic scan, so a name counts as used wherever it
+// qualifies a type, function, or value.
+func ImportUses(program *Program) map[string]bool {
+	used := map[string]bool{}
+	if program == nil {
+		return used
+	}
+	for _, stmt := range program.Statements {
+		collectImportUsesInStatement(stmt, used)
+	}
+	return used
+}
+
+func collectImportUsesInType(t DeclaredType, used map[string]bool) {
+	switch v := t.(type) {
+	case *MutableType:
+		collectImportUsesInType(v.Inner, used)
+	case MutableType:
+		collectImportUsesInType(v.Inner, used)
+	case *CustomType:
+		if v.Type.Target != nil {
+			if name := simpleImportUseName(v.Type.Target); name != "" {
+				used[name] = true
+			}
+		}
+		for _, arg := range v.TypeArgs {
+			collectImportUsesInType(arg, used)
+		}
+	case CustomType:
+		if v.Type.Target != nil {
+			if name := simpleImportUseName(v.Type.Target); name != "" {
+				used[name] = true
+			}
+		}
+		for _, arg := range v.TypeArgs {
+			collectImportUsesInType(arg, used)
+		}
+	case *List:
+		collectImportUsesInType(v.Element, used)
+	case List:
+		collectImportUsesInType(v.Element, used)
+	case *FixedArray:
+		collectImportUsesInType(v.Element, used)
+	case FixedArray:
+		collectImportUsesInType(v.Element, used)
+	case *Map:
+		collectImportUsesInType(v.Key, used)
+		collectImportUsesInType(v.Value, used)
+	case Map:
+		collectImportUsesInType(v.Key, used)
+		collectImportUsesInType(v.Value, used)
+	case *ResultType:
+		collectImportUsesInType(v.Val, used)
+		collectImportUsesInType(v.Err, used)
+	case ResultType:
+		collectImportUsesInType(v.Val, used)
+		collectImportUsesInType(v.Err, used)
+	case *FunctionType:
+		for _, p := range v.Params {
+			collectImportUsesInType(p, used)
+		}
+		collectImportUsesInType(v.Return, used)
+	case FunctionType:
+		for _, p := range v.Params {
+			collectImportUsesInType(p, used)
+		}
+		collectImportUsesInType(v.Return, used)
+	case *UnionType:
+		for _, member := range v.Members {
+			collectImportUsesInType(member, used)
+		}
+	}
+}
+
+func collectImportUsesInStatement(stmt Statement, used map[string]bool) {
+	switch s := stmt.(type) {
+	case *VariableDeclaration:
+		if s.Type != nil {
+			collectImportUsesInType(s.Type, used)
+		}
+		collectImportUsesInExpression(s.Value, used)
+	case *VariableAssignment:
+		collectImportUsesInExpression(s.Target, used)
+		collectImportUsesInExpression(s.Value, used)
+	case *Assert:
+		collectImportUsesInExpression(s.Condition, used)
+		collectImportUsesInExpression(s.Message, used)
+	case *Defer:
+		collectImportUsesInExpression(s.Expr, used)
+		for _, body := range s.Body {
+			collectImportUsesInStatement(body, used)
+		}
+	case *FunctionDeclaration:
+		for _, p := range s.Parameters {
+			if p.Type != nil {
+				collectImportUsesInType(p.Type, used)
+			}
+		}
+		if s.ReturnType != nil {
+			collectImportUsesInType(s.ReturnType, used)
+		}
+		for _, body := range s.Body {
+			collectImportUsesInStatement(body, used)
+		}
+	case *StaticFunctionDeclaration:
+		collectImportUsesInExpression(&s.Path, used)
+		collectImportUsesInStatement(&s.FunctionDeclaration, used)
+	case *TypeDeclaration:
+		for _, t := range s.Type {
+			collectImportUsesInType(t, used)
+		}
+	case *StructDefinition:
+		for _, field := range s.Fields {
+			collectImportUsesInType(field.Type, used)
+			collectImportUsesInExpression(field.Default, used)
+		}
+	case *ImplBlock:
+		collectImportUsesInExpression(s.Target, used)
+		for i := range s.Methods {
+			collectImportUsesInStatement(&s.Methods[i], used)
+		}
+	case *TraitDefinition:
+		for i := range s.Methods {
+			collectImportUsesInStatement(&s.Methods[i], used)
+		}
+	case *TraitImplementation:
+		collectImportUsesInExpression(s.Trait, used)
+		collectImportUsesInExpression(s.ForType, used)
+		for i := range s.Methods {
+			collectImportUsesInStatement(&s.Methods[i], used)
+		}
+	case *StructInstance:
+		collectImportUsesInExpression(s, used)
+	case *EnumDefinition:
+		for _, variant := range s.Variants {
+			collectImportUsesInExpression(variant.Value, used)
+			for _, t := range variant.Payload {
+				collectImportUsesInType(t, used)
+			}
+		}
+	case *WhileLoop:
+		collectImportUsesInExpression(s.Condition, used)
+		for _, body := range s.Body {
+			collectImportUsesInStatement(body, used)
+		}
+	case *RangeLoop:
+		collectImportUsesInExpression(s.Start, used)
+		collectImportUsesInExpression(s.End, used)
+		collectImportUsesInExpression(s.Step, used)
+		for _, body := range s.Body {
+			collectImportUsesInStatement(body, used)
+		}
+	case *ForInLoop:
+		collectImportUsesInExpression(s.Iterable, used)
+		for _, body := range s.Body {
+			collectImportUsesInStatement(body, used)
+		}
+	case *ForLoop:
+		collectImportUsesInStatement(s.Init, used)
+		collectImportUsesInExpression(s.Condition, used)
+		collectImportUsesInStatement(s.Incrementer, used)
+		for _, body := range s.Body {
+			collectImportUsesInStatement(body, used)
+		}
+	case *IfStatement:
+		collectImportUsesInExpression(s.Condition, used)
+		for _, body := range s.Body {
+			collectImportUsesInStatement(body, used)
+		}
+		collectImportUsesInStatement(s.Else, used)
+	case *MatchExpression, *SelectExpression, *ConditionalMatchExpression, *Try, *BlockExpression, *UnsafeBlock:
+		collectImportUsesInExpression(s, used)
+	default:
+		if expr, ok := stmt.(Expression); ok {
+			collectImportUsesInExpression(expr, used)
+		}
+	}
+}
+
+func collectImportUsesInExpression(expr Expression, used map[string]bool) {
+	switch e := expr.(type) {
+	case nil:
+		return
+	case *Identifier:
+		if strings.Contains(e.Name, "::") {
+			used[strings.SplitN(e.Name, "::", 2)[0]] = true
+		}
+	case *StaticProperty:
+		collectStaticPropertyImportUses(e.Target, e.Property, used)
+	case StaticProperty:
+		collectStaticPropertyImportUses(e.Target, e.Property, used)
+	case *StaticFunction:
+		if name := simpleImportUseName(e.Target); name != "" {
+			used[name] = true
+		}
+		collectImportUsesInExpression(e.Target, used)
+		for _, typeArg := range e.Function.TypeArgs {
+			collectImportUsesInType(typeArg, used)
+		}
+		for _, arg := range e.Function.Args {
+			collectImportUsesInExpression(arg.Value, used)
+		}
+	case *FunctionCall:
+		for _, typeArg := range e.TypeArgs {
+			collectImportUsesInType(typeArg, used)
+		}
+		for _, arg := range e.Args {
+			collectImportUsesInExpression(arg.Value, used)
+		}
+	case *FunctionValueCall:
+		collectImportUsesInExpression(e.Callee, used)
+		for _, typeArg := range e.TypeArgs {
+			collectImportUsesInType(typeArg, used)
+		}
+		for _, arg := range e.Args {
+			collectImportUsesInExpression(arg.Value, used)
+		}
+	case *InstanceProperty:
+		collectImportUsesInExpression(e.Target, used)
+		collectImportUsesInExpression(e.Property, used)
+	case *InstanceMethod:
+		collectImportUsesInExpression(e.Target, used)
+		for _, typeArg := range e.Method.TypeArgs {
+			collectImportUsesInType(typeArg, used)
+		}
+		for _, arg := range e.Method.Args {
+			collectImportUsesInExpression(arg.Value, used)
+		}
+	case *StructInstance:
+		if strings.Contains(e.Name.Name, "::") {
+			used[strings.SplitN(e.Name.Name, "::", 2)[0]] = true
+		}
+		for _, typeArg := range e.TypeArgs {
+			collectImportUsesInType(typeArg, used)
+		}
+		collectImportUsesInExpression(e.Base, used)
+		for _, prop := range e.Properties {
+			collectImportUsesInExpression(prop.Value, used)
+		}
+	case *AnonymousFunction:
+		for _, p := range e.Parameters {
+			if p.Type != nil {
+				collectImportUsesInType(p.Type, used)
+			}
+		}
+		if e.ReturnType != nil {
+			collectImportUsesInType(e.ReturnType, used)
+		}
+		for _, body := range e.Body {
+			collectImportUsesInStatement(body, used)
+		}
+	case *BinaryExpression:
+		collectImportUsesInExpression(e.Left, used)
+		collectImportUsesInExpression(e.Right, used)
+	case *UnaryExpression:
+		collectImportUsesInExpression(e.Operand, used)
+	case *ChainedComparison:
+		for _, operand := range e.Operands {
+			collectImportUsesInExpression(operand, used)
+		}
+	case *PipeExpression:
+		collectImportUsesInExpression(e.Value, used)
+		collectImportUsesInExpression(e.Call, used)
+	case *AsExpression:
+		collectImportUsesInExpression(e.Value, used)
+		collectImportUsesInType(e.Type, used)
+	case *OrPattern:
+		for _, pattern := range e.Patterns {
+			collectImportUsesInExpression(pattern, used)
+		}
+	case *StructPattern:
+		if strings.Contains(e.Name.Name, "::") {
+			used[strings.SplitN(e.Name.Name, "::", 2)[0]] = true
+		}
+		for _, field := range e.Fields {
+			collectImportUsesInExpression(field.Pattern, used)
+		}
+	case *RangeExpression:
+		collectImportUsesInExpression(e.Start, used)
+		collectImportUsesInExpression(e.End, used)
+	case *InterpolatedStr:
+		for _, chunk := range e.Chunks {
+			collectImportUsesInExpression(chunk, used)
+		}
+	case *ListLiteral:
+		for _, item := range e.Items {
+			collectImportUsesInExpression(item, used)
+		}
+	case *MapLiteral:
+		for _, entry := range e.Entries {
+			collectImportUsesInExpression(entry.Key, used)
+			collectImportUsesInExpression(entry.Value, used)
+		}
+	case *MatchExpression:
+		collectImportUsesInExpression(e.Subject, used)
+		for _, c := range e.Cases {
+			collectImportUsesInExpression(c.Pattern, used)
+			for _, body := range c.Body {
+				collectImportUsesInStatement(body, used)
+			}
+		}
+	case *SelectExpression:
+		for _, c := range e.Cases {
+			collectImportUsesInExpression(c.Op, used)
+			for _, body := range c.Body {
+				collectImportUsesInStatement(body, used)
+			}
+		}
+	case *ConditionalMatchExpression:
+		for _, c := range e.Cases {
+			collectImportUsesInExpression(c.Condition, used)
+			for _, body := range c.Body {
+				collectImportUsesInStatement(body, used)
+			}
+		}
+	case *Try:
+		collectImportUsesInExpression(e.Expression, used)
+		for _, body := range e.CatchBlock {
+			collectImportUsesInStatement(body, used)
+		}
+	case *BlockExpression:
+		for _, body := range e.Statements {
+			collectImportUsesInStatement(body, used)
+		}
+	case *UnsafeBlock:
+		for _, body := range e.Statements {
+			collectImportUsesInStatement(body, used)
+		}
+	case *IfStatement:
+		collectImportUsesInStatement(e, used)
+	}
+}
+
+func collectStaticPropertyImportUses(target Expression, property Expression, used map[string]bool) {
+	if name := simpleImportUseName(target); name != "" {
+		used[name] = true
+	}
+	collectImportUsesInExpression(target, used)
+	collectImportUsesInExpression(property, used)
+}
+
+func simpleImportUseName(expr Expression) string {
+	id, ok := expr.(*Identifier)
+	if !ok {
+		return ""
+	}
+	return id.Name
+}
@@ -40,3 +40,75 @@ func TestImportPathLocations(t *testing.T) {
 		})
 	}
 }
+
+func TestPubUseReExports(t *testing.T) {
+	result := Parse([]byte("use ard/io\npub use app/shapes::Box\n\nfn main() {}\n"), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	if len(result.Program.Imports) != 2 {
+		t.Fatalf("imports = %#v", result.Program.Imports)
+	}
+	got := result.Program.Imports[1]
+	if got.Path != "app/shapes" || got.ReExport != "Box" || got.Kind != ImportKindModule {
+		t.Fatalf("re-export = %#v", got)
+	}
+	wantPath := Location{Start: Point{Row: 2, Col: 9}, End: Point{Row: 2, Col: 18}}
+	if got.PathLocation != wantPath {
+		t.Fatalf("path location = %v, want %v", got.PathLocation, wantPath)
+	}
+	if got.GetStart() != (Point{Row: 2, Col: 1}) {
+		t.Fatalf("start = %v", got.GetStart())
+	}
+	if len(result.Program.Statements) != 1 {
+		t.Fatalf("statements = %#v", result.Program.Statements)
+	}
+}
+
+func TestPubUseRequiresQualifiedSymbol(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{name: "module only", source: "pub use app/shapes\n", want: "Expected 'module/path::Name' after 'pub use'"},
+		{name: "go package", source: "pub use go:fmt::Println\n", want: "Expected 'module/path::Name' after 'pub use'"},
+		{name: "alias", source: "pub use app/shapes::Box as Crate\n", want: "Re-exports cannot be renamed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Parse([]byte(tt.source), "main.ard")
+			if len(result.Errors) == 0 || result.Errors[0].Message != tt.want {
+				t.Fatalf("errors = %v, want %q", result.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestModulePragmas(t *testing.T) {
+	result := Parse([]byte("#deny(warnings)\nuse ard/io\n#forbid(dynamic, other)\n\nfn main() {}\n"), "main.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	pragmas := result.Program.Pragmas
+	if len(pragmas) != 2 || len(result.Program.Imports) != 1 {
+		t.Fatalf("pragmas = %#v, imports = %#v", pragmas, result.Program.Imports)
+	}
+	if pragmas[0].String() != "#deny(warnings)" || pragmas[1].String() != "#forbid(dynamic, other)" {
+		t.Fatalf("pragmas = %s, %s", pragmas[0], pragmas[1])
+	}
+	wantLocation := Location{Start: Point{Row: 1, Col: 1}, End: Point{Row: 1, Col: 15}}
+	if pragmas[0].Location != wantLocation {
+		t.Fatalf("location = %v, want %v", pragmas[0].Location, wantLocation)
+	}
+	if !result.Program.HasPragma("forbid", "dynamic") || result.Program.HasPragma("deny", "dynamic") {
+		t.Fatal("HasPragma does not match the declared pragmas")
+	}
+}
+
+func TestPragmasMustPrecedeDeclarations(t *testing.T) {
+	result := Parse([]byte("fn main() {}\n#deny(warnings)\n"), "main.ard")
+	if len(result.Errors) != 1 || result.Errors[0].Message != "Pragmas must come before any declarations" {
+		t.Fatalf("errors = %v", result.Errors)
+	}
+}
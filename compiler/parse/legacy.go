@@ -0,0 +1,51 @@
+package parse
+
+import "github.com/akonwi/ard/version"
+
+// LegacySyntax records a spelling that an older language version accepted
+// and the current one rejects. The parser still reports it as an error, but
+// recovers it into the AST the current spelling would produce, so printing
+// the tree (see formatter.Migrate) rewrites the source.
+type LegacySyntax struct {
+	Error ParseError
+	// RemovedIn is the first language version that rejects the spelling.
+	RemovedIn version.Semver
+}
+
+// AllowedIn reports whether a project pinned to language still accepts the
+// spelling.
+func (l LegacySyntax) AllowedIn(language version.Semver) bool {
+	return language.Compare(l.RemovedIn) == version.LessThan
+}
+
+// AllowLegacy drops the errors for legacy spellings that language still
+// accepts and returns those spellings so callers can report them as
+// deprecations. Legacy spellings removed at or before language stay errors.
+// The accepted spellings are also recorded on the parsed program.
+func (pr *ParseResult) AllowLegacy(language version.Semver) []LegacySyntax {
+	allowed := []LegacySyntax{}
+	for _, legacy := range pr.Legacy {
+		if !legacy.AllowedIn(language) {
+			continue
+		}
+		for i, err := range pr.Errors {
+			if err == legacy.Error {
+				pr.Errors = append(pr.Errors[:i:i], pr.Errors[i+1:]...)
+				allowed = append(allowed, legacy)
+				break
+			}
+		}
+	}
+	if pr.Program != nil {
+		pr.Program.Legacy = allowed
+	}
+	return allowed
+}
+
+// legacyMutParameterRemovedIn is the language version that made parameter
+// mutability type syntax only (`name: mut T`, see ADR 0022).
+var legacyMutParameterRemovedIn = version.Semver{Major: 0, Minor: 22}
+
+func (p *parser) addLegacy(err ParseError, removedIn version.Semver) {
+	p.legacy = append(p.legacy, LegacySyntax{Error: err, RemovedIn: removedIn})
+}
@@ -23,11 +23,13 @@ const (
 	dot_dot            = "dot_dot"
 	question_mark      = "question_mark"
 	pipe               = "pipe"
+	pipe_arrow         = "pipe_arrow"
 	double_quote       = "double_quote"
 	single_quote       = "single_quote"
 	backtick           = "backtick"
 	dollar             = "dollar"
 	at_sign            = "at_sign"
+	hash               = "hash"
 
 	colon_colon        = "colon_colon"
 	bang               = "bang"
@@ -47,6 +49,8 @@ const (
 	fat_arrow          = "fat_arrow"
 	increment          = "increment"
 	decrement          = "decrement"
+	multiply_assign    = "multiply_assign"
+	divide_assign      = "divide_assign"
 	expr_open          = "expr_open"
 	expr_close         = "expr_close"
 
@@ -144,12 +148,24 @@ type lexer struct {
 	templateDepth int
 }
 
+// bytesPerToken is roughly how many bytes of Ard source make one token,
+// counting the whitespace between them.
+const bytesPerToken = 4
+
 func NewLexer(source []byte) *lexer {
+	return newLexerAt(source, 1)
+}
+
+// newLexerAt lexes source as the text starting at the first column of line
+// in a larger file, so its tokens carry their positions in that file.
+func newLexerAt(source []byte, line int) *lexer {
 	return &lexer{
 		source: source,
-		tokens: []token{},
+		// sized from the source so a large file's tokens are not copied over
+		// and over as the slice grows
+		tokens: make([]token, 0, len(source)/bytesPerToken+1),
 		cursor: 0,
-		line:   1,
+		line:   line,
 		column: 1,
 	}
 }
@@ -332,10 +348,15 @@ func (l *lexer) take() (token, bool) {
 	case '?':
 		return currentChar.asToken(question_mark), true
 	case '|':
+		if l.hasMore() && l.matchNext('>') != nil {
+			return currentChar.asToken(pipe_arrow), true
+		}
 		return currentChar.asToken(pipe), true
 	case '@':
 		// Simply return the at_sign token
 		return currentChar.asToken(at_sign), true
+	case '#':
+		return currentChar.asToken(hash), true
 	case '$':
 		if l.hasMore() && l.peek().isAlpha() {
 			l.start = l.cursor - 1
@@ -391,6 +412,13 @@ func (l *lexer) take() (token, bool) {
 		if l.matchNext('-') != nil {
 			return currentChar.asToken(decrement), true
 		}
+		if l.matchNext('*') != nil {
+			return currentChar.asToken(multiply_assign), true
+		}
+		// `=//` is an assignment followed by a comment, not `=/`.
+		if next := l.at(l.cursor + 1); (next == nil || next.raw != '/') && l.matchNext('/') != nil {
+			return currentChar.asToken(divide_assign), true
+		}
 		return currentChar.asToken(equal), true
 	case '"':
 		return l.takeString(*currentChar)
@@ -756,12 +784,35 @@ func (l *lexer) at(i int) *char {
 func (l *lexer) takeNumber() token {
 	// record the start column
 	column := l.column - 1
+	// 0x, 0b, and 0o literals take every following letter and digit so a
+	// malformed one like 0xZZ stays a single token for the checker to reject
+	if l.source[l.start] == '0' && l.hasMore() && strings.ContainsRune("xXbBoO", rune(l.peek().raw)) {
+		l.advance()
+		for l.hasMore() && l.peek().isAlphaNumeric() {
+			l.advance()
+		}
+		text := string(l.source[l.start:l.cursor])
+		return token{kind: number, text: text, line: l.line, column: column}
+	}
 	for l.hasMore() && (l.peek().isDigit() || l.check("_") || (l.check(".") && !l.check(".."))) {
 		if l.check(".") && !l.at(l.cursor+1).isDigit() {
 			break
 		}
 		l.advance()
 	}
+	// exponent: 1e9, 1.5E-3
+	if l.hasMore() && (l.check("e") || l.check("E")) {
+		next := l.cursor + 1
+		if sign := l.at(next); sign != nil && (sign.raw == '+' || sign.raw == '-') {
+			next++
+		}
+		if digit := l.at(next); digit != nil && digit.isDigit() {
+			l.advanceN(next - l.cursor)
+			for l.hasMore() && (l.peek().isDigit() || l.check("_")) {
+				l.advance()
+			}
+		}
+	}
 	text := string(l.source[l.start:l.cursor])
 	return token{kind: number, text: text, line: l.line, column: column}
 }
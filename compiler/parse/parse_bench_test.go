@@ -0,0 +1,73 @@
+package parse
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// largeSource generates a program of `count` functions dense with calls,
+// literals, and operators, the small nodes a large file is mostly made of.
+func largeSource(count int) []byte {
+	var b strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&b, `fn compute_%d(values: [Int], names: [Str: Int]) Int {
+  mut total = 0
+  for value, i in values {
+    let scaled = value * %d + i - 1
+    if scaled > 10 and names.has("n{i}") {
+      total = total + scaled
+    }
+  }
+  match total {
+    0 => names.get("zero").or(-1),
+    _ => total + values.size() * 2,
+  }
+}
+
+`, i, i)
+	}
+	return []byte(b.String())
+}
+
+func benchmarkParse(b *testing.B, count int) {
+	source := largeSource(count)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result := Parse(source, "large.ard")
+		if len(result.Errors) > 0 {
+			b.Fatalf("parse errors: %v", result.Errors[0].Message)
+		}
+	}
+}
+
+func BenchmarkParseLargeSource100(b *testing.B)  { benchmarkParse(b, 100) }
+func BenchmarkParseLargeSource1000(b *testing.B) { benchmarkParse(b, 1000) }
+
+// benchmarkReparse reparses a large program after an edit in the middle of
+// it, changing a line in place or adding a line.
+func benchmarkReparse(b *testing.B, count int, replacement string) {
+	source := largeSource(count)
+	old := Parse(source, "large.ard")
+	target := []byte(fmt.Sprintf("let scaled = value * %d + i - 1", count/2))
+	at := strings.Index(string(source), string(target))
+	edited := append(append(append([]byte{}, source[:at]...), replacement...), source[at+len(target):]...)
+	edits := []Edit{EditBetween(source, edited)}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		result := Reparse(old, edits, edited)
+		if len(result.Errors) > 0 {
+			b.Fatalf("parse errors: %v", result.Errors[0].Message)
+		}
+	}
+}
+
+func BenchmarkReparseLineChange1000(b *testing.B) {
+	benchmarkReparse(b, 1000, "let scaled = value * 7 + i - 1")
+}
+
+func BenchmarkReparseLineInsert1000(b *testing.B) {
+	benchmarkReparse(b, 1000, "let scaled = value * 7 + i - 1\n    let unused = 1")
+}
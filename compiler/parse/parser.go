@@ -17,6 +17,9 @@ type ParseResult struct {
 	fileName string
 	Program  *Program
 	Errors   []ParseError
+	// Legacy lists the spellings among Errors that older language versions
+	// accepted (see AllowLegacy).
+	Legacy []LegacySyntax
 }
 
 func (pr ParseResult) PrintErrors() {
@@ -41,16 +44,36 @@ type parser struct {
 	// it can't leak through anonymous-function bodies. (#285)
 	structOperandAllowed bool
 	inCallTypeArguments  bool
+	legacy               []LegacySyntax
+	nodes                nodeArena
+	// lines is the source split by line, for statements that keep the text
+	// they were written with.
+	lines []string
+	// stopRow, when set, ends parsing at the first top-level statement on or
+	// after that line, and stopped records whether one started right at its
+	// first column. See Reparse.
+	stopRow int
+	stopped bool
+	// formatTemplate is the index of the string token that starts the
+	// literal template of a Str::format call, or 0 when none is pending.
+	formatTemplate int
 }
 
 func Parse(source []byte, fileName string) ParseResult {
-	p := new(NewLexer(source).Scan(), fileName)
+	return parseTokens(NewLexer(source).Scan(), strings.Split(string(source), "\n"), fileName)
+}
+
+// parseTokens parses tokens lexed from a file whose text is split into lines.
+func parseTokens(tokens []token, lines []string, fileName string) ParseResult {
+	p := new(tokens, fileName)
+	p.lines = lines
 	program, err := p.parse()
 
 	result := ParseResult{
 		fileName: fileName,
 		Program:  program,
 		Errors:   p.errors,
+		Legacy:   p.legacy,
 	}
 
 	// If there was a panic-based error, add it to errors
@@ -116,6 +139,80 @@ func (p *parser) synchronizeToBlockEnd() {
 	}
 }
 
+// synchronizeStatement skips the rest of a statement that failed to parse:
+// up to and including the next line break outside any brackets the
+// statement opened. It stops early before a '}' that closes the enclosing
+// block and before a declaration at the start of a line, which means that
+// block is missing its '}'.
+func (p *parser) synchronizeStatement() {
+	nesting := 0
+	for !p.isAtEnd() {
+		switch p.peek().kind {
+		case new_line:
+			if nesting == 0 {
+				p.advance()
+				return
+			}
+		case left_paren, left_bracket, left_brace:
+			nesting++
+		case right_paren, right_bracket:
+			if nesting > 0 {
+				nesting--
+			}
+		case right_brace:
+			if nesting == 0 {
+				return
+			}
+			nesting--
+		}
+		p.advance()
+		if p.atDeclaration() {
+			return
+		}
+	}
+}
+
+// synchronizeDeclaration skips to the next line that starts at the first
+// column, where the next top-level declaration begins.
+func (p *parser) synchronizeDeclaration() {
+	for !p.isAtEnd() {
+		if p.advance().kind != new_line || p.peek().column != 1 {
+			continue
+		}
+		switch p.peek().kind {
+		case new_line, right_brace, right_paren, right_bracket:
+		default:
+			return
+		}
+	}
+}
+
+// atDeclaration reports whether the next token starts a top-level
+// declaration at the first column of its line.
+func (p *parser) atDeclaration() bool {
+	next := p.peek()
+	if next.column != 1 {
+		return false
+	}
+	switch next.kind {
+	case fn, struct_, enum, impl, trait, type_, private, at_sign, use:
+		return true
+	default:
+		return false
+	}
+}
+
+// unclosed reports whether the input ends inside a construct that still
+// expects `closer`, adding an error if so, so that loops over the items of a
+// construct always end.
+func (p *parser) unclosed(closer string, construct string) bool {
+	if !p.isAtEnd() {
+		return false
+	}
+	p.addError(p.peek(), fmt.Sprintf("Expected '%s' to close %s", closer, construct))
+	return true
+}
+
 func adjacent(left Location, right *token) bool {
 	if right == nil || left.End.Row != right.line {
 		return false
@@ -224,23 +321,38 @@ func (p *parser) parse() (*Program, error) {
 	for importing {
 		if imp := p.parseImport(); imp != nil {
 			program.Imports = append(program.Imports, *imp)
+		} else if p.check(hash) {
+			if pragma := p.parsePragma(); pragma != nil {
+				program.Pragmas = append(program.Pragmas, *pragma)
+			}
 		} else {
 			if c := p.parseComment(); c != nil {
 				program.Statements = append(program.Statements, c)
 			}
-			// Continue import phase while the current token is an empty line, 'use', or comment
-			importing = p.check(new_line) || p.check(use) || p.check(comment)
+			// Continue import phase while the current token is an empty line, 'use', pragma, or comment
+			importing = p.check(new_line) || p.check(use) || p.checkPubUse() || p.check(hash) || p.check(comment)
 		}
 	}
 
 	// Parse statements
 	for !p.isAtEnd() {
+		if p.stopRow > 0 && p.peek().line >= p.stopRow {
+			p.stopped = p.peek().line == p.stopRow && p.peek().column == 1
+			break
+		}
 		if p.match(new_line) {
 			continue
 		}
+		if p.check(hash) {
+			p.addError(p.peek(), "Pragmas must come before any declarations")
+			p.synchronize()
+			continue
+		}
 		stmt, err := p.parseStatement()
 		if err != nil {
-			return nil, err
+			p.recordError(err)
+			p.synchronizeDeclaration()
+			continue
 		}
 		if stmt != nil {
 			program.Statements = append(program.Statements, stmt)
@@ -250,10 +362,142 @@ func (p *parser) parse() (*Program, error) {
 	return program, nil
 }
 
+// parsePragma parses a `#name(arg, ...)` module directive. Pragmas share the
+// import section at the top of a module.
+func (p *parser) parsePragma() *Pragma {
+	hashToken := p.advance()
+	if !p.check(identifier) {
+		p.addError(p.peek(), "Expected pragma name after '#'")
+		p.synchronize()
+		return nil
+	}
+	nameToken := p.advance()
+	pragma := &Pragma{Name: nameToken.text, NameLocation: nameToken.getLocation(), Args: []string{}}
+	if !p.match(left_paren) {
+		p.addError(p.peek(), "Expected '(' after pragma name")
+		p.synchronize()
+		return nil
+	}
+	for !p.check(right_paren) {
+		if !p.check(identifier) {
+			p.addError(p.peek(), "Expected pragma argument")
+			p.synchronize()
+			return nil
+		}
+		pragma.Args = append(pragma.Args, p.advance().text)
+		if !p.match(comma) {
+			break
+		}
+	}
+	closing := p.peek()
+	if !p.match(right_paren) {
+		p.addError(closing, "Expected ')' after pragma arguments")
+		p.synchronize()
+		return nil
+	}
+	pragma.Location = Location{Start: hashToken.getLocation().Start, End: closing.getLocation().Start}
+	return pragma
+}
+
+// deprecation parses a `@deprecated("note")` attribute line and the newlines
+// after it. It returns nil after reporting an error for a malformed attribute.
+func (p *parser) deprecation() *Deprecation {
+	atToken := p.advance()
+	nameToken := p.peek()
+	if !p.match(identifier) || nameToken.text != "deprecated" {
+		p.addError(nameToken, "Unknown attribute: only @deprecated(\"note\") is supported")
+		p.synchronize()
+		return nil
+	}
+	if !p.match(left_paren) || !p.check(string_) {
+		p.addError(p.peek(), "Expected a note after @deprecated, as in @deprecated(\"use new_fn\")")
+		p.synchronize()
+		return nil
+	}
+	note := p.advance()
+	closing := p.peek()
+	if !p.match(right_paren) {
+		p.addError(closing, "Expected ')' after the deprecation note; the note must be a plain string")
+		p.synchronize()
+		return nil
+	}
+	p.skipNewlines()
+	return &Deprecation{
+		Location: Location{Start: atToken.getLocation().Start, End: closing.getLocation().End},
+		Note:     note.text,
+	}
+}
+
+// deprecatedDeclaration attaches a `@deprecated` attribute to the function or
+// struct declaration that follows it.
+func (p *parser) deprecatedDeclaration() (Statement, error) {
+	atToken := *p.peek()
+	deprecation := p.deprecation()
+	stmt, err := p.parseStatement()
+	if err != nil || deprecation == nil {
+		return stmt, err
+	}
+	switch s := stmt.(type) {
+	case *FunctionDeclaration:
+		s.Deprecated = deprecation
+		s.Location.Start = deprecation.Start
+	case *StaticFunctionDeclaration:
+		s.Deprecated = deprecation
+		s.Location.Start = deprecation.Start
+	case *StructDefinition:
+		s.Deprecated = deprecation
+		s.Location.Start = deprecation.Start
+	default:
+		p.addError(&atToken, "@deprecated can only annotate functions and structs")
+	}
+	return stmt, nil
+}
+
+// checkPubUse reports whether the next tokens start a `pub use` re-export.
+// `pub` is only a keyword in this position, so it lexes as an identifier.
+func (p *parser) checkPubUse() bool {
+	return p.check(identifier, use) && p.peek().text == "pub"
+}
+
+// parseReExport finishes a `pub use path::Symbol` statement whose path token
+// has been consumed.
+func (p *parser) parseReExport(pathToken token, start Point) *Import {
+	importPath := pathToken.text
+	sep := strings.LastIndex(importPath, "::")
+	if strings.HasPrefix(importPath, "go:") || sep <= 0 || sep+2 == len(importPath) {
+		p.addError(&pathToken, "Expected 'module/path::Name' after 'pub use'")
+		p.synchronize()
+		return nil
+	}
+	if p.check(as) {
+		p.addError(p.peek(), "Re-exports cannot be renamed")
+		p.synchronize()
+		return nil
+	}
+	pathLocation := pathToken.getLocation()
+	end := pathLocation.End
+	pathLocation.End.Col = pathLocation.Start.Col + sep - 1
+	p.match(new_line)
+	return &Import{
+		Path:         importPath[:sep],
+		Kind:         ImportKindModule,
+		ReExport:     importPath[sep+2:],
+		PathLocation: pathLocation,
+		Location:     Location{Start: start, End: end},
+	}
+}
+
 func (p *parser) parseImport() *Import {
 	// Skip any leading newlines
 	p.skipNewlines()
 
+	// `pub use path::Symbol` re-exports a symbol of another module
+	reExport := p.checkPubUse()
+	var start Point
+	if reExport {
+		start = p.advance().getLocation().Start
+	}
+
 	// If not 'use', return nil (end of import section)
 	if !p.check(use) {
 		return nil
@@ -261,7 +505,9 @@ func (p *parser) parseImport() *Import {
 
 	// We have 'use' - consume it
 	useToken := p.advance()
-	start := useToken.getLocation().Start
+	if !reExport {
+		start = useToken.getLocation().Start
+	}
 
 	// Check for missing path
 	if !p.check(path) {
@@ -285,6 +531,10 @@ func (p *parser) parseImport() *Import {
 		}
 	}
 
+	if reExport {
+		return p.parseReExport(pathToken, start)
+	}
+
 	// Parse optional alias
 	var name string
 	if p.match(as) {
@@ -372,9 +622,19 @@ func (p *parser) parseStatement() (Statement, error) {
 	if p.match(defer_) {
 		return p.deferStatement()
 	}
+	if p.checkAssert() {
+		return p.assertStatement()
+	}
+	if p.check(private, let) || (p.check(private, identifier, identifier) && p.peek2().text == "const") {
+		return p.privateVariableDef()
+	}
 	if p.match(let, mut) {
 		return p.parseVariableDef()
 	}
+	if p.check(identifier, identifier) && p.peek().text == "const" {
+		p.advance() // consume contextual 'const'
+		return p.parseVariableDef()
+	}
 	if p.match(if_) {
 		return p.ifStatement()
 	}
@@ -385,6 +645,10 @@ func (p *parser) parseStatement() (Statement, error) {
 		return p.forLoop()
 	}
 
+	if p.check(at_sign) {
+		return p.deprecatedDeclaration()
+	}
+
 	if p.check(identifier, fn) && p.peek().text == "test" {
 		p.advance() // consume contextual 'test'
 		return p.functionDef(false, true)
@@ -420,10 +684,10 @@ func (p *parser) parseStatement() (Statement, error) {
 	if p.check(private, trait) {
 		p.match(private)
 		p.match(trait)
-		return p.traitDef(true), nil
+		return statementOrNil(p.traitDef(true)), nil
 	}
 	if p.match(trait) {
-		return p.traitDef(false), nil
+		return statementOrNil(p.traitDef(false)), nil
 	}
 	if p.match(impl) {
 		// if implementing a static reference, it's a trait
@@ -446,11 +710,24 @@ func (p *parser) parseStatement() (Statement, error) {
 				return p.traitImpl()
 			}
 		}
-		return p.implBlock(), nil
+		return statementOrNil(p.implBlock()), nil
 	}
 	return p.assignment()
 }
 
+// statementOrNil returns node as a Statement, or a nil Statement when a
+// declaration that failed to parse returned a nil node, so the tree never
+// holds a nil node behind a non-nil interface.
+func statementOrNil[T any, P interface {
+	*T
+	Statement
+}](node P) Statement {
+	if node == nil {
+		return nil
+	}
+	return node
+}
+
 func (p *parser) deferStatement() (Statement, error) {
 	start := p.previous()
 	if p.check(left_brace) {
@@ -470,10 +747,77 @@ func (p *parser) deferStatement() (Statement, error) {
 	return &Defer{Expr: expr, Location: Location{Start: start.getLocation().Start, End: expr.GetLocation().End}}, nil
 }
 
+// checkAssert reports whether the statement starts with the contextual
+// `assert` or `ensure` keyword. Anything that continues the name as a value,
+// such as a call `assert(x)` or access `assert.x`, leaves it an identifier;
+// `assert (x), "m"` with a space is a statement.
+func (p *parser) checkAssert() bool {
+	if !p.check(identifier) || (p.peek().text != "assert" && p.peek().text != "ensure") {
+		return false
+	}
+	keyword, next := p.peek(), p.peek2()
+	if next == nil || next.line != keyword.line {
+		return false
+	}
+	switch next.kind {
+	case left_paren:
+		return next.column > keyword.column+len(keyword.text)
+	case dot, dot_dot, colon_colon, equal, increment, decrement, multiply_assign, divide_assign, comma, colon, left_bracket, right_paren, right_brace, right_bracket, eof, comment,
+		equal_equal, bang_equal, less_than, less_than_equal, greater_than, greater_than_equal, plus, star, slash, percent, and, or, question_mark, fat_arrow, thin_arrow, pipe, pipe_arrow:
+		return false
+	}
+	return true
+}
+
+func (p *parser) assertStatement() (Statement, error) {
+	keyword := p.advance()
+	conditionStart := p.peek().getLocation().Start
+	condition, err := p.parseExpression()
+	if err != nil {
+		return nil, err
+	}
+	source := p.sourceText(conditionStart, p.peek().getLocation().Start)
+	statement := &Assert{Ensure: keyword.text == "ensure", Condition: condition, Source: source}
+	end := condition.GetLocation().End
+	if p.match(comma) {
+		message, err := p.parseExpression()
+		if err != nil {
+			return nil, err
+		}
+		statement.Message = message
+		end = message.GetLocation().End
+	}
+	statement.Location = Location{Start: keyword.getLocation().Start, End: end}
+	p.match(new_line)
+	return statement, nil
+}
+
+// sourceText returns the source from start up to, but not including, end,
+// with runs of whitespace collapsed.
+func (p *parser) sourceText(start Point, end Point) string {
+	if start.Row < 1 || end.Row > len(p.lines) || start.Row > end.Row {
+		return ""
+	}
+	var text strings.Builder
+	for row := start.Row; row <= end.Row; row++ {
+		line := p.lines[row-1]
+		from, to := 0, len(line)
+		if row == start.Row {
+			from = min(start.Col-1, len(line))
+		}
+		if row == end.Row {
+			to = max(from, min(end.Col-1, len(line)))
+		}
+		text.WriteString(line[from:to])
+		text.WriteByte(' ')
+	}
+	return strings.Join(strings.Fields(text.String()), " ")
+}
+
 func (p *parser) parseVariableDef() (Statement, error) {
 	start := p.previous()
 	kind := start.kind
-	name := p.consumeVariableName(fmt.Sprintf("Expected identifier after '%s'", string(kind)))
+	name := p.consumeVariableName(fmt.Sprintf("Expected identifier after '%s'", start.text))
 	var declaredType DeclaredType = nil
 	if p.match(colon) {
 		declaredType = p.parseType()
@@ -493,13 +837,27 @@ func (p *parser) parseVariableDef() (Statement, error) {
 		return nil, err
 	}
 	end := value.GetLocation().End
+	var elseBlock []Statement
+	if kind != identifier && p.match(else_) {
+		elseBlock, err = p.block()
+		if err != nil {
+			return nil, err
+		}
+		if elseBlock == nil {
+			elseBlock = []Statement{}
+		}
+		blockEnd := p.previous()
+		end = Point{Row: blockEnd.line, Col: blockEnd.column}
+	}
 	p.match(new_line)
 	return &VariableDeclaration{
 		Mutable:      kind == mut,
+		Const:        kind == identifier,
 		Name:         name.text,
 		NameLocation: name.getLocation(),
 		Value:        value,
 		Type:         declaredType,
+		Else:         elseBlock,
 		Location: Location{
 			Start: Point{Row: start.line, Col: start.column},
 			End:   end,
@@ -507,8 +865,30 @@ func (p *parser) parseVariableDef() (Statement, error) {
 	}, nil
 }
 
+// privateVariableDef parses `private let` and `private const`, which keep a
+// module-level value out of the module's public API.
+func (p *parser) privateVariableDef() (Statement, error) {
+	start := p.advance()
+	p.advance() // consume 'let' or contextual 'const'
+	stmt, err := p.parseVariableDef()
+	if decl, ok := stmt.(*VariableDeclaration); ok {
+		decl.Private = true
+		decl.Location.Start = Point{Row: start.line, Col: start.column}
+	}
+	return stmt, err
+}
+
 func (p *parser) ifStatement() (Statement, error) {
 	ifToken := p.previous()
+	var binding *Identifier
+	if p.match(let) {
+		name := p.consumeVariableName("Expected identifier after 'let'")
+		binding = p.nodes.identifiers.Put(Identifier{Name: name.text, Location: name.getLocation()})
+		if !p.match(equal) {
+			p.addError(p.peek(), "Expected '=' after variable name")
+			return nil, nil
+		}
+	}
 	condition, err := p.or()
 	if err != nil {
 		return nil, err
@@ -523,6 +903,7 @@ func (p *parser) ifStatement() (Statement, error) {
 
 	stmt := &IfStatement{
 		Condition: condition,
+		Let:       binding,
 		Body:      statements,
 		Location: Location{
 			Start: Point{Row: ifToken.line, Col: ifToken.column},
@@ -562,6 +943,19 @@ func (p *parser) ifStatement() (Statement, error) {
 func (p *parser) whileLoop() (Statement, error) {
 	whileToken := p.previous()
 	var condition Expression
+	var binding *Identifier
+	if p.match(let) {
+		name := p.consumeVariableName("Expected identifier after 'let'")
+		binding = p.nodes.identifiers.Put(Identifier{Name: name.text, Location: name.getLocation()})
+		if !p.match(equal) {
+			p.addError(p.peek(), "Expected '=' after variable name")
+			return nil, nil
+		}
+		if p.check(left_brace) {
+			p.addError(p.peek(), "Expected a value after '='")
+			return nil, nil
+		}
+	}
 
 	// skip condition for infinite loop - `while { foo() }`
 	if !p.match(left_brace) {
@@ -593,6 +987,7 @@ func (p *parser) whileLoop() (Statement, error) {
 		// Recovery: Create while loop with statements parsed so far
 		return &WhileLoop{
 			Condition: condition,
+			Let:       binding,
 			Body:      statements,
 			Location: Location{
 				Start: Point{Row: whileToken.line, Col: whileToken.column},
@@ -605,6 +1000,7 @@ func (p *parser) whileLoop() (Statement, error) {
 
 	return &WhileLoop{
 		Condition: condition,
+		Let:       binding,
 		Body:      statements,
 		Location: Location{
 			Start: Point{Row: whileToken.line, Col: whileToken.column},
@@ -650,6 +1046,14 @@ func (p *parser) forLoop() (Statement, error) {
 			if err != nil {
 				return nil, err
 			}
+			// `by` is contextual: it only introduces a step after a range.
+			var step Expression
+			if _, isRange := seq.(*RangeExpression); isRange && p.check(identifier) && p.peek().text == "by" {
+				p.advance()
+				if step, err = p.or(); err != nil {
+					return nil, err
+				}
+			}
 			body, err := p.block()
 			if err != nil {
 				return nil, err
@@ -661,6 +1065,7 @@ func (p *parser) forLoop() (Statement, error) {
 					Cursor2: cursor2,
 					Start:   seq.Start,
 					End:     seq.End,
+					Step:    step,
 					Body:    body,
 					Location: Location{
 						Start: Point{Row: forToken.line, Col: forToken.column},
@@ -773,7 +1178,13 @@ func (p *parser) typeUnion(private bool) (Statement, error) {
 			p.synchronize()
 			return nil, nil
 		}
-		decl.Type = append(decl.Type, declType)
+		// `type T = A | B` on one line parses as an inline union; its
+		// members are the declaration's members.
+		if union, ok := declType.(*UnionType); ok && !union.IsNullable() {
+			decl.Type = append(decl.Type, union.Members...)
+		} else {
+			decl.Type = append(decl.Type, declType)
+		}
 		hasMore = p.matchTypeUnionSeparator()
 	}
 
@@ -836,6 +1247,9 @@ func (p *parser) enumDef(private bool) Statement {
 
 	p.match(new_line)
 	for !p.match(right_brace) {
+		if p.unclosed("}", "enum") {
+			break
+		}
 		// Parse and collect comments
 		if c := p.parseInlineComment(); c != nil {
 			enum.Comments = append(enum.Comments, *c)
@@ -860,6 +1274,26 @@ func (p *parser) enumDef(private bool) Statement {
 		variantToken := p.advance()
 		variant := EnumVariant{Name: variantToken.text}
 
+		// Associated data: `Click(Int, Int)`
+		if p.match(left_paren) {
+			for !p.match(right_paren) {
+				if p.unclosed(")", "variant payload") {
+					break
+				}
+				payloadType := p.parseType()
+				if payloadType == nil {
+					p.synchronize()
+					return nil
+				}
+				variant.Payload = append(variant.Payload, payloadType)
+				if !p.match(comma) && !p.check(right_paren) {
+					p.addError(p.peek(), "Expected ',' or ')' in variant payload")
+					p.synchronize()
+					return nil
+				}
+			}
+		}
+
 		// Check for explicit value assignment
 		if p.match(equal) {
 			variant.Value, _ = p.parseExpression()
@@ -880,7 +1314,7 @@ func (p *parser) enumDef(private bool) Statement {
 	return enum
 }
 
-func (p *parser) structDef(private bool) Statement {
+func (p *parser) structDef(isPrivate bool) Statement {
 	structToken := p.previous()
 	if !p.check(identifier) {
 		p.addError(p.peek(), "Expected name after 'struct'")
@@ -890,7 +1324,7 @@ func (p *parser) structDef(private bool) Statement {
 	nameToken := p.advance()
 	typeParams := p.parseGenericTypeParameters()
 	structDef := &StructDefinition{
-		Private:    private,
+		Private:    isPrivate,
 		Name:       Identifier{Name: nameToken.text, Location: nameToken.getLocation()},
 		TypeParams: typeParams,
 		Fields:     []StructField{},
@@ -908,6 +1342,9 @@ func (p *parser) structDef(private bool) Statement {
 
 	p.match(new_line)
 	for !p.check(right_brace) {
+		if p.unclosed("}", "struct") {
+			break
+		}
 		// Parse and collect comments
 		if c := p.parseInlineComment(); c != nil {
 			structDef.Comments = append(structDef.Comments, *c)
@@ -920,6 +1357,14 @@ func (p *parser) structDef(private bool) Statement {
 			continue
 		}
 
+		// `private name: T` hides the field from other modules. A field may
+		// itself be named `private`, so the modifier needs a name after it.
+		fieldPrivate := false
+		if p.check(private) && !p.check(private, colon) {
+			p.advance()
+			fieldPrivate = true
+		}
+
 		// Check for field name (identifier or allowed keywords)
 		current := p.peek()
 		if !(current.kind == identifier || p.isAllowedIdentifierKeyword(current.kind)) {
@@ -951,12 +1396,22 @@ func (p *parser) structDef(private bool) Statement {
 			p.match(new_line)
 			continue
 		}
+		var fieldDefault Expression
+		if p.match(equal) {
+			if p.check(comma) || p.check(right_brace) || p.check(new_line) {
+				p.addError(p.peek(), "Expected expression after '='")
+			} else {
+				fieldDefault, _ = p.parseExpression()
+			}
+		}
 		structDef.Fields = append(structDef.Fields, StructField{
 			Name: Identifier{
 				Name:     fieldName.text,
 				Location: fieldName.getLocation(),
 			},
-			Type: fieldType,
+			Type:    fieldType,
+			Private: fieldPrivate,
+			Default: fieldDefault,
 		})
 
 		// Check for inline comment after field type
@@ -1048,6 +1503,9 @@ func (p *parser) implBlock() *ImplBlock {
 	}
 
 	for !p.match(right_brace) {
+		if p.unclosed("}", "impl block") {
+			break
+		}
 		// Parse and collect comments
 		if c := p.parseInlineComment(); c != nil {
 			impl.Comments = append(impl.Comments, *c)
@@ -1060,6 +1518,10 @@ func (p *parser) implBlock() *ImplBlock {
 			continue
 		}
 
+		var deprecation *Deprecation
+		if p.check(at_sign) {
+			deprecation = p.deprecation()
+		}
 		stmt, err := p.functionDef(true, false)
 		if err != nil {
 			// For now, keep the old error handling until functionDef is converted
@@ -1073,6 +1535,10 @@ func (p *parser) implBlock() *ImplBlock {
 			p.synchronizeToBlockEnd()
 			break
 		}
+		if deprecation != nil {
+			fn.Deprecated = deprecation
+			fn.Location.Start = deprecation.Start
+		}
 		impl.Methods = append(impl.Methods, *fn)
 	}
 
@@ -1118,6 +1584,9 @@ func (p *parser) traitDef(private bool) *TraitDefinition {
 	}
 
 	for !p.match(right_brace) {
+		if p.unclosed("}", "trait") {
+			break
+		}
 		// Parse and collect comments
 		if c := p.parseInlineComment(); c != nil {
 			traitDef.Comments = append(traitDef.Comments, *c)
@@ -1158,6 +1627,9 @@ func (p *parser) traitDef(private bool) *TraitDefinition {
 		// Parse parameters
 		params := []Parameter{}
 		for !p.check(right_paren) {
+			if p.unclosed(")", "parameter list") {
+				break
+			}
 			if len(params) > 0 {
 				if !p.check(comma) {
 					p.addError(p.peek(), "Expected ',' between parameters")
@@ -1338,6 +1810,9 @@ func (p *parser) traitImpl() (*TraitImplementation, error) {
 	}
 
 	for !p.match(right_brace) {
+		if p.unclosed("}", "impl block") {
+			break
+		}
 		if p.match(new_line) {
 			continue
 		}
@@ -1384,11 +1859,24 @@ func (p *parser) blockStatements() ([]Statement, error) {
 			break
 		}
 
+		errorCount := len(p.errors)
 		stmt, err := p.parseStatement()
 		if err != nil {
-			return nil, err
+			p.recordError(err)
+			p.synchronizeStatement()
+		} else {
+			statements = append(statements, stmt)
+			if len(p.errors) > errorCount && !p.check(new_line) && !p.check(right_brace) {
+				// skip the rest of a broken line rather than report each of
+				// its leftover tokens as another error
+				p.synchronizeStatement()
+			}
+		}
+		if p.atDeclaration() {
+			// a declaration at the start of a line begins the next top-level
+			// item, so this block is missing its '}'
+			break
 		}
-		statements = append(statements, stmt)
 	}
 
 	if !p.check(right_brace) {
@@ -1401,12 +1889,21 @@ func (p *parser) blockStatements() ([]Statement, error) {
 	return statements, nil
 }
 
+// compoundAssignmentOperators maps each compound assignment token (`=+`,
+// `=-`, `=*`, `=/`) to the arithmetic it applies.
+var compoundAssignmentOperators = map[kind]Operator{
+	increment:       Plus,
+	decrement:       Minus,
+	multiply_assign: Multiply,
+	divide_assign:   Divide,
+}
+
 func (p *parser) assignment() (Statement, error) {
 	expr, err := p.parseExpression()
 	if err != nil {
 		return nil, err
 	}
-	if p.match(equal, increment, decrement) {
+	if p.match(equal, increment, decrement, multiply_assign, divide_assign) {
 		opToken := p.previous()
 		value, err := p.parseExpression()
 		if err != nil {
@@ -1414,54 +1911,26 @@ func (p *parser) assignment() (Statement, error) {
 		}
 		p.match(new_line)
 
-		switch opToken.kind {
-		case increment:
-			return &VariableAssignment{
-				Location: Location{
-					Start: expr.GetLocation().Start,
-					End:   value.GetLocation().End,
-				},
-				Operator: Assign,
-				Target:   expr,
-				Value: &BinaryExpression{
-					Location: Location{
-						Start: expr.GetLocation().Start,
-						End:   value.GetLocation().End,
-					},
-					Operator: Plus,
-					Left:     expr,
-					Right:    value,
-				},
-			}, nil
-		case decrement:
-			return &VariableAssignment{
-				Location: Location{
-					Start: expr.GetLocation().Start,
-					End:   value.GetLocation().End,
-				},
-				Operator: Assign,
-				Target:   expr,
-				Value: &BinaryExpression{
-					Location: Location{
-						Start: expr.GetLocation().Start,
-						End:   value.GetLocation().End,
-					},
-					Operator: Minus,
-					Left:     expr,
-					Right:    value,
-				},
-			}, nil
-		default:
-			return &VariableAssignment{
-				Location: Location{
-					Start: expr.GetLocation().Start,
-					End:   value.GetLocation().End,
-				},
-				Operator: Assign,
-				Target:   expr,
-				Value:    value,
-			}, nil
+		location := Location{
+			Start: expr.GetLocation().Start,
+			End:   value.GetLocation().End,
 		}
+		// Compound assignments desugar to `target = target <op> value`; the
+		// formatter recognizes the shared target location and prints the sugar.
+		if operator, ok := compoundAssignmentOperators[opToken.kind]; ok {
+			value = p.nodes.binaries.Put(BinaryExpression{
+				Location: location,
+				Operator: operator,
+				Left:     expr,
+				Right:    value,
+			})
+		}
+		return &VariableAssignment{
+			Location: location,
+			Operator: Assign,
+			Target:   expr,
+			Value:    value,
+		}, nil
 	}
 	p.match(new_line)
 
@@ -1516,6 +1985,30 @@ func (p *parser) recoverFromBadType() {
 // they recurse through the reporting parseType and propagate nil upward
 // without adding further diagnostics.
 func (p *parser) tryParseType() DeclaredType {
+	first := p.tryParseUnionMember()
+	if first == nil || !p.check(pipe) {
+		return first
+	}
+	union := &UnionType{Members: []DeclaredType{first}}
+	for p.match(pipe) {
+		p.skipNewlines()
+		errorsBefore := len(p.errors)
+		member := p.tryParseUnionMember()
+		if member == nil {
+			if len(p.errors) == errorsBefore {
+				p.addError(p.peek(), "Expected a type after '|'")
+			}
+			return nil
+		}
+		union.Members = append(union.Members, member)
+	}
+	union.Location = Location{Start: first.GetLocation().Start, End: union.Members[len(union.Members)-1].GetLocation().End}
+	return union
+}
+
+// tryParseUnionMember parses one type without a trailing `| ...`; a union
+// member that is itself a union must be parenthesized.
+func (p *parser) tryParseUnionMember() DeclaredType {
 	if p.match(mut) {
 		mutToken := p.previous()
 		inner := p.parseType()
@@ -1610,13 +2103,13 @@ func (p *parser) tryParseType() DeclaredType {
 				},
 			}
 		}
-		return &CustomType{
+		return p.parseNullableResult(&CustomType{
 			Name:     static.String(),
 			Location: static.Location,
 			Type:     *static,
 			nullable: p.match(question_mark),
 			TypeArgs: typeArgs,
-		}
+		})
 	}
 
 	// Check for function type: fn(ParamType) ReturnType
@@ -1687,7 +2180,7 @@ func (p *parser) tryParseType() DeclaredType {
 		// The return type is optional: every token that can legally follow a
 		// function type must be excluded here, because parseType reports when
 		// it cannot parse a type.
-		if !p.check(right_bracket) && !p.check(right_paren) && !p.check(comma) && !p.check(equal) && !p.check(new_line) && !p.check(greater_than) && !p.check(left_brace) && !p.check(right_brace) && !p.check(colon) && !p.check(bang) && !p.check(question_mark) && !returnStartsAdjacentCallArgs {
+		if !p.check(right_bracket) && !p.check(right_paren) && !p.check(comma) && !p.check(equal) && !p.check(new_line) && !p.check(greater_than) && !p.check(left_brace) && !p.check(right_brace) && !p.check(colon) && !p.check(bang) && !p.check(question_mark) && !p.check(pipe) && !returnStartsAdjacentCallArgs {
 			returnType = p.parseType()
 		}
 
@@ -1768,7 +2261,7 @@ func (p *parser) tryParseType() DeclaredType {
 				}
 			}
 
-			return &Map{
+			return p.parseNullableResult(&Map{
 				Key:      elementType,
 				Value:    valElementType,
 				nullable: p.match(question_mark),
@@ -1776,7 +2269,7 @@ func (p *parser) tryParseType() DeclaredType {
 					Start: bracket.getLocation().Start,
 					End:   endBracket.getLocation().Start,
 				},
-			}
+			})
 		}
 		if p.match(semicolon) {
 			lengthToken := p.peek()
@@ -1821,7 +2314,7 @@ func (p *parser) tryParseType() DeclaredType {
 				}
 			}
 			arrayType.nullable = p.match(question_mark)
-			return arrayType
+			return p.parseNullableResult(arrayType)
 		}
 		if !p.match(right_bracket) {
 			p.addError(p.peek(), "Expected ']'")
@@ -1848,16 +2341,36 @@ func (p *parser) tryParseType() DeclaredType {
 			}
 		}
 
-		return &List{
+		return p.parseNullableResult(&List{
 			Location: bracket.getLocation(),
 			Element:  elementType,
 			nullable: p.match(question_mark),
-		}
+		})
 	}
 
 	return nil
 }
 
+// parseNullableResult parses the `!Err` that may follow a nullable value
+// type, as in `[Str]?!Str`, the way parseNamedType's callers do for `Str?!Str`.
+func (p *parser) parseNullableResult(valType DeclaredType) DeclaredType {
+	if !valType.IsNullable() || !p.match(bang) {
+		return valType
+	}
+	errType := p.parseType()
+	if p.match(question_mark) {
+		p.addError(p.previous(), "Unexpected '?': Result can't be nullable")
+	}
+	return &ResultType{
+		Val: valType,
+		Err: errType,
+		Location: Location{
+			Start: valType.GetLocation().Start,
+			End:   Point{Row: p.previous().line, Col: p.previous().column},
+		},
+	}
+}
+
 func (p *parser) startsAdjacentCallArgsAfterFunctionType(paramClose token, hasRightParen bool) bool {
 	if !p.inCallTypeArguments || !hasRightParen || !p.check(left_paren) || !adjacent(paramClose.getLocation(), p.peek()) {
 		return false
@@ -1911,6 +2424,8 @@ func nullableDeclaredType(t DeclaredType) DeclaredType {
 		ty.Nullable = true
 	case *MutableType:
 		ty.nullable = true
+	case *UnionType:
+		ty.nullable = true
 	}
 	return t
 }
@@ -2007,20 +2522,20 @@ func (p *parser) parseStaticPath() *StaticProperty {
 			End:   Point{propName.line, propName.column + len(propName.text)},
 		},
 	}
-	prop.Target = &Identifier{
+	prop.Target = p.nodes.identifiers.Put(Identifier{
 		Location: Location{
 			Start: Point{namespace.line, namespace.column},
 			End:   Point{joint.line, joint.column - 1},
 		},
 		Name: namespace.text,
-	}
-	prop.Property = &Identifier{
+	})
+	prop.Property = p.nodes.identifiers.Put(Identifier{
 		Location: Location{
 			Start: Point{propName.line, propName.column},
 			End:   Point{propName.line, propName.column + len(propName.text)},
 		},
 		Name: propName.text,
-	}
+	})
 
 	for p.match(colon_colon) {
 		if p.check(identifier) {
@@ -2031,13 +2546,13 @@ func (p *parser) parseStaticPath() *StaticProperty {
 					End:   Point{propName.line, propName.column + len(propName.text)},
 				},
 				Target: prop,
-				Property: &Identifier{
+				Property: p.nodes.identifiers.Put(Identifier{
 					Location: Location{
 						Start: Point{propName.line, propName.column},
 						End:   Point{propName.line, propName.column + len(propName.text)},
 					},
 					Name: propName.text,
-				},
+				}),
 			}
 		} else {
 			p.addError(p.peek(), "Expected an identifier after '::'")
@@ -2118,6 +2633,10 @@ func (p *parser) parseExpression() (Expression, error) {
 	if p.check(select_) {
 		return p.selectExpr()
 	}
+	if p.match(if_) {
+		// An if used as a value; without an else it produces a Maybe.
+		return p.ifStatement()
+	}
 	return p.matchExpr()
 }
 
@@ -2143,6 +2662,9 @@ func (p *parser) selectExpr() (Expression, error) {
 	}
 
 	for !p.match(right_brace) {
+		if p.unclosed("}", "select") {
+			break
+		}
 		if c := p.parseInlineComment(); c != nil {
 			sel.Comments = append(sel.Comments, *c)
 			p.match(new_line)
@@ -2162,10 +2684,10 @@ func (p *parser) selectExpr() (Expression, error) {
 				p.addError(p.peek(), "Expected identifier after 'let'")
 			} else {
 				name := p.advance()
-				selCase.Binding = &Identifier{
+				selCase.Binding = p.nodes.identifiers.Put(Identifier{
 					Location: Location{Start: Point{Row: name.line, Col: name.column}},
 					Name:     name.text,
-				}
+				})
 			}
 			if p.check(equal) {
 				p.advance()
@@ -2270,10 +2792,26 @@ func (p *parser) matchExpr() (Expression, error) {
 			if p.match(new_line) {
 				continue
 			}
-			pattern, err := p.iterRange()
-			if err != nil {
+			var pattern Expression
+			var err error
+			if p.atStructPattern() {
+				pattern = p.structPattern()
+			} else if pattern, err = p.iterRange(); err != nil {
 				return nil, err
 			}
+			if p.check(pipe) {
+				or := &OrPattern{Location: Location{Start: pattern.GetLocation().Start}, Patterns: []Expression{pattern}}
+				for p.match(pipe) {
+					p.match(new_line)
+					alternative, err := p.iterRange()
+					if err != nil {
+						return nil, err
+					}
+					or.Patterns = append(or.Patterns, alternative)
+				}
+				or.Location.End = or.Patterns[len(or.Patterns)-1].GetLocation().End
+				pattern = or
+			}
 
 			if !p.check(fat_arrow) {
 				p.addError(p.peek(), "Expected '=>' after pattern")
@@ -2318,6 +2856,55 @@ func (p *parser) matchExpr() (Expression, error) {
 	return p.try(true)
 }
 
+// atStructPattern reports whether the next tokens open a struct pattern,
+// `Name{`, rather than an expression pattern.
+func (p *parser) atStructPattern() bool {
+	next := p.peek2()
+	return p.check(identifier) && next != nil && next.kind == left_brace
+}
+
+// structPattern parses `Name{field, field: pattern, ...}` in a match arm.
+func (p *parser) structPattern() *StructPattern {
+	name := p.advance()
+	pattern := &StructPattern{Name: Identifier{Name: name.text, Location: name.getLocation()}}
+	p.advance() // consume the '{'
+	for {
+		p.skipNewlines()
+		if p.check(right_brace) || p.isAtEnd() {
+			break
+		}
+		fieldName := p.consumeVariableName("Expected a field name in struct pattern")
+		field := StructPatternField{Name: Identifier{Name: fieldName.text, Location: fieldName.getLocation()}}
+		if p.match(colon) {
+			if p.atStructPattern() {
+				field.Pattern = p.structPattern()
+			} else {
+				value, err := p.or()
+				if err != nil {
+					p.addError(p.peek(), err.Error())
+				}
+				field.Pattern = value
+			}
+		}
+		pattern.Fields = append(pattern.Fields, field)
+		p.skipNewlines()
+		if !p.match(comma) {
+			break
+		}
+	}
+	if !p.match(right_brace) {
+		p.addError(p.peek(), "Expected '}' after struct pattern fields")
+		p.synchronizeToTokens(right_brace, fat_arrow)
+		p.match(right_brace)
+	}
+	end := p.previous()
+	pattern.Location = Location{
+		Start: name.getLocation().Start,
+		End:   Point{Row: end.line, Col: end.column},
+	}
+	return pattern
+}
+
 func (p *parser) parseConditionalMatch(keyword token) (Expression, error) {
 	conditionalMatch := &ConditionalMatchExpression{
 		Location: Location{
@@ -2454,10 +3041,10 @@ func (p *parser) try(allowCatch bool) (Expression, error) {
 			// Check if this is a function reference (no block) or variable binding (with block)
 			if p.check(left_brace) {
 				// Block syntax: -> var { ... }
-				catchVar = &Identifier{
+				catchVar = p.nodes.identifiers.Put(Identifier{
 					Name:     idToken.text,
 					Location: idToken.getLocation(),
-				}
+				})
 
 				block, err := p.block()
 				if err != nil {
@@ -2468,19 +3055,19 @@ func (p *parser) try(allowCatch bool) (Expression, error) {
 			} else {
 				// Function syntax: -> function_name or -> qualified::function
 				// Desugar to: -> err { function_name(err) } or -> err { qualified::function(err) }
-				catchVar = &Identifier{
+				catchVar = p.nodes.identifiers.Put(Identifier{
 					Name:     "err",
 					Location: idToken.getLocation(),
-				}
+				})
 
 				// Build the function expression - could be qualified with ::
 				var functionExpr Expression
 
 				// Start with the first identifier
-				functionExpr = &Identifier{
+				functionExpr = p.nodes.identifiers.Put(Identifier{
 					Name:     idToken.text,
 					Location: idToken.getLocation(),
-				}
+				})
 
 				// Parse qualified path (e.g., module::function or Type::method)
 				startLoc := idToken.getLocation()
@@ -2499,10 +3086,10 @@ func (p *parser) try(allowCatch bool) (Expression, error) {
 							End:   propToken.getLocation().End,
 						},
 						Target: functionExpr,
-						Property: &Identifier{
+						Property: p.nodes.identifiers.Put(Identifier{
 							Location: propToken.getLocation(),
 							Name:     propToken.text,
-						},
+						}),
 					}
 					endLoc = propToken.getLocation()
 				}
@@ -2538,17 +3125,17 @@ func (p *parser) try(allowCatch bool) (Expression, error) {
 								{
 									Location: endLoc,
 									Name:     "",
-									Value: &Identifier{
+									Value: p.nodes.identifiers.Put(Identifier{
 										Name:     "err",
 										Location: endLoc,
-									},
+									}),
 								},
 							},
 						},
 					}
 				} else {
 					// Simple function name - create FunctionCall
-					callStmt = &FunctionCall{
+					callStmt = p.nodes.calls.Put(FunctionCall{
 						Location: endLoc,
 						Name:     idToken.text,
 						Args: []Argument{
@@ -2561,7 +3148,7 @@ func (p *parser) try(allowCatch bool) (Expression, error) {
 								},
 							},
 						},
-					}
+					})
 				}
 
 				// Wrap in a statement
@@ -2590,7 +3177,6 @@ func (p *parser) try(allowCatch bool) (Expression, error) {
 				Start: keyword.Location.Start,
 				End:   endLoc,
 			},
-			keyword:    keyword,
 			Expression: expr,
 			CatchVar:   catchVar,
 			CatchBlock: catchBlock,
@@ -2600,7 +3186,11 @@ func (p *parser) try(allowCatch bool) (Expression, error) {
 	// Match subjects enter at this level only to recognize a leading `try`;
 	// ordinary subjects retain their existing struct/block disambiguation.
 	if !allowCatch {
-		return p.or()
+		subject, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return p.pipe(subject)
 	}
 
 	// Not a `try` expression: parse the underlying expression as usual.
@@ -2638,6 +3228,7 @@ func (p *parser) functionDef(asMethod bool, isTest bool) (Statement, error) {
 	if p.match(fn) {
 		keyword := p.previous()
 		var name any = ""
+		var nameLocation Location
 		mutates := p.check(mut) && !(asMethod && p.check(mut, left_paren))
 		if mutates {
 			p.advance()
@@ -2648,8 +3239,10 @@ func (p *parser) functionDef(asMethod bool, isTest bool) (Statement, error) {
 
 		if path := p.parseStaticPath(); path != nil {
 			name = path
+			nameLocation = path.Location
 		} else if p.check(identifier) || (asMethod && p.isKeyword(p.peek().kind)) {
 			nameToken := p.advance()
+			nameLocation = nameToken.getLocation()
 			if nameToken.text == "" {
 				name = string(nameToken.kind)
 			} else {
@@ -2732,9 +3325,14 @@ func (p *parser) functionDef(asMethod bool, isTest bool) (Statement, error) {
 			// as an identifier, so anonymous functions would otherwise
 			// swallow it as an untyped parameter and miscount arguments.
 			// Reject it explicitly and recover by parsing the real
-			// parameter that follows. (#286)
+			// parameter that follows. (#286) Languages before 0.22
+			// accepted the spelling, so the recovered parameter carries
+			// the mutability in its type for `ard migrate` to print.
+			var legacyMut *ParseError
 			if p.check(mut) {
 				p.addError(p.peek(), "parameter mutability belongs in the type ('name: mut T'), not before the name")
+				reported := p.errors[len(p.errors)-1]
+				legacyMut = &reported
 				p.advance() // consume 'mut' and continue with the parameter name
 			}
 			nameToken := p.consumeVariableName("Expected parameter name")
@@ -2762,6 +3360,13 @@ func (p *parser) functionDef(asMethod bool, isTest bool) (Statement, error) {
 				}
 			}
 
+			if legacyMut != nil && paramType != nil {
+				if _, ok := paramType.(*MutableType); !ok {
+					paramType = &MutableType{Location: paramType.GetLocation(), Inner: paramType}
+				}
+				p.addLegacy(*legacyMut, legacyMutParameterRemovedIn)
+			}
+
 			params = append(params, Parameter{
 				Location: nameToken.getLocation(),
 				Name:     nameToken.text,
@@ -2806,13 +3411,14 @@ func (p *parser) functionDef(asMethod bool, isTest bool) (Statement, error) {
 		}
 
 		fnDef := &FunctionDeclaration{
-			Private:    private,
-			Mutates:    asMethod && mutates,
-			IsTest:     isTest,
-			Parameters: params,
-			ReturnType: returnType,
-			Body:       statements,
-			Comments:   functionComments, // Add collected comments
+			NameLocation: nameLocation,
+			Private:      private,
+			Mutates:      asMethod && mutates,
+			IsTest:       isTest,
+			Parameters:   params,
+			ReturnType:   returnType,
+			Body:         statements,
+			Comments:     functionComments, // Add collected comments
 			Location: Location{
 				Start: Point{Row: keyword.line, Col: keyword.column},
 				End:   Point{Row: p.previous().line, Col: p.previous().column},
@@ -2831,7 +3437,49 @@ func (p *parser) functionDef(asMethod bool, isTest bool) (Statement, error) {
 		}
 	}
 
-	return p.structInstance()
+	value, err := p.structInstance()
+	if err != nil {
+		return nil, err
+	}
+	return p.pipe(value)
+}
+
+// pipe parses the `|> call` stages that follow value. A stage may start on the
+// next line, so a long chain can put one call per line.
+func (p *parser) pipe(value Expression) (Expression, error) {
+	if value == nil {
+		return value, nil
+	}
+	for {
+		savedIndex := p.index
+		p.skipNewlines()
+		if !p.match(pipe_arrow) {
+			p.index = savedIndex
+			return value, nil
+		}
+		operator := p.previous()
+		p.skipNewlines()
+		call, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		switch call.(type) {
+		case *FunctionCall, *StaticFunction, *InstanceMethod, *Identifier, *StaticProperty:
+		default:
+			p.addError(operator, "Expected a function call after '|>'")
+			if call == nil {
+				return value, nil
+			}
+		}
+		value = &PipeExpression{
+			Location: Location{
+				Start: value.GetLocation().Start,
+				End:   call.GetLocation().End,
+			},
+			Value: value,
+			Call:  call,
+		}
+	}
 }
 
 func (p *parser) structInstance() (Expression, error) {
@@ -2874,13 +3522,13 @@ func (p *parser) structInstance() (Expression, error) {
 			return nil, nil
 		}
 
-		name := &Identifier{
+		name := p.nodes.identifiers.Put(Identifier{
 			Name: nameToken.text,
 			Location: Location{
 				Start: Point{Row: nameToken.line, Col: nameToken.column},
 				End:   Point{Row: nameToken.line, Col: nameToken.column + len(nameToken.text)},
 			},
-		}
+		})
 		instance, err := p.parseStructFields(name)
 		if err != nil {
 			return nil, err
@@ -2926,13 +3574,13 @@ func (p *parser) tryGenericStructInstance(index int, static *StaticProperty) (Ex
 	}
 	p.advance() // consume the '>'
 
-	name := &Identifier{
+	name := p.nodes.identifiers.Put(Identifier{
 		Name: nameToken.text,
 		Location: Location{
 			Start: Point{Row: nameToken.line, Col: nameToken.column},
 			End:   Point{Row: nameToken.line, Col: nameToken.column + len(nameToken.text)},
 		},
-	}
+	})
 	instance, err := p.parseStructFields(name)
 	if err != nil {
 		return nil, true, err
@@ -2970,6 +3618,9 @@ func (p *parser) parseStructFields(name *Identifier) (*StructInstance, error) {
 	p.match(new_line)
 
 	for !p.match(right_brace) {
+		if p.unclosed("}", "struct instance") {
+			break
+		}
 		// Parse and collect comments between properties
 		if c := p.parseInlineComment(); c != nil {
 			instance.Comments = append(instance.Comments, *c)
@@ -2982,6 +3633,20 @@ func (p *parser) parseStructFields(name *Identifier) (*StructInstance, error) {
 			continue
 		}
 
+		if spread := p.peek(); p.match(dot_dot) {
+			base, err := p.parseExpression()
+			if err != nil {
+				return nil, err
+			}
+			if instance.Base != nil {
+				p.addError(spread, "A struct literal can only have one '..' base")
+			}
+			instance.Base = base
+			p.match(comma)
+			p.match(new_line)
+			continue
+		}
+
 		propToken := p.consumeVariableName("Expected name")
 
 		if !p.check(colon) {
@@ -3050,7 +3715,7 @@ func (p *parser) or() (Expression, error) {
 		if err != nil {
 			return nil, err
 		}
-		return &BinaryExpression{
+		return p.nodes.binaries.Put(BinaryExpression{
 			Location: Location{
 				Start: left.GetLocation().Start,
 				End:   right.GetLocation().End,
@@ -3058,7 +3723,7 @@ func (p *parser) or() (Expression, error) {
 			Operator: Or,
 			Left:     left,
 			Right:    right,
-		}, nil
+		}), nil
 	}
 	return left, nil
 }
@@ -3073,7 +3738,7 @@ func (p *parser) and() (Expression, error) {
 		if err != nil {
 			return nil, err
 		}
-		return &BinaryExpression{
+		return p.nodes.binaries.Put(BinaryExpression{
 			Location: Location{
 				Start: left.GetLocation().Start,
 				End:   right.GetLocation().End,
@@ -3081,7 +3746,7 @@ func (p *parser) and() (Expression, error) {
 			Operator: And,
 			Left:     left,
 			Right:    right,
-		}, nil
+		}), nil
 	}
 	return left, nil
 }
@@ -3171,7 +3836,7 @@ func (p *parser) comparison() (Expression, error) {
 	}
 
 	// Single comparison
-	return &BinaryExpression{
+	return p.nodes.binaries.Put(BinaryExpression{
 		Location: Location{
 			Start: left.GetLocation().Start,
 			End:   right.GetLocation().End,
@@ -3179,7 +3844,7 @@ func (p *parser) comparison() (Expression, error) {
 		Operator: operator,
 		Left:     left,
 		Right:    right,
-	}, nil
+	}), nil
 }
 
 func (p *parser) modulo() (Expression, error) {
@@ -3192,7 +3857,7 @@ func (p *parser) modulo() (Expression, error) {
 		if err != nil {
 			return nil, err
 		}
-		return &BinaryExpression{
+		return p.nodes.binaries.Put(BinaryExpression{
 			Location: Location{
 				Start: left.GetLocation().Start,
 				End:   right.GetLocation().End,
@@ -3200,7 +3865,7 @@ func (p *parser) modulo() (Expression, error) {
 			Operator: Modulo,
 			Left:     left,
 			Right:    right,
-		}, nil
+		}), nil
 	}
 	return left, nil
 }
@@ -3221,7 +3886,7 @@ func (p *parser) addition() (Expression, error) {
 		if err != nil {
 			return nil, err
 		}
-		left = &BinaryExpression{
+		left = p.nodes.binaries.Put(BinaryExpression{
 			Location: Location{
 				Start: left.GetLocation().Start,
 				End:   right.GetLocation().End,
@@ -3229,13 +3894,13 @@ func (p *parser) addition() (Expression, error) {
 			Operator: operator,
 			Left:     left,
 			Right:    right,
-		}
+		})
 	}
 	return left, nil
 }
 
 func (p *parser) multiplication() (Expression, error) {
-	left, err := p.unary()
+	left, err := p.as()
 	if err != nil {
 		return nil, err
 	}
@@ -3246,11 +3911,11 @@ func (p *parser) multiplication() (Expression, error) {
 			operator = Divide
 		}
 
-		right, err := p.unary()
+		right, err := p.as()
 		if err != nil {
 			return nil, err
 		}
-		left = &BinaryExpression{
+		left = p.nodes.binaries.Put(BinaryExpression{
 			Location: Location{
 				Start: left.GetLocation().Start,
 				End:   right.GetLocation().End,
@@ -3258,11 +3923,35 @@ func (p *parser) multiplication() (Expression, error) {
 			Operator: operator,
 			Left:     left,
 			Right:    right,
-		}
+		})
 	}
 	return left, nil
 }
 
+// as parses `value as Type`, which binds tighter than the arithmetic
+// operators so `a as T` needs no parentheses inside a larger expression.
+func (p *parser) as() (Expression, error) {
+	value, err := p.unary()
+	if err != nil || value == nil {
+		return value, err
+	}
+	for p.match(as) {
+		declared := p.parseTypeAfter("'as'")
+		if declared == nil {
+			return value, nil
+		}
+		value = &AsExpression{
+			Location: Location{
+				Start: value.GetLocation().Start,
+				End:   declared.GetLocation().End,
+			},
+			Value: value,
+			Type:  declared,
+		}
+	}
+	return value, nil
+}
+
 func (p *parser) unary() (Expression, error) {
 	if p.match(mut) {
 		mutToken := p.previous()
@@ -3362,14 +4051,14 @@ func (p *parser) memberAccess() (Expression, error) {
 					},
 				}
 			case *FunctionCall:
-				expr = &InstanceMethod{
+				expr = p.nodes.instanceMethods.Put(InstanceMethod{
 					Target: expr,
 					Method: *prop,
 					Location: Location{
 						Start: expr.GetLocation().Start,
 						End:   prop.GetLocation().End,
 					},
-				}
+				})
 			}
 		} else {
 			if staticCall, ok, err := p.tryStaticGenericFunctionCall(expr); err != nil {
@@ -3377,6 +4066,7 @@ func (p *parser) memberAccess() (Expression, error) {
 			} else if ok {
 				expr = staticCall
 			} else {
+				p.markFormatTemplate(expr)
 				call, err := p.memberCall()
 				if err != nil {
 					return nil, err
@@ -3639,7 +4329,7 @@ func (p *parser) call() (Expression, error) {
 					p.synchronizeToTokens(right_paren)
 					if !p.check(right_paren) {
 						// Could not find ')', return partial function call
-						return &FunctionCall{
+						return p.nodes.calls.Put(FunctionCall{
 							Name:     expr.(*Identifier).Name,
 							TypeArgs: typeArgs,
 							Args:     args,
@@ -3648,12 +4338,12 @@ func (p *parser) call() (Expression, error) {
 								Start: expr.GetLocation().Start,
 								End:   Point{Row: p.previous().line, Col: p.previous().column},
 							},
-						}, nil
+						}), nil
 					}
 				}
 				p.advance() // consume the ')'
 
-				return &FunctionCall{
+				return p.nodes.calls.Put(FunctionCall{
 					Name:     expr.(*Identifier).Name,
 					TypeArgs: typeArgs,
 					Args:     args,
@@ -3662,7 +4352,7 @@ func (p *parser) call() (Expression, error) {
 						Start: expr.GetLocation().Start,
 						End:   Point{Row: p.previous().line, Col: p.previous().column},
 					},
-				}, nil
+				}), nil
 			}
 		}
 
@@ -3708,7 +4398,7 @@ func (p *parser) call() (Expression, error) {
 			p.synchronizeToTokens(right_paren)
 			if !p.check(right_paren) {
 				// Could not find ')', return partial function call
-				return functionCallForCallee(expr, nil, args, argComments, Location{
+				return p.functionCallForCallee(expr, nil, args, argComments, Location{
 					Start: expr.GetLocation().Start,
 					End:   callEnd,
 				}), nil
@@ -3716,7 +4406,7 @@ func (p *parser) call() (Expression, error) {
 		}
 		p.advance() // consume the ')'
 
-		return functionCallForCallee(expr, nil, args, argComments, Location{
+		return p.functionCallForCallee(expr, nil, args, argComments, Location{
 			Start: expr.GetLocation().Start,
 			End:   Point{Row: p.previous().line, Col: p.previous().column},
 		}), nil
@@ -3725,15 +4415,15 @@ func (p *parser) call() (Expression, error) {
 	return expr, nil
 }
 
-func functionCallForCallee(callee Expression, typeArgs []DeclaredType, args []Argument, comments []Comment, location Location) Expression {
+func (p *parser) functionCallForCallee(callee Expression, typeArgs []DeclaredType, args []Argument, comments []Comment, location Location) Expression {
 	if id, ok := callee.(*Identifier); ok {
-		return &FunctionCall{
+		return p.nodes.calls.Put(FunctionCall{
 			Name:     id.Name,
 			TypeArgs: typeArgs,
 			Args:     args,
 			Comments: comments,
 			Location: location,
-		}
+		})
 	}
 	return &FunctionValueCall{
 		Callee:   callee,
@@ -3765,10 +4455,10 @@ func (p *parser) primary() (Expression, error) {
 	}
 	if p.match(number) {
 		tok := p.previous()
-		return &NumLiteral{
+		return p.nodes.numbers.Put(NumLiteral{
 			Value:    tok.text,
 			Location: tok.getLocation(),
-		}, nil
+		}), nil
 	}
 	if p.match(string_) {
 		return p.string()
@@ -3792,10 +4482,10 @@ func (p *parser) primary() (Expression, error) {
 	}
 	if p.match(identifier) {
 		tok := p.previous()
-		return &Identifier{
+		return p.nodes.identifiers.Put(Identifier{
 			Name:     tok.text,
 			Location: tok.getLocation(),
-		}, nil
+		}), nil
 	}
 	if p.match(left_paren) {
 		// Check for empty parentheses (void literal)
@@ -3850,22 +4540,46 @@ func (p *parser) primary() (Expression, error) {
 		if name == "" {
 			name = string(tok.kind)
 		}
-		return &Identifier{
+		return p.nodes.identifiers.Put(Identifier{
 			Name:     name,
 			Location: tok.getLocation(),
-		}, nil
+		}), nil
 	default:
 		peek := p.peek()
-		p.addError(peek, fmt.Sprintf("Unexpected token: %s", peek.kind))
+		if spelling, ok := misspelledCompoundAssignment(p.previous(), peek); ok {
+			p.addError(peek, fmt.Sprintf("Compound assignment is written `%s`", spelling))
+		} else {
+			p.addError(peek, fmt.Sprintf("Unexpected token: %s", peek.kind))
+		}
 		// Advance past the unexpected token to prevent infinite loops
 		p.advance()
-		return &Identifier{
+		return p.nodes.identifiers.Put(Identifier{
 			Name:     peek.text,
 			Location: peek.getLocation(),
-		}, nil
+		}), nil
 	}
 }
 
+// misspelledCompoundAssignment recognizes `+=`, `-=`, `*=` and `/=`, which
+// reach the parser as an operator immediately followed by `=`, and returns
+// Ard's spelling of the same assignment.
+func misspelledCompoundAssignment(operator, next *token) (string, bool) {
+	if operator == nil || next == nil || next.kind != equal || next.line != operator.line || next.column != operator.column+1 {
+		return "", false
+	}
+	switch operator.kind {
+	case plus:
+		return "=+", true
+	case minus:
+		return "=-", true
+	case star:
+		return "=*", true
+	case slash:
+		return "=/", true
+	}
+	return "", false
+}
+
 func (p *parser) list() (Expression, error) {
 	startToken := p.previous()
 	if p.check(colon) {
@@ -3878,6 +4592,14 @@ func (p *parser) list() (Expression, error) {
 	items := []Expression{}
 	comments := []Comment{}
 	for !p.match(right_bracket) {
+		if p.check(right_brace) {
+			// a '}' closes an enclosing block, so this list was never closed
+			p.addError(p.peek(), "Expected ']' to close list")
+			break
+		}
+		if p.unclosed("]", "list") {
+			break
+		}
 		// Parse and collect comments
 		if c := p.parseInlineComment(); c != nil {
 			comments = append(comments, *c)
@@ -3938,6 +4660,9 @@ func (p *parser) map_() (Expression, error) {
 	}
 
 	for !p.match(right_bracket) {
+		if p.unclosed("]", "map") {
+			break
+		}
 		// Parse and collect comments
 		if c := p.parseInlineComment(); c != nil {
 			node.Comments = append(node.Comments, *c)
@@ -3989,10 +4714,14 @@ func (p *parser) map_() (Expression, error) {
 
 func (p *parser) string() (Expression, error) {
 	tok := p.previous()
-	str := &StrLiteral{
+	if p.formatTemplate > 0 && p.index-1 == p.formatTemplate {
+		p.formatTemplate = 0
+		return p.rawTemplate(*tok), nil
+	}
+	str := p.nodes.strs.Put(StrLiteral{
 		Value:    tok.text,
 		Location: tok.getLocation(),
-	}
+	})
 	if p.match(expr_open) {
 		chunks := []Expression{str}
 		for !p.check(expr_close) {
@@ -4028,6 +4757,85 @@ func (p *parser) string() (Expression, error) {
 	return str, nil
 }
 
+// markFormatTemplate notes the string literal that starts the arguments of
+// `Str::format(`, which string() then reads as a raw template.
+func (p *parser) markFormatTemplate(target Expression) {
+	if id, ok := target.(*Identifier); !ok || id.Name != "Str" {
+		return
+	}
+	if !p.check(identifier) || p.peek().text != "format" {
+		return
+	}
+	i := p.index + 1
+	if i >= len(p.tokens) || p.tokens[i].kind != left_paren {
+		return
+	}
+	for i++; i < len(p.tokens) && p.tokens[i].kind == new_line; i++ {
+	}
+	if i < len(p.tokens) && p.tokens[i].kind == string_ {
+		p.formatTemplate = i
+	}
+}
+
+// rawTemplate reads the string literal starting at first as written: the
+// lexer's interpolations go back into the text with their braces, so the
+// checker can parse Str::format placeholders such as {0:>8} itself.
+func (p *parser) rawTemplate(first token) Expression {
+	var text strings.Builder
+	text.WriteString(first.text)
+	for p.match(expr_open) {
+		open := p.previous()
+		depth := 0
+		for !p.isAtEnd() && (depth > 0 || !p.check(expr_close)) {
+			switch p.advance().kind {
+			case expr_open:
+				depth++
+			case expr_close:
+				depth--
+			}
+		}
+		if p.isAtEnd() {
+			p.addError(p.previous(), "Unterminated string interpolation")
+			break
+		}
+		close := p.advance()
+		text.WriteString("{" + p.rawSource(Point{Row: open.line, Col: open.column + 1}, Point{Row: close.line, Col: close.column}) + "}")
+		if !p.match(string_) {
+			break
+		}
+		text.WriteString(p.previous().text)
+	}
+	return p.nodes.strs.Put(StrLiteral{
+		Value:    text.String(),
+		Template: true,
+		Location: Location{Start: first.getLocation().Start, End: p.previous().getLocation().End},
+	})
+}
+
+// rawSource returns the source from start up to, but not including, end,
+// exactly as written.
+func (p *parser) rawSource(start Point, end Point) string {
+	if start.Row < 1 || end.Row > len(p.lines) || start.Row > end.Row {
+		return ""
+	}
+	var text strings.Builder
+	for row := start.Row; row <= end.Row; row++ {
+		line := p.lines[row-1]
+		from, to := 0, len(line)
+		if row == start.Row {
+			from = min(start.Col-1, len(line))
+		}
+		if row == end.Row {
+			to = max(from, min(end.Col-1, len(line)))
+		}
+		text.WriteString(line[from:to])
+		if row < end.Row {
+			text.WriteByte('\n')
+		}
+	}
+	return text.String()
+}
+
 func (p *parser) advance() token {
 	if !p.isAtEnd() {
 		p.index++
@@ -4091,8 +4899,30 @@ func (p *parser) isKeyword(k kind) bool {
 }
 
 /* Error creation helpers */
+
+// syntaxError is an error that stops the statement being parsed. The
+// statement loops record it and resynchronize, so it never ends the parse.
+type syntaxError struct {
+	fileName string
+	at       token
+	msg      string
+}
+
+func (e *syntaxError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", e.fileName, e.at.line, e.at.column, e.msg)
+}
+
 func (p *parser) makeError(at *token, msg string) error {
-	return fmt.Errorf("%s:%d:%d: %s", p.fileName, at.line, at.column, msg)
+	return &syntaxError{fileName: p.fileName, at: *at, msg: msg}
+}
+
+// recordError adds an error that stopped a statement to the parse errors.
+func (p *parser) recordError(err error) {
+	if syntaxErr, ok := err.(*syntaxError); ok {
+		p.addError(&syntaxErr.at, syntaxErr.msg)
+		return
+	}
+	p.addError(p.peek(), err.Error())
 }
 
 /* conditionally advance if the current token is one of those provided */
@@ -4208,7 +5038,7 @@ func (p *parser) parseFunctionArguments() ([]Argument, []Comment, error) {
 		} else {
 			// Check if we've already seen named arguments
 			if hasNamedArgs {
-				return nil, nil, fmt.Errorf("positional arguments cannot follow named arguments")
+				return nil, nil, p.makeError(p.peek(), "positional arguments cannot follow named arguments")
 			}
 
 			arg, err := p.parseExpression()
@@ -11,9 +11,11 @@ import (
 var compareOptions = cmp.Options{
 	cmpopts.SortMaps(func(a, b string) bool { return a < b }),
 	cmpopts.IgnoreFields(EnumDefinition{}, "NameLocation"),
+	cmpopts.IgnoreFields(FunctionDeclaration{}, "NameLocation"),
 	cmpopts.IgnoreFields(VariableDeclaration{}, "NameLocation"),
 	cmpopts.IgnoreFields(Import{}, "PathLocation"),
 	cmp.AllowUnexported(MutableType{}),
+	cmp.AllowUnexported(UnionType{}),
 	cmpopts.IgnoreUnexported(
 		Identifier{},
 		IntType{},
@@ -221,6 +223,28 @@ func TestWhileLoop(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "While let",
+			input: `while let item = queue.pop() {}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&WhileLoop{
+						Condition: &InstanceMethod{
+							Target: &Identifier{Name: "queue"},
+							Method: FunctionCall{Name: "pop", Args: []Argument{}, Comments: []Comment{}},
+						},
+						Let:  &Identifier{Name: "item"},
+						Body: []Statement{},
+					},
+				},
+			},
+		},
+		{
+			name:     "while let needs an equals sign",
+			input:    `while let item queue.pop() {}`,
+			wantErrs: []string{"Expected '=' after variable name"},
+		},
 	})
 }
 func TestIfAndElse(t *testing.T) {
@@ -342,6 +366,49 @@ func TestIfAndElse(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "if let binds the unwrapped value",
+			input: `if let user = find(id) {} else {}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&IfStatement{
+						Let: &Identifier{Name: "user"},
+						Condition: &FunctionCall{
+							Name:     "find",
+							Args:     []Argument{{Value: &Identifier{Name: "id"}}},
+							Comments: []Comment{},
+						},
+						Body: []Statement{},
+						Else: &IfStatement{
+							Condition: nil,
+							Body:      []Statement{},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "if let needs an equals sign",
+			input:    `if let user find(id) {}`,
+			wantErrs: []string{"Expected '=' after variable name"},
+		},
+		{
+			name:  "if as a variable's value",
+			input: `let x = if true { 1 }`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&VariableDeclaration{
+						Name: "x",
+						Value: &IfStatement{
+							Condition: &BoolLiteral{Value: true},
+							Body:      []Statement{&NumLiteral{Value: "1"}},
+						},
+					},
+				},
+			},
+		},
 	})
 }
 func TestForInLoops(t *testing.T) {
@@ -361,6 +428,36 @@ func TestForInLoops(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Iterating over a range with a step",
+			input: `for i in 10..0 by -2 {}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&RangeLoop{
+						Cursor: Identifier{Name: "i"},
+						Start:  &NumLiteral{Value: "10"},
+						End:    &NumLiteral{Value: "0"},
+						Step:   &UnaryExpression{Operator: Minus, Operand: &NumLiteral{Value: "2"}},
+						Body:   []Statement{},
+					},
+				},
+			},
+		},
+		{
+			name:  "by is only a step after a range",
+			input: `for by in items {}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&ForInLoop{
+						Cursor:   Identifier{Name: "by"},
+						Iterable: &Identifier{Name: "items"},
+						Body:     []Statement{},
+					},
+				},
+			},
+		},
 		{
 			name:  "Iterating over a string",
 			input: `for char in "foobar" {}`,
@@ -523,6 +620,45 @@ func TestTypeUnion(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Inline union annotations",
+			input: `fn show(value: Int | Str) Bool | Str {}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&FunctionDeclaration{
+						Name: "show",
+						Parameters: []Parameter{
+							{
+								Name: "value",
+								Type: &UnionType{Members: []DeclaredType{&IntType{}, &StringType{}}},
+							},
+						},
+						ReturnType: &UnionType{Members: []DeclaredType{&BooleanType{}, &StringType{}}},
+						Body:       []Statement{},
+					},
+				},
+			},
+		},
+		{
+			name:  "Grouped nullable inline union",
+			input: `let value: (Int | Str)? = 1`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&VariableDeclaration{
+						Name:  "value",
+						Value: &NumLiteral{Value: "1"},
+						Type:  &UnionType{Members: []DeclaredType{&IntType{}, &StringType{}}, nullable: true},
+					},
+				},
+			},
+		},
+		{
+			name:     "Inline union missing a member",
+			input:    `let value: Int | = 1`,
+			wantErrs: []string{"Expected a type after '|'"},
+		},
 		{
 			name: "Type union with leading pipe after equals new line",
 			input: `type X =
@@ -0,0 +1,109 @@
+package parse
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRecoveryReportsErrorsInSeparateDeclarations(t *testing.T) {
+	result := Parse([]byte(`fn one() Int {
+  let x = 1 + * 2
+  x
+}
+
+fn two(a: Int) Int {
+  add(a: 1, 2)
+}
+
+fn three() Int {
+  3
+}
+`), "test.ard")
+
+	if len(result.Errors) != 2 {
+		t.Fatalf("got %d errors, want one per broken function: %v", len(result.Errors), result.Errors)
+	}
+	if row := result.Errors[0].Location.Start.Row; row != 2 {
+		t.Errorf("first error on line %d, want 2", row)
+	}
+	if !strings.Contains(result.Errors[1].Message, "positional arguments cannot follow named arguments") {
+		t.Errorf("second error = %q", result.Errors[1].Message)
+	}
+	if row := result.Errors[1].Location.Start.Row; row != 7 {
+		t.Errorf("second error on line %d, want 7", row)
+	}
+	if got := len(result.Program.Statements); got != 3 {
+		t.Fatalf("got %d declarations, want all 3", got)
+	}
+}
+
+func TestRecoveryKeepsTheRestOfABlock(t *testing.T) {
+	result := Parse([]byte(`fn main() {
+  let x = 1 + * 2
+  let y = 3
+}
+`), "test.ard")
+
+	if len(result.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1: %v", len(result.Errors), result.Errors)
+	}
+	fn := result.Program.Statements[0].(*FunctionDeclaration)
+	if len(fn.Body) != 2 {
+		t.Fatalf("got %d statements in the body, want both", len(fn.Body))
+	}
+	if def, ok := fn.Body[1].(*VariableDeclaration); !ok || def.Name != "y" {
+		t.Fatalf("second statement = %#v, want `let y`", fn.Body[1])
+	}
+}
+
+func TestRecoveryEndsABlockMissingItsBrace(t *testing.T) {
+	result := Parse([]byte(`fn one() {
+  if true {
+    let x = 1
+}
+
+fn two() Int {
+  2
+}
+`), "test.ard")
+
+	if len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "Expected '}' to close block") {
+		t.Fatalf("errors = %v, want one missing '}'", result.Errors)
+	}
+	if got := len(result.Program.Statements); got != 2 {
+		t.Fatalf("got %d declarations, want `two` parsed on its own", got)
+	}
+}
+
+func TestUnclosedDelimitersDoNotHang(t *testing.T) {
+	for _, input := range []string{
+		"fn main() {\n  let z = [1, 2\n  3\n}\n",
+		"fn main() {\n  let m = [\"a\": 1\n",
+		"fn main() {\n  Point{x: 1,\n",
+		"struct Point {\n  x: Int,\n",
+		"enum Dir {\n  Up,\n",
+		"impl Point {\n  fn x() Int {\n",
+		"trait Named {\n  fn name(",
+	} {
+		assertParseCompletes(t, input, true)
+	}
+}
+
+func TestRecoveryLeavesNoNilDeclarations(t *testing.T) {
+	for _, input := range []string{
+		"impl)Point {\n  fn x() Int { 1 }\n}\n",
+		"trait)Named {\n  fn name() Str\n}\n",
+		"private trait {\n}\n",
+	} {
+		result := Parse([]byte(input), "test.ard")
+		if len(result.Errors) == 0 {
+			t.Fatalf("%q: expected a parse error", input)
+		}
+		for _, stmt := range result.Program.Statements {
+			if stmt == nil || reflect.ValueOf(stmt).IsNil() {
+				t.Fatalf("%q: got a nil %T declaration", input, stmt)
+			}
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package parse
+
+import (
+	"reflect"
+	"sync"
+)
+
+// relocated moves a node to another line by finding the Points in its exported
+// fields through reflection and rewriting them. Unexported fields are copied
+// as they are, so a node must not keep a position in one;
+// TestNodePositionsAreInExportedFields holds every type in ast.go to that. The
+// rewrite happens on a copy, because parse trees are shared and must never
+// change once built.
+
+var pointType = reflect.TypeOf(Point{})
+
+// relocated returns a copy of stmt moved down by rows lines. Parts of the
+// tree that hold no positions, such as names, are shared with stmt.
+func relocated(stmt Statement, rows int) Statement {
+	if rows == 0 {
+		return stmt
+	}
+	var moved Statement
+	relocateInto(reflect.ValueOf(&moved).Elem(), reflect.ValueOf(&stmt).Elem(), rows)
+	return moved
+}
+
+func relocatePoint(point Point, rows int) Point {
+	// a zero Point marks a position the parser did not have
+	if point.Row > 0 {
+		point.Row += rows
+	}
+	return point
+}
+
+// relocateInto sets dst to a copy of src moved down by rows lines.
+func relocateInto(dst reflect.Value, src reflect.Value, rows int) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() || !holdsPositions(src.Type().Elem()) {
+			dst.Set(src)
+			return
+		}
+		moved := reflect.New(src.Type().Elem())
+		relocateInto(moved.Elem(), src.Elem(), rows)
+		dst.Set(moved)
+	case reflect.Interface:
+		if src.IsNil() {
+			dst.Set(src)
+			return
+		}
+		value := src.Elem()
+		if !holdsPositions(value.Type()) {
+			dst.Set(value)
+			return
+		}
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				dst.Set(value)
+				return
+			}
+			moved := reflect.New(value.Type().Elem())
+			relocateInto(moved.Elem(), value.Elem(), rows)
+			dst.Set(moved)
+			return
+		}
+		moved := reflect.New(value.Type()).Elem()
+		relocateInto(moved, value, rows)
+		dst.Set(moved)
+	case reflect.Slice:
+		if src.IsNil() || !holdsPositions(src.Type().Elem()) {
+			dst.Set(src)
+			return
+		}
+		moved := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := range src.Len() {
+			relocateInto(moved.Index(i), src.Index(i), rows)
+		}
+		dst.Set(moved)
+	case reflect.Array:
+		for i := range src.Len() {
+			relocateInto(dst.Index(i), src.Index(i), rows)
+		}
+	case reflect.Map:
+		if src.IsNil() || !holdsPositions(src.Type()) {
+			dst.Set(src)
+			return
+		}
+		moved := reflect.MakeMapWithSize(src.Type(), src.Len())
+		key := reflect.New(src.Type().Key()).Elem()
+		value := reflect.New(src.Type().Elem()).Elem()
+		for iter := src.MapRange(); iter.Next(); {
+			relocateInto(key, iter.Key(), rows)
+			relocateInto(value, iter.Value(), rows)
+			moved.SetMapIndex(key, value)
+		}
+		dst.Set(moved)
+	case reflect.Struct:
+		// copy the whole struct first so the fields that hold no positions,
+		// including unexported ones, come along
+		dst.Set(src)
+		if src.Type() == pointType {
+			if row := src.Field(0).Int(); row > 0 {
+				dst.Field(0).SetInt(row + int64(rows))
+			}
+			return
+		}
+		for _, i := range positionFields(src.Type()) {
+			relocateInto(dst.Field(i), src.Field(i), rows)
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+var structPositions sync.Map // reflect.Type -> []int
+
+// positionFields returns the indexes of the exported fields of struct type t
+// that can contain a Point.
+func positionFields(t reflect.Type) []int {
+	if fields, ok := structPositions.Load(t); ok {
+		return fields.([]int)
+	}
+	fields := []int{}
+	for i := range t.NumField() {
+		if field := t.Field(i); field.IsExported() && holdsPositions(field.Type) {
+			fields = append(fields, i)
+		}
+	}
+	structPositions.Store(t, fields)
+	return fields
+}
+
+var positionTypes sync.Map // reflect.Type -> bool
+
+// holdsPositions reports whether a value of type t can contain a Point,
+// which is what relocateValue has to copy.
+func holdsPositions(t reflect.Type) bool {
+	if holds, ok := positionTypes.Load(t); ok {
+		return holds.(bool)
+	}
+	holds := findPositions(t, map[reflect.Type]bool{})
+	positionTypes.Store(t, holds)
+	return holds
+}
+
+// findPositions searches the types reachable from t for a Point, skipping the
+// types in seen. Only its answer for the type a search starts from is
+// complete, so only that one is kept.
+func findPositions(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if holds, ok := positionTypes.Load(t); ok {
+		return holds.(bool)
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	holds := false
+	switch t.Kind() {
+	case reflect.Interface:
+		holds = true
+	case reflect.Pointer, reflect.Slice, reflect.Array:
+		holds = findPositions(t.Elem(), seen)
+	case reflect.Map:
+		holds = findPositions(t.Key(), seen) || findPositions(t.Elem(), seen)
+	case reflect.Struct:
+		holds = t == pointType
+		for i := 0; i < t.NumField() && !holds; i++ {
+			field := t.Field(i)
+			holds = field.IsExported() && findPositions(field.Type, seen)
+		}
+	}
+	return holds
+}
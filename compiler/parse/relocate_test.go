@@ -0,0 +1,87 @@
+package parse
+
+import (
+	goast "go/ast"
+	goparser "go/parser"
+	gotoken "go/token"
+	"testing"
+)
+
+// relocated only rewrites the positions it can reach through exported
+// fields, so a node that kept a position in an unexported field would keep
+// its old line after an edit above it. This checks every type in ast.go.
+func TestNodePositionsAreInExportedFields(t *testing.T) {
+	file, err := goparser.ParseFile(gotoken.NewFileSet(), "ast.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	structs := map[string]*goast.StructType{}
+	holds := map[string]bool{"Point": true}
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*goast.GenDecl)
+		if !ok || gen.Tok != gotoken.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			spec := spec.(*goast.TypeSpec)
+			switch typ := spec.Type.(type) {
+			case *goast.StructType:
+				structs[spec.Name.Name] = typ
+			case *goast.InterfaceType:
+				// any node can sit behind an interface
+				holds[spec.Name.Name] = true
+			}
+		}
+	}
+	if len(structs) < 50 {
+		t.Fatalf("found only %d node types in ast.go", len(structs))
+	}
+
+	// a type holds positions if any of its fields can, found by repeating
+	// until nothing changes
+	for changed := true; changed; {
+		changed = false
+		for name, typ := range structs {
+			if holds[name] {
+				continue
+			}
+			for _, field := range typ.Fields.List {
+				if referencesAny(field.Type, holds) {
+					holds[name] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+
+	for name, typ := range structs {
+		for _, field := range typ.Fields.List {
+			if !referencesAny(field.Type, holds) {
+				continue
+			}
+			for _, fieldName := range field.Names {
+				if !fieldName.IsExported() {
+					t.Errorf("%s.%s holds a position in an unexported field", name, fieldName.Name)
+				}
+			}
+		}
+	}
+}
+
+// referencesAny reports whether the type expression expr names one of the
+// types in names, directly or as the element of a pointer, slice, or map.
+func referencesAny(expr goast.Expr, names map[string]bool) bool {
+	found := false
+	goast.Inspect(expr, func(node goast.Node) bool {
+		switch node := node.(type) {
+		case *goast.Ident:
+			found = found || names[node.Name]
+		case *goast.InterfaceType:
+			found = true
+		}
+		return !found
+	})
+	return found
+}
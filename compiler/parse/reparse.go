@@ -0,0 +1,228 @@
+package parse
+
+import (
+	"bytes"
+	"math"
+	"strings"
+)
+
+// Edit describes one change to a source file: the text from Start up to
+// OldEnd was replaced with text that now ends at NewEnd. Each edit is given
+// against the source as the edits before it left it, the way editors report
+// them.
+type Edit struct {
+	Start  Point
+	OldEnd Point
+	NewEnd Point
+}
+
+// EditBetween returns the single edit that turns before into after, spanning
+// from their first difference to their last.
+func EditBetween(before []byte, after []byte) Edit {
+	prefix := 0
+	for prefix < len(before) && prefix < len(after) && before[prefix] == after[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(before)-prefix && suffix < len(after)-prefix &&
+		before[len(before)-1-suffix] == after[len(after)-1-suffix] {
+		suffix++
+	}
+	return Edit{
+		Start:  pointAt(before, prefix),
+		OldEnd: pointAt(before, len(before)-suffix),
+		NewEnd: pointAt(after, len(after)-suffix),
+	}
+}
+
+func pointAt(source []byte, offset int) Point {
+	line := bytes.Count(source[:offset], []byte{'\n'})
+	lineStart := bytes.LastIndexByte(source[:offset], '\n') + 1
+	return Point{Row: line + 1, Col: offset - lineStart + 1}
+}
+
+// Reparse parses source, the text of old's file after edits, reusing the
+// top-level declarations of old that the edits did not touch. Only the lines
+// between the closest untouched declarations on either side of the edits are
+// parsed again; the declarations after them are moved to their new lines.
+// The result is the tree Parse would build for source, and it shares nodes
+// with old, so neither may be changed. old should come straight from Parse
+// or Reparse, before AllowLegacy.
+func Reparse(old ParseResult, edits []Edit, source []byte) ParseResult {
+	lines := strings.Split(string(source), "\n")
+	full := func() ParseResult {
+		return parseTokens(NewLexer(source).Scan(), lines, old.fileName)
+	}
+	if old.Program == nil || len(edits) == 0 {
+		return full()
+	}
+	changed, ok := combineEdits(edits)
+	if !ok {
+		return full()
+	}
+	for _, err := range old.Errors {
+		// an error without a line can't be placed before or after the edit
+		if err.Location.Start.Row <= 0 {
+			return full()
+		}
+	}
+
+	shift := changed.newEnd - changed.oldEnd
+	statements := old.Program.Statements
+	// keep[:before] stay where they are and statements[after:] move by shift
+	before, after := 0, len(statements)
+	for i, stmt := range statements {
+		if stmt.GetLocation().End.Row >= changed.start {
+			break
+		}
+		if isAnchor(statements, i, lines, 0) {
+			before = i + 1
+		}
+	}
+	for i := len(statements) - 1; i >= before; i-- {
+		if statements[i].GetLocation().Start.Row <= changed.oldEnd {
+			break
+		}
+		if isAnchor(statements, i, lines, shift) {
+			after = i
+		}
+	}
+
+	// the lines left between the kept declarations, in the new source
+	firstRow, lastRow := 1, len(lines)
+	if before > 0 {
+		firstRow = statements[before-1].GetLocation().End.Row + 1
+	}
+	if after < len(statements) {
+		// the first line of the next kept declaration is parsed as well, to
+		// see that the region ends right before it rather than running on
+		// into it, like an unclosed string or bracket does
+		lastRow = statements[after].GetLocation().Start.Row + shift
+	}
+	if firstRow > lastRow || lastRow > len(lines) {
+		return full()
+	}
+	region := []byte(strings.Join(lines[firstRow-1:lastRow], "\n"))
+	p := new(newLexerAt(region, firstRow).Scan(), old.fileName)
+	p.lines = lines
+	if after < len(statements) {
+		p.stopRow = lastRow
+	}
+	reparsed, _ := p.parse()
+	if p.stopRow > 0 && !p.stopped {
+		return full()
+	}
+	if before > 0 && (len(reparsed.Imports) > 0 || len(reparsed.Pragmas) > 0) {
+		return full()
+	}
+
+	program := &Program{
+		Imports:    old.Program.Imports,
+		Pragmas:    old.Program.Pragmas,
+		Statements: make([]Statement, 0, before+len(reparsed.Statements)+len(statements)-after),
+	}
+	if before == 0 {
+		program.Imports = reparsed.Imports
+		program.Pragmas = reparsed.Pragmas
+	}
+	program.Statements = append(program.Statements, statements[:before]...)
+	program.Statements = append(program.Statements, reparsed.Statements...)
+	for _, stmt := range statements[after:] {
+		program.Statements = append(program.Statements, relocated(stmt, shift))
+	}
+
+	result := ParseResult{fileName: old.fileName, Program: program}
+	// old errors and legacy spellings in the kept declarations come along
+	keptBefore, keptAfter := firstRow-1, math.MaxInt
+	if after < len(statements) {
+		keptAfter = statements[after].GetLocation().Start.Row
+	}
+	for _, err := range old.Errors {
+		if row := err.Location.Start.Row; row <= keptBefore {
+			result.Errors = append(result.Errors, err)
+		} else if row >= keptAfter {
+			result.Errors = append(result.Errors, relocatedError(err, shift))
+		}
+	}
+	result.Errors = append(result.Errors, p.errors...)
+	for _, legacy := range old.Legacy {
+		if row := legacy.Error.Location.Start.Row; row <= keptBefore {
+			result.Legacy = append(result.Legacy, legacy)
+		} else if row >= keptAfter {
+			legacy.Error = relocatedError(legacy.Error, shift)
+			result.Legacy = append(result.Legacy, legacy)
+		}
+	}
+	result.Legacy = append(result.Legacy, p.legacy...)
+	if result.Errors == nil {
+		result.Errors = []ParseError{}
+	}
+	return result
+}
+
+// changedRows is the span of lines a set of edits touched: from start to
+// oldEnd in the old source, and from start to newEnd in the new one.
+type changedRows struct {
+	start, oldEnd, newEnd int
+}
+
+// combineEdits folds edits, each given against the source the ones before it
+// left, into the one span of lines they touched together.
+func combineEdits(edits []Edit) (changedRows, bool) {
+	var changed changedRows
+	for i, edit := range edits {
+		next := changedRows{start: edit.Start.Row, oldEnd: edit.OldEnd.Row, newEnd: edit.NewEnd.Row}
+		if next.start <= 0 || next.oldEnd < next.start || next.newEnd < next.start {
+			return changedRows{}, false
+		}
+		if i == 0 {
+			changed = next
+			continue
+		}
+		// the lines touched so far, as they sit before next is applied
+		end := max(changed.newEnd, next.oldEnd)
+		oldEnd := changed.oldEnd
+		if next.oldEnd > changed.newEnd {
+			oldEnd = next.oldEnd - (changed.newEnd - changed.oldEnd)
+		}
+		changed = changedRows{
+			start:  min(changed.start, next.start),
+			oldEnd: oldEnd,
+			newEnd: end + next.newEnd - next.oldEnd,
+		}
+	}
+	return changed, true
+}
+
+// isAnchor reports whether statements[i] can be reused in place: a braced
+// declaration that starts a line at the first column and shares its lines
+// with no other statement. The parser never looks past such a declaration's
+// closing brace, so what comes after it can't change how it parses. lines is
+// the new source, where the declaration sits shift lines further down.
+func isAnchor(statements []Statement, i int, lines []string, shift int) bool {
+	switch statements[i].(type) {
+	case *FunctionDeclaration, *StaticFunctionDeclaration, *StructDefinition,
+		*EnumDefinition, *ImplBlock, *TraitDefinition, *TraitImplementation:
+	default:
+		return false
+	}
+	location := statements[i].GetLocation()
+	if i > 0 && statements[i-1].GetLocation().End.Row >= location.Start.Row {
+		return false
+	}
+	if i+1 < len(statements) && statements[i+1].GetLocation().Start.Row <= location.End.Row {
+		return false
+	}
+	row := location.Start.Row + shift
+	if row < 1 || row > len(lines) {
+		return false
+	}
+	line := lines[row-1]
+	return line != "" && line[0] != ' ' && line[0] != '\t'
+}
+
+func relocatedError(err ParseError, rows int) ParseError {
+	err.Location.Start = relocatePoint(err.Location.Start, rows)
+	err.Location.End = relocatePoint(err.Location.End, rows)
+	return err
+}
@@ -0,0 +1,156 @@
+package parse
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const reparseSource = `use ard/io
+
+// adds one
+fn one(a: Int) Int {
+  a + 1
+}
+
+struct Point {
+  x: Int,
+  y: Int,
+}
+
+impl Point {
+  fn sum() Int {
+    self.x + self.y
+  }
+}
+
+let origin = Point{x: 0, y: 0}
+
+fn main() {
+  io::print(one(origin.sum()))
+}
+`
+
+// assertReparseMatchesParse reparses old, the tree for before, as edited
+// into after and checks the result is the tree a fresh parse of after builds.
+func assertReparseMatchesParse(t *testing.T, old ParseResult, before string, after string) ParseResult {
+	t.Helper()
+	got := Reparse(old, []Edit{EditBetween([]byte(before), []byte(after))}, []byte(after))
+	want := Parse([]byte(after), "test.ard")
+	if !reflect.DeepEqual(got.Program, want.Program) {
+		t.Fatalf("reparsed program differs from a fresh parse\n got: %v\nwant: %v", got.Program.Statements, want.Program.Statements)
+	}
+	if !reflect.DeepEqual(got.Errors, want.Errors) {
+		t.Fatalf("reparsed errors = %v, want %v", got.Errors, want.Errors)
+	}
+	return got
+}
+
+func TestReparseMatchesParse(t *testing.T) {
+	edits := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{"change a line", "a + 1", "a + 2"},
+		{"add a line", "a + 1", "let b = a\n  b + 1"},
+		{"remove a declaration", "let origin = Point{x: 0, y: 0}\n", ""},
+		{"add a declaration", "\nfn main", "\nfn two() Int {\n  2\n}\n\nfn main"},
+		{"edit an import", "use ard/io", "use ard/io\nuse ard/list"},
+		{"edit a comment", "// adds one", "// adds one\n// to a"},
+		{"edit the last line", "io::print(one(origin.sum()))\n}\n", "io::print(one(origin.sum()))\n}\n\nfn extra() {}\n"},
+		{"break a declaration", "a + 1", "a + * 1"},
+		{"remove a closing brace", "  a + 1\n}", "  a + 1"},
+		{"open a string", "a + 1", "\"a + 1"},
+		{"open a list", "a + 1", "[a + 1"},
+		{"join two declarations", "}\n\nstruct Point", "} struct Point"},
+		{"add a pragma", "// adds one", "#target(js)\n// adds one"},
+	}
+	for _, edit := range edits {
+		t.Run(edit.name, func(t *testing.T) {
+			after := strings.Replace(reparseSource, edit.from, edit.to, 1)
+			if after == reparseSource {
+				t.Fatalf("%q is not in the source", edit.from)
+			}
+			assertReparseMatchesParse(t, Parse([]byte(reparseSource), "test.ard"), reparseSource, after)
+		})
+	}
+}
+
+func TestReparseReusesUntouchedDeclarations(t *testing.T) {
+	old := Parse([]byte(reparseSource), "test.ard")
+
+	inPlace := strings.Replace(reparseSource, "a + 1", "a + 2", 1)
+	result := assertReparseMatchesParse(t, old, reparseSource, inPlace)
+	// the lines up to the first untouched declaration, the comment and one(),
+	// are parsed again
+	for i, stmt := range result.Program.Statements {
+		if reused := stmt == old.Program.Statements[i]; reused != (i >= 2) {
+			t.Errorf("statement %d (%T): reused = %v", i, stmt, reused)
+		}
+	}
+
+	moved := strings.Replace(reparseSource, "a + 1", "let b = a\n  b + 1", 1)
+	result = assertReparseMatchesParse(t, old, reparseSource, moved)
+	main := result.Program.Statements[len(result.Program.Statements)-1]
+	oldMain := old.Program.Statements[len(old.Program.Statements)-1]
+	if main == oldMain {
+		t.Fatalf("a declaration after an added line was not moved")
+	}
+	if got, want := main.GetLocation().Start.Row, oldMain.GetLocation().Start.Row+1; got != want {
+		t.Errorf("main starts on line %d, want %d", got, want)
+	}
+	if oldMain.GetLocation().Start.Row != 21 {
+		t.Errorf("moving main changed the old tree: it starts on line %d", oldMain.GetLocation().Start.Row)
+	}
+}
+
+func TestReparseCombinesEdits(t *testing.T) {
+	before := reparseSource
+	// add a line to one(), then change main() as the source stands after that
+	middle := strings.Replace(before, "a + 1", "let b = a\n  b + 1", 1)
+	after := strings.Replace(middle, "io::print", "io::print_line", 1)
+	edits := []Edit{
+		EditBetween([]byte(before), []byte(middle)),
+		EditBetween([]byte(middle), []byte(after)),
+	}
+
+	got := Reparse(Parse([]byte(before), "test.ard"), edits, []byte(after))
+	want := Parse([]byte(after), "test.ard")
+	if !reflect.DeepEqual(got.Program, want.Program) {
+		t.Fatalf("reparsed program differs from a fresh parse\n got: %v\nwant: %v", got.Program.Statements, want.Program.Statements)
+	}
+}
+
+func TestReparseMatchesParseOnStandardLibrary(t *testing.T) {
+	paths, err := filepath.Glob("../std_lib/*.ard")
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("no standard library sources: %v", err)
+	}
+	for _, path := range paths {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		old := Parse(source, path)
+		lines := strings.SplitAfter(string(source), "\n")
+		for i := 0; i < len(lines); i += 5 {
+			edited := func(line string) string {
+				return strings.Join(lines[:i], "") + line + strings.Join(lines[i+1:], "")
+			}
+			for _, after := range []string{
+				edited(""),
+				edited(lines[i] + "\n"),
+				edited(strings.Replace(lines[i], "(", "", 1)),
+			} {
+				got := Reparse(old, []Edit{EditBetween(source, []byte(after))}, []byte(after))
+				want := Parse([]byte(after), path)
+				if !reflect.DeepEqual(got.Program, want.Program) || !reflect.DeepEqual(got.Errors, want.Errors) {
+					t.Fatalf("%s: reparsing an edit to line %d differs from a fresh parse", path, i+1)
+				}
+			}
+		}
+	}
+}
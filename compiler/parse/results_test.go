@@ -115,6 +115,35 @@ func TestResultTypeInSignature(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Result sugar with nullable collection values",
+			input: `
+			fn next() [Str]?!Str {}
+			fn record() [Str: Int]?!Str {}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&FunctionDeclaration{
+						Name:       "next",
+						Parameters: []Parameter{},
+						ReturnType: &ResultType{
+							Val: &List{Element: &StringType{}, nullable: true},
+							Err: &StringType{},
+						},
+						Body: []Statement{},
+					},
+					&FunctionDeclaration{
+						Name:       "record",
+						Parameters: []Parameter{},
+						ReturnType: &ResultType{
+							Val: &Map{Key: &StringType{}, Value: &IntType{}, nullable: true},
+							Err: &StringType{},
+						},
+						Body: []Statement{},
+					},
+				},
+			},
+		},
 		{
 			name: "Result sugar with qualified types",
 			input: `
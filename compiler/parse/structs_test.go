@@ -14,9 +14,9 @@ struct Person {
 var personStruct = &StructDefinition{
 	Name: Identifier{Name: "Person"},
 	Fields: []StructField{
-		{Identifier{Name: "name"}, &StringType{}},
-		{Identifier{Name: "age"}, &IntType{}},
-		{Identifier{Name: "employed"}, &BooleanType{}},
+		{Identifier{Name: "name"}, &StringType{}, false, nil},
+		{Identifier{Name: "age"}, &IntType{}, false, nil},
+		{Identifier{Name: "employed"}, &BooleanType{}, false, nil},
 	},
 }
 
@@ -90,7 +90,7 @@ func TestStructDefinitions(t *testing.T) {
 						Name:       Identifier{Name: "State"},
 						TypeParams: []string{"T"},
 						Fields: []StructField{
-							{Identifier{Name: "handle"}, &CustomType{Name: "StateHandle"}},
+							{Identifier{Name: "handle"}, &CustomType{Name: "StateHandle"}, false, nil},
 						},
 					},
 				},
@@ -107,7 +107,47 @@ func TestStructDefinitions(t *testing.T) {
 					&StructDefinition{
 						Name: Identifier{Name: "Context"},
 						Fields: []StructField{
-							{Identifier{Name: "tree"}, &MutableType{Inner: &CustomType{Name: "ViewTree"}}},
+							{Identifier{Name: "tree"}, &MutableType{Inner: &CustomType{Name: "ViewTree"}}, false, nil},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "A struct with private fields",
+			input: `struct Account {
+					private balance: Int,
+					owner: Str,
+					private: Bool,
+				}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&StructDefinition{
+						Name: Identifier{Name: "Account"},
+						Fields: []StructField{
+							{Identifier{Name: "balance"}, &IntType{}, true, nil},
+							{Identifier{Name: "owner"}, &StringType{}, false, nil},
+							{Identifier{Name: "private"}, &BooleanType{}, false, nil},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "A struct with default field values",
+			input: `struct Config {
+					host: Str,
+					retries: Int = 3,
+				}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&StructDefinition{
+						Name: Identifier{Name: "Config"},
+						Fields: []StructField{
+							{Identifier{Name: "host"}, &StringType{}, false, nil},
+							{Identifier{Name: "retries"}, &IntType{}, false, &NumLiteral{Value: "3"}},
 						},
 					},
 				},
@@ -185,6 +225,11 @@ func TestStructDefinitions(t *testing.T) {
 			input:    "struct Person { name: string age: int }",
 			wantErrs: []string{"Expected ',' or '}' after field type", "Expected '}'"},
 		},
+		{
+			name:     "Missing default value",
+			input:    "struct Config { retries: Int = }",
+			wantErrs: []string{"Expected expression after '='"},
+		},
 		{
 			name:     "Empty struct works",
 			input:    "struct Person { }",
@@ -230,6 +275,27 @@ func TestUsingStructs(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:  "Instantiating from a base",
+			input: `Config{ ..base, retries: 5 }`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&StructInstance{
+						Name: Identifier{Name: "Config"},
+						Base: &Identifier{Name: "base"},
+						Properties: []StructValue{
+							{Name: Identifier{Name: "retries"}, Value: &NumLiteral{Value: "5"}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "Only one base is allowed",
+			input:    `Config{ ..base, ..other }`,
+			wantErrs: []string{"A struct literal can only have one '..' base"},
+		},
 		{
 			name: "Referencing fields",
 			input: `
@@ -561,3 +627,75 @@ func TestMutOperandInSubjectPositions(t *testing.T) {
 		},
 	})
 }
+
+func TestStructPatterns(t *testing.T) {
+	runTests(t, []test{
+		{
+			name: "struct patterns in match arms",
+			input: `
+					match point {
+						Point{x: 0, y} => y,
+						Shape{origin: Point{x: 0, y: _}, kind: Kind::circle} => 1,
+						_ => 0,
+					}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&MatchExpression{
+						Subject: &Identifier{Name: "point"},
+						Cases: []MatchCase{
+							{
+								Pattern: &StructPattern{
+									Name: Identifier{Name: "Point"},
+									Fields: []StructPatternField{
+										{Name: Identifier{Name: "x"}, Pattern: &NumLiteral{Value: "0"}},
+										{Name: Identifier{Name: "y"}},
+									},
+								},
+								Body: []Statement{&Identifier{Name: "y"}},
+							},
+							{
+								Pattern: &StructPattern{
+									Name: Identifier{Name: "Shape"},
+									Fields: []StructPatternField{
+										{
+											Name: Identifier{Name: "origin"},
+											Pattern: &StructPattern{
+												Name: Identifier{Name: "Point"},
+												Fields: []StructPatternField{
+													{Name: Identifier{Name: "x"}, Pattern: &NumLiteral{Value: "0"}},
+													{Name: Identifier{Name: "y"}, Pattern: &Identifier{Name: "_"}},
+												},
+											},
+										},
+										{
+											Name: Identifier{Name: "kind"},
+											Pattern: &StaticProperty{
+												Target:   &Identifier{Name: "Kind"},
+												Property: &Identifier{Name: "circle"},
+											},
+										},
+									},
+								},
+								Body: []Statement{&NumLiteral{Value: "1"}},
+							},
+							{
+								Pattern: &Identifier{Name: "_"},
+								Body:    []Statement{&NumLiteral{Value: "0"}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "unclosed struct pattern",
+			input: `
+					match point {
+						Point{x: 0 => 1,
+						_ => 0,
+					}`,
+			wantErrs: []string{"Expected '}' after struct pattern fields"},
+		},
+	})
+}
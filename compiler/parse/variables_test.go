@@ -16,6 +16,37 @@ func TestVariableDeclarationNameLocation(t *testing.T) {
 	}
 }
 
+func TestConstantDeclaration(t *testing.T) {
+	result := Parse([]byte("const MAX: Int = 100\nlet const = 1"), "test.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	declaration := result.Program.Statements[0].(*VariableDeclaration)
+	if !declaration.Const || declaration.Mutable || declaration.Name != "MAX" {
+		t.Fatalf("declaration = %+v, want immutable const MAX", declaration)
+	}
+	if binding := result.Program.Statements[1].(*VariableDeclaration); binding.Const || binding.Name != "const" {
+		t.Fatalf("`const` should remain usable as a name, got %+v", binding)
+	}
+}
+
+func TestPrivateVariableDeclaration(t *testing.T) {
+	result := Parse([]byte("private let cache = 1\nprivate const LIMIT = 2\nlet open = 3"), "test.ard")
+	if len(result.Errors) > 0 {
+		t.Fatalf("parse errors: %v", result.Errors)
+	}
+	cache := result.Program.Statements[0].(*VariableDeclaration)
+	if !cache.Private || cache.Const || cache.Name != "cache" || cache.Location.Start.Col != 1 {
+		t.Fatalf("cache = %+v, want a private let starting at the modifier", cache)
+	}
+	if limit := result.Program.Statements[1].(*VariableDeclaration); !limit.Private || !limit.Const || limit.Name != "LIMIT" {
+		t.Fatalf("limit = %+v, want a private const", limit)
+	}
+	if open := result.Program.Statements[2].(*VariableDeclaration); open.Private {
+		t.Fatalf("open = %+v, want a public let", open)
+	}
+}
+
 func TestVariables(t *testing.T) {
 	tests := []test{
 		{
@@ -115,6 +146,40 @@ func TestVariables(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Scaling variables",
+			input: `
+				total =* 3
+				total =/ 2`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&VariableAssignment{
+						Target:   &Identifier{Name: "total"},
+						Operator: Assign,
+						Value: &BinaryExpression{
+							Operator: Multiply,
+							Left:     &Identifier{Name: "total"},
+							Right:    &NumLiteral{Value: "3"},
+						},
+					},
+					&VariableAssignment{
+						Target:   &Identifier{Name: "total"},
+						Operator: Assign,
+						Value: &BinaryExpression{
+							Operator: Divide,
+							Left:     &Identifier{Name: "total"},
+							Right:    &NumLiteral{Value: "2"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "C-style compound assignment points to Ard's spelling",
+			input:    `total *= 3`,
+			wantErrs: []string{"Compound assignment is written `=*`"},
+		},
 	}
 
 	runTests(t, tests)
@@ -356,3 +421,41 @@ func TestGenericTypeParameters(t *testing.T) {
 
 // TestStaticPropertyAccess - basic error recovery implemented
 // Comprehensive testing skipped due to complex interaction with assignment parsing
+
+func TestLetElse(t *testing.T) {
+	runTests(t, []test{
+		{
+			name:  "let with an else block",
+			input: `let user = find(id) else { fallback }`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&VariableDeclaration{
+						Name: "user",
+						Value: &FunctionCall{
+							Name:     "find",
+							Args:     []Argument{{Value: &Identifier{Name: "id"}}},
+							Comments: []Comment{},
+						},
+						Else: []Statement{&Identifier{Name: "fallback"}},
+					},
+				},
+			},
+		},
+		{
+			name:  "mut with an empty else block",
+			input: `mut count = lookup() else {}`,
+			output: Program{
+				Imports: []Import{},
+				Statements: []Statement{
+					&VariableDeclaration{
+						Name:    "count",
+						Mutable: true,
+						Value:   &FunctionCall{Name: "lookup", Args: []Argument{}, Comments: []Comment{}},
+						Else:    []Statement{},
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,6 @@
+package runtime
+
+// BuildMetadata is set when `ard build` links a program: base64-encoded JSON
+// describing the compiler and modules that built it, which `ard inspect`
+// reads back from the binary.
+var BuildMetadata string
@@ -0,0 +1,115 @@
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CapabilitiesEnv names the environment variable a host uses to sandbox a
+// program. Each line grants one capability, either outright (`net`) or under a
+// directory (`read=/srv/data`). When the variable is unset the program is not
+// sandboxed and every capability is granted.
+const CapabilitiesEnv = "ARD_CAPABILITIES"
+
+type capabilityGrants struct {
+	sandboxed bool
+	all       map[string]bool
+	roots     map[string][]string
+}
+
+var grants = sync.OnceValue(func() capabilityGrants {
+	raw, ok := os.LookupEnv(CapabilitiesEnv)
+	if !ok {
+		return capabilityGrants{}
+	}
+	return parseCapabilityGrants(raw)
+})
+
+func parseCapabilityGrants(raw string) capabilityGrants {
+	parsed := capabilityGrants{sandboxed: true, all: map[string]bool{}, roots: map[string][]string{}}
+	for _, line := range strings.Split(raw, "\n") {
+		name, root, scoped := strings.Cut(line, "=")
+		switch {
+		case name == "":
+		case scoped:
+			parsed.roots[name] = append(parsed.roots[name], resolvePath(root))
+		default:
+			parsed.all[name] = true
+		}
+	}
+	return parsed
+}
+
+// Require stops the program unless the host granted capability. A path is
+// checked against the directories the capability was granted under; pass ""
+// for capabilities that are not scoped to files.
+func Require(capability string, path string) {
+	if err := grants().check(capability, path); err != nil {
+		deny(err)
+	}
+}
+
+// Forbid stops a sandboxed program that uses a Go symbol the sandbox does not
+// know to be safe. Outside a sandbox it does nothing.
+func Forbid(symbol string) {
+	if grants().sandboxed {
+		deny(fmt.Errorf("%s is not allowed in a sandbox", symbol))
+	}
+}
+
+// deny reports a sandbox violation and exits. It does not panic, so the
+// report has no goroutine trace and `unsafe` blocks cannot catch it.
+func deny(err error) {
+	fmt.Fprintln(os.Stderr, "runtime error: "+err.Error())
+	os.Exit(2)
+}
+
+func (g capabilityGrants) check(capability string, path string) error {
+	if !g.sandboxed || g.all[capability] {
+		return nil
+	}
+	if path != "" && len(g.roots[capability]) > 0 {
+		resolved := resolvePath(path)
+		for _, root := range g.roots[capability] {
+			if rel, err := filepath.Rel(root, resolved); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return nil
+			}
+		}
+		return fmt.Errorf("capability not granted: %s access to %s (run with --allow-%s=%s)", capability, path, capability, path)
+	}
+	return fmt.Errorf("capability not granted: %s (run with --allow-%s)", capability, capability)
+}
+
+// resolvePath makes path absolute and follows its symlinks one element at a
+// time, the way opening it would, so that neither a link inside a granted
+// directory nor a `..` after one can reach outside it. Elements that do not
+// exist yet, such as a file about to be created, are kept as written.
+func resolvePath(path string) string {
+	if !filepath.IsAbs(path) {
+		wd, err := os.Getwd()
+		if err != nil {
+			return filepath.Clean(path)
+		}
+		path = wd + string(filepath.Separator) + path
+	}
+	volume := filepath.VolumeName(path)
+	resolved := volume + string(filepath.Separator)
+	for _, element := range strings.Split(path[len(volume):], string(filepath.Separator)) {
+		switch element {
+		case "", ".":
+			continue
+		case "..":
+			resolved = filepath.Dir(resolved)
+			continue
+		}
+		next := filepath.Join(resolved, element)
+		if real, err := filepath.EvalSymlinks(next); err == nil {
+			next = real
+		}
+		resolved = next
+	}
+	return resolved
+}
@@ -0,0 +1,79 @@
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCapabilityGrantsScopeFilesToDirectories(t *testing.T) {
+	root, err := filepath.Abs("data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	grants := parseCapabilityGrants("net\nread=" + root)
+	allowed := []struct{ capability, path string }{
+		{"net", ""},
+		{"read", "data/users.json"},
+		{"read", "data"},
+		{"read", "./data/../data/nested/file"},
+	}
+	for _, tc := range allowed {
+		if err := grants.check(tc.capability, tc.path); err != nil {
+			t.Errorf("check(%q, %q) = %v, want granted", tc.capability, tc.path, err)
+		}
+	}
+	denied := map[[2]string]string{
+		{"read", "secrets.txt"}:      "capability not granted: read access to secrets.txt (run with --allow-read=secrets.txt)",
+		{"read", "data-backup/a"}:    "capability not granted: read access to data-backup/a (run with --allow-read=data-backup/a)",
+		{"read", "data/../../etc"}:   "capability not granted: read access to data/../../etc (run with --allow-read=data/../../etc)",
+		{"write", "data/users.json"}: "capability not granted: write (run with --allow-write)",
+	}
+	for input, want := range denied {
+		err := grants.check(input[0], input[1])
+		if err == nil || err.Error() != want {
+			t.Errorf("check(%q, %q) = %v, want %q", input[0], input[1], err, want)
+		}
+	}
+}
+
+func TestCapabilityGrantsAllowEverythingOutsideASandbox(t *testing.T) {
+	if err := (capabilityGrants{}).check("run", ""); err != nil {
+		t.Fatalf("check = %v, want granted", err)
+	}
+	if err := parseCapabilityGrants("").check("run", ""); err == nil {
+		t.Fatal("check = nil, want an empty sandbox to deny")
+	}
+}
+
+func TestCapabilityGrantsFollowSymlinksOutOfGrantedDirectories(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "granted")
+	outside := filepath.Join(dir, "outside", "nested")
+	for _, path := range []string{root, outside} {
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	grants := parseCapabilityGrants("read=" + root)
+	for _, path := range []string{
+		filepath.Join(root, "data.txt"),
+		filepath.Join(root, "missing", "file.txt"),
+	} {
+		if err := grants.check("read", path); err != nil {
+			t.Errorf("check(%q) = %v, want granted", path, err)
+		}
+	}
+	for _, path := range []string{
+		filepath.Join(root, "link", "secret.txt"),
+		filepath.Join(root, "link") + "/../secret.txt",
+	} {
+		if err := grants.check("read", path); err == nil {
+			t.Errorf("check(%q) = nil, want the symlink to leave the granted directory", path)
+		}
+	}
+}
@@ -5,11 +5,20 @@ import "embed"
 // SourceFiles embeds the runtime support files copied into generated programs.
 // Keep SourceFileNames in sync with this directive.
 //
-//go:embed maybe.go result.go unsafe.go
+//go:embed build.go capabilities.go errors.go format.go graphemes.go inspect.go maps.go math.go maybe.go result.go strings.go unsafe.go
 var SourceFiles embed.FS
 
 var SourceFileNames = []string{
+	"build.go",
+	"capabilities.go",
+	"errors.go",
+	"format.go",
+	"graphemes.go",
+	"inspect.go",
+	"maps.go",
+	"math.go",
 	"maybe.go",
 	"result.go",
+	"strings.go",
 	"unsafe.go",
 }
@@ -0,0 +1,21 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WrapError implements Error::wrap. The result's message is the context
+// followed by the wrapped error's message, and errors.Unwrap, errors.Is, and
+// errors.As see through it to err.
+func WrapError(err error, context string) error {
+	return fmt.Errorf("%s: %w", context, err)
+}
+
+// ErrorCause implements Error::cause: the error err wraps, if any.
+func ErrorCause(err error) Maybe[error] {
+	if cause := errors.Unwrap(err); cause != nil {
+		return Some(cause)
+	}
+	return None[error]()
+}
@@ -0,0 +1,24 @@
+package runtime
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestWrapErrorKeepsCause(t *testing.T) {
+	base := errors.New("connection refused")
+	wrapped := WrapError(WrapError(base, "dial"), "load config")
+	if got := wrapped.Error(); got != "load config: dial: connection refused" {
+		t.Fatalf("message = %q", got)
+	}
+	if !errors.Is(wrapped, base) {
+		t.Fatal("wrapped error does not match its root cause")
+	}
+	cause := ErrorCause(wrapped)
+	if cause.IsNone() || cause.Value().Error() != "dial: connection refused" {
+		t.Fatalf("cause = %v, want the dial error", cause)
+	}
+	if ErrorCause(base).IsSome() {
+		t.Fatal("unwrapped error has a cause")
+	}
+}
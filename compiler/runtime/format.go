@@ -0,0 +1,264 @@
+package runtime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FormatPart is one piece of a Str::format template: literal text, or a
+// placeholder selecting an argument by position or by name.
+type FormatPart struct {
+	Text        string
+	Placeholder bool
+	// Index is the positional argument, or -1 for a named placeholder.
+	Index int
+	Name  string
+	Fill  rune
+	// Align is '<', '>', or '^', or 0 for the value's default: numbers
+	// align right and everything else aligns left.
+	Align byte
+	// Zero pads numbers with zeros after their sign, from a width written
+	// with a leading 0 and no alignment.
+	Zero      bool
+	Width     int
+	Precision int // -1 when unset
+}
+
+// ParseFormat splits a template into literal text and placeholders. A
+// placeholder is `{key}` or `{key:spec}` where key is a position, a name, or
+// empty for the next position, and spec is `[[fill]align][0][width][.precision]`.
+// `{{` and `}}` stand for literal braces.
+func ParseFormat(template string) ([]FormatPart, error) {
+	var parts []FormatPart
+	var text strings.Builder
+	next := 0
+	for i := 0; i < len(template); i++ {
+		switch template[i] {
+		case '{':
+			if i+1 < len(template) && template[i+1] == '{' {
+				text.WriteByte('{')
+				i++
+				continue
+			}
+			end := strings.IndexByte(template[i+1:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed placeholder at offset %d", i)
+			}
+			part, err := parsePlaceholder(template[i+1:i+1+end], &next)
+			if err != nil {
+				return nil, err
+			}
+			if text.Len() > 0 {
+				parts = append(parts, FormatPart{Text: text.String()})
+				text.Reset()
+			}
+			parts = append(parts, part)
+			i += end + 1
+		case '}':
+			if i+1 < len(template) && template[i+1] == '}' {
+				text.WriteByte('}')
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("unmatched '}' at offset %d, write '}}' for a literal brace", i)
+		default:
+			text.WriteByte(template[i])
+		}
+	}
+	if text.Len() > 0 {
+		parts = append(parts, FormatPart{Text: text.String()})
+	}
+	return parts, nil
+}
+
+func parsePlaceholder(body string, next *int) (FormatPart, error) {
+	part := FormatPart{Placeholder: true, Index: -1, Fill: ' ', Precision: -1}
+	key, spec, hasSpec := strings.Cut(body, ":")
+	switch {
+	case key == "":
+		part.Index = *next
+		*next++
+	case isDigits(key):
+		index, err := strconv.Atoi(key)
+		if err != nil {
+			return part, fmt.Errorf("invalid placeholder {%s}", body)
+		}
+		part.Index = index
+	case isName(key):
+		part.Name = key
+	default:
+		return part, fmt.Errorf("invalid placeholder {%s}", body)
+	}
+	if !hasSpec {
+		return part, nil
+	}
+
+	if fill, size := utf8.DecodeRuneInString(spec); size > 0 && len(spec) > size && isAlign(spec[size]) {
+		part.Fill = fill
+		part.Align = spec[size]
+		spec = spec[size+1:]
+	} else if spec != "" && isAlign(spec[0]) {
+		part.Align = spec[0]
+		spec = spec[1:]
+	}
+	width, spec := leadingDigits(spec)
+	if len(width) > 1 && width[0] == '0' && part.Align == 0 {
+		part.Zero = true
+	}
+	if width != "" {
+		part.Width, _ = strconv.Atoi(width)
+	}
+	if rest, ok := strings.CutPrefix(spec, "."); ok {
+		precision, remaining := leadingDigits(rest)
+		if precision == "" {
+			return part, fmt.Errorf("missing precision after '.' in {%s}", body)
+		}
+		part.Precision, _ = strconv.Atoi(precision)
+		spec = remaining
+	}
+	if spec != "" {
+		return part, fmt.Errorf("invalid format spec in {%s}", body)
+	}
+	return part, nil
+}
+
+// Format renders template with positional and named arguments. Arguments are
+// Str, Bool, integer, or float values; anything else should be converted to
+// Str first. It panics when the template is invalid or names a missing
+// argument, which the checker rules out for literal templates.
+func Format(template string, args []any, named map[string]any) string {
+	parts, err := ParseFormat(template)
+	if err != nil {
+		panic("Str::format: " + err.Error())
+	}
+	var out strings.Builder
+	for _, part := range parts {
+		if !part.Placeholder {
+			out.WriteString(part.Text)
+			continue
+		}
+		var value any
+		if part.Name != "" {
+			arg, ok := named[part.Name]
+			if !ok {
+				panic(fmt.Sprintf("Str::format: no argument named %s", part.Name))
+			}
+			value = arg
+		} else {
+			if part.Index >= len(args) {
+				panic(fmt.Sprintf("Str::format: no argument at position %d", part.Index))
+			}
+			value = args[part.Index]
+		}
+		text, err := FormatValue(part, value)
+		if err != nil {
+			panic("Str::format: " + err.Error())
+		}
+		out.WriteString(text)
+	}
+	return out.String()
+}
+
+// FormatValue renders one argument for a placeholder. Floats print with two
+// decimals unless a precision is given, like Float64 to_str.
+func FormatValue(part FormatPart, value any) (string, error) {
+	var text string
+	numeric := true
+	switch v := value.(type) {
+	case float64:
+		text = formatFloat(v, part.Precision)
+	case float32:
+		text = formatFloat(float64(v), part.Precision)
+	case string:
+		text, numeric = v, false
+	case bool:
+		text, numeric = strconv.FormatBool(v), false
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		text = fmt.Sprint(v)
+	default:
+		text, numeric = fmt.Sprint(v), false
+	}
+	if part.Precision >= 0 {
+		switch value.(type) {
+		case float64, float32:
+		default:
+			return "", fmt.Errorf("precision in {%s} needs a Float64 argument", placeholderKey(part))
+		}
+	}
+	return pad(text, part, numeric), nil
+}
+
+func formatFloat(value float64, precision int) string {
+	if precision < 0 {
+		precision = 2
+	}
+	return strconv.FormatFloat(value, 'f', precision, 64)
+}
+
+func pad(text string, part FormatPart, numeric bool) string {
+	count := utf8.RuneCountInString(text)
+	if count >= part.Width {
+		return text
+	}
+	align := part.Align
+	if align == 0 {
+		align = '<'
+		if numeric {
+			align = '>'
+		}
+	}
+	fill := string(part.Fill)
+	missing := part.Width - count
+	if part.Zero && numeric && part.Align == 0 {
+		sign := ""
+		if strings.HasPrefix(text, "-") {
+			sign, text = "-", text[1:]
+		}
+		return sign + strings.Repeat("0", missing) + text
+	}
+	switch align {
+	case '>':
+		return strings.Repeat(fill, missing) + text
+	case '^':
+		left := missing / 2
+		return strings.Repeat(fill, left) + text + strings.Repeat(fill, missing-left)
+	default:
+		return text + strings.Repeat(fill, missing)
+	}
+}
+
+func placeholderKey(part FormatPart) string {
+	if part.Name != "" {
+		return part.Name
+	}
+	return strconv.Itoa(part.Index)
+}
+
+func isAlign(b byte) bool {
+	return b == '<' || b == '>' || b == '^'
+}
+
+func isDigits(s string) bool {
+	digits, rest := leadingDigits(s)
+	return digits != "" && rest == ""
+}
+
+func isName(s string) bool {
+	for i, r := range s {
+		if r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9') {
+			continue
+		}
+		return false
+	}
+	return s != ""
+}
+
+func leadingDigits(s string) (string, string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	return s[:i], s[i:]
+}
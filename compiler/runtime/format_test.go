@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatSpecs(t *testing.T) {
+	cases := []struct {
+		template string
+		args     []any
+		named    map[string]any
+		want     string
+	}{
+		{"{0:>6}|{0:<6}|{0:^6}", []any{"ab"}, nil, "    ab|ab    |  ab  "},
+		{"{0:6}|{1:6}", []any{42, "ab"}, nil, "    42|ab    "},
+		{"{0:05}|{1:06.1}", []any{-7, 2.25}, nil, "-0007|0002.2"},
+		{"{0}|{0:.3}|{1:*>8.1}", []any{3.14159, 2.0}, nil, "3.14|3.142|*****2.0"},
+		{"{} {} {name:-^7}", []any{1, true}, map[string]any{"name": "é"}, "1 true ---é---"},
+		{"{{literal}} {0}", []any{1}, nil, "{literal} 1"},
+	}
+	for _, tc := range cases {
+		if got := Format(tc.template, tc.args, tc.named); got != tc.want {
+			t.Errorf("Format(%q) = %q, want %q", tc.template, got, tc.want)
+		}
+	}
+}
+
+func TestParseFormatRejectsInvalidTemplates(t *testing.T) {
+	cases := map[string]string{
+		"{0":     "unclosed placeholder",
+		"oops }": "unmatched '}'",
+		"{a-b}":  "invalid placeholder",
+		"{0:x}":  "invalid format spec",
+		"{0:5.}": "missing precision",
+	}
+	for template, want := range cases {
+		if _, err := ParseFormat(template); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("ParseFormat(%q) error = %v, want %q", template, err, want)
+		}
+	}
+}
+
+func TestFormatPanicsOnMissingArguments(t *testing.T) {
+	defer func() {
+		if r := recover(); r != "Str::format: no argument at position 1" {
+			t.Fatalf("panic = %v", r)
+		}
+	}()
+	Format("{0} {1}", []any{1}, nil)
+}
@@ -0,0 +1,191 @@
+package runtime
+
+import "unicode"
+
+// StrGraphemes implements Str.graphemes, splitting value into extended
+// grapheme clusters: what a reader sees as one character, such as "e" with a
+// combining accent, a flag, or an emoji joined with zero-width joiners.
+//
+// It follows the Unicode segmentation rules (UAX #29) using the unicode
+// package's tables. Extended_Pictographic has no table there, so emoji are
+// recognised by their code point blocks.
+func StrGraphemes(value string) []string {
+	graphemes := []string{}
+	start := 0
+	prev := graphemeOther
+	// emojiTail is true while the cluster ends in an emoji followed by Extend
+	// runes; emojiJoin is true when a ZWJ follows such a tail
+	emojiTail := false
+	emojiJoin := false
+	regional := 0
+	conjunct := conjunctNone
+	for offset, r := range value {
+		class := graphemeClassOf(r)
+		consonant := isConjunctConsonant(r)
+		if offset > 0 && !(consonant && conjunct == conjunctLinked) && graphemeBreak(prev, class, emojiJoin, regional) {
+			graphemes = append(graphemes, value[start:offset])
+			start = offset
+			regional = 0
+		}
+		switch {
+		case consonant:
+			conjunct = conjunctConsonant
+		case isConjunctLinker(r):
+			if conjunct != conjunctNone {
+				conjunct = conjunctLinked
+			}
+		case class != graphemeExtend && class != graphemeZWJ:
+			conjunct = conjunctNone
+		}
+		switch class {
+		case graphemePictographic:
+			emojiTail = true
+		case graphemeExtend:
+			// keeps the tail
+		case graphemeZWJ:
+			emojiJoin = emojiTail
+			emojiTail = false
+		default:
+			emojiTail = false
+		}
+		if class == graphemeRegional {
+			regional++
+		} else {
+			regional = 0
+		}
+		prev = class
+	}
+	if start < len(value) {
+		graphemes = append(graphemes, value[start:])
+	}
+	return graphemes
+}
+
+type graphemeClass uint8
+
+const (
+	graphemeOther graphemeClass = iota
+	graphemeCR
+	graphemeLF
+	graphemeControl
+	graphemeExtend
+	graphemeZWJ
+	graphemeRegional
+	graphemePrepend
+	graphemeSpacingMark
+	graphemeL
+	graphemeV
+	graphemeT
+	graphemeLV
+	graphemeLVT
+	graphemePictographic
+)
+
+// graphemeBreak reports whether a cluster boundary falls between a rune of
+// class prev and one of class next. emojiJoin is true when prev is a ZWJ that
+// follows an emoji and optional Extend runes; regional counts the regional
+// indicators ending the cluster.
+func graphemeBreak(prev graphemeClass, next graphemeClass, emojiJoin bool, regional int) bool {
+	switch {
+	case prev == graphemeCR && next == graphemeLF:
+		return false
+	case prev == graphemeCR || prev == graphemeLF || prev == graphemeControl:
+		return true
+	case next == graphemeCR || next == graphemeLF || next == graphemeControl:
+		return true
+	case prev == graphemeL && (next == graphemeL || next == graphemeV || next == graphemeLV || next == graphemeLVT):
+		return false
+	case (prev == graphemeLV || prev == graphemeV) && (next == graphemeV || next == graphemeT):
+		return false
+	case (prev == graphemeLVT || prev == graphemeT) && next == graphemeT:
+		return false
+	case next == graphemeExtend || next == graphemeZWJ || next == graphemeSpacingMark:
+		return false
+	case prev == graphemePrepend:
+		return false
+	case prev == graphemeZWJ && next == graphemePictographic && emojiJoin:
+		return false
+	case prev == graphemeRegional && next == graphemeRegional:
+		return regional%2 == 0
+	default:
+		return true
+	}
+}
+
+func graphemeClassOf(r rune) graphemeClass {
+	switch {
+	case r == '\r':
+		return graphemeCR
+	case r == '\n':
+		return graphemeLF
+	case r == 0x200D:
+		return graphemeZWJ
+	case r >= 0x1F1E6 && r <= 0x1F1FF:
+		return graphemeRegional
+	// emoji skin tone modifiers
+	case r >= 0x1F3FB && r <= 0x1F3FF, r == 0x200C,
+		unicode.In(r, unicode.Mn, unicode.Me, unicode.Other_Grapheme_Extend):
+		return graphemeExtend
+	case unicode.Is(unicode.Prepended_Concatenation_Mark, r):
+		return graphemePrepend
+	case unicode.In(r, unicode.Cc, unicode.Cf, unicode.Zl, unicode.Zp):
+		return graphemeControl
+	case unicode.Is(unicode.Mc, r):
+		return graphemeSpacingMark
+	case r >= 0x1100 && r <= 0x115F, r >= 0xA960 && r <= 0xA97C:
+		return graphemeL
+	case r >= 0x1160 && r <= 0x11A7, r >= 0xD7B0 && r <= 0xD7C6:
+		return graphemeV
+	case r >= 0x11A8 && r <= 0x11FF, r >= 0xD7CB && r <= 0xD7FB:
+		return graphemeT
+	case r >= 0xAC00 && r <= 0xD7A3:
+		if (r-0xAC00)%28 == 0 {
+			return graphemeLV
+		}
+		return graphemeLVT
+	case isPictographic(r):
+		return graphemePictographic
+	default:
+		return graphemeOther
+	}
+}
+
+// Indic conjuncts (a consonant, a virama, and another consonant) stay in one
+// cluster in the scripts Unicode lists for this rule.
+const (
+	conjunctNone = iota
+	conjunctConsonant
+	conjunctLinked
+)
+
+func isConjunctLinker(r rune) bool {
+	switch r {
+	case 0x094D, 0x09CD, 0x0ACD, 0x0B4D, 0x0C4D, 0x0D4D:
+		return true
+	default:
+		return false
+	}
+}
+
+func isConjunctConsonant(r rune) bool {
+	switch {
+	case r >= 0x0900 && r <= 0x097F, r >= 0x0980 && r <= 0x09FF, r >= 0x0A80 && r <= 0x0AFF,
+		r >= 0x0B00 && r <= 0x0B7F, r >= 0x0C00 && r <= 0x0C7F, r >= 0x0D00 && r <= 0x0D7F:
+		return unicode.IsLetter(r) && !unicode.In(r, unicode.Mn, unicode.Mc)
+	default:
+		return false
+	}
+}
+
+func isPictographic(r rune) bool {
+	switch {
+	case r == 0xA9, r == 0xAE, r == 0x203C, r == 0x2049, r == 0x2122, r == 0x2139,
+		r == 0x3030, r == 0x303D, r == 0x3297, r == 0x3299:
+		return true
+	case r >= 0x2190 && r <= 0x21FF, r >= 0x2300 && r <= 0x23FF, r >= 0x25A0 && r <= 0x27BF,
+		r >= 0x2B00 && r <= 0x2BFF, r >= 0x1F000 && r <= 0x1FAFF:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,30 @@
+package runtime
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestStrGraphemes(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []string
+	}{
+		{"", []string{}},
+		{"e\u0301x", []string{"e\u0301", "x"}},
+		{"a\r\nb", []string{"a", "\r\n", "b"}},
+		{"👨‍👩‍👧!", []string{"👨‍👩‍👧", "!"}},
+		{"👍🏽a", []string{"👍🏽", "a"}},
+		{"🇫🇷🇩🇪🇺", []string{"🇫🇷", "🇩🇪", "🇺"}},
+		{"각가힣", []string{"각", "가", "힣"}},
+		{"\u1100\u1161\u11a8", []string{"\u1100\u1161\u11a8"}},
+		{"क्षि", []string{"क्षि"}},
+		{"a\u200dc", []string{"a\u200d", "c"}},
+		{"\tx", []string{"\t", "x"}},
+	}
+	for _, tc := range cases {
+		if got := StrGraphemes(tc.input); !slices.Equal(got, tc.want) {
+			t.Fatalf("StrGraphemes(%q) = %q, want %q", tc.input, got, tc.want)
+		}
+	}
+}
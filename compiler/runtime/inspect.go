@@ -0,0 +1,49 @@
+package runtime
+
+import "reflect"
+
+// InspectSeen holds the maps, lists, and mutable references one inspect call
+// is rendering. Values share these, so they can form cycles; a reference that
+// is reached again while it is still being rendered renders as `<cycle>`.
+type InspectSeen map[inspectRef]bool
+
+// inspectRef identifies a shared value. A list is identified by its backing
+// array and length, so a list nested in itself is caught while a shorter
+// slice of the same array is still rendered.
+type inspectRef struct {
+	typ    reflect.Type
+	ptr    uintptr
+	length int
+}
+
+// Once renders with render unless ref, a map, slice, or pointer, is already
+// being rendered.
+func (seen InspectSeen) Once(ref any, render func() string) string {
+	key, ok := inspectRefOf(ref)
+	if !ok {
+		return render()
+	}
+	if seen[key] {
+		return "<cycle>"
+	}
+	seen[key] = true
+	defer delete(seen, key)
+	return render()
+}
+
+func inspectRefOf(ref any) (inspectRef, bool) {
+	value := reflect.ValueOf(ref)
+	switch value.Kind() {
+	case reflect.Map, reflect.Pointer:
+		if value.IsNil() {
+			return inspectRef{}, false
+		}
+		return inspectRef{typ: value.Type(), ptr: value.Pointer()}, true
+	case reflect.Slice:
+		if value.Len() == 0 {
+			return inspectRef{}, false
+		}
+		return inspectRef{typ: value.Type(), ptr: value.Pointer(), length: value.Len()}, true
+	}
+	return inspectRef{}, false
+}
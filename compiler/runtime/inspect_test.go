@@ -0,0 +1,42 @@
+package runtime
+
+import "testing"
+
+func TestInspectSeenRendersRepeatedReferencesAsCycles(t *testing.T) {
+	type node struct{ children map[string]node }
+	root := node{children: map[string]node{}}
+	root.children["self"] = root
+	seen := InspectSeen{}
+	var render func(node) string
+	render = func(n node) string {
+		return seen.Once(n.children, func() string {
+			out := "{"
+			for name, child := range n.children {
+				out += name + ": " + render(child)
+			}
+			return out + "}"
+		})
+	}
+	if got := render(root); got != "{self: <cycle>}" {
+		t.Fatalf("render = %q", got)
+	}
+	if len(seen) != 0 {
+		t.Fatalf("seen kept %d references after rendering", len(seen))
+	}
+}
+
+func TestInspectSeenRendersSharedValuesThatAreNotCycles(t *testing.T) {
+	shared := []int{1}
+	seen := InspectSeen{}
+	first := seen.Once(shared, func() string { return "a" })
+	second := seen.Once(shared, func() string { return "b" })
+	if first != "a" || second != "b" {
+		t.Fatalf("siblings rendered as %q and %q", first, second)
+	}
+	nested := seen.Once(shared, func() string {
+		return seen.Once(shared[:0], func() string { return "empty" })
+	})
+	if nested != "empty" {
+		t.Fatalf("empty slice rendered as %q", nested)
+	}
+}
@@ -0,0 +1,77 @@
+package runtime
+
+import (
+	"cmp"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// SortedKeys returns the keys of m in ascending order so map iteration and
+// key positions are the same on every run.
+func SortedKeys[K comparable, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.SortFunc(keys, func(a, b K) int {
+		return CompareKeys(reflect.ValueOf(a), reflect.ValueOf(b))
+	})
+	return keys
+}
+
+// CompareKeys orders two map keys of the same type. Numbers, strings, and
+// booleans compare by value; structs and arrays compare field by field.
+// Other kinds fall back to their printed form.
+func CompareKeys(a, b reflect.Value) int {
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	case reflect.Bool:
+		switch {
+		case a.Bool() == b.Bool():
+			return 0
+		case b.Bool():
+			return -1
+		default:
+			return 1
+		}
+	case reflect.Struct:
+		for i := range a.NumField() {
+			if c := CompareKeys(a.Field(i), b.Field(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Array:
+		for i := range a.Len() {
+			if c := CompareKeys(a.Index(i), b.Index(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Interface, reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			return cmp.Compare(boolRank(!a.IsNil()), boolRank(!b.IsNil()))
+		}
+		a, b = a.Elem(), b.Elem()
+		if a.Type() == b.Type() {
+			return CompareKeys(a, b)
+		}
+		return cmp.Compare(a.Type().String(), b.Type().String())
+	}
+	return cmp.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func boolRank(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,29 @@
+package runtime
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortedKeysOrdersByValue(t *testing.T) {
+	names := SortedKeys(map[string]int{"zed": 1, "amy": 2, "bob": 3})
+	if !slices.Equal(names, []string{"amy", "bob", "zed"}) {
+		t.Fatalf("string keys = %v", names)
+	}
+	ints := SortedKeys(map[int]bool{3: true, -1: true, 2: true})
+	if !slices.Equal(ints, []int{-1, 2, 3}) {
+		t.Fatalf("int keys = %v", ints)
+	}
+	flags := SortedKeys(map[bool]int{true: 1, false: 0})
+	if !slices.Equal(flags, []bool{false, true}) {
+		t.Fatalf("bool keys = %v", flags)
+	}
+}
+
+func TestSortedKeysOrdersStructsFieldByField(t *testing.T) {
+	type point struct{ x, y int }
+	keys := SortedKeys(map[point]string{{2, 1}: "c", {1, 5}: "b", {1, 2}: "a"})
+	if !slices.Equal(keys, []point{{1, 2}, {1, 5}, {2, 1}}) {
+		t.Fatalf("struct keys = %v", keys)
+	}
+}
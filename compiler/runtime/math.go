@@ -0,0 +1,88 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// IntAbs implements Int.abs. The most negative Int has no positive
+// counterpart and stays negative, as in two's complement negation.
+func IntAbs(value int) int {
+	if value < 0 {
+		return -value
+	}
+	return value
+}
+
+// IntPow implements Int.pow by repeated squaring. Results that overflow wrap
+// like Int multiplication. It panics on a negative exponent.
+func IntPow(base int, exponent int) int {
+	if exponent < 0 {
+		panic(fmt.Sprintf("Int.pow: negative exponent %d", exponent))
+	}
+	result := 1
+	for exponent > 0 {
+		if exponent&1 == 1 {
+			result *= base
+		}
+		base *= base
+		exponent >>= 1
+	}
+	return result
+}
+
+// IntClamp implements Int.clamp. It panics when low is greater than high.
+func IntClamp(value int, low int, high int) int {
+	if low > high {
+		panic(fmt.Sprintf("Int.clamp: low %d is greater than high %d", low, high))
+	}
+	return min(max(value, low), high)
+}
+
+// FloatRound implements Float64.round, rounding halves away from zero.
+func FloatRound(value float64) int {
+	return int(math.Round(value))
+}
+
+// FloatFloor implements Float64.floor.
+func FloatFloor(value float64) int {
+	return int(math.Floor(value))
+}
+
+// FloatCeil implements Float64.ceil.
+func FloatCeil(value float64) int {
+	return int(math.Ceil(value))
+}
+
+// IntParse implements Int::parse. It reads a base-10 integer with an optional
+// sign.
+func IntParse(text string) Result[int, string] {
+	value, err := strconv.ParseInt(text, 10, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return Err[int](fmt.Sprintf("%q is out of range for Int", text))
+	}
+	if err != nil {
+		return Err[int](fmt.Sprintf("%q is not a valid Int", text))
+	}
+	return Ok[int, string](int(value))
+}
+
+// FloatParse implements Float64::parse. It reads decimal notation with an
+// optional exponent; Go's hex floats, Inf, and NaN are rejected so both
+// targets accept the same text.
+func FloatParse(text string) Result[float64, string] {
+	if strings.ContainsAny(strings.ToLower(text), "_inpx") {
+		return Err[float64](fmt.Sprintf("%q is not a valid Float64", text))
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if errors.Is(err, strconv.ErrRange) {
+		return Err[float64](fmt.Sprintf("%q is out of range for Float64", text))
+	}
+	if err != nil {
+		return Err[float64](fmt.Sprintf("%q is not a valid Float64", text))
+	}
+	return Ok[float64, string](value)
+}
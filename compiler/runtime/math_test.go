@@ -0,0 +1,52 @@
+package runtime
+
+import "testing"
+
+func TestIntPow(t *testing.T) {
+	cases := []struct{ base, exponent, want int }{
+		{2, 10, 1024},
+		{-3, 3, -27},
+		{7, 0, 1},
+		{0, 0, 1},
+	}
+	for _, tc := range cases {
+		if got := IntPow(tc.base, tc.exponent); got != tc.want {
+			t.Fatalf("IntPow(%d, %d) = %d, want %d", tc.base, tc.exponent, got, tc.want)
+		}
+	}
+}
+
+func TestIntClamp(t *testing.T) {
+	if got := IntClamp(12, 0, 10); got != 10 {
+		t.Fatalf("IntClamp(12, 0, 10) = %d, want 10", got)
+	}
+	if got := IntClamp(-4, 0, 10); got != 0 {
+		t.Fatalf("IntClamp(-4, 0, 10) = %d, want 0", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("IntClamp with low > high did not panic")
+		}
+	}()
+	IntClamp(1, 10, 0)
+}
+
+func TestNumberParse(t *testing.T) {
+	if got := IntParse("-42"); !got.Ok || got.Value != -42 {
+		t.Fatalf("IntParse(-42) = %+v", got)
+	}
+	if got := IntParse("0x10"); got.Ok || got.Err != `"0x10" is not a valid Int` {
+		t.Fatalf("IntParse(0x10) = %+v", got)
+	}
+	if got := IntParse("9223372036854775808"); got.Ok || got.Err != `"9223372036854775808" is out of range for Int` {
+		t.Fatalf("IntParse(2^63) = %+v", got)
+	}
+	if got := FloatParse("1.5e3"); !got.Ok || got.Value != 1500 {
+		t.Fatalf("FloatParse(1.5e3) = %+v", got)
+	}
+	for _, text := range []string{"NaN", "Inf", "0x1p-2", "1_0.5", ""} {
+		if got := FloatParse(text); got.Ok {
+			t.Fatalf("FloatParse(%q) = %+v, want an error", text, got)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Str methods that take or return positions count runes, like Str.at, so
+// multi-byte characters are never split.
+
+// StrSlice implements Str.slice. Indices are clamped to the string, and an
+// end before start yields an empty string.
+func StrSlice(value string, start int, end int) string {
+	runes := []rune(value)
+	start = min(max(start, 0), len(runes))
+	end = min(max(end, start), len(runes))
+	return string(runes[start:end])
+}
+
+// StrIndexOf implements Str.index_of, returning the rune index of the first
+// occurrence of sub.
+func StrIndexOf(value string, sub string) Maybe[int] {
+	index := strings.Index(value, sub)
+	if index < 0 {
+		return None[int]()
+	}
+	return Some(utf8.RuneCountInString(value[:index]))
+}
+
+// StrTrimStart and StrTrimEnd trim spaces from one side, like Str.trim.
+func StrTrimStart(value string) string {
+	return strings.TrimLeft(value, " ")
+}
+
+func StrTrimEnd(value string) string {
+	return strings.TrimRight(value, " ")
+}
+
+// StrPadLeft implements Str.pad_left, prepending copies of fill until value
+// is width runes long. The padding is cut short to land on width exactly.
+func StrPadLeft(value string, width int, fill string) string {
+	return strPadding(value, width, fill) + value
+}
+
+// StrPadRight is StrPadLeft but appends the padding.
+func StrPadRight(value string, width int, fill string) string {
+	return value + strPadding(value, width, fill)
+}
+
+func strPadding(value string, width int, fill string) string {
+	missing := width - utf8.RuneCountInString(value)
+	fillRunes := []rune(fill)
+	if missing <= 0 || len(fillRunes) == 0 {
+		return ""
+	}
+	padding := make([]rune, missing)
+	for i := range padding {
+		padding[i] = fillRunes[i%len(fillRunes)]
+	}
+	return string(padding)
+}
+
+// StrRepeat implements Str.repeat. It panics on a negative count.
+func StrRepeat(value string, count int) string {
+	if count < 0 {
+		panic(fmt.Sprintf("Str.repeat: negative count %d", count))
+	}
+	return strings.Repeat(value, count)
+}
+
+// StrReverse implements Str.reverse by reversing runes.
+func StrReverse(value string) string {
+	runes := []rune(value)
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+	return string(runes)
+}
+
+// StrChars implements Str.chars, splitting value into one Str per rune.
+func StrChars(value string) []string {
+	chars := make([]string, 0, utf8.RuneCountInString(value))
+	for _, r := range value {
+		chars = append(chars, string(r))
+	}
+	return chars
+}
@@ -0,0 +1,63 @@
+package runtime
+
+import "testing"
+
+func TestStrSliceCountsRunesAndClamps(t *testing.T) {
+	cases := []struct {
+		start, end int
+		want       string
+	}{
+		{0, 2, "hé"},
+		{1, 100, "éllo"},
+		{-3, 1, "h"},
+		{4, 2, ""},
+	}
+	for _, tc := range cases {
+		if got := StrSlice("héllo", tc.start, tc.end); got != tc.want {
+			t.Fatalf("StrSlice(%d, %d) = %q, want %q", tc.start, tc.end, got, tc.want)
+		}
+	}
+}
+
+func TestStrIndexOf(t *testing.T) {
+	if got := StrIndexOf("naïve café", "café"); got.Value() != 6 {
+		t.Fatalf("StrIndexOf rune index = %d, want 6", got.Value())
+	}
+	if StrIndexOf("abc", "z").IsSome() {
+		t.Fatal("StrIndexOf found a missing substring")
+	}
+}
+
+func TestStrPad(t *testing.T) {
+	if got := StrPadLeft("7", 4, "0"); got != "0007" {
+		t.Fatalf("StrPadLeft = %q, want 0007", got)
+	}
+	if got := StrPadRight("ab", 7, "-="); got != "ab-=-=-" {
+		t.Fatalf("StrPadRight = %q, want ab-=-=-", got)
+	}
+	if got := StrPadLeft("wide", 2, "*"); got != "wide" {
+		t.Fatalf("StrPadLeft past width = %q, want wide", got)
+	}
+}
+
+func TestStrReverseAndChars(t *testing.T) {
+	if got := StrReverse("añb😀"); got != "😀bña" {
+		t.Fatalf("StrReverse = %q", got)
+	}
+	chars := StrChars("añ😀")
+	if len(chars) != 3 || chars[1] != "ñ" || chars[2] != "😀" {
+		t.Fatalf("StrChars = %q", chars)
+	}
+}
+
+func TestStrRepeatPanicsOnNegativeCount(t *testing.T) {
+	if got := StrRepeat("ab", 3); got != "ababab" {
+		t.Fatalf("StrRepeat = %q, want ababab", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("StrRepeat with a negative count did not panic")
+		}
+	}()
+	StrRepeat("ab", -1)
+}
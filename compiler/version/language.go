@@ -0,0 +1,35 @@
+package version
+
+import "fmt"
+
+// language is the Ard language version implemented by this compiler.
+//
+// It is tracked separately from the compiler release: a project pins the
+// language it was written against with `language = "0.21"` in ard.toml, and
+// the compiler keeps accepting syntax removed after that version (reporting
+// it as deprecated) until `ard migrate` rewrites the sources.
+var language = Semver{Major: 0, Minor: 22}
+
+// Language returns the Ard language version implemented by this compiler.
+func Language() Semver {
+	return language
+}
+
+// ParseLanguage parses a language version like "0.22" and rejects versions
+// newer than the one this compiler implements.
+func ParseLanguage(s string) (Semver, error) {
+	v, err := ParseSemver(s)
+	if err != nil {
+		return Semver{}, fmt.Errorf("invalid language version %q: %w", s, err)
+	}
+	if v.Compare(language) == GreaterThan {
+		return Semver{}, fmt.Errorf("this project targets Ard language %s, but this compiler implements %s", LanguageString(v), LanguageString(language))
+	}
+	return v, nil
+}
+
+// LanguageString renders a language version without its patch component,
+// which language versions do not use.
+func LanguageString(v Semver) string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
@@ -172,3 +172,23 @@ func TestCheckVersionWithRealVersion(t *testing.T) {
 		}
 	})
 }
+func TestParseLanguage(t *testing.T) {
+	original := language
+	defer func() { language = original }()
+	language = Semver{0, 22, 0}
+
+	if got, err := ParseLanguage("0.21"); err != nil || got != (Semver{0, 21, 0}) {
+		t.Fatalf("ParseLanguage(0.21) = %v, %v", got, err)
+	}
+	_, err := ParseLanguage("0.23")
+	if err == nil {
+		t.Fatal("expected a newer language version to be rejected")
+	}
+	expected := "this project targets Ard language 0.23, but this compiler implements 0.22"
+	if err.Error() != expected {
+		t.Fatalf("expected %q, got %q", expected, err.Error())
+	}
+	if _, err := ParseLanguage("latest"); err == nil {
+		t.Fatal("expected an invalid language version to be rejected")
+	}
+}
@@ -227,10 +227,28 @@ export default defineConfig({
               label: "Modules",
               items: [
                 { label: "ard/async", slug: "stdlib/async" },
+                { label: "ard/base64", slug: "stdlib/base64" },
+                { label: "ard/bigint", slug: "stdlib/bigint" },
+                { label: "ard/cache", slug: "stdlib/cache" },
+                { label: "ard/csv", slug: "stdlib/csv" },
+                { label: "ard/decode", slug: "stdlib/decode" },
+                { label: "ard/dynamic", slug: "stdlib/dynamic" },
+                { label: "ard/hash", slug: "stdlib/hash" },
+                { label: "ard/hex", slug: "stdlib/hex" },
+                { label: "ard/http", slug: "stdlib/http" },
+                { label: "ard/io", slug: "stdlib/io" },
+                { label: "ard/json", slug: "stdlib/json" },
+                { label: "ard/lazy", slug: "stdlib/lazy" },
                 { label: "ard/list", slug: "stdlib/list" },
+                { label: "ard/log", slug: "stdlib/log" },
                 { label: "ard/map", slug: "stdlib/map" },
+                { label: "ard/math", slug: "stdlib/math" },
+                { label: "ard/net", slug: "stdlib/net" },
+                { label: "ard/sql", slug: "stdlib/sql" },
                 { label: "ard/testing", slug: "stdlib/testing" },
                 { label: "ard/unsafe", slug: "stdlib/unsafe" },
+                { label: "ard/url", slug: "stdlib/url" },
+                { label: "ard/uuid", slug: "stdlib/uuid" },
               ],
             },
           ],